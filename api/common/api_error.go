@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/utils"
 )
 
 // Error constants
@@ -13,6 +14,127 @@ var (
 	ErrUserExists        = errors.New("user already exists")
 	ErrAccountExists     = errors.New("account already exists")
 	ErrTransactionFailed = errors.New("transaction failed")
+
+	ErrInvalidAccountType    = errors.New(utils.GetValidAccountTypesMessage())
+	ErrInvalidAccountNumber  = errors.New("invalid account number format")
+	ErrAccountReferenceError = errors.New("user, account type or currency does not exist")
+	ErrAccountHasHistory     = errors.New("account has ledger history and cannot be removed, close the account instead")
+	ErrAccountNotEmpty       = errors.New("account still holds a balance, open holds, scheduled transfers or a running fixed deposit and cannot be closed")
+
+	ErrInvalidUserData   = errors.New("invalid user data")
+	ErrInvalidImage      = errors.New("profile image must be a JPEG, PNG or WEBP file up to 5MB")
+	ErrUserHasAccounts   = errors.New("user still owns accounts and cannot be removed")
+	ErrUserHasHistory    = errors.New("user has audited history and cannot be removed, deactivate the user instead")
+	ErrStorageNotEnabled = errors.New("file storage is not configured")
+
+	ErrAccountNotFound         = errors.New("sender or receiver account does not exist")
+	ErrAccountInactive         = errors.New("sender or receiver account is not active")
+	ErrCurrencyMismatch        = errors.New("transfer currency must match the sender account currency")
+	ErrExchangeRateUnavailable = errors.New("no exchange rate available for the currency pair")
+	ErrSameAccount             = errors.New("sender and receiver must be different accounts")
+	ErrInvalidAmount           = errors.New("amount must be greater than zero")
+	ErrInvalidAmountPrecision  = errors.New("amount has more decimal places than the currency allows")
+	ErrNegativeAmount          = errors.New("overdraft limits and fees cannot be negative")
+	ErrInvalidRatePrecision    = errors.New("rate has more decimal places than it is stored with")
+	ErrInsufficientFunds       = errors.New("insufficient funds in sender account")
+	ErrDuplicateTransfer       = errors.New("transfer with this reference has already been executed")
+
+	ErrTransactionNotFound      = errors.New("transaction not found")
+	ErrInvalidTransactionNumber = errors.New("invalid transaction number: must be a UUID")
+	ErrTransactionNotReversible = errors.New("only completed transfers between two accounts can be reversed or refunded")
+	ErrAlreadyReversed          = errors.New("transaction has already been reversed or fully refunded")
+	ErrRefundExceedsOriginal    = errors.New("refund exceeds the amount left on the original transaction")
+
+	ErrHoldNotFound       = errors.New("hold not found")
+	ErrInvalidHoldNumber  = errors.New("invalid hold number: must be a UUID")
+	ErrHoldNotOpen        = errors.New("hold has already been captured, released or expired")
+	ErrHoldExpired        = errors.New("hold has expired")
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds the held amount")
+
+	ErrScheduledTransferNotFound = errors.New("scheduled transfer not found")
+	ErrInvalidScheduleNumber     = errors.New("invalid schedule number: must be a UUID")
+	ErrInvalidSchedule           = errors.New("invalid schedule: check frequency, cron expression and dates")
+	ErrInvalidScheduleTransition = errors.New("scheduled transfer cannot change to the requested status")
+
+	ErrTransferBatchNotFound  = errors.New("transfer batch not found")
+	ErrInvalidBatchNumber     = errors.New("invalid batch number: must be a UUID")
+	ErrInvalidBatchFile       = errors.New("batch file must be a CSV with the columns from_account_id,to_account_id,amount,currency_code,description")
+	ErrBatchUploadNotEnabled  = errors.New("batch file uploads are not configured")
+	ErrDuplicateTransferBatch = errors.New("transfer batch with this reference has already been submitted")
+
+	ErrReconciliationRunNotFound = errors.New("reconciliation run not found")
+	ErrInvalidRunNumber          = errors.New("invalid run number: must be a UUID")
+
+	ErrInterestSettingsNotFound = errors.New("interest settings not found")
+	ErrUnknownAccountType       = errors.New("account type does not exist")
+	ErrInvalidInterestDate      = errors.New("through must be a date (YYYY-MM-DD) before today")
+
+	ErrFeeScheduleNotFound      = errors.New("fee schedule not found")
+	ErrFeeIncomeAccountMissing  = errors.New("no fee income account is configured for the transfer currency")
+	ErrFeeIncomeAccountNotFound = errors.New("fee income account not found")
+	ErrFeeIncomeAccountCurrency = errors.New("fee income account must hold the currency it collects")
+	ErrInvalidFeeDate           = errors.New("day must be a date (YYYY-MM-DD) before today")
+
+	ErrFixedDepositNotFound  = errors.New("fixed deposit not found")
+	ErrInvalidDepositNumber  = errors.New("invalid deposit number: must be a UUID")
+	ErrFixedDepositLocked    = errors.New("account holds a fixed deposit that has not matured yet")
+	ErrFixedDepositNotActive = errors.New("fixed deposit has already matured or been broken")
+	ErrFixedDepositMatured   = errors.New("fixed deposit has reached maturity and will be paid out shortly")
+	ErrInvalidLinkedAccount  = errors.New("a fixed deposit cannot be linked to another fixed deposit")
+	ErrFixedDepositTerm      = errors.New("no fixed deposit is offered for this term")
+	ErrInvalidTermMonths     = errors.New("invalid term_months: must be between 1 and 120")
+
+	ErrStatementNotFound          = errors.New("statement not found")
+	ErrInvalidStatementNumber     = errors.New("invalid statement number: must be a UUID")
+	ErrInvalidStatementPeriod     = errors.New("from and to must be dates (YYYY-MM-DD), from no later than to and to no later than today")
+	ErrUnsupportedStatementFormat = errors.New("format must be one of csv, pdf, ofx or camt053")
+	ErrStatementTooLarge          = errors.New("statement has too many entries to download directly, request it with POST /statements instead")
+	ErrStatementNotReady          = errors.New("statement has not been generated yet")
+
+	ErrAuditRecordNotFound = errors.New("audit record not found")
+	ErrInvalidAuditTable   = errors.New("table must be one of users, accounts, account_types, account_currencies, transactions or access_control")
+	ErrInvalidAuditPeriod  = errors.New("from and to must be RFC 3339 timestamps, from before to")
+
+	ErrCurrencyNotFound        = errors.New("currency not found")
+	ErrCurrencyExists          = errors.New("currency already exists")
+	ErrCurrencyMinorUnitsFixed = errors.New("minor units of an ISO 4217 currency cannot be changed")
+	ErrCurrencyInUse           = errors.New("minor units cannot be changed while accounts hold the currency")
+	ErrInvalidExchangeRateTime = errors.New("at must be an RFC 3339 timestamp")
+	ErrInvalidRateHistoryRange = errors.New("from and to must be RFC 3339 timestamps, from before to")
+
+	ErrInvalidCredentials  = errors.New("invalid username or password")
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid, expired or revoked")
+	ErrForbidden           = errors.New("you can only access your own users and accounts")
+	ErrUserInactive        = errors.New("user does not exist or is not active")
+	ErrInvalidRole         = errors.New("role must be one of: CUSTOMER, SUPPORT, ADMIN, AUDITOR")
+	ErrOwnRoleChange       = errors.New("you cannot change your own role")
+
+	ErrInvalidChallengeToken   = errors.New("two-factor challenge is invalid or has expired")
+	ErrInvalidTwoFactorCode    = errors.New("two-factor code is invalid or was already used")
+	ErrTwoFactorLocked         = errors.New("too many wrong two-factor codes, try again later")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("start two-factor enrollment before confirming it")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorSetupRequired  = errors.New("this transfer requires two-factor authentication, enable it first")
+	ErrStepUpRequired          = errors.New("this transfer requires a two-factor code in the X-TOTP-Code header")
+
+	ErrAPIKeyNotFound      = errors.New("API key not found")
+	ErrInvalidAPIKey       = errors.New("invalid API key")
+	ErrAPIKeyExpired       = errors.New("API key has expired")
+	ErrAPIKeyInactive      = errors.New("API key is revoked or expired")
+	ErrInvalidAPIKeyScopes = errors.New("scopes must be a list of READ_ONLY, TRANSFERS or ADMIN")
+	ErrInvalidAPIKeyExpiry = errors.New("expires_at must be in the future")
+	ErrAPIKeyOwnerNotFound = errors.New("API key owner does not exist")
+
+	ErrSignatureRequired      = errors.New("requests sent with this API key must be signed")
+	ErrReplayedNonce          = errors.New("request signature nonce has already been used")
+	ErrRequestSigningDisabled = errors.New("request signing is not enabled")
+	ErrSignedBodyTooLarge     = errors.New("signed request body is larger than 11 MiB")
+
+	ErrIdempotencyKeyMismatch   = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
+	ErrInvalidIdempotencyKey    = errors.New("idempotency key must be between 1 and 255 characters")
+	ErrIdempotentBodyTooLarge   = errors.New("request body sent with an idempotency key is larger than 11 MiB")
 )
 
 // HandleCreateUserAccountError handles errors that occur when creating a user account
@@ -0,0 +1,7 @@
+package common
+
+// Keys used to share request scoped values through the gin context
+const (
+	// ContextKeyIdempotencyReference holds the transaction reference derived from the Idempotency-Key header
+	ContextKeyIdempotencyReference = "idempotency_reference"
+)
@@ -2,45 +2,244 @@ package dependency
 
 import (
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/riad/banksystemendtoend/api/handler"
 	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/middleware"
 	"github.com/riad/banksystemendtoend/api/repository"
 	"github.com/riad/banksystemendtoend/api/service"
 	db "github.com/riad/banksystemendtoend/db/sqlc"
 	"github.com/riad/banksystemendtoend/pkg/cache"
+	"github.com/riad/banksystemendtoend/pkg/jobs"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/pkg/rabbitmq"
 	"github.com/riad/banksystemendtoend/pkg/redis"
+	pkg_repository "github.com/riad/banksystemendtoend/pkg/repository"
+	"github.com/riad/banksystemendtoend/pkg/s3"
+	upload_service "github.com/riad/banksystemendtoend/pkg/service"
+	"github.com/riad/banksystemendtoend/util/rbac"
+	"github.com/riad/banksystemendtoend/util/signing"
+	"github.com/riad/banksystemendtoend/util/token"
+	"go.uber.org/zap"
+)
+
+const (
+	batchUploadExchange = "transfer_batches"
+	batchUploadQueue    = "transfer_batch_uploads"
+	batchUploadMaxSize  = 10 << 20
 )
 
 type DependencyContainer struct {
 	handlers     map[string][]RouteHandler
+	jobs         []jobs.Job
 	redisClient  *redis.Client
 	cacheService *cache.Service
+	// userAuth checks the permission routes declare against the role of the user they are called by
+	userAuth *middleware.UserAuth
+	// apiKey checks the API key every request but the health check is sent with
+	apiKey *middleware.APIKey
+	// requestSignature checks the signatures of requests sent with API keys
+	requestSignature *middleware.RequestSignature
 
+	AuthHandler        handler_interface.AuthHandler
+	TwoFactorHandler   handler_interface.TwoFactorHandler
+	APIKeyHandler      handler_interface.APIKeyHandler
 	AccountTypeHandler handler_interface.AccountTypeHandler
+	AccountHandler     handler_interface.AccountHandler
+	UserHandler        handler_interface.UserHandler
+	UserAccountHandler handler_interface.UserAccountHandler
+	TransferHandler    handler_interface.TransferHandler
+	HoldHandler        handler_interface.HoldHandler
+
+	ScheduledTransferHandler handler_interface.ScheduledTransferHandler
+	TransferBatchHandler     handler_interface.TransferBatchHandler
+	ReconciliationHandler    handler_interface.ReconciliationHandler
+	InterestHandler          handler_interface.InterestHandler
+	FeeHandler               handler_interface.FeeHandler
+	FixedDepositHandler      handler_interface.FixedDepositHandler
+	StatementHandler         handler_interface.StatementHandler
+	AuditHandler             handler_interface.AuditHandler
+	ExchangeRateHandler      handler_interface.ExchangeRateHandler
+	CurrencyHandler          handler_interface.CurrencyHandler
 }
 
 type RouteHandler struct {
 	Method      string
 	Path        string
 	HandlerFunc gin.HandlerFunc
+	// Permission the caller's role must hold, routes without one are public
+	Permission rbac.Permission
+	// Middlewares run before HandlerFunc for this route only
+	Middlewares []gin.HandlerFunc
+}
+
+// Handlers returns the route middlewares followed by the handler, ready to be registered on a router group
+func (r RouteHandler) Handlers() []gin.HandlerFunc {
+	handlers := make([]gin.HandlerFunc, 0, len(r.Middlewares)+1)
+	handlers = append(handlers, r.Middlewares...)
+	return append(handlers, r.HandlerFunc)
 }
 
 func NewDependencyContainer(store db.Store, redisClient *redis.Client) (*DependencyContainer, error) {
 	cacheService := cache.NewService(redisClient, "wallet_app", 60*time.Minute)
 
+	tokenMaker, err := token.MakerFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := signing.SignerFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	container := &DependencyContainer{
 		handlers:     make(map[string][]RouteHandler),
 		redisClient:  redisClient,
 		cacheService: cacheService,
 	}
 
+	userRepo := repository.NewUserRepository(store, cacheService)
+	auditRepo := repository.NewAuditRepository(store)
+	accessControlService := service.NewAccessControlService(userRepo, auditRepo)
+	container.userAuth = middleware.NewUserAuth(tokenMaker, accessControlService)
+	// Logins and every way of moving money check the second factor
+	twoFactorService := service.NewTwoFactorService(repository.NewTwoFactorRepository(store), userRepo)
+
+	container.registerAuthHandlers(store, cacheService, tokenMaker, twoFactorService)
+	container.registerAPIKeyHandlers(store, cacheService, signer)
 	container.registerAccountTypeHandlers(store, cacheService)
+	container.registerAccountHandlers(store, cacheService)
+	container.registerUserHandlers(store, cacheService)
+	if err := container.registerUserAccountHandlers(store); err != nil {
+		return nil, err
+	}
+	container.registerTransferHandlers(store, cacheService, twoFactorService)
+	container.registerHoldHandlers(store, cacheService, twoFactorService)
+	container.registerScheduledTransferHandlers(store, cacheService, twoFactorService)
+	container.registerTransferBatchHandlers(store, cacheService, twoFactorService)
+	container.registerReconciliationHandlers(store)
+	container.registerInterestHandlers(store)
+	container.registerFeeHandlers(store)
+	container.registerFixedDepositHandlers(store, cacheService, twoFactorService)
+	container.registerStatementHandlers(store)
+	container.registerAuditHandlers(store)
+	container.registerExchangeRateHandlers(store)
+	container.registerCurrencyHandlers(store, cacheService)
+	container.guardRoutes()
 	return container, nil
 }
 
+// guardRoutes puts the permission check of every route declaring a permission in front of its other middlewares
+func (c *DependencyContainer) guardRoutes() {
+	for _, routes := range c.handlers {
+		for i, route := range routes {
+			if route.Permission == "" {
+				continue
+			}
+			routes[i].Middlewares = append([]gin.HandlerFunc{c.userAuth.RequirePermission(route.Permission)}, route.Middlewares...)
+		}
+	}
+}
+
+func (c *DependencyContainer) registerAuthHandlers(store db.Store, cacheService *cache.Service, tokenMaker *token.Maker,
+	twoFactorService interface_service.TwoFactorService) {
+	userRepo := repository.NewUserRepository(store, cacheService)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(store)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, twoFactorService, tokenMaker)
+	authHandler := handler.NewAuthHandler(authService)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
+
+	c.AuthHandler = authHandler
+	c.TwoFactorHandler = twoFactorHandler
+
+	// Logging in and refreshing only need the API key, the refresh token proves who the caller is
+	c.handlers["auth"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "/login",
+			HandlerFunc: authHandler.Login,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/refresh",
+			HandlerFunc: authHandler.RefreshToken,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/logout",
+			HandlerFunc: authHandler.Logout,
+		},
+		// The challenge token from the login proves who the caller is
+		{
+			Method:      http.MethodPost,
+			Path:        "/2fa/verify",
+			HandlerFunc: authHandler.VerifyTwoFactor,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/2fa/enroll",
+			HandlerFunc: twoFactorHandler.EnrollTwoFactor,
+			Permission:  rbac.TwoFactorManage,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/2fa/confirm",
+			HandlerFunc: twoFactorHandler.ConfirmTwoFactor,
+			Permission:  rbac.TwoFactorManage,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/2fa",
+			HandlerFunc: twoFactorHandler.DisableTwoFactor,
+			Permission:  rbac.TwoFactorManage,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerAPIKeyHandlers(store db.Store, cacheService *cache.Service, signer *signing.Signer) {
+	apiKeyRepo := repository.NewAPIKeyRepository(store, cacheService)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, signer)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	nonceRepo := repository.NewNonceRepository(cacheService)
+	requestSigningService := service.NewRequestSigningService(nonceRepo, signer)
+
+	c.APIKeyHandler = apiKeyHandler
+	c.apiKey = middleware.NewAPIKey(apiKeyService)
+	c.requestSignature = middleware.NewRequestSignature(requestSigningService)
+
+	c.handlers["api-keys"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: apiKeyHandler.IssueAPIKey,
+			Permission:  rbac.APIKeysManage,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "",
+			HandlerFunc: apiKeyHandler.ListAPIKeys,
+			Permission:  rbac.APIKeysManage,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:api_key_id/rotate",
+			HandlerFunc: apiKeyHandler.RotateAPIKey,
+			Permission:  rbac.APIKeysManage,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/:api_key_id",
+			HandlerFunc: apiKeyHandler.RevokeAPIKey,
+			Permission:  rbac.APIKeysManage,
+		},
+	}
+}
+
 func (c *DependencyContainer) registerAccountTypeHandlers(store db.Store, cacheService *cache.Service) {
 	accountTypeRepo := repository.NewAccountTypeRepository(store, cacheService)
 	accountTypeService := service.NewAccountTypeService(accountTypeRepo)
@@ -53,6 +252,7 @@ func (c *DependencyContainer) registerAccountTypeHandlers(store db.Store, cacheS
 			Method:      http.MethodPost,
 			Path:        "",
 			HandlerFunc: accountTypeHandler.CreateAccountType,
+			Permission:  rbac.ReferenceDataManage,
 		},
 		{
 			Method:      http.MethodGet,
@@ -68,16 +268,611 @@ func (c *DependencyContainer) registerAccountTypeHandlers(store db.Store, cacheS
 			Method:      http.MethodPut,
 			Path:        "/:account_type",
 			HandlerFunc: accountTypeHandler.UpdateAccountType,
+			Permission:  rbac.ReferenceDataManage,
 		},
 		{
 			Method:      http.MethodPatch,
 			Path:        "/:account_type",
 			HandlerFunc: accountTypeHandler.UpdateAccountType,
+			Permission:  rbac.ReferenceDataManage,
 		},
 		{
 			Method:      http.MethodDelete,
 			Path:        "/:account_type",
 			HandlerFunc: accountTypeHandler.DeleteAccountType,
+			Permission:  rbac.ReferenceDataManage,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerAccountHandlers(store db.Store, cacheService *cache.Service) {
+	accountRepo := repository.NewAccountRepository(store)
+	currencyRepo := repository.NewCurrencyRepository(store, cacheService)
+	accountService := service.NewAccountService(accountRepo, currencyRepo)
+	accountHandler := handler.NewAccountHandler(accountService)
+
+	c.AccountHandler = accountHandler
+
+	c.handlers["accounts"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: accountHandler.CreateAccount,
+			Permission:  rbac.AccountsWrite,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "",
+			HandlerFunc: accountHandler.ListAccountsByUser,
+			Permission:  rbac.AccountsRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:account_id",
+			HandlerFunc: accountHandler.GetAccount,
+			Permission:  rbac.AccountsRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/number/:account_number",
+			HandlerFunc: accountHandler.GetAccountByNumber,
+			Permission:  rbac.AccountsRead,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/:account_id",
+			HandlerFunc: accountHandler.CloseAccount,
+			Permission:  rbac.AccountsWrite,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/:account_id/hard",
+			HandlerFunc: accountHandler.HardDeleteAccount,
+			Permission:  rbac.AccountsManage,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/:account_id/terms",
+			HandlerFunc: accountHandler.UpdateAccountTerms,
+			Permission:  rbac.AccountsManage,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerUserHandlers(store db.Store, cacheService *cache.Service) {
+	userRepo := repository.NewUserRepository(store, cacheService)
+	profileImageStorage := service.NewS3StorageService(s3.NewS3Service(s3.S3ConfigFromEnv()), "profile_images")
+	userService := service.NewUserService(userRepo, profileImageStorage)
+	userHandler := handler.NewUserHandler(userService)
+
+	c.UserHandler = userHandler
+
+	c.handlers["users"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: userHandler.CreateUser,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "",
+			HandlerFunc: userHandler.ListUsers,
+			Permission:  rbac.UsersRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:user_id",
+			HandlerFunc: userHandler.GetUser,
+			Permission:  rbac.UsersRead,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/:user_id",
+			HandlerFunc: userHandler.UpdateUser,
+			Permission:  rbac.UsersWrite,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        "/:user_id",
+			HandlerFunc: userHandler.UpdateUser,
+			Permission:  rbac.UsersWrite,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/:user_id",
+			HandlerFunc: userHandler.DeleteUser,
+			Permission:  rbac.UsersWrite,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/:user_id/hard",
+			HandlerFunc: userHandler.HardDeleteUser,
+			Permission:  rbac.UsersManage,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/:user_id/role",
+			HandlerFunc: userHandler.UpdateUserRole,
+			Permission:  rbac.UsersManage,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerUserAccountHandlers(store db.Store) error {
+	sqlStore, err := db.GetSQLStore(store)
+	if err != nil {
+		return err
+	}
+	userAccountService := service.NewUserAccountService(sqlStore)
+	userAccountHandler := handler.NewUserAccountHandler(userAccountService)
+
+	c.UserAccountHandler = userAccountHandler
+
+	c.handlers["onboarding"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: userAccountHandler.CreateUserAccount,
+		},
+	}
+	return nil
+}
+
+func (c *DependencyContainer) registerTransferHandlers(store db.Store, cacheService *cache.Service,
+	twoFactorService interface_service.TwoFactorService) {
+	accountRepo := repository.NewAccountRepository(store)
+	currencyRepo := repository.NewCurrencyRepository(store, cacheService)
+	transferService := service.NewTransferService(accountRepo, currencyRepo, twoFactorService)
+	transferHandler := handler.NewTransferHandler(transferService)
+
+	idempotencyRepo := repository.NewIdempotencyRepository(store, cacheService)
+	idempotency := middleware.Idempotency(service.NewIdempotencyService(idempotencyRepo))
+
+	c.TransferHandler = transferHandler
+
+	c.handlers["transfers"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: transferHandler.CreateTransfer,
+			Permission:  rbac.TransfersCreate,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+	}
+
+	c.handlers["transactions"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "/:transaction_number/reverse",
+			HandlerFunc: transferHandler.ReverseTransaction,
+			Permission:  rbac.TransactionsReverse,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:transaction_number/refund",
+			HandlerFunc: transferHandler.RefundTransaction,
+			Permission:  rbac.TransactionsReverse,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+	}
+}
+
+func (c *DependencyContainer) registerHoldHandlers(store db.Store, cacheService *cache.Service,
+	twoFactorService interface_service.TwoFactorService) {
+	accountRepo := repository.NewAccountRepository(store)
+	holdRepo := repository.NewHoldRepository(store)
+	currencyRepo := repository.NewCurrencyRepository(store, cacheService)
+	holdService := service.NewHoldService(accountRepo, holdRepo, currencyRepo, twoFactorService)
+	holdHandler := handler.NewHoldHandler(holdService)
+
+	idempotencyRepo := repository.NewIdempotencyRepository(store, cacheService)
+	idempotency := middleware.Idempotency(service.NewIdempotencyService(idempotencyRepo))
+
+	c.HoldHandler = holdHandler
+
+	c.handlers["holds"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: holdHandler.AuthorizeHold,
+			Permission:  rbac.TransfersCreate,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "",
+			HandlerFunc: holdHandler.ListOpenHolds,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:hold_number",
+			HandlerFunc: holdHandler.GetHold,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:hold_number/capture",
+			HandlerFunc: holdHandler.CaptureHold,
+			Permission:  rbac.TransfersCreate,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:hold_number/release",
+			HandlerFunc: holdHandler.ReleaseHold,
+			Permission:  rbac.TransfersCreate,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+	}
+}
+
+func (c *DependencyContainer) registerScheduledTransferHandlers(store db.Store, cacheService *cache.Service,
+	twoFactorService interface_service.TwoFactorService) {
+	accountRepo := repository.NewAccountRepository(store)
+	scheduledRepo := repository.NewScheduledTransferRepository(store)
+	currencyRepo := repository.NewCurrencyRepository(store, cacheService)
+	scheduledService := service.NewScheduledTransferService(accountRepo, scheduledRepo, currencyRepo, twoFactorService)
+	scheduledHandler := handler.NewScheduledTransferHandler(scheduledService)
+
+	c.ScheduledTransferHandler = scheduledHandler
+
+	c.handlers["scheduled-transfers"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: scheduledHandler.CreateScheduledTransfer,
+			Permission:  rbac.TransfersCreate,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "",
+			HandlerFunc: scheduledHandler.ListScheduledTransfers,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:schedule_number",
+			HandlerFunc: scheduledHandler.GetScheduledTransfer,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:schedule_number/executions",
+			HandlerFunc: scheduledHandler.ListExecutions,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:schedule_number/pause",
+			HandlerFunc: scheduledHandler.PauseScheduledTransfer,
+			Permission:  rbac.TransfersCreate,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:schedule_number/resume",
+			HandlerFunc: scheduledHandler.ResumeScheduledTransfer,
+			Permission:  rbac.TransfersCreate,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/:schedule_number",
+			HandlerFunc: scheduledHandler.CancelScheduledTransfer,
+			Permission:  rbac.TransfersCreate,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerTransferBatchHandlers(store db.Store, cacheService *cache.Service,
+	twoFactorService interface_service.TwoFactorService) {
+	accountRepo := repository.NewAccountRepository(store)
+	batchRepo := repository.NewTransferBatchRepository(store)
+	currencyRepo := repository.NewCurrencyRepository(store, cacheService)
+
+	// CSV uploads go through the upload queue, without RabbitMQ only JSON batches are accepted
+	var uploads *upload_service.UploadService
+	if rmqClient := rabbitmq.GetClient(); rmqClient != nil {
+		var err error
+		uploads, err = upload_service.NewUploadService(
+			filepath.Join(os.TempDir(), "transfer_batches"),
+			batchUploadMaxSize,
+			rmqClient,
+			pkg_repository.NewSQLUploadRepository(store, cacheService),
+			batchUploadExchange,
+			batchUploadQueue,
+		)
+		if err != nil {
+			logger.GetLogger().Error("Failed to create batch upload service", zap.Error(err))
+			uploads = nil
+		}
+	}
+
+	batchService := service.NewTransferBatchService(accountRepo, batchRepo, currencyRepo, twoFactorService, uploads)
+	batchHandler := handler.NewTransferBatchHandler(batchService)
+
+	if uploads != nil {
+		c.jobs = append(c.jobs, jobs.NewUploadConsumerJob("transfer_batch_uploads", uploads, batchService.ImportUpload))
+	}
+
+	idempotencyRepo := repository.NewIdempotencyRepository(store, cacheService)
+	idempotency := middleware.Idempotency(service.NewIdempotencyService(idempotencyRepo))
+
+	c.TransferBatchHandler = batchHandler
+
+	c.handlers["batch-transfers"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: batchHandler.CreateTransferBatch,
+			Permission:  rbac.TransfersCreate,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/upload",
+			HandlerFunc: batchHandler.UploadTransferBatch,
+			Permission:  rbac.TransfersCreate,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:batch_number",
+			HandlerFunc: batchHandler.GetTransferBatch,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:batch_number/items",
+			HandlerFunc: batchHandler.ListTransferBatchItems,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:batch_number/report",
+			HandlerFunc: batchHandler.DownloadTransferBatchReport,
+			Permission:  rbac.TransfersRead,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerReconciliationHandlers(store db.Store) {
+	reconciliationRepo := repository.NewReconciliationRepository(store)
+	reconciliationService := service.NewReconciliationService(reconciliationRepo)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService)
+
+	c.ReconciliationHandler = reconciliationHandler
+
+	c.handlers["reconciliation"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "/runs",
+			HandlerFunc: reconciliationHandler.RunReconciliation,
+			Permission:  rbac.LedgerOperate,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/runs",
+			HandlerFunc: reconciliationHandler.ListReconciliationRuns,
+			Permission:  rbac.LedgerRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/runs/:run_number",
+			HandlerFunc: reconciliationHandler.GetReconciliationRun,
+			Permission:  rbac.LedgerRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/runs/:run_number/discrepancies",
+			HandlerFunc: reconciliationHandler.ListLedgerDiscrepancies,
+			Permission:  rbac.LedgerRead,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerInterestHandlers(store db.Store) {
+	interestRepo := repository.NewInterestRepository(store)
+	interestService := service.NewInterestService(interestRepo)
+	interestHandler := handler.NewInterestHandler(interestService)
+
+	c.InterestHandler = interestHandler
+
+	c.handlers["interest"] = []RouteHandler{
+		{
+			Method:      http.MethodGet,
+			Path:        "/settings",
+			HandlerFunc: interestHandler.ListInterestSettings,
+			Permission:  rbac.LedgerRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/settings/:account_type",
+			HandlerFunc: interestHandler.GetInterestSettings,
+			Permission:  rbac.LedgerRead,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/settings/:account_type",
+			HandlerFunc: interestHandler.UpsertInterestSettings,
+			Permission:  rbac.LedgerOperate,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/accounts/:account_id/accruals",
+			HandlerFunc: interestHandler.ListInterestAccruals,
+			Permission:  rbac.LedgerRead,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/runs",
+			HandlerFunc: interestHandler.RunInterest,
+			Permission:  rbac.LedgerOperate,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerFeeHandlers(store db.Store) {
+	feeRepo := repository.NewFeeRepository(store)
+	accountRepo := repository.NewAccountRepository(store)
+	feeService := service.NewFeeService(feeRepo, accountRepo)
+	feeHandler := handler.NewFeeHandler(feeService)
+
+	c.FeeHandler = feeHandler
+
+	c.handlers["fees"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "/preview",
+			HandlerFunc: feeHandler.PreviewFees,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/schedules",
+			HandlerFunc: feeHandler.ListFeeSchedules,
+			Permission:  rbac.LedgerRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/schedules/:account_type",
+			HandlerFunc: feeHandler.GetFeeSchedule,
+			Permission:  rbac.LedgerRead,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/schedules/:account_type",
+			HandlerFunc: feeHandler.UpsertFeeSchedule,
+			Permission:  rbac.LedgerOperate,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/income-accounts",
+			HandlerFunc: feeHandler.ListFeeIncomeAccounts,
+			Permission:  rbac.LedgerRead,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/income-accounts/:currency_code",
+			HandlerFunc: feeHandler.SetFeeIncomeAccount,
+			Permission:  rbac.LedgerOperate,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/accounts/:account_id/charges",
+			HandlerFunc: feeHandler.ListFeeCharges,
+			Permission:  rbac.LedgerRead,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/runs",
+			HandlerFunc: feeHandler.RunFees,
+			Permission:  rbac.LedgerOperate,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerFixedDepositHandlers(store db.Store, cacheService *cache.Service,
+	twoFactorService interface_service.TwoFactorService) {
+	accountRepo := repository.NewAccountRepository(store)
+	depositRepo := repository.NewFixedDepositRepository(store)
+	currencyRepo := repository.NewCurrencyRepository(store, cacheService)
+	depositService := service.NewFixedDepositService(accountRepo, depositRepo, currencyRepo, twoFactorService)
+	depositHandler := handler.NewFixedDepositHandler(depositService)
+
+	c.FixedDepositHandler = depositHandler
+
+	idempotencyRepo := repository.NewIdempotencyRepository(store, cacheService)
+	idempotency := middleware.Idempotency(service.NewIdempotencyService(idempotencyRepo))
+
+	c.handlers["fixed-deposits"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: depositHandler.OpenFixedDeposit,
+			Permission:  rbac.TransfersCreate,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:deposit_number",
+			HandlerFunc: depositHandler.GetFixedDeposit,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/accounts/:account_id",
+			HandlerFunc: depositHandler.ListFixedDeposits,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/:deposit_number/break",
+			HandlerFunc: depositHandler.BreakFixedDeposit,
+			Permission:  rbac.TransfersCreate,
+			Middlewares: []gin.HandlerFunc{idempotency},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/runs",
+			HandlerFunc: depositHandler.RunFixedDepositMaturity,
+			Permission:  rbac.LedgerOperate,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/terms",
+			HandlerFunc: depositHandler.ListFixedDepositTerms,
+			Permission:  rbac.TransfersRead,
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/terms/:term_months",
+			HandlerFunc: depositHandler.UpsertFixedDepositTerm,
+			Permission:  rbac.LedgerOperate,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerStatementHandlers(store db.Store) {
+	accountRepo := repository.NewAccountRepository(store)
+	statementRepo := repository.NewStatementRepository(store)
+	statementStorage := service.NewS3StorageService(s3.NewS3Service(s3.S3ConfigFromEnv()), "statements")
+	statementService := service.NewStatementService(accountRepo, statementRepo, statementStorage)
+	statementHandler := handler.NewStatementHandler(statementService)
+
+	c.StatementHandler = statementHandler
+	c.jobs = append(c.jobs, jobs.NewStatementJob(jobs.DefaultStatementInterval, statementService.ProcessPending))
+
+	c.handlers["statements"] = []RouteHandler{
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: statementHandler.CreateStatement,
+			Permission:  rbac.StatementsCreate,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:statement_number",
+			HandlerFunc: statementHandler.GetStatement,
+			Permission:  rbac.StatementsRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:statement_number/download",
+			HandlerFunc: statementHandler.DownloadStatement,
+			Permission:  rbac.StatementsRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/accounts/:account_id",
+			HandlerFunc: statementHandler.ListStatements,
+			Permission:  rbac.StatementsRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/accounts/:account_id/download",
+			HandlerFunc: statementHandler.DownloadAccountStatement,
+			Permission:  rbac.StatementsRead,
 		},
 	}
 }
@@ -86,6 +881,26 @@ func (c *DependencyContainer) GetRouteHandlers(groupPrefix string) []RouteHandle
 	return c.handlers[groupPrefix]
 }
 
+// BackgroundJobs returns the jobs the registered features need running next to the HTTP server
+func (c *DependencyContainer) BackgroundJobs() []jobs.Job {
+	return c.jobs
+}
+
+// RequirePermission returns the permission check routes registered outside the container are guarded with
+func (c *DependencyContainer) RequirePermission(permission rbac.Permission) gin.HandlerFunc {
+	return c.userAuth.RequirePermission(permission)
+}
+
+// ValidateAPIKey returns the API key check every request is sent through
+func (c *DependencyContainer) ValidateAPIKey() gin.HandlerFunc {
+	return c.apiKey.ValidateAPIKey()
+}
+
+// VerifySignature returns the request signature check, it must run after ValidateAPIKey
+func (c *DependencyContainer) VerifySignature() gin.HandlerFunc {
+	return c.requestSignature.VerifySignature()
+}
+
 func (c *DependencyContainer) GetCacheService() *cache.Service {
 	return c.cacheService
 }
@@ -96,3 +911,86 @@ func (c *DependencyContainer) Close() error {
 	}
 	return nil
 }
+
+func (c *DependencyContainer) registerAuditHandlers(store db.Store) {
+	auditRepo := repository.NewAuditRepository(store)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditService)
+
+	c.AuditHandler = auditHandler
+
+	c.handlers["audit-trail"] = []RouteHandler{
+		{
+			Method:      http.MethodGet,
+			Path:        "",
+			HandlerFunc: auditHandler.ListAuditTrail,
+			Permission:  rbac.AuditRead,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:audit_id",
+			HandlerFunc: auditHandler.GetAuditRecord,
+			Permission:  rbac.AuditRead,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerExchangeRateHandlers(store db.Store) {
+	exchangeRateRepo := repository.NewExchangeRateRepository(store)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo)
+	exchangeRateHandler := handler.NewExchangeRateHandler(exchangeRateService)
+
+	c.ExchangeRateHandler = exchangeRateHandler
+
+	c.handlers["exchange-rates"] = []RouteHandler{
+		{
+			Method:      http.MethodGet,
+			Path:        "",
+			HandlerFunc: exchangeRateHandler.GetExchangeRate,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:currency_code/history",
+			HandlerFunc: exchangeRateHandler.ListExchangeRateHistory,
+		},
+	}
+}
+
+func (c *DependencyContainer) registerCurrencyHandlers(store db.Store, cacheService *cache.Service) {
+	currencyRepo := repository.NewCurrencyRepository(store, cacheService)
+	currencyService := service.NewCurrencyService(currencyRepo)
+	currencyHandler := handler.NewCurrencyHandler(currencyService)
+
+	c.CurrencyHandler = currencyHandler
+
+	c.handlers["currencies"] = []RouteHandler{
+		{
+			Method:      http.MethodGet,
+			Path:        "",
+			HandlerFunc: currencyHandler.ListCurrencies,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/:currency_code",
+			HandlerFunc: currencyHandler.GetCurrency,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "",
+			HandlerFunc: currencyHandler.CreateCurrency,
+			Permission:  rbac.ReferenceDataManage,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        "/:currency_code",
+			HandlerFunc: currencyHandler.UpdateCurrency,
+			Permission:  rbac.ReferenceDataManage,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        "/:currency_code",
+			HandlerFunc: currencyHandler.DeleteCurrency,
+			Permission:  rbac.ReferenceDataManage,
+		},
+	}
+}
@@ -1,6 +1,11 @@
 package dto
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/money"
+)
 
 // CreateUserAccountResponse represents the combined response after creating both user and account
 type CreateUserAccountRequest struct {
@@ -14,10 +19,8 @@ type CreateUserAccountRequest struct {
 	ProfileImageUrl string `json:"profile_image_url"`
 
 	// Account details
-	AccountType    string  `json:"account_type" binding:"required"`
-	CurrencyCode   string  `json:"currency_code" binding:"required"`
-	InterestRate   float64 `json:"interest_rate" binding:"required"`
-	OverdraftLimit float64 `json:"overdraft_limit" binding:"required"`
+	AccountType  string `json:"account_type" binding:"required"`
+	CurrencyCode string `json:"currency_code" binding:"required"`
 }
 
 // CreateAccountTypeRequest represents the request for creating an account type
@@ -26,10 +29,25 @@ type CreateAccountTypeRequest struct {
 	Description string `json:"description" binding:"required,min=2,max=200"`
 }
 
+// CreateAccountRequest represents the request for opening a new account. Accounts open without
+// interest or overdraft, only admins grant either through UpdateAccountTermsRequest.
+type CreateAccountRequest struct {
+	UserID       int64  `json:"user_id" binding:"required,min=1"`
+	AccountType  string `json:"account_type" binding:"required"`
+	CurrencyCode string `json:"currency_code" binding:"required,len=3"`
+}
+
+// UpdateAccountTermsRequest represents the interest rate, in percent, and the overdraft limit of an
+// account. Fields left out keep their current value.
+type UpdateAccountTermsRequest struct {
+	InterestRate   *money.Rate  `json:"interest_rate" binding:"omitempty,min=0,max=100"`
+	OverdraftLimit *money.Money `json:"overdraft_limit"`
+}
+
 // CreateUserRequest defines the input for creating a new user
 type CreateUserRequest struct {
 	Username     string                `form:"username" binding:"required"`
-	Password     string                `form:"password" binding:"required"`
+	Password     string                `form:"password" binding:"required,min=6"`
 	Email        string                `form:"email" binding:"required,email"`
 	FirstName    string                `form:"first_name"`
 	LastName     string                `form:"last_name"`
@@ -47,9 +65,297 @@ type UpdateUserRequest struct {
 	ProfileImage *multipart.FileHeader `form:"profile_image"`
 }
 
+// UpdateUserRoleRequest defines the input for changing the role of a user
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
 // ChangePasswordRequest defines the input for changing a user's password
 type ChangePasswordRequest struct {
 	CurrentPassword string `form:"current_password" binding:"required"`
-	NewPassword     string `form:"new_password" binding:"required"`
+	NewPassword     string `form:"new_password" binding:"required,min=6"`
 	ConfirmPassword string `form:"confirm_password" binding:"required,eqfield=NewPassword"`
 }
+
+// LoginRequest represents the credentials a user logs in with
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	// UserAgent is filled from the request headers, never from the request body
+	UserAgent string `json:"-"`
+}
+
+// RefreshTokenRequest carries a refresh token to exchange or revoke
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	// UserAgent is filled from the request headers, never from the request body
+	UserAgent string `json:"-"`
+}
+
+// TwoFactorLoginRequest carries the challenge token of a login and the second factor, a code from
+// the authenticator app or a recovery code
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=32"`
+	// UserAgent is filled from the request headers, never from the request body
+	UserAgent string `json:"-"`
+}
+
+// TwoFactorCodeRequest carries a code from the authenticator app, or a recovery code where one is accepted
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// IssueAPIKeyRequest represents the request body for issuing an API key. Keys without ExpiresAt
+// work until they are revoked, requests sent with SigningRequired keys must be signed.
+type IssueAPIKeyRequest struct {
+	Name            string     `json:"name" binding:"required,max=100"`
+	OwnerUserID     int64      `json:"owner_user_id" binding:"required,min=1,max=2147483647"`
+	Scopes          []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	SigningRequired bool       `json:"signing_required"`
+}
+
+// RotateAPIKeyRequest represents the optional request body for rotating an API key. The old key
+// keeps working for OverlapMinutes, API_KEY_ROTATION_OVERLAP when it is not set.
+type RotateAPIKeyRequest struct {
+	ExpiresAt      *time.Time `json:"expires_at"`
+	OverlapMinutes int64      `json:"overlap_minutes" binding:"omitempty,min=1,max=43200"`
+}
+
+// APIKeyQuery represents the filters of an API key listing, keys of every owner are listed
+// without OwnerUserID
+type APIKeyQuery struct {
+	OwnerUserID int64 `form:"owner_user_id" binding:"omitempty,min=1,max=2147483647"`
+}
+
+// CreateTransferRequest represents the request body for moving money between two accounts
+type CreateTransferRequest struct {
+	FromAccountID int64       `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64       `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount        money.Money `json:"amount"`
+	CurrencyCode  string      `json:"currency_code" binding:"required,len=3"`
+	Description   string      `json:"description" binding:"max=255"`
+	// ReferenceNumber is filled from the Idempotency-Key, never from the request body
+	ReferenceNumber string `json:"-"`
+	// TOTPCode is filled from the X-TOTP-Code header, large transfers and transfers to new
+	// beneficiaries need it
+	TOTPCode string `json:"-"`
+}
+
+// AuthorizeHoldRequest represents the request body for reserving funds for a merchant
+type AuthorizeHoldRequest struct {
+	AccountID         int64       `json:"account_id" binding:"required,min=1"`
+	MerchantAccountID int64       `json:"merchant_account_id" binding:"required,min=1,nefield=AccountID"`
+	Amount            money.Money `json:"amount"`
+	CurrencyCode      string      `json:"currency_code" binding:"required,len=3"`
+	Description       string      `json:"description" binding:"max=255"`
+	// ExpiresInMinutes defaults to seven days when omitted
+	ExpiresInMinutes int    `json:"expires_in_minutes" binding:"omitempty,min=1,max=43200"`
+	ReferenceNumber  string `json:"-"`
+	// TOTPCode is filled from the X-TOTP-Code header, large holds and holds for new merchants need it
+	TOTPCode string `json:"-"`
+}
+
+// CaptureHoldRequest represents the request body for capturing a hold, an omitted amount captures it in full
+type CaptureHoldRequest struct {
+	Amount *money.Money `json:"amount"`
+}
+
+// CreateScheduledTransferRequest represents the request body for a one-off or recurring transfer.
+// CronExpression is required for the CRON frequency and uses the five field minute hour
+// day-of-month month day-of-week syntax, evaluated in UTC.
+type CreateScheduledTransferRequest struct {
+	FromAccountID  int64       `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID    int64       `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount         money.Money `json:"amount"`
+	CurrencyCode   string      `json:"currency_code" binding:"required,len=3"`
+	Description    string      `json:"description" binding:"max=255"`
+	Frequency      string      `json:"frequency" binding:"required,oneof=ONCE DAILY WEEKLY MONTHLY CRON"`
+	CronExpression string      `json:"cron_expression" binding:"required_if=Frequency CRON,max=100"`
+	StartAt        time.Time   `json:"start_at" binding:"required"`
+	EndAt          *time.Time  `json:"end_at"`
+	MaxRetries     *int32      `json:"max_retries" binding:"omitempty,min=0,max=10"`
+	// TOTPCode is filled from the X-TOTP-Code header, large schedules and schedules to new
+	// beneficiaries need it
+	TOTPCode string `json:"-"`
+}
+
+// ReverseTransactionRequest represents the request body for reversing a transaction in full
+type ReverseTransactionRequest struct {
+	Description     string `json:"description" binding:"max=255"`
+	ReferenceNumber string `json:"-"`
+}
+
+// RefundTransactionRequest represents the request body for refunding part of a transaction
+type RefundTransactionRequest struct {
+	Amount          money.Money `json:"amount"`
+	Description     string      `json:"description" binding:"max=255"`
+	ReferenceNumber string      `json:"-"`
+}
+
+// CreateTransferBatchRequest represents the request body for a batch of transfers. ALL_OR_NOTHING
+// books every item or none of them, BEST_EFFORT books each item on its own.
+type CreateTransferBatchRequest struct {
+	Mode            string                     `json:"mode" binding:"required,oneof=ALL_OR_NOTHING BEST_EFFORT"`
+	Items           []TransferBatchItemRequest `json:"items" binding:"required,min=1,max=1000,dive"`
+	ReferenceNumber string                     `json:"-"`
+	// TOTPCode is filled from the X-TOTP-Code header, one code confirms every item of the batch
+	TOTPCode string `json:"-"`
+}
+
+// TransferBatchItemRequest represents one transfer of a batch
+type TransferBatchItemRequest struct {
+	FromAccountID int64       `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64       `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount        money.Money `json:"amount"`
+	CurrencyCode  string      `json:"currency_code" binding:"required,len=3"`
+	Description   string      `json:"description" binding:"max=255"`
+}
+
+// UploadTransferBatchRequest represents the multipart form for a batch read from a CSV file with
+// the header from_account_id,to_account_id,amount,currency_code,description
+type UploadTransferBatchRequest struct {
+	Mode            string                `form:"mode" binding:"required,oneof=ALL_OR_NOTHING BEST_EFFORT"`
+	File            *multipart.FileHeader `form:"file" binding:"required"`
+	ReferenceNumber string                `form:"-"`
+	// UserID is the user the access token was issued to, never taken from the form
+	UserID int32 `form:"-"`
+	// TOTPCode is filled from the X-TOTP-Code header, the file is read after the request so
+	// uploads always need it
+	TOTPCode string `form:"-"`
+}
+
+// RunReconciliationRequest represents the request body for a reconciliation run, an empty list of
+// account ids checks the whole ledger
+type RunReconciliationRequest struct {
+	AccountIDs []int64 `json:"account_ids" binding:"omitempty,max=1000,dive,min=1"`
+}
+
+// UpsertInterestSettingsRequest represents the request body for the interest settings of an account type
+type UpsertInterestSettingsRequest struct {
+	DayCountConvention string `json:"day_count_convention" binding:"required,oneof=ACT_365 30_360"`
+	PostingPeriod      string `json:"posting_period" binding:"required,oneof=MONTHLY QUARTERLY ANNUALLY"`
+	IsActive           *bool  `json:"is_active"`
+}
+
+// RunInterestRequest represents the request body for a manual interest run, through defaults to yesterday
+type RunInterestRequest struct {
+	Through string `json:"through" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// UpsertFeeScheduleRequest represents the request body for the fee schedule of an account type,
+// percentages are given in percent of the transfer amount
+type UpsertFeeScheduleRequest struct {
+	TransferFeeFlat       money.Money `json:"transfer_fee_flat"`
+	TransferFeePercent    money.Rate  `json:"transfer_fee_percent" binding:"min=0,max=100"`
+	FxMarkupPercent       money.Rate  `json:"fx_markup_percent" binding:"min=0,max=100"`
+	MonthlyMaintenanceFee money.Money `json:"monthly_maintenance_fee"`
+	OverdraftFee          money.Money `json:"overdraft_fee"`
+	IsActive              *bool       `json:"is_active"`
+}
+
+// SetFeeIncomeAccountRequest represents the request body for the account that collects the fees of a currency
+type SetFeeIncomeAccountRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+}
+
+// FeePreviewRequest represents the request body for quoting the fees of a transfer
+type FeePreviewRequest struct {
+	FromAccountID int64       `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64       `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount        money.Money `json:"amount"`
+	CurrencyCode  string      `json:"currency_code" binding:"required,len=3"`
+}
+
+// RunFeesRequest represents the request body for a manual periodic fee run, day defaults to yesterday
+type RunFeesRequest struct {
+	Day string `json:"day" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// OpenFixedDepositRequest represents the request body for opening a fixed deposit funded from the linked account,
+// which also receives the payout at maturity. The interest rate and early break penalty are those of the term.
+type OpenFixedDepositRequest struct {
+	LinkedAccountID     int64       `json:"linked_account_id" binding:"required,min=1"`
+	Principal           money.Money `json:"principal"`
+	TermMonths          int32       `json:"term_months" binding:"required,min=1,max=120"`
+	MaturityInstruction string      `json:"maturity_instruction" binding:"omitempty,oneof=PAYOUT ROLLOVER"`
+	// ReferenceNumber is filled from the Idempotency-Key, never from the request body
+	ReferenceNumber string `json:"-"`
+	// TOTPCode is filled from the X-TOTP-Code header, large deposits need it
+	TOTPCode string `json:"-"`
+}
+
+// UpsertFixedDepositTermRequest represents the request body for the rates of a fixed deposit term, in percent
+type UpsertFixedDepositTermRequest struct {
+	InterestRate             money.Rate `json:"interest_rate" binding:"min=0,max=100"`
+	EarlyBreakPenaltyPercent money.Rate `json:"early_break_penalty_percent" binding:"min=0,max=100"`
+	IsActive                 *bool      `json:"is_active"`
+}
+
+// StatementPeriodQuery represents the query parameters of a direct statement download
+type StatementPeriodQuery struct {
+	From   string `form:"from" binding:"required"`
+	To     string `form:"to" binding:"required"`
+	Format string `form:"format" binding:"required"`
+}
+
+// CreateStatementRequest represents the request body for generating a statement in the background
+type CreateStatementRequest struct {
+	AccountID int64  `json:"account_id" binding:"required,min=1"`
+	From      string `json:"from" binding:"required"`
+	To        string `json:"to" binding:"required"`
+	Format    string `json:"format" binding:"required"`
+}
+
+// AuditTrailQuery represents the filters of an audit trail search, every filter is optional.
+// From and To are RFC 3339 timestamps, From inclusive and To exclusive.
+type AuditTrailQuery struct {
+	Table    string `form:"table"`
+	RecordID string `form:"record_id"`
+	UserID   int64  `form:"user_id" binding:"omitempty,min=1,max=2147483647"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+}
+
+// ExchangeRateQuery asks for the rate from one currency to another. At is an optional RFC 3339
+// timestamp, the rate valid at that time is returned instead of the current one.
+type ExchangeRateQuery struct {
+	From string `form:"from" binding:"required,len=3"`
+	To   string `form:"to" binding:"required,len=3"`
+	At   string `form:"at"`
+}
+
+// ExchangeRateHistoryQuery limits a rate history to the rates that became valid in a period.
+// From and To are optional RFC 3339 timestamps, From inclusive and To exclusive.
+type ExchangeRateHistoryQuery struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// CreateCurrencyRequest represents the request body for adding a currency to the registry. Minor
+// units default to the ISO 4217 value for ISO currencies and to 2 otherwise. ExchangeRate is the
+// value of one unit in the base currency, a currency without one can only be used in transfers
+// that stay in the same currency.
+type CreateCurrencyRequest struct {
+	CurrencyCode string      `json:"currency_code" binding:"required,len=3,alpha"`
+	CurrencyName string      `json:"currency_name" binding:"required,max=50"`
+	Symbol       string      `json:"symbol" binding:"omitempty,max=5"`
+	MinorUnits   *int16      `json:"minor_units" binding:"omitempty,min=0,max=4"`
+	ExchangeRate *money.Rate `json:"exchange_rate" binding:"omitempty,gt=0,lt=10000"`
+	IsActive     *bool       `json:"is_active"`
+}
+
+// UpdateCurrencyRequest represents the request body for changing a currency, omitted fields are kept
+type UpdateCurrencyRequest struct {
+	CurrencyName *string     `json:"currency_name" binding:"omitempty,min=1,max=50"`
+	Symbol       *string     `json:"symbol" binding:"omitempty,max=5"`
+	MinorUnits   *int16      `json:"minor_units" binding:"omitempty,min=0,max=4"`
+	ExchangeRate *money.Rate `json:"exchange_rate" binding:"omitempty,gt=0,lt=10000"`
+	IsActive     *bool       `json:"is_active"`
+}
+
+// CurrencyQuery represents the query parameters of the currency list
+type CurrencyQuery struct {
+	IncludeInactive bool `form:"include_inactive"`
+}
@@ -2,7 +2,10 @@ package dto
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/riad/banksystemendtoend/util/money"
 )
 
 // CreateUserAccountResponse represents the combined response after creating both user and account
@@ -21,23 +24,75 @@ type UserResponse struct {
 	PhoneNumber     sql.NullString `json:"phone_number,omitempty"`
 	ProfileImageUrl sql.NullString `json:"profile_image_url,omitempty"`
 	IsActive        bool           `json:"is_active"`
+	Role            string         `json:"role"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 }
 
+// TokenResponse represents the tokens issued at login or on refresh
+type TokenResponse struct {
+	TokenType             string       `json:"token_type"`
+	AccessToken           string       `json:"access_token"`
+	AccessTokenExpiresAt  time.Time    `json:"access_token_expires_at"`
+	RefreshToken          string       `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"`
+	User                  UserResponse `json:"user"`
+}
+
+// TwoFactorChallengeResponse represents the answer to a login of a user with two-factor
+// authentication, the challenge token is exchanged for tokens together with a code
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired  bool      `json:"two_factor_required"`
+	ChallengeToken     string    `json:"challenge_token"`
+	ChallengeExpiresAt time.Time `json:"challenge_expires_at"`
+}
+
+// TwoFactorEnrollmentResponse represents the secret to add to an authenticator app
+type TwoFactorEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResponse represents the recovery codes issued when two-factor authentication is
+// enabled, they are not shown again
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// APIKeyResponse represents an API key. Key and SigningSecret are only set in the response that
+// issues the key, they are not shown again.
+type APIKeyResponse struct {
+	APIKeyID        int32      `json:"api_key_id"`
+	Key             string     `json:"key,omitempty"`
+	SigningSecret   string     `json:"signing_secret,omitempty"`
+	KeyPrefix       string     `json:"key_prefix"`
+	Name            string     `json:"name"`
+	OwnerUserID     int32      `json:"owner_user_id"`
+	Scopes          []string   `json:"scopes"`
+	SigningRequired bool       `json:"signing_required"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	RotatedFrom     *int32     `json:"rotated_from,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
 // AccountResponse represents the account details in the response
 type AccountResponse struct {
-	AccountID      int64     `json:"account_id"`
-	UserID         int64     `json:"user_id"`
-	AccountNumber  string    `json:"account_number"`
-	AccountType    string    `json:"account_type"`
-	CurrencyCode   string    `json:"currency_code"`
-	Balance        float64   `json:"balance"`
-	InterestRate   float64   `json:"interest_rate"`
-	OverdraftLimit float64   `json:"overdraft_limit"`
-	IsActive       bool      `json:"is_active"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	AccountID      int64       `json:"account_id"`
+	UserID         int64       `json:"user_id"`
+	AccountNumber  string      `json:"account_number"`
+	AccountType    string      `json:"account_type"`
+	CurrencyCode   string      `json:"currency_code"`
+	Balance        money.Money `json:"balance"`
+	InterestRate   money.Rate  `json:"interest_rate"`
+	OverdraftLimit money.Money `json:"overdraft_limit"`
+	IsActive       bool        `json:"is_active"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	// HeldAmount is reserved by open authorization holds, AvailableBalance is the balance minus that amount
+	HeldAmount       money.Money `json:"held_amount"`
+	AvailableBalance money.Money `json:"available_balance"`
 }
 
 // AccountTypeResponse represents the response for an account type
@@ -54,3 +109,361 @@ type UpdateAccountTypeParams struct {
 	Description string `json:"description"`
 	IsActive    bool   `json:"is_active"`
 }
+
+// TransactionResponse represents the transaction details in the response
+type TransactionResponse struct {
+	TransactionID         int64        `json:"transaction_id"`
+	TransactionNumber     string       `json:"transaction_number"`
+	ReferenceNumber       string       `json:"reference_number"`
+	FromAccountID         int64        `json:"from_account_id"`
+	ToAccountID           int64        `json:"to_account_id"`
+	TypeCode              string       `json:"type_code"`
+	StatusCode            string       `json:"status_code"`
+	Amount                money.Money  `json:"amount"`
+	CurrencyCode          string       `json:"currency_code"`
+	ExchangeRate          money.Rate   `json:"exchange_rate"`
+	ConvertedAmount       *money.Money `json:"converted_amount,omitempty"`
+	ConvertedCurrency     string       `json:"converted_currency_code,omitempty"`
+	Description           string       `json:"description,omitempty"`
+	OriginalTransactionID int64        `json:"original_transaction_id,omitempty"`
+	TransactionDate       time.Time    `json:"transaction_date"`
+}
+
+// EntryResponse represents a single ledger entry in the response
+type EntryResponse struct {
+	EntryID      int64       `json:"entry_id"`
+	AccountID    int64       `json:"account_id"`
+	Amount       money.Money `json:"amount"`
+	CurrencyCode string      `json:"currency_code"`
+	ExchangeRate money.Rate  `json:"exchange_rate"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// TransferResponse represents the result of a completed transfer with both updated balances
+type TransferResponse struct {
+	Transaction TransactionResponse `json:"transaction"`
+	FromEntry   EntryResponse       `json:"from_entry"`
+	ToEntry     EntryResponse       `json:"to_entry"`
+	FromAccount AccountResponse     `json:"from_account"`
+	ToAccount   AccountResponse     `json:"to_account"`
+	// Fees lists the fees charged to the sender, FromAccount already includes them
+	Fees []FeeChargeResponse `json:"fees,omitempty"`
+}
+
+// ReversalResponse represents a refund or reversal together with the transaction it compensates
+type ReversalResponse struct {
+	Original    TransactionResponse `json:"original"`
+	Transaction TransactionResponse `json:"transaction"`
+	FromEntry   EntryResponse       `json:"from_entry"`
+	ToEntry     EntryResponse       `json:"to_entry"`
+	FromAccount AccountResponse     `json:"from_account"`
+	ToAccount   AccountResponse     `json:"to_account"`
+}
+
+// HoldResponse represents an authorization hold in the response
+type HoldResponse struct {
+	HoldNumber        string      `json:"hold_number"`
+	AccountID         int64       `json:"account_id"`
+	MerchantAccountID int64       `json:"merchant_account_id"`
+	TransactionID     int64       `json:"transaction_id"`
+	Amount            money.Money `json:"amount"`
+	CapturedAmount    money.Money `json:"captured_amount"`
+	CurrencyCode      string      `json:"currency_code"`
+	Status            string      `json:"status"`
+	Description       string      `json:"description,omitempty"`
+	ExpiresAt         time.Time   `json:"expires_at"`
+	ClosedAt          *time.Time  `json:"closed_at,omitempty"`
+	CreatedAt         time.Time   `json:"created_at"`
+}
+
+// HoldTransactionResponse represents a hold together with its transaction and the accounts it changed
+type HoldTransactionResponse struct {
+	Hold            HoldResponse        `json:"hold"`
+	Transaction     TransactionResponse `json:"transaction"`
+	Account         AccountResponse     `json:"account"`
+	MerchantAccount *AccountResponse    `json:"merchant_account,omitempty"`
+	FromEntry       *EntryResponse      `json:"from_entry,omitempty"`
+	ToEntry         *EntryResponse      `json:"to_entry,omitempty"`
+}
+
+// ScheduledTransferResponse represents a scheduled transfer in the response
+type ScheduledTransferResponse struct {
+	ScheduleNumber string      `json:"schedule_number"`
+	FromAccountID  int64       `json:"from_account_id"`
+	ToAccountID    int64       `json:"to_account_id"`
+	Amount         money.Money `json:"amount"`
+	CurrencyCode   string      `json:"currency_code"`
+	Description    string      `json:"description,omitempty"`
+	Frequency      string      `json:"frequency"`
+	CronExpression string      `json:"cron_expression,omitempty"`
+	Status         string      `json:"status"`
+	StartAt        time.Time   `json:"start_at"`
+	EndAt          *time.Time  `json:"end_at,omitempty"`
+	NextRunAt      time.Time   `json:"next_run_at"`
+	DueAt          time.Time   `json:"due_at"`
+	LastRunAt      *time.Time  `json:"last_run_at,omitempty"`
+	RetryCount     int32       `json:"retry_count"`
+	MaxRetries     int32       `json:"max_retries"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// ScheduledTransferExecutionResponse represents one attempt at executing a scheduled transfer
+type ScheduledTransferExecutionResponse struct {
+	ExecutionID   int64     `json:"execution_id"`
+	ScheduledFor  time.Time `json:"scheduled_for"`
+	Attempt       int32     `json:"attempt"`
+	Status        string    `json:"status"`
+	TransactionID int64     `json:"transaction_id,omitempty"`
+	ErrorMessage  string    `json:"error_message,omitempty"`
+	ExecutedAt    time.Time `json:"executed_at"`
+}
+
+// TransferBatchResponse represents a batch of transfers and its progress
+type TransferBatchResponse struct {
+	BatchNumber    string     `json:"batch_number"`
+	Mode           string     `json:"mode"`
+	Status         string     `json:"status"`
+	UploadID       string     `json:"upload_id,omitempty"`
+	TotalItems     int32      `json:"total_items"`
+	ProcessedItems int32      `json:"processed_items"`
+	SucceededItems int32      `json:"succeeded_items"`
+	FailedItems    int32      `json:"failed_items"`
+	Progress       float64    `json:"progress"`
+	ErrorMessage   string     `json:"error_message,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// TransferBatchItemResponse represents the outcome of one transfer of a batch
+type TransferBatchItemResponse struct {
+	ItemIndex     int32       `json:"item_index"`
+	FromAccountID int64       `json:"from_account_id"`
+	ToAccountID   int64       `json:"to_account_id"`
+	Amount        money.Money `json:"amount"`
+	CurrencyCode  string      `json:"currency_code"`
+	Description   string      `json:"description,omitempty"`
+	Status        string      `json:"status"`
+	TransactionID int64       `json:"transaction_id,omitempty"`
+	ErrorMessage  string      `json:"error_message,omitempty"`
+	ProcessedAt   *time.Time  `json:"processed_at,omitempty"`
+}
+
+// ReconciliationRunResponse represents a reconciliation run. Drift is the absolute balance drift per
+// currency and is only known right after the run.
+type ReconciliationRunResponse struct {
+	RunNumber           string                 `json:"run_number"`
+	AccountIDs          []int64                `json:"account_ids,omitempty"`
+	Status              string                 `json:"status"`
+	AccountsChecked     int32                  `json:"accounts_checked"`
+	TransactionsChecked int32                  `json:"transactions_checked"`
+	DiscrepancyCount    int32                  `json:"discrepancy_count"`
+	ErrorMessage        string                 `json:"error_message,omitempty"`
+	Drift               map[string]money.Money `json:"drift,omitempty"`
+	StartedAt           time.Time              `json:"started_at"`
+	CompletedAt         *time.Time             `json:"completed_at,omitempty"`
+}
+
+// LedgerDiscrepancyResponse represents one finding of a reconciliation run
+type LedgerDiscrepancyResponse struct {
+	DiscrepancyID   int64       `json:"discrepancy_id"`
+	DiscrepancyType string      `json:"discrepancy_type"`
+	AccountID       int64       `json:"account_id,omitempty"`
+	TransactionID   int64       `json:"transaction_id,omitempty"`
+	CurrencyCode    string      `json:"currency_code,omitempty"`
+	ExpectedAmount  money.Money `json:"expected_amount"`
+	ActualAmount    money.Money `json:"actual_amount"`
+	Difference      money.Money `json:"difference"`
+	Details         string      `json:"details,omitempty"`
+	DetectedAt      time.Time   `json:"detected_at"`
+}
+
+// InterestSettingsResponse represents how the accounts of an account type earn interest
+type InterestSettingsResponse struct {
+	AccountType        string    `json:"account_type"`
+	DayCountConvention string    `json:"day_count_convention"`
+	PostingPeriod      string    `json:"posting_period"`
+	IsActive           bool      `json:"is_active"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// InterestAccrualResponse represents one day of interest accrued on an account
+type InterestAccrualResponse struct {
+	AccrualDate        string      `json:"accrual_date"`
+	Balance            money.Money `json:"balance"`
+	InterestRate       money.Rate  `json:"interest_rate"`
+	DayCountConvention string      `json:"day_count_convention"`
+	Amount             money.Money `json:"amount"`
+	TransactionID      int64       `json:"transaction_id,omitempty"`
+	PostedAt           *time.Time  `json:"posted_at,omitempty"`
+}
+
+// InterestRunResponse represents the outcome of an interest run
+type InterestRunResponse struct {
+	Through       string `json:"through"`
+	Days          int    `json:"days"`
+	Accrued       int    `json:"accrued"`
+	Posted        int    `json:"posted"`
+	RemainingDays int    `json:"remaining_days"`
+}
+
+// FeeScheduleResponse represents the fees charged to the accounts of an account type
+type FeeScheduleResponse struct {
+	AccountType           string      `json:"account_type"`
+	TransferFeeFlat       money.Money `json:"transfer_fee_flat"`
+	TransferFeePercent    money.Rate  `json:"transfer_fee_percent"`
+	FxMarkupPercent       money.Rate  `json:"fx_markup_percent"`
+	MonthlyMaintenanceFee money.Money `json:"monthly_maintenance_fee"`
+	OverdraftFee          money.Money `json:"overdraft_fee"`
+	IsActive              bool        `json:"is_active"`
+	UpdatedAt             time.Time   `json:"updated_at"`
+}
+
+// FeeIncomeAccountResponse represents the account that collects the fees of a currency
+type FeeIncomeAccountResponse struct {
+	CurrencyCode string    `json:"currency_code"`
+	AccountID    int64     `json:"account_id"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// FeeChargeResponse represents one fee charged to an account
+type FeeChargeResponse struct {
+	ChargeID             int64       `json:"charge_id"`
+	AccountID            int64       `json:"account_id"`
+	FeeType              string      `json:"fee_type"`
+	Amount               money.Money `json:"amount"`
+	CurrencyCode         string      `json:"currency_code"`
+	TransactionID        int64       `json:"transaction_id"`
+	RelatedTransactionID int64       `json:"related_transaction_id,omitempty"`
+	PeriodStart          string      `json:"period_start,omitempty"`
+	CreatedAt            time.Time   `json:"created_at"`
+}
+
+// FeeResponse represents one fee a transfer would cost
+type FeeResponse struct {
+	FeeType string      `json:"fee_type"`
+	Amount  money.Money `json:"amount"`
+}
+
+// FeePreviewResponse represents what a transfer would debit, credit and cost before it is made
+type FeePreviewResponse struct {
+	DebitAmount    money.Money   `json:"debit_amount"`
+	DebitCurrency  string        `json:"debit_currency"`
+	CreditAmount   money.Money   `json:"credit_amount"`
+	CreditCurrency string        `json:"credit_currency"`
+	ExchangeRate   money.Rate    `json:"exchange_rate"`
+	Fees           []FeeResponse `json:"fees"`
+	TotalFees      money.Money   `json:"total_fees"`
+	TotalDebit     money.Money   `json:"total_debit"`
+}
+
+// FeeRunResponse represents the outcome of a periodic fee run
+type FeeRunResponse struct {
+	Day      string   `json:"day"`
+	Charged  int      `json:"charged"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// FixedDepositResponse represents a fixed deposit in the response
+type FixedDepositResponse struct {
+	DepositNumber            string      `json:"deposit_number"`
+	AccountID                int64       `json:"account_id"`
+	LinkedAccountID          int64       `json:"linked_account_id"`
+	Principal                money.Money `json:"principal"`
+	InterestRate             money.Rate  `json:"interest_rate"`
+	TermMonths               int32       `json:"term_months"`
+	StartDate                string      `json:"start_date"`
+	MaturityDate             string      `json:"maturity_date"`
+	MaturityInstruction      string      `json:"maturity_instruction"`
+	EarlyBreakPenaltyPercent money.Rate  `json:"early_break_penalty_percent"`
+	Status                   string      `json:"status"`
+	RolloverCount            int32       `json:"rollover_count"`
+	PayoutTransactionID      int64       `json:"payout_transaction_id,omitempty"`
+	ClosedAt                 *time.Time  `json:"closed_at,omitempty"`
+	CreatedAt                time.Time   `json:"created_at"`
+}
+
+// FixedDepositTransactionResponse represents a fixed deposit together with the money it moved
+type FixedDepositTransactionResponse struct {
+	Deposit       FixedDepositResponse `json:"deposit"`
+	Account       AccountResponse      `json:"account"`
+	LinkedAccount AccountResponse      `json:"linked_account"`
+	Transaction   TransactionResponse  `json:"transaction"`
+	Interest      *TransactionResponse `json:"interest,omitempty"`
+	Penalty       *FeeChargeResponse   `json:"penalty,omitempty"`
+}
+
+// FixedDepositTermResponse represents the rates a fixed deposit term is opened with
+type FixedDepositTermResponse struct {
+	TermMonths               int32      `json:"term_months"`
+	InterestRate             money.Rate `json:"interest_rate"`
+	EarlyBreakPenaltyPercent money.Rate `json:"early_break_penalty_percent"`
+	IsActive                 bool       `json:"is_active"`
+	UpdatedAt                time.Time  `json:"updated_at"`
+}
+
+// FixedDepositRunResponse represents the outcome of a manual maturity run
+type FixedDepositRunResponse struct {
+	Matured int `json:"matured"`
+}
+
+// StatementResponse represents a statement export in the response, completed statements are
+// downloaded from /statements/{statement_number}/download
+type StatementResponse struct {
+	StatementNumber string     `json:"statement_number"`
+	AccountID       int64      `json:"account_id"`
+	Format          string     `json:"format"`
+	PeriodStart     string     `json:"period_start"`
+	PeriodEnd       string     `json:"period_end"`
+	Status          string     `json:"status"`
+	EntryCount      *int32     `json:"entry_count,omitempty"`
+	FailureReason   string     `json:"failure_reason,omitempty"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// AuditRecordResponse represents one recorded change. UserID and IPAddress are left out for
+// changes made outside an API request, such as background jobs.
+type AuditRecordResponse struct {
+	AuditID   int32           `json:"audit_id"`
+	TableName string          `json:"table_name"`
+	RecordID  string          `json:"record_id"`
+	Action    string          `json:"action"`
+	OldValues json.RawMessage `json:"old_values,omitempty"`
+	NewValues json.RawMessage `json:"new_values,omitempty"`
+	UserID    int64           `json:"user_id,omitempty"`
+	IPAddress string          `json:"ip_address,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ExchangeRateResponse is the rate from one currency to another at a point in time
+type ExchangeRateResponse struct {
+	FromCurrency string     `json:"from_currency"`
+	ToCurrency   string     `json:"to_currency"`
+	Rate         money.Rate `json:"rate"`
+	At           time.Time  `json:"at"`
+}
+
+// ExchangeRateHistoryResponse is one recorded rate, the value of one unit of the currency in the
+// base currency from ValidFrom until the next recorded rate
+type ExchangeRateHistoryResponse struct {
+	CurrencyCode string     `json:"currency_code"`
+	Rate         money.Rate `json:"rate"`
+	Source       string     `json:"source"`
+	ValidFrom    time.Time  `json:"valid_from"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CurrencyResponse represents a currency of the registry. MinorUnits is the number of decimals its
+// amounts are written with, ExchangeRate the value of one unit in the base currency.
+type CurrencyResponse struct {
+	CurrencyCode  string      `json:"currency_code"`
+	CurrencyName  string      `json:"currency_name"`
+	Symbol        string      `json:"symbol,omitempty"`
+	MinorUnits    int16       `json:"minor_units"`
+	ExchangeRate  *money.Rate `json:"exchange_rate,omitempty"`
+	IsActive      bool        `json:"is_active"`
+	LastUpdatedAt *time.Time  `json:"last_updated_at,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+)
+
+type accountHandler struct {
+	service interface_service.AccountService
+}
+
+func NewAccountHandler(service interface_service.AccountService) handler_interface.AccountHandler {
+	return &accountHandler{service: service}
+}
+
+func (h *accountHandler) CreateAccount(ctx *gin.Context) {
+	var req dto.CreateAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	account, err := h.service.CreateAccount(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrInvalidAccountType), errors.Is(err, common.ErrAccountReferenceError):
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		case errors.Is(err, common.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewAccountResponse(account)})
+}
+
+func (h *accountHandler) GetAccount(ctx *gin.Context) {
+	accountID, err := utils.ParseID(ctx.Param("account_id"), "account_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	account, err := h.service.GetAccount(ctx, accountID)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, common.ErrorResponse(common.InstanceNotFoundError("Account")))
+		case errors.Is(err, common.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewAccountResponse(account)})
+}
+
+func (h *accountHandler) GetAccountByNumber(ctx *gin.Context) {
+	accountNumber := ctx.Param("account_number")
+	if accountNumber == "" {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(common.RequiredFieldError("account_number")))
+		return
+	}
+
+	account, err := h.service.GetAccountByNumber(ctx, accountNumber)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrInvalidAccountNumber):
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, common.ErrorResponse(common.InstanceNotFoundError("Account")))
+		case errors.Is(err, common.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewAccountResponse(account)})
+}
+
+func (h *accountHandler) ListAccountsByUser(ctx *gin.Context) {
+	userIDParam := ctx.Query("user_id")
+	if userIDParam == "" {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(common.RequiredFieldError("user_id")))
+		return
+	}
+	userID, err := utils.ParseID(userIDParam, "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	accounts, err := h.service.ListAccountsByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, common.ErrForbidden) {
+			ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+		return
+	}
+
+	rsp := make([]dto.AccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		rsp = append(rsp, NewAccountResponse(account))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp})
+}
+
+func (h *accountHandler) CloseAccount(ctx *gin.Context) {
+	accountID, err := utils.ParseID(ctx.Param("account_id"), "account_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	if err := h.service.CloseAccount(ctx, accountID); err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, common.ErrorResponse(common.InstanceNotFoundError("Account")))
+		case errors.Is(err, common.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+		case errors.Is(err, common.ErrAccountNotEmpty):
+			ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+		}
+		return
+	}
+
+	message := fmt.Sprintf("Account %d closed successfully", accountID)
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": message})
+}
+
+func (h *accountHandler) HardDeleteAccount(ctx *gin.Context) {
+	accountID, err := utils.ParseID(ctx.Param("account_id"), "account_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	if err := h.service.HardDeleteAccount(ctx, accountID); err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, common.ErrorResponse(common.InstanceNotFoundError("Account")))
+		case errors.Is(err, common.ErrAccountHasHistory):
+			ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+		case errors.Is(err, common.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+		}
+		return
+	}
+
+	message := fmt.Sprintf("Account %d deleted permanently", accountID)
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": message})
+}
+
+func (h *accountHandler) UpdateAccountTerms(ctx *gin.Context) {
+	accountID, err := utils.ParseID(ctx.Param("account_id"), "account_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	var req dto.UpdateAccountTermsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	account, err := h.service.UpdateAccountTerms(ctx, accountID, req)
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, common.ErrorResponse(common.InstanceNotFoundError("Account")))
+		case errors.Is(err, common.ErrNegativeAmount), errors.Is(err, common.ErrInvalidAmountPrecision),
+			errors.Is(err, common.ErrInvalidRatePrecision):
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		case errors.Is(err, common.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewAccountResponse(account)})
+}
+
+// NewAccountResponse maps a database account onto its API representation
+func NewAccountResponse(account db.Account) dto.AccountResponse {
+	balance := money.FromNumeric(account.Balance, account.CurrencyCode)
+	held := money.FromNumeric(account.HeldAmount, account.CurrencyCode)
+	return dto.AccountResponse{
+		AccountID:      int64(account.AccountID),
+		UserID:         int64(account.UserID),
+		AccountNumber:  account.AccountNumber,
+		AccountType:    account.AccountType,
+		CurrencyCode:   account.CurrencyCode,
+		Balance:        balance,
+		InterestRate:   money.RateFromNumeric(account.InterestRate),
+		OverdraftLimit: money.FromNumeric(account.OverdraftLimit, account.CurrencyCode),
+		IsActive:       account.IsActive,
+		CreatedAt:      account.CreatedAt,
+		UpdatedAt:      account.UpdatedAt,
+
+		HeldAmount:       held,
+		AvailableBalance: money.New(balance.Amount.Sub(held.Amount), account.CurrencyCode),
+	}
+}
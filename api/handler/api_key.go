@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+type apiKeyHandler struct {
+	service interface_service.APIKeyService
+}
+
+func NewAPIKeyHandler(service interface_service.APIKeyService) handler_interface.APIKeyHandler {
+	return &apiKeyHandler{service: service}
+}
+
+// IssueAPIKey issues a key, the response is the only time the key itself is shown
+func (h *apiKeyHandler) IssueAPIKey(ctx *gin.Context) {
+	var req dto.IssueAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	issued, err := h.service.Issue(ctx, req)
+	if err != nil {
+		writeAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewIssuedAPIKeyResponse(issued)})
+}
+
+func (h *apiKeyHandler) ListAPIKeys(ctx *gin.Context) {
+	var query dto.APIKeyQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	page, pageSize, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	keys, err := h.service.List(ctx, query, page, pageSize)
+	if err != nil {
+		writeAPIKeyError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		rsp = append(rsp, NewAPIKeyResponse(key))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+// RotateAPIKey issues the replacement of a key, the old key keeps working for the overlap period
+func (h *apiKeyHandler) RotateAPIKey(ctx *gin.Context) {
+	apiKeyID, err := utils.ParseID(ctx.Param("api_key_id"), "api_key_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	var req dto.RotateAPIKeyRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+			return
+		}
+	}
+
+	issued, err := h.service.Rotate(ctx, apiKeyID, req)
+	if err != nil {
+		writeAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewIssuedAPIKeyResponse(issued)})
+}
+
+func (h *apiKeyHandler) RevokeAPIKey(ctx *gin.Context) {
+	apiKeyID, err := utils.ParseID(ctx.Param("api_key_id"), "api_key_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	revoked, err := h.service.Revoke(ctx, apiKeyID)
+	if err != nil {
+		writeAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewAPIKeyResponse(revoked)})
+}
+
+// writeAPIKeyError maps API key service errors to HTTP responses
+func writeAPIKeyError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrAPIKeyNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrAPIKeyInactive):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidAPIKeyScopes),
+		errors.Is(err, common.ErrInvalidAPIKeyExpiry),
+		errors.Is(err, common.ErrAPIKeyOwnerNotFound),
+		errors.Is(err, common.ErrRequestSigningDisabled):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+// NewAPIKeyResponse maps an API key onto its API representation, which never includes the hash
+func NewAPIKeyResponse(key db.ApiKey) dto.APIKeyResponse {
+	rsp := dto.APIKeyResponse{
+		APIKeyID:        key.ApiKeyID,
+		KeyPrefix:       key.KeyPrefix,
+		Name:            key.Name,
+		OwnerUserID:     key.OwnerUserID,
+		Scopes:          key.Scopes,
+		SigningRequired: key.SigningRequired,
+		CreatedAt:       key.CreatedAt,
+	}
+	if key.ExpiresAt.Valid {
+		rsp.ExpiresAt = &key.ExpiresAt.Time
+	}
+	if key.LastUsedAt.Valid {
+		rsp.LastUsedAt = &key.LastUsedAt.Time
+	}
+	if key.RevokedAt.Valid {
+		rsp.RevokedAt = &key.RevokedAt.Time
+	}
+	if key.RotatedFrom.Valid {
+		rsp.RotatedFrom = &key.RotatedFrom.Int32
+	}
+	return rsp
+}
+
+// NewIssuedAPIKeyResponse maps a newly issued key onto its API representation, with the key itself
+// and its signing secret
+func NewIssuedAPIKeyResponse(issued schemas.IssuedAPIKey) dto.APIKeyResponse {
+	rsp := NewAPIKeyResponse(issued.APIKey)
+	rsp.Key = issued.Key
+	rsp.SigningSecret = issued.SigningSecret
+	return rsp
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+type auditHandler struct {
+	service interface_service.AuditService
+}
+
+func NewAuditHandler(service interface_service.AuditService) handler_interface.AuditHandler {
+	return &auditHandler{service: service}
+}
+
+// ListAuditTrail searches the audit trail by table, record, acting user and time range
+func (h *auditHandler) ListAuditTrail(ctx *gin.Context) {
+	var query dto.AuditTrailQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	page, pageSize, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	records, err := h.service.List(ctx, query, page, pageSize)
+	if err != nil {
+		writeAuditError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.AuditRecordResponse, 0, len(records))
+	for _, record := range records {
+		rsp = append(rsp, NewAuditRecordResponse(record))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+func (h *auditHandler) GetAuditRecord(ctx *gin.Context) {
+	auditID, err := utils.ParseID(ctx.Param("audit_id"), "audit_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	record, err := h.service.Get(ctx, auditID)
+	if err != nil {
+		writeAuditError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewAuditRecordResponse(record)})
+}
+
+// writeAuditError maps audit service errors to HTTP responses
+func writeAuditError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrAuditRecordNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidAuditTable),
+		errors.Is(err, common.ErrInvalidAuditPeriod):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+func NewAuditRecordResponse(record db.AuditTrail) dto.AuditRecordResponse {
+	return dto.AuditRecordResponse{
+		AuditID:   record.AuditID,
+		TableName: record.TableName,
+		RecordID:  record.RecordID,
+		Action:    record.Action,
+		OldValues: jsonbToRaw(record.OldValues),
+		NewValues: jsonbToRaw(record.NewValues),
+		UserID:    int64(record.UserID.Int32),
+		IPAddress: record.IpAddress.String,
+		CreatedAt: record.CreatedAt,
+	}
+}
+
+// jsonbToRaw passes a stored JSON document through unchanged, NULL becomes an omitted field
+func jsonbToRaw(value pgtype.JSONB) json.RawMessage {
+	if value.Status != pgtype.Present {
+		return nil
+	}
+	return json.RawMessage(value.Bytes)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+type authHandler struct {
+	service interface_service.AuthService
+}
+
+func NewAuthHandler(service interface_service.AuthService) handler_interface.AuthHandler {
+	return &authHandler{service: service}
+}
+
+func (h *authHandler) Login(ctx *gin.Context) {
+	var req dto.LoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.UserAgent = ctx.Request.UserAgent()
+
+	result, err := h.service.Login(ctx, req)
+	if err != nil {
+		writeAuthError(ctx, err)
+		return
+	}
+	if result.ChallengeToken != "" {
+		ctx.JSON(http.StatusOK, gin.H{"data": dto.TwoFactorChallengeResponse{
+			TwoFactorRequired:  true,
+			ChallengeToken:     result.ChallengeToken,
+			ChallengeExpiresAt: result.ChallengeExpiresAt,
+		}})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewTokenResponse(result.Tokens)})
+}
+
+// VerifyTwoFactor exchanges the challenge token of a login and a code for tokens
+func (h *authHandler) VerifyTwoFactor(ctx *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.UserAgent = ctx.Request.UserAgent()
+
+	tokens, err := h.service.VerifyTwoFactor(ctx, req)
+	if err != nil {
+		writeAuthError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewTokenResponse(tokens)})
+}
+
+func (h *authHandler) RefreshToken(ctx *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.UserAgent = ctx.Request.UserAgent()
+
+	tokens, err := h.service.RefreshToken(ctx, req)
+	if err != nil {
+		writeAuthError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewTokenResponse(tokens)})
+}
+
+func (h *authHandler) Logout(ctx *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	if err := h.service.Logout(ctx, req.RefreshToken); err != nil {
+		writeAuthError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "Logged out successfully"})
+}
+
+// writeAuthError maps auth service errors onto HTTP responses
+func writeAuthError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrInvalidCredentials),
+		errors.Is(err, common.ErrInvalidRefreshToken),
+		errors.Is(err, common.ErrInvalidChallengeToken),
+		errors.Is(err, common.ErrInvalidTwoFactorCode):
+		ctx.JSON(http.StatusUnauthorized, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrTwoFactorLocked):
+		ctx.JSON(http.StatusTooManyRequests, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+// NewTokenResponse maps issued tokens onto their API representation
+func NewTokenResponse(tokens schemas.AuthTokens) dto.TokenResponse {
+	return dto.TokenResponse{
+		TokenType:             "Bearer",
+		AccessToken:           tokens.AccessToken,
+		AccessTokenExpiresAt:  tokens.AccessTokenExpiresAt,
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt,
+		User:                  NewUserResponse(tokens.User),
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+)
+
+type currencyHandler struct {
+	service interface_service.CurrencyService
+}
+
+func NewCurrencyHandler(service interface_service.CurrencyService) handler_interface.CurrencyHandler {
+	return &currencyHandler{service: service}
+}
+
+func (h *currencyHandler) CreateCurrency(ctx *gin.Context) {
+	var req dto.CreateCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	currency, err := h.service.Create(ctx, req)
+	if err != nil {
+		writeCurrencyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewCurrencyResponse(currency)})
+}
+
+func (h *currencyHandler) GetCurrency(ctx *gin.Context) {
+	currency, err := h.service.Get(ctx, ctx.Param("currency_code"))
+	if err != nil {
+		writeCurrencyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewCurrencyResponse(currency)})
+}
+
+// ListCurrencies returns the active currencies, every currency with ?include_inactive=true
+func (h *currencyHandler) ListCurrencies(ctx *gin.Context) {
+	var query dto.CurrencyQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	currencies, err := h.service.List(ctx, query)
+	if err != nil {
+		writeCurrencyError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.CurrencyResponse, 0, len(currencies))
+	for _, currency := range currencies {
+		rsp = append(rsp, NewCurrencyResponse(currency))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp})
+}
+
+func (h *currencyHandler) UpdateCurrency(ctx *gin.Context) {
+	var req dto.UpdateCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	currency, err := h.service.Update(ctx, ctx.Param("currency_code"), req)
+	if err != nil {
+		writeCurrencyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewCurrencyResponse(currency)})
+}
+
+// DeleteCurrency deactivates a currency, it stays in the registry for the accounts that hold it
+func (h *currencyHandler) DeleteCurrency(ctx *gin.Context) {
+	currency, err := h.service.Deactivate(ctx, ctx.Param("currency_code"))
+	if err != nil {
+		writeCurrencyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewCurrencyResponse(currency)})
+}
+
+// writeCurrencyError maps currency service errors to HTTP responses
+func writeCurrencyError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrCurrencyNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrCurrencyExists), errors.Is(err, common.ErrCurrencyInUse):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidRatePrecision):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrCurrencyMinorUnitsFixed):
+		ctx.JSON(http.StatusUnprocessableEntity, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+func NewCurrencyResponse(currency db.AccountCurrency) dto.CurrencyResponse {
+	rsp := dto.CurrencyResponse{
+		CurrencyCode: currency.CurrencyCode,
+		CurrencyName: currency.CurrencyName,
+		Symbol:       currency.Symbol.String,
+		MinorUnits:   currency.MinorUnits,
+		IsActive:     currency.IsActive,
+		CreatedAt:    currency.CreatedAt,
+		UpdatedAt:    currency.UpdatedAt,
+	}
+	if currency.ExchangeRate.Status == pgtype.Present {
+		rate := money.RateFromNumeric(currency.ExchangeRate)
+		rsp.ExchangeRate = &rate
+	}
+	if currency.LastUpdatedAt.Valid {
+		rsp.LastUpdatedAt = &currency.LastUpdatedAt.Time
+	}
+	return rsp
+}
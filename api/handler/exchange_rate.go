@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+type exchangeRateHandler struct {
+	service interface_service.ExchangeRateService
+}
+
+func NewExchangeRateHandler(service interface_service.ExchangeRateService) handler_interface.ExchangeRateHandler {
+	return &exchangeRateHandler{service: service}
+}
+
+// GetExchangeRate returns the rate between two currencies, the one valid at ?at= when given
+func (h *exchangeRateHandler) GetExchangeRate(ctx *gin.Context) {
+	var query dto.ExchangeRateQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	rate, err := h.service.Get(ctx, query)
+	if err != nil {
+		writeExchangeRateError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewExchangeRateResponse(rate)})
+}
+
+// ListExchangeRateHistory returns the rates recorded for a currency, newest first
+func (h *exchangeRateHandler) ListExchangeRateHistory(ctx *gin.Context) {
+	var query dto.ExchangeRateHistoryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	page, pageSize, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	history, err := h.service.History(ctx, ctx.Param("currency_code"), query, page, pageSize)
+	if err != nil {
+		writeExchangeRateError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.ExchangeRateHistoryResponse, 0, len(history))
+	for _, rate := range history {
+		rsp = append(rsp, NewExchangeRateHistoryResponse(rate))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+// writeExchangeRateError maps exchange rate service errors to HTTP responses
+func writeExchangeRateError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrCurrencyNotFound),
+		errors.Is(err, common.ErrExchangeRateUnavailable):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidExchangeRateTime),
+		errors.Is(err, common.ErrInvalidRateHistoryRange):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+func NewExchangeRateResponse(rate schemas.ExchangeRate) dto.ExchangeRateResponse {
+	return dto.ExchangeRateResponse{
+		FromCurrency: rate.FromCurrency,
+		ToCurrency:   rate.ToCurrency,
+		Rate:         money.NewRate(rate.Rate),
+		At:           rate.At,
+	}
+}
+
+func NewExchangeRateHistoryResponse(rate db.ExchangeRateHistory) dto.ExchangeRateHistoryResponse {
+	return dto.ExchangeRateHistoryResponse{
+		CurrencyCode: rate.CurrencyCode,
+		Rate:         money.RateFromNumeric(rate.Rate),
+		Source:       rate.Source,
+		ValidFrom:    rate.ValidFrom,
+		CreatedAt:    rate.CreatedAt,
+	}
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+type feeHandler struct {
+	service interface_service.FeeService
+}
+
+func NewFeeHandler(service interface_service.FeeService) handler_interface.FeeHandler {
+	return &feeHandler{service: service}
+}
+
+func (h *feeHandler) UpsertFeeSchedule(ctx *gin.Context) {
+	var req dto.UpsertFeeScheduleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	schedule, err := h.service.UpsertSchedule(ctx, ctx.Param("account_type"), req)
+	if err != nil {
+		writeFeeError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewFeeScheduleResponse(schedule)})
+}
+
+func (h *feeHandler) GetFeeSchedule(ctx *gin.Context) {
+	schedule, err := h.service.GetSchedule(ctx, ctx.Param("account_type"))
+	if err != nil {
+		writeFeeError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewFeeScheduleResponse(schedule)})
+}
+
+func (h *feeHandler) ListFeeSchedules(ctx *gin.Context) {
+	schedules, err := h.service.ListSchedules(ctx)
+	if err != nil {
+		writeFeeError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.FeeScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		rsp = append(rsp, NewFeeScheduleResponse(schedule))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp})
+}
+
+func (h *feeHandler) SetFeeIncomeAccount(ctx *gin.Context) {
+	var req dto.SetFeeIncomeAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	account, err := h.service.SetIncomeAccount(ctx, ctx.Param("currency_code"), req)
+	if err != nil {
+		writeFeeError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewFeeIncomeAccountResponse(account)})
+}
+
+func (h *feeHandler) ListFeeIncomeAccounts(ctx *gin.Context) {
+	accounts, err := h.service.ListIncomeAccounts(ctx)
+	if err != nil {
+		writeFeeError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.FeeIncomeAccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		rsp = append(rsp, NewFeeIncomeAccountResponse(account))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp})
+}
+
+func (h *feeHandler) ListFeeCharges(ctx *gin.Context) {
+	accountID, err := strconv.ParseInt(ctx.Param("account_id"), 10, 32)
+	if err != nil || accountID < 1 {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(fmt.Errorf("invalid account_id %q", ctx.Param("account_id"))))
+		return
+	}
+	page, pageSize, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	charges, err := h.service.ListCharges(ctx, int32(accountID), page, pageSize)
+	if err != nil {
+		writeFeeError(ctx, err)
+		return
+	}
+
+	rsp := NewFeeChargeResponses(charges)
+	if rsp == nil {
+		rsp = []dto.FeeChargeResponse{}
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+// PreviewFees quotes a transfer: what the sender pays, what the receiver gets and the fees on top
+func (h *feeHandler) PreviewFees(ctx *gin.Context) {
+	var req dto.FeePreviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	preview, err := h.service.Preview(ctx, req)
+	if err != nil {
+		writeFeeError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewFeePreviewResponse(preview)})
+}
+
+// RunFees charges the maintenance and overdraft fees the fee job has not charged yet
+func (h *feeHandler) RunFees(ctx *gin.Context) {
+	var req dto.RunFeesRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+			return
+		}
+	}
+
+	result, day, err := h.service.Run(ctx, req)
+	if err != nil {
+		writeFeeError(ctx, err)
+		return
+	}
+
+	rsp := dto.FeeRunResponse{Day: day.Format(time.DateOnly), Charged: result.Charged}
+	for _, failure := range result.Failures {
+		rsp.Failures = append(rsp.Failures, failure.Error())
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp})
+}
+
+// writeFeeError maps fee service errors to HTTP responses
+func writeFeeError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrFeeScheduleNotFound),
+		errors.Is(err, common.ErrUnknownAccountType),
+		errors.Is(err, common.ErrFeeIncomeAccountNotFound),
+		errors.Is(err, common.ErrAccountNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidFeeDate),
+		errors.Is(err, common.ErrSameAccount),
+		errors.Is(err, common.ErrInvalidAmount),
+		errors.Is(err, common.ErrInvalidAmountPrecision),
+		errors.Is(err, common.ErrInvalidRatePrecision),
+		errors.Is(err, common.ErrNegativeAmount),
+		errors.Is(err, common.ErrCurrencyMismatch),
+		errors.Is(err, common.ErrFeeIncomeAccountCurrency):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrAccountInactive),
+		errors.Is(err, common.ErrExchangeRateUnavailable),
+		errors.Is(err, common.ErrFixedDepositLocked):
+		ctx.JSON(http.StatusUnprocessableEntity, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+func NewFeeScheduleResponse(schedule db.FeeSchedule) dto.FeeScheduleResponse {
+	return dto.FeeScheduleResponse{
+		AccountType:           schedule.AccountType,
+		TransferFeeFlat:       money.FromNumeric(schedule.TransferFeeFlat, ""),
+		TransferFeePercent:    money.RateFromNumeric(schedule.TransferFeePercent),
+		FxMarkupPercent:       money.RateFromNumeric(schedule.FxMarkupPercent),
+		MonthlyMaintenanceFee: money.FromNumeric(schedule.MonthlyMaintenanceFee, ""),
+		OverdraftFee:          money.FromNumeric(schedule.OverdraftFee, ""),
+		IsActive:              schedule.IsActive,
+		UpdatedAt:             schedule.UpdatedAt,
+	}
+}
+
+func NewFeeIncomeAccountResponse(account db.FeeIncomeAccount) dto.FeeIncomeAccountResponse {
+	return dto.FeeIncomeAccountResponse{
+		CurrencyCode: account.CurrencyCode,
+		AccountID:    int64(account.AccountID),
+		UpdatedAt:    account.UpdatedAt,
+	}
+}
+
+// NewFeeChargeResponses maps fee charges, no charges map to nil
+func NewFeeChargeResponses(charges []db.FeeCharge) []dto.FeeChargeResponse {
+	var rsp []dto.FeeChargeResponse
+	for _, charge := range charges {
+		item := dto.FeeChargeResponse{
+			ChargeID:             charge.ChargeID,
+			AccountID:            int64(charge.AccountID),
+			FeeType:              string(charge.FeeType),
+			Amount:               money.FromNumeric(charge.Amount, charge.CurrencyCode),
+			CurrencyCode:         charge.CurrencyCode,
+			TransactionID:        int64(charge.TransactionID),
+			RelatedTransactionID: int64(charge.RelatedTransactionID.Int32),
+			CreatedAt:            charge.CreatedAt,
+		}
+		if charge.PeriodStart.Valid {
+			item.PeriodStart = charge.PeriodStart.Time.Format(time.DateOnly)
+		}
+		rsp = append(rsp, item)
+	}
+	return rsp
+}
+
+func NewFeePreviewResponse(preview schemas.FeePreview) dto.FeePreviewResponse {
+	rsp := dto.FeePreviewResponse{
+		DebitAmount:    money.New(preview.DebitAmount, preview.DebitCurrency),
+		DebitCurrency:  preview.DebitCurrency,
+		CreditAmount:   money.New(preview.CreditAmount, preview.CreditCurrency),
+		CreditCurrency: preview.CreditCurrency,
+		ExchangeRate:   money.NewRate(preview.ExchangeRate),
+		Fees:           make([]dto.FeeResponse, 0, len(preview.Fees)),
+		TotalFees:      money.New(preview.TotalFees, preview.DebitCurrency),
+		TotalDebit:     money.New(preview.TotalDebit, preview.DebitCurrency),
+	}
+	for _, fee := range preview.Fees {
+		rsp.Fees = append(rsp.Fees, dto.FeeResponse{
+			FeeType: string(fee.Type),
+			Amount:  money.New(fee.Amount, preview.DebitCurrency),
+		})
+	}
+	return rsp
+}
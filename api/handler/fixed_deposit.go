@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+type fixedDepositHandler struct {
+	service interface_service.FixedDepositService
+}
+
+func NewFixedDepositHandler(service interface_service.FixedDepositService) handler_interface.FixedDepositHandler {
+	return &fixedDepositHandler{service: service}
+}
+
+func (h *fixedDepositHandler) OpenFixedDeposit(ctx *gin.Context) {
+	var req dto.OpenFixedDepositRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.ReferenceNumber = ctx.GetString(common.ContextKeyIdempotencyReference)
+	req.TOTPCode = ctx.GetHeader(TOTPCodeHeader)
+
+	result, err := h.service.Open(ctx, req)
+	if err != nil {
+		writeFixedDepositError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewFixedDepositTransactionResponse(result)})
+}
+
+func (h *fixedDepositHandler) GetFixedDeposit(ctx *gin.Context) {
+	deposit, err := h.service.Get(ctx, ctx.Param("deposit_number"))
+	if err != nil {
+		writeFixedDepositError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewFixedDepositResponse(deposit)})
+}
+
+func (h *fixedDepositHandler) ListFixedDeposits(ctx *gin.Context) {
+	accountID, err := utils.ParseID(ctx.Param("account_id"), "account_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	page, pageSize, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	deposits, err := h.service.ListByLinkedAccount(ctx, accountID, page, pageSize)
+	if err != nil {
+		writeFixedDepositError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.FixedDepositResponse, 0, len(deposits))
+	for _, deposit := range deposits {
+		rsp = append(rsp, NewFixedDepositResponse(deposit))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+// BreakFixedDeposit ends a deposit early, the linked account gets the balance minus the penalty
+func (h *fixedDepositHandler) BreakFixedDeposit(ctx *gin.Context) {
+	result, err := h.service.Break(ctx, ctx.Param("deposit_number"))
+	if err != nil {
+		writeFixedDepositError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewFixedDepositTransactionResponse(result)})
+}
+
+// RunFixedDepositMaturity pays out or rolls over the deposits the maturity job has not reached yet
+func (h *fixedDepositHandler) RunFixedDepositMaturity(ctx *gin.Context) {
+	matured, err := h.service.RunMaturity(ctx)
+	if err != nil {
+		writeFixedDepositError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": dto.FixedDepositRunResponse{Matured: matured}})
+}
+
+// UpsertFixedDepositTerm sets the interest rate and early break penalty new deposits of a term get
+func (h *fixedDepositHandler) UpsertFixedDepositTerm(ctx *gin.Context) {
+	termMonths, err := utils.ParseID(ctx.Param("term_months"), "term_months")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	var req dto.UpsertFixedDepositTermRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	term, err := h.service.UpsertTerm(ctx, termMonths, req)
+	if err != nil {
+		writeFixedDepositError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewFixedDepositTermResponse(term)})
+}
+
+func (h *fixedDepositHandler) ListFixedDepositTerms(ctx *gin.Context) {
+	terms, err := h.service.ListTerms(ctx)
+	if err != nil {
+		writeFixedDepositError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.FixedDepositTermResponse, 0, len(terms))
+	for _, term := range terms {
+		rsp = append(rsp, NewFixedDepositTermResponse(term))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp})
+}
+
+// writeFixedDepositError maps fixed deposit service errors to HTTP responses, falling back to the transfer mapping
+func writeFixedDepositError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrFixedDepositNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidDepositNumber), errors.Is(err, common.ErrInvalidLinkedAccount),
+		errors.Is(err, common.ErrInvalidTermMonths), errors.Is(err, common.ErrInvalidRatePrecision):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrFixedDepositNotActive):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrFixedDepositMatured), errors.Is(err, common.ErrFixedDepositTerm):
+		ctx.JSON(http.StatusUnprocessableEntity, common.ErrorResponse(err))
+	default:
+		writeTransferError(ctx, err)
+	}
+}
+
+func NewFixedDepositTransactionResponse(result schemas.FixedDepositTxResult) dto.FixedDepositTransactionResponse {
+	rsp := dto.FixedDepositTransactionResponse{
+		Deposit:       NewFixedDepositResponse(result.Deposit),
+		Account:       NewAccountResponse(result.Account),
+		LinkedAccount: NewAccountResponse(result.LinkedAccount),
+		Transaction:   NewTransactionResponse(result.Transaction),
+	}
+	if result.Interest != nil {
+		interest := NewTransactionResponse(*result.Interest)
+		rsp.Interest = &interest
+	}
+	if result.Penalty != nil {
+		penalty := NewFeeChargeResponses([]db.FeeCharge{*result.Penalty})[0]
+		rsp.Penalty = &penalty
+	}
+	return rsp
+}
+
+func NewFixedDepositResponse(deposit db.FixedDeposit) dto.FixedDepositResponse {
+	rsp := dto.FixedDepositResponse{
+		DepositNumber:            deposit.DepositNumber.String(),
+		AccountID:                int64(deposit.AccountID),
+		LinkedAccountID:          int64(deposit.LinkedAccountID),
+		Principal:                money.FromNumeric(deposit.Principal, ""),
+		InterestRate:             money.RateFromNumeric(deposit.InterestRate),
+		TermMonths:               deposit.TermMonths,
+		StartDate:                deposit.StartDate.Format(time.DateOnly),
+		MaturityDate:             deposit.MaturityDate.Format(time.DateOnly),
+		MaturityInstruction:      string(deposit.MaturityInstruction),
+		EarlyBreakPenaltyPercent: money.RateFromNumeric(deposit.EarlyBreakPenaltyPercent),
+		Status:                   string(deposit.Status),
+		RolloverCount:            deposit.RolloverCount,
+		PayoutTransactionID:      int64(deposit.PayoutTransactionID.Int32),
+		CreatedAt:                deposit.CreatedAt,
+	}
+	if deposit.ClosedAt.Valid {
+		rsp.ClosedAt = &deposit.ClosedAt.Time
+	}
+	return rsp
+}
+
+func NewFixedDepositTermResponse(term db.FixedDepositTerm) dto.FixedDepositTermResponse {
+	return dto.FixedDepositTermResponse{
+		TermMonths:               term.TermMonths,
+		InterestRate:             money.RateFromNumeric(term.InterestRate),
+		EarlyBreakPenaltyPercent: money.RateFromNumeric(term.EarlyBreakPenaltyPercent),
+		IsActive:                 term.IsActive,
+		UpdatedAt:                term.UpdatedAt,
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+type holdHandler struct {
+	service interface_service.HoldService
+}
+
+func NewHoldHandler(service interface_service.HoldService) handler_interface.HoldHandler {
+	return &holdHandler{service: service}
+}
+
+func (h *holdHandler) AuthorizeHold(ctx *gin.Context) {
+	var req dto.AuthorizeHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.ReferenceNumber = ctx.GetString(common.ContextKeyIdempotencyReference)
+	req.TOTPCode = ctx.GetHeader(TOTPCodeHeader)
+
+	result, err := h.service.AuthorizeHold(ctx, req)
+	if err != nil {
+		writeHoldError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewHoldTransactionResponse(result)})
+}
+
+func (h *holdHandler) GetHold(ctx *gin.Context) {
+	hold, err := h.service.GetHold(ctx, ctx.Param("hold_number"))
+	if err != nil {
+		writeHoldError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewHoldResponse(hold)})
+}
+
+func (h *holdHandler) ListOpenHolds(ctx *gin.Context) {
+	accountIDParam := ctx.Query("account_id")
+	if accountIDParam == "" {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(common.RequiredFieldError("account_id")))
+		return
+	}
+	accountID, err := utils.ParseID(accountIDParam, "account_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	holds, err := h.service.ListOpenHolds(ctx, accountID)
+	if err != nil {
+		writeHoldError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.HoldResponse, 0, len(holds))
+	for _, hold := range holds {
+		rsp = append(rsp, NewHoldResponse(hold))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp})
+}
+
+func (h *holdHandler) CaptureHold(ctx *gin.Context) {
+	var req dto.CaptureHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	result, err := h.service.CaptureHold(ctx, ctx.Param("hold_number"), req)
+	if err != nil {
+		writeHoldError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewHoldTransactionResponse(result)})
+}
+
+func (h *holdHandler) ReleaseHold(ctx *gin.Context) {
+	result, err := h.service.ReleaseHold(ctx, ctx.Param("hold_number"))
+	if err != nil {
+		writeHoldError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewHoldTransactionResponse(result)})
+}
+
+// writeHoldError maps hold service errors to HTTP responses, falling back to the transfer mapping
+func writeHoldError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrHoldNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidHoldNumber):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrHoldNotOpen):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrHoldExpired), errors.Is(err, common.ErrCaptureExceedsHold):
+		ctx.JSON(http.StatusUnprocessableEntity, common.ErrorResponse(err))
+	default:
+		writeTransferError(ctx, err)
+	}
+}
+
+func NewHoldTransactionResponse(result schemas.HoldTxResult) dto.HoldTransactionResponse {
+	rsp := dto.HoldTransactionResponse{
+		Hold:        NewHoldResponse(result.Hold),
+		Transaction: NewTransactionResponse(result.Transaction),
+		Account:     NewAccountResponse(result.Account),
+	}
+	// Money only moves on capture, the other results are empty until then
+	if result.Hold.Status == db.HoldStatusCAPTURED {
+		merchantAccount := NewAccountResponse(result.MerchantAccount)
+		fromEntry := NewEntryResponse(result.FromEntry)
+		toEntry := NewEntryResponse(result.ToEntry)
+		rsp.MerchantAccount = &merchantAccount
+		rsp.FromEntry = &fromEntry
+		rsp.ToEntry = &toEntry
+	}
+	return rsp
+}
+
+func NewHoldResponse(hold db.Hold) dto.HoldResponse {
+	rsp := dto.HoldResponse{
+		HoldNumber:        hold.HoldNumber.String(),
+		AccountID:         int64(hold.AccountID),
+		MerchantAccountID: int64(hold.MerchantAccountID),
+		TransactionID:     int64(hold.TransactionID),
+		Amount:            money.FromNumeric(hold.Amount, hold.CurrencyCode),
+		CapturedAmount:    money.FromNumeric(hold.CapturedAmount, hold.CurrencyCode),
+		CurrencyCode:      hold.CurrencyCode,
+		Status:            string(hold.Status),
+		Description:       hold.Description.String,
+		ExpiresAt:         hold.ExpiresAt,
+		CreatedAt:         hold.CreatedAt,
+	}
+	if hold.ClosedAt.Valid {
+		rsp.ClosedAt = &hold.ClosedAt.Time
+	}
+	return rsp
+}
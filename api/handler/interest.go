@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+)
+
+type interestHandler struct {
+	service interface_service.InterestService
+}
+
+func NewInterestHandler(service interface_service.InterestService) handler_interface.InterestHandler {
+	return &interestHandler{service: service}
+}
+
+func (h *interestHandler) UpsertInterestSettings(ctx *gin.Context) {
+	var req dto.UpsertInterestSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	settings, err := h.service.UpsertSettings(ctx, ctx.Param("account_type"), req)
+	if err != nil {
+		writeInterestError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewInterestSettingsResponse(settings)})
+}
+
+func (h *interestHandler) GetInterestSettings(ctx *gin.Context) {
+	settings, err := h.service.GetSettings(ctx, ctx.Param("account_type"))
+	if err != nil {
+		writeInterestError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewInterestSettingsResponse(settings)})
+}
+
+func (h *interestHandler) ListInterestSettings(ctx *gin.Context) {
+	settings, err := h.service.ListSettings(ctx)
+	if err != nil {
+		writeInterestError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.InterestSettingsResponse, 0, len(settings))
+	for _, setting := range settings {
+		rsp = append(rsp, NewInterestSettingsResponse(setting))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp})
+}
+
+func (h *interestHandler) ListInterestAccruals(ctx *gin.Context) {
+	accountID, err := strconv.ParseInt(ctx.Param("account_id"), 10, 32)
+	if err != nil || accountID < 1 {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(fmt.Errorf("invalid account_id %q", ctx.Param("account_id"))))
+		return
+	}
+	page, pageSize, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	accruals, err := h.service.ListAccruals(ctx, int32(accountID), page, pageSize)
+	if err != nil {
+		writeInterestError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.InterestAccrualResponse, 0, len(accruals))
+	for _, accrual := range accruals {
+		rsp = append(rsp, NewInterestAccrualResponse(accrual))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+// RunInterest accrues the days the interest job has not reached yet and posts ended periods
+func (h *interestHandler) RunInterest(ctx *gin.Context) {
+	var req dto.RunInterestRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+			return
+		}
+	}
+
+	result, through, err := h.service.Run(ctx, req)
+	if err != nil {
+		writeInterestError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": dto.InterestRunResponse{
+		Through:       through.Format(time.DateOnly),
+		Days:          result.Days,
+		Accrued:       result.Accrued,
+		Posted:        result.Posted,
+		RemainingDays: result.Remaining,
+	}})
+}
+
+// writeInterestError maps interest service errors to HTTP responses
+func writeInterestError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrInterestSettingsNotFound),
+		errors.Is(err, common.ErrUnknownAccountType):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidInterestDate):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+func NewInterestSettingsResponse(settings db.InterestSetting) dto.InterestSettingsResponse {
+	return dto.InterestSettingsResponse{
+		AccountType:        settings.AccountType,
+		DayCountConvention: string(settings.DayCountConvention),
+		PostingPeriod:      string(settings.PostingPeriod),
+		IsActive:           settings.IsActive,
+		UpdatedAt:          settings.UpdatedAt,
+	}
+}
+
+func NewInterestAccrualResponse(accrual db.InterestAccrual) dto.InterestAccrualResponse {
+	rsp := dto.InterestAccrualResponse{
+		AccrualDate:        accrual.AccrualDate.Format(time.DateOnly),
+		Balance:            money.FromNumeric(accrual.Balance, ""),
+		InterestRate:       money.RateFromNumeric(accrual.InterestRate),
+		DayCountConvention: string(accrual.DayCountConvention),
+		Amount:             money.FromNumeric(accrual.Amount, ""),
+		TransactionID:      int64(accrual.TransactionID.Int32),
+	}
+	if accrual.PostedAt.Valid {
+		rsp.PostedAt = &accrual.PostedAt.Time
+	}
+	return rsp
+}
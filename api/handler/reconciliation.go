@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+)
+
+type reconciliationHandler struct {
+	service interface_service.ReconciliationService
+}
+
+func NewReconciliationHandler(service interface_service.ReconciliationService) handler_interface.ReconciliationHandler {
+	return &reconciliationHandler{service: service}
+}
+
+// RunReconciliation runs a reconciliation synchronously and returns the run with its findings
+func (h *reconciliationHandler) RunReconciliation(ctx *gin.Context) {
+	var req dto.RunReconciliationRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+			return
+		}
+	}
+
+	result, err := h.service.Reconcile(ctx, req)
+	if err != nil {
+		writeReconciliationError(ctx, err)
+		return
+	}
+
+	rsp := NewReconciliationRunResponse(result.Run)
+	rsp.Drift = make(map[string]money.Money, len(result.Drift))
+	for currency, drift := range result.Drift {
+		rsp.Drift[currency] = money.New(drift, currency)
+	}
+	discrepancies := make([]dto.LedgerDiscrepancyResponse, 0, len(result.Discrepancies))
+	for _, discrepancy := range result.Discrepancies {
+		discrepancies = append(discrepancies, NewLedgerDiscrepancyResponse(discrepancy))
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": rsp, "discrepancies": discrepancies})
+}
+
+func (h *reconciliationHandler) ListReconciliationRuns(ctx *gin.Context) {
+	page, pageSize, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	runs, err := h.service.ListRuns(ctx, page, pageSize)
+	if err != nil {
+		writeReconciliationError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.ReconciliationRunResponse, 0, len(runs))
+	for _, run := range runs {
+		rsp = append(rsp, NewReconciliationRunResponse(run))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+func (h *reconciliationHandler) GetReconciliationRun(ctx *gin.Context) {
+	run, err := h.service.GetRun(ctx, ctx.Param("run_number"))
+	if err != nil {
+		writeReconciliationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewReconciliationRunResponse(run)})
+}
+
+func (h *reconciliationHandler) ListLedgerDiscrepancies(ctx *gin.Context) {
+	page, pageSize, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	discrepancies, err := h.service.ListDiscrepancies(ctx, ctx.Param("run_number"), page, pageSize)
+	if err != nil {
+		writeReconciliationError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.LedgerDiscrepancyResponse, 0, len(discrepancies))
+	for _, discrepancy := range discrepancies {
+		rsp = append(rsp, NewLedgerDiscrepancyResponse(discrepancy))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+// parsePage reads the page and page_size query parameters, writing a 400 response when they are invalid
+func parsePage(ctx *gin.Context) (int32, int32, bool) {
+	page, err := strconv.ParseInt(ctx.DefaultQuery("page", "1"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(fmt.Errorf("invalid page: %w", err)))
+		return 0, 0, false
+	}
+	pageSize, err := strconv.ParseInt(ctx.DefaultQuery("page_size", "10"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(fmt.Errorf("invalid page_size: %w", err)))
+		return 0, 0, false
+	}
+	return int32(page), int32(pageSize), true
+}
+
+// writeReconciliationError maps reconciliation service errors to HTTP responses
+func writeReconciliationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrReconciliationRunNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidRunNumber):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+func NewReconciliationRunResponse(run db.ReconciliationRun) dto.ReconciliationRunResponse {
+	rsp := dto.ReconciliationRunResponse{
+		RunNumber:           run.RunNumber.String(),
+		Status:              string(run.Status),
+		AccountsChecked:     run.AccountsChecked,
+		TransactionsChecked: run.TransactionsChecked,
+		DiscrepancyCount:    run.DiscrepancyCount,
+		ErrorMessage:        run.ErrorMessage.String,
+		StartedAt:           run.StartedAt,
+	}
+	for _, accountID := range run.AccountIds {
+		rsp.AccountIDs = append(rsp.AccountIDs, int64(accountID))
+	}
+	if run.CompletedAt.Valid {
+		rsp.CompletedAt = &run.CompletedAt.Time
+	}
+	return rsp
+}
+
+func NewLedgerDiscrepancyResponse(discrepancy db.LedgerDiscrepancy) dto.LedgerDiscrepancyResponse {
+	return dto.LedgerDiscrepancyResponse{
+		DiscrepancyID:   discrepancy.DiscrepancyID,
+		DiscrepancyType: string(discrepancy.DiscrepancyType),
+		AccountID:       int64(discrepancy.AccountID.Int32),
+		TransactionID:   int64(discrepancy.TransactionID.Int32),
+		CurrencyCode:    discrepancy.CurrencyCode.String,
+		ExpectedAmount:  money.FromNumeric(discrepancy.ExpectedAmount, discrepancy.CurrencyCode.String),
+		ActualAmount:    money.FromNumeric(discrepancy.ActualAmount, discrepancy.CurrencyCode.String),
+		Difference:      money.FromNumeric(discrepancy.Difference, discrepancy.CurrencyCode.String),
+		Details:         discrepancy.Details.String,
+		DetectedAt:      discrepancy.DetectedAt,
+	}
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+)
+
+type scheduledTransferHandler struct {
+	service interface_service.ScheduledTransferService
+}
+
+func NewScheduledTransferHandler(service interface_service.ScheduledTransferService) handler_interface.ScheduledTransferHandler {
+	return &scheduledTransferHandler{service: service}
+}
+
+func (h *scheduledTransferHandler) CreateScheduledTransfer(ctx *gin.Context) {
+	var req dto.CreateScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.TOTPCode = ctx.GetHeader(TOTPCodeHeader)
+
+	scheduled, err := h.service.CreateScheduledTransfer(ctx, req)
+	if err != nil {
+		writeScheduledTransferError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewScheduledTransferResponse(scheduled)})
+}
+
+func (h *scheduledTransferHandler) GetScheduledTransfer(ctx *gin.Context) {
+	scheduled, err := h.service.GetScheduledTransfer(ctx, ctx.Param("schedule_number"))
+	if err != nil {
+		writeScheduledTransferError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewScheduledTransferResponse(scheduled)})
+}
+
+func (h *scheduledTransferHandler) ListScheduledTransfers(ctx *gin.Context) {
+	accountIDParam := ctx.Query("account_id")
+	if accountIDParam == "" {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(common.RequiredFieldError("account_id")))
+		return
+	}
+	accountID, err := utils.ParseID(accountIDParam, "account_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	scheduledTransfers, err := h.service.ListScheduledTransfers(ctx, accountID)
+	if err != nil {
+		writeScheduledTransferError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.ScheduledTransferResponse, 0, len(scheduledTransfers))
+	for _, scheduled := range scheduledTransfers {
+		rsp = append(rsp, NewScheduledTransferResponse(scheduled))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp})
+}
+
+func (h *scheduledTransferHandler) ListExecutions(ctx *gin.Context) {
+	page, err := strconv.ParseInt(ctx.DefaultQuery("page", "1"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(fmt.Errorf("invalid page: %w", err)))
+		return
+	}
+	pageSize, err := strconv.ParseInt(ctx.DefaultQuery("page_size", "10"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(fmt.Errorf("invalid page_size: %w", err)))
+		return
+	}
+
+	executions, err := h.service.ListExecutions(ctx, ctx.Param("schedule_number"), int32(page), int32(pageSize))
+	if err != nil {
+		writeScheduledTransferError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.ScheduledTransferExecutionResponse, 0, len(executions))
+	for _, execution := range executions {
+		rsp = append(rsp, NewScheduledTransferExecutionResponse(execution))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+func (h *scheduledTransferHandler) PauseScheduledTransfer(ctx *gin.Context) {
+	h.updateStatus(ctx, db.ScheduledTransferStatusPAUSED)
+}
+
+func (h *scheduledTransferHandler) ResumeScheduledTransfer(ctx *gin.Context) {
+	h.updateStatus(ctx, db.ScheduledTransferStatusACTIVE)
+}
+
+func (h *scheduledTransferHandler) CancelScheduledTransfer(ctx *gin.Context) {
+	h.updateStatus(ctx, db.ScheduledTransferStatusCANCELLED)
+}
+
+func (h *scheduledTransferHandler) updateStatus(ctx *gin.Context, status db.ScheduledTransferStatus) {
+	scheduled, err := h.service.UpdateStatus(ctx, ctx.Param("schedule_number"), status)
+	if err != nil {
+		writeScheduledTransferError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewScheduledTransferResponse(scheduled)})
+}
+
+// writeScheduledTransferError maps scheduled transfer service errors to HTTP responses
+func writeScheduledTransferError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrScheduledTransferNotFound), errors.Is(err, common.ErrAccountNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidScheduleNumber),
+		errors.Is(err, common.ErrInvalidSchedule),
+		errors.Is(err, common.ErrSameAccount),
+		errors.Is(err, common.ErrInvalidAmount),
+		errors.Is(err, common.ErrInvalidAmountPrecision),
+		errors.Is(err, common.ErrCurrencyMismatch):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+	// Not 403, the caller may retry once it has a code
+	case errors.Is(err, common.ErrStepUpRequired), errors.Is(err, common.ErrTwoFactorSetupRequired):
+		ctx.JSON(http.StatusPreconditionRequired, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidTwoFactorCode):
+		ctx.JSON(http.StatusUnauthorized, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrTwoFactorLocked):
+		ctx.JSON(http.StatusTooManyRequests, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidScheduleTransition):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrAccountInactive):
+		ctx.JSON(http.StatusUnprocessableEntity, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+func NewScheduledTransferResponse(scheduled db.ScheduledTransfer) dto.ScheduledTransferResponse {
+	rsp := dto.ScheduledTransferResponse{
+		ScheduleNumber: scheduled.ScheduleNumber.String(),
+		FromAccountID:  int64(scheduled.FromAccountID),
+		ToAccountID:    int64(scheduled.ToAccountID),
+		Amount:         money.FromNumeric(scheduled.Amount, scheduled.CurrencyCode),
+		CurrencyCode:   scheduled.CurrencyCode,
+		Description:    scheduled.Description.String,
+		Frequency:      string(scheduled.Frequency),
+		CronExpression: scheduled.CronExpression.String,
+		Status:         string(scheduled.Status),
+		StartAt:        scheduled.StartAt,
+		NextRunAt:      scheduled.NextRunAt,
+		DueAt:          scheduled.DueAt,
+		RetryCount:     scheduled.RetryCount,
+		MaxRetries:     scheduled.MaxRetries,
+		CreatedAt:      scheduled.CreatedAt,
+	}
+	if scheduled.EndAt.Valid {
+		rsp.EndAt = &scheduled.EndAt.Time
+	}
+	if scheduled.LastRunAt.Valid {
+		rsp.LastRunAt = &scheduled.LastRunAt.Time
+	}
+	return rsp
+}
+
+func NewScheduledTransferExecutionResponse(execution db.ScheduledTransferExecution) dto.ScheduledTransferExecutionResponse {
+	return dto.ScheduledTransferExecutionResponse{
+		ExecutionID:   execution.ExecutionID,
+		ScheduledFor:  execution.ScheduledFor,
+		Attempt:       execution.Attempt,
+		Status:        string(execution.Status),
+		TransactionID: int64(execution.TransactionID.Int32),
+		ErrorMessage:  execution.ErrorMessage.String,
+		ExecutedAt:    execution.ExecutedAt,
+	}
+}
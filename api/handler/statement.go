@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/statement"
+)
+
+type statementHandler struct {
+	service interface_service.StatementService
+}
+
+func NewStatementHandler(service interface_service.StatementService) handler_interface.StatementHandler {
+	return &statementHandler{service: service}
+}
+
+// DownloadAccountStatement renders a statement for the requested period and sends it as a file
+func (h *statementHandler) DownloadAccountStatement(ctx *gin.Context) {
+	accountID, err := utils.ParseID(ctx.Param("account_id"), "account_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	var query dto.StatementPeriodQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	file, err := h.service.Export(ctx, accountID, query)
+	if err != nil {
+		writeStatementError(ctx, err)
+		return
+	}
+	writeStatementFile(ctx, file)
+}
+
+// CreateStatement queues a statement for the statement job, the response is the statement to poll
+func (h *statementHandler) CreateStatement(ctx *gin.Context) {
+	var req dto.CreateStatementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	stmt, err := h.service.Request(ctx, req)
+	if err != nil {
+		writeStatementError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"data": NewStatementResponse(stmt)})
+}
+
+func (h *statementHandler) GetStatement(ctx *gin.Context) {
+	stmt, err := h.service.Get(ctx, ctx.Param("statement_number"))
+	if err != nil {
+		writeStatementError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewStatementResponse(stmt)})
+}
+
+func (h *statementHandler) ListStatements(ctx *gin.Context) {
+	accountID, err := utils.ParseID(ctx.Param("account_id"), "account_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	page, pageSize, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	statements, err := h.service.ListByAccount(ctx, accountID, page, pageSize)
+	if err != nil {
+		writeStatementError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.StatementResponse, 0, len(statements))
+	for _, stmt := range statements {
+		rsp = append(rsp, NewStatementResponse(stmt))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+func (h *statementHandler) DownloadStatement(ctx *gin.Context) {
+	file, err := h.service.Download(ctx, ctx.Param("statement_number"))
+	if err != nil {
+		writeStatementError(ctx, err)
+		return
+	}
+	writeStatementFile(ctx, file)
+}
+
+func writeStatementFile(ctx *gin.Context, file statement.File) {
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	ctx.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// writeStatementError maps statement service errors to HTTP responses
+func writeStatementError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrStatementNotFound), errors.Is(err, common.ErrAccountNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidStatementNumber),
+		errors.Is(err, common.ErrInvalidStatementPeriod),
+		errors.Is(err, common.ErrUnsupportedStatementFormat):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrStatementNotReady):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrStatementTooLarge):
+		ctx.JSON(http.StatusUnprocessableEntity, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+func NewStatementResponse(stmt db.Statement) dto.StatementResponse {
+	rsp := dto.StatementResponse{
+		StatementNumber: stmt.StatementNumber.String(),
+		AccountID:       int64(stmt.AccountID),
+		Format:          string(stmt.Format),
+		PeriodStart:     stmt.PeriodStart.Format(time.DateOnly),
+		PeriodEnd:       stmt.PeriodEnd.Format(time.DateOnly),
+		Status:          string(stmt.Status),
+		FailureReason:   stmt.FailureReason.String,
+		CreatedAt:       stmt.CreatedAt,
+	}
+	if stmt.EntryCount.Valid {
+		rsp.EntryCount = &stmt.EntryCount.Int32
+	}
+	if stmt.CompletedAt.Valid {
+		rsp.CompletedAt = &stmt.CompletedAt.Time
+	}
+	return rsp
+}
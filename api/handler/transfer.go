@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+type transferHandler struct {
+	service interface_service.TransferService
+}
+
+func NewTransferHandler(service interface_service.TransferService) handler_interface.TransferHandler {
+	return &transferHandler{service: service}
+}
+
+func (h *transferHandler) CreateTransfer(ctx *gin.Context) {
+	var req dto.CreateTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.ReferenceNumber = ctx.GetString(common.ContextKeyIdempotencyReference)
+	req.TOTPCode = ctx.GetHeader(TOTPCodeHeader)
+
+	result, err := h.service.CreateTransfer(ctx, req)
+	if err != nil {
+		writeTransferError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewTransferResponse(result)})
+}
+
+func (h *transferHandler) ReverseTransaction(ctx *gin.Context) {
+	var req dto.ReverseTransactionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.ReferenceNumber = ctx.GetString(common.ContextKeyIdempotencyReference)
+
+	result, err := h.service.ReverseTransaction(ctx, ctx.Param("transaction_number"), req)
+	if err != nil {
+		writeTransferError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewReversalResponse(result)})
+}
+
+func (h *transferHandler) RefundTransaction(ctx *gin.Context) {
+	var req dto.RefundTransactionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.ReferenceNumber = ctx.GetString(common.ContextKeyIdempotencyReference)
+
+	result, err := h.service.RefundTransaction(ctx, ctx.Param("transaction_number"), req)
+	if err != nil {
+		writeTransferError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewReversalResponse(result)})
+}
+
+// writeTransferError maps transfer service errors to HTTP responses
+func writeTransferError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrAccountNotFound), errors.Is(err, common.ErrTransactionNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrSameAccount),
+		errors.Is(err, common.ErrInvalidAmount),
+		errors.Is(err, common.ErrInvalidAmountPrecision),
+		errors.Is(err, common.ErrCurrencyMismatch),
+		errors.Is(err, common.ErrInvalidTransactionNumber):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+	// Not 403, the caller may retry once it has a code
+	case errors.Is(err, common.ErrStepUpRequired), errors.Is(err, common.ErrTwoFactorSetupRequired):
+		ctx.JSON(http.StatusPreconditionRequired, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidTwoFactorCode):
+		ctx.JSON(http.StatusUnauthorized, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrTwoFactorLocked):
+		ctx.JSON(http.StatusTooManyRequests, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrDuplicateTransfer), errors.Is(err, common.ErrAlreadyReversed):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrAccountInactive),
+		errors.Is(err, common.ErrInsufficientFunds),
+		errors.Is(err, common.ErrExchangeRateUnavailable),
+		errors.Is(err, common.ErrFeeIncomeAccountMissing),
+		errors.Is(err, common.ErrFixedDepositLocked),
+		errors.Is(err, common.ErrTransactionNotReversible),
+		errors.Is(err, common.ErrRefundExceedsOriginal):
+		ctx.JSON(http.StatusUnprocessableEntity, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(common.ErrTransactionFailed))
+	}
+}
+
+func NewTransferResponse(result schemas.TransferTxResult) dto.TransferResponse {
+	return dto.TransferResponse{
+		Transaction: NewTransactionResponse(result.Transaction),
+		FromEntry:   NewEntryResponse(result.FromEntry),
+		ToEntry:     NewEntryResponse(result.ToEntry),
+		FromAccount: NewAccountResponse(result.FromAccount),
+		ToAccount:   NewAccountResponse(result.ToAccount),
+		Fees:        NewFeeChargeResponses(result.Fees),
+	}
+}
+
+func NewReversalResponse(result schemas.ReversalTxResult) dto.ReversalResponse {
+	return dto.ReversalResponse{
+		Original:    NewTransactionResponse(result.Original),
+		Transaction: NewTransactionResponse(result.Transaction),
+		FromEntry:   NewEntryResponse(result.FromEntry),
+		ToEntry:     NewEntryResponse(result.ToEntry),
+		FromAccount: NewAccountResponse(result.FromAccount),
+		ToAccount:   NewAccountResponse(result.ToAccount),
+	}
+}
+
+func NewTransactionResponse(transaction db.Transaction) dto.TransactionResponse {
+	rsp := dto.TransactionResponse{
+		TransactionID:         int64(transaction.TransactionID),
+		TransactionNumber:     transaction.TransactionNumber.String(),
+		ReferenceNumber:       transaction.ReferenceNumber.String,
+		FromAccountID:         int64(transaction.FromAccountID.Int32),
+		ToAccountID:           int64(transaction.ToAccountID.Int32),
+		TypeCode:              transaction.TypeCode,
+		StatusCode:            transaction.StatusCode,
+		Amount:                money.FromNumeric(transaction.Amount, transaction.CurrencyCode),
+		CurrencyCode:          transaction.CurrencyCode,
+		ExchangeRate:          money.RateFromNumeric(transaction.ExchangeRate),
+		ConvertedCurrency:     transaction.ConvertedCurrencyCode.String,
+		Description:           transaction.Description.String,
+		OriginalTransactionID: int64(transaction.OriginalTransactionID.Int32),
+		TransactionDate:       transaction.TransactionDate,
+	}
+	if transaction.ConvertedAmount.Status == pgtype.Present {
+		converted := money.FromNumeric(transaction.ConvertedAmount, transaction.ConvertedCurrencyCode.String)
+		rsp.ConvertedAmount = &converted
+	}
+	return rsp
+}
+
+func NewEntryResponse(entry db.Entry) dto.EntryResponse {
+	return dto.EntryResponse{
+		EntryID:      entry.ID,
+		AccountID:    int64(entry.AccountID.Int32),
+		Amount:       money.FromNumeric(entry.Amount, entry.CurrencyCode.String),
+		CurrencyCode: entry.CurrencyCode.String,
+		ExchangeRate: money.RateFromNumeric(entry.ExchangeRate),
+		CreatedAt:    entry.CreatedAt,
+	}
+}
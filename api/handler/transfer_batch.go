@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+)
+
+// batchReportHeader lists the columns of the downloadable batch result report
+var batchReportHeader = []string{
+	"item_index", "from_account_id", "to_account_id", "amount", "currency_code",
+	"description", "status", "transaction_id", "error_message",
+}
+
+type transferBatchHandler struct {
+	service interface_service.TransferBatchService
+}
+
+func NewTransferBatchHandler(service interface_service.TransferBatchService) handler_interface.TransferBatchHandler {
+	return &transferBatchHandler{service: service}
+}
+
+func (h *transferBatchHandler) CreateTransferBatch(ctx *gin.Context) {
+	var req dto.CreateTransferBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.ReferenceNumber = ctx.GetString(common.ContextKeyIdempotencyReference)
+	req.TOTPCode = ctx.GetHeader(TOTPCodeHeader)
+
+	batch, err := h.service.CreateBatch(ctx, req)
+	if err != nil {
+		writeTransferBatchError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"data": NewTransferBatchResponse(batch)})
+}
+
+func (h *transferBatchHandler) UploadTransferBatch(ctx *gin.Context) {
+	var req dto.UploadTransferBatchRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+	req.ReferenceNumber = ctx.GetString(common.ContextKeyIdempotencyReference)
+	if userID, ok := utils.AuthUserID(ctx); ok {
+		req.UserID = int32(userID)
+	}
+	req.TOTPCode = ctx.GetHeader(TOTPCodeHeader)
+
+	batch, err := h.service.UploadBatch(ctx, req)
+	if err != nil {
+		writeTransferBatchError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"data": NewTransferBatchResponse(batch)})
+}
+
+func (h *transferBatchHandler) GetTransferBatch(ctx *gin.Context) {
+	batch, err := h.service.GetBatch(ctx, ctx.Param("batch_number"))
+	if err != nil {
+		writeTransferBatchError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewTransferBatchResponse(batch)})
+}
+
+func (h *transferBatchHandler) ListTransferBatchItems(ctx *gin.Context) {
+	batch, items, err := h.service.ListItems(ctx, ctx.Param("batch_number"))
+	if err != nil {
+		writeTransferBatchError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.TransferBatchItemResponse, 0, len(items))
+	for _, item := range items {
+		rsp = append(rsp, NewTransferBatchItemResponse(item))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"batch": NewTransferBatchResponse(batch), "data": rsp})
+}
+
+// DownloadTransferBatchReport writes the outcome of every item as a CSV file. Items of a batch that
+// is still running are reported as PENDING.
+func (h *transferBatchHandler) DownloadTransferBatchReport(ctx *gin.Context) {
+	batch, items, err := h.service.ListItems(ctx, ctx.Param("batch_number"))
+	if err != nil {
+		writeTransferBatchError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"batch-%s.csv\"", batch.BatchNumber))
+	ctx.Status(http.StatusOK)
+
+	writer := csv.NewWriter(ctx.Writer)
+	writer.Write(batchReportHeader)
+	for _, item := range items {
+		transactionID := ""
+		if item.TransactionID.Valid {
+			transactionID = strconv.FormatInt(int64(item.TransactionID.Int32), 10)
+		}
+		writer.Write([]string{
+			strconv.FormatInt(int64(item.ItemIndex), 10),
+			strconv.FormatInt(int64(item.FromAccountID), 10),
+			strconv.FormatInt(int64(item.ToAccountID), 10),
+			money.FromNumeric(item.Amount, item.CurrencyCode).Text(),
+			item.CurrencyCode,
+			item.Description.String,
+			string(item.Status),
+			transactionID,
+			item.ErrorMessage.String,
+		})
+	}
+	writer.Flush()
+}
+
+// writeTransferBatchError maps batch transfer service errors to HTTP responses
+func writeTransferBatchError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrTransferBatchNotFound):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidBatchNumber),
+		errors.Is(err, common.ErrInvalidBatchFile),
+		errors.Is(err, common.ErrSameAccount),
+		errors.Is(err, common.ErrInvalidAmount):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+	// Not 403, the caller may retry once it has a code
+	case errors.Is(err, common.ErrStepUpRequired), errors.Is(err, common.ErrTwoFactorSetupRequired):
+		ctx.JSON(http.StatusPreconditionRequired, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidTwoFactorCode):
+		ctx.JSON(http.StatusUnauthorized, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrTwoFactorLocked):
+		ctx.JSON(http.StatusTooManyRequests, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrDuplicateTransferBatch):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrBatchUploadNotEnabled):
+		ctx.JSON(http.StatusServiceUnavailable, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+func NewTransferBatchResponse(batch db.TransferBatch) dto.TransferBatchResponse {
+	rsp := dto.TransferBatchResponse{
+		BatchNumber:    batch.BatchNumber.String(),
+		Mode:           string(batch.Mode),
+		Status:         string(batch.Status),
+		UploadID:       batch.UploadID.String,
+		TotalItems:     batch.TotalItems,
+		ProcessedItems: batch.ProcessedItems,
+		SucceededItems: batch.SucceededItems,
+		FailedItems:    batch.FailedItems,
+		ErrorMessage:   batch.ErrorMessage.String,
+		CreatedAt:      batch.CreatedAt,
+	}
+	if batch.TotalItems > 0 {
+		rsp.Progress = float64(batch.ProcessedItems) / float64(batch.TotalItems)
+	}
+	if batch.StartedAt.Valid {
+		rsp.StartedAt = &batch.StartedAt.Time
+	}
+	if batch.CompletedAt.Valid {
+		rsp.CompletedAt = &batch.CompletedAt.Time
+	}
+	return rsp
+}
+
+func NewTransferBatchItemResponse(item db.TransferBatchItem) dto.TransferBatchItemResponse {
+	rsp := dto.TransferBatchItemResponse{
+		ItemIndex:     item.ItemIndex,
+		FromAccountID: int64(item.FromAccountID),
+		ToAccountID:   int64(item.ToAccountID),
+		Amount:        money.FromNumeric(item.Amount, item.CurrencyCode),
+		CurrencyCode:  item.CurrencyCode,
+		Description:   item.Description.String,
+		Status:        string(item.Status),
+		TransactionID: int64(item.TransactionID.Int32),
+		ErrorMessage:  item.ErrorMessage.String,
+	}
+	if item.ProcessedAt.Valid {
+		rsp.ProcessedAt = &item.ProcessedAt.Time
+	}
+	return rsp
+}
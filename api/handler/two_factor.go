@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+)
+
+// TOTPCodeHeader carries the two-factor code of requests that need a fresh one, such as large transfers
+const TOTPCodeHeader = "X-TOTP-Code"
+
+// twoFactorHandler acts on the second factor of the caller, its routes all require an access token
+type twoFactorHandler struct {
+	service interface_service.TwoFactorService
+}
+
+func NewTwoFactorHandler(service interface_service.TwoFactorService) handler_interface.TwoFactorHandler {
+	return &twoFactorHandler{service: service}
+}
+
+// EnrollTwoFactor returns a new secret for the caller's authenticator app, it is enabled by ConfirmTwoFactor
+func (h *twoFactorHandler) EnrollTwoFactor(ctx *gin.Context) {
+	userID, _ := utils.AuthUserID(ctx)
+
+	enrollment, err := h.service.Enroll(ctx, userID)
+	if err != nil {
+		writeTwoFactorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": dto.TwoFactorEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	}})
+}
+
+// ConfirmTwoFactor enables two-factor authentication, the response is the only time the recovery
+// codes are shown
+func (h *twoFactorHandler) ConfirmTwoFactor(ctx *gin.Context) {
+	userID, _ := utils.AuthUserID(ctx)
+	var req dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	codes, err := h.service.Confirm(ctx, userID, req.Code)
+	if err != nil {
+		writeTwoFactorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": dto.RecoveryCodesResponse{RecoveryCodes: codes}})
+}
+
+func (h *twoFactorHandler) DisableTwoFactor(ctx *gin.Context) {
+	userID, _ := utils.AuthUserID(ctx)
+	var req dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	if err := h.service.Disable(ctx, userID, req.Code); err != nil {
+		writeTwoFactorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "Two-factor authentication disabled"})
+}
+
+// writeTwoFactorError maps two-factor service errors to HTTP responses
+func writeTwoFactorError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, common.ErrInvalidTwoFactorCode):
+		ctx.JSON(http.StatusUnauthorized, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, common.ErrTwoFactorNotEnrolled),
+		errors.Is(err, common.ErrTwoFactorNotEnabled):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrTwoFactorLocked):
+		ctx.JSON(http.StatusTooManyRequests, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrUserInactive):
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+type userHandler struct {
+	service interface_service.UserService
+}
+
+func NewUserHandler(service interface_service.UserService) handler_interface.UserHandler {
+	return &userHandler{service: service}
+}
+
+func (h *userHandler) CreateUser(ctx *gin.Context) {
+	var req dto.CreateUserRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	user, err := h.service.CreateUser(ctx, req)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": NewUserResponse(user)})
+}
+
+func (h *userHandler) GetUser(ctx *gin.Context) {
+	userID, err := utils.ParseID(ctx.Param("user_id"), "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	user, err := h.service.GetUser(ctx, userID)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewUserResponse(user)})
+}
+
+func (h *userHandler) ListUsers(ctx *gin.Context) {
+	page, err := strconv.ParseInt(ctx.DefaultQuery("page", "1"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(fmt.Errorf("invalid page: %w", err)))
+		return
+	}
+	pageSize, err := strconv.ParseInt(ctx.DefaultQuery("page_size", "10"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(fmt.Errorf("invalid page_size: %w", err)))
+		return
+	}
+
+	users, err := h.service.ListUsers(ctx, int32(page), int32(pageSize))
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	rsp := make([]dto.UserResponse, 0, len(users))
+	for _, user := range users {
+		rsp = append(rsp, NewUserResponse(user))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": rsp, "page": page, "page_size": pageSize})
+}
+
+func (h *userHandler) UpdateUser(ctx *gin.Context) {
+	userID, err := utils.ParseID(ctx.Param("user_id"), "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	var req dto.UpdateUserRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	user, err := h.service.UpdateUser(ctx, userID, req)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewUserResponse(user)})
+}
+
+func (h *userHandler) DeleteUser(ctx *gin.Context) {
+	userID, err := utils.ParseID(ctx.Param("user_id"), "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteUser(ctx, userID); err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	message := fmt.Sprintf("User %d deactivated successfully", userID)
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": message})
+}
+
+func (h *userHandler) HardDeleteUser(ctx *gin.Context) {
+	userID, err := utils.ParseID(ctx.Param("user_id"), "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	if err := h.service.HardDeleteUser(ctx, userID); err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	message := fmt.Sprintf("User %d deleted permanently", userID)
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": message})
+}
+
+func (h *userHandler) UpdateUserRole(ctx *gin.Context) {
+	userID, err := utils.ParseID(ctx.Param("user_id"), "user_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	var req dto.UpdateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	user, err := h.service.UpdateUserRole(ctx, userID, req)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": NewUserResponse(user)})
+}
+
+// writeUserError maps user service errors onto HTTP responses
+func writeUserError(ctx *gin.Context, err error) {
+	switch {
+	case err == sql.ErrNoRows:
+		ctx.JSON(http.StatusNotFound, common.ErrorResponse(common.InstanceNotFoundError("User")))
+	case errors.Is(err, common.ErrUserExists), errors.Is(err, common.ErrUserHasAccounts),
+		errors.Is(err, common.ErrUserHasHistory):
+		ctx.JSON(http.StatusConflict, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrInvalidUserData), errors.Is(err, common.ErrInvalidImage),
+		errors.Is(err, common.ErrInvalidRole):
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrForbidden), errors.Is(err, common.ErrOwnRoleChange):
+		ctx.JSON(http.StatusForbidden, common.ErrorResponse(err))
+	case errors.Is(err, common.ErrStorageNotEnabled):
+		ctx.JSON(http.StatusServiceUnavailable, common.ErrorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, common.ErrorResponse(err))
+	}
+}
+
+// NewUserResponse maps a database user onto its API representation, omitting the password hash
+func NewUserResponse(user db.User) dto.UserResponse {
+	return dto.UserResponse{
+		UserID:          int64(user.UserID),
+		Username:        user.Username,
+		Email:           user.Email.String,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		PhoneNumber:     user.PhoneNumber,
+		ProfileImageUrl: user.ProfileImageUrl,
+		IsActive:        user.IsActive,
+		Role:            string(user.Role),
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	handler_interface "github.com/riad/banksystemendtoend/api/interface/handler"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+)
+
+type userAccountHandler struct {
+	service interface_service.UserAccountService
+}
+
+func NewUserAccountHandler(service interface_service.UserAccountService) handler_interface.UserAccountHandler {
+	return &userAccountHandler{service: service}
+}
+
+func (h *userAccountHandler) CreateUserAccount(ctx *gin.Context) {
+	var req dto.CreateUserAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		return
+	}
+
+	user, account, err := h.service.CreateUserWithAccount(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrInvalidAccountType),
+			errors.Is(err, common.ErrAccountReferenceError),
+			errors.Is(err, common.ErrInvalidUserData),
+			errors.Is(err, common.ErrNegativeAmount),
+			errors.Is(err, common.ErrInvalidAmountPrecision):
+			ctx.JSON(http.StatusBadRequest, common.ErrorResponse(err))
+		default:
+			common.HandleCreateUserAccountError(ctx, err)
+		}
+		return
+	}
+
+	rsp := dto.CreateUserAccountResponse{
+		User:    NewUserResponse(user),
+		Account: NewAccountResponse(account),
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": rsp})
+}
@@ -39,4 +39,185 @@ type UserHandler interface {
 
 	// HardDeleteUser handles permanently removing a user
 	HardDeleteUser(ctx *gin.Context)
+
+	// UpdateUserRole handles changing the role of a user
+	UpdateUserRole(ctx *gin.Context)
+}
+
+// AuthHandler defines the interface for login and token HTTP handlers
+type AuthHandler interface {
+	// Login handles logging a user in
+	Login(ctx *gin.Context)
+
+	// VerifyTwoFactor handles finishing a login with a second factor
+	VerifyTwoFactor(ctx *gin.Context)
+
+	// RefreshToken handles exchanging a refresh token for new tokens
+	RefreshToken(ctx *gin.Context)
+
+	// Logout handles revoking a refresh token
+	Logout(ctx *gin.Context)
+}
+
+// TwoFactorHandler defines the interface for two-factor authentication HTTP handlers
+type TwoFactorHandler interface {
+	// EnrollTwoFactor handles generating a secret for an authenticator app
+	EnrollTwoFactor(ctx *gin.Context)
+
+	// ConfirmTwoFactor handles enabling two-factor authentication with a first code
+	ConfirmTwoFactor(ctx *gin.Context)
+
+	// DisableTwoFactor handles turning two-factor authentication off
+	DisableTwoFactor(ctx *gin.Context)
+}
+
+// APIKeyHandler defines the interface for API key management HTTP handlers
+type APIKeyHandler interface {
+	// IssueAPIKey handles issuing an API key
+	IssueAPIKey(ctx *gin.Context)
+
+	// ListAPIKeys handles listing API keys
+	ListAPIKeys(ctx *gin.Context)
+
+	// RotateAPIKey handles replacing an API key with a new one
+	RotateAPIKey(ctx *gin.Context)
+
+	// RevokeAPIKey handles revoking an API key
+	RevokeAPIKey(ctx *gin.Context)
+}
+
+// AccountHandler defines the interface for account-related HTTP handlers
+type AccountHandler interface {
+	// CreateAccount handles opening a new account
+	CreateAccount(ctx *gin.Context)
+
+	// GetAccount handles retrieving an account by ID
+	GetAccount(ctx *gin.Context)
+
+	// GetAccountByNumber handles retrieving an account by its account number
+	GetAccountByNumber(ctx *gin.Context)
+
+	// ListAccountsByUser handles retrieving all accounts of a user
+	ListAccountsByUser(ctx *gin.Context)
+
+	// CloseAccount handles soft-deleting an account (deactivating)
+	CloseAccount(ctx *gin.Context)
+
+	// HardDeleteAccount handles permanently removing an account
+	HardDeleteAccount(ctx *gin.Context)
+
+	// UpdateAccountTerms handles setting the interest rate and overdraft limit of an account
+	UpdateAccountTerms(ctx *gin.Context)
+}
+
+// UserAccountHandler defines the interface for the user onboarding HTTP handler
+type UserAccountHandler interface {
+	// CreateUserAccount handles creating a user and their first account in one step
+	CreateUserAccount(ctx *gin.Context)
+}
+
+// TransferHandler defines the interface for transfer HTTP handlers
+type TransferHandler interface {
+	CreateTransfer(ctx *gin.Context)
+	ReverseTransaction(ctx *gin.Context)
+	RefundTransaction(ctx *gin.Context)
+}
+
+// HoldHandler defines the interface for authorization hold HTTP handlers
+type HoldHandler interface {
+	AuthorizeHold(ctx *gin.Context)
+	GetHold(ctx *gin.Context)
+	ListOpenHolds(ctx *gin.Context)
+	CaptureHold(ctx *gin.Context)
+	ReleaseHold(ctx *gin.Context)
+}
+
+// ScheduledTransferHandler defines the interface for scheduled transfer HTTP handlers
+type ScheduledTransferHandler interface {
+	CreateScheduledTransfer(ctx *gin.Context)
+	GetScheduledTransfer(ctx *gin.Context)
+	ListScheduledTransfers(ctx *gin.Context)
+	ListExecutions(ctx *gin.Context)
+	PauseScheduledTransfer(ctx *gin.Context)
+	ResumeScheduledTransfer(ctx *gin.Context)
+	CancelScheduledTransfer(ctx *gin.Context)
+}
+
+// TransferBatchHandler defines the interface for batch transfer HTTP handlers
+type TransferBatchHandler interface {
+	CreateTransferBatch(ctx *gin.Context)
+	UploadTransferBatch(ctx *gin.Context)
+	GetTransferBatch(ctx *gin.Context)
+	ListTransferBatchItems(ctx *gin.Context)
+	DownloadTransferBatchReport(ctx *gin.Context)
+}
+
+// ReconciliationHandler defines the interface for ledger reconciliation HTTP handlers
+type ReconciliationHandler interface {
+	RunReconciliation(ctx *gin.Context)
+	ListReconciliationRuns(ctx *gin.Context)
+	GetReconciliationRun(ctx *gin.Context)
+	ListLedgerDiscrepancies(ctx *gin.Context)
+}
+
+// InterestHandler defines the interface for interest HTTP handlers
+type InterestHandler interface {
+	UpsertInterestSettings(ctx *gin.Context)
+	GetInterestSettings(ctx *gin.Context)
+	ListInterestSettings(ctx *gin.Context)
+	ListInterestAccruals(ctx *gin.Context)
+	RunInterest(ctx *gin.Context)
+}
+
+// FeeHandler defines the interface for fee HTTP handlers
+type FeeHandler interface {
+	UpsertFeeSchedule(ctx *gin.Context)
+	GetFeeSchedule(ctx *gin.Context)
+	ListFeeSchedules(ctx *gin.Context)
+	SetFeeIncomeAccount(ctx *gin.Context)
+	ListFeeIncomeAccounts(ctx *gin.Context)
+	ListFeeCharges(ctx *gin.Context)
+	PreviewFees(ctx *gin.Context)
+	RunFees(ctx *gin.Context)
+}
+
+// FixedDepositHandler defines the interface for fixed deposit HTTP handlers
+type FixedDepositHandler interface {
+	OpenFixedDeposit(ctx *gin.Context)
+	GetFixedDeposit(ctx *gin.Context)
+	ListFixedDeposits(ctx *gin.Context)
+	BreakFixedDeposit(ctx *gin.Context)
+	RunFixedDepositMaturity(ctx *gin.Context)
+	ListFixedDepositTerms(ctx *gin.Context)
+	UpsertFixedDepositTerm(ctx *gin.Context)
+}
+
+// StatementHandler defines the interface for account statement HTTP handlers
+type StatementHandler interface {
+	DownloadAccountStatement(ctx *gin.Context)
+	CreateStatement(ctx *gin.Context)
+	GetStatement(ctx *gin.Context)
+	ListStatements(ctx *gin.Context)
+	DownloadStatement(ctx *gin.Context)
+}
+
+// AuditHandler defines the interface for audit trail HTTP handlers
+type AuditHandler interface {
+	ListAuditTrail(ctx *gin.Context)
+	GetAuditRecord(ctx *gin.Context)
+}
+
+// ExchangeRateHandler defines the interface for exchange rate HTTP handlers
+type ExchangeRateHandler interface {
+	GetExchangeRate(ctx *gin.Context)
+	ListExchangeRateHistory(ctx *gin.Context)
+}
+
+// CurrencyHandler defines the interface for currency registry HTTP handlers
+type CurrencyHandler interface {
+	CreateCurrency(ctx *gin.Context)
+	GetCurrency(ctx *gin.Context)
+	ListCurrencies(ctx *gin.Context)
+	UpdateCurrency(ctx *gin.Context)
+	DeleteCurrency(ctx *gin.Context)
 }
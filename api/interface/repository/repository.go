@@ -4,10 +4,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	db "github.com/riad/banksystemendtoend/db/sqlc"
 )
 
-// AccountRepository defines the interface for account-related database operations
+// AccountTypeRepository defines the interface for account type-related database operations
 type AccountTypeRepository interface {
 	// CreateAccountType creates a new account type
 	CreateAccountType(ctx context.Context, arg db.CreateAccountTypeParams) (db.AccountType, error)
@@ -33,6 +34,9 @@ type UserRepository interface {
 	// GetUser retrieves a user by their ID
 	GetUser(ctx context.Context, userID int64) (db.User, error)
 
+	// GetUserByUsername retrieves a user by their username
+	GetUserByUsername(ctx context.Context, username string) (db.User, error)
+
 	//ListUsers retrieves a list of users with the given limit and offset
 	ListUsers(ctx context.Context, limit, offset int32) ([]db.User, error)
 
@@ -47,4 +51,289 @@ type UserRepository interface {
 
 	// UpdateLastLogin updates the last login time of a user with the given ID
 	UpdateLastLogin(ctx context.Context, userID int64, time time.Time) error
+
+	// GetUserAccess retrieves the role and status of a user with the given ID
+	GetUserAccess(ctx context.Context, userID int64) (db.GetUserAccessRow, error)
+
+	// UpdateUserRole changes the role of a user with the given ID
+	UpdateUserRole(ctx context.Context, userID int64, role db.UserRole) (db.User, error)
+}
+
+// RefreshTokenRepository defines the interface for refresh token database operations
+type RefreshTokenRepository interface {
+	// CreateRefreshToken stores the hash of a new refresh token
+	CreateRefreshToken(ctx context.Context, arg db.CreateRefreshTokenParams) (db.RefreshToken, error)
+
+	// GetRefreshToken retrieves a refresh token by its hash
+	GetRefreshToken(ctx context.Context, tokenHash string) (db.RefreshToken, error)
+
+	// RevokeRefreshTokenFamily revokes every token rotated from the same login
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+}
+
+// TwoFactorRepository defines the interface for the database operations of two-factor authentication
+type TwoFactorRepository interface {
+	// StartTwoFactorEnrollment stores a new secret for a user who has not enabled two-factor authentication
+	StartTwoFactorEnrollment(ctx context.Context, arg db.StartTwoFactorEnrollmentParams) (db.UserTwoFactor, error)
+
+	// GetTwoFactor retrieves the second factor of a user
+	GetTwoFactor(ctx context.Context, userID int64) (db.UserTwoFactor, error)
+
+	// EnableTwoFactor confirms an enrollment and stores the hashes of the recovery codes
+	EnableTwoFactor(ctx context.Context, arg db.EnableTwoFactorParams) (db.UserTwoFactor, error)
+
+	// UseTwoFactorStep records the time step of an accepted code, it fails for steps used before
+	UseTwoFactorStep(ctx context.Context, arg db.UseTwoFactorStepParams) (db.UserTwoFactor, error)
+
+	// UseRecoveryCode removes a recovery code by its hash, it fails for codes used before
+	UseRecoveryCode(ctx context.Context, arg db.UseRecoveryCodeParams) (db.UserTwoFactor, error)
+
+	// RecordTwoFactorFailure counts a wrong code and returns the failures within the lockout window
+	RecordTwoFactorFailure(ctx context.Context, arg db.RecordTwoFactorFailureParams) (db.UserTwoFactor, error)
+
+	// DeleteTwoFactor disables two-factor authentication for a user
+	DeleteTwoFactor(ctx context.Context, userID int64) error
+}
+
+// APIKeyRepository defines the interface for API key database operations
+type APIKeyRepository interface {
+	// CreateAPIKey stores the hash of a new API key
+	CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (db.ApiKey, error)
+
+	// GetAPIKey retrieves an API key by its ID
+	GetAPIKey(ctx context.Context, apiKeyID int32) (db.ApiKey, error)
+
+	// GetAPIKeyByPrefix retrieves an API key by the prefix of the key, from the cache when it can
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (db.ApiKey, error)
+
+	// ListAPIKeys retrieves API keys, of one owner or of every owner
+	ListAPIKeys(ctx context.Context, arg db.ListAPIKeysParams) ([]db.ApiKey, error)
+
+	// RevokeAPIKey revokes an API key that is not revoked yet
+	RevokeAPIKey(ctx context.Context, apiKeyID int32) (db.ApiKey, error)
+
+	// TouchAPIKey records when an API key was last used
+	TouchAPIKey(ctx context.Context, key db.ApiKey, usedAt time.Time) error
+
+	// InvalidateAPIKey drops the cached copy of the API key with prefix
+	InvalidateAPIKey(ctx context.Context, prefix string)
+}
+
+// NonceRepository defines the interface for remembering the nonces of signed requests
+type NonceRepository interface {
+	// ClaimNonce records a nonce of the client whose API key has keyPrefix for ttl, and reports
+	// whether it was new
+	ClaimNonce(ctx context.Context, keyPrefix, nonce string, ttl time.Duration) (bool, error)
+}
+
+// AccountRepository defines the interface for account-related database operations
+type AccountRepository interface {
+	// CreateAccount creates a new account
+	CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error)
+
+	// GetAccount retrieves an account by its ID
+	GetAccount(ctx context.Context, accountID int64) (db.Account, error)
+
+	// GetAccountByNumber retrieves an account by its account number
+	GetAccountByNumber(ctx context.Context, accountNumber string) (db.Account, error)
+
+	// ListAccountsByUser retrieves all accounts owned by a user
+	ListAccountsByUser(ctx context.Context, userID int64) ([]db.Account, error)
+
+	// CloseAccount deactivates an account that holds no money, it reports false when the account
+	// still holds some
+	CloseAccount(ctx context.Context, accountID int64) (bool, error)
+
+	// HardDeleteAccount permanently removes an account
+	HardDeleteAccount(ctx context.Context, accountID int64) error
+
+	// UpdateAccountTerms sets the interest rate and overdraft limit of an account
+	UpdateAccountTerms(ctx context.Context, arg db.UpdateAccountTermsParams) (db.Account, error)
+
+	// HasTransferredTo reports whether any account of a user has completed a transaction to an account
+	HasTransferredTo(ctx context.Context, userID, accountID int64) (bool, error)
+}
+
+// HoldRepository defines the interface for authorization hold database operations
+type HoldRepository interface {
+	// GetHold retrieves a hold by its public hold number
+	GetHold(ctx context.Context, holdNumber uuid.UUID) (db.Hold, error)
+
+	// ListOpenHoldsByAccount retrieves the holds still reserving funds on an account
+	ListOpenHoldsByAccount(ctx context.Context, accountID int64) ([]db.Hold, error)
+}
+
+// ScheduledTransferRepository defines the interface for scheduled transfer database operations
+type ScheduledTransferRepository interface {
+	// CreateScheduledTransfer stores a new scheduled transfer
+	CreateScheduledTransfer(ctx context.Context, arg db.CreateScheduledTransferParams) (db.ScheduledTransfer, error)
+
+	// GetScheduledTransfer retrieves a scheduled transfer by its schedule number
+	GetScheduledTransfer(ctx context.Context, scheduleNumber uuid.UUID) (db.ScheduledTransfer, error)
+
+	// ListScheduledTransfersByAccount retrieves the scheduled transfers paid from an account
+	ListScheduledTransfersByAccount(ctx context.Context, accountID int64) ([]db.ScheduledTransfer, error)
+
+	// ListScheduledTransferExecutions retrieves the execution history of a scheduled transfer, newest first
+	ListScheduledTransferExecutions(ctx context.Context, arg db.ListScheduledTransferExecutionsParams) ([]db.ScheduledTransferExecution, error)
+}
+
+// TransferBatchRepository defines the interface for transfer batch database operations
+type TransferBatchRepository interface {
+	// GetTransferBatch retrieves a batch by its batch number
+	GetTransferBatch(ctx context.Context, batchNumber uuid.UUID) (db.TransferBatch, error)
+
+	// ListTransferBatchItems retrieves the items of a batch in upload order
+	ListTransferBatchItems(ctx context.Context, batchID int32) ([]db.TransferBatchItem, error)
+}
+
+// IdempotencyRepository defines the interface for idempotency key database operations
+type IdempotencyRepository interface {
+	// CreateIdempotencyKey reserves a key; it returns no rows when the key is already taken
+	CreateIdempotencyKey(ctx context.Context, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error)
+
+	// GetCachedIdempotencyKey retrieves a completed key from the cache, it reports false on a miss
+	GetCachedIdempotencyKey(ctx context.Context, clientID, key string) (db.IdempotencyKey, bool)
+
+	// GetIdempotencyKey retrieves a key from the database
+	GetIdempotencyKey(ctx context.Context, clientID, key string) (db.IdempotencyKey, error)
+
+	// CompleteIdempotencyKey stores the response produced for a key
+	CompleteIdempotencyKey(ctx context.Context, arg db.CompleteIdempotencyKeyParams) (db.IdempotencyKey, error)
+
+	// DeleteIdempotencyKey removes a key so the request can be retried
+	DeleteIdempotencyKey(ctx context.Context, clientID, key string) error
+
+	// DeleteStaleIdempotencyKey removes a key reserved before createdBefore by a request that never
+	// completed, it reports whether the key was removed
+	DeleteStaleIdempotencyKey(ctx context.Context, clientID, key string, createdBefore time.Time) (bool, error)
+}
+
+// ReconciliationRepository defines the interface for reconciliation run database operations
+type ReconciliationRepository interface {
+	// GetReconciliationRun retrieves a run by its run number
+	GetReconciliationRun(ctx context.Context, runNumber uuid.UUID) (db.ReconciliationRun, error)
+
+	// ListReconciliationRuns retrieves runs, most recent first
+	ListReconciliationRuns(ctx context.Context, arg db.ListReconciliationRunsParams) ([]db.ReconciliationRun, error)
+
+	// ListLedgerDiscrepancies retrieves the findings of a run
+	ListLedgerDiscrepancies(ctx context.Context, arg db.ListLedgerDiscrepanciesParams) ([]db.LedgerDiscrepancy, error)
+}
+
+// InterestRepository defines the interface for interest settings and accrual database operations
+type InterestRepository interface {
+	// UpsertInterestSettings creates or replaces the interest settings of an account type
+	UpsertInterestSettings(ctx context.Context, arg db.UpsertInterestSettingsParams) (db.InterestSetting, error)
+
+	// GetInterestSettings retrieves the interest settings of an account type
+	GetInterestSettings(ctx context.Context, accountType string) (db.InterestSetting, error)
+
+	// ListInterestSettings retrieves the interest settings of every account type
+	ListInterestSettings(ctx context.Context) ([]db.InterestSetting, error)
+
+	// ListInterestAccruals retrieves the accruals of an account, most recent day first
+	ListInterestAccruals(ctx context.Context, arg db.ListInterestAccrualsParams) ([]db.InterestAccrual, error)
+}
+
+// FeeRepository defines the interface for fee schedule, fee income account and fee charge database operations
+type FeeRepository interface {
+	// UpsertFeeSchedule creates or replaces the fee schedule of an account type
+	UpsertFeeSchedule(ctx context.Context, arg db.UpsertFeeScheduleParams) (db.FeeSchedule, error)
+
+	// GetFeeSchedule retrieves the fee schedule of an account type
+	GetFeeSchedule(ctx context.Context, accountType string) (db.FeeSchedule, error)
+
+	// ListFeeSchedules retrieves the fee schedule of every account type
+	ListFeeSchedules(ctx context.Context) ([]db.FeeSchedule, error)
+
+	// UpsertFeeIncomeAccount sets the account that collects the fees of a currency
+	UpsertFeeIncomeAccount(ctx context.Context, arg db.UpsertFeeIncomeAccountParams) (db.FeeIncomeAccount, error)
+
+	// ListFeeIncomeAccounts retrieves the fee income account of every currency
+	ListFeeIncomeAccounts(ctx context.Context) ([]db.FeeIncomeAccount, error)
+
+	// ListFeeChargesByAccount retrieves the fees charged to an account, most recent first
+	ListFeeChargesByAccount(ctx context.Context, arg db.ListFeeChargesByAccountParams) ([]db.FeeCharge, error)
+}
+
+// FixedDepositRepository defines the interface for fixed deposit database operations
+type FixedDepositRepository interface {
+	// GetFixedDeposit retrieves a fixed deposit by its deposit number
+	GetFixedDeposit(ctx context.Context, depositNumber uuid.UUID) (db.FixedDeposit, error)
+
+	// ListFixedDepositsByLinkedAccount retrieves the deposits funded from an account, newest first
+	ListFixedDepositsByLinkedAccount(ctx context.Context, arg db.ListFixedDepositsByLinkedAccountParams) ([]db.FixedDeposit, error)
+
+	// UpsertFixedDepositTerm creates or replaces the rates of a term
+	UpsertFixedDepositTerm(ctx context.Context, arg db.UpsertFixedDepositTermParams) (db.FixedDepositTerm, error)
+
+	// ListFixedDepositTerms retrieves every configured term, shortest first
+	ListFixedDepositTerms(ctx context.Context) ([]db.FixedDepositTerm, error)
+}
+
+// StatementRepository defines the interface for statement export database operations
+type StatementRepository interface {
+	// CreateStatement queues a statement for the statement job
+	CreateStatement(ctx context.Context, arg db.CreateStatementParams) (db.Statement, error)
+
+	// GetStatement retrieves a statement by its statement number
+	GetStatement(ctx context.Context, statementNumber uuid.UUID) (db.Statement, error)
+
+	// ListStatementsByAccount retrieves the statements requested for an account, newest first
+	ListStatementsByAccount(ctx context.Context, arg db.ListStatementsByAccountParams) ([]db.Statement, error)
+
+	// ClaimPendingStatements marks a batch of pending statements as being generated and returns them
+	ClaimPendingStatements(ctx context.Context, arg db.ClaimPendingStatementsParams) ([]db.Statement, error)
+
+	// CompleteStatement records where a generated statement is stored
+	CompleteStatement(ctx context.Context, arg db.CompleteStatementParams) (db.Statement, error)
+
+	// FailStatement gives up on a statement
+	FailStatement(ctx context.Context, arg db.FailStatementParams) (db.Statement, error)
+
+	// CountStatementEntries counts the entries a statement period would list
+	CountStatementEntries(ctx context.Context, arg db.CountStatementEntriesParams) (int64, error)
+}
+
+// AuditRepository defines the interface for audit trail database operations
+type AuditRepository interface {
+	// GetAuditTrail retrieves one audit record
+	GetAuditTrail(ctx context.Context, auditID int32) (db.AuditTrail, error)
+
+	// ListAuditTrail retrieves the audit records matching the filters, newest first
+	ListAuditTrail(ctx context.Context, arg db.ListAuditTrailParams) ([]db.AuditTrail, error)
+
+	// CreateAccessDeniedAudit records a request refused by access control
+	CreateAccessDeniedAudit(ctx context.Context, arg db.CreateAccessDeniedAuditParams) error
+}
+
+// ExchangeRateRepository defines the interface for exchange rate history database operations
+type ExchangeRateRepository interface {
+	// GetCurrency retrieves a currency by its code
+	GetCurrency(ctx context.Context, currencyCode string) (db.AccountCurrency, error)
+
+	// ListExchangeRateHistory retrieves the recorded rates of a currency, newest first
+	ListExchangeRateHistory(ctx context.Context, arg db.ListExchangeRateHistoryParams) ([]db.ExchangeRateHistory, error)
+}
+
+// CurrencyRepository defines the interface for currency registry database operations
+type CurrencyRepository interface {
+	// CreateCurrency adds a currency to the registry
+	CreateCurrency(ctx context.Context, arg db.CreateCurrencyParams) (db.AccountCurrency, error)
+
+	// GetCurrency retrieves a currency by its code
+	GetCurrency(ctx context.Context, currencyCode string) (db.AccountCurrency, error)
+
+	// ListCurrencies retrieves the active currencies, or every currency when includeInactive is set
+	ListCurrencies(ctx context.Context, includeInactive bool) ([]db.AccountCurrency, error)
+
+	// UpdateCurrency changes the fields of a currency that are set in arg
+	UpdateCurrency(ctx context.Context, arg db.UpdateCurrencyParams) (db.AccountCurrency, error)
+
+	// UpdateExchangeRate sets the value of one unit of a currency in the base currency
+	UpdateExchangeRate(ctx context.Context, arg db.UpdateExchangeRateParams) (db.AccountCurrency, error)
+
+	// CountAccountsByCurrency counts the accounts held in a currency
+	CountAccountsByCurrency(ctx context.Context, currencyCode string) (int64, error)
 }
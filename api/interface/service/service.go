@@ -3,9 +3,14 @@ package interface_service
 import (
 	"context"
 	"mime/multipart"
+	"time"
 
 	"github.com/riad/banksystemendtoend/api/dto"
 	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/pkg/model"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/riad/banksystemendtoend/util/signing"
+	"github.com/riad/banksystemendtoend/util/statement"
 )
 
 // AccountTypeService defines the interface for account type-related business logic
@@ -31,6 +36,12 @@ type S3Service interface {
 	// UploadFile uploads a file to S3 and returns the URL
 	UploadFile(ctx context.Context, file *multipart.FileHeader) (string, error)
 
+	// UploadData stores generated content under name and returns the URL
+	UploadData(ctx context.Context, name string, data []byte, contentType string) (string, error)
+
+	// DownloadFile reads back a file stored by this service
+	DownloadFile(ctx context.Context, fileURL string) ([]byte, error)
+
 	// DeleteFile removes a file from S3
 	DeleteFile(ctx context.Context, fileURL string) error
 }
@@ -54,4 +65,326 @@ type UserService interface {
 
 	// HardDeleteUser permanently removes a user from the system
 	HardDeleteUser(ctx context.Context, userID int64) error
+
+	// UpdateLastLogin records the current time as the user's last login
+	UpdateLastLogin(ctx context.Context, userID int64) error
+
+	// UpdateUserRole changes the role of a user, users cannot change their own
+	UpdateUserRole(ctx context.Context, userID int64, req dto.UpdateUserRoleRequest) (db.User, error)
+}
+
+// AccessControlService defines the business logic interface for deciding what authenticated users may do
+type AccessControlService interface {
+	// UserRole returns the role of an active user
+	UserRole(ctx context.Context, userID int64) (db.UserRole, error)
+
+	// RecordDenied writes a refused request to the audit trail
+	RecordDenied(ctx context.Context, denial schemas.AccessDenial) error
+}
+
+// AuthService defines the business logic interface for logging users in and out
+type AuthService interface {
+	// Login checks a user's credentials and issues an access token and a refresh token. Users with
+	// two-factor authentication get a challenge token instead, see VerifyTwoFactor.
+	Login(ctx context.Context, req dto.LoginRequest) (schemas.LoginResult, error)
+
+	// VerifyTwoFactor exchanges the challenge token of a login and a second factor for tokens
+	VerifyTwoFactor(ctx context.Context, req dto.TwoFactorLoginRequest) (schemas.AuthTokens, error)
+
+	// RefreshToken exchanges a refresh token for new tokens, the old refresh token stops working
+	RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (schemas.AuthTokens, error)
+
+	// Logout revokes a refresh token and every token rotated from the same login
+	Logout(ctx context.Context, refreshToken string) error
+}
+
+// TwoFactorService defines the business logic interface for TOTP two-factor authentication
+type TwoFactorService interface {
+	// Enroll generates a secret for a user to add to an authenticator app, it only takes effect once confirmed
+	Enroll(ctx context.Context, userID int64) (schemas.TwoFactorEnrollment, error)
+
+	// Confirm enables two-factor authentication with a first code and returns the recovery codes
+	Confirm(ctx context.Context, userID int64, code string) ([]string, error)
+
+	// Disable turns two-factor authentication off, it takes a code or a recovery code
+	Disable(ctx context.Context, userID int64, code string) error
+
+	// Enabled reports whether a user has confirmed two-factor authentication
+	Enabled(ctx context.Context, userID int64) (bool, error)
+
+	// Verify checks a code or a recovery code of a user, each is accepted once
+	Verify(ctx context.Context, userID int64, code string) error
+}
+
+// APIKeyService defines the business logic interface for issuing and checking API keys
+type APIKeyService interface {
+	// Issue creates an API key, the key itself is only returned here
+	Issue(ctx context.Context, req dto.IssueAPIKeyRequest) (schemas.IssuedAPIKey, error)
+
+	// List retrieves API keys, of one owner or of every owner
+	List(ctx context.Context, query dto.APIKeyQuery, page, pageSize int32) ([]db.ApiKey, error)
+
+	// Rotate issues the replacement of an API key, the old key keeps working for an overlap period
+	Rotate(ctx context.Context, apiKeyID int64, req dto.RotateAPIKeyRequest) (schemas.IssuedAPIKey, error)
+
+	// Revoke makes an API key stop working right away
+	Revoke(ctx context.Context, apiKeyID int64) (db.ApiKey, error)
+
+	// Validate returns the stored API key a request was sent with, if it is known, active and not expired
+	Validate(ctx context.Context, key string) (db.ApiKey, error)
+}
+
+// RequestSigningService defines the business logic interface for checking signed requests
+type RequestSigningService interface {
+	// Verify checks the signature of a request sent with key, and that its nonce was not used before
+	Verify(ctx context.Context, key db.ApiKey, req signing.Request, signature string) error
+}
+
+// AccountService defines the business logic interface for account operations
+type AccountService interface {
+	// CreateAccount opens a new account with a server-generated account number
+	CreateAccount(ctx context.Context, req dto.CreateAccountRequest) (db.Account, error)
+
+	// GetAccount retrieves an account by ID
+	GetAccount(ctx context.Context, accountID int64) (db.Account, error)
+
+	// GetAccountByNumber retrieves an account by its account number
+	GetAccountByNumber(ctx context.Context, accountNumber string) (db.Account, error)
+
+	// ListAccountsByUser retrieves all accounts owned by a user
+	ListAccountsByUser(ctx context.Context, userID int64) ([]db.Account, error)
+
+	// CloseAccount soft deletes an account (marks as inactive), once it holds no money
+	CloseAccount(ctx context.Context, accountID int64) error
+
+	// HardDeleteAccount permanently removes an account from the system
+	HardDeleteAccount(ctx context.Context, accountID int64) error
+
+	// UpdateAccountTerms sets the interest rate and overdraft limit of an account
+	UpdateAccountTerms(ctx context.Context, accountID int64, req dto.UpdateAccountTermsRequest) (db.Account, error)
+}
+
+// UserAccountService defines the business logic interface for onboarding a user together with an account
+type UserAccountService interface {
+	// CreateUserWithAccount atomically creates a user and their first account
+	CreateUserWithAccount(ctx context.Context, req dto.CreateUserAccountRequest) (db.User, db.Account, error)
+}
+
+// TransferService defines the business logic interface for moving money between accounts
+type TransferService interface {
+	CreateTransfer(ctx context.Context, req dto.CreateTransferRequest) (schemas.TransferTxResult, error)
+
+	// ReverseTransaction compensates everything not yet refunded on a transaction
+	ReverseTransaction(ctx context.Context, transactionNumber string, req dto.ReverseTransactionRequest) (schemas.ReversalTxResult, error)
+
+	// RefundTransaction returns part of a transaction to its sender
+	RefundTransaction(ctx context.Context, transactionNumber string, req dto.RefundTransactionRequest) (schemas.ReversalTxResult, error)
+}
+
+// HoldService defines the business logic interface for authorization holds
+type HoldService interface {
+	// AuthorizeHold reserves funds on an account for a merchant
+	AuthorizeHold(ctx context.Context, req dto.AuthorizeHoldRequest) (schemas.HoldTxResult, error)
+
+	// GetHold retrieves a hold by its hold number
+	GetHold(ctx context.Context, holdNumber string) (db.Hold, error)
+
+	// ListOpenHolds retrieves the open holds of an account
+	ListOpenHolds(ctx context.Context, accountID int64) ([]db.Hold, error)
+
+	// CaptureHold settles a hold in full or in part
+	CaptureHold(ctx context.Context, holdNumber string, req dto.CaptureHoldRequest) (schemas.HoldTxResult, error)
+
+	// ReleaseHold cancels a hold and frees the reserved funds
+	ReleaseHold(ctx context.Context, holdNumber string) (schemas.HoldTxResult, error)
+}
+
+// ScheduledTransferService defines the business logic interface for one-off and recurring transfers
+type ScheduledTransferService interface {
+	// CreateScheduledTransfer validates the schedule and stores it
+	CreateScheduledTransfer(ctx context.Context, req dto.CreateScheduledTransferRequest) (db.ScheduledTransfer, error)
+
+	// GetScheduledTransfer retrieves a scheduled transfer by its schedule number
+	GetScheduledTransfer(ctx context.Context, scheduleNumber string) (db.ScheduledTransfer, error)
+
+	// ListScheduledTransfers retrieves the scheduled transfers paid from an account
+	ListScheduledTransfers(ctx context.Context, accountID int64) ([]db.ScheduledTransfer, error)
+
+	// ListExecutions retrieves the execution history of a scheduled transfer
+	ListExecutions(ctx context.Context, scheduleNumber string, page, pageSize int32) ([]db.ScheduledTransferExecution, error)
+
+	// UpdateStatus pauses, resumes or cancels a scheduled transfer
+	UpdateStatus(ctx context.Context, scheduleNumber string, status db.ScheduledTransferStatus) (db.ScheduledTransfer, error)
+}
+
+// TransferBatchService defines the business logic interface for batch transfers
+type TransferBatchService interface {
+	// CreateBatch stores a batch of transfers for the batch worker
+	CreateBatch(ctx context.Context, req dto.CreateTransferBatchRequest) (db.TransferBatch, error)
+
+	// UploadBatch queues a CSV file of transfers, the batch waits in UPLOADED until the file is imported
+	UploadBatch(ctx context.Context, req dto.UploadTransferBatchRequest) (db.TransferBatch, error)
+
+	// ImportUpload reads the transfers of a queued CSV file into its batch
+	ImportUpload(ctx context.Context, job *model.UploadJob) error
+
+	// GetBatch retrieves a batch and its progress by batch number
+	GetBatch(ctx context.Context, batchNumber string) (db.TransferBatch, error)
+
+	// ListItems retrieves a batch together with the outcome of each of its items
+	ListItems(ctx context.Context, batchNumber string) (db.TransferBatch, []db.TransferBatchItem, error)
+}
+
+// IdempotencyService defines the business logic interface for idempotent request handling
+type IdempotencyService interface {
+	// Begin reserves the key for a request. It returns the stored key when the request is a replay
+	// of a completed one, and nil when the caller should execute the request.
+	Begin(ctx context.Context, clientID, key, method, path, requestHash string) (*db.IdempotencyKey, error)
+
+	// Complete stores the response of an executed request
+	Complete(ctx context.Context, clientID, key string, status int, body []byte) error
+
+	// Release frees the key of a request that failed, so it can be retried
+	Release(ctx context.Context, clientID, key string) error
+}
+
+// ReconciliationService defines the business logic interface for ledger reconciliation
+type ReconciliationService interface {
+	// Reconcile checks the ledger of the given accounts, or of every account when none are given
+	Reconcile(ctx context.Context, req dto.RunReconciliationRequest) (schemas.ReconciliationResult, error)
+
+	// GetRun retrieves a run by its run number
+	GetRun(ctx context.Context, runNumber string) (db.ReconciliationRun, error)
+
+	// ListRuns retrieves a page of runs, most recent first
+	ListRuns(ctx context.Context, page, pageSize int32) ([]db.ReconciliationRun, error)
+
+	// ListDiscrepancies retrieves a page of the findings of a run
+	ListDiscrepancies(ctx context.Context, runNumber string, page, pageSize int32) ([]db.LedgerDiscrepancy, error)
+}
+
+// InterestService defines the business logic interface for interest accrual and posting
+type InterestService interface {
+	// UpsertSettings creates or replaces the interest settings of an account type
+	UpsertSettings(ctx context.Context, accountType string, req dto.UpsertInterestSettingsRequest) (db.InterestSetting, error)
+
+	// GetSettings retrieves the interest settings of an account type
+	GetSettings(ctx context.Context, accountType string) (db.InterestSetting, error)
+
+	// ListSettings retrieves the interest settings of every account type
+	ListSettings(ctx context.Context) ([]db.InterestSetting, error)
+
+	// ListAccruals retrieves a page of the accruals of an account
+	ListAccruals(ctx context.Context, accountID int32, page, pageSize int32) ([]db.InterestAccrual, error)
+
+	// Run accrues and posts interest up to the requested day, it returns the day used
+	Run(ctx context.Context, req dto.RunInterestRequest) (schemas.InterestRunResult, time.Time, error)
+}
+
+// FeeService defines the business logic interface for fee schedules and fee charges
+type FeeService interface {
+	// UpsertSchedule creates or replaces the fee schedule of an account type
+	UpsertSchedule(ctx context.Context, accountType string, req dto.UpsertFeeScheduleRequest) (db.FeeSchedule, error)
+
+	// GetSchedule retrieves the fee schedule of an account type
+	GetSchedule(ctx context.Context, accountType string) (db.FeeSchedule, error)
+
+	// ListSchedules retrieves the fee schedule of every account type
+	ListSchedules(ctx context.Context) ([]db.FeeSchedule, error)
+
+	// SetIncomeAccount sets the account that collects the fees of a currency
+	SetIncomeAccount(ctx context.Context, currencyCode string, req dto.SetFeeIncomeAccountRequest) (db.FeeIncomeAccount, error)
+
+	// ListIncomeAccounts retrieves the fee income account of every currency
+	ListIncomeAccounts(ctx context.Context) ([]db.FeeIncomeAccount, error)
+
+	// ListCharges retrieves a page of the fees charged to an account
+	ListCharges(ctx context.Context, accountID int32, page, pageSize int32) ([]db.FeeCharge, error)
+
+	// Preview quotes the amounts and fees of a transfer without making it
+	Preview(ctx context.Context, req dto.FeePreviewRequest) (schemas.FeePreview, error)
+
+	// Run charges the maintenance and overdraft fees of the requested day, it returns the day used
+	Run(ctx context.Context, req dto.RunFeesRequest) (schemas.PeriodicFeeResult, time.Time, error)
+}
+
+// FixedDepositService defines the business logic interface for fixed deposits
+type FixedDepositService interface {
+	// Open moves the principal from the linked account into a new fixed deposit
+	Open(ctx context.Context, req dto.OpenFixedDepositRequest) (schemas.FixedDepositTxResult, error)
+
+	// Get retrieves a fixed deposit by its deposit number
+	Get(ctx context.Context, depositNumber string) (db.FixedDeposit, error)
+
+	// ListByLinkedAccount retrieves a page of the deposits funded from an account
+	ListByLinkedAccount(ctx context.Context, accountID int64, page, pageSize int32) ([]db.FixedDeposit, error)
+
+	// Break ends a deposit before maturity, charging the early break penalty
+	Break(ctx context.Context, depositNumber string) (schemas.FixedDepositTxResult, error)
+
+	// RunMaturity pays out or rolls over every deposit that has matured, it returns how many were processed
+	RunMaturity(ctx context.Context) (int, error)
+
+	// UpsertTerm sets the rates new deposits of a term are opened with
+	UpsertTerm(ctx context.Context, termMonths int64, req dto.UpsertFixedDepositTermRequest) (db.FixedDepositTerm, error)
+
+	// ListTerms retrieves every configured term
+	ListTerms(ctx context.Context) ([]db.FixedDepositTerm, error)
+}
+
+// StatementService defines the business logic interface for account statements
+type StatementService interface {
+	// Export renders a statement straight away, refusing periods with too many entries
+	Export(ctx context.Context, accountID int64, query dto.StatementPeriodQuery) (statement.File, error)
+
+	// Request queues a statement for the statement job
+	Request(ctx context.Context, req dto.CreateStatementRequest) (db.Statement, error)
+
+	// Get retrieves a statement by its statement number
+	Get(ctx context.Context, statementNumber string) (db.Statement, error)
+
+	// ListByAccount retrieves a page of the statements requested for an account
+	ListByAccount(ctx context.Context, accountID int64, page, pageSize int32) ([]db.Statement, error)
+
+	// Download reads a completed statement back from storage
+	Download(ctx context.Context, statementNumber string) (statement.File, error)
+
+	// ProcessPending generates and stores the statements waiting for the statement job
+	ProcessPending(ctx context.Context) error
+}
+
+// AuditService defines the business logic interface for searching the audit trail
+type AuditService interface {
+	// Get retrieves one audit record
+	Get(ctx context.Context, auditID int64) (db.AuditTrail, error)
+
+	// List retrieves a page of the audit records matching the query, newest first
+	List(ctx context.Context, query dto.AuditTrailQuery, page, pageSize int32) ([]db.AuditTrail, error)
+}
+
+// ExchangeRateService defines the business logic interface for looking up exchange rates
+type ExchangeRateService interface {
+	// Get returns the rate between two currencies, now or at the time the query asks for
+	Get(ctx context.Context, query dto.ExchangeRateQuery) (schemas.ExchangeRate, error)
+
+	// History retrieves a page of the recorded rates of a currency, newest first
+	History(ctx context.Context, currencyCode string, query dto.ExchangeRateHistoryQuery, page, pageSize int32) ([]db.ExchangeRateHistory, error)
+}
+
+// CurrencyService defines the business logic interface for the currency registry
+type CurrencyService interface {
+	// Create adds a currency to the registry
+	Create(ctx context.Context, req dto.CreateCurrencyRequest) (db.AccountCurrency, error)
+
+	// Get retrieves a currency by its code
+	Get(ctx context.Context, currencyCode string) (db.AccountCurrency, error)
+
+	// List retrieves the active currencies, or every currency when the query asks for it
+	List(ctx context.Context, query dto.CurrencyQuery) ([]db.AccountCurrency, error)
+
+	// Update changes a currency, minor units only while no account holds it
+	Update(ctx context.Context, currencyCode string, req dto.UpdateCurrencyRequest) (db.AccountCurrency, error)
+
+	// Deactivate stops a currency from being used for new accounts
+	Deactivate(ctx context.Context, currencyCode string) (db.AccountCurrency, error)
 }
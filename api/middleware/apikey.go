@@ -1,35 +1,39 @@
 package middleware
 
 import (
-	"fmt"
-	"log"
+	"errors"
 	"net/http"
-	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
 	"github.com/riad/banksystemendtoend/api/utils"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/apikey"
+	"go.uber.org/zap"
 )
 
-// APIKey struct holds the actual API key.
+const (
+	// authPathPrefix is where clients log in, which every scope may do
+	authPathPrefix = "/api/v1/auth/"
+	// apiKeyContextKey is where ValidateAPIKey leaves the stored key for VerifySignature
+	apiKeyContextKey = "api_key"
+)
+
+// APIKey checks the API keys clients identify themselves with against the keys stored in the database
 type APIKey struct {
-	apiKey string
+	service interface_service.APIKeyService
 }
 
-// NewAPIKey creates a new instance of APIKey by reading the API key from the environment variable.
-func NewAPIKey() (*APIKey, error) {
-	if err := utils.LoadEnvFile(); err != nil {
-		log.Printf("Warning: %v", err)
-	}
-
-	apiKey := os.Getenv("API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("API_KEY environment variable is not set")
-	}
-
-	return &APIKey{apiKey: apiKey}, nil
+// NewAPIKey creates a new instance of APIKey validating keys with service
+func NewAPIKey(service interface_service.APIKeyService) *APIKey {
+	return &APIKey{service: service}
 }
 
-// ValidateAPIKey is a middleware function that checks if the provided API key in the request
+// ValidateAPIKey is a middleware function that only lets requests through that carry a known, active
+// API key in X-API-Key whose scopes allow the request method. The key's ID and scopes are stored under
+// utils.APIKeyIDKey and utils.APIKeyScopesKey, RequirePermission checks back office routes against them.
 func (apk *APIKey) ValidateAPIKey() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Skip API key validation for health check endpoint
@@ -48,13 +52,37 @@ func (apk *APIKey) ValidateAPIKey() gin.HandlerFunc {
 			return
 		}
 
-		if apiKey != apk.apiKey {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid API key",
-				"code":  "INVALID_API_KEY",
+		key, err := apk.service.Validate(c, apiKey)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrInvalidAPIKey):
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": "Invalid API key",
+					"code":  "INVALID_API_KEY",
+				})
+			case errors.Is(err, common.ErrAPIKeyExpired):
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": err.Error(),
+					"code":  "EXPIRED_API_KEY",
+				})
+			default:
+				logger.GetLogger().Error("Failed to validate API key", zap.Error(err))
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to validate API key"})
+			}
+			return
+		}
+
+		if !strings.HasPrefix(c.Request.URL.Path, authPathPrefix) && !apikey.AllowsMethod(key.Scopes, c.Request.Method) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "API key scopes do not allow this request",
+				"code":  "API_KEY_SCOPE",
 			})
 			return
 		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Set(utils.APIKeyIDKey, key.ApiKeyID)
+		c.Set(utils.APIKeyScopesKey, key.Scopes)
 		c.Next()
 	}
 }
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// AuditActor records the client IP address of the request as the audit actor, so database
+// changes made while serving it are attributed in audit_trail. Services receive the gin context,
+// which the database pool reads the actor from when a connection is acquired.
+func AuditActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(db.AuditActorContextKey, db.AuditActor{IPAddress: c.ClientIP()})
+		c.Next()
+	}
+}
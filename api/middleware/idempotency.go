@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client chosen idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses that were served from a stored result
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// MaxIdempotentBodySize bounds the body Idempotency reads into memory to fingerprint, with room for
+// the largest transfer batch upload and its multipart framing
+const MaxIdempotentBodySize = 11 << 20
+
+// responseRecorder keeps a copy of the response body so it can be stored for replays
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// Idempotency is a middleware that executes a request at most once per Idempotency-Key and client.
+// Requests without the header are passed through unchanged.
+func Idempotency(service interface_service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse(common.ErrInvalidIdempotencyKey))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxIdempotentBodySize)
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+					"error": common.ErrIdempotentBodyTooLarge.Error(),
+					"code":  "BODY_TOO_LARGE",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		clientID := ClientIdentity(c)
+		requestHash := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		stored, err := service.Begin(c, clientID, key, c.Request.Method, c.Request.URL.Path, requestHash)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrIdempotencyKeyMismatch), errors.Is(err, common.ErrIdempotencyKeyInProgress):
+				c.AbortWithStatusJSON(http.StatusConflict, common.ErrorResponse(err))
+			default:
+				logger.GetLogger().Error("failed to reserve idempotency key", zap.Error(err))
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to process idempotency key"})
+			}
+			return
+		}
+
+		//? Replay the original response
+		if stored != nil {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(int(stored.ResponseStatus.Int32), "application/json; charset=utf-8", stored.ResponseBody.Bytes)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+		c.Set(common.ContextKeyIdempotencyReference, idempotencyReference(clientID, key))
+
+		c.Next()
+
+		//? The request outlives its own context here, the result must be stored regardless
+		ctx := context.Background()
+		status := recorder.Status()
+		if retryableStatus(status) {
+			// Server side failures and missing or wrong second factors are not final, let the client
+			// retry with the same key
+			if err := service.Release(ctx, clientID, key); err != nil {
+				logger.GetLogger().Error("failed to release idempotency key", zap.Error(err))
+			}
+			return
+		}
+		if err := service.Complete(ctx, clientID, key, status, recorder.body.Bytes()); err != nil {
+			logger.GetLogger().Error("failed to store idempotent response", zap.Error(err))
+		}
+	}
+}
+
+// retryableStatus reports whether a response leaves the request unexecuted for a reason the client
+// can fix without changing the request, such as sending the two-factor code a transfer asked for
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusPreconditionRequired, http.StatusTooManyRequests:
+		return true
+	}
+	return status >= http.StatusInternalServerError
+}
+
+// ClientIdentity returns the identity an idempotency key is scoped to: the authenticated user when
+// there is one, otherwise a hash of the API key used for the request.
+func ClientIdentity(c *gin.Context) string {
+	if userID, ok := utils.AuthUserID(c); ok {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	sum := sha256.Sum256([]byte(c.GetHeader("X-API-Key")))
+	return "apikey:" + hex.EncodeToString(sum[:])
+}
+
+// requestFingerprint hashes the parts of a request that must match for a replay
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotencyReference derives a stable transaction reference number from the client and key
+func idempotencyReference(clientID, key string) string {
+	sum := sha256.Sum256([]byte(clientID + "\x00" + key))
+	return "IDK-" + hex.EncodeToString(sum[:])[:32]
+}
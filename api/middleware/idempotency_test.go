@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/middleware"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryIdempotencyService keeps idempotency keys in memory, the way the database backed service
+// scopes them: one entry per client and key
+type memoryIdempotencyService struct {
+	mu   sync.Mutex
+	keys map[string]*db.IdempotencyKey
+}
+
+func newMemoryIdempotencyService() *memoryIdempotencyService {
+	return &memoryIdempotencyService{keys: make(map[string]*db.IdempotencyKey)}
+}
+
+func (s *memoryIdempotencyService) Begin(_ context.Context, clientID, key, method, path, requestHash string) (*db.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.keys[clientID+"\x00"+key]
+	if !ok {
+		s.keys[clientID+"\x00"+key] = &db.IdempotencyKey{
+			ClientID:       clientID,
+			IdempotencyKey: key,
+			RequestMethod:  method,
+			RequestPath:    path,
+			RequestHash:    requestHash,
+		}
+		return nil, nil
+	}
+	if stored.RequestHash != requestHash {
+		return nil, common.ErrIdempotencyKeyMismatch
+	}
+	if !stored.ResponseStatus.Valid {
+		return nil, common.ErrIdempotencyKeyInProgress
+	}
+	return stored, nil
+}
+
+func (s *memoryIdempotencyService) Complete(_ context.Context, clientID, key string, status int, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.keys[clientID+"\x00"+key]
+	stored.ResponseStatus = sql.NullInt32{Int32: int32(status), Valid: true}
+	stored.ResponseBody = pgtype.JSONB{Bytes: body, Status: pgtype.Present}
+	return nil
+}
+
+func (s *memoryIdempotencyService) Release(_ context.Context, clientID, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, clientID+"\x00"+key)
+	return nil
+}
+
+func TestIdempotencyKeyScopedToUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	executed := 0
+	router := gin.New()
+	router.POST("/transfers",
+		//? Stands in for the access token check, which stores the user the same way
+		func(c *gin.Context) {
+			var userID int64
+			_, err := fmt.Sscan(c.GetHeader("X-Test-User"), &userID)
+			require.NoError(t, err)
+			c.Set(utils.AuthUserIDKey, userID)
+		},
+		middleware.Idempotency(newMemoryIdempotencyService()),
+		func(c *gin.Context) {
+			executed++
+			userID, _ := utils.AuthUserID(c)
+			c.JSON(http.StatusCreated, gin.H{"user_id": userID, "execution": executed})
+		},
+	)
+
+	send := func(userID int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/transfers", strings.NewReader(`{"amount":"10.00"}`))
+		req.Header.Set(middleware.IdempotencyKeyHeader, "same-key")
+		req.Header.Set("X-Test-User", fmt.Sprint(userID))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := send(1)
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.JSONEq(t, `{"user_id":1,"execution":1}`, first.Body.String())
+
+	//? Another user choosing the same key gets its own request executed, not the first user's response
+	second := send(2)
+	require.Equal(t, http.StatusCreated, second.Code)
+	require.Empty(t, second.Header().Get(middleware.IdempotentReplayedHeader))
+	require.JSONEq(t, `{"user_id":2,"execution":2}`, second.Body.String())
+
+	//? The first user retrying is answered from the stored response
+	replay := send(1)
+	require.Equal(t, http.StatusCreated, replay.Code)
+	require.Equal(t, "true", replay.Header().Get(middleware.IdempotentReplayedHeader))
+	require.JSONEq(t, first.Body.String(), replay.Body.String())
+	require.Equal(t, 2, executed)
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/common"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/signing"
+	"go.uber.org/zap"
+)
+
+// MaxSignedBodySize bounds the body VerifySignature reads into memory to hash, with room for the
+// largest transfer batch upload and its multipart framing
+const MaxSignedBodySize = 11 << 20
+
+// RequestSignature checks the signatures partner systems sign their requests with
+type RequestSignature struct {
+	service interface_service.RequestSigningService
+}
+
+// NewRequestSignature creates a new instance of RequestSignature checking signatures with service
+func NewRequestSignature(service interface_service.RequestSigningService) *RequestSignature {
+	return &RequestSignature{service: service}
+}
+
+// VerifySignature is a middleware function that checks the signature of requests sent with an API
+// key requiring signed requests, and of every other request carrying an X-Signature header. It runs
+// after ValidateAPIKey, requests that did not need an API key are let through.
+func (rs *RequestSignature) VerifySignature() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(apiKeyContextKey)
+		if !ok {
+			c.Next()
+			return
+		}
+		key := value.(db.ApiKey)
+
+		signature := c.GetHeader(signing.SignatureHeader)
+		if signature == "" {
+			if key.SigningRequired {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": common.ErrSignatureRequired.Error(),
+					"code":  "MISSING_SIGNATURE",
+				})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxSignedBodySize)
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+					"error": common.ErrSignedBodyTooLarge.Error(),
+					"code":  "BODY_TOO_LARGE",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		//? Handlers read the body again after it was hashed
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		err = rs.service.Verify(c, key, signing.Request{
+			Method:    c.Request.Method,
+			Path:      signing.RequestPath(c.Request.URL),
+			Timestamp: c.GetHeader(signing.TimestampHeader),
+			Nonce:     c.GetHeader(signing.NonceHeader),
+			Body:      body,
+		}, signature)
+		if err != nil {
+			code := ""
+			switch {
+			case errors.Is(err, signing.ErrInvalidSignature):
+				code = "INVALID_SIGNATURE"
+			case errors.Is(err, signing.ErrInvalidTimestamp):
+				code = "INVALID_SIGNATURE_TIMESTAMP"
+			case errors.Is(err, signing.ErrInvalidNonce):
+				code = "INVALID_SIGNATURE_NONCE"
+			case errors.Is(err, common.ErrReplayedNonce):
+				code = "REPLAYED_NONCE"
+			case errors.Is(err, common.ErrRequestSigningDisabled):
+				code = "SIGNING_DISABLED"
+			default:
+				logger.GetLogger().Error("Failed to verify request signature", zap.Error(err))
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to verify request signature"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+				"code":  code,
+			})
+			return
+		}
+		c.Next()
+	}
+}
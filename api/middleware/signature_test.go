@@ -0,0 +1,68 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/riad/banksystemendtoend/api/middleware"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/signing"
+	"github.com/stretchr/testify/require"
+)
+
+// acceptingSigningService accepts every signature and remembers the body it was asked to check
+type acceptingSigningService struct {
+	body []byte
+}
+
+func (s *acceptingSigningService) Verify(_ context.Context, _ db.ApiKey, req signing.Request, _ string) error {
+	s.body = req.Body
+	return nil
+}
+
+func TestVerifySignatureBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := &acceptingSigningService{}
+	var received []byte
+	router := gin.New()
+	router.POST("/transfers",
+		//? Stands in for ValidateAPIKey, which leaves the key where VerifySignature looks for it
+		func(c *gin.Context) {
+			c.Set("api_key", db.ApiKey{ApiKeyID: 1, SigningRequired: true})
+		},
+		middleware.NewRequestSignature(service).VerifySignature(),
+		func(c *gin.Context) {
+			var err error
+			received, err = io.ReadAll(c.Request.Body)
+			require.NoError(t, err)
+			c.Status(http.StatusOK)
+		},
+	)
+
+	send := func(body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(body))
+		req.Header.Set(signing.SignatureHeader, "00")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	body := []byte(`{"amount":"10.00"}`)
+	rec := send(body)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, body, service.body)
+	require.Equal(t, body, received)
+
+	//? Bodies over the limit are refused before they are hashed
+	service.body = nil
+	rec = send(bytes.Repeat([]byte("a"), middleware.MaxSignedBodySize+1))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	require.Contains(t, rec.Body.String(), "BODY_TOO_LARGE")
+	require.Nil(t, service.body)
+}
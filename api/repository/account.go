@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// accountRepository reads accounts straight from the store; balances change on every
+// transfer, so account rows are intentionally not cached.
+type accountRepository struct {
+	store db.Store
+}
+
+func NewAccountRepository(store db.Store) interface_repository.AccountRepository {
+	return &accountRepository{store: store}
+}
+
+func (r *accountRepository) CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+	return r.store.CreateAccount(ctx, arg)
+}
+
+func (r *accountRepository) GetAccount(ctx context.Context, accountID int64) (db.Account, error) {
+	return r.store.GetAccount(ctx, int32(accountID))
+}
+
+func (r *accountRepository) GetAccountByNumber(ctx context.Context, accountNumber string) (db.Account, error) {
+	return r.store.GetAccountByNumber(ctx, accountNumber)
+}
+
+func (r *accountRepository) ListAccountsByUser(ctx context.Context, userID int64) ([]db.Account, error) {
+	return r.store.ListAccountsByUser(ctx, int32(userID))
+}
+
+func (r *accountRepository) CloseAccount(ctx context.Context, accountID int64) (bool, error) {
+	closed, err := r.store.CloseAccount(ctx, int32(accountID))
+	return closed > 0, err
+}
+
+func (r *accountRepository) HardDeleteAccount(ctx context.Context, accountID int64) error {
+	return r.store.HardDeleteAccount(ctx, int32(accountID))
+}
+
+func (r *accountRepository) UpdateAccountTerms(ctx context.Context, arg db.UpdateAccountTermsParams) (db.Account, error) {
+	return r.store.UpdateAccountTerms(ctx, arg)
+}
+
+func (r *accountRepository) HasTransferredTo(ctx context.Context, userID, accountID int64) (bool, error) {
+	return r.store.HasTransferredTo(ctx, db.HasTransferredToParams{UserID: int32(userID), ToAccountID: int32(accountID)})
+}
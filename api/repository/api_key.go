@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/pkg/cache"
+)
+
+// apiKeyCacheTTL bounds how long a key stays usable from the cache should an invalidation be missed
+const apiKeyCacheTTL = 5 * time.Minute
+
+// apiKeyRepository caches keys by prefix, every request carries one. Revoking or rotating a key drops
+// its cached copy so it stops working right away.
+type apiKeyRepository struct {
+	store     db.Store
+	cacheable *CacheableRepository
+}
+
+func NewAPIKeyRepository(store db.Store, cacheService *cache.Service) interface_repository.APIKeyRepository {
+	//? Create a dedicated cache service for API keys
+	apiKeyCache := cache.NewService(
+		cacheService.GetRedisClient(),
+		"api_key",
+		apiKeyCacheTTL,
+	)
+
+	return &apiKeyRepository{
+		store:     store,
+		cacheable: NewCacheableRepository(apiKeyCache),
+	}
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+	return r.store.CreateAPIKey(ctx, arg)
+}
+
+func (r *apiKeyRepository) GetAPIKey(ctx context.Context, apiKeyID int32) (db.ApiKey, error) {
+	return r.store.GetAPIKey(ctx, apiKeyID)
+}
+
+func (r *apiKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (db.ApiKey, error) {
+	var result db.ApiKey
+
+	err := r.cacheable.GetCached(ctx, prefix, &result, func() (interface{}, error) {
+		return r.store.GetAPIKeyByPrefix(ctx, prefix)
+	})
+	return result, err
+}
+
+func (r *apiKeyRepository) ListAPIKeys(ctx context.Context, arg db.ListAPIKeysParams) ([]db.ApiKey, error) {
+	return r.store.ListAPIKeys(ctx, arg)
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, apiKeyID int32) (db.ApiKey, error) {
+	result, err := r.store.RevokeAPIKey(ctx, apiKeyID)
+	if err != nil {
+		return db.ApiKey{}, err
+	}
+	r.InvalidateAPIKey(ctx, result.KeyPrefix)
+	return result, nil
+}
+
+func (r *apiKeyRepository) TouchAPIKey(ctx context.Context, key db.ApiKey, usedAt time.Time) error {
+	err := r.store.TouchAPIKey(ctx, db.TouchAPIKeyParams{
+		LastUsedAt: sql.NullTime{Time: usedAt, Valid: true},
+		ApiKeyID:   key.ApiKeyID,
+	})
+	if err != nil {
+		return err
+	}
+	r.InvalidateAPIKey(ctx, key.KeyPrefix)
+	return nil
+}
+
+func (r *apiKeyRepository) InvalidateAPIKey(ctx context.Context, prefix string) {
+	r.cacheable.InvalidateCache(ctx, prefix)
+}
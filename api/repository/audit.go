@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// auditRepository reads the audit trail straight from the store, it grows with every change
+type auditRepository struct {
+	store db.Store
+}
+
+func NewAuditRepository(store db.Store) interface_repository.AuditRepository {
+	return &auditRepository{store: store}
+}
+
+func (r *auditRepository) GetAuditTrail(ctx context.Context, auditID int32) (db.AuditTrail, error) {
+	return r.store.GetAuditTrail(ctx, auditID)
+}
+
+func (r *auditRepository) ListAuditTrail(ctx context.Context, arg db.ListAuditTrailParams) ([]db.AuditTrail, error) {
+	return r.store.ListAuditTrail(ctx, arg)
+}
+
+func (r *auditRepository) CreateAccessDeniedAudit(ctx context.Context, arg db.CreateAccessDeniedAuditParams) error {
+	return r.store.CreateAccessDeniedAudit(ctx, arg)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/riad/banksystemendtoend/apperrors"
 	"github.com/riad/banksystemendtoend/pkg/cache"
 	logger "github.com/riad/banksystemendtoend/pkg/log"
 	"go.uber.org/zap"
@@ -25,7 +24,8 @@ func (r *CacheableRepository) GetCached(ctx context.Context, key string, target
 	//!Cache Side
 	err := r.cacheService.Get(ctx, key, target)
 
-	if err != nil && !apperrors.IsRedisError(err) {
+	//? A hit is served from the cache, misses and Redis failures fall back to the getter
+	if err == nil {
 		return nil
 	}
 
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/pkg/cache"
+)
+
+// currencyCacheTTL is kept short because the exchange rate job updates rates behind the cache
+const currencyCacheTTL = 5 * time.Minute
+
+type currencyRepository struct {
+	store     db.Store
+	cacheable *CacheableRepository
+}
+
+func NewCurrencyRepository(store db.Store, cacheService *cache.Service) interface_repository.CurrencyRepository {
+	//? Create a dedicated cache service for currencies
+	currencyCache := cache.NewService(
+		cacheService.GetRedisClient(),
+		"currency",
+		currencyCacheTTL,
+	)
+
+	return &currencyRepository{
+		store:     store,
+		cacheable: NewCacheableRepository(currencyCache),
+	}
+}
+
+func (r *currencyRepository) CreateCurrency(ctx context.Context, arg db.CreateCurrencyParams) (db.AccountCurrency, error) {
+	result, err := r.store.CreateCurrency(ctx, arg)
+	if err != nil {
+		return db.AccountCurrency{}, err
+	}
+	r.cacheable.InvalidateCache(ctx, "")
+	return result, nil
+}
+
+func (r *currencyRepository) GetCurrency(ctx context.Context, currencyCode string) (db.AccountCurrency, error) {
+	var result db.AccountCurrency
+
+	err := r.cacheable.GetCached(ctx, currencyCode, &result, func() (interface{}, error) {
+		return r.store.GetCurrency(ctx, currencyCode)
+	})
+	return result, err
+}
+
+func (r *currencyRepository) ListCurrencies(ctx context.Context, includeInactive bool) ([]db.AccountCurrency, error) {
+	var result []db.AccountCurrency
+
+	key := "list_active"
+	if includeInactive {
+		key = "list_all"
+	}
+	err := r.cacheable.GetCached(ctx, key, &result, func() (interface{}, error) {
+		if includeInactive {
+			return r.store.ListAllCurrencies(ctx)
+		}
+		return r.store.ListCurrencies(ctx)
+	})
+	return result, err
+}
+
+func (r *currencyRepository) UpdateCurrency(ctx context.Context, arg db.UpdateCurrencyParams) (db.AccountCurrency, error) {
+	result, err := r.store.UpdateCurrency(ctx, arg)
+	if err != nil {
+		return db.AccountCurrency{}, err
+	}
+	r.cacheable.InvalidateCache(ctx, "")
+	return result, nil
+}
+
+func (r *currencyRepository) UpdateExchangeRate(ctx context.Context, arg db.UpdateExchangeRateParams) (db.AccountCurrency, error) {
+	result, err := r.store.UpdateExchangeRate(ctx, arg)
+	if err != nil {
+		return db.AccountCurrency{}, err
+	}
+	r.cacheable.InvalidateCache(ctx, "")
+	return result, nil
+}
+
+func (r *currencyRepository) CountAccountsByCurrency(ctx context.Context, currencyCode string) (int64, error) {
+	return r.store.CountAccountsByCurrency(ctx, currencyCode)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// exchangeRateRepository reads the rate history straight from the store, it is appended to by the
+// exchange rate job
+type exchangeRateRepository struct {
+	store db.Store
+}
+
+func NewExchangeRateRepository(store db.Store) interface_repository.ExchangeRateRepository {
+	return &exchangeRateRepository{store: store}
+}
+
+func (r *exchangeRateRepository) GetCurrency(ctx context.Context, currencyCode string) (db.AccountCurrency, error) {
+	return r.store.GetCurrency(ctx, currencyCode)
+}
+
+func (r *exchangeRateRepository) ListExchangeRateHistory(ctx context.Context, arg db.ListExchangeRateHistoryParams) ([]db.ExchangeRateHistory, error) {
+	return r.store.ListExchangeRateHistory(ctx, arg)
+}
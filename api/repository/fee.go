@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// feeRepository reads schedules and income accounts straight from the store, transfers read them
+// inside their own database transaction so a cached copy would only disagree with them
+type feeRepository struct {
+	store db.Store
+}
+
+func NewFeeRepository(store db.Store) interface_repository.FeeRepository {
+	return &feeRepository{store: store}
+}
+
+func (r *feeRepository) UpsertFeeSchedule(ctx context.Context, arg db.UpsertFeeScheduleParams) (db.FeeSchedule, error) {
+	return r.store.UpsertFeeSchedule(ctx, arg)
+}
+
+func (r *feeRepository) GetFeeSchedule(ctx context.Context, accountType string) (db.FeeSchedule, error) {
+	return r.store.GetFeeSchedule(ctx, accountType)
+}
+
+func (r *feeRepository) ListFeeSchedules(ctx context.Context) ([]db.FeeSchedule, error) {
+	return r.store.ListFeeSchedules(ctx)
+}
+
+func (r *feeRepository) UpsertFeeIncomeAccount(ctx context.Context,
+	arg db.UpsertFeeIncomeAccountParams) (db.FeeIncomeAccount, error) {
+	return r.store.UpsertFeeIncomeAccount(ctx, arg)
+}
+
+func (r *feeRepository) ListFeeIncomeAccounts(ctx context.Context) ([]db.FeeIncomeAccount, error) {
+	return r.store.ListFeeIncomeAccounts(ctx)
+}
+
+func (r *feeRepository) ListFeeChargesByAccount(ctx context.Context,
+	arg db.ListFeeChargesByAccountParams) ([]db.FeeCharge, error) {
+	return r.store.ListFeeChargesByAccount(ctx, arg)
+}
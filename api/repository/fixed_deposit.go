@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// fixedDepositRepository reads deposits straight from the store, the maturity job changes them in the background
+type fixedDepositRepository struct {
+	store db.Store
+}
+
+func NewFixedDepositRepository(store db.Store) interface_repository.FixedDepositRepository {
+	return &fixedDepositRepository{store: store}
+}
+
+func (r *fixedDepositRepository) GetFixedDeposit(ctx context.Context, depositNumber uuid.UUID) (db.FixedDeposit, error) {
+	return r.store.GetFixedDeposit(ctx, depositNumber)
+}
+
+func (r *fixedDepositRepository) ListFixedDepositsByLinkedAccount(ctx context.Context,
+	arg db.ListFixedDepositsByLinkedAccountParams) ([]db.FixedDeposit, error) {
+	return r.store.ListFixedDepositsByLinkedAccount(ctx, arg)
+}
+
+func (r *fixedDepositRepository) UpsertFixedDepositTerm(ctx context.Context,
+	arg db.UpsertFixedDepositTermParams) (db.FixedDepositTerm, error) {
+	return r.store.UpsertFixedDepositTerm(ctx, arg)
+}
+
+func (r *fixedDepositRepository) ListFixedDepositTerms(ctx context.Context) ([]db.FixedDepositTerm, error) {
+	return r.store.ListFixedDepositTerms(ctx)
+}
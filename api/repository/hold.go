@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// holdRepository reads holds straight from the store, their status changes with every capture or release
+type holdRepository struct {
+	store db.Store
+}
+
+func NewHoldRepository(store db.Store) interface_repository.HoldRepository {
+	return &holdRepository{store: store}
+}
+
+func (r *holdRepository) GetHold(ctx context.Context, holdNumber uuid.UUID) (db.Hold, error) {
+	return r.store.GetHold(ctx, holdNumber)
+}
+
+func (r *holdRepository) ListOpenHoldsByAccount(ctx context.Context, accountID int64) ([]db.Hold, error) {
+	return r.store.ListOpenHoldsByAccount(ctx, int32(accountID))
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/pkg/cache"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+type idempotencyRepository struct {
+	store db.Store
+	cache *cache.Service
+}
+
+func NewIdempotencyRepository(store db.Store, cacheService *cache.Service) interface_repository.IdempotencyRepository {
+	//? Create a dedicated cache service for idempotency keys
+	idempotencyCache := cache.NewService(
+		cacheService.GetRedisClient(),
+		"idempotency",
+		cacheService.GetDefaultTTL(),
+	)
+
+	return &idempotencyRepository{
+		store: store,
+		cache: idempotencyCache,
+	}
+}
+
+func (r *idempotencyRepository) CreateIdempotencyKey(ctx context.Context, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	return r.store.CreateIdempotencyKey(ctx, arg)
+}
+
+// GetCachedIdempotencyKey only looks at the cache. Only completed keys are cached, pending ones must
+// always be read from Postgres.
+func (r *idempotencyRepository) GetCachedIdempotencyKey(ctx context.Context, clientID, key string) (db.IdempotencyKey, bool) {
+	var result db.IdempotencyKey
+	if err := r.cache.Get(ctx, idempotencyCacheKey(clientID, key), &result); err != nil {
+		return db.IdempotencyKey{}, false
+	}
+	return result, true
+}
+
+func (r *idempotencyRepository) GetIdempotencyKey(ctx context.Context, clientID, key string) (db.IdempotencyKey, error) {
+	return r.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		ClientID:       clientID,
+		IdempotencyKey: key,
+	})
+}
+
+func (r *idempotencyRepository) CompleteIdempotencyKey(ctx context.Context, arg db.CompleteIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	result, err := r.store.CompleteIdempotencyKey(ctx, arg)
+	if err != nil {
+		return db.IdempotencyKey{}, err
+	}
+
+	ttl := time.Until(result.ExpiresAt)
+	if ttl > 0 {
+		if err := r.cache.Set(ctx, idempotencyCacheKey(arg.ClientID, arg.IdempotencyKey), result, ttl); err != nil {
+			logger.GetLogger().Error("failed to cache idempotency key", zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+func (r *idempotencyRepository) DeleteIdempotencyKey(ctx context.Context, clientID, key string) error {
+	err := r.store.DeleteIdempotencyKey(ctx, db.DeleteIdempotencyKeyParams{
+		ClientID:       clientID,
+		IdempotencyKey: key,
+	})
+	if err != nil {
+		return err
+	}
+	r.cache.Delete(ctx, idempotencyCacheKey(clientID, key))
+	return nil
+}
+
+func (r *idempotencyRepository) DeleteStaleIdempotencyKey(ctx context.Context, clientID, key string, createdBefore time.Time) (bool, error) {
+	deleted, err := r.store.DeleteStaleIdempotencyKey(ctx, db.DeleteStaleIdempotencyKeyParams{
+		ClientID:       clientID,
+		IdempotencyKey: key,
+		CreatedBefore:  createdBefore,
+	})
+	return deleted > 0, err
+}
+
+func idempotencyCacheKey(clientID, key string) string {
+	return fmt.Sprintf("%s:%s", clientID, key)
+}
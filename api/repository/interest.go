@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// interestRepository reads settings and accruals straight from the store, the interest job reads
+// the settings from the database as well so a cached copy would only disagree with it
+type interestRepository struct {
+	store db.Store
+}
+
+func NewInterestRepository(store db.Store) interface_repository.InterestRepository {
+	return &interestRepository{store: store}
+}
+
+func (r *interestRepository) UpsertInterestSettings(ctx context.Context,
+	arg db.UpsertInterestSettingsParams) (db.InterestSetting, error) {
+	return r.store.UpsertInterestSettings(ctx, arg)
+}
+
+func (r *interestRepository) GetInterestSettings(ctx context.Context, accountType string) (db.InterestSetting, error) {
+	return r.store.GetInterestSettings(ctx, accountType)
+}
+
+func (r *interestRepository) ListInterestSettings(ctx context.Context) ([]db.InterestSetting, error) {
+	return r.store.ListInterestSettings(ctx)
+}
+
+func (r *interestRepository) ListInterestAccruals(ctx context.Context,
+	arg db.ListInterestAccrualsParams) ([]db.InterestAccrual, error) {
+	return r.store.ListInterestAccruals(ctx, arg)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	"github.com/riad/banksystemendtoend/pkg/cache"
+)
+
+// nonceRepository keeps nonces in Redis only, they are worthless once the clock skew window has passed
+type nonceRepository struct {
+	cache *cache.Service
+}
+
+func NewNonceRepository(cacheService *cache.Service) interface_repository.NonceRepository {
+	//? Create a dedicated cache service for request signature nonces
+	nonceCache := cache.NewService(
+		cacheService.GetRedisClient(),
+		"signature_nonce",
+		cacheService.GetDefaultTTL(),
+	)
+
+	return &nonceRepository{cache: nonceCache}
+}
+
+func (r *nonceRepository) ClaimNonce(ctx context.Context, keyPrefix, nonce string, ttl time.Duration) (bool, error) {
+	return r.cache.SetIfAbsent(ctx, keyPrefix+":"+nonce, true, ttl)
+}
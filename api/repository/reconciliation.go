@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// reconciliationRepository reads runs straight from the store, a run changes until it completes
+type reconciliationRepository struct {
+	store db.Store
+}
+
+func NewReconciliationRepository(store db.Store) interface_repository.ReconciliationRepository {
+	return &reconciliationRepository{store: store}
+}
+
+func (r *reconciliationRepository) GetReconciliationRun(ctx context.Context, runNumber uuid.UUID) (db.ReconciliationRun, error) {
+	return r.store.GetReconciliationRun(ctx, runNumber)
+}
+
+func (r *reconciliationRepository) ListReconciliationRuns(ctx context.Context,
+	arg db.ListReconciliationRunsParams) ([]db.ReconciliationRun, error) {
+	return r.store.ListReconciliationRuns(ctx, arg)
+}
+
+func (r *reconciliationRepository) ListLedgerDiscrepancies(ctx context.Context,
+	arg db.ListLedgerDiscrepanciesParams) ([]db.LedgerDiscrepancy, error) {
+	return r.store.ListLedgerDiscrepancies(ctx, arg)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// refreshTokenRepository is not cached, a revoked token must stop working right away
+type refreshTokenRepository struct {
+	store db.Store
+}
+
+func NewRefreshTokenRepository(store db.Store) interface_repository.RefreshTokenRepository {
+	return &refreshTokenRepository{store: store}
+}
+
+func (r *refreshTokenRepository) CreateRefreshToken(ctx context.Context, arg db.CreateRefreshTokenParams) (db.RefreshToken, error) {
+	return r.store.CreateRefreshToken(ctx, arg)
+}
+
+func (r *refreshTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (db.RefreshToken, error) {
+	return r.store.GetRefreshToken(ctx, tokenHash)
+}
+
+func (r *refreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := r.store.RevokeRefreshTokenFamily(ctx, familyID)
+	return err
+}
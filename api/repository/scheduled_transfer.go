@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// scheduledTransferRepository reads scheduled transfers straight from the store, the scheduler
+// updates them in the background
+type scheduledTransferRepository struct {
+	store db.Store
+}
+
+func NewScheduledTransferRepository(store db.Store) interface_repository.ScheduledTransferRepository {
+	return &scheduledTransferRepository{store: store}
+}
+
+func (r *scheduledTransferRepository) CreateScheduledTransfer(ctx context.Context,
+	arg db.CreateScheduledTransferParams) (db.ScheduledTransfer, error) {
+	return r.store.CreateScheduledTransfer(ctx, arg)
+}
+
+func (r *scheduledTransferRepository) GetScheduledTransfer(ctx context.Context, scheduleNumber uuid.UUID) (db.ScheduledTransfer, error) {
+	return r.store.GetScheduledTransfer(ctx, scheduleNumber)
+}
+
+func (r *scheduledTransferRepository) ListScheduledTransfersByAccount(ctx context.Context, accountID int64) ([]db.ScheduledTransfer, error) {
+	return r.store.ListScheduledTransfersByAccount(ctx, int32(accountID))
+}
+
+func (r *scheduledTransferRepository) ListScheduledTransferExecutions(ctx context.Context,
+	arg db.ListScheduledTransferExecutionsParams) ([]db.ScheduledTransferExecution, error) {
+	return r.store.ListScheduledTransferExecutions(ctx, arg)
+}
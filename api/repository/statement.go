@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// statementRepository reads statements straight from the store, the statement job updates them in the background
+type statementRepository struct {
+	store db.Store
+}
+
+func NewStatementRepository(store db.Store) interface_repository.StatementRepository {
+	return &statementRepository{store: store}
+}
+
+func (r *statementRepository) CreateStatement(ctx context.Context, arg db.CreateStatementParams) (db.Statement, error) {
+	return r.store.CreateStatement(ctx, arg)
+}
+
+func (r *statementRepository) GetStatement(ctx context.Context, statementNumber uuid.UUID) (db.Statement, error) {
+	return r.store.GetStatement(ctx, statementNumber)
+}
+
+func (r *statementRepository) ListStatementsByAccount(ctx context.Context,
+	arg db.ListStatementsByAccountParams) ([]db.Statement, error) {
+	return r.store.ListStatementsByAccount(ctx, arg)
+}
+
+func (r *statementRepository) ClaimPendingStatements(ctx context.Context,
+	arg db.ClaimPendingStatementsParams) ([]db.Statement, error) {
+	return r.store.ClaimPendingStatements(ctx, arg)
+}
+
+func (r *statementRepository) CompleteStatement(ctx context.Context, arg db.CompleteStatementParams) (db.Statement, error) {
+	return r.store.CompleteStatement(ctx, arg)
+}
+
+func (r *statementRepository) FailStatement(ctx context.Context, arg db.FailStatementParams) (db.Statement, error) {
+	return r.store.FailStatement(ctx, arg)
+}
+
+func (r *statementRepository) CountStatementEntries(ctx context.Context, arg db.CountStatementEntriesParams) (int64, error) {
+	return r.store.CountStatementEntries(ctx, arg)
+}
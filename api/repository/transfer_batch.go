@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// transferBatchRepository reads batches straight from the store, their progress changes while the
+// batch worker runs so nothing is cached
+type transferBatchRepository struct {
+	store db.Store
+}
+
+func NewTransferBatchRepository(store db.Store) interface_repository.TransferBatchRepository {
+	return &transferBatchRepository{store: store}
+}
+
+func (r *transferBatchRepository) GetTransferBatch(ctx context.Context, batchNumber uuid.UUID) (db.TransferBatch, error) {
+	return r.store.GetTransferBatch(ctx, batchNumber)
+}
+
+func (r *transferBatchRepository) ListTransferBatchItems(ctx context.Context, batchID int32) ([]db.TransferBatchItem, error) {
+	return r.store.ListTransferBatchItems(ctx, batchID)
+}
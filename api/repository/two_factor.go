@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// twoFactorRepository is not cached, a used code or recovery code must stop working right away
+type twoFactorRepository struct {
+	store db.Store
+}
+
+func NewTwoFactorRepository(store db.Store) interface_repository.TwoFactorRepository {
+	return &twoFactorRepository{store: store}
+}
+
+func (r *twoFactorRepository) StartTwoFactorEnrollment(ctx context.Context, arg db.StartTwoFactorEnrollmentParams) (db.UserTwoFactor, error) {
+	return r.store.StartTwoFactorEnrollment(ctx, arg)
+}
+
+func (r *twoFactorRepository) GetTwoFactor(ctx context.Context, userID int64) (db.UserTwoFactor, error) {
+	return r.store.GetTwoFactor(ctx, int32(userID))
+}
+
+func (r *twoFactorRepository) EnableTwoFactor(ctx context.Context, arg db.EnableTwoFactorParams) (db.UserTwoFactor, error) {
+	return r.store.EnableTwoFactor(ctx, arg)
+}
+
+func (r *twoFactorRepository) UseTwoFactorStep(ctx context.Context, arg db.UseTwoFactorStepParams) (db.UserTwoFactor, error) {
+	return r.store.UseTwoFactorStep(ctx, arg)
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, arg db.UseRecoveryCodeParams) (db.UserTwoFactor, error) {
+	return r.store.UseRecoveryCode(ctx, arg)
+}
+
+func (r *twoFactorRepository) RecordTwoFactorFailure(ctx context.Context, arg db.RecordTwoFactorFailureParams) (db.UserTwoFactor, error) {
+	return r.store.RecordTwoFactorFailure(ctx, arg)
+}
+
+func (r *twoFactorRepository) DeleteTwoFactor(ctx context.Context, userID int64) error {
+	return r.store.DeleteTwoFactor(ctx, int32(userID))
+}
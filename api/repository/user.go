@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/pkg/cache"
+)
+
+type userRepository struct {
+	store     db.Store
+	cacheable *CacheableRepository
+}
+
+func NewUserRepository(store db.Store, cacheService *cache.Service) interface_repository.UserRepository {
+	//? Create a dedicated cache service for users
+	userCache := cache.NewService(
+		cacheService.GetRedisClient(),
+		"user",
+		cacheService.GetDefaultTTL(),
+	)
+
+	return &userRepository{
+		store:     store,
+		cacheable: NewCacheableRepository(userCache),
+	}
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+	return r.store.CreateUser(ctx, arg)
+}
+
+func (r *userRepository) GetUser(ctx context.Context, userID int64) (db.User, error) {
+	var result db.User
+
+	err := r.cacheable.GetCached(ctx, userCacheKey(userID), &result, func() (interface{}, error) {
+		return r.store.GetUser(ctx, int32(userID))
+	})
+	return result, err
+}
+
+// GetUserByUsername is not cached, logins must see password and status changes right away
+func (r *userRepository) GetUserByUsername(ctx context.Context, username string) (db.User, error) {
+	return r.store.GetUserByUsername(ctx, username)
+}
+
+func (r *userRepository) ListUsers(ctx context.Context, limit, offset int32) ([]db.User, error) {
+	return r.store.ListUsers(ctx, db.ListUsersParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func (r *userRepository) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
+	result, err := r.store.UpdateUser(ctx, arg)
+	if err != nil {
+		return db.User{}, err
+	}
+	// Invalidate the cache
+	r.cacheable.InvalidateCache(ctx, userCacheKey(int64(arg.UserID)))
+	return result, nil
+}
+
+func (r *userRepository) DeleteUser(ctx context.Context, userID int64) error {
+	if err := r.store.DeleteUser(ctx, int32(userID)); err != nil {
+		return err
+	}
+	// Invalidate the cache
+	r.cacheable.InvalidateCache(ctx, userCacheKey(userID))
+	return nil
+}
+
+func (r *userRepository) HardDeleteUser(ctx context.Context, userID int64) error {
+	if err := r.store.HardDeleteUser(ctx, int32(userID)); err != nil {
+		return err
+	}
+	// Invalidate the cache
+	r.cacheable.InvalidateCache(ctx, userCacheKey(userID))
+	return nil
+}
+
+func (r *userRepository) UpdateLastLogin(ctx context.Context, userID int64, time time.Time) error {
+	if err := r.store.UpdateLastLogin(ctx, db.UpdateLastLoginParams{
+		LastLogin: time,
+		UserID:    int32(userID),
+	}); err != nil {
+		return err
+	}
+	// Invalidate the cache
+	r.cacheable.InvalidateCache(ctx, userCacheKey(userID))
+	return nil
+}
+
+// GetUserAccess is not cached, a changed role or a deactivated user must apply to the next request
+func (r *userRepository) GetUserAccess(ctx context.Context, userID int64) (db.GetUserAccessRow, error) {
+	return r.store.GetUserAccess(ctx, int32(userID))
+}
+
+func (r *userRepository) UpdateUserRole(ctx context.Context, userID int64, role db.UserRole) (db.User, error) {
+	result, err := r.store.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Role:   role,
+		UserID: int32(userID),
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+	// Invalidate the cache
+	r.cacheable.InvalidateCache(ctx, userCacheKey(userID))
+	return result, nil
+}
+
+// userCacheKey builds the cache key of a single user
+func userCacheKey(userID int64) string {
+	return fmt.Sprintf("%d", userID)
+}
@@ -2,15 +2,22 @@ package api
 
 import (
 	"net/http"
+	"reflect"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/riad/banksystemendtoend/api/dependency"
 	"github.com/riad/banksystemendtoend/api/middleware"
 	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/pkg/jobs"
 	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/pkg/metrics"
 	cache_setup "github.com/riad/banksystemendtoend/util/cache"
 	db_setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/rbac"
 	"go.uber.org/zap"
 )
 
@@ -51,33 +58,135 @@ func NewServer() (*Server, error) {
 // setupRouter configures all the API routes and middleware
 func (s *Server) setupRouter() {
 	router := gin.Default()
-
-	apiKey, err := middleware.NewAPIKey()
-
-	if err != nil {
-		zap.L().Fatal("Failed to create API key", zap.Error(err))
-	}
+	registerValidators()
 
 	router.Use(middleware.Cors())
 	router.Use(middleware.TimeOut(3 * time.Minute))
-	router.Use(apiKey.ValidateAPIKey())
+	router.Use(s.dependencies.ValidateAPIKey())
+	router.Use(s.dependencies.VerifySignature())
+	router.Use(middleware.AuditActor())
 
 	// API v1 group
 	v1 := router.Group("/api/v1")
 	{
-		//Account Routes
+		// Auth Routes - login and access token refresh
+		auth := v1.Group("/auth")
+		for _, route := range s.dependencies.GetRouteHandlers("auth") {
+			auth.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Account Routes - dynamically register from dependency container
 		accounts := v1.Group("/accounts")
-		{
-			accounts.GET("", s.listAccounts)
+		for _, route := range s.dependencies.GetRouteHandlers("accounts") {
+			accounts.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// User Routes - dynamically register from dependency container
+		users := v1.Group("/users")
+		for _, route := range s.dependencies.GetRouteHandlers("users") {
+			users.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Onboarding Routes - user and first account in one transaction
+		onboarding := v1.Group("/onboarding")
+		for _, route := range s.dependencies.GetRouteHandlers("onboarding") {
+			onboarding.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Transfer Routes - dynamically register from dependency container
+		transfers := v1.Group("/transfers")
+		for _, route := range s.dependencies.GetRouteHandlers("transfers") {
+			transfers.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Transaction Routes - dynamically register from dependency container
+		transactions := v1.Group("/transactions")
+		for _, route := range s.dependencies.GetRouteHandlers("transactions") {
+			transactions.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Hold Routes - dynamically register from dependency container
+		holds := v1.Group("/holds")
+		for _, route := range s.dependencies.GetRouteHandlers("holds") {
+			holds.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Scheduled Transfer Routes - dynamically register from dependency container
+		scheduledTransfers := v1.Group("/scheduled-transfers")
+		for _, route := range s.dependencies.GetRouteHandlers("scheduled-transfers") {
+			scheduledTransfers.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Batch Transfer Routes - dynamically register from dependency container
+		batchTransfers := v1.Group("/batch-transfers")
+		for _, route := range s.dependencies.GetRouteHandlers("batch-transfers") {
+			batchTransfers.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Reconciliation Routes - dynamically register from dependency container
+		reconciliation := v1.Group("/reconciliation")
+		for _, route := range s.dependencies.GetRouteHandlers("reconciliation") {
+			reconciliation.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Interest Routes - dynamically register from dependency container
+		interestRoutes := v1.Group("/interest")
+		for _, route := range s.dependencies.GetRouteHandlers("interest") {
+			interestRoutes.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Fee Routes - dynamically register from dependency container
+		feeRoutes := v1.Group("/fees")
+		for _, route := range s.dependencies.GetRouteHandlers("fees") {
+			feeRoutes.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Fixed Deposit Routes - dynamically register from dependency container
+		fixedDeposits := v1.Group("/fixed-deposits")
+		for _, route := range s.dependencies.GetRouteHandlers("fixed-deposits") {
+			fixedDeposits.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Statement Routes - dynamically register from dependency container
+		statements := v1.Group("/statements")
+		for _, route := range s.dependencies.GetRouteHandlers("statements") {
+			statements.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Audit Trail Routes - dynamically register from dependency container
+		auditTrail := v1.Group("/audit-trail")
+		for _, route := range s.dependencies.GetRouteHandlers("audit-trail") {
+			auditTrail.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Exchange Rate Routes - dynamically register from dependency container
+		exchangeRates := v1.Group("/exchange-rates")
+		for _, route := range s.dependencies.GetRouteHandlers("exchange-rates") {
+			exchangeRates.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// Currency Routes - dynamically register from dependency container
+		currencies := v1.Group("/currencies")
+		for _, route := range s.dependencies.GetRouteHandlers("currencies") {
+			currencies.Handle(route.Method, route.Path, route.Handlers()...)
+		}
+
+		// API Key Routes - issued, rotated and revoked by administrators
+		apiKeys := v1.Group("/api-keys")
+		for _, route := range s.dependencies.GetRouteHandlers("api-keys") {
+			apiKeys.Handle(route.Method, route.Path, route.Handlers()...)
 		}
 
 		// Account Type Routes - dynamically register from dependency container
 		accountTypes := v1.Group("/account-types")
 		for _, route := range s.dependencies.GetRouteHandlers("account-types") {
-			accountTypes.Handle(route.Method, route.Path, route.HandlerFunc)
+			accountTypes.Handle(route.Method, route.Path, route.Handlers()...)
 		}
 	}
 
+	// Ledger metrics, they include balance drift so only roles reading the ledger may see them
+	router.GET("/metrics", s.dependencies.RequirePermission(rbac.LedgerRead), metrics.Handler())
+
 	//Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		cacheService := s.dependencies.GetCacheService()
@@ -90,7 +199,7 @@ func (s *Server) setupRouter() {
 			is_connect := cacheService.CheckRedisConnection()
 			if !is_connect {
 				redisStatus = "down"
-				logger.GetLogger().Error("Failed to connect Redis", zap.Error(err))
+				logger.GetLogger().Error("Failed to connect Redis")
 			}
 		} else {
 			redisStatus = "not_configured"
@@ -98,7 +207,7 @@ func (s *Server) setupRouter() {
 		}
 
 		// Database connection test
-		err = db_setup.CheckDBHealth(c.Request.Context(), store)
+		err := db_setup.CheckDBHealth(c.Request.Context(), store)
 		if err != nil {
 			dbStatus = "down"
 			logger.GetLogger().Error("Failed to connect DB", zap.Error(err))
@@ -109,7 +218,22 @@ func (s *Server) setupRouter() {
 	s.router = router
 }
 
+// BackgroundJobs returns the jobs the API features need running alongside the server
+func (s *Server) BackgroundJobs() []jobs.Job {
+	return s.dependencies.BackgroundJobs()
+}
+
 // Start launches the HTTP server on the specified address
 func (s *Server) Start(address string) error {
 	return s.router.Run(address)
 }
+
+// registerValidators lets the min, max, gt and lt binding tags compare rates and percentages, which
+// are decimals rather than numbers in the request structs
+func registerValidators() {
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+			return field.Interface().(money.Rate).Value.InexactFloat64()
+		}, money.Rate{})
+	}
+}
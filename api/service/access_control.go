@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/common"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+// accessDeniedTable is the audit_trail table name refused requests are recorded under
+const accessDeniedTable = "access_control"
+
+type accessControlService struct {
+	userRepo  interface_repository.UserRepository
+	auditRepo interface_repository.AuditRepository
+}
+
+func NewAccessControlService(userRepo interface_repository.UserRepository,
+	auditRepo interface_repository.AuditRepository) interface_service.AccessControlService {
+	return &accessControlService{userRepo: userRepo, auditRepo: auditRepo}
+}
+
+func (s *accessControlService) UserRole(ctx context.Context, userID int64) (db.UserRole, error) {
+	access, err := s.userRepo.GetUserAccess(ctx, userID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return "", common.ErrUserInactive
+		}
+		return "", fmt.Errorf("failed to get user role: %w", err)
+	}
+	if !access.IsActive {
+		return "", common.ErrUserInactive
+	}
+	return access.Role, nil
+}
+
+func (s *accessControlService) RecordDenied(ctx context.Context, denial schemas.AccessDenial) error {
+	details, err := json.Marshal(map[string]string{
+		"method":     denial.Method,
+		"path":       denial.Path,
+		"role":       string(denial.Role),
+		"permission": denial.Permission,
+		"reason":     denial.Reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.auditRepo.CreateAccessDeniedAudit(ctx, db.CreateAccessDeniedAuditParams{
+		Route:     truncate(denial.Method+" "+denial.Route, 64),
+		Details:   pgtype.JSONB{Bytes: details, Status: pgtype.Present},
+		UserID:    sql.NullInt32{Int32: int32(denial.UserID), Valid: denial.UserID != 0},
+		IpAddress: nullString(denial.IPAddress),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record denied request: %w", err)
+	}
+	return nil
+}
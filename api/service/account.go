@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	util_common "github.com/riad/banksystemendtoend/util/common"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/money"
+	"go.uber.org/zap"
+)
+
+// maxAccountNumberRetries bounds how many times a colliding account number is regenerated
+const maxAccountNumberRetries = 5
+
+type accountService struct {
+	repo         interface_repository.AccountRepository
+	currencyRepo interface_repository.CurrencyRepository
+}
+
+func NewAccountService(repo interface_repository.AccountRepository,
+	currencyRepo interface_repository.CurrencyRepository) interface_service.AccountService {
+	return &accountService{repo: repo, currencyRepo: currencyRepo}
+}
+
+func (s *accountService) CreateAccount(ctx context.Context, req dto.CreateAccountRequest) (db.Account, error) {
+	if err := authorizeUser(ctx, req.UserID); err != nil {
+		return db.Account{}, err
+	}
+
+	accountType := strings.ToUpper(req.AccountType)
+	if !config.IsValidAccountType(accountType) {
+		return db.Account{}, common.ErrInvalidAccountType
+	}
+
+	currencyCode := strings.ToUpper(req.CurrencyCode)
+	currency, err := s.currencyRepo.GetCurrency(ctx, currencyCode)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.Account{}, common.ErrAccountReferenceError
+		}
+		return db.Account{}, err
+	}
+	if !currency.IsActive {
+		return db.Account{}, common.ErrAccountReferenceError
+	}
+
+	interestRate, overdraftLimit, err := openingTerms()
+	if err != nil {
+		return db.Account{}, err
+	}
+
+	arg := db.CreateAccountParams{
+		UserID:         int32(req.UserID),
+		AccountType:    accountType,
+		CurrencyCode:   currencyCode,
+		InterestRate:   interestRate,
+		OverdraftLimit: overdraftLimit,
+	}
+
+	for i := 0; i < maxAccountNumberRetries; i++ {
+		arg.AccountNumber, err = utils.GenerateAccountNumber()
+		if err != nil {
+			return db.Account{}, err
+		}
+
+		account, err := s.repo.CreateAccount(ctx, arg)
+		if err == nil {
+			return account, nil
+		}
+		if utils.IsUniqueViolationError(err) {
+			continue
+		}
+		if utils.IsForeignKeyError(err) {
+			return db.Account{}, common.ErrAccountReferenceError
+		}
+		logger.GetLogger().Error("failed to create account", zap.Error(err))
+		return db.Account{}, fmt.Errorf("failed to create account: %w", err)
+	}
+	return db.Account{}, fmt.Errorf("failed to generate a unique account number after %d attempts", maxAccountNumberRetries)
+}
+
+func (s *accountService) GetAccount(ctx context.Context, accountID int64) (db.Account, error) {
+	account, err := s.repo.GetAccount(ctx, accountID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.Account{}, sql.ErrNoRows
+		}
+		logger.GetLogger().Error("Failed to get account", zap.Error(err))
+		return db.Account{}, fmt.Errorf("failed to get account: %w", err)
+	}
+	if err := authorizeAccount(ctx, account); err != nil {
+		return db.Account{}, err
+	}
+	return account, nil
+}
+
+func (s *accountService) GetAccountByNumber(ctx context.Context, accountNumber string) (db.Account, error) {
+	if !utils.IsValidAccountNumber(accountNumber) {
+		return db.Account{}, common.ErrInvalidAccountNumber
+	}
+	account, err := s.repo.GetAccountByNumber(ctx, accountNumber)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.Account{}, sql.ErrNoRows
+		}
+		logger.GetLogger().Error("Failed to get account by number", zap.Error(err))
+		return db.Account{}, fmt.Errorf("failed to get account: %w", err)
+	}
+	if err := authorizeAccount(ctx, account); err != nil {
+		return db.Account{}, err
+	}
+	return account, nil
+}
+
+func (s *accountService) ListAccountsByUser(ctx context.Context, userID int64) ([]db.Account, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	accounts, err := s.repo.ListAccountsByUser(ctx, userID)
+	if err != nil {
+		logger.GetLogger().Error("Failed to list accounts", zap.Error(err))
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// CloseAccount deactivates an account. Accounts that still hold money, or that holds, scheduled
+// transfers or fixed deposits refer to, are refused until those are settled.
+func (s *accountService) CloseAccount(ctx context.Context, accountID int64) error {
+	if _, err := s.GetAccount(ctx, accountID); err != nil {
+		return err
+	}
+	closed, err := s.repo.CloseAccount(ctx, accountID)
+	if err != nil {
+		logger.GetLogger().Error("Failed to close account", zap.Error(err))
+		return fmt.Errorf("failed to close account: %w", err)
+	}
+	if !closed {
+		return common.ErrAccountNotEmpty
+	}
+	return nil
+}
+
+func (s *accountService) HardDeleteAccount(ctx context.Context, accountID int64) error {
+	if _, err := s.GetAccount(ctx, accountID); err != nil {
+		return err
+	}
+	if err := s.repo.HardDeleteAccount(ctx, accountID); err != nil {
+		// Entries referencing the account, or sealed into the ledger hash chain, are never removed
+		if utils.IsForeignKeyError(err) || utils.IsRestrictViolationError(err) {
+			return common.ErrAccountHasHistory
+		}
+		logger.GetLogger().Error("Failed to hard delete account", zap.Error(err))
+		return fmt.Errorf("failed to hard delete account: %w", err)
+	}
+	return nil
+}
+
+func (s *accountService) UpdateAccountTerms(ctx context.Context, accountID int64, req dto.UpdateAccountTermsRequest) (db.Account, error) {
+	account, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return db.Account{}, err
+	}
+
+	arg := db.UpdateAccountTermsParams{
+		InterestRate:   pgtype.Numeric{Status: pgtype.Null},
+		OverdraftLimit: pgtype.Numeric{Status: pgtype.Null},
+		AccountID:      account.AccountID,
+	}
+	if req.InterestRate != nil {
+		if arg.InterestRate, err = rateNumeric(*req.InterestRate, 2); err != nil {
+			return db.Account{}, err
+		}
+	}
+	if req.OverdraftLimit != nil {
+		if arg.OverdraftLimit, err = nonNegativeAmount(req.OverdraftLimit.WithCurrency(account.CurrencyCode)); err != nil {
+			return db.Account{}, err
+		}
+	}
+
+	updated, err := s.repo.UpdateAccountTerms(ctx, arg)
+	if err != nil {
+		logger.GetLogger().Error("Failed to update account terms", zap.Error(err))
+		return db.Account{}, fmt.Errorf("failed to update account terms: %w", err)
+	}
+	return updated, nil
+}
+
+// openingTerms returns the interest rate and overdraft limit accounts are opened with: neither. They
+// are granted afterwards by an admin, never chosen by whoever opens the account.
+func openingTerms() (pgtype.Numeric, pgtype.Numeric, error) {
+	interestRate, err := util_common.SetNumeric("0.00")
+	if err != nil {
+		return pgtype.Numeric{}, pgtype.Numeric{}, err
+	}
+	overdraftLimit, err := util_common.SetNumeric("0.00")
+	if err != nil {
+		return pgtype.Numeric{}, pgtype.Numeric{}, err
+	}
+	return interestRate, overdraftLimit, nil
+}
+
+// nonNegativeAmount converts an overdraft limit or a fee for the database, refusing negative amounts
+// and amounts with more decimals than their currency
+func nonNegativeAmount(amount money.Money) (pgtype.Numeric, error) {
+	if amount.IsNegative() {
+		return pgtype.Numeric{}, common.ErrNegativeAmount
+	}
+	if !amount.FitsScale() {
+		return pgtype.Numeric{}, common.ErrInvalidAmountPrecision
+	}
+	return amount.Numeric()
+}
+
+// rateNumeric converts a rate or a percentage for a column with scale decimals, refusing rates that
+// would be rounded to fit it
+func rateNumeric(rate money.Rate, scale int32) (pgtype.Numeric, error) {
+	if !rate.FitsScale(scale) {
+		return pgtype.Numeric{}, common.ErrInvalidRatePrecision
+	}
+	return rate.Numeric()
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/apikey"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/riad/banksystemendtoend/util/signing"
+	"go.uber.org/zap"
+)
+
+// apiKeyTouchInterval is how stale last_used_at may get, so a busy key is not written on every request
+const apiKeyTouchInterval = time.Minute
+
+type apiKeyService struct {
+	apiKeyRepo interface_repository.APIKeyRepository
+	// signer derives the signing secrets handed out with new keys, nil while request signing is disabled
+	signer *signing.Signer
+}
+
+func NewAPIKeyService(apiKeyRepo interface_repository.APIKeyRepository, signer *signing.Signer) interface_service.APIKeyService {
+	return &apiKeyService{apiKeyRepo: apiKeyRepo, signer: signer}
+}
+
+func (s *apiKeyService) Issue(ctx context.Context, req dto.IssueAPIKeyRequest) (schemas.IssuedAPIKey, error) {
+	if !apikey.ValidScopes(req.Scopes) {
+		return schemas.IssuedAPIKey{}, common.ErrInvalidAPIKeyScopes
+	}
+	if req.SigningRequired && s.signer == nil {
+		return schemas.IssuedAPIKey{}, common.ErrRequestSigningDisabled
+	}
+	expiresAt, err := apiKeyExpiry(req.ExpiresAt)
+	if err != nil {
+		return schemas.IssuedAPIKey{}, err
+	}
+
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return schemas.IssuedAPIKey{}, err
+	}
+	created, err := s.apiKeyRepo.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		KeyPrefix:       prefix,
+		KeyHash:         hash,
+		Name:            req.Name,
+		OwnerUserID:     int32(req.OwnerUserID),
+		Scopes:          req.Scopes,
+		ExpiresAt:       expiresAt,
+		CreatedBy:       authUser(ctx),
+		SigningRequired: req.SigningRequired,
+	})
+	if err != nil {
+		if utils.IsForeignKeyError(err) {
+			return schemas.IssuedAPIKey{}, common.ErrAPIKeyOwnerNotFound
+		}
+		logger.GetLogger().Error("Failed to create API key", zap.Error(err))
+		return schemas.IssuedAPIKey{}, fmt.Errorf("failed to create API key: %w", err)
+	}
+	return s.issued(key, created), nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, query dto.APIKeyQuery, page, pageSize int32) ([]db.ApiKey, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return s.apiKeyRepo.ListAPIKeys(ctx, db.ListAPIKeysParams{
+		OwnerUserID: sql.NullInt32{Int32: int32(query.OwnerUserID), Valid: query.OwnerUserID != 0},
+		Limit:       pageSize,
+		Offset:      (page - 1) * pageSize,
+	})
+}
+
+// Rotate issues a key with the owner, name and scopes of apiKeyID. The old key keeps working for
+// the requested overlap, API_KEY_ROTATION_OVERLAP by default, so clients can switch without downtime.
+func (s *apiKeyService) Rotate(ctx context.Context, apiKeyID int64, req dto.RotateAPIKeyRequest) (schemas.IssuedAPIKey, error) {
+	expiresAt, err := apiKeyExpiry(req.ExpiresAt)
+	if err != nil {
+		return schemas.IssuedAPIKey{}, err
+	}
+	overlap := utils.GetEnvAsDuration(apikey.RotationOverlapEnv, apikey.DefaultRotationOverlap)
+	if req.OverlapMinutes > 0 {
+		overlap = time.Duration(req.OverlapMinutes) * time.Minute
+	}
+
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return schemas.IssuedAPIKey{}, err
+	}
+	rotated, previous, err := transaction.RotateAPIKey(ctx, int32(apiKeyID), db.CreateAPIKeyParams{
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+		ExpiresAt: expiresAt,
+		CreatedBy: authUser(ctx),
+	}, time.Now().Add(overlap))
+	if err != nil {
+		switch {
+		case errors.Is(err, transaction.ErrAPIKeyNotFound):
+			return schemas.IssuedAPIKey{}, common.ErrAPIKeyNotFound
+		case errors.Is(err, transaction.ErrAPIKeyInactive):
+			return schemas.IssuedAPIKey{}, common.ErrAPIKeyInactive
+		}
+		logger.GetLogger().Error("Failed to rotate API key", zap.Error(err))
+		return schemas.IssuedAPIKey{}, fmt.Errorf("failed to rotate API key: %w", err)
+	}
+	//? The cached copy of the old key still has its old expiry
+	s.apiKeyRepo.InvalidateAPIKey(ctx, previous.KeyPrefix)
+	return s.issued(key, rotated), nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, apiKeyID int64) (db.ApiKey, error) {
+	revoked, err := s.apiKeyRepo.RevokeAPIKey(ctx, int32(apiKeyID))
+	if err == nil {
+		return revoked, nil
+	}
+	if !utils.IsNotFoundError(err) {
+		logger.GetLogger().Error("Failed to revoke API key", zap.Error(err))
+		return db.ApiKey{}, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	//? Nothing was revoked, either the key does not exist or it was revoked before
+	if _, err := s.apiKeyRepo.GetAPIKey(ctx, int32(apiKeyID)); err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.ApiKey{}, common.ErrAPIKeyNotFound
+		}
+		return db.ApiKey{}, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return db.ApiKey{}, common.ErrAPIKeyInactive
+}
+
+// Validate compares key with the stored hash in constant time. Unknown, malformed and revoked keys
+// are all refused with ErrInvalidAPIKey, so callers cannot tell them apart.
+func (s *apiKeyService) Validate(ctx context.Context, key string) (db.ApiKey, error) {
+	prefix, ok := apikey.Prefix(key)
+	if !ok {
+		return db.ApiKey{}, common.ErrInvalidAPIKey
+	}
+	stored, err := s.apiKeyRepo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.ApiKey{}, common.ErrInvalidAPIKey
+		}
+		logger.GetLogger().Error("Failed to get API key", zap.Error(err))
+		return db.ApiKey{}, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if !apikey.Matches(key, stored.KeyHash) || stored.RevokedAt.Valid {
+		return db.ApiKey{}, common.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if stored.ExpiresAt.Valid && !now.Before(stored.ExpiresAt.Time) {
+		return db.ApiKey{}, common.ErrAPIKeyExpired
+	}
+	if !stored.LastUsedAt.Valid || now.Sub(stored.LastUsedAt.Time) > apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchAPIKey(ctx, stored, now); err != nil {
+			logger.GetLogger().Error("Failed to record API key use", zap.Error(err))
+		}
+	}
+	return stored, nil
+}
+
+// issued pairs a new key with the secret requests sent with it are signed with
+func (s *apiKeyService) issued(key string, stored db.ApiKey) schemas.IssuedAPIKey {
+	issued := schemas.IssuedAPIKey{Key: key, APIKey: stored}
+	if s.signer != nil {
+		issued.SigningSecret = s.signer.ClientSecret(stored.KeyPrefix)
+	}
+	return issued
+}
+
+// apiKeyExpiry validates an optional expiry, keys without one work until they are revoked
+func apiKeyExpiry(expiresAt *time.Time) (sql.NullTime, error) {
+	if expiresAt == nil {
+		return sql.NullTime{}, nil
+	}
+	if !expiresAt.After(time.Now()) {
+		return sql.NullTime{}, common.ErrInvalidAPIKeyExpiry
+	}
+	return sql.NullTime{Time: *expiresAt, Valid: true}, nil
+}
+
+// authUser returns the user the request was authenticated as, for columns recording who acted
+func authUser(ctx context.Context) sql.NullInt32 {
+	userID, ok := utils.AuthUserID(ctx)
+	return sql.NullInt32{Int32: int32(userID), Valid: ok}
+}
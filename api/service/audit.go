@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// auditedTables are the tables the audit_trail triggers record changes of, and the name requests
+// refused by access control are recorded under
+var auditedTables = map[string]bool{
+	"users":              true,
+	"accounts":           true,
+	"account_types":      true,
+	"account_currencies": true,
+	"transactions":       true,
+	accessDeniedTable:    true,
+}
+
+type auditService struct {
+	auditRepo interface_repository.AuditRepository
+}
+
+func NewAuditService(auditRepo interface_repository.AuditRepository) interface_service.AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+func (s *auditService) Get(ctx context.Context, auditID int64) (db.AuditTrail, error) {
+	if auditID > math.MaxInt32 {
+		return db.AuditTrail{}, common.ErrAuditRecordNotFound
+	}
+	record, err := s.auditRepo.GetAuditTrail(ctx, int32(auditID))
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.AuditTrail{}, common.ErrAuditRecordNotFound
+		}
+		return db.AuditTrail{}, err
+	}
+	return record, nil
+}
+
+func (s *auditService) List(ctx context.Context, query dto.AuditTrailQuery, page, pageSize int32) ([]db.AuditTrail, error) {
+	arg, err := auditTrailFilters(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	arg.Limit = pageSize
+	arg.Offset = (page - 1) * pageSize
+	return s.auditRepo.ListAuditTrail(ctx, arg)
+}
+
+// auditTrailFilters validates the query and turns it into the optional filters of ListAuditTrail
+func auditTrailFilters(query dto.AuditTrailQuery) (db.ListAuditTrailParams, error) {
+	var arg db.ListAuditTrailParams
+
+	if query.Table != "" {
+		if !auditedTables[query.Table] {
+			return arg, common.ErrInvalidAuditTable
+		}
+		arg.TableName = sql.NullString{String: query.Table, Valid: true}
+	}
+	if query.RecordID != "" {
+		arg.RecordID = sql.NullString{String: query.RecordID, Valid: true}
+	}
+	if query.UserID != 0 {
+		arg.UserID = sql.NullInt32{Int32: int32(query.UserID), Valid: true}
+	}
+	if query.From != "" {
+		from, err := time.Parse(time.RFC3339, query.From)
+		if err != nil {
+			return arg, common.ErrInvalidAuditPeriod
+		}
+		arg.FromTime = sql.NullTime{Time: from, Valid: true}
+	}
+	if query.To != "" {
+		to, err := time.Parse(time.RFC3339, query.To)
+		if err != nil {
+			return arg, common.ErrInvalidAuditPeriod
+		}
+		arg.ToTime = sql.NullTime{Time: to, Valid: true}
+	}
+	if arg.FromTime.Valid && arg.ToTime.Valid && !arg.FromTime.Time.Before(arg.ToTime.Time) {
+		return arg, common.ErrInvalidAuditPeriod
+	}
+	return arg, nil
+}
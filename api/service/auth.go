@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/riad/banksystemendtoend/util/token"
+	"go.uber.org/zap"
+)
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+type authService struct {
+	userRepo  interface_repository.UserRepository
+	tokenRepo interface_repository.RefreshTokenRepository
+	twoFactor interface_service.TwoFactorService
+	maker     *token.Maker
+}
+
+func NewAuthService(userRepo interface_repository.UserRepository, tokenRepo interface_repository.RefreshTokenRepository,
+	twoFactor interface_service.TwoFactorService, maker *token.Maker) interface_service.AuthService {
+	return &authService{userRepo: userRepo, tokenRepo: tokenRepo, twoFactor: twoFactor, maker: maker}
+}
+
+func (s *authService) Login(ctx context.Context, req dto.LoginRequest) (schemas.LoginResult, error) {
+	user, err := s.userRepo.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		if !utils.IsNotFoundError(err) {
+			logger.GetLogger().Error("Failed to get user", zap.Error(err))
+			return schemas.LoginResult{}, fmt.Errorf("failed to get user: %w", err)
+		}
+		//? Check the password anyway so unknown usernames take as long to refuse as wrong passwords
+		_ = utils.CheckPassword(req.Password, dummyPasswordHash())
+		return schemas.LoginResult{}, common.ErrInvalidCredentials
+	}
+	if err := utils.CheckPassword(req.Password, user.PasswordHash); err != nil || !user.IsActive {
+		return schemas.LoginResult{}, common.ErrInvalidCredentials
+	}
+
+	enabled, err := s.twoFactor.Enabled(ctx, int64(user.UserID))
+	if err != nil {
+		return schemas.LoginResult{}, err
+	}
+	if enabled {
+		challenge, payload, err := s.maker.CreateChallengeToken(int64(user.UserID))
+		if err != nil {
+			return schemas.LoginResult{}, fmt.Errorf("failed to create challenge token: %w", err)
+		}
+		return schemas.LoginResult{ChallengeToken: challenge, ChallengeExpiresAt: payload.ExpiresAt}, nil
+	}
+
+	tokens, err := s.startSession(ctx, user, req.UserAgent)
+	if err != nil {
+		return schemas.LoginResult{}, err
+	}
+	return schemas.LoginResult{Tokens: tokens}, nil
+}
+
+// VerifyTwoFactor finishes the login of a user with two-factor authentication. A challenge token
+// is only worth a session together with a code that was not accepted before.
+func (s *authService) VerifyTwoFactor(ctx context.Context, req dto.TwoFactorLoginRequest) (schemas.AuthTokens, error) {
+	payload, err := s.maker.VerifyChallengeToken(req.ChallengeToken)
+	if err != nil {
+		return schemas.AuthTokens{}, common.ErrInvalidChallengeToken
+	}
+	user, err := s.userRepo.GetUser(ctx, payload.UserID)
+	if err != nil || !user.IsActive {
+		if err != nil && !utils.IsNotFoundError(err) {
+			logger.GetLogger().Error("Failed to get user", zap.Error(err))
+			return schemas.AuthTokens{}, fmt.Errorf("failed to get user: %w", err)
+		}
+		return schemas.AuthTokens{}, common.ErrInvalidChallengeToken
+	}
+	if err := s.twoFactor.Verify(ctx, payload.UserID, req.Code); err != nil {
+		//? Disabled since the challenge was issued, the user logs in again
+		if errors.Is(err, common.ErrTwoFactorNotEnabled) {
+			return schemas.AuthTokens{}, common.ErrInvalidChallengeToken
+		}
+		return schemas.AuthTokens{}, err
+	}
+	return s.startSession(ctx, user, req.UserAgent)
+}
+
+// startSession records the login and issues the tokens of a new refresh token family
+func (s *authService) startSession(ctx context.Context, user db.User, userAgent string) (schemas.AuthTokens, error) {
+	now := time.Now()
+	if err := s.userRepo.UpdateLastLogin(ctx, int64(user.UserID), now); err != nil {
+		logger.GetLogger().Error("Failed to update last login", zap.Error(err))
+		return schemas.AuthTokens{}, fmt.Errorf("failed to update last login: %w", err)
+	}
+	user.LastLogin = now
+
+	refreshToken, expiresAt, err := s.maker.NewRefreshToken()
+	if err != nil {
+		return schemas.AuthTokens{}, err
+	}
+	_, err = s.tokenRepo.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		UserID:    user.UserID,
+		TokenHash: token.HashRefreshToken(refreshToken),
+		FamilyID:  uuid.New(),
+		ExpiresAt: expiresAt,
+		UserAgent: nullString(truncate(userAgent, 255)),
+		IpAddress: nullString(clientIP(ctx)),
+	})
+	if err != nil {
+		logger.GetLogger().Error("Failed to store refresh token", zap.Error(err))
+		return schemas.AuthTokens{}, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return s.issueTokens(user, refreshToken, expiresAt)
+}
+
+func (s *authService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (schemas.AuthTokens, error) {
+	refreshToken, expiresAt, err := s.maker.NewRefreshToken()
+	if err != nil {
+		return schemas.AuthTokens{}, err
+	}
+
+	rotated, err := transaction.RotateRefreshToken(ctx, token.HashRefreshToken(req.RefreshToken), db.CreateRefreshTokenParams{
+		TokenHash: token.HashRefreshToken(refreshToken),
+		ExpiresAt: expiresAt,
+		UserAgent: nullString(truncate(req.UserAgent, 255)),
+		IpAddress: nullString(clientIP(ctx)),
+	})
+	if err != nil {
+		if errors.Is(err, transaction.ErrRefreshTokenReused) {
+			logger.GetLogger().Warn("Replaced refresh token presented again, its family was revoked",
+				zap.String("ip_address", clientIP(ctx)))
+			return schemas.AuthTokens{}, common.ErrInvalidRefreshToken
+		}
+		if errors.Is(err, transaction.ErrRefreshTokenInvalid) {
+			return schemas.AuthTokens{}, common.ErrInvalidRefreshToken
+		}
+		logger.GetLogger().Error("Failed to rotate refresh token", zap.Error(err))
+		return schemas.AuthTokens{}, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	user, err := s.userRepo.GetUser(ctx, int64(rotated.UserID))
+	if err != nil || !user.IsActive {
+		if err := s.tokenRepo.RevokeRefreshTokenFamily(ctx, rotated.FamilyID); err != nil {
+			logger.GetLogger().Error("Failed to revoke refresh tokens", zap.Error(err))
+		}
+		return schemas.AuthTokens{}, common.ErrInvalidRefreshToken
+	}
+	return s.issueTokens(user, refreshToken, expiresAt)
+}
+
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.tokenRepo.GetRefreshToken(ctx, token.HashRefreshToken(refreshToken))
+	if err != nil {
+		//? Logging out with an unknown token leaves nothing to revoke
+		if utils.IsNotFoundError(err) {
+			return nil
+		}
+		logger.GetLogger().Error("Failed to get refresh token", zap.Error(err))
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if err := s.tokenRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyID); err != nil {
+		logger.GetLogger().Error("Failed to revoke refresh tokens", zap.Error(err))
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+func (s *authService) issueTokens(user db.User, refreshToken string, refreshExpiresAt time.Time) (schemas.AuthTokens, error) {
+	accessToken, payload, err := s.maker.CreateAccessToken(int64(user.UserID))
+	if err != nil {
+		return schemas.AuthTokens{}, fmt.Errorf("failed to create access token: %w", err)
+	}
+	return schemas.AuthTokens{
+		User:                  user,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  payload.ExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// authorizeUser refuses requests authenticated as another user, unless the role of the caller may act
+// for every user. Requests without an access token, such as those of background jobs, are not
+// limited to one user.
+func authorizeUser(ctx context.Context, userID int64) error {
+	if authUserID, ok := utils.AuthUserID(ctx); ok && authUserID != userID && !utils.AuthAllUsers(ctx) {
+		return common.ErrForbidden
+	}
+	return nil
+}
+
+// authorizeAllUsers refuses requests authenticated as a user whose role is limited to its own resources
+func authorizeAllUsers(ctx context.Context) error {
+	if _, ok := utils.AuthUserID(ctx); ok && !utils.AuthAllUsers(ctx) {
+		return common.ErrForbidden
+	}
+	return nil
+}
+
+// authorizeAccount refuses requests authenticated as a user who does not own account
+func authorizeAccount(ctx context.Context, account db.Account) error {
+	return authorizeUser(ctx, int64(account.UserID))
+}
+
+// dummyPasswordHash is a bcrypt hash of nothing anyone knows, with the cost real hashes use
+func dummyPasswordHash() string {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = utils.HashPassword(uuid.NewString())
+	})
+	return dummyHash
+}
+
+// clientIP returns the address the request came from, as recorded for the audit trail
+func clientIP(ctx context.Context) string {
+	actor, _ := db.AuditActorFromContext(ctx)
+	return actor.IPAddress
+}
+
+// truncate cuts value to at most size bytes without splitting a character
+func truncate(value string, size int) string {
+	if len(value) > size {
+		return strings.ToValidUTF8(value[:size], "")
+	}
+	return value
+}
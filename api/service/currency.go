@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/money"
+	"go.uber.org/zap"
+)
+
+// defaultMinorUnits is used for currencies that are not in ISO 4217
+const defaultMinorUnits = 2
+
+// exchangeRateScale is the scale of the NUMERIC(20, 10) exchange rate columns
+const exchangeRateScale = 10
+
+type currencyService struct {
+	currencyRepo interface_repository.CurrencyRepository
+}
+
+func NewCurrencyService(currencyRepo interface_repository.CurrencyRepository) interface_service.CurrencyService {
+	return &currencyService{currencyRepo: currencyRepo}
+}
+
+// Create adds a currency. ISO 4217 currencies always get their ISO minor units, they are reset to
+// them at every start anyway.
+func (s *currencyService) Create(ctx context.Context, req dto.CreateCurrencyRequest) (db.AccountCurrency, error) {
+	currencyCode := strings.ToUpper(req.CurrencyCode)
+
+	minorUnits := int16(defaultMinorUnits)
+	if iso, ok := iso4217.Lookup(currencyCode); ok {
+		if req.MinorUnits != nil && *req.MinorUnits != iso.MinorUnits {
+			return db.AccountCurrency{}, common.ErrCurrencyMinorUnitsFixed
+		}
+		minorUnits = iso.MinorUnits
+	} else if req.MinorUnits != nil {
+		minorUnits = *req.MinorUnits
+	}
+
+	exchangeRate := pgtype.Numeric{Status: pgtype.Null}
+	if req.ExchangeRate != nil {
+		var err error
+		if exchangeRate, err = rateNumeric(*req.ExchangeRate, exchangeRateScale); err != nil {
+			return db.AccountCurrency{}, err
+		}
+	}
+
+	currency, err := s.currencyRepo.CreateCurrency(ctx, db.CreateCurrencyParams{
+		CurrencyCode: currencyCode,
+		CurrencyName: req.CurrencyName,
+		Symbol:       sql.NullString{String: req.Symbol, Valid: req.Symbol != ""},
+		ExchangeRate: exchangeRate,
+		MinorUnits:   sql.NullInt16{Int16: minorUnits, Valid: true},
+		IsActive:     optionalBool(req.IsActive),
+	})
+	if err != nil {
+		if utils.IsUniqueViolationError(err) {
+			return db.AccountCurrency{}, common.ErrCurrencyExists
+		}
+		logger.GetLogger().Error("Failed to create currency", zap.String("currency_code", currencyCode), zap.Error(err))
+		return db.AccountCurrency{}, err
+	}
+	money.RegisterMinorUnits(currency.CurrencyCode, currency.MinorUnits)
+	return currency, nil
+}
+
+func (s *currencyService) Get(ctx context.Context, currencyCode string) (db.AccountCurrency, error) {
+	currency, err := s.currencyRepo.GetCurrency(ctx, strings.ToUpper(currencyCode))
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.AccountCurrency{}, common.ErrCurrencyNotFound
+		}
+		return db.AccountCurrency{}, err
+	}
+	return currency, nil
+}
+
+func (s *currencyService) List(ctx context.Context, query dto.CurrencyQuery) ([]db.AccountCurrency, error) {
+	return s.currencyRepo.ListCurrencies(ctx, query.IncludeInactive)
+}
+
+// Update changes a currency. Minor units cannot change for ISO 4217 currencies, nor while accounts
+// hold the currency since their balances were written with the old ones. A new exchange rate is
+// recorded in the rate history like any other rate change.
+func (s *currencyService) Update(ctx context.Context, currencyCode string, req dto.UpdateCurrencyRequest) (db.AccountCurrency, error) {
+	current, err := s.Get(ctx, currencyCode)
+	if err != nil {
+		return db.AccountCurrency{}, err
+	}
+
+	if req.MinorUnits != nil && *req.MinorUnits != current.MinorUnits {
+		if _, ok := iso4217.Lookup(current.CurrencyCode); ok {
+			return db.AccountCurrency{}, common.ErrCurrencyMinorUnitsFixed
+		}
+		accounts, err := s.currencyRepo.CountAccountsByCurrency(ctx, current.CurrencyCode)
+		if err != nil {
+			return db.AccountCurrency{}, err
+		}
+		if accounts > 0 {
+			return db.AccountCurrency{}, common.ErrCurrencyInUse
+		}
+	}
+
+	arg := db.UpdateCurrencyParams{
+		CurrencyName: optionalString(req.CurrencyName),
+		Symbol:       optionalString(req.Symbol),
+		IsActive:     optionalBool(req.IsActive),
+		CurrencyCode: current.CurrencyCode,
+	}
+	if req.MinorUnits != nil {
+		arg.MinorUnits = sql.NullInt16{Int16: *req.MinorUnits, Valid: true}
+	}
+	currency, err := s.currencyRepo.UpdateCurrency(ctx, arg)
+	if err != nil {
+		return db.AccountCurrency{}, s.mapNotFound(err)
+	}
+	money.RegisterMinorUnits(currency.CurrencyCode, currency.MinorUnits)
+
+	if req.ExchangeRate != nil {
+		rate, err := rateNumeric(*req.ExchangeRate, exchangeRateScale)
+		if err != nil {
+			return db.AccountCurrency{}, err
+		}
+		currency, err = s.currencyRepo.UpdateExchangeRate(ctx, db.UpdateExchangeRateParams{
+			ExchangeRate: rate,
+			CurrencyCode: current.CurrencyCode,
+		})
+		if err != nil {
+			return db.AccountCurrency{}, s.mapNotFound(err)
+		}
+	}
+	return currency, nil
+}
+
+// Deactivate keeps the currency and the accounts that hold it, new accounts can no longer use it
+func (s *currencyService) Deactivate(ctx context.Context, currencyCode string) (db.AccountCurrency, error) {
+	inactive := false
+	currency, err := s.currencyRepo.UpdateCurrency(ctx, db.UpdateCurrencyParams{
+		IsActive:     optionalBool(&inactive),
+		CurrencyCode: strings.ToUpper(currencyCode),
+	})
+	if err != nil {
+		return db.AccountCurrency{}, s.mapNotFound(err)
+	}
+	return currency, nil
+}
+
+func (s *currencyService) mapNotFound(err error) error {
+	if utils.IsNotFoundError(err) {
+		return common.ErrCurrencyNotFound
+	}
+	return err
+}
+
+// optionalString converts a field that may be left out of a request into sql.NullString
+func optionalString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
+
+// optionalBool converts a field that may be left out of a request into sql.NullBool
+func optionalBool(value *bool) sql.NullBool {
+	if value == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *value, Valid: true}
+}
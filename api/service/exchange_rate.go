@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+type exchangeRateService struct {
+	exchangeRateRepo interface_repository.ExchangeRateRepository
+}
+
+func NewExchangeRateService(exchangeRateRepo interface_repository.ExchangeRateRepository) interface_service.ExchangeRateService {
+	return &exchangeRateService{exchangeRateRepo: exchangeRateRepo}
+}
+
+func (s *exchangeRateService) Get(ctx context.Context, query dto.ExchangeRateQuery) (schemas.ExchangeRate, error) {
+	result := schemas.ExchangeRate{
+		FromCurrency: strings.ToUpper(query.From),
+		ToCurrency:   strings.ToUpper(query.To),
+		At:           time.Now().UTC(),
+	}
+	if query.At != "" {
+		at, err := time.Parse(time.RFC3339, query.At)
+		if err != nil {
+			return result, common.ErrInvalidExchangeRateTime
+		}
+		result.At = at
+	}
+	for _, code := range []string{result.FromCurrency, result.ToCurrency} {
+		if err := s.ensureCurrency(ctx, code); err != nil {
+			return result, err
+		}
+	}
+
+	rate, err := transaction.ExchangeRateAt(ctx, result.FromCurrency, result.ToCurrency, result.At)
+	if err != nil {
+		if errors.Is(err, transaction.ErrExchangeRateUnavailable) {
+			return result, common.ErrExchangeRateUnavailable
+		}
+		return result, err
+	}
+	result.Rate = rate
+	return result, nil
+}
+
+func (s *exchangeRateService) History(ctx context.Context, currencyCode string, query dto.ExchangeRateHistoryQuery,
+	page, pageSize int32) ([]db.ExchangeRateHistory, error) {
+
+	currencyCode = strings.ToUpper(currencyCode)
+	if err := s.ensureCurrency(ctx, currencyCode); err != nil {
+		return nil, err
+	}
+
+	arg := db.ListExchangeRateHistoryParams{CurrencyCode: currencyCode}
+	if query.From != "" {
+		from, err := time.Parse(time.RFC3339, query.From)
+		if err != nil {
+			return nil, common.ErrInvalidRateHistoryRange
+		}
+		arg.FromTime = sql.NullTime{Time: from, Valid: true}
+	}
+	if query.To != "" {
+		to, err := time.Parse(time.RFC3339, query.To)
+		if err != nil {
+			return nil, common.ErrInvalidRateHistoryRange
+		}
+		arg.ToTime = sql.NullTime{Time: to, Valid: true}
+	}
+	if arg.FromTime.Valid && arg.ToTime.Valid && !arg.FromTime.Time.Before(arg.ToTime.Time) {
+		return nil, common.ErrInvalidRateHistoryRange
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	arg.Limit = pageSize
+	arg.Offset = (page - 1) * pageSize
+	return s.exchangeRateRepo.ListExchangeRateHistory(ctx, arg)
+}
+
+// ensureCurrency reports an unknown currency code as ErrCurrencyNotFound
+func (s *exchangeRateService) ensureCurrency(ctx context.Context, currencyCode string) error {
+	if _, err := s.exchangeRateRepo.GetCurrency(ctx, currencyCode); err != nil {
+		if utils.IsNotFoundError(err) {
+			return common.ErrCurrencyNotFound
+		}
+		return err
+	}
+	return nil
+}
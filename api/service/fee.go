@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"go.uber.org/zap"
+)
+
+type feeService struct {
+	feeRepo     interface_repository.FeeRepository
+	accountRepo interface_repository.AccountRepository
+}
+
+func NewFeeService(feeRepo interface_repository.FeeRepository,
+	accountRepo interface_repository.AccountRepository) interface_service.FeeService {
+	return &feeService{feeRepo: feeRepo, accountRepo: accountRepo}
+}
+
+func (s *feeService) UpsertSchedule(ctx context.Context, accountType string,
+	req dto.UpsertFeeScheduleRequest) (db.FeeSchedule, error) {
+
+	arg := db.UpsertFeeScheduleParams{AccountType: accountType, IsActive: true}
+	if req.IsActive != nil {
+		arg.IsActive = *req.IsActive
+	}
+
+	var err error
+	if arg.TransferFeeFlat, err = nonNegativeAmount(req.TransferFeeFlat); err != nil {
+		return db.FeeSchedule{}, err
+	}
+	if arg.TransferFeePercent, err = rateNumeric(req.TransferFeePercent, 4); err != nil {
+		return db.FeeSchedule{}, err
+	}
+	if arg.FxMarkupPercent, err = rateNumeric(req.FxMarkupPercent, 4); err != nil {
+		return db.FeeSchedule{}, err
+	}
+	if arg.MonthlyMaintenanceFee, err = nonNegativeAmount(req.MonthlyMaintenanceFee); err != nil {
+		return db.FeeSchedule{}, err
+	}
+	if arg.OverdraftFee, err = nonNegativeAmount(req.OverdraftFee); err != nil {
+		return db.FeeSchedule{}, err
+	}
+
+	schedule, err := s.feeRepo.UpsertFeeSchedule(ctx, arg)
+	if err != nil {
+		if utils.IsForeignKeyError(err) {
+			return db.FeeSchedule{}, common.ErrUnknownAccountType
+		}
+		return db.FeeSchedule{}, err
+	}
+	return schedule, nil
+}
+
+func (s *feeService) GetSchedule(ctx context.Context, accountType string) (db.FeeSchedule, error) {
+	schedule, err := s.feeRepo.GetFeeSchedule(ctx, accountType)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.FeeSchedule{}, common.ErrFeeScheduleNotFound
+		}
+		return db.FeeSchedule{}, err
+	}
+	return schedule, nil
+}
+
+func (s *feeService) ListSchedules(ctx context.Context) ([]db.FeeSchedule, error) {
+	return s.feeRepo.ListFeeSchedules(ctx)
+}
+
+// SetIncomeAccount points the fees of a currency at an account, which must hold that currency
+func (s *feeService) SetIncomeAccount(ctx context.Context, currencyCode string,
+	req dto.SetFeeIncomeAccountRequest) (db.FeeIncomeAccount, error) {
+
+	currencyCode = strings.ToUpper(currencyCode)
+	account, err := s.accountRepo.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.FeeIncomeAccount{}, common.ErrFeeIncomeAccountNotFound
+		}
+		return db.FeeIncomeAccount{}, err
+	}
+	if account.CurrencyCode != currencyCode {
+		return db.FeeIncomeAccount{}, common.ErrFeeIncomeAccountCurrency
+	}
+
+	return s.feeRepo.UpsertFeeIncomeAccount(ctx, db.UpsertFeeIncomeAccountParams{
+		CurrencyCode: currencyCode,
+		AccountID:    account.AccountID,
+	})
+}
+
+func (s *feeService) ListIncomeAccounts(ctx context.Context) ([]db.FeeIncomeAccount, error) {
+	return s.feeRepo.ListFeeIncomeAccounts(ctx)
+}
+
+func (s *feeService) ListCharges(ctx context.Context, accountID int32,
+	page, pageSize int32) ([]db.FeeCharge, error) {
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return s.feeRepo.ListFeeChargesByAccount(ctx, db.ListFeeChargesByAccountParams{
+		AccountID: accountID,
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+	})
+}
+
+// Preview validates the transfer the same way CreateTransfer does, then quotes it
+func (s *feeService) Preview(ctx context.Context, req dto.FeePreviewRequest) (schemas.FeePreview, error) {
+	if req.FromAccountID == req.ToAccountID {
+		return schemas.FeePreview{}, common.ErrSameAccount
+	}
+	if !req.Amount.IsPositive() {
+		return schemas.FeePreview{}, common.ErrInvalidAmount
+	}
+	currencyCode := strings.ToUpper(req.CurrencyCode)
+
+	sender, err := getTransferAccount(ctx, s.accountRepo, req.FromAccountID)
+	if err != nil {
+		return schemas.FeePreview{}, err
+	}
+	if err := authorizeAccount(ctx, sender); err != nil {
+		return schemas.FeePreview{}, err
+	}
+	if sender.CurrencyCode != currencyCode {
+		return schemas.FeePreview{}, common.ErrCurrencyMismatch
+	}
+	if _, err := getTransferAccount(ctx, s.accountRepo, req.ToAccountID); err != nil {
+		return schemas.FeePreview{}, err
+	}
+
+	amount, err := req.Amount.WithCurrency(currencyCode).Numeric()
+	if err != nil {
+		return schemas.FeePreview{}, err
+	}
+
+	preview, err := transaction.PreviewTransferFees(ctx, schemas.TransferTxParams{
+		SenderAccountID:   int32(req.FromAccountID),
+		ReceiverAccountID: int32(req.ToAccountID),
+		Amount:            amount,
+		CurrencyCode:      currencyCode,
+	})
+	if err != nil {
+		if errors.Is(err, transaction.ErrExchangeRateUnavailable) {
+			return schemas.FeePreview{}, common.ErrExchangeRateUnavailable
+		}
+		if errors.Is(err, transaction.ErrFixedDepositLocked) {
+			return schemas.FeePreview{}, common.ErrFixedDepositLocked
+		}
+		if errors.Is(err, transaction.ErrAmountPrecision) {
+			return schemas.FeePreview{}, common.ErrInvalidAmountPrecision
+		}
+		return schemas.FeePreview{}, err
+	}
+	return preview, nil
+}
+
+// Run charges the fees of the requested day. Today is refused because its end of day balances are
+// not known yet.
+func (s *feeService) Run(ctx context.Context, req dto.RunFeesRequest) (schemas.PeriodicFeeResult, time.Time, error) {
+	today := interest.Date(time.Now())
+	day := today.AddDate(0, 0, -1)
+	if req.Day != "" {
+		parsed, err := time.Parse(time.DateOnly, req.Day)
+		if err != nil || !parsed.Before(today) {
+			return schemas.PeriodicFeeResult{}, time.Time{}, common.ErrInvalidFeeDate
+		}
+		day = parsed
+	}
+
+	result, err := transaction.RunPeriodicFees(ctx, day)
+	if err != nil {
+		logger.GetLogger().Error("Periodic fee run failed",
+			zap.String("day", day.Format(time.DateOnly)),
+			zap.Error(err))
+		return result, day, err
+	}
+	return result, day, nil
+}
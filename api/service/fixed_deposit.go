@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"go.uber.org/zap"
+)
+
+// maturityBatchSize bounds how many deposits a manual maturity run processes
+const maturityBatchSize = 500
+
+type fixedDepositService struct {
+	accountRepo interface_repository.AccountRepository
+	depositRepo interface_repository.FixedDepositRepository
+	stepUp      stepUpGuard
+}
+
+func NewFixedDepositService(accountRepo interface_repository.AccountRepository, depositRepo interface_repository.FixedDepositRepository,
+	currencyRepo interface_repository.CurrencyRepository, twoFactor interface_service.TwoFactorService) interface_service.FixedDepositService {
+	return &fixedDepositService{
+		accountRepo: accountRepo,
+		depositRepo: depositRepo,
+		stepUp:      newStepUpGuard(accountRepo, currencyRepo, twoFactor),
+	}
+}
+
+// Open checks the linked account, then opens the deposit under a freshly generated account number.
+// A colliding account number rolls the whole transaction back and is retried with a new one.
+func (s *fixedDepositService) Open(ctx context.Context, req dto.OpenFixedDepositRequest) (schemas.FixedDepositTxResult, error) {
+	if !req.Principal.IsPositive() {
+		return schemas.FixedDepositTxResult{}, common.ErrInvalidAmount
+	}
+	linked, err := getTransferAccount(ctx, s.accountRepo, req.LinkedAccountID)
+	if err != nil {
+		return schemas.FixedDepositTxResult{}, err
+	}
+	if err := authorizeAccount(ctx, linked); err != nil {
+		return schemas.FixedDepositTxResult{}, err
+	}
+	if linked.AccountType == config.AccountTypes.FIXED_DEPOSIT {
+		return schemas.FixedDepositTxResult{}, common.ErrInvalidLinkedAccount
+	}
+	// The deposit account is opened for the same user, only the amount can call for a step-up
+	transfer := stepUpTransfer{
+		senderUserID:   linked.UserID,
+		receiverUserID: linked.UserID,
+		amount:         req.Principal.WithCurrency(linked.CurrencyCode),
+	}
+	if err := s.stepUp.check(ctx, req.TOTPCode, transfer); err != nil {
+		return schemas.FixedDepositTxResult{}, err
+	}
+
+	arg := schemas.OpenFixedDepositParams{
+		LinkedAccountID:     linked.AccountID,
+		TermMonths:          req.TermMonths,
+		MaturityInstruction: db.MaturityInstructionPAYOUT,
+		ReferenceNumber:     req.ReferenceNumber,
+	}
+	if req.MaturityInstruction != "" {
+		arg.MaturityInstruction = db.MaturityInstruction(req.MaturityInstruction)
+	}
+	if arg.Principal, err = req.Principal.WithCurrency(linked.CurrencyCode).Numeric(); err != nil {
+		return schemas.FixedDepositTxResult{}, err
+	}
+
+	for i := 0; i < maxAccountNumberRetries; i++ {
+		arg.AccountNumber, err = utils.GenerateAccountNumber()
+		if err != nil {
+			return schemas.FixedDepositTxResult{}, err
+		}
+
+		result, err := transaction.OpenFixedDeposit(ctx, arg)
+		if err == nil {
+			return result, nil
+		}
+		if utils.IsUniqueViolationError(err) && strings.Contains(err.Error(), "accounts_account_number_key") {
+			continue
+		}
+		return schemas.FixedDepositTxResult{}, mapFixedDepositError(err, req.ReferenceNumber)
+	}
+	return schemas.FixedDepositTxResult{}, fmt.Errorf("failed to generate a unique account number after %d attempts", maxAccountNumberRetries)
+}
+
+func (s *fixedDepositService) Get(ctx context.Context, depositNumber string) (db.FixedDeposit, error) {
+	number, err := uuid.Parse(depositNumber)
+	if err != nil {
+		return db.FixedDeposit{}, common.ErrInvalidDepositNumber
+	}
+	deposit, err := s.depositRepo.GetFixedDeposit(ctx, number)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.FixedDeposit{}, common.ErrFixedDepositNotFound
+		}
+		return db.FixedDeposit{}, err
+	}
+	if err := s.authorizeDeposit(ctx, deposit); err != nil {
+		return db.FixedDeposit{}, err
+	}
+	return deposit, nil
+}
+
+func (s *fixedDepositService) ListByLinkedAccount(ctx context.Context, accountID int64,
+	page, pageSize int32) ([]db.FixedDeposit, error) {
+
+	account, err := s.accountRepo.GetAccount(ctx, accountID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil, common.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return s.depositRepo.ListFixedDepositsByLinkedAccount(ctx, db.ListFixedDepositsByLinkedAccountParams{
+		LinkedAccountID: int32(accountID),
+		Limit:           pageSize,
+		Offset:          (page - 1) * pageSize,
+	})
+}
+
+func (s *fixedDepositService) Break(ctx context.Context, depositNumber string) (schemas.FixedDepositTxResult, error) {
+	number, err := uuid.Parse(depositNumber)
+	if err != nil {
+		return schemas.FixedDepositTxResult{}, common.ErrInvalidDepositNumber
+	}
+	if _, err := s.Get(ctx, depositNumber); err != nil {
+		return schemas.FixedDepositTxResult{}, err
+	}
+
+	result, err := transaction.BreakFixedDeposit(ctx, number)
+	if err != nil {
+		return schemas.FixedDepositTxResult{}, mapFixedDepositError(err, "")
+	}
+	return result, nil
+}
+
+// RunMaturity processes the deposits the maturity job would pick up on its next run
+func (s *fixedDepositService) RunMaturity(ctx context.Context) (int, error) {
+	matured, err := transaction.MatureFixedDeposits(ctx, time.Now(), maturityBatchSize)
+	if err != nil {
+		logger.GetLogger().Error("Fixed deposit maturity run failed",
+			zap.Int("matured", matured),
+			zap.Error(err))
+		return matured, err
+	}
+	return matured, nil
+}
+
+// UpsertTerm sets the rates of a term, deposits already open keep the rates they were opened with
+func (s *fixedDepositService) UpsertTerm(ctx context.Context, termMonths int64,
+	req dto.UpsertFixedDepositTermRequest) (db.FixedDepositTerm, error) {
+
+	if termMonths < 1 || termMonths > 120 {
+		return db.FixedDepositTerm{}, common.ErrInvalidTermMonths
+	}
+	arg := db.UpsertFixedDepositTermParams{TermMonths: int32(termMonths), IsActive: true}
+	if req.IsActive != nil {
+		arg.IsActive = *req.IsActive
+	}
+
+	var err error
+	if arg.InterestRate, err = rateNumeric(req.InterestRate, 2); err != nil {
+		return db.FixedDepositTerm{}, err
+	}
+	if arg.EarlyBreakPenaltyPercent, err = rateNumeric(req.EarlyBreakPenaltyPercent, 2); err != nil {
+		return db.FixedDepositTerm{}, err
+	}
+	return s.depositRepo.UpsertFixedDepositTerm(ctx, arg)
+}
+
+func (s *fixedDepositService) ListTerms(ctx context.Context) ([]db.FixedDepositTerm, error) {
+	return s.depositRepo.ListFixedDepositTerms(ctx)
+}
+
+// mapFixedDepositError translates transaction package errors into API errors
+func mapFixedDepositError(err error, referenceNumber string) error {
+	switch {
+	case utils.IsNotFoundError(err):
+		return common.ErrFixedDepositNotFound
+	case errors.Is(err, transaction.ErrFixedDepositNotActive):
+		return common.ErrFixedDepositNotActive
+	case errors.Is(err, transaction.ErrFixedDepositMatured):
+		return common.ErrFixedDepositMatured
+	case errors.Is(err, transaction.ErrFixedDepositLocked):
+		return common.ErrFixedDepositLocked
+	case errors.Is(err, transaction.ErrFixedDepositTerm):
+		return common.ErrFixedDepositTerm
+	case errors.Is(err, transaction.ErrFeeIncomeAccountMissing):
+		return common.ErrFeeIncomeAccountMissing
+	case errors.Is(err, transaction.ErrAmountPrecision):
+		return common.ErrInvalidAmountPrecision
+	case utils.IsCheckViolationError(err) && strings.Contains(err.Error(), "accounts_balance_check"):
+		return common.ErrInsufficientFunds
+	case referenceNumber != "" && utils.IsUniqueViolationError(err):
+		return common.ErrDuplicateTransfer
+	}
+	logger.GetLogger().Error("fixed deposit operation failed", zap.Error(err))
+	return common.ErrTransactionFailed
+}
+
+// authorizeDeposit refuses users who do not own a fixed deposit, which belongs to the owner of its
+// linked account
+func (s *fixedDepositService) authorizeDeposit(ctx context.Context, deposit db.FixedDeposit) error {
+	if _, ok := utils.AuthUserID(ctx); !ok {
+		return nil
+	}
+	account, err := s.accountRepo.GetAccount(ctx, int64(deposit.AccountID))
+	if err != nil {
+		return err
+	}
+	return authorizeAccount(ctx, account)
+}
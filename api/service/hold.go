@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"go.uber.org/zap"
+)
+
+// DefaultHoldExpiry is how long a hold reserves funds when the request does not say otherwise
+const DefaultHoldExpiry = 7 * 24 * time.Hour
+
+type holdService struct {
+	accountRepo interface_repository.AccountRepository
+	holdRepo    interface_repository.HoldRepository
+	stepUp      stepUpGuard
+}
+
+func NewHoldService(accountRepo interface_repository.AccountRepository, holdRepo interface_repository.HoldRepository,
+	currencyRepo interface_repository.CurrencyRepository, twoFactor interface_service.TwoFactorService) interface_service.HoldService {
+	return &holdService{
+		accountRepo: accountRepo,
+		holdRepo:    holdRepo,
+		stepUp:      newStepUpGuard(accountRepo, currencyRepo, twoFactor),
+	}
+}
+
+func (s *holdService) AuthorizeHold(ctx context.Context, req dto.AuthorizeHoldRequest) (schemas.HoldTxResult, error) {
+	if req.AccountID == req.MerchantAccountID {
+		return schemas.HoldTxResult{}, common.ErrSameAccount
+	}
+	if !req.Amount.IsPositive() {
+		return schemas.HoldTxResult{}, common.ErrInvalidAmount
+	}
+	currencyCode := strings.ToUpper(req.CurrencyCode)
+
+	account, err := getTransferAccount(ctx, s.accountRepo, req.AccountID)
+	if err != nil {
+		return schemas.HoldTxResult{}, err
+	}
+	if err := authorizeAccount(ctx, account); err != nil {
+		return schemas.HoldTxResult{}, err
+	}
+	if account.CurrencyCode != currencyCode {
+		return schemas.HoldTxResult{}, common.ErrCurrencyMismatch
+	}
+	merchant, err := getTransferAccount(ctx, s.accountRepo, req.MerchantAccountID)
+	if err != nil {
+		return schemas.HoldTxResult{}, err
+	}
+	// Confirmed at authorization, the merchant captures without the account holder
+	transfer := accountTransfer(account, merchant, req.Amount.WithCurrency(currencyCode))
+	if err := s.stepUp.check(ctx, req.TOTPCode, transfer); err != nil {
+		return schemas.HoldTxResult{}, err
+	}
+
+	amount, err := req.Amount.WithCurrency(currencyCode).Numeric()
+	if err != nil {
+		return schemas.HoldTxResult{}, err
+	}
+	expiry := DefaultHoldExpiry
+	if req.ExpiresInMinutes > 0 {
+		expiry = time.Duration(req.ExpiresInMinutes) * time.Minute
+	}
+
+	result, err := transaction.AuthorizeHold(ctx, schemas.AuthorizeHoldParams{
+		AccountID:         int32(req.AccountID),
+		MerchantAccountID: int32(req.MerchantAccountID),
+		Amount:            amount,
+		CurrencyCode:      currencyCode,
+		Description:       req.Description,
+		ExpiresAt:         time.Now().Add(expiry),
+		ReferenceNumber:   req.ReferenceNumber,
+	})
+	if err != nil {
+		return schemas.HoldTxResult{}, mapHoldError(err, req.ReferenceNumber)
+	}
+	return result, nil
+}
+
+func (s *holdService) GetHold(ctx context.Context, holdNumber string) (db.Hold, error) {
+	number, err := uuid.Parse(holdNumber)
+	if err != nil {
+		return db.Hold{}, common.ErrInvalidHoldNumber
+	}
+	hold, err := s.holdRepo.GetHold(ctx, number)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.Hold{}, common.ErrHoldNotFound
+		}
+		return db.Hold{}, err
+	}
+	if err := s.authorizeHold(ctx, hold, false); err != nil {
+		return db.Hold{}, err
+	}
+	return hold, nil
+}
+
+func (s *holdService) ListOpenHolds(ctx context.Context, accountID int64) ([]db.Hold, error) {
+	account, err := s.accountRepo.GetAccount(ctx, accountID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil, common.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return s.holdRepo.ListOpenHoldsByAccount(ctx, accountID)
+}
+
+func (s *holdService) CaptureHold(ctx context.Context, holdNumber string,
+	req dto.CaptureHoldRequest) (schemas.HoldTxResult, error) {
+
+	number, err := uuid.Parse(holdNumber)
+	if err != nil {
+		return schemas.HoldTxResult{}, common.ErrInvalidHoldNumber
+	}
+	if req.Amount != nil && !req.Amount.IsPositive() {
+		return schemas.HoldTxResult{}, common.ErrInvalidAmount
+	}
+	// Only the merchant captures, the account holder would be paying itself
+	if err := s.authorizeHoldNumber(ctx, number, true); err != nil {
+		return schemas.HoldTxResult{}, err
+	}
+
+	// An omitted amount captures the full hold
+	amount := pgtype.Numeric{Status: pgtype.Null}
+	if req.Amount != nil {
+		if amount, err = req.Amount.Numeric(); err != nil {
+			return schemas.HoldTxResult{}, err
+		}
+	}
+
+	result, err := transaction.CaptureHold(ctx, schemas.CaptureHoldParams{
+		HoldNumber: number,
+		Amount:     amount,
+	})
+	if err != nil {
+		return schemas.HoldTxResult{}, mapHoldError(err, "")
+	}
+	return result, nil
+}
+
+func (s *holdService) ReleaseHold(ctx context.Context, holdNumber string) (schemas.HoldTxResult, error) {
+	number, err := uuid.Parse(holdNumber)
+	if err != nil {
+		return schemas.HoldTxResult{}, common.ErrInvalidHoldNumber
+	}
+	if err := s.authorizeHoldNumber(ctx, number, false); err != nil {
+		return schemas.HoldTxResult{}, err
+	}
+
+	result, err := transaction.ReleaseHold(ctx, number)
+	if err != nil {
+		return schemas.HoldTxResult{}, mapHoldError(err, "")
+	}
+	return result, nil
+}
+
+// authorizeHoldNumber looks a hold up for authorizeHold
+func (s *holdService) authorizeHoldNumber(ctx context.Context, holdNumber uuid.UUID, merchantOnly bool) error {
+	if _, ok := utils.AuthUserID(ctx); !ok {
+		return nil
+	}
+	hold, err := s.holdRepo.GetHold(ctx, holdNumber)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return common.ErrHoldNotFound
+		}
+		return err
+	}
+	return s.authorizeHold(ctx, hold, merchantOnly)
+}
+
+// authorizeHold refuses users who own neither the held account nor the merchant account of a hold,
+// or who do not own the merchant account when merchantOnly is set
+func (s *holdService) authorizeHold(ctx context.Context, hold db.Hold, merchantOnly bool) error {
+	if _, ok := utils.AuthUserID(ctx); !ok {
+		return nil
+	}
+	merchant, err := s.accountRepo.GetAccount(ctx, int64(hold.MerchantAccountID))
+	if err != nil {
+		return err
+	}
+	if authorizeAccount(ctx, merchant) == nil {
+		return nil
+	}
+	if merchantOnly {
+		return common.ErrForbidden
+	}
+	account, err := s.accountRepo.GetAccount(ctx, int64(hold.AccountID))
+	if err != nil {
+		return err
+	}
+	return authorizeAccount(ctx, account)
+}
+
+// mapHoldError translates hold transaction failures into API errors
+func mapHoldError(err error, referenceNumber string) error {
+	switch {
+	case utils.IsNotFoundError(err):
+		return common.ErrHoldNotFound
+	case errors.Is(err, transaction.ErrHoldNotOpen):
+		return common.ErrHoldNotOpen
+	case errors.Is(err, transaction.ErrHoldExpired):
+		return common.ErrHoldExpired
+	case errors.Is(err, transaction.ErrCaptureExceedsHold):
+		return common.ErrCaptureExceedsHold
+	case errors.Is(err, transaction.ErrInvalidHoldAmount):
+		return common.ErrInvalidAmount
+	case errors.Is(err, transaction.ErrAmountPrecision):
+		return common.ErrInvalidAmountPrecision
+	case errors.Is(err, transaction.ErrCurrencyMismatch):
+		return common.ErrCurrencyMismatch
+	case errors.Is(err, transaction.ErrExchangeRateUnavailable):
+		return common.ErrExchangeRateUnavailable
+	case errors.Is(err, transaction.ErrFixedDepositLocked):
+		return common.ErrFixedDepositLocked
+	case utils.IsCheckViolationError(err) && strings.Contains(err.Error(), "accounts_balance_check"):
+		return common.ErrInsufficientFunds
+	case referenceNumber != "" && utils.IsUniqueViolationError(err):
+		return common.ErrDuplicateTransfer
+	}
+	logger.GetLogger().Error("hold operation failed", zap.Error(err))
+	return common.ErrTransactionFailed
+}
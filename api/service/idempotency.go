@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/common"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+const (
+	// idempotencyKeyTTL is how long a stored response can be replayed
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyPendingTimeout is how long a key can stay reserved without a response before the
+	// request is taken for crashed, well past the request timeout
+	idempotencyPendingTimeout = 10 * time.Minute
+)
+
+type idempotencyService struct {
+	repo interface_repository.IdempotencyRepository
+}
+
+func NewIdempotencyService(repo interface_repository.IdempotencyRepository) interface_service.IdempotencyService {
+	return &idempotencyService{repo: repo}
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, clientID, key, method, path, requestHash string) (*db.IdempotencyKey, error) {
+	//? Replays of completed requests are answered from the cache without a round trip to Postgres
+	if cached, ok := s.repo.GetCachedIdempotencyKey(ctx, clientID, key); ok && time.Now().Before(cached.ExpiresAt) {
+		if cached.RequestHash != requestHash {
+			return nil, common.ErrIdempotencyKeyMismatch
+		}
+		return &cached, nil
+	}
+
+	arg := db.CreateIdempotencyKeyParams{
+		ClientID:       clientID,
+		IdempotencyKey: key,
+		RequestMethod:  method,
+		RequestPath:    path,
+		RequestHash:    requestHash,
+		ExpiresAt:      time.Now().Add(idempotencyKeyTTL),
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		_, err := s.repo.CreateIdempotencyKey(ctx, arg)
+		if err == nil {
+			return nil, nil
+		}
+		if !utils.IsNotFoundError(err) {
+			return nil, err
+		}
+
+		//? The key is taken, decide between replay, conflict and expiry
+		existing, err := s.repo.GetIdempotencyKey(ctx, clientID, key)
+		if err != nil {
+			if utils.IsNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+		if time.Now().After(existing.ExpiresAt) {
+			if err := s.repo.DeleteIdempotencyKey(ctx, clientID, key); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if existing.RequestHash != requestHash {
+			return nil, common.ErrIdempotencyKeyMismatch
+		}
+		if !existing.CompletedAt.Valid {
+			//? A request that crashed never completes nor releases its key, a retry takes it over
+			if time.Since(existing.CreatedAt) > idempotencyPendingTimeout {
+				deleted, err := s.repo.DeleteStaleIdempotencyKey(ctx, clientID, key, time.Now().Add(-idempotencyPendingTimeout))
+				if err != nil {
+					return nil, err
+				}
+				if deleted {
+					continue
+				}
+			}
+			return nil, common.ErrIdempotencyKeyInProgress
+		}
+		return &existing, nil
+	}
+	return nil, common.ErrIdempotencyKeyInProgress
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, clientID, key string, status int, body []byte) error {
+	responseBody := pgtype.JSONB{Status: pgtype.Null}
+	if json.Valid(body) {
+		responseBody = pgtype.JSONB{Bytes: body, Status: pgtype.Present}
+	}
+
+	_, err := s.repo.CompleteIdempotencyKey(ctx, db.CompleteIdempotencyKeyParams{
+		ResponseStatus: sql.NullInt32{Int32: int32(status), Valid: true},
+		ResponseBody:   responseBody,
+		ClientID:       clientID,
+		IdempotencyKey: key,
+	})
+	return err
+}
+
+func (s *idempotencyService) Release(ctx context.Context, clientID, key string) error {
+	return s.repo.DeleteIdempotencyKey(ctx, clientID, key)
+}
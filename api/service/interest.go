@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"go.uber.org/zap"
+)
+
+type interestService struct {
+	interestRepo interface_repository.InterestRepository
+}
+
+func NewInterestService(interestRepo interface_repository.InterestRepository) interface_service.InterestService {
+	return &interestService{interestRepo: interestRepo}
+}
+
+func (s *interestService) UpsertSettings(ctx context.Context, accountType string,
+	req dto.UpsertInterestSettingsRequest) (db.InterestSetting, error) {
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	settings, err := s.interestRepo.UpsertInterestSettings(ctx, db.UpsertInterestSettingsParams{
+		AccountType:        accountType,
+		DayCountConvention: db.DayCountConvention(req.DayCountConvention),
+		PostingPeriod:      db.InterestPostingPeriod(req.PostingPeriod),
+		IsActive:           isActive,
+	})
+	if err != nil {
+		if utils.IsForeignKeyError(err) {
+			return db.InterestSetting{}, common.ErrUnknownAccountType
+		}
+		return db.InterestSetting{}, err
+	}
+	return settings, nil
+}
+
+func (s *interestService) GetSettings(ctx context.Context, accountType string) (db.InterestSetting, error) {
+	settings, err := s.interestRepo.GetInterestSettings(ctx, accountType)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.InterestSetting{}, common.ErrInterestSettingsNotFound
+		}
+		return db.InterestSetting{}, err
+	}
+	return settings, nil
+}
+
+func (s *interestService) ListSettings(ctx context.Context) ([]db.InterestSetting, error) {
+	return s.interestRepo.ListInterestSettings(ctx)
+}
+
+func (s *interestService) ListAccruals(ctx context.Context, accountID int32,
+	page, pageSize int32) ([]db.InterestAccrual, error) {
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return s.interestRepo.ListInterestAccruals(ctx, db.ListInterestAccrualsParams{
+		AccountID: accountID,
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+	})
+}
+
+// Run accrues up to the requested day, or as far as one run catches up. Today is refused because
+// its end of day balances are not known yet.
+func (s *interestService) Run(ctx context.Context, req dto.RunInterestRequest) (schemas.InterestRunResult, time.Time, error) {
+	today := interest.Date(time.Now())
+	through := today.AddDate(0, 0, -1)
+	if req.Through != "" {
+		day, err := time.Parse(time.DateOnly, req.Through)
+		if err != nil || !day.Before(today) {
+			return schemas.InterestRunResult{}, time.Time{}, common.ErrInvalidInterestDate
+		}
+		through = day
+	}
+
+	result, err := transaction.RunInterest(ctx, through)
+	if err != nil {
+		logger.GetLogger().Error("Interest run failed",
+			zap.String("through", through.Format(time.DateOnly)),
+			zap.Error(err))
+		return result, through, err
+	}
+	return result, result.Through, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"go.uber.org/zap"
+)
+
+type reconciliationService struct {
+	reconciliationRepo interface_repository.ReconciliationRepository
+}
+
+func NewReconciliationService(reconciliationRepo interface_repository.ReconciliationRepository) interface_service.ReconciliationService {
+	return &reconciliationService{reconciliationRepo: reconciliationRepo}
+}
+
+func (s *reconciliationService) Reconcile(ctx context.Context, req dto.RunReconciliationRequest) (schemas.ReconciliationResult, error) {
+	accountIDs := make([]int32, 0, len(req.AccountIDs))
+	for _, accountID := range req.AccountIDs {
+		accountIDs = append(accountIDs, int32(accountID))
+	}
+
+	result, err := transaction.RunReconciliation(ctx, accountIDs)
+	if err != nil {
+		logger.GetLogger().Error("Reconciliation run failed",
+			zap.Int("accounts", len(accountIDs)),
+			zap.Error(err))
+		return result, err
+	}
+	if result.Run.DiscrepancyCount > 0 {
+		logger.GetLogger().Warn("Reconciliation found ledger discrepancies",
+			zap.String("run_number", result.Run.RunNumber.String()),
+			zap.Int32("discrepancies", result.Run.DiscrepancyCount))
+	}
+	return result, nil
+}
+
+func (s *reconciliationService) GetRun(ctx context.Context, runNumber string) (db.ReconciliationRun, error) {
+	number, err := uuid.Parse(runNumber)
+	if err != nil {
+		return db.ReconciliationRun{}, common.ErrInvalidRunNumber
+	}
+	run, err := s.reconciliationRepo.GetReconciliationRun(ctx, number)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.ReconciliationRun{}, common.ErrReconciliationRunNotFound
+		}
+		return db.ReconciliationRun{}, err
+	}
+	return run, nil
+}
+
+func (s *reconciliationService) ListRuns(ctx context.Context, page, pageSize int32) ([]db.ReconciliationRun, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return s.reconciliationRepo.ListReconciliationRuns(ctx, db.ListReconciliationRunsParams{
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	})
+}
+
+func (s *reconciliationService) ListDiscrepancies(ctx context.Context, runNumber string,
+	page, pageSize int32) ([]db.LedgerDiscrepancy, error) {
+
+	run, err := s.GetRun(ctx, runNumber)
+	if err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return s.reconciliationRepo.ListLedgerDiscrepancies(ctx, db.ListLedgerDiscrepanciesParams{
+		RunID:  run.RunID,
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	})
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/signing"
+	"go.uber.org/zap"
+)
+
+type requestSigningService struct {
+	nonceRepo interface_repository.NonceRepository
+	// signer is nil while request signing is disabled
+	signer *signing.Signer
+}
+
+func NewRequestSigningService(nonceRepo interface_repository.NonceRepository, signer *signing.Signer) interface_service.RequestSigningService {
+	return &requestSigningService{nonceRepo: nonceRepo, signer: signer}
+}
+
+// Verify checks the signature before the nonce, so unsigned requests cannot use up the nonces of a
+// client. Nonces are remembered for twice the clock skew, as long as a timestamp stays acceptable.
+func (s *requestSigningService) Verify(ctx context.Context, key db.ApiKey, req signing.Request, signature string) error {
+	if s.signer == nil {
+		return common.ErrRequestSigningDisabled
+	}
+	if err := s.signer.Verify(key.KeyPrefix, req, signature); err != nil {
+		return err
+	}
+
+	claimed, err := s.nonceRepo.ClaimNonce(ctx, key.KeyPrefix, req.Nonce, 2*s.signer.MaxClockSkew())
+	if err != nil {
+		logger.GetLogger().Error("Failed to record request nonce", zap.Error(err))
+		return fmt.Errorf("failed to record request nonce: %w", err)
+	}
+	if !claimed {
+		logger.GetLogger().Warn("Signed request replayed",
+			zap.Int32("api_key_id", key.ApiKeyID),
+			zap.String("nonce", req.Nonce))
+		return common.ErrReplayedNonce
+	}
+	return nil
+}
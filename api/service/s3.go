@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/pkg/s3"
+	"go.uber.org/zap"
+)
+
+type s3StorageService struct {
+	client *s3.S3Service
+	folder string
+}
+
+// NewS3StorageService adapts pkg/s3 to the multipart-oriented S3Service interface; files are stored under folder
+func NewS3StorageService(client *s3.S3Service, folder string) interface_service.S3Service {
+	return &s3StorageService{client: client, folder: strings.Trim(folder, "/")}
+}
+
+func (s *s3StorageService) UploadFile(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	key := fmt.Sprintf("%s/%s%s", s.folder, uuid.New().String(), strings.ToLower(filepath.Ext(file.Filename)))
+	url, err := s.client.UploadFile(ctx, key, data, file.Header.Get("Content-Type"))
+	if err != nil {
+		logger.GetLogger().Error("failed to upload file to S3", zap.String("key", key), zap.Error(err))
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	return url, nil
+}
+
+func (s *s3StorageService) UploadData(ctx context.Context, name string, data []byte, contentType string) (string, error) {
+	key := fmt.Sprintf("%s/%s", s.folder, name)
+	url, err := s.client.UploadFile(ctx, key, data, contentType)
+	if err != nil {
+		logger.GetLogger().Error("failed to upload file to S3", zap.String("key", key), zap.Error(err))
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	return url, nil
+}
+
+func (s *s3StorageService) DownloadFile(ctx context.Context, fileURL string) ([]byte, error) {
+	key, err := s3Key(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.DownloadFile(ctx, key)
+}
+
+func (s *s3StorageService) DeleteFile(ctx context.Context, fileURL string) error {
+	key, err := s3Key(fileURL)
+	if err != nil {
+		return err
+	}
+	return s.client.DeleteFile(ctx, key)
+}
+
+// s3Key extracts the object key from the URL UploadFile returned
+func s3Key(fileURL string) (string, error) {
+	idx := strings.Index(fileURL, ".amazonaws.com/")
+	if idx == -1 {
+		return "", fmt.Errorf("not an S3 file URL: %s", fileURL)
+	}
+	return fileURL[idx+len(".amazonaws.com/"):], nil
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/schedule"
+	"go.uber.org/zap"
+)
+
+const defaultScheduledTransferRetries = 3
+
+type scheduledTransferService struct {
+	accountRepo   interface_repository.AccountRepository
+	scheduledRepo interface_repository.ScheduledTransferRepository
+	stepUp        stepUpGuard
+}
+
+func NewScheduledTransferService(accountRepo interface_repository.AccountRepository,
+	scheduledRepo interface_repository.ScheduledTransferRepository, currencyRepo interface_repository.CurrencyRepository,
+	twoFactor interface_service.TwoFactorService) interface_service.ScheduledTransferService {
+	return &scheduledTransferService{
+		accountRepo:   accountRepo,
+		scheduledRepo: scheduledRepo,
+		stepUp:        newStepUpGuard(accountRepo, currencyRepo, twoFactor),
+	}
+}
+
+func (s *scheduledTransferService) CreateScheduledTransfer(ctx context.Context,
+	req dto.CreateScheduledTransferRequest) (db.ScheduledTransfer, error) {
+
+	if req.FromAccountID == req.ToAccountID {
+		return db.ScheduledTransfer{}, common.ErrSameAccount
+	}
+	if !req.Amount.IsPositive() {
+		return db.ScheduledTransfer{}, common.ErrInvalidAmount
+	}
+	currencyCode := strings.ToUpper(req.CurrencyCode)
+
+	sender, err := getTransferAccount(ctx, s.accountRepo, req.FromAccountID)
+	if err != nil {
+		return db.ScheduledTransfer{}, err
+	}
+	if err := authorizeAccount(ctx, sender); err != nil {
+		return db.ScheduledTransfer{}, err
+	}
+	if sender.CurrencyCode != currencyCode {
+		return db.ScheduledTransfer{}, common.ErrCurrencyMismatch
+	}
+	receiver, err := getTransferAccount(ctx, s.accountRepo, req.ToAccountID)
+	if err != nil {
+		return db.ScheduledTransfer{}, err
+	}
+	// Confirmed once for every run, each run moves the same amount
+	transfer := accountTransfer(sender, receiver, req.Amount.WithCurrency(currencyCode))
+	if err := s.stepUp.check(ctx, req.TOTPCode, transfer); err != nil {
+		return db.ScheduledTransfer{}, err
+	}
+
+	plan := schedule.Schedule{
+		Frequency: strings.ToUpper(req.Frequency),
+		CronExpr:  strings.TrimSpace(req.CronExpression),
+		Start:     req.StartAt.UTC(),
+	}
+	if plan.Frequency != schedule.FrequencyCron {
+		plan.CronExpr = ""
+	}
+	firstRun, err := s.firstRun(plan, req.EndAt)
+	if err != nil {
+		return db.ScheduledTransfer{}, err
+	}
+
+	amount, err := req.Amount.WithCurrency(currencyCode).Numeric()
+	if err != nil {
+		return db.ScheduledTransfer{}, err
+	}
+	// Checked now, an amount the currency cannot hold would fail every run
+	if err := transaction.CheckAmount(ctx, currencyCode, amount); err != nil {
+		if errors.Is(err, transaction.ErrAmountPrecision) {
+			return db.ScheduledTransfer{}, common.ErrInvalidAmountPrecision
+		}
+		return db.ScheduledTransfer{}, err
+	}
+	maxRetries := int32(defaultScheduledTransferRetries)
+	if req.MaxRetries != nil {
+		maxRetries = *req.MaxRetries
+	}
+	endAt := sql.NullTime{}
+	if req.EndAt != nil {
+		endAt = sql.NullTime{Time: req.EndAt.UTC(), Valid: true}
+	}
+
+	// The schedule keeps the owner of the sender account, runs are cancelled once it changes hands
+	scheduled, err := s.scheduledRepo.CreateScheduledTransfer(ctx, db.CreateScheduledTransferParams{
+		FromAccountID:  int32(req.FromAccountID),
+		ToAccountID:    int32(req.ToAccountID),
+		Amount:         amount,
+		CurrencyCode:   currencyCode,
+		Description:    sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Frequency:      db.ScheduleFrequency(plan.Frequency),
+		CronExpression: sql.NullString{String: plan.CronExpr, Valid: plan.CronExpr != ""},
+		StartAt:        plan.Start,
+		EndAt:          endAt,
+		NextRunAt:      firstRun,
+		DueAt:          firstRun,
+		MaxRetries:     maxRetries,
+		UserID:         sql.NullInt32{Int32: sender.UserID, Valid: true},
+	})
+	if err != nil {
+		logger.GetLogger().Error("Failed to create scheduled transfer",
+			zap.Int64("from_account_id", req.FromAccountID),
+			zap.Error(err))
+		return db.ScheduledTransfer{}, fmt.Errorf("failed to create scheduled transfer: %w", err)
+	}
+	return scheduled, nil
+}
+
+// firstRun validates the schedule and returns its first occurrence
+func (s *scheduledTransferService) firstRun(plan schedule.Schedule, endAt *time.Time) (time.Time, error) {
+	if plan.Start.Before(time.Now().Add(-time.Minute)) {
+		return time.Time{}, fmt.Errorf("%w: start_at is in the past", common.ErrInvalidSchedule)
+	}
+	if err := plan.Validate(); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", common.ErrInvalidSchedule, err)
+	}
+	first, ok, err := plan.First()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", common.ErrInvalidSchedule, err)
+	}
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: the schedule never runs", common.ErrInvalidSchedule)
+	}
+	if endAt != nil && first.After(*endAt) {
+		return time.Time{}, fmt.Errorf("%w: end_at is before the first run", common.ErrInvalidSchedule)
+	}
+	return first, nil
+}
+
+func (s *scheduledTransferService) GetScheduledTransfer(ctx context.Context, scheduleNumber string) (db.ScheduledTransfer, error) {
+	number, err := uuid.Parse(scheduleNumber)
+	if err != nil {
+		return db.ScheduledTransfer{}, common.ErrInvalidScheduleNumber
+	}
+	scheduled, err := s.scheduledRepo.GetScheduledTransfer(ctx, number)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.ScheduledTransfer{}, common.ErrScheduledTransferNotFound
+		}
+		return db.ScheduledTransfer{}, err
+	}
+	if err := s.authorizeFromAccount(ctx, scheduled); err != nil {
+		return db.ScheduledTransfer{}, err
+	}
+	return scheduled, nil
+}
+
+func (s *scheduledTransferService) ListScheduledTransfers(ctx context.Context, accountID int64) ([]db.ScheduledTransfer, error) {
+	account, err := s.accountRepo.GetAccount(ctx, accountID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil, common.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	return s.scheduledRepo.ListScheduledTransfersByAccount(ctx, accountID)
+}
+
+func (s *scheduledTransferService) ListExecutions(ctx context.Context, scheduleNumber string,
+	page, pageSize int32) ([]db.ScheduledTransferExecution, error) {
+
+	scheduled, err := s.GetScheduledTransfer(ctx, scheduleNumber)
+	if err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return s.scheduledRepo.ListScheduledTransferExecutions(ctx, db.ListScheduledTransferExecutionsParams{
+		ScheduledTransferID: scheduled.ScheduledTransferID,
+		Limit:               pageSize,
+		Offset:              (page - 1) * pageSize,
+	})
+}
+
+func (s *scheduledTransferService) UpdateStatus(ctx context.Context, scheduleNumber string,
+	status db.ScheduledTransferStatus) (db.ScheduledTransfer, error) {
+
+	number, err := uuid.Parse(scheduleNumber)
+	if err != nil {
+		return db.ScheduledTransfer{}, common.ErrInvalidScheduleNumber
+	}
+	if _, err := s.GetScheduledTransfer(ctx, scheduleNumber); err != nil {
+		return db.ScheduledTransfer{}, err
+	}
+
+	scheduled, err := transaction.UpdateScheduledTransferStatus(ctx, number, status)
+	if err != nil {
+		switch {
+		case utils.IsNotFoundError(err):
+			return db.ScheduledTransfer{}, common.ErrScheduledTransferNotFound
+		case errors.Is(err, transaction.ErrInvalidScheduleTransition):
+			return db.ScheduledTransfer{}, common.ErrInvalidScheduleTransition
+		}
+		logger.GetLogger().Error("Failed to update scheduled transfer status",
+			zap.String("schedule_number", scheduleNumber),
+			zap.Error(err))
+		return db.ScheduledTransfer{}, err
+	}
+	return scheduled, nil
+}
+
+// authorizeFromAccount refuses users who do not own the account a scheduled transfer is paid from
+func (s *scheduledTransferService) authorizeFromAccount(ctx context.Context, scheduled db.ScheduledTransfer) error {
+	if _, ok := utils.AuthUserID(ctx); !ok {
+		return nil
+	}
+	account, err := s.accountRepo.GetAccount(ctx, int64(scheduled.FromAccountID))
+	if err != nil {
+		return err
+	}
+	return authorizeAccount(ctx, account)
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/riad/banksystemendtoend/util/statement"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxInlineStatementEntries is the most entries a statement may list to be downloaded directly,
+	// longer statements are generated by the statement job
+	MaxInlineStatementEntries = 1000
+
+	statementBatchSize   = 10
+	maxStatementAttempts = 3
+	// statementClaimTimeout is how long a claimed statement may stay PROCESSING before another run
+	// takes it over
+	statementClaimTimeout = 10 * time.Minute
+)
+
+type statementService struct {
+	accountRepo   interface_repository.AccountRepository
+	statementRepo interface_repository.StatementRepository
+	storage       interface_service.S3Service
+}
+
+func NewStatementService(accountRepo interface_repository.AccountRepository,
+	statementRepo interface_repository.StatementRepository,
+	storage interface_service.S3Service) interface_service.StatementService {
+	return &statementService{accountRepo: accountRepo, statementRepo: statementRepo, storage: storage}
+}
+
+func (s *statementService) Export(ctx context.Context, accountID int64,
+	query dto.StatementPeriodQuery) (statement.File, error) {
+
+	format, from, to, err := parseStatementPeriod(query.Format, query.From, query.To)
+	if err != nil {
+		return statement.File{}, err
+	}
+	if err := s.ensureAccount(ctx, accountID); err != nil {
+		return statement.File{}, err
+	}
+
+	fromTime, toTime := transaction.StatementBounds(from, to)
+	count, err := s.statementRepo.CountStatementEntries(ctx, db.CountStatementEntriesParams{
+		AccountID: sql.NullInt32{Int32: int32(accountID), Valid: true},
+		FromTime:  fromTime,
+		ToTime:    toTime,
+	})
+	if err != nil {
+		return statement.File{}, err
+	}
+	if count > MaxInlineStatementEntries {
+		return statement.File{}, common.ErrStatementTooLarge
+	}
+
+	built, err := transaction.BuildStatement(ctx, int32(accountID), from, to)
+	if err != nil {
+		return statement.File{}, err
+	}
+	return statement.Render(format, built)
+}
+
+func (s *statementService) Request(ctx context.Context, req dto.CreateStatementRequest) (db.Statement, error) {
+	format, from, to, err := parseStatementPeriod(req.Format, req.From, req.To)
+	if err != nil {
+		return db.Statement{}, err
+	}
+	if err := s.ensureAccount(ctx, req.AccountID); err != nil {
+		return db.Statement{}, err
+	}
+
+	return s.statementRepo.CreateStatement(ctx, db.CreateStatementParams{
+		AccountID:   int32(req.AccountID),
+		Format:      format,
+		PeriodStart: from,
+		PeriodEnd:   to,
+	})
+}
+
+func (s *statementService) Get(ctx context.Context, statementNumber string) (db.Statement, error) {
+	number, err := uuid.Parse(statementNumber)
+	if err != nil {
+		return db.Statement{}, common.ErrInvalidStatementNumber
+	}
+	stmt, err := s.statementRepo.GetStatement(ctx, number)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.Statement{}, common.ErrStatementNotFound
+		}
+		return db.Statement{}, err
+	}
+	if err := s.ensureAccount(ctx, int64(stmt.AccountID)); err != nil {
+		return db.Statement{}, err
+	}
+	return stmt, nil
+}
+
+func (s *statementService) ListByAccount(ctx context.Context, accountID int64,
+	page, pageSize int32) ([]db.Statement, error) {
+
+	if err := s.ensureAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	return s.statementRepo.ListStatementsByAccount(ctx, db.ListStatementsByAccountParams{
+		AccountID: int32(accountID),
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+	})
+}
+
+func (s *statementService) Download(ctx context.Context, statementNumber string) (statement.File, error) {
+	stmt, err := s.Get(ctx, statementNumber)
+	if err != nil {
+		return statement.File{}, err
+	}
+	if stmt.Status != db.StatementStatusCOMPLETED {
+		return statement.File{}, common.ErrStatementNotReady
+	}
+
+	data, err := s.storage.DownloadFile(ctx, stmt.FileUrl.String)
+	if err != nil {
+		return statement.File{}, fmt.Errorf("failed to download statement: %w", err)
+	}
+	return statement.File{
+		Name:        statementFileName(stmt),
+		ContentType: statement.ContentType(stmt.Format),
+		Data:        data,
+	}, nil
+}
+
+// ProcessPending generates a batch of queued statements. A statement that fails is picked up again
+// once its claim times out, after maxStatementAttempts it is marked FAILED.
+func (s *statementService) ProcessPending(ctx context.Context) error {
+	pending, err := s.statementRepo.ClaimPendingStatements(ctx, db.ClaimPendingStatementsParams{
+		StaleBefore: time.Now().Add(-statementClaimTimeout),
+		BatchSize:   statementBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim pending statements: %w", err)
+	}
+
+	for _, stmt := range pending {
+		if err := s.generate(ctx, stmt); err != nil {
+			logger.GetLogger().Warn("Statement generation failed",
+				zap.String("statement_number", stmt.StatementNumber.String()),
+				zap.Int32("attempt", stmt.Attempts),
+				zap.Error(err))
+
+			if stmt.Attempts < maxStatementAttempts {
+				continue
+			}
+			if _, err := s.statementRepo.FailStatement(ctx, db.FailStatementParams{
+				StatementID:   stmt.StatementID,
+				FailureReason: sql.NullString{String: err.Error(), Valid: true},
+			}); err != nil {
+				return fmt.Errorf("failed to mark statement %s as failed: %w", stmt.StatementNumber, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *statementService) generate(ctx context.Context, stmt db.Statement) error {
+	built, err := transaction.BuildStatement(ctx, stmt.AccountID, stmt.PeriodStart, stmt.PeriodEnd)
+	if err != nil {
+		return err
+	}
+	file, err := statement.Render(stmt.Format, built)
+	if err != nil {
+		return err
+	}
+	url, err := s.storage.UploadData(ctx, statementFileName(stmt), file.Data, file.ContentType)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.statementRepo.CompleteStatement(ctx, db.CompleteStatementParams{
+		StatementID: stmt.StatementID,
+		EntryCount:  sql.NullInt32{Int32: int32(len(built.Lines)), Valid: true},
+		FileUrl:     sql.NullString{String: url, Valid: true},
+	})
+	return err
+}
+
+// ensureAccount checks that an account exists and belongs to the user the request was made by
+func (s *statementService) ensureAccount(ctx context.Context, accountID int64) error {
+	account, err := s.accountRepo.GetAccount(ctx, accountID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return common.ErrAccountNotFound
+		}
+		return err
+	}
+	return authorizeAccount(ctx, account)
+}
+
+// parseStatementPeriod validates the format and the inclusive period of a statement request
+func parseStatementPeriod(format, from, to string) (db.StatementFormat, time.Time, time.Time, error) {
+	parsedFormat, err := statement.ParseFormat(format)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, common.ErrUnsupportedStatementFormat
+	}
+
+	start, err := time.Parse(time.DateOnly, from)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, common.ErrInvalidStatementPeriod
+	}
+	end, err := time.Parse(time.DateOnly, to)
+	if err != nil || end.Before(start) || end.After(interest.Date(time.Now())) {
+		return "", time.Time{}, time.Time{}, common.ErrInvalidStatementPeriod
+	}
+	return parsedFormat, start, end, nil
+}
+
+// statementFileName names a stored statement after its statement number
+func statementFileName(stmt db.Statement) string {
+	return statement.FileName(stmt.Format, "statement-"+stmt.StatementNumber.String())
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// StepUpThresholdEnv overrides the amount, in the base currency, from which moving money needs a
+// fresh two-factor code
+const StepUpThresholdEnv = "TRANSFER_STEP_UP_THRESHOLD"
+
+var defaultStepUpThreshold = decimal.NewFromInt(10000)
+
+// stepUpGuard asks for a fresh two-factor code before money leaves a user's accounts in amounts of
+// at least the step-up threshold, or towards an account of another user it was never sent to.
+// Transfers, scheduled transfers, batches, holds and fixed deposits all go through it.
+type stepUpGuard struct {
+	accountRepo  interface_repository.AccountRepository
+	currencyRepo interface_repository.CurrencyRepository
+	twoFactor    interface_service.TwoFactorService
+	// threshold is compared with the amount converted to the base currency
+	threshold decimal.Decimal
+}
+
+// stepUpTransfer is one movement of money checked by the guard
+type stepUpTransfer struct {
+	senderUserID      int32
+	receiverUserID    int32
+	receiverAccountID int64
+	amount            money.Money
+}
+
+// accountTransfer describes amount moving from sender to receiver
+func accountTransfer(sender, receiver db.Account, amount money.Money) stepUpTransfer {
+	return stepUpTransfer{
+		senderUserID:      sender.UserID,
+		receiverUserID:    receiver.UserID,
+		receiverAccountID: int64(receiver.AccountID),
+		amount:            amount,
+	}
+}
+
+func newStepUpGuard(accountRepo interface_repository.AccountRepository, currencyRepo interface_repository.CurrencyRepository,
+	twoFactor interface_service.TwoFactorService) stepUpGuard {
+	return stepUpGuard{
+		accountRepo:  accountRepo,
+		currencyRepo: currencyRepo,
+		twoFactor:    twoFactor,
+		threshold:    stepUpThresholdFromEnv(),
+	}
+}
+
+// check verifies code once when any of transfers needs a step-up, codes are single use so a batch
+// is confirmed by one code. Requests without an access token, such as those of background jobs,
+// have no second factor to ask for.
+func (g stepUpGuard) check(ctx context.Context, code string, transfers ...stepUpTransfer) error {
+	if _, ok := utils.AuthUserID(ctx); !ok {
+		return nil
+	}
+	rates := make(map[string]decimal.Decimal)
+	for _, transfer := range transfers {
+		required, err := g.required(ctx, transfer, rates)
+		if err != nil {
+			return err
+		}
+		if required {
+			return g.verify(ctx, code)
+		}
+	}
+	return nil
+}
+
+// verify checks code against the second factor of the authenticated user, for requests that always
+// need a step-up
+func (g stepUpGuard) verify(ctx context.Context, code string) error {
+	userID, ok := utils.AuthUserID(ctx)
+	if !ok {
+		return nil
+	}
+	if code == "" {
+		enabled, err := g.twoFactor.Enabled(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			return common.ErrTwoFactorSetupRequired
+		}
+		return common.ErrStepUpRequired
+	}
+	if err := g.twoFactor.Verify(ctx, userID, code); err != nil {
+		if errors.Is(err, common.ErrTwoFactorNotEnabled) {
+			return common.ErrTwoFactorSetupRequired
+		}
+		return err
+	}
+	return nil
+}
+
+// required reports whether transfer needs a step-up, rates caches the exchange rates already looked up
+func (g stepUpGuard) required(ctx context.Context, transfer stepUpTransfer, rates map[string]decimal.Decimal) (bool, error) {
+	rate, ok := rates[transfer.amount.Currency]
+	if !ok {
+		currency, err := g.currencyRepo.GetCurrency(ctx, transfer.amount.Currency)
+		if err != nil && !utils.IsNotFoundError(err) {
+			logger.GetLogger().Error("Failed to get currency", zap.Error(err))
+			return false, err
+		}
+		rate = money.FromNumeric(currency.ExchangeRate, "").Amount
+		rates[transfer.amount.Currency] = rate
+	}
+	//? Without a rate the amount cannot be compared, it counts as a large one
+	if !rate.IsPositive() || transfer.amount.Amount.Mul(rate).GreaterThanOrEqual(g.threshold) {
+		return true, nil
+	}
+
+	if transfer.receiverUserID == transfer.senderUserID {
+		return false, nil
+	}
+	known, err := g.accountRepo.HasTransferredTo(ctx, int64(transfer.senderUserID), transfer.receiverAccountID)
+	if err != nil {
+		logger.GetLogger().Error("Failed to check transfer history", zap.Error(err))
+		return false, err
+	}
+	return !known, nil
+}
+
+// stepUpThresholdFromEnv reads TRANSFER_STEP_UP_THRESHOLD, falling back to the default when it is
+// not a positive decimal
+func stepUpThresholdFromEnv() decimal.Decimal {
+	value := os.Getenv(StepUpThresholdEnv)
+	if value == "" {
+		return defaultStepUpThreshold
+	}
+	threshold, err := decimal.NewFromString(value)
+	if err != nil || !threshold.IsPositive() {
+		logger.GetLogger().Warn("Invalid transfer step-up threshold, using the default",
+			zap.String("value", value), zap.String("default", defaultStepUpThreshold.String()))
+		return defaultStepUpThreshold
+	}
+	return threshold
+}
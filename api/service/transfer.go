@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"go.uber.org/zap"
+)
+
+type transferService struct {
+	accountRepo interface_repository.AccountRepository
+	stepUp      stepUpGuard
+}
+
+func NewTransferService(accountRepo interface_repository.AccountRepository, currencyRepo interface_repository.CurrencyRepository,
+	twoFactor interface_service.TwoFactorService) interface_service.TransferService {
+	return &transferService{
+		accountRepo: accountRepo,
+		stepUp:      newStepUpGuard(accountRepo, currencyRepo, twoFactor),
+	}
+}
+
+func (s *transferService) CreateTransfer(ctx context.Context, req dto.CreateTransferRequest) (schemas.TransferTxResult, error) {
+	if req.FromAccountID == req.ToAccountID {
+		return schemas.TransferTxResult{}, common.ErrSameAccount
+	}
+	if !req.Amount.IsPositive() {
+		return schemas.TransferTxResult{}, common.ErrInvalidAmount
+	}
+	currencyCode := strings.ToUpper(req.CurrencyCode)
+
+	sender, err := getTransferAccount(ctx, s.accountRepo, req.FromAccountID)
+	if err != nil {
+		return schemas.TransferTxResult{}, err
+	}
+	if err := authorizeAccount(ctx, sender); err != nil {
+		return schemas.TransferTxResult{}, err
+	}
+	// The amount is always in the sender's currency, the receiver may hold another one
+	if sender.CurrencyCode != currencyCode {
+		return schemas.TransferTxResult{}, common.ErrCurrencyMismatch
+	}
+	receiver, err := getTransferAccount(ctx, s.accountRepo, req.ToAccountID)
+	if err != nil {
+		return schemas.TransferTxResult{}, err
+	}
+	transfer := accountTransfer(sender, receiver, req.Amount.WithCurrency(sender.CurrencyCode))
+	if err := s.stepUp.check(ctx, req.TOTPCode, transfer); err != nil {
+		return schemas.TransferTxResult{}, err
+	}
+
+	amount, err := req.Amount.WithCurrency(currencyCode).Numeric()
+	if err != nil {
+		return schemas.TransferTxResult{}, err
+	}
+
+	// TransferTx expects the reference rows to exist already
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	if err != nil {
+		return schemas.TransferTxResult{}, err
+	}
+	pendingStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.PENDING)
+	if err != nil {
+		return schemas.TransferTxResult{}, err
+	}
+
+	result, err := transaction.TransferTx(ctx, schemas.TransferTxParams{
+		SenderAccountID:   int32(req.FromAccountID),
+		ReceiverAccountID: int32(req.ToAccountID),
+		Amount:            amount,
+		CurrencyCode:      currencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        pendingStatus.StatusCode,
+		Description:       req.Description,
+		ReferenceNumber:   req.ReferenceNumber,
+	})
+	if err != nil {
+		// The balance update is rejected by accounts_balance_check when the sender would go below its overdraft limit
+		if utils.IsCheckViolationError(err) && strings.Contains(err.Error(), "accounts_balance_check") {
+			return schemas.TransferTxResult{}, common.ErrInsufficientFunds
+		}
+		if errors.Is(err, transaction.ErrCurrencyMismatch) {
+			return schemas.TransferTxResult{}, common.ErrCurrencyMismatch
+		}
+		if errors.Is(err, transaction.ErrAmountPrecision) {
+			return schemas.TransferTxResult{}, common.ErrInvalidAmountPrecision
+		}
+		if errors.Is(err, transaction.ErrExchangeRateUnavailable) {
+			return schemas.TransferTxResult{}, common.ErrExchangeRateUnavailable
+		}
+		if errors.Is(err, transaction.ErrFeeIncomeAccountMissing) {
+			return schemas.TransferTxResult{}, common.ErrFeeIncomeAccountMissing
+		}
+		if errors.Is(err, transaction.ErrFixedDepositLocked) {
+			return schemas.TransferTxResult{}, common.ErrFixedDepositLocked
+		}
+		// A reference taken by an earlier execution of the same idempotent request
+		if req.ReferenceNumber != "" && utils.IsUniqueViolationError(err) {
+			return schemas.TransferTxResult{}, common.ErrDuplicateTransfer
+		}
+		logger.GetLogger().Error("transfer failed",
+			zap.Int64("from_account_id", req.FromAccountID),
+			zap.Int64("to_account_id", req.ToAccountID),
+			zap.Error(err))
+		return schemas.TransferTxResult{}, common.ErrTransactionFailed
+	}
+	return result, nil
+}
+
+func (s *transferService) ReverseTransaction(ctx context.Context, transactionNumber string,
+	req dto.ReverseTransactionRequest) (schemas.ReversalTxResult, error) {
+
+	number, err := uuid.Parse(transactionNumber)
+	if err != nil {
+		return schemas.ReversalTxResult{}, common.ErrInvalidTransactionNumber
+	}
+
+	result, err := transaction.ReverseTx(ctx, schemas.ReverseTxParams{
+		TransactionNumber: number,
+		Description:       req.Description,
+		ReferenceNumber:   req.ReferenceNumber,
+	})
+	if err != nil {
+		return schemas.ReversalTxResult{}, mapCompensationError(err, req.ReferenceNumber)
+	}
+	return result, nil
+}
+
+func (s *transferService) RefundTransaction(ctx context.Context, transactionNumber string,
+	req dto.RefundTransactionRequest) (schemas.ReversalTxResult, error) {
+
+	number, err := uuid.Parse(transactionNumber)
+	if err != nil {
+		return schemas.ReversalTxResult{}, common.ErrInvalidTransactionNumber
+	}
+	if !req.Amount.IsPositive() {
+		return schemas.ReversalTxResult{}, common.ErrInvalidAmount
+	}
+	// The original transaction decides the currency, the amount is checked against it there
+	amount, err := req.Amount.Numeric()
+	if err != nil {
+		return schemas.ReversalTxResult{}, err
+	}
+
+	result, err := transaction.RefundTx(ctx, schemas.RefundTxParams{
+		TransactionNumber: number,
+		Amount:            amount,
+		Description:       req.Description,
+		ReferenceNumber:   req.ReferenceNumber,
+	})
+	if err != nil {
+		return schemas.ReversalTxResult{}, mapCompensationError(err, req.ReferenceNumber)
+	}
+	return result, nil
+}
+
+// mapCompensationError translates ReverseTx and RefundTx failures into API errors
+func mapCompensationError(err error, referenceNumber string) error {
+	switch {
+	case utils.IsNotFoundError(err):
+		return common.ErrTransactionNotFound
+	case errors.Is(err, transaction.ErrAlreadyReversed):
+		return common.ErrAlreadyReversed
+	case errors.Is(err, transaction.ErrTransactionNotReversible):
+		return common.ErrTransactionNotReversible
+	case errors.Is(err, transaction.ErrRefundExceedsOriginal):
+		return common.ErrRefundExceedsOriginal
+	case errors.Is(err, transaction.ErrInvalidRefundAmount):
+		return common.ErrInvalidAmount
+	case errors.Is(err, transaction.ErrAmountPrecision):
+		return common.ErrInvalidAmountPrecision
+	case errors.Is(err, transaction.ErrFixedDepositLocked):
+		return common.ErrFixedDepositLocked
+	case utils.IsCheckViolationError(err) && strings.Contains(err.Error(), "accounts_balance_check"):
+		return common.ErrInsufficientFunds
+	case referenceNumber != "" && utils.IsUniqueViolationError(err):
+		return common.ErrDuplicateTransfer
+	}
+	logger.GetLogger().Error("transaction compensation failed", zap.Error(err))
+	return common.ErrTransactionFailed
+}
+
+// getTransferAccount loads an account taking part in a transfer and checks that it can be used
+func getTransferAccount(ctx context.Context, accountRepo interface_repository.AccountRepository,
+	accountID int64) (db.Account, error) {
+	account, err := accountRepo.GetAccount(ctx, accountID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.Account{}, common.ErrAccountNotFound
+		}
+		return db.Account{}, err
+	}
+	if !account.IsActive {
+		return db.Account{}, common.ErrAccountInactive
+	}
+	return account, nil
+}
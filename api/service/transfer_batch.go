@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/pkg/model"
+	upload_service "github.com/riad/banksystemendtoend/pkg/service"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxBatchFileItems caps the number of rows read from an uploaded batch file
+	maxBatchFileItems = 10000
+	// batchUploadGracePeriod is how long an upload may wait for its batch row before the import gives up
+	batchUploadGracePeriod = time.Minute
+)
+
+// batchFileHeader lists the columns of an uploaded batch file, in order
+var batchFileHeader = []string{"from_account_id", "to_account_id", "amount", "currency_code", "description"}
+
+type transferBatchService struct {
+	accountRepo interface_repository.AccountRepository
+	batchRepo   interface_repository.TransferBatchRepository
+	uploads     *upload_service.UploadService
+	stepUp      stepUpGuard
+}
+
+// NewTransferBatchService creates the batch transfer service, uploads may be nil when RabbitMQ is
+// not configured in which case only JSON batches are accepted
+func NewTransferBatchService(accountRepo interface_repository.AccountRepository, batchRepo interface_repository.TransferBatchRepository,
+	currencyRepo interface_repository.CurrencyRepository, twoFactor interface_service.TwoFactorService,
+	uploads *upload_service.UploadService) interface_service.TransferBatchService {
+	return &transferBatchService{
+		accountRepo: accountRepo,
+		batchRepo:   batchRepo,
+		uploads:     uploads,
+		stepUp:      newStepUpGuard(accountRepo, currencyRepo, twoFactor),
+	}
+}
+
+func (s *transferBatchService) CreateBatch(ctx context.Context, req dto.CreateTransferBatchRequest) (db.TransferBatch, error) {
+	items := make([]schemas.TransferBatchItemParams, 0, len(req.Items))
+	transfers := make([]stepUpTransfer, 0, len(req.Items))
+	receivers := make(map[int64]db.Account)
+	for _, item := range req.Items {
+		if item.FromAccountID == item.ToAccountID {
+			return db.TransferBatch{}, common.ErrSameAccount
+		}
+		if !item.Amount.IsPositive() {
+			return db.TransferBatch{}, common.ErrInvalidAmount
+		}
+		amount, err := item.Amount.WithCurrency(item.CurrencyCode).Numeric()
+		if err != nil {
+			return db.TransferBatch{}, err
+		}
+		items = append(items, schemas.TransferBatchItemParams{
+			FromAccountID: int32(item.FromAccountID),
+			ToAccountID:   int32(item.ToAccountID),
+			Amount:        amount,
+			CurrencyCode:  item.CurrencyCode,
+			Description:   item.Description,
+		})
+
+		receiver, err := s.batchReceiver(ctx, item.ToAccountID, receivers)
+		if err != nil {
+			return db.TransferBatch{}, err
+		}
+		transfers = append(transfers, stepUpTransfer{
+			senderUserID:      batchOwner(ctx),
+			receiverUserID:    receiver.UserID,
+			receiverAccountID: item.ToAccountID,
+			amount:            item.Amount.WithCurrency(strings.ToUpper(item.CurrencyCode)),
+		})
+	}
+	if err := s.stepUp.check(ctx, req.TOTPCode, transfers...); err != nil {
+		return db.TransferBatch{}, err
+	}
+
+	return s.createBatch(ctx, schemas.CreateTransferBatchParams{
+		Mode:            db.BatchMode(req.Mode),
+		ReferenceNumber: req.ReferenceNumber,
+		Items:           items,
+		UserID:          batchOwner(ctx),
+	})
+}
+
+func (s *transferBatchService) UploadBatch(ctx context.Context, req dto.UploadTransferBatchRequest) (db.TransferBatch, error) {
+	if s.uploads == nil {
+		return db.TransferBatch{}, common.ErrBatchUploadNotEnabled
+	}
+	if strings.ToLower(filepath.Ext(req.File.Filename)) != ".csv" {
+		return db.TransferBatch{}, common.ErrInvalidBatchFile
+	}
+	// The rows are only read once the file is queued, so their amounts and beneficiaries cannot be checked here
+	if err := s.stepUp.verify(ctx, req.TOTPCode); err != nil {
+		return db.TransferBatch{}, err
+	}
+
+	uploadID, err := s.uploads.HandleFileUpload(ctx, req.File, req.UserID, "transfer_batches/"+req.File.Filename)
+	if err != nil {
+		logger.GetLogger().Error("Failed to queue batch file",
+			zap.Int32("user_id", req.UserID),
+			zap.Error(err))
+		return db.TransferBatch{}, fmt.Errorf("failed to queue batch file: %w", err)
+	}
+
+	return s.createBatch(ctx, schemas.CreateTransferBatchParams{
+		Mode:            db.BatchMode(req.Mode),
+		ReferenceNumber: req.ReferenceNumber,
+		UploadID:        uploadID,
+		UserID:          batchOwner(ctx),
+	})
+}
+
+func (s *transferBatchService) createBatch(ctx context.Context, arg schemas.CreateTransferBatchParams) (db.TransferBatch, error) {
+	batch, err := transaction.CreateTransferBatch(ctx, arg)
+	if err != nil {
+		// A reference taken by an earlier execution of the same idempotent request
+		if arg.ReferenceNumber != "" && utils.IsUniqueViolationError(err) {
+			return db.TransferBatch{}, common.ErrDuplicateTransferBatch
+		}
+		logger.GetLogger().Error("Failed to create transfer batch",
+			zap.String("mode", string(arg.Mode)),
+			zap.Int("items", len(arg.Items)),
+			zap.Error(err))
+		return db.TransferBatch{}, err
+	}
+	return batch, nil
+}
+
+// ImportUpload is the upload queue handler for batch files. A malformed file fails the batch, the
+// batch worker picks up a successfully imported one.
+func (s *transferBatchService) ImportUpload(ctx context.Context, job *model.UploadJob) error {
+	items, parseErr := readBatchFile(job.TempPath)
+	if parseErr != nil {
+		_, err := transaction.FailTransferBatchImport(ctx, job.ID, parseErr.Error())
+		if err != nil {
+			return s.importError(job, err)
+		}
+		return parseErr
+	}
+
+	batch, err := transaction.ImportTransferBatch(ctx, job.ID, items)
+	if err != nil {
+		return s.importError(job, err)
+	}
+	logger.GetLogger().Info("Imported transfer batch file",
+		zap.String("batch_number", batch.BatchNumber.String()),
+		zap.Int32("items", batch.TotalItems))
+	return nil
+}
+
+// importError asks for a redelivery while the batch row of a fresh upload may still be on its way
+func (s *transferBatchService) importError(job *model.UploadJob, err error) error {
+	if utils.IsNotFoundError(err) && time.Since(job.CreatedAt) < batchUploadGracePeriod {
+		return upload_service.ErrUploadNotReady
+	}
+	return err
+}
+
+func (s *transferBatchService) GetBatch(ctx context.Context, batchNumber string) (db.TransferBatch, error) {
+	number, err := uuid.Parse(batchNumber)
+	if err != nil {
+		return db.TransferBatch{}, common.ErrInvalidBatchNumber
+	}
+	batch, err := s.batchRepo.GetTransferBatch(ctx, number)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.TransferBatch{}, common.ErrTransferBatchNotFound
+		}
+		return db.TransferBatch{}, err
+	}
+	if err := authorizeBatch(ctx, batch); err != nil {
+		return db.TransferBatch{}, err
+	}
+	return batch, nil
+}
+
+func (s *transferBatchService) ListItems(ctx context.Context, batchNumber string) (db.TransferBatch, []db.TransferBatchItem, error) {
+	batch, err := s.GetBatch(ctx, batchNumber)
+	if err != nil {
+		return db.TransferBatch{}, nil, err
+	}
+	items, err := s.batchRepo.ListTransferBatchItems(ctx, batch.BatchID)
+	if err != nil {
+		return db.TransferBatch{}, nil, err
+	}
+	return batch, items, nil
+}
+
+// batchReceiver loads the receiver of a batch item, seen caches the receivers already loaded. An
+// unknown account counts as a new beneficiary, its item fails when the batch runs.
+func (s *transferBatchService) batchReceiver(ctx context.Context, accountID int64, seen map[int64]db.Account) (db.Account, error) {
+	if receiver, ok := seen[accountID]; ok {
+		return receiver, nil
+	}
+	receiver, err := s.accountRepo.GetAccount(ctx, accountID)
+	if err != nil && !utils.IsNotFoundError(err) {
+		return db.Account{}, err
+	}
+	seen[accountID] = receiver
+	return receiver, nil
+}
+
+// batchOwner returns the user a batch is submitted by, zero when the request carried no access token
+func batchOwner(ctx context.Context) int32 {
+	userID, _ := utils.AuthUserID(ctx)
+	return int32(userID)
+}
+
+// authorizeBatch refuses users other than the one who submitted a batch
+func authorizeBatch(ctx context.Context, batch db.TransferBatch) error {
+	if _, ok := utils.AuthUserID(ctx); !ok {
+		return nil
+	}
+	if !batch.UserID.Valid {
+		return common.ErrForbidden
+	}
+	return authorizeUser(ctx, int64(batch.UserID.Int32))
+}
+
+// readBatchFile parses an uploaded batch file, rejecting the whole file on the first invalid row
+func readBatchFile(path string) ([]schemas.TransferBatchItemParams, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open batch file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = len(batchFileHeader)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInvalidBatchFile, err)
+	}
+	for i, column := range batchFileHeader {
+		if strings.ToLower(strings.TrimSpace(header[i])) != column {
+			return nil, common.ErrInvalidBatchFile
+		}
+	}
+
+	var items []schemas.TransferBatchItemParams
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", common.ErrInvalidBatchFile, err)
+		}
+		if len(items) == maxBatchFileItems {
+			return nil, fmt.Errorf("batch file has more than %d transfers", maxBatchFileItems)
+		}
+		item, err := parseBatchRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("batch file has no transfers")
+	}
+	return items, nil
+}
+
+func parseBatchRecord(record []string) (schemas.TransferBatchItemParams, error) {
+	fromAccountID, err := strconv.ParseInt(strings.TrimSpace(record[0]), 10, 32)
+	if err != nil || fromAccountID < 1 {
+		return schemas.TransferBatchItemParams{}, fmt.Errorf("invalid from_account_id %q", record[0])
+	}
+	toAccountID, err := strconv.ParseInt(strings.TrimSpace(record[1]), 10, 32)
+	if err != nil || toAccountID < 1 {
+		return schemas.TransferBatchItemParams{}, fmt.Errorf("invalid to_account_id %q", record[1])
+	}
+	if fromAccountID == toAccountID {
+		return schemas.TransferBatchItemParams{}, common.ErrSameAccount
+	}
+	currencyCode := strings.TrimSpace(record[3])
+	if len(currencyCode) != 3 {
+		return schemas.TransferBatchItemParams{}, fmt.Errorf("invalid currency_code %q", record[3])
+	}
+	amountValue, err := money.Parse(strings.TrimSpace(record[2]), currencyCode)
+	if err != nil {
+		return schemas.TransferBatchItemParams{}, fmt.Errorf("invalid amount %q", record[2])
+	}
+	if !amountValue.IsPositive() {
+		return schemas.TransferBatchItemParams{}, common.ErrInvalidAmount
+	}
+	amount, err := amountValue.Numeric()
+	if err != nil {
+		return schemas.TransferBatchItemParams{}, err
+	}
+	description := strings.TrimSpace(record[4])
+	if len(description) > 255 {
+		return schemas.TransferBatchItemParams{}, fmt.Errorf("description is longer than 255 characters")
+	}
+
+	return schemas.TransferBatchItemParams{
+		FromAccountID: int32(fromAccountID),
+		ToAccountID:   int32(toAccountID),
+		Amount:        amount,
+		CurrencyCode:  currencyCode,
+		Description:   description,
+	}, nil
+}
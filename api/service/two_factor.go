@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/riad/banksystemendtoend/util/totp"
+	"go.uber.org/zap"
+)
+
+const (
+	// twoFactorMaxFailures wrong codes within twoFactorLockout lock the second factor until the
+	// lockout has passed since the last of them
+	twoFactorMaxFailures = 5
+	twoFactorLockout     = 15 * time.Minute
+)
+
+type twoFactorService struct {
+	twoFactorRepo interface_repository.TwoFactorRepository
+	userRepo      interface_repository.UserRepository
+	issuer        string
+}
+
+func NewTwoFactorService(twoFactorRepo interface_repository.TwoFactorRepository,
+	userRepo interface_repository.UserRepository) interface_service.TwoFactorService {
+	return &twoFactorService{twoFactorRepo: twoFactorRepo, userRepo: userRepo, issuer: totp.IssuerFromEnv()}
+}
+
+// Enroll replaces the secret of an enrollment that was not confirmed, so a user who lost the QR code
+// can start over. Once enabled, two-factor authentication has to be disabled before enrolling again.
+func (s *twoFactorService) Enroll(ctx context.Context, userID int64) (schemas.TwoFactorEnrollment, error) {
+	user, err := s.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return schemas.TwoFactorEnrollment{}, common.ErrUserInactive
+		}
+		logger.GetLogger().Error("Failed to get user", zap.Error(err))
+		return schemas.TwoFactorEnrollment{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return schemas.TwoFactorEnrollment{}, err
+	}
+
+	_, err = s.twoFactorRepo.StartTwoFactorEnrollment(ctx, db.StartTwoFactorEnrollmentParams{
+		UserID: user.UserID,
+		Secret: secret,
+	})
+	if err != nil {
+		//? The upsert leaves enabled second factors alone and returns no row for them
+		if utils.IsNotFoundError(err) {
+			return schemas.TwoFactorEnrollment{}, common.ErrTwoFactorAlreadyEnabled
+		}
+		logger.GetLogger().Error("Failed to start two-factor enrollment", zap.Error(err))
+		return schemas.TwoFactorEnrollment{}, fmt.Errorf("failed to start two-factor enrollment: %w", err)
+	}
+	return schemas.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.issuer, user.Username, secret),
+	}, nil
+}
+
+// Confirm proves the authenticator app was set up with the secret. The recovery codes are returned
+// here only, just their hashes are stored.
+func (s *twoFactorService) Confirm(ctx context.Context, userID int64, code string) ([]string, error) {
+	stored, err := s.getTwoFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, common.ErrTwoFactorNotEnabled) {
+			return nil, common.ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+	if stored.EnabledAt.Valid {
+		return nil, common.ErrTwoFactorAlreadyEnabled
+	}
+	step, ok := totp.Validate(stored.Secret, code, time.Now(), stored.LastUsedStep)
+	if !ok {
+		return nil, common.ErrInvalidTwoFactorCode
+	}
+
+	codes, err := totp.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, recoveryCode := range codes {
+		hashes = append(hashes, totp.HashRecoveryCode(recoveryCode))
+	}
+	_, err = s.twoFactorRepo.EnableTwoFactor(ctx, db.EnableTwoFactorParams{
+		RecoveryCodeHashes: hashes,
+		LastUsedStep:       step,
+		UserID:             stored.UserID,
+	})
+	if err != nil {
+		//? Confirmed by a concurrent request, its recovery codes are the ones that count
+		if utils.IsNotFoundError(err) {
+			return nil, common.ErrTwoFactorAlreadyEnabled
+		}
+		logger.GetLogger().Error("Failed to enable two-factor authentication", zap.Error(err))
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	return codes, nil
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, userID int64, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.twoFactorRepo.DeleteTwoFactor(ctx, userID); err != nil {
+		logger.GetLogger().Error("Failed to disable two-factor authentication", zap.Error(err))
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+func (s *twoFactorService) Enabled(ctx context.Context, userID int64) (bool, error) {
+	stored, err := s.getTwoFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, common.ErrTwoFactorNotEnabled) {
+			return false, nil
+		}
+		return false, err
+	}
+	return stored.EnabledAt.Valid, nil
+}
+
+// Verify accepts a code of a time step after the last accepted one, or a recovery code that was not
+// used yet. Wrong codes are counted, after twoFactorMaxFailures of them every code is refused with
+// ErrTwoFactorLocked until twoFactorLockout has passed.
+func (s *twoFactorService) Verify(ctx context.Context, userID int64, code string) error {
+	stored, err := s.getTwoFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !stored.EnabledAt.Valid {
+		return common.ErrTwoFactorNotEnabled
+	}
+	now := time.Now()
+	if stored.FailedAttempts >= twoFactorMaxFailures && stored.LastFailedAt.Valid &&
+		now.Sub(stored.LastFailedAt.Time) < twoFactorLockout {
+		return common.ErrTwoFactorLocked
+	}
+
+	if totp.IsRecoveryCode(code) {
+		_, err = s.twoFactorRepo.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+			CodeHash: totp.HashRecoveryCode(code),
+			UserID:   stored.UserID,
+		})
+	} else if step, ok := totp.Validate(stored.Secret, code, now, stored.LastUsedStep); ok {
+		//? Two requests with the same code race here, only one of them moves last_used_step
+		_, err = s.twoFactorRepo.UseTwoFactorStep(ctx, db.UseTwoFactorStepParams{
+			Step:   step,
+			UserID: stored.UserID,
+		})
+	} else {
+		err = sql.ErrNoRows
+	}
+	if err == nil {
+		return nil
+	}
+	if !utils.IsNotFoundError(err) {
+		logger.GetLogger().Error("Failed to verify two-factor code", zap.Error(err))
+		return fmt.Errorf("failed to verify two-factor code: %w", err)
+	}
+
+	failed, err := s.twoFactorRepo.RecordTwoFactorFailure(ctx, db.RecordTwoFactorFailureParams{
+		WindowStart: sql.NullTime{Time: now.Add(-twoFactorLockout), Valid: true},
+		UserID:      stored.UserID,
+	})
+	if err != nil {
+		logger.GetLogger().Error("Failed to record two-factor failure", zap.Error(err))
+	} else if failed.FailedAttempts == twoFactorMaxFailures {
+		logger.GetLogger().Warn("Two-factor authentication locked after repeated wrong codes",
+			zap.Int64("user_id", userID), zap.String("ip_address", clientIP(ctx)))
+	}
+	return common.ErrInvalidTwoFactorCode
+}
+
+// getTwoFactor returns ErrTwoFactorNotEnabled for users who never enrolled
+func (s *twoFactorService) getTwoFactor(ctx context.Context, userID int64) (db.UserTwoFactor, error) {
+	stored, err := s.twoFactorRepo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.UserTwoFactor{}, common.ErrTwoFactorNotEnabled
+		}
+		logger.GetLogger().Error("Failed to get two-factor authentication", zap.Error(err))
+		return db.UserTwoFactor{}, fmt.Errorf("failed to get two-factor authentication: %w", err)
+	}
+	return stored, nil
+}
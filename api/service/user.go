@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_repository "github.com/riad/banksystemendtoend/api/interface/repository"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/rbac"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize     = 10
+	maxPageSize         = 100
+	maxProfileImageSize = 5 * 1024 * 1024
+)
+
+var allowedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+type userService struct {
+	repo    interface_repository.UserRepository
+	storage interface_service.S3Service
+}
+
+// NewUserService creates the user service; storage may be nil, in which case profile images are rejected
+func NewUserService(repo interface_repository.UserRepository, storage interface_service.S3Service) interface_service.UserService {
+	return &userService{repo: repo, storage: storage}
+}
+
+func (s *userService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (db.User, error) {
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		logger.GetLogger().Error("failed to hash password", zap.Error(err))
+		return db.User{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	imageURL, err := s.uploadProfileImage(ctx, req.ProfileImage)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	arg := db.CreateUserParams{
+		Username:        req.Username,
+		PasswordHash:    hashedPassword,
+		Email:           nullString(req.Email),
+		FirstName:       nullString(req.FirstName),
+		LastName:        nullString(req.LastName),
+		PhoneNumber:     nullString(req.PhoneNumber),
+		ProfileImageUrl: nullString(imageURL),
+	}
+
+	user, err := s.repo.CreateUser(ctx, arg)
+	if err != nil {
+		s.deleteProfileImage(ctx, imageURL)
+		return db.User{}, mapUserWriteError(err, "failed to create user")
+	}
+	return user, nil
+}
+
+func (s *userService) GetUser(ctx context.Context, userID int64) (db.User, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return db.User{}, err
+	}
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.User{}, sql.ErrNoRows
+		}
+		logger.GetLogger().Error("Failed to get user", zap.Error(err))
+		return db.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+func (s *userService) ListUsers(ctx context.Context, page, pageSize int32) ([]db.User, error) {
+	if err := authorizeAllUsers(ctx); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	users, err := s.repo.ListUsers(ctx, pageSize, (page-1)*pageSize)
+	if err != nil {
+		logger.GetLogger().Error("Failed to list users", zap.Error(err))
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
+}
+
+func (s *userService) UpdateUser(ctx context.Context, userID int64, req dto.UpdateUserRequest) (db.User, error) {
+	existing, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	imageURL, err := s.uploadProfileImage(ctx, req.ProfileImage)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	arg := db.UpdateUserParams{
+		UserID:          int32(userID),
+		Username:        nullString(req.Username),
+		Email:           nullString(req.Email),
+		FirstName:       nullString(req.FirstName),
+		LastName:        nullString(req.LastName),
+		PhoneNumber:     nullString(req.PhoneNumber),
+		ProfileImageUrl: nullString(imageURL),
+	}
+
+	user, err := s.repo.UpdateUser(ctx, arg)
+	if err != nil {
+		s.deleteProfileImage(ctx, imageURL)
+		if utils.IsNotFoundError(err) {
+			return db.User{}, sql.ErrNoRows
+		}
+		return db.User{}, mapUserWriteError(err, "failed to update user")
+	}
+
+	//? The old image is only removed once the new one is persisted
+	if imageURL != "" && existing.ProfileImageUrl.Valid {
+		s.deleteProfileImage(ctx, existing.ProfileImageUrl.String)
+	}
+	return user, nil
+}
+
+func (s *userService) DeleteUser(ctx context.Context, userID int64) error {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteUser(ctx, userID); err != nil {
+		logger.GetLogger().Error("Failed to delete user", zap.Error(err))
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+func (s *userService) HardDeleteUser(ctx context.Context, userID int64) error {
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.HardDeleteUser(ctx, userID); err != nil {
+		if utils.IsForeignKeyError(err) {
+			return common.ErrUserHasAccounts
+		}
+		if utils.IsRestrictViolationError(err) {
+			return common.ErrUserHasHistory
+		}
+		logger.GetLogger().Error("Failed to hard delete user", zap.Error(err))
+		return fmt.Errorf("failed to hard delete user: %w", err)
+	}
+	if user.ProfileImageUrl.Valid {
+		s.deleteProfileImage(ctx, user.ProfileImageUrl.String)
+	}
+	return nil
+}
+
+func (s *userService) UpdateLastLogin(ctx context.Context, userID int64) error {
+	if err := s.repo.UpdateLastLogin(ctx, userID, time.Now()); err != nil {
+		logger.GetLogger().Error("Failed to update last login", zap.Error(err))
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+	return nil
+}
+
+func (s *userService) UpdateUserRole(ctx context.Context, userID int64, req dto.UpdateUserRoleRequest) (db.User, error) {
+	role := strings.ToUpper(req.Role)
+	if !rbac.IsValidRole(role) {
+		return db.User{}, common.ErrInvalidRole
+	}
+	//? Admins cannot demote themselves, so there is always an admin left to grant roles
+	if authUserID, ok := utils.AuthUserID(ctx); ok && authUserID == userID {
+		return db.User{}, common.ErrOwnRoleChange
+	}
+
+	user, err := s.repo.UpdateUserRole(ctx, userID, db.UserRole(role))
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return db.User{}, sql.ErrNoRows
+		}
+		logger.GetLogger().Error("Failed to update user role", zap.Error(err))
+		return db.User{}, fmt.Errorf("failed to update user role: %w", err)
+	}
+	return user, nil
+}
+
+// uploadProfileImage validates and stores an optional profile image, returning its URL
+func (s *userService) uploadProfileImage(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	if file == nil {
+		return "", nil
+	}
+	if file.Size > maxProfileImageSize {
+		return "", common.ErrInvalidImage
+	}
+	contentType, err := sniffContentType(file)
+	if err != nil {
+		return "", err
+	}
+	if !allowedImageTypes[contentType] {
+		return "", common.ErrInvalidImage
+	}
+	if s.storage == nil {
+		return "", common.ErrStorageNotEnabled
+	}
+	//? The image is stored with the type of its content, not the one the client claimed
+	file.Header.Set("Content-Type", contentType)
+	return s.storage.UploadFile(ctx, file)
+}
+
+// sniffContentType detects the type of an uploaded file from its first 512 bytes
+func sniffContentType(file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// deleteProfileImage removes a stored profile image; failures are only logged
+func (s *userService) deleteProfileImage(ctx context.Context, url string) {
+	if url == "" || s.storage == nil {
+		return
+	}
+	if err := s.storage.DeleteFile(ctx, url); err != nil {
+		logger.GetLogger().Warn("failed to delete profile image", zap.String("url", url), zap.Error(err))
+	}
+}
+
+// mapUserWriteError converts constraint violations on the users table into API errors
+func mapUserWriteError(err error, message string) error {
+	if utils.IsUniqueViolationError(err) {
+		return common.ErrUserExists
+	}
+	if utils.IsCheckViolationError(err) {
+		return common.ErrInvalidUserData
+	}
+	logger.GetLogger().Error(message, zap.Error(err))
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// nullString converts an optional string into sql.NullString
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
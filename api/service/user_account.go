@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/riad/banksystemendtoend/api/common"
+	"github.com/riad/banksystemendtoend/api/dto"
+	interface_service "github.com/riad/banksystemendtoend/api/interface/service"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/config"
+	"go.uber.org/zap"
+)
+
+type userAccountService struct {
+	store *db.SQLStore
+}
+
+func NewUserAccountService(store *db.SQLStore) interface_service.UserAccountService {
+	return &userAccountService{store: store}
+}
+
+// CreateUserWithAccount creates the user and the first account inside one database transaction,
+// so a failure at any step leaves neither row behind.
+func (s *userAccountService) CreateUserWithAccount(ctx context.Context, req dto.CreateUserAccountRequest) (db.User, db.Account, error) {
+	accountType := strings.ToUpper(req.AccountType)
+	currencyCode := strings.ToUpper(req.CurrencyCode)
+	if !config.IsValidAccountType(accountType) {
+		return db.User{}, db.Account{}, common.ErrInvalidAccountType
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		logger.GetLogger().Error("failed to hash password", zap.Error(err))
+		return db.User{}, db.Account{}, common.ErrTransactionFailed
+	}
+	interestRate, overdraftLimit, err := openingTerms()
+	if err != nil {
+		return db.User{}, db.Account{}, err
+	}
+
+	var user db.User
+	var account db.Account
+
+	err = s.store.ExecTx(ctx, func(q *db.Queries) error {
+		// Step 1: Check the reference tables
+		if _, err := q.GetAccountType(ctx, accountType); err != nil {
+			if utils.IsNotFoundError(err) {
+				return common.ErrAccountReferenceError
+			}
+			return err
+		}
+		currency, err := q.GetCurrency(ctx, currencyCode)
+		if err != nil {
+			if utils.IsNotFoundError(err) {
+				return common.ErrAccountReferenceError
+			}
+			return err
+		}
+		if !currency.IsActive {
+			return common.ErrAccountReferenceError
+		}
+
+		// Step 2: Create the user
+		user, err = q.CreateUser(ctx, db.CreateUserParams{
+			Username:        req.Username,
+			PasswordHash:    hashedPassword,
+			Email:           nullString(req.Email),
+			FirstName:       nullString(req.FirstName),
+			LastName:        nullString(req.LastName),
+			PhoneNumber:     nullString(req.PhoneNumber),
+			ProfileImageUrl: nullString(req.ProfileImageUrl),
+		})
+		if err != nil {
+			if utils.IsUniqueViolationError(err) {
+				return common.ErrUserExists
+			}
+			if utils.IsCheckViolationError(err) {
+				return common.ErrInvalidUserData
+			}
+			return err
+		}
+
+		// Step 3: Reserve a free account number and open the account
+		accountNumber, err := freeAccountNumber(ctx, q)
+		if err != nil {
+			return err
+		}
+		account, err = q.CreateAccount(ctx, db.CreateAccountParams{
+			UserID:         user.UserID,
+			AccountNumber:  accountNumber,
+			AccountType:    accountType,
+			CurrencyCode:   currency.CurrencyCode,
+			InterestRate:   interestRate,
+			OverdraftLimit: overdraftLimit,
+		})
+		if err != nil {
+			if utils.IsUniqueViolationError(err) {
+				return common.ErrAccountExists
+			}
+			return err
+		}
+		return nil
+	})
+
+	if err != nil {
+		for _, sentinel := range []error{
+			common.ErrUserExists,
+			common.ErrAccountExists,
+			common.ErrAccountReferenceError,
+			common.ErrInvalidUserData,
+		} {
+			if errors.Is(err, sentinel) {
+				return db.User{}, db.Account{}, sentinel
+			}
+		}
+		logger.GetLogger().Error("failed to create user with account", zap.Error(err))
+		return db.User{}, db.Account{}, common.ErrTransactionFailed
+	}
+	return user, account, nil
+}
+
+// freeAccountNumber generates account numbers until one is not taken yet. A statement error aborts a
+// Postgres transaction, so collisions are detected by lookup instead of by retrying the insert.
+func freeAccountNumber(ctx context.Context, q *db.Queries) (string, error) {
+	for i := 0; i < maxAccountNumberRetries; i++ {
+		accountNumber, err := utils.GenerateAccountNumber()
+		if err != nil {
+			return "", err
+		}
+		_, err = q.GetAccountByNumber(ctx, accountNumber)
+		if utils.IsNotFoundError(err) {
+			return accountNumber, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", common.ErrAccountExists
+}
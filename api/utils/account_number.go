@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// AccountNumberPrefix is the fixed prefix of every account number issued by the system
+	AccountNumberPrefix = "WS"
+	// accountNumberDigits is the number of digits following the prefix (including the check digit)
+	accountNumberDigits = 16
+)
+
+// GenerateAccountNumber returns a new random account number in the form WS + 15 digits + Luhn check digit
+func GenerateAccountNumber() (string, error) {
+	var sb strings.Builder
+	sb.Grow(accountNumberDigits - 1)
+
+	for i := 0; i < accountNumberDigits-1; i++ {
+		// The first digit is drawn from 1-9 so the numeric part always has a fixed width
+		lowest, choices := int64(0), int64(10)
+		if i == 0 {
+			lowest, choices = 1, 9
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(choices))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate account number: %w", err)
+		}
+		sb.WriteByte(byte('0' + lowest + n.Int64()))
+	}
+
+	body := sb.String()
+	return AccountNumberPrefix + body + string(luhnCheckDigit(body)), nil
+}
+
+// IsValidAccountNumber checks the prefix, length and Luhn check digit of an account number
+func IsValidAccountNumber(accountNumber string) bool {
+	if !strings.HasPrefix(accountNumber, AccountNumberPrefix) {
+		return false
+	}
+	digits := strings.TrimPrefix(accountNumber, AccountNumberPrefix)
+	if len(digits) != accountNumberDigits {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	body, check := digits[:len(digits)-1], digits[len(digits)-1]
+	return luhnCheckDigit(body) == check
+}
+
+// luhnCheckDigit computes the Luhn (mod 10) check digit for a string of digits
+func luhnCheckDigit(digits string) byte {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
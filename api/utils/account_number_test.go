@@ -0,0 +1,28 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/riad/banksystemendtoend/api/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAccountNumber(t *testing.T) {
+	firstDigits := make(map[byte]int)
+	for i := 0; i < 2000; i++ {
+		accountNumber, err := utils.GenerateAccountNumber()
+		require.NoError(t, err)
+		require.True(t, utils.IsValidAccountNumber(accountNumber), accountNumber)
+
+		digits := strings.TrimPrefix(accountNumber, utils.AccountNumberPrefix)
+		require.NotEqual(t, byte('0'), digits[0], accountNumber)
+		firstDigits[digits[0]]++
+	}
+
+	//? Every first digit is drawn about as often, 1 is not twice as likely as the others
+	require.Len(t, firstDigits, 9)
+	for digit, count := range firstDigits {
+		require.Less(t, count, 2000/6, string(digit))
+	}
+}
@@ -0,0 +1,36 @@
+package utils
+
+import "context"
+
+// AuthUserIDKey is the context key the ID of the user an access token was issued to is stored under.
+// It is a string so it can be set on a gin context with Set and still be found through Value.
+const AuthUserIDKey = "auth_user_id"
+
+// AuthAllUsersKey is the context key marking requests whose role may act on the resources of every
+// user, not only on those of the authenticated user
+const AuthAllUsersKey = "auth_all_users"
+
+// AuthUserID returns the ID of the user the request was authenticated as, if it carried an access token
+func AuthUserID(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(AuthUserIDKey).(int64)
+	return userID, ok
+}
+
+// AuthAllUsers reports whether the authenticated user may act on the resources of every user
+func AuthAllUsers(ctx context.Context) bool {
+	allUsers, _ := ctx.Value(AuthAllUsersKey).(bool)
+	return allUsers
+}
+
+// APIKeyIDKey and APIKeyScopesKey are the context keys the ID and scopes of the API key a request
+// was sent with are stored under
+const (
+	APIKeyIDKey     = "api_key_id"
+	APIKeyScopesKey = "api_key_scopes"
+)
+
+// APIKeyScopes returns the scopes of the API key the request was sent with, if it carried one
+func APIKeyScopes(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(APIKeyScopesKey).([]string)
+	return scopes, ok
+}
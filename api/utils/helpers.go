@@ -1,12 +1,15 @@
 package utils
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/joho/godotenv"
 	"github.com/riad/banksystemendtoend/util/config"
 	"golang.org/x/crypto/bcrypt"
@@ -51,11 +54,27 @@ func IsForeignKeyError(err error) bool {
 	return strings.Contains(err.Error(), "SQLSTATE 23503")
 }
 
+// IsNotFoundError checks if an error means the requested row does not exist
+func IsNotFoundError(err error) bool {
+	return errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows)
+}
+
 // IsUniqueViolationError checks if an error is a unique violation error
 func IsUniqueViolationError(err error) bool {
 	return strings.Contains(err.Error(), "SQLSTATE 23505")
 }
 
+// IsRestrictViolationError checks if an error is a restrict violation, raised when a row sealed into
+// a hash chain is changed or deleted
+func IsRestrictViolationError(err error) bool {
+	return strings.Contains(err.Error(), "SQLSTATE 23001")
+}
+
+// IsCheckViolationError checks if an error is a check constraint violation error
+func IsCheckViolationError(err error) bool {
+	return strings.Contains(err.Error(), "SQLSTATE 23514")
+}
+
 // GetValidAccountTypesMessage returns a message with valid account types
 func GetValidAccountTypesMessage() string {
 	types := []string{}
@@ -66,6 +85,15 @@ func GetValidAccountTypesMessage() string {
 	return fmt.Sprintf("invalid account type: must be one of %s", strings.Join(types, ", "))
 }
 
+// ParseID parses a positive integer identifier taken from a path or query parameter
+func ParseID(value, fieldName string) (int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be a positive integer", fieldName)
+	}
+	return id, nil
+}
+
 // GetEnvAsInt returns the value of the environment variable as an integer
 func GetEnvAsInt(key string, defaultVal int) int {
 	if value, exists := os.LookupEnv(key); exists {
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/apikey"
+	environment_config "github.com/riad/banksystemendtoend/util/config"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/hashchain"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/rbac"
+	"github.com/riad/banksystemendtoend/util/signing"
+)
+
+// errDriftFound and errChainBroken make a command exit with status 2 instead of 1
+var (
+	errDriftFound  = errors.New("ledger discrepancies found")
+	errChainBroken = errors.New("hash chain broken")
+)
+
+// ! commands are one-off tasks run instead of the server: go run . <command> [flags]
+var commands = map[string]func(args []string) error{
+	"reconcile":     reconcileCommand,
+	"verify-chain":  verifyChainCommand,
+	"set-role":      setRoleCommand,
+	"issue-api-key": issueAPIKeyCommand,
+}
+
+// ! reconcileCommand runs a ledger reconciliation and prints its discrepancies
+func reconcileCommand(args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	accounts := flags.String("accounts", "", "comma separated account ids to reconcile, all accounts when empty")
+	failOnDrift := flags.Bool("fail-on-drift", false, "exit with status 2 when discrepancies are found")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	accountIDs, err := parseAccountIDs(*accounts)
+	if err != nil {
+		return err
+	}
+
+	if err := setup.InitializeEnvironment(environment_config.DevEnvironment); err != nil {
+		return err
+	}
+
+	result, err := transaction.RunReconciliation(context.Background(), accountIDs)
+	if err != nil {
+		return err
+	}
+
+	run := result.Run
+	fmt.Printf("Reconciliation %s: %d accounts, %d transactions checked, %d discrepancies\n",
+		run.RunNumber, run.AccountsChecked, run.TransactionsChecked, run.DiscrepancyCount)
+	for currency, drift := range result.Drift {
+		fmt.Printf("Balance drift %s: %s\n", currency, money.New(drift, currency).Text())
+	}
+
+	if len(result.Discrepancies) > 0 {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "TYPE\tACCOUNT\tTRANSACTION\tCURRENCY\tEXPECTED\tACTUAL\tDIFFERENCE")
+		for _, discrepancy := range result.Discrepancies {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				discrepancy.DiscrepancyType,
+				nullID(discrepancy.AccountID.Int32, discrepancy.AccountID.Valid),
+				nullID(discrepancy.TransactionID.Int32, discrepancy.TransactionID.Valid),
+				discrepancy.CurrencyCode.String,
+				money.FromNumeric(discrepancy.ExpectedAmount, discrepancy.CurrencyCode.String).Text(),
+				money.FromNumeric(discrepancy.ActualAmount, discrepancy.CurrencyCode.String).Text(),
+				money.FromNumeric(discrepancy.Difference, discrepancy.CurrencyCode.String).Text())
+		}
+		writer.Flush()
+	}
+
+	if *failOnDrift && run.DiscrepancyCount > 0 {
+		return errDriftFound
+	}
+	return nil
+}
+
+// ! verifyChainCommand walks the audit_trail and entries hash chains and reports the first broken link
+func verifyChainCommand(args []string) error {
+	flags := flag.NewFlagSet("verify-chain", flag.ContinueOnError)
+	chain := flags.String("chain", "", "chain to verify (audit_trail or entries), every chain when empty")
+	publicKey := flags.String("public-key", "", "base64 Ed25519 key to check checkpoint signatures with, "+
+		"derived from "+hashchain.CheckpointKeyEnv+" when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	chains := hashchain.Chains
+	if *chain != "" {
+		chains = []string{*chain}
+	}
+
+	if err := setup.InitializeEnvironment(environment_config.DevEnvironment); err != nil {
+		return err
+	}
+
+	var key ed25519.PublicKey
+	if *publicKey != "" {
+		parsed, err := hashchain.ParsePublicKey(*publicKey)
+		if err != nil {
+			return err
+		}
+		key = parsed
+	} else {
+		signer, err := hashchain.SignerFromEnv()
+		if err != nil {
+			return err
+		}
+		if signer != nil {
+			key = signer.PublicKey()
+		}
+	}
+	if key == nil {
+		fmt.Println("No checkpoint key given, checkpoint signatures are not checked")
+	}
+
+	broken := false
+	for _, name := range chains {
+		result, err := transaction.VerifyHashChain(context.Background(), name, key)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Chain %s: %d links, %d checkpoints checked, head at link %d\n",
+			result.Chain, result.LinksChecked, result.CheckpointsChecked, result.Head.LastSeq)
+		if result.Break != nil {
+			broken = true
+			record := result.Break.RecordID
+			if record == "" {
+				record = "-"
+			}
+			fmt.Printf("First broken link: %d (record %s): %s\n", result.Break.Seq, record, result.Break.Reason)
+		}
+	}
+
+	if broken {
+		return errChainBroken
+	}
+	return nil
+}
+
+// ! setRoleCommand gives a user a role, the first admin can only be appointed this way
+func setRoleCommand(args []string) error {
+	flags := flag.NewFlagSet("set-role", flag.ContinueOnError)
+	username := flags.String("username", "", "username of the user to give the role")
+	role := flags.String("role", "", "role to give: CUSTOMER, SUPPORT, ADMIN or AUDITOR")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *username == "" {
+		return errors.New("username is required")
+	}
+	if !rbac.IsValidRole(strings.ToUpper(*role)) {
+		return fmt.Errorf("invalid role %q", *role)
+	}
+
+	if err := setup.InitializeEnvironment(environment_config.DevEnvironment); err != nil {
+		return err
+	}
+
+	user, err := transaction.SetUserRole(context.Background(), *username, db.UserRole(strings.ToUpper(*role)))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("User %s (%d) is now %s\n", user.Username, user.UserID, user.Role)
+	return nil
+}
+
+// ! issueAPIKeyCommand issues an API key, the first key can only be issued this way
+func issueAPIKeyCommand(args []string) error {
+	flags := flag.NewFlagSet("issue-api-key", flag.ContinueOnError)
+	username := flags.String("username", "", "username of the user owning the key")
+	name := flags.String("name", "", "name telling the key apart from the owner's other keys")
+	scopes := flags.String("scopes", apikey.ScopeReadOnly, "comma separated scopes: READ_ONLY, TRANSFERS, ADMIN")
+	expires := flags.Duration("expires", 0, "how long the key is valid, until revoked when zero")
+	signed := flags.Bool("signed", false, "require requests sent with the key to be signed")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *username == "" || *name == "" {
+		return errors.New("username and name are required")
+	}
+	var keyScopes []string
+	for _, scope := range strings.Split(*scopes, ",") {
+		keyScopes = append(keyScopes, strings.ToUpper(strings.TrimSpace(scope)))
+	}
+	if !apikey.ValidScopes(keyScopes) {
+		return fmt.Errorf("invalid scopes %q", *scopes)
+	}
+	if *expires < 0 {
+		return errors.New("expires must not be negative")
+	}
+
+	if err := setup.InitializeEnvironment(environment_config.DevEnvironment); err != nil {
+		return err
+	}
+	signer, err := signing.SignerFromEnv()
+	if err != nil {
+		return err
+	}
+	if *signed && signer == nil {
+		return fmt.Errorf("signed keys need %s to be set", signing.SecretEnv)
+	}
+
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return err
+	}
+	arg := db.CreateAPIKeyParams{
+		KeyPrefix:       prefix,
+		KeyHash:         hash,
+		Name:            *name,
+		Scopes:          keyScopes,
+		SigningRequired: *signed,
+	}
+	if *expires > 0 {
+		arg.ExpiresAt = sql.NullTime{Time: time.Now().Add(*expires), Valid: true}
+	}
+	issued, err := transaction.IssueAPIKey(context.Background(), *username, arg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("API key %d for %s (%s), it is not shown again:\n%s\n",
+		issued.ApiKeyID, *username, strings.Join(issued.Scopes, ","), key)
+	if signer != nil {
+		fmt.Printf("Request signing secret:\n%s\n", signer.ClientSecret(issued.KeyPrefix))
+	}
+	return nil
+}
+
+func parseAccountIDs(value string) ([]int32, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var ids []int32
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 32)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("invalid account id %q", part)
+		}
+		ids = append(ids, int32(id))
+	}
+	return ids, nil
+}
+
+func nullID(id int32, valid bool) string {
+	if !valid {
+		return "-"
+	}
+	return strconv.FormatInt(int64(id), 10)
+}
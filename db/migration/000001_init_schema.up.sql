@@ -46,10 +46,10 @@ CREATE TABLE accounts (
     user_id INTEGER NOT NULL REFERENCES users(user_id),
     account_number VARCHAR(20) NOT NULL UNIQUE,
     account_type VARCHAR(50) NOT NULL REFERENCES account_types(account_type),
-    balance DECIMAL(15, 2) NOT NULL DEFAULT 0.00,
+    balance DECIMAL(16, 3) NOT NULL DEFAULT 0.00,
     currency_code VARCHAR(3) NOT NULL REFERENCES account_currencies(currency_code),
     interest_rate DECIMAL(5, 2),
-    overdraft_limit DECIMAL(15, 2) DEFAULT 0.00,
+    overdraft_limit DECIMAL(16, 3) DEFAULT 0.00,
     is_active BOOLEAN NOT NULL DEFAULT true,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -82,7 +82,7 @@ CREATE TABLE transactions (
     from_account_id INTEGER REFERENCES accounts(account_id),
     to_account_id INTEGER REFERENCES accounts(account_id),
     type_code VARCHAR(50) NOT NULL REFERENCES transaction_types(type_code),
-    amount DECIMAL(15, 2) NOT NULL,
+    amount DECIMAL(16, 3) NOT NULL,
     currency_code VARCHAR(3) NOT NULL REFERENCES account_currencies(currency_code),
     exchange_rate DECIMAL(10, 6),
     status_code VARCHAR(50) NOT NULL REFERENCES transaction_status(status_code),
@@ -131,7 +131,7 @@ CREATE INDEX idx_audit_trail_composite ON audit_trail(table_name, record_id);
 CREATE TABLE entries (
     id BIGSERIAL PRIMARY KEY,
     account_id INTEGER REFERENCES accounts(account_id),
-    amount DECIMAL(32,2) NOT NULL,
+    amount DECIMAL(32,3) NOT NULL,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -0,0 +1,6 @@
+-- Migration to remove idempotency_keys table
+-- db/migration/000004_add_idempotency_keys.down.sql
+
+DROP INDEX IF EXISTS idx_idempotency_keys_expires_at;
+
+DROP TABLE IF EXISTS idempotency_keys;
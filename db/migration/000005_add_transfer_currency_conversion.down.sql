@@ -0,0 +1,17 @@
+-- Migration to remove currency conversion from transfers
+-- db/migration/000005_add_transfer_currency_conversion.down.sql
+
+-- Rates with more than 6 places are rounded, rates of 10000 and above no longer fit and fail
+ALTER TABLE transactions ALTER COLUMN exchange_rate TYPE DECIMAL(10, 6);
+ALTER TABLE account_currencies ALTER COLUMN exchange_rate TYPE DECIMAL(10, 6);
+
+DROP INDEX IF EXISTS idx_entries_transaction;
+
+ALTER TABLE entries
+DROP COLUMN IF EXISTS exchange_rate,
+DROP COLUMN IF EXISTS currency_code,
+DROP COLUMN IF EXISTS transaction_id;
+
+ALTER TABLE transactions
+DROP COLUMN IF EXISTS converted_currency_code,
+DROP COLUMN IF EXISTS converted_amount;
@@ -0,0 +1,20 @@
+-- Migration to record currency conversion on transfers
+-- db/migration/000005_add_transfer_currency_conversion.up.sql
+
+-- The credited side of a cross-currency transfer, amount stays in the debited currency
+ALTER TABLE transactions
+ADD COLUMN converted_amount DECIMAL(16, 3),
+ADD COLUMN converted_currency_code VARCHAR(3) REFERENCES account_currencies(currency_code);
+
+-- Every entry is booked in the currency of its own account
+ALTER TABLE entries
+ADD COLUMN transaction_id INTEGER REFERENCES transactions(transaction_id),
+ADD COLUMN currency_code VARCHAR(3) REFERENCES account_currencies(currency_code),
+ADD COLUMN exchange_rate NUMERIC(20, 10);
+
+CREATE INDEX idx_entries_transaction ON entries(transaction_id);
+
+-- DECIMAL(10, 6) stops at 9999.999999 and loses most digits of rates like IDR or VND against the
+-- base currency, which derived pair rates such as USD to IDR need
+ALTER TABLE account_currencies ALTER COLUMN exchange_rate TYPE NUMERIC(20, 10);
+ALTER TABLE transactions ALTER COLUMN exchange_rate TYPE NUMERIC(20, 10);
@@ -0,0 +1,7 @@
+-- Migration to remove the link between refunds and their original transaction
+-- db/migration/000006_add_transaction_reversals.down.sql
+
+DROP INDEX IF EXISTS idx_transactions_original;
+
+ALTER TABLE transactions
+DROP COLUMN IF EXISTS original_transaction_id;
@@ -0,0 +1,7 @@
+-- Migration to link refunds and reversals to the transaction they compensate
+-- db/migration/000006_add_transaction_reversals.up.sql
+
+ALTER TABLE transactions
+ADD COLUMN original_transaction_id INTEGER REFERENCES transactions(transaction_id);
+
+CREATE INDEX idx_transactions_original ON transactions(original_transaction_id);
@@ -0,0 +1,19 @@
+-- Migration to remove authorization holds
+-- db/migration/000007_add_authorization_holds.down.sql
+
+DROP TRIGGER IF EXISTS trigger_update_holds_updated_at ON holds;
+
+DROP INDEX IF EXISTS idx_holds_open_expiry;
+DROP INDEX IF EXISTS idx_holds_account_status;
+
+DROP TABLE IF EXISTS holds;
+
+DROP TYPE IF EXISTS hold_status;
+
+ALTER TABLE accounts
+DROP CONSTRAINT accounts_balance_check,
+ADD CONSTRAINT accounts_balance_check CHECK (balance >= - overdraft_limit);
+
+ALTER TABLE accounts
+DROP CONSTRAINT IF EXISTS accounts_held_amount_check,
+DROP COLUMN IF EXISTS held_amount;
@@ -0,0 +1,49 @@
+-- Migration to add authorization holds
+-- db/migration/000007_add_authorization_holds.up.sql
+
+-- Funds reserved by open holds, the available balance is balance - held_amount
+ALTER TABLE accounts
+ADD COLUMN held_amount DECIMAL(16, 3) NOT NULL DEFAULT 0.00,
+ADD CONSTRAINT accounts_held_amount_check CHECK (held_amount >= 0);
+
+-- Open holds count against the overdraft limit like posted debits do
+ALTER TABLE accounts
+DROP CONSTRAINT accounts_balance_check,
+ADD CONSTRAINT accounts_balance_check CHECK (balance - held_amount >= - overdraft_limit);
+
+-- Create hold status enum type
+CREATE TYPE hold_status AS ENUM (
+    'OPEN',
+    'CAPTURED',
+    'RELEASED',
+    'EXPIRED'
+);
+
+-- Create holds table
+CREATE TABLE IF NOT EXISTS holds (
+    hold_id SERIAL PRIMARY KEY,
+    hold_number UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    account_id INTEGER NOT NULL REFERENCES accounts(account_id),
+    merchant_account_id INTEGER NOT NULL REFERENCES accounts(account_id),
+    transaction_id INTEGER NOT NULL REFERENCES transactions(transaction_id),
+    amount DECIMAL(16, 3) NOT NULL,
+    captured_amount DECIMAL(16, 3) NOT NULL DEFAULT 0.00,
+    currency_code VARCHAR(3) NOT NULL REFERENCES account_currencies(currency_code),
+    status hold_status NOT NULL DEFAULT 'OPEN',
+    description TEXT,
+    expires_at TIMESTAMPTZ NOT NULL,
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT holds_amount_check CHECK (amount > 0),
+    CONSTRAINT holds_captured_amount_check CHECK (captured_amount >= 0 AND captured_amount <= amount),
+    CONSTRAINT holds_accounts_check CHECK (account_id != merchant_account_id)
+);
+
+CREATE INDEX idx_holds_account_status ON holds(account_id, status);
+CREATE INDEX idx_holds_open_expiry ON holds(expires_at) WHERE status = 'OPEN';
+
+CREATE TRIGGER trigger_update_holds_updated_at
+BEFORE UPDATE ON holds
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,17 @@
+-- Migration to remove scheduled and recurring transfers
+-- db/migration/000008_add_scheduled_transfers.down.sql
+
+DROP INDEX IF EXISTS idx_scheduled_transfer_executions_schedule;
+
+DROP TABLE IF EXISTS scheduled_transfer_executions;
+
+DROP TRIGGER IF EXISTS trigger_update_scheduled_transfers_updated_at ON scheduled_transfers;
+
+DROP INDEX IF EXISTS idx_scheduled_transfers_from_account;
+DROP INDEX IF EXISTS idx_scheduled_transfers_due;
+
+DROP TABLE IF EXISTS scheduled_transfers;
+
+DROP TYPE IF EXISTS scheduled_execution_status;
+DROP TYPE IF EXISTS scheduled_transfer_status;
+DROP TYPE IF EXISTS schedule_frequency;
@@ -0,0 +1,80 @@
+-- Migration to add scheduled and recurring transfers
+-- db/migration/000008_add_scheduled_transfers.up.sql
+
+-- Create schedule frequency enum type
+CREATE TYPE schedule_frequency AS ENUM (
+    'ONCE',
+    'DAILY',
+    'WEEKLY',
+    'MONTHLY',
+    'CRON'
+);
+
+-- Create scheduled transfer status enum type
+CREATE TYPE scheduled_transfer_status AS ENUM (
+    'ACTIVE',
+    'PAUSED',
+    'COMPLETED',
+    'FAILED',
+    'CANCELLED'
+);
+
+-- Create scheduled execution status enum type
+CREATE TYPE scheduled_execution_status AS ENUM (
+    'SUCCEEDED',
+    'FAILED'
+);
+
+-- Standing orders; next_run_at is the occurrence being executed and due_at is when the
+-- scheduler tries it next, they only differ while a failed occurrence is being retried
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    scheduled_transfer_id SERIAL PRIMARY KEY,
+    schedule_number UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    from_account_id INTEGER NOT NULL REFERENCES accounts(account_id),
+    to_account_id INTEGER NOT NULL REFERENCES accounts(account_id),
+    amount DECIMAL(16, 3) NOT NULL,
+    currency_code VARCHAR(3) NOT NULL REFERENCES account_currencies(currency_code),
+    description TEXT,
+    frequency schedule_frequency NOT NULL,
+    cron_expression VARCHAR(100),
+    status scheduled_transfer_status NOT NULL DEFAULT 'ACTIVE',
+    start_at TIMESTAMPTZ NOT NULL,
+    end_at TIMESTAMPTZ,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    due_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    max_retries INTEGER NOT NULL DEFAULT 3,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- The owner of the sender account when the schedule was created, a run paying from an account
+    -- that changed hands since cancels the schedule
+    user_id INT REFERENCES users(user_id),
+    CONSTRAINT scheduled_transfers_amount_check CHECK (amount > 0),
+    CONSTRAINT scheduled_transfers_accounts_check CHECK (from_account_id != to_account_id),
+    CONSTRAINT scheduled_transfers_cron_check CHECK ((frequency = 'CRON') = (cron_expression IS NOT NULL)),
+    CONSTRAINT scheduled_transfers_end_check CHECK (end_at IS NULL OR end_at >= start_at),
+    CONSTRAINT scheduled_transfers_retries_check CHECK (retry_count >= 0 AND max_retries >= 0)
+);
+
+CREATE INDEX idx_scheduled_transfers_due ON scheduled_transfers(due_at) WHERE status = 'ACTIVE';
+CREATE INDEX idx_scheduled_transfers_from_account ON scheduled_transfers(from_account_id);
+
+CREATE TRIGGER trigger_update_scheduled_transfers_updated_at
+BEFORE UPDATE ON scheduled_transfers
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- One row per attempt, successful or not
+CREATE TABLE IF NOT EXISTS scheduled_transfer_executions (
+    execution_id BIGSERIAL PRIMARY KEY,
+    scheduled_transfer_id INTEGER NOT NULL REFERENCES scheduled_transfers(scheduled_transfer_id) ON DELETE CASCADE,
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    attempt INTEGER NOT NULL,
+    status scheduled_execution_status NOT NULL,
+    transaction_id INTEGER REFERENCES transactions(transaction_id),
+    error_message TEXT,
+    executed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_scheduled_transfer_executions_schedule ON scheduled_transfer_executions(scheduled_transfer_id, executed_at);
@@ -0,0 +1,14 @@
+-- Migration to remove batch transfers
+-- db/migration/000009_add_transfer_batches.down.sql
+
+DROP TABLE IF EXISTS transfer_batch_items;
+
+DROP TRIGGER IF EXISTS trigger_update_transfer_batches_updated_at ON transfer_batches;
+
+DROP INDEX IF EXISTS idx_transfer_batches_status;
+
+DROP TABLE IF EXISTS transfer_batches;
+
+DROP TYPE IF EXISTS batch_item_status;
+DROP TYPE IF EXISTS batch_status;
+DROP TYPE IF EXISTS batch_mode;
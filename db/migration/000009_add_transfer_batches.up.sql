@@ -0,0 +1,68 @@
+-- Migration to add batch transfers
+-- db/migration/000009_add_transfer_batches.up.sql
+
+-- Create batch mode enum type
+CREATE TYPE batch_mode AS ENUM (
+    'ALL_OR_NOTHING',
+    'BEST_EFFORT'
+);
+
+-- Create batch status enum type, UPLOADED batches wait for their CSV file to be imported
+CREATE TYPE batch_status AS ENUM (
+    'UPLOADED',
+    'PENDING',
+    'PROCESSING',
+    'COMPLETED',
+    'PARTIALLY_COMPLETED',
+    'FAILED'
+);
+
+-- Create batch item status enum type
+CREATE TYPE batch_item_status AS ENUM (
+    'PENDING',
+    'SUCCEEDED',
+    'FAILED'
+);
+
+-- Create transfer_batches table
+CREATE TABLE IF NOT EXISTS transfer_batches (
+    batch_id SERIAL PRIMARY KEY,
+    batch_number UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    mode batch_mode NOT NULL,
+    status batch_status NOT NULL DEFAULT 'PENDING',
+    reference_number VARCHAR(50) UNIQUE,
+    upload_id VARCHAR(36) UNIQUE REFERENCES upload_jobs(id),
+    total_items INTEGER NOT NULL DEFAULT 0,
+    processed_items INTEGER NOT NULL DEFAULT 0,
+    succeeded_items INTEGER NOT NULL DEFAULT 0,
+    failed_items INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_transfer_batches_status ON transfer_batches(status, created_at);
+
+CREATE TRIGGER trigger_update_transfer_batches_updated_at
+BEFORE UPDATE ON transfer_batches
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Accounts are not foreign keys here, an unknown account fails its item instead of the whole import
+CREATE TABLE IF NOT EXISTS transfer_batch_items (
+    item_id BIGSERIAL PRIMARY KEY,
+    batch_id INTEGER NOT NULL REFERENCES transfer_batches(batch_id) ON DELETE CASCADE,
+    item_index INTEGER NOT NULL,
+    from_account_id INTEGER NOT NULL,
+    to_account_id INTEGER NOT NULL,
+    amount DECIMAL(16, 3) NOT NULL,
+    currency_code VARCHAR(3) NOT NULL,
+    description TEXT,
+    status batch_item_status NOT NULL DEFAULT 'PENDING',
+    transaction_id INTEGER REFERENCES transactions(transaction_id),
+    error_message TEXT,
+    processed_at TIMESTAMPTZ,
+    CONSTRAINT transfer_batch_items_index_unique UNIQUE (batch_id, item_index)
+);
@@ -0,0 +1,14 @@
+-- Migration to remove ledger reconciliation
+-- db/migration/000010_add_ledger_reconciliation.down.sql
+
+DROP INDEX IF EXISTS idx_ledger_discrepancies_account;
+DROP INDEX IF EXISTS idx_ledger_discrepancies_run;
+
+DROP TABLE IF EXISTS ledger_discrepancies;
+
+DROP INDEX IF EXISTS idx_reconciliation_runs_started;
+
+DROP TABLE IF EXISTS reconciliation_runs;
+
+DROP TYPE IF EXISTS discrepancy_type;
+DROP TYPE IF EXISTS reconciliation_status;
@@ -0,0 +1,51 @@
+-- Migration to add ledger reconciliation
+-- db/migration/000010_add_ledger_reconciliation.up.sql
+
+-- Create reconciliation run status enum type
+CREATE TYPE reconciliation_status AS ENUM (
+    'RUNNING',
+    'COMPLETED',
+    'FAILED'
+);
+
+-- Create discrepancy type enum type
+CREATE TYPE discrepancy_type AS ENUM (
+    'BALANCE_MISMATCH',
+    'UNBALANCED_TRANSACTION',
+    'MISSING_ENTRIES'
+);
+
+-- Create reconciliation_runs table, account_ids is NULL when every account was checked
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    run_id SERIAL PRIMARY KEY,
+    run_number UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    account_ids INTEGER[],
+    status reconciliation_status NOT NULL DEFAULT 'RUNNING',
+    accounts_checked INTEGER NOT NULL DEFAULT 0,
+    transactions_checked INTEGER NOT NULL DEFAULT 0,
+    discrepancy_count INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_reconciliation_runs_started ON reconciliation_runs(started_at);
+
+-- Create ledger_discrepancies table. expected is what the ledger says, actual what was found:
+-- the entries sum against accounts.balance, or zero against the net of a transaction's entries.
+CREATE TABLE IF NOT EXISTS ledger_discrepancies (
+    discrepancy_id BIGSERIAL PRIMARY KEY,
+    run_id INTEGER NOT NULL REFERENCES reconciliation_runs(run_id) ON DELETE CASCADE,
+    discrepancy_type discrepancy_type NOT NULL,
+    account_id INTEGER REFERENCES accounts(account_id) ON DELETE SET NULL,
+    transaction_id INTEGER REFERENCES transactions(transaction_id) ON DELETE SET NULL,
+    currency_code VARCHAR(3),
+    expected_amount DECIMAL(32, 3) NOT NULL DEFAULT 0,
+    actual_amount DECIMAL(32, 3) NOT NULL DEFAULT 0,
+    difference DECIMAL(32, 3) NOT NULL DEFAULT 0,
+    details TEXT,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ledger_discrepancies_run ON ledger_discrepancies(run_id);
+CREATE INDEX idx_ledger_discrepancies_account ON ledger_discrepancies(account_id);
@@ -0,0 +1,13 @@
+-- Migration to remove interest accrual and posting
+-- db/migration/000011_add_interest_accrual.down.sql
+
+DROP INDEX IF EXISTS idx_interest_accruals_unposted;
+
+DROP TABLE IF EXISTS interest_accruals;
+
+DROP TRIGGER IF EXISTS trigger_update_interest_settings_updated_at ON interest_settings;
+
+DROP TABLE IF EXISTS interest_settings;
+
+DROP TYPE IF EXISTS interest_posting_period;
+DROP TYPE IF EXISTS day_count_convention;
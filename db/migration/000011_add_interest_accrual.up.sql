@@ -0,0 +1,57 @@
+-- Migration to add interest accrual and posting
+-- db/migration/000011_add_interest_accrual.up.sql
+
+-- Create day count convention enum type, 30_360 is the 30E/360 convention
+CREATE TYPE day_count_convention AS ENUM (
+    'ACT_365',
+    '30_360'
+);
+
+-- Create interest posting period enum type
+CREATE TYPE interest_posting_period AS ENUM (
+    'MONTHLY',
+    'QUARTERLY',
+    'ANNUALLY'
+);
+
+-- Create interest_settings table, only accounts whose type has active settings earn interest
+CREATE TABLE IF NOT EXISTS interest_settings (
+    account_type VARCHAR(50) PRIMARY KEY REFERENCES account_types(account_type),
+    day_count_convention day_count_convention NOT NULL DEFAULT 'ACT_365',
+    posting_period interest_posting_period NOT NULL DEFAULT 'MONTHLY',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER trigger_update_interest_settings_updated_at
+BEFORE UPDATE ON interest_settings
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Savings and money market accounts earn interest when their account types already exist
+INSERT INTO interest_settings (account_type)
+SELECT account_type FROM account_types
+WHERE account_type IN ('SAVINGS', 'MONEY_MARKET')
+ON CONFLICT (account_type) DO NOTHING;
+
+-- Create interest_accruals table, one row per account and day. The amount keeps its full
+-- precision and is only rounded when the period is posted as an INTEREST transaction. The residue
+-- of the last accrual of a posting is what rounding left out, the next posting adds it back.
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    accrual_id BIGSERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(account_id),
+    accrual_date DATE NOT NULL,
+    balance DECIMAL(16, 3) NOT NULL,
+    interest_rate DECIMAL(5, 2) NOT NULL,
+    day_count_convention day_count_convention NOT NULL,
+    amount DECIMAL(20, 10) NOT NULL,
+    residue DECIMAL(20, 10) NOT NULL DEFAULT 0,
+    transaction_id INTEGER REFERENCES transactions(transaction_id),
+    posted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT interest_accruals_account_date_unique UNIQUE (account_id, accrual_date)
+);
+
+CREATE INDEX idx_interest_accruals_unposted ON interest_accruals(account_id, accrual_date)
+WHERE transaction_id IS NULL;
@@ -0,0 +1,17 @@
+-- Migration to remove fee schedules and fee charges
+-- db/migration/000012_add_fee_schedules.down.sql
+
+DROP INDEX IF EXISTS idx_fee_charges_related;
+DROP INDEX IF EXISTS idx_fee_charges_account;
+
+DROP TABLE IF EXISTS fee_charges;
+
+DROP TRIGGER IF EXISTS trigger_update_fee_income_accounts_updated_at ON fee_income_accounts;
+
+DROP TABLE IF EXISTS fee_income_accounts;
+
+DROP TRIGGER IF EXISTS trigger_update_fee_schedules_updated_at ON fee_schedules;
+
+DROP TABLE IF EXISTS fee_schedules;
+
+DROP TYPE IF EXISTS fee_type;
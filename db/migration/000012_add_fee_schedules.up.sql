@@ -0,0 +1,69 @@
+-- Migration to add fee schedules and fee charges
+-- db/migration/000012_add_fee_schedules.up.sql
+
+-- Create fee type enum type
+CREATE TYPE fee_type AS ENUM (
+    'TRANSFER',
+    'FX_MARKUP',
+    'MAINTENANCE',
+    'OVERDRAFT'
+);
+
+-- Create fee_schedules table, the fees the accounts of an account type pay. Transfer fees are the
+-- flat amount plus the percentage of the amount sent, the FX markup is a percentage of the amount
+-- of a cross-currency transfer, maintenance is charged monthly and overdraft per overdrawn day.
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    account_type VARCHAR(50) PRIMARY KEY REFERENCES account_types(account_type),
+    transfer_fee_flat DECIMAL(16, 3) NOT NULL DEFAULT 0.00,
+    transfer_fee_percent DECIMAL(7, 4) NOT NULL DEFAULT 0.0000,
+    fx_markup_percent DECIMAL(7, 4) NOT NULL DEFAULT 0.0000,
+    monthly_maintenance_fee DECIMAL(16, 3) NOT NULL DEFAULT 0.00,
+    overdraft_fee DECIMAL(16, 3) NOT NULL DEFAULT 0.00,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fee_schedules_amounts_check CHECK (
+        transfer_fee_flat >= 0
+        AND transfer_fee_percent >= 0
+        AND fx_markup_percent >= 0
+        AND monthly_maintenance_fee >= 0
+        AND overdraft_fee >= 0
+    )
+);
+
+CREATE TRIGGER trigger_update_fee_schedules_updated_at
+BEFORE UPDATE ON fee_schedules
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Create fee_income_accounts table, the account collecting the fees charged in each currency
+CREATE TABLE IF NOT EXISTS fee_income_accounts (
+    currency_code VARCHAR(3) PRIMARY KEY REFERENCES account_currencies(currency_code),
+    account_id INTEGER NOT NULL UNIQUE REFERENCES accounts(account_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER trigger_update_fee_income_accounts_updated_at
+BEFORE UPDATE ON fee_income_accounts
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Create fee_charges table, one row per FEE transaction. Periodic fees carry the start of the
+-- period they pay for, which makes charging them idempotent; transfer fees point at their transfer.
+CREATE TABLE IF NOT EXISTS fee_charges (
+    charge_id BIGSERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts(account_id),
+    fee_type fee_type NOT NULL,
+    amount DECIMAL(16, 3) NOT NULL,
+    currency_code VARCHAR(3) NOT NULL REFERENCES account_currencies(currency_code),
+    transaction_id INTEGER NOT NULL REFERENCES transactions(transaction_id),
+    related_transaction_id INTEGER REFERENCES transactions(transaction_id),
+    period_start DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fee_charges_amount_check CHECK (amount > 0),
+    CONSTRAINT fee_charges_period_unique UNIQUE (account_id, fee_type, period_start)
+);
+
+CREATE INDEX idx_fee_charges_account ON fee_charges(account_id, created_at);
+CREATE INDEX idx_fee_charges_related ON fee_charges(related_transaction_id);
@@ -0,0 +1,28 @@
+-- Migration to remove fixed deposits
+-- db/migration/000013_add_fixed_deposits.down.sql
+
+DROP TRIGGER IF EXISTS trigger_update_fixed_deposit_terms_updated_at ON fixed_deposit_terms;
+
+DROP TABLE IF EXISTS fixed_deposit_terms;
+
+DROP TRIGGER IF EXISTS trigger_update_fixed_deposits_updated_at ON fixed_deposits;
+
+DROP INDEX IF EXISTS idx_fixed_deposits_active_maturity;
+DROP INDEX IF EXISTS idx_fixed_deposits_linked_account;
+
+DROP TABLE IF EXISTS fixed_deposits;
+
+-- Enum values cannot be dropped, so fee_type is recreated without EARLY_BREAK
+DELETE FROM fee_charges WHERE fee_type = 'EARLY_BREAK';
+ALTER TYPE fee_type RENAME TO fee_type_old;
+CREATE TYPE fee_type AS ENUM (
+    'TRANSFER',
+    'FX_MARKUP',
+    'MAINTENANCE',
+    'OVERDRAFT'
+);
+ALTER TABLE fee_charges ALTER COLUMN fee_type TYPE fee_type USING fee_type::TEXT::fee_type;
+DROP TYPE fee_type_old;
+
+DROP TYPE IF EXISTS fixed_deposit_status;
+DROP TYPE IF EXISTS maturity_instruction;
@@ -0,0 +1,75 @@
+-- Migration to add fixed deposits
+-- db/migration/000013_add_fixed_deposits.up.sql
+
+-- What happens to a fixed deposit at maturity: PAYOUT credits principal plus interest to the
+-- linked account, ROLLOVER keeps both in the deposit for another term of the same length
+CREATE TYPE maturity_instruction AS ENUM (
+    'PAYOUT',
+    'ROLLOVER'
+);
+
+-- Create fixed deposit status enum type, BROKEN deposits were closed before maturity
+CREATE TYPE fixed_deposit_status AS ENUM (
+    'ACTIVE',
+    'MATURED',
+    'BROKEN'
+);
+
+-- Breaking a deposit early costs a penalty, charged like any other fee
+ALTER TYPE fee_type ADD VALUE IF NOT EXISTS 'EARLY_BREAK';
+
+-- Create fixed_deposits table. The deposit lives in its own FIXED_DEPOSIT account, funded from and
+-- paid out to the linked account. While a deposit is ACTIVE nothing can be debited from its account.
+CREATE TABLE IF NOT EXISTS fixed_deposits (
+    deposit_id SERIAL PRIMARY KEY,
+    deposit_number UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    account_id INTEGER NOT NULL UNIQUE REFERENCES accounts(account_id),
+    linked_account_id INTEGER NOT NULL REFERENCES accounts(account_id),
+    principal DECIMAL(16, 3) NOT NULL,
+    interest_rate DECIMAL(5, 2) NOT NULL,
+    term_months INTEGER NOT NULL,
+    start_date DATE NOT NULL,
+    maturity_date DATE NOT NULL,
+    maturity_instruction maturity_instruction NOT NULL DEFAULT 'PAYOUT',
+    early_break_penalty_percent DECIMAL(5, 2) NOT NULL DEFAULT 1.00,
+    status fixed_deposit_status NOT NULL DEFAULT 'ACTIVE',
+    rollover_count INTEGER NOT NULL DEFAULT 0,
+    payout_transaction_id INTEGER REFERENCES transactions(transaction_id),
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fixed_deposits_principal_check CHECK (principal > 0),
+    CONSTRAINT fixed_deposits_interest_rate_check CHECK (interest_rate >= 0),
+    CONSTRAINT fixed_deposits_term_check CHECK (term_months > 0),
+    CONSTRAINT fixed_deposits_maturity_check CHECK (maturity_date > start_date),
+    CONSTRAINT fixed_deposits_penalty_check CHECK (early_break_penalty_percent >= 0 AND early_break_penalty_percent <= 100),
+    CONSTRAINT fixed_deposits_accounts_check CHECK (account_id != linked_account_id)
+);
+
+CREATE INDEX idx_fixed_deposits_linked_account ON fixed_deposits(linked_account_id);
+CREATE INDEX idx_fixed_deposits_active_maturity ON fixed_deposits(maturity_date) WHERE status = 'ACTIVE';
+
+CREATE TRIGGER trigger_update_fixed_deposits_updated_at
+BEFORE UPDATE ON fixed_deposits
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Create fixed_deposit_terms table, the interest rate and early break penalty of each term length.
+-- A deposit copies both when it is opened, so changing a term only affects new deposits. Only
+-- active terms can be opened, none is offered until one is configured.
+CREATE TABLE IF NOT EXISTS fixed_deposit_terms (
+    term_months INTEGER PRIMARY KEY,
+    interest_rate DECIMAL(5, 2) NOT NULL,
+    early_break_penalty_percent DECIMAL(5, 2) NOT NULL DEFAULT 1.00,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fixed_deposit_terms_term_check CHECK (term_months > 0 AND term_months <= 120),
+    CONSTRAINT fixed_deposit_terms_interest_rate_check CHECK (interest_rate >= 0),
+    CONSTRAINT fixed_deposit_terms_penalty_check CHECK (early_break_penalty_percent >= 0 AND early_break_penalty_percent <= 100)
+);
+
+CREATE TRIGGER trigger_update_fixed_deposit_terms_updated_at
+BEFORE UPDATE ON fixed_deposit_terms
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
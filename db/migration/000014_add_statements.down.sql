@@ -0,0 +1,13 @@
+-- Migration to remove account statement exports
+-- db/migration/000014_add_statements.down.sql
+
+DROP TRIGGER IF EXISTS trigger_update_statements_updated_at ON statements;
+
+DROP INDEX IF EXISTS idx_entries_account_created_at;
+DROP INDEX IF EXISTS idx_statements_pending;
+DROP INDEX IF EXISTS idx_statements_account;
+
+DROP TABLE IF EXISTS statements;
+
+DROP TYPE IF EXISTS statement_status;
+DROP TYPE IF EXISTS statement_format;
@@ -0,0 +1,49 @@
+-- Migration to add account statement exports
+-- db/migration/000014_add_statements.up.sql
+
+-- Create statement format enum type, CAMT053 is the ISO 20022 bank to customer statement
+CREATE TYPE statement_format AS ENUM (
+    'CSV',
+    'PDF',
+    'OFX',
+    'CAMT053'
+);
+
+-- Create statement status enum type
+CREATE TYPE statement_status AS ENUM (
+    'PENDING',
+    'PROCESSING',
+    'COMPLETED',
+    'FAILED'
+);
+
+-- Create statements table, one row per requested export. The statement job renders PENDING rows and
+-- stores the file, the period covers whole days (UTC) from period_start through period_end.
+CREATE TABLE IF NOT EXISTS statements (
+    statement_id SERIAL PRIMARY KEY,
+    statement_number UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    account_id INTEGER NOT NULL REFERENCES accounts(account_id),
+    format statement_format NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    status statement_status NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    entry_count INTEGER,
+    file_url TEXT,
+    failure_reason TEXT,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT statements_period_check CHECK (period_end >= period_start)
+);
+
+CREATE INDEX idx_statements_account ON statements(account_id);
+CREATE INDEX idx_statements_pending ON statements(statement_id) WHERE status IN ('PENDING', 'PROCESSING');
+
+-- Statements read an account's entries by booking time
+CREATE INDEX idx_entries_account_created_at ON entries(account_id, created_at);
+
+CREATE TRIGGER trigger_update_statements_updated_at
+BEFORE UPDATE ON statements
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
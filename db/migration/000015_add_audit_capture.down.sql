@@ -0,0 +1,22 @@
+-- Migration to stop recording changes in audit_trail
+-- db/migration/000015_add_audit_capture.down.sql
+
+DROP TRIGGER IF EXISTS audit_transactions ON transactions;
+DROP TRIGGER IF EXISTS audit_account_currencies ON account_currencies;
+DROP TRIGGER IF EXISTS audit_account_types ON account_types;
+DROP TRIGGER IF EXISTS audit_accounts ON accounts;
+DROP TRIGGER IF EXISTS audit_users ON users;
+
+DROP FUNCTION IF EXISTS audit_row_change();
+
+DROP INDEX IF EXISTS idx_audit_trail_created_at;
+DROP INDEX IF EXISTS idx_audit_trail_user;
+
+-- Records keyed by code and records of deleted users do not fit the original columns
+DELETE FROM audit_trail WHERE record_id !~ '^[0-9]+$';
+UPDATE audit_trail SET user_id = NULL
+WHERE user_id IS NOT NULL AND user_id NOT IN (SELECT user_id FROM users);
+
+ALTER TABLE audit_trail ALTER COLUMN record_id TYPE INTEGER USING record_id::INTEGER;
+ALTER TABLE audit_trail ADD CONSTRAINT audit_trail_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(user_id);
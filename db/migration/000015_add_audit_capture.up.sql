@@ -0,0 +1,91 @@
+-- Migration to record changes to users, accounts, account types, currencies and transactions
+-- db/migration/000015_add_audit_capture.up.sql
+
+-- Account types and currencies are keyed by code, so record keys are stored as text
+ALTER TABLE audit_trail ALTER COLUMN record_id TYPE VARCHAR(64) USING record_id::VARCHAR;
+
+-- The acting user stays on the record after the user itself is deleted
+ALTER TABLE audit_trail DROP CONSTRAINT IF EXISTS audit_trail_user_id_fkey;
+
+CREATE INDEX idx_audit_trail_user ON audit_trail(user_id, created_at);
+CREATE INDEX idx_audit_trail_created_at ON audit_trail(created_at);
+
+-- audit_row_change writes one audit_trail row per changed row. The first trigger argument names
+-- the key column of the table, further arguments name columns whose values are never copied;
+-- a change to one of them is recorded as '[changed]'. The acting user and IP address come from
+-- the app.user_id and app.ip_address settings the application puts on the connection, changes
+-- made without them (jobs, migrations) are recorded without an actor.
+CREATE OR REPLACE FUNCTION audit_row_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    old_values JSONB;
+    new_values JSONB;
+    hidden_changed BOOLEAN;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_values := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_values := to_jsonb(NEW);
+    END IF;
+
+    FOR i IN 1 .. TG_NARGS - 1 LOOP
+        hidden_changed := TG_OP = 'UPDATE'
+            AND old_values -> TG_ARGV[i] IS DISTINCT FROM new_values -> TG_ARGV[i];
+        old_values := old_values - TG_ARGV[i];
+        new_values := new_values - TG_ARGV[i];
+        IF hidden_changed THEN
+            new_values := new_values || jsonb_build_object(TG_ARGV[i], '[changed]'::TEXT);
+        END IF;
+    END LOOP;
+
+    -- Updates that only moved updated_at are not worth a record
+    IF TG_OP = 'UPDATE' AND old_values - 'updated_at' = new_values - 'updated_at' THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO audit_trail (
+        table_name,
+        record_id,
+        action,
+        old_values,
+        new_values,
+        user_id,
+        ip_address
+    ) VALUES (
+        TG_TABLE_NAME,
+        COALESCE(new_values, old_values) ->> TG_ARGV[0],
+        TG_OP,
+        old_values,
+        new_values,
+        NULLIF(current_setting('app.user_id', true), '')::INTEGER,
+        NULLIF(current_setting('app.ip_address', true), '')
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_users
+    AFTER INSERT OR UPDATE OR DELETE ON users
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_row_change('user_id', 'password_hash');
+
+CREATE TRIGGER audit_accounts
+    AFTER INSERT OR UPDATE OR DELETE ON accounts
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_row_change('account_id');
+
+CREATE TRIGGER audit_account_types
+    AFTER INSERT OR UPDATE OR DELETE ON account_types
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_row_change('account_type');
+
+CREATE TRIGGER audit_account_currencies
+    AFTER INSERT OR UPDATE OR DELETE ON account_currencies
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_row_change('currency_code');
+
+CREATE TRIGGER audit_transactions
+    AFTER INSERT OR UPDATE OR DELETE ON transactions
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_row_change('transaction_id');
@@ -0,0 +1,31 @@
+-- Migration to remove the audit_trail and entries hash chains
+-- db/migration/000016_add_hash_chains.down.sql
+
+DROP TRIGGER IF EXISTS seal_entries ON entries;
+DROP TRIGGER IF EXISTS seal_audit_trail ON audit_trail;
+DROP TRIGGER IF EXISTS protect_entries_chain ON entries;
+DROP TRIGGER IF EXISTS protect_audit_trail_chain ON audit_trail;
+
+DROP FUNCTION IF EXISTS protect_hash_chain();
+DROP FUNCTION IF EXISTS seal_entry();
+DROP FUNCTION IF EXISTS seal_audit_trail();
+DROP FUNCTION IF EXISTS seal_entry_row(BIGINT);
+DROP FUNCTION IF EXISTS seal_audit_trail_row(INTEGER);
+DROP FUNCTION IF EXISTS append_hash_chain(TEXT, TEXT);
+DROP FUNCTION IF EXISTS entries_chain_payload(entries);
+DROP FUNCTION IF EXISTS audit_trail_chain_payload(audit_trail);
+DROP FUNCTION IF EXISTS chain_timestamp(TIMESTAMPTZ);
+DROP FUNCTION IF EXISTS chain_field(TEXT);
+
+DROP TABLE IF EXISTS hash_chain_checkpoints;
+DROP TABLE IF EXISTS hash_chain_heads;
+
+ALTER TABLE entries
+DROP COLUMN IF EXISTS row_hash,
+DROP COLUMN IF EXISTS prev_hash,
+DROP COLUMN IF EXISTS chain_seq;
+
+ALTER TABLE audit_trail
+DROP COLUMN IF EXISTS row_hash,
+DROP COLUMN IF EXISTS prev_hash,
+DROP COLUMN IF EXISTS chain_seq;
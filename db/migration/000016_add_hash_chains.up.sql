@@ -0,0 +1,216 @@
+-- Migration to make audit_trail and entries tamper evident with hash chains
+-- db/migration/000016_add_hash_chains.up.sql
+
+-- Every row links to the row before it: row_hash covers the row's content, its position in the
+-- chain and prev_hash, the row_hash of the previous link. Rows are linked when their database
+-- transaction commits, so the chain follows commit order rather than id order.
+ALTER TABLE audit_trail
+ADD COLUMN chain_seq BIGINT UNIQUE,
+ADD COLUMN prev_hash CHAR(64),
+ADD COLUMN row_hash CHAR(64);
+
+ALTER TABLE entries
+ADD COLUMN chain_seq BIGINT UNIQUE,
+ADD COLUMN prev_hash CHAR(64),
+ADD COLUMN row_hash CHAR(64);
+
+-- The last link of each chain, a chain starts from a hash of zeros
+CREATE TABLE hash_chain_heads (
+    chain_name VARCHAR(50) PRIMARY KEY,
+    last_seq BIGINT NOT NULL DEFAULT 0,
+    last_hash CHAR(64) NOT NULL DEFAULT repeat('0', 64),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO hash_chain_heads (chain_name) VALUES ('audit_trail'), ('entries');
+
+-- Signed snapshots of a chain head, a rewritten chain no longer matches them
+CREATE TABLE hash_chain_checkpoints (
+    checkpoint_id SERIAL PRIMARY KEY,
+    chain_name VARCHAR(50) NOT NULL REFERENCES hash_chain_heads(chain_name),
+    chain_seq BIGINT NOT NULL,
+    row_hash CHAR(64) NOT NULL,
+    signature TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (chain_name, chain_seq)
+);
+
+-- chain_field encodes one value for hashing as its length in bytes and the value, NULL as '-',
+-- so no two different rows encode the same way
+CREATE OR REPLACE FUNCTION chain_field(value TEXT)
+RETURNS TEXT AS $$
+    SELECT CASE WHEN value IS NULL THEN '-' ELSE octet_length(value) || ':' || value END;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- chain_timestamp renders a timestamp the same way whatever the session time zone
+CREATE OR REPLACE FUNCTION chain_timestamp(value TIMESTAMPTZ)
+RETURNS TEXT AS $$
+    SELECT to_char(value AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"');
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION audit_trail_chain_payload(r audit_trail)
+RETURNS TEXT AS $$
+    SELECT chain_field(r.audit_id::TEXT)
+        || chain_field(r.table_name)
+        || chain_field(r.record_id)
+        || chain_field(r.action)
+        || chain_field(r.old_values::TEXT)
+        || chain_field(r.new_values::TEXT)
+        || chain_field(r.user_id::TEXT)
+        || chain_field(r.ip_address)
+        || chain_field(chain_timestamp(r.created_at));
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION entries_chain_payload(r entries)
+RETURNS TEXT AS $$
+    SELECT chain_field(r.id::TEXT)
+        || chain_field(r.account_id::TEXT)
+        || chain_field(r.amount::TEXT)
+        || chain_field(chain_timestamp(r.created_at))
+        || chain_field(r.transaction_id::TEXT)
+        || chain_field(r.currency_code)
+        || chain_field(r.exchange_rate::TEXT);
+$$ LANGUAGE sql STABLE;
+
+-- append_hash_chain moves the head of a chain one link forward and returns the new link. It locks
+-- the head of its chain together with the heads named before it, in name order: a transaction that
+-- seals rows of several chains always takes their heads in the same order, whichever row its
+-- triggers seal first, so committing transactions cannot deadlock. Sealing entries therefore also
+-- waits for audit records being sealed, only a transaction sealing audit records alone takes a
+-- single head.
+CREATE OR REPLACE FUNCTION append_hash_chain(chain TEXT, payload TEXT,
+    OUT seq BIGINT, OUT prev_hash TEXT, OUT row_hash TEXT) AS $$
+BEGIN
+    -- A head removed together with its rows starts again from zero
+    INSERT INTO hash_chain_heads (chain_name) VALUES (chain) ON CONFLICT DO NOTHING;
+    PERFORM 1 FROM hash_chain_heads WHERE chain_name <= chain ORDER BY chain_name FOR UPDATE;
+
+    SELECT last_seq + 1, last_hash INTO seq, prev_hash
+    FROM hash_chain_heads
+    WHERE chain_name = chain;
+
+    row_hash := encode(sha256(convert_to(prev_hash || chain_field(seq::TEXT) || payload, 'UTF8')), 'hex');
+
+    UPDATE hash_chain_heads
+    SET last_seq = seq,
+        last_hash = row_hash,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE chain_name = chain;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION seal_audit_trail_row(id INTEGER)
+RETURNS VOID AS $$
+DECLARE
+    r audit_trail%ROWTYPE;
+    link RECORD;
+BEGIN
+    SELECT * INTO r FROM audit_trail WHERE audit_id = id;
+    IF NOT FOUND OR r.row_hash IS NOT NULL THEN
+        RETURN;
+    END IF;
+
+    SELECT * INTO link FROM append_hash_chain('audit_trail', audit_trail_chain_payload(r));
+    UPDATE audit_trail
+    SET chain_seq = link.seq,
+        prev_hash = link.prev_hash,
+        row_hash = link.row_hash
+    WHERE audit_id = id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION seal_entry_row(entry_id BIGINT)
+RETURNS VOID AS $$
+DECLARE
+    r entries%ROWTYPE;
+    link RECORD;
+BEGIN
+    SELECT * INTO r FROM entries WHERE id = entry_id;
+    IF NOT FOUND OR r.row_hash IS NOT NULL THEN
+        RETURN;
+    END IF;
+
+    SELECT * INTO link FROM append_hash_chain('entries', entries_chain_payload(r));
+    UPDATE entries
+    SET chain_seq = link.seq,
+        prev_hash = link.prev_hash,
+        row_hash = link.row_hash
+    WHERE id = entry_id;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Link the rows written before the chains existed, oldest first
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN SELECT audit_id FROM audit_trail ORDER BY audit_id LOOP
+        PERFORM seal_audit_trail_row(r.audit_id);
+    END LOOP;
+    FOR r IN SELECT id FROM entries ORDER BY id LOOP
+        PERFORM seal_entry_row(r.id);
+    END LOOP;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION seal_audit_trail()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM seal_audit_trail_row(NEW.audit_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION seal_entry()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM seal_entry_row(NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- protect_hash_chain keeps the chain columns for the sealing triggers and refuses to change or
+-- delete a row once it is linked
+CREATE OR REPLACE FUNCTION protect_hash_chain()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        NEW.chain_seq := NULL;
+        NEW.prev_hash := NULL;
+        NEW.row_hash := NULL;
+        RETURN NEW;
+    END IF;
+
+    IF OLD.row_hash IS NOT NULL THEN
+        RAISE EXCEPTION '% rows are part of a hash chain and cannot be changed', TG_TABLE_NAME
+            USING ERRCODE = 'restrict_violation';
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER protect_audit_trail_chain
+    BEFORE INSERT OR UPDATE OR DELETE ON audit_trail
+    FOR EACH ROW
+    EXECUTE FUNCTION protect_hash_chain();
+
+CREATE TRIGGER protect_entries_chain
+    BEFORE INSERT OR UPDATE OR DELETE ON entries
+    FOR EACH ROW
+    EXECUTE FUNCTION protect_hash_chain();
+
+-- Rows are linked at commit, after everything else the transaction locks
+CREATE CONSTRAINT TRIGGER seal_audit_trail
+    AFTER INSERT ON audit_trail
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    EXECUTE FUNCTION seal_audit_trail();
+
+CREATE CONSTRAINT TRIGGER seal_entries
+    AFTER INSERT ON entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    EXECUTE FUNCTION seal_entry();
@@ -0,0 +1,7 @@
+-- Migration to remove the exchange rate history
+-- db/migration/000017_add_exchange_rate_history.down.sql
+
+DROP TRIGGER IF EXISTS record_account_currency_rate ON account_currencies;
+DROP FUNCTION IF EXISTS record_exchange_rate();
+
+DROP TABLE IF EXISTS exchange_rate_history;
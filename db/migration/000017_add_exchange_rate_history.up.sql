@@ -0,0 +1,44 @@
+-- Migration to keep every exchange rate a currency had, with the time it became valid
+-- db/migration/000017_add_exchange_rate_history.up.sql
+
+-- Rates are stored like account_currencies.exchange_rate, as the value of one unit of the
+-- currency in the base currency. A rate is valid from valid_from until the next one.
+CREATE TABLE exchange_rate_history (
+    rate_id BIGSERIAL PRIMARY KEY,
+    currency_code VARCHAR(3) NOT NULL REFERENCES account_currencies(currency_code) ON DELETE CASCADE,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    source VARCHAR(50) NOT NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (currency_code, valid_from)
+);
+
+CREATE INDEX idx_exchange_rate_history_lookup ON exchange_rate_history(currency_code, valid_from DESC);
+
+-- Rates set directly on account_currencies are kept as well, so the history never misses the
+-- rate a transfer was converted with. A rate already recorded for the same time is left alone.
+CREATE OR REPLACE FUNCTION record_exchange_rate()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.exchange_rate IS NULL OR NEW.exchange_rate <= 0 THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'UPDATE' AND NEW.exchange_rate IS NOT DISTINCT FROM OLD.exchange_rate THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO exchange_rate_history (currency_code, rate, source, valid_from)
+    VALUES (NEW.currency_code, NEW.exchange_rate, 'manual', COALESCE(NEW.last_updated_at, CURRENT_TIMESTAMP))
+    ON CONFLICT (currency_code, valid_from) DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_account_currency_rate
+AFTER INSERT OR UPDATE OF exchange_rate ON account_currencies
+FOR EACH ROW EXECUTE FUNCTION record_exchange_rate();
+
+INSERT INTO exchange_rate_history (currency_code, rate, source, valid_from)
+SELECT currency_code, exchange_rate, 'manual', COALESCE(last_updated_at, created_at)
+FROM account_currencies
+WHERE exchange_rate > 0;
@@ -0,0 +1,6 @@
+-- Migration to remove the minor units of the currency registry
+-- db/migration/000018_add_currency_registry.down.sql
+
+DROP INDEX IF EXISTS idx_accounts_currency_code;
+
+ALTER TABLE account_currencies DROP COLUMN IF EXISTS minor_units;
@@ -0,0 +1,9 @@
+-- Migration to turn account_currencies into a currency registry with ISO 4217 minor units
+-- db/migration/000018_add_currency_registry.up.sql
+
+-- minor_units is the number of decimals amounts in the currency are written with. The ISO 4217
+-- currencies are seeded, and their minor units corrected, by the application when it starts.
+ALTER TABLE account_currencies
+ADD COLUMN minor_units SMALLINT NOT NULL DEFAULT 2 CHECK (minor_units BETWEEN 0 AND 4);
+
+CREATE INDEX idx_accounts_currency_code ON accounts(currency_code);
@@ -0,0 +1,6 @@
+-- Migration to remove the refresh tokens and the owner of transfer batches
+-- db/migration/000019_add_refresh_tokens.down.sql
+
+ALTER TABLE transfer_batches DROP COLUMN IF EXISTS user_id;
+
+DROP TABLE IF EXISTS refresh_tokens;
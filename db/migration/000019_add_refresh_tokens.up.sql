@@ -0,0 +1,25 @@
+-- Migration to store the refresh tokens issued at login and the owner of transfer batches
+-- db/migration/000019_add_refresh_tokens.up.sql
+
+-- Only the SHA-256 of a token is stored. Every refresh replaces the token with a new one of the same
+-- family; a replaced token presented again revokes the whole family, since it means it was copied.
+CREATE TABLE refresh_tokens (
+    token_id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    family_id UUID NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    replaced_by BIGINT REFERENCES refresh_tokens(token_id),
+    user_agent VARCHAR(255),
+    ip_address VARCHAR(45),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+
+-- The user whose access token submitted a batch, NULL for batches submitted without one. Items
+-- paying from an account of another user fail when the batch is processed.
+ALTER TABLE transfer_batches
+ADD COLUMN user_id INT REFERENCES users(user_id);
@@ -0,0 +1,6 @@
+-- Migration to remove user roles
+-- db/migration/000020_add_user_roles.down.sql
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+
+DROP TYPE IF EXISTS user_role;
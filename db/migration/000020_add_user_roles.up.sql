@@ -0,0 +1,14 @@
+-- Migration to give every user a role deciding which operations they may perform
+-- db/migration/000020_add_user_roles.up.sql
+
+-- Create user role enum type, the permissions of each role are defined by the application
+CREATE TYPE user_role AS ENUM (
+    'CUSTOMER',
+    'SUPPORT',
+    'ADMIN',
+    'AUDITOR'
+);
+
+-- Existing and newly signed up users are customers, other roles are granted by an admin
+ALTER TABLE users
+ADD COLUMN role user_role NOT NULL DEFAULT 'CUSTOMER';
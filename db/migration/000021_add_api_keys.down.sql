@@ -0,0 +1,4 @@
+-- Migration to remove API keys
+-- db/migration/000021_add_api_keys.down.sql
+
+DROP TABLE IF EXISTS api_keys;
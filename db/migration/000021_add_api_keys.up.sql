@@ -0,0 +1,26 @@
+-- Migration to store the API keys clients identify themselves with
+-- db/migration/000021_add_api_keys.up.sql
+
+-- A key is bsk_<key_prefix>_<secret>. The prefix finds the key, only the SHA-256 of the whole key is
+-- stored. Scopes limit what a client may do whoever is logged in through it: READ_ONLY clients only
+-- read, TRANSFERS clients also write, ADMIN clients may call back office routes too.
+CREATE TABLE api_keys (
+    api_key_id SERIAL PRIMARY KEY,
+    key_prefix VARCHAR(16) NOT NULL UNIQUE,
+    key_hash CHAR(64) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    owner_user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    scopes VARCHAR(20)[] NOT NULL,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    -- The key this one replaced, both work until the old key expires
+    rotated_from INT REFERENCES api_keys(api_key_id),
+    created_by INT REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT api_keys_scopes_check CHECK (
+        cardinality(scopes) > 0 AND scopes <@ ARRAY['READ_ONLY', 'TRANSFERS', 'ADMIN']::VARCHAR(20)[]
+    )
+);
+
+CREATE INDEX idx_api_keys_owner_user_id ON api_keys(owner_user_id);
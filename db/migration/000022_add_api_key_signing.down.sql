@@ -0,0 +1,4 @@
+-- Migration to stop API keys from requiring signed requests
+-- db/migration/000022_add_api_key_signing.down.sql
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS signing_required;
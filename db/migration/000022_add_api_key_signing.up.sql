@@ -0,0 +1,6 @@
+-- Migration to let API keys require signed requests
+-- db/migration/000022_add_api_key_signing.up.sql
+
+-- Requests sent with a key requiring signatures are refused unless they are signed with the secret
+-- of the key. Other keys may still sign requests, their signatures are checked too.
+ALTER TABLE api_keys ADD COLUMN signing_required BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+-- Migration to remove the TOTP second factor of users
+-- db/migration/000023_add_two_factor.down.sql
+
+DROP TABLE IF EXISTS user_two_factor;
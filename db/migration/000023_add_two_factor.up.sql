@@ -0,0 +1,18 @@
+-- Migration to store the TOTP second factor of users
+-- db/migration/000023_add_two_factor.up.sql
+
+-- One row per user who started enrolling. The TOTP secret is kept apart from users so it never
+-- reaches the audit trail or the user cache. enabled_at stays NULL until a first code confirms the
+-- enrollment. Recovery codes are random, only their SHA-256 is kept and each works once.
+-- last_used_step is the time step of the last accepted code, a code is never accepted twice.
+CREATE TABLE user_two_factor (
+    user_id INT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at TIMESTAMPTZ,
+    recovery_code_hashes VARCHAR(64)[] NOT NULL DEFAULT '{}',
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -14,6 +14,10 @@ INSERT INTO accounts (
 SELECT * FROM accounts
 WHERE account_id = $1;
 
+-- name: GetAccountByNumber :one
+SELECT * FROM accounts
+WHERE account_number = $1;
+
 -- name: GetAccountForUpdate :one
 SELECT * FROM accounts
 WHERE account_id = $1
@@ -30,11 +34,47 @@ SET balance = balance + sqlc.arg('amount')
 WHERE account_id = sqlc.arg('account_id')
 RETURNING *;
 
+-- name: UpdateAccountHeldAmount :one
+UPDATE accounts
+SET held_amount = held_amount + sqlc.arg('amount')
+WHERE account_id = sqlc.arg('account_id')
+RETURNING *;
+
+-- name: UpdateAccountTerms :one
+-- Sets the interest rate and overdraft limit of an account, a NULL keeps the current value
+UPDATE accounts
+SET interest_rate = COALESCE(sqlc.narg('interest_rate'), interest_rate),
+    overdraft_limit = COALESCE(sqlc.narg('overdraft_limit'), overdraft_limit)
+WHERE account_id = sqlc.arg('account_id')
+RETURNING *;
+
 -- name: DeleteAccount :exec
 UPDATE accounts
 SET is_active = false
 WHERE account_id = $1;
 
+-- name: CloseAccount :execrows
+-- Deactivates an account that holds no money and that no hold, schedule or deposit still refers
+-- to, it updates no row otherwise
+UPDATE accounts
+SET is_active = false
+WHERE account_id = $1
+  AND balance = 0
+  AND held_amount = 0
+  AND NOT EXISTS (
+      SELECT 1 FROM holds h
+      WHERE h.merchant_account_id = accounts.account_id AND h.status = 'OPEN'
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM scheduled_transfers s
+      WHERE accounts.account_id IN (s.from_account_id, s.to_account_id)
+        AND s.status IN ('ACTIVE', 'PAUSED')
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM fixed_deposits fd
+      WHERE accounts.account_id IN (fd.account_id, fd.linked_account_id) AND fd.status = 'ACTIVE'
+  );
+
 -- name: HardDeleteAccount :exec
 DELETE FROM accounts
 WHERE account_id = $1;
\ No newline at end of file
@@ -4,12 +4,16 @@ INSERT INTO account_currencies (
     currency_name,
     symbol,
     exchange_rate,
+    minor_units,
+    is_active,
     last_updated_at
 ) VALUES (
     sqlc.arg('currency_code'),
     sqlc.arg('currency_name'),
     sqlc.arg('symbol'),
     sqlc.arg('exchange_rate'),
+    COALESCE(sqlc.narg('minor_units'), 2),
+    COALESCE(sqlc.narg('is_active'), true),
     CURRENT_TIMESTAMP
 ) RETURNING *;
 
@@ -17,6 +21,10 @@ INSERT INTO account_currencies (
 SELECT * FROM account_currencies
 WHERE is_active = true;
 
+-- name: ListAllCurrencies :many
+SELECT * FROM account_currencies
+ORDER BY currency_code;
+
 -- name: UpdateExchangeRate :one
 UPDATE account_currencies
 SET 
@@ -25,6 +33,54 @@ SET
 WHERE currency_code = sqlc.arg('currency_code')
 RETURNING *;
 
+-- name: UpdateCurrency :one
+UPDATE account_currencies
+SET
+    currency_name = COALESCE(sqlc.narg('currency_name'), currency_name),
+    symbol = COALESCE(sqlc.narg('symbol'), symbol),
+    minor_units = COALESCE(sqlc.narg('minor_units'), minor_units),
+    is_active = COALESCE(sqlc.narg('is_active'), is_active),
+    updated_at = CURRENT_TIMESTAMP
+WHERE currency_code = sqlc.arg('currency_code')
+RETURNING *;
+
+-- name: SeedCurrencies :execrows
+-- SeedCurrencies adds the currencies missing from the registry and corrects the minor units of
+-- the ones already there; names, symbols, rates and active flags set since are kept
+INSERT INTO account_currencies (
+    currency_code,
+    currency_name,
+    symbol,
+    minor_units,
+    is_active,
+    exchange_rate,
+    last_updated_at
+)
+SELECT
+    seed.currency_code,
+    seed.currency_name,
+    seed.symbol,
+    seed.minor_units,
+    seed.is_active,
+    seed.exchange_rate,
+    CASE WHEN seed.exchange_rate IS NULL THEN NULL ELSE CURRENT_TIMESTAMP END
+FROM unnest(
+    sqlc.arg('currency_codes')::VARCHAR[],
+    sqlc.arg('currency_names')::VARCHAR[],
+    sqlc.arg('symbols')::VARCHAR[],
+    sqlc.arg('minor_units')::SMALLINT[],
+    sqlc.arg('is_active')::BOOLEAN[],
+    sqlc.arg('exchange_rates')::DECIMAL[]
+) AS seed(currency_code, currency_name, symbol, minor_units, is_active, exchange_rate)
+ON CONFLICT (currency_code) DO UPDATE
+SET minor_units = EXCLUDED.minor_units,
+    updated_at = CURRENT_TIMESTAMP
+WHERE account_currencies.minor_units <> EXCLUDED.minor_units;
+
+-- name: CountAccountsByCurrency :one
+SELECT COUNT(*) FROM accounts
+WHERE currency_code = $1;
+
 -- name: GetCurrency :one
 SELECT * FROM account_currencies
 WHERE currency_code = $1;
@@ -36,4 +92,4 @@ WHERE currency_code = $1;
 
 -- name: HardDeleteCurrency :exec
 DELETE FROM account_currencies
-WHERE currency_code = $1;
\ No newline at end of file
+WHERE currency_code = $1;
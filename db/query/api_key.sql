@@ -0,0 +1,50 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+    key_prefix,
+    key_hash,
+    name,
+    owner_user_id,
+    scopes,
+    expires_at,
+    rotated_from,
+    created_by,
+    signing_required
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: GetAPIKey :one
+SELECT * FROM api_keys
+WHERE api_key_id = $1;
+
+-- name: GetAPIKeyForUpdate :one
+SELECT * FROM api_keys
+WHERE api_key_id = $1
+FOR UPDATE;
+
+-- name: GetAPIKeyByPrefix :one
+SELECT * FROM api_keys
+WHERE key_prefix = $1;
+
+-- name: ListAPIKeys :many
+-- ListAPIKeys returns API keys, newest first. Keys of every owner are listed when owner_user_id is NULL.
+SELECT * FROM api_keys
+WHERE (sqlc.narg(owner_user_id)::INTEGER IS NULL OR owner_user_id = sqlc.narg(owner_user_id))
+ORDER BY api_key_id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE api_key_id = $1 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: SetAPIKeyExpiry :exec
+UPDATE api_keys
+SET expires_at = sqlc.arg(expires_at)
+WHERE api_key_id = sqlc.arg(api_key_id);
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = sqlc.arg(last_used_at)
+WHERE api_key_id = sqlc.arg(api_key_id);
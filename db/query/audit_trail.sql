@@ -0,0 +1,28 @@
+-- name: GetAuditTrail :one
+SELECT * FROM audit_trail
+WHERE audit_id = $1;
+
+-- name: ListAuditTrail :many
+-- ListAuditTrail returns audit records, newest first. Filters left NULL match every record.
+SELECT * FROM audit_trail
+WHERE (sqlc.narg(table_name)::VARCHAR IS NULL OR table_name = sqlc.narg(table_name))
+  AND (sqlc.narg(record_id)::VARCHAR IS NULL OR record_id = sqlc.narg(record_id))
+  AND (sqlc.narg(user_id)::INTEGER IS NULL OR user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(from_time)::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg(to_time))
+ORDER BY created_at DESC, audit_id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CreateAccessDeniedAudit :exec
+-- CreateAccessDeniedAudit records a request refused by access control. The record is keyed by the
+-- route, details holds the method, path, role and why the request was refused.
+INSERT INTO audit_trail (
+    table_name,
+    record_id,
+    action,
+    new_values,
+    user_id,
+    ip_address
+) VALUES (
+    'access_control', sqlc.arg('route'), 'DENIED', sqlc.arg('details'), sqlc.narg('user_id'), sqlc.narg('ip_address')
+);
@@ -1,9 +1,12 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
     account_id,
-    amount
+    amount,
+    transaction_id,
+    currency_code,
+    exchange_rate
 ) VALUES (
-    $1, $2
+    $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetEntry :one
@@ -0,0 +1,48 @@
+-- name: CreateExchangeRateHistory :one
+-- CreateExchangeRateHistory returns no rows when the currency already has a rate valid from the same time
+INSERT INTO exchange_rate_history (
+    currency_code,
+    rate,
+    source,
+    valid_from
+) VALUES (
+    sqlc.arg('currency_code'),
+    sqlc.arg('rate'),
+    sqlc.arg('source'),
+    sqlc.arg('valid_from')
+)
+ON CONFLICT (currency_code, valid_from) DO NOTHING
+RETURNING *;
+
+-- name: GetExchangeRateAt :one
+-- GetExchangeRateAt returns the rate of a currency that was valid at the given time
+SELECT * FROM exchange_rate_history
+WHERE currency_code = sqlc.arg('currency_code')
+  AND valid_from <= sqlc.arg('valid_at')
+ORDER BY valid_from DESC
+LIMIT 1;
+
+-- name: ListExchangeRateHistory :many
+SELECT * FROM exchange_rate_history
+WHERE currency_code = sqlc.arg('currency_code')
+  AND (sqlc.narg('from_time')::TIMESTAMPTZ IS NULL OR valid_from >= sqlc.narg('from_time'))
+  AND (sqlc.narg('to_time')::TIMESTAMPTZ IS NULL OR valid_from < sqlc.narg('to_time'))
+ORDER BY valid_from DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: SyncCurrentExchangeRate :execrows
+-- SyncCurrentExchangeRate sets the current rate of a currency to the latest rate in its history that is already valid
+UPDATE account_currencies
+SET
+    exchange_rate = latest.rate,
+    last_updated_at = latest.valid_from
+FROM (
+    SELECT rate, valid_from FROM exchange_rate_history
+    WHERE exchange_rate_history.currency_code = sqlc.arg('currency_code')
+      AND valid_from <= CURRENT_TIMESTAMP
+    ORDER BY valid_from DESC
+    LIMIT 1
+) AS latest
+WHERE account_currencies.currency_code = sqlc.arg('currency_code')
+  AND account_currencies.exchange_rate IS DISTINCT FROM latest.rate;
@@ -0,0 +1,117 @@
+-- name: UpsertFeeSchedule :one
+INSERT INTO fee_schedules (
+    account_type,
+    transfer_fee_flat,
+    transfer_fee_percent,
+    fx_markup_percent,
+    monthly_maintenance_fee,
+    overdraft_fee,
+    is_active
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (account_type) DO UPDATE
+SET transfer_fee_flat = EXCLUDED.transfer_fee_flat,
+    transfer_fee_percent = EXCLUDED.transfer_fee_percent,
+    fx_markup_percent = EXCLUDED.fx_markup_percent,
+    monthly_maintenance_fee = EXCLUDED.monthly_maintenance_fee,
+    overdraft_fee = EXCLUDED.overdraft_fee,
+    is_active = EXCLUDED.is_active
+RETURNING *;
+
+-- name: GetFeeSchedule :one
+SELECT * FROM fee_schedules
+WHERE account_type = $1;
+
+-- name: GetActiveFeeScheduleByAccount :one
+SELECT s.* FROM fee_schedules s
+JOIN accounts a ON a.account_type = s.account_type
+WHERE a.account_id = $1 AND s.is_active;
+
+-- name: ListFeeSchedules :many
+SELECT * FROM fee_schedules
+ORDER BY account_type;
+
+-- name: UpsertFeeIncomeAccount :one
+INSERT INTO fee_income_accounts (
+    currency_code,
+    account_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (currency_code) DO UPDATE
+SET account_id = EXCLUDED.account_id
+RETURNING *;
+
+-- name: GetFeeIncomeAccount :one
+SELECT * FROM fee_income_accounts
+WHERE currency_code = $1;
+
+-- name: ListFeeIncomeAccounts :many
+SELECT * FROM fee_income_accounts
+ORDER BY currency_code;
+
+-- name: CreateFeeCharge :one
+INSERT INTO fee_charges (
+    account_id,
+    fee_type,
+    amount,
+    currency_code,
+    transaction_id,
+    related_transaction_id,
+    period_start
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListFeeChargesByAccount :many
+SELECT * FROM fee_charges
+WHERE account_id = $1
+ORDER BY created_at DESC, charge_id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListFeeChargesByRelatedTransaction :many
+SELECT * FROM fee_charges
+WHERE related_transaction_id = $1
+ORDER BY charge_id;
+
+-- name: ListAccountsDueMaintenanceFee :many
+-- Accounts that owe the maintenance fee of the month starting on period_start
+SELECT a.account_id, a.currency_code, s.monthly_maintenance_fee AS amount
+FROM accounts a
+JOIN fee_schedules s ON s.account_type = a.account_type
+WHERE s.is_active
+  AND a.is_active
+  AND s.monthly_maintenance_fee > 0
+  AND a.created_at < (sqlc.arg(period_start)::DATE + INTERVAL '1 month') AT TIME ZONE 'UTC'
+  AND NOT EXISTS (SELECT 1 FROM fee_income_accounts f WHERE f.account_id = a.account_id)
+  AND NOT EXISTS (
+      SELECT 1 FROM fee_charges c
+      WHERE c.account_id = a.account_id
+        AND c.fee_type = 'MAINTENANCE'
+        AND c.period_start = sqlc.arg(period_start)::DATE
+  )
+ORDER BY a.account_id;
+
+-- name: ListAccountsDueOverdraftFee :many
+-- Accounts whose ledger balance was negative at the end of the day (UTC) starting on period_start
+-- and that have not paid that day's overdraft fee
+SELECT a.account_id, a.currency_code, s.overdraft_fee AS amount
+FROM accounts a
+JOIN fee_schedules s ON s.account_type = a.account_type
+WHERE s.is_active
+  AND a.is_active
+  AND s.overdraft_fee > 0
+  AND (
+      SELECT COALESCE(SUM(e.amount), 0) FROM entries e
+      WHERE e.account_id = a.account_id
+        AND e.created_at < (sqlc.arg(period_start)::DATE + 1)::TIMESTAMP AT TIME ZONE 'UTC'
+  ) < 0
+  AND NOT EXISTS (SELECT 1 FROM fee_income_accounts f WHERE f.account_id = a.account_id)
+  AND NOT EXISTS (
+      SELECT 1 FROM fee_charges c
+      WHERE c.account_id = a.account_id
+        AND c.fee_type = 'OVERDRAFT'
+        AND c.period_start = sqlc.arg(period_start)::DATE
+  )
+ORDER BY a.account_id;
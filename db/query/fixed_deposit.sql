@@ -0,0 +1,91 @@
+-- name: CreateFixedDeposit :one
+INSERT INTO fixed_deposits (
+    account_id,
+    linked_account_id,
+    principal,
+    interest_rate,
+    term_months,
+    start_date,
+    maturity_date,
+    maturity_instruction,
+    early_break_penalty_percent
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: GetFixedDeposit :one
+SELECT * FROM fixed_deposits
+WHERE deposit_number = $1 LIMIT 1;
+
+-- name: GetFixedDepositForUpdate :one
+SELECT * FROM fixed_deposits
+WHERE deposit_number = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListFixedDepositsByLinkedAccount :many
+SELECT * FROM fixed_deposits
+WHERE linked_account_id = $1
+ORDER BY deposit_id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: IsAccountLockedByFixedDeposit :one
+-- Whether the account holds a fixed deposit that has not matured or been broken yet
+SELECT EXISTS (
+    SELECT 1 FROM fixed_deposits
+    WHERE account_id = $1 AND status = 'ACTIVE'
+);
+
+-- name: ListMaturedFixedDeposits :many
+-- Active deposits whose maturity date is on or before the given day, oldest maturity first
+SELECT deposit_number FROM fixed_deposits
+WHERE status = 'ACTIVE' AND maturity_date <= $1
+ORDER BY maturity_date, deposit_id
+LIMIT $2;
+
+-- name: CloseFixedDeposit :one
+-- Ends a deposit, which unlocks its account so the balance can be paid out
+UPDATE fixed_deposits
+SET status = $2,
+    closed_at = CURRENT_TIMESTAMP
+WHERE deposit_id = $1 AND status = 'ACTIVE'
+RETURNING *;
+
+-- name: SetFixedDepositPayout :one
+UPDATE fixed_deposits
+SET payout_transaction_id = $2
+WHERE deposit_id = $1
+RETURNING *;
+
+-- name: RollOverFixedDeposit :one
+-- Starts the next term on the maturity date with the principal grown by the interest of the last one
+UPDATE fixed_deposits
+SET principal = $2,
+    start_date = $3,
+    maturity_date = $4,
+    rollover_count = rollover_count + 1
+WHERE deposit_id = $1 AND status = 'ACTIVE'
+RETURNING *;
+
+-- name: UpsertFixedDepositTerm :one
+INSERT INTO fixed_deposit_terms (
+    term_months,
+    interest_rate,
+    early_break_penalty_percent,
+    is_active
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (term_months) DO UPDATE
+SET interest_rate = EXCLUDED.interest_rate,
+    early_break_penalty_percent = EXCLUDED.early_break_penalty_percent,
+    is_active = EXCLUDED.is_active
+RETURNING *;
+
+-- name: GetFixedDepositTerm :one
+SELECT * FROM fixed_deposit_terms
+WHERE term_months = $1;
+
+-- name: ListFixedDepositTerms :many
+SELECT * FROM fixed_deposit_terms
+ORDER BY term_months;
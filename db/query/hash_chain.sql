@@ -0,0 +1,48 @@
+-- name: GetHashChainHead :one
+SELECT * FROM hash_chain_heads
+WHERE chain_name = $1;
+
+-- name: ListAuditTrailChain :many
+-- ListAuditTrailChain returns the links of the audit_trail chain after a position, in chain order
+SELECT * FROM audit_trail
+WHERE chain_seq > sqlc.arg(after_seq)
+ORDER BY chain_seq
+LIMIT sqlc.arg('limit');
+
+-- name: ListEntriesChain :many
+-- ListEntriesChain returns the links of the entries chain after a position, in chain order
+SELECT * FROM entries
+WHERE chain_seq > sqlc.arg(after_seq)
+ORDER BY chain_seq
+LIMIT sqlc.arg('limit');
+
+-- name: CountUnchainedAuditTrail :one
+-- CountUnchainedAuditTrail counts committed audit records that were never linked into the chain
+SELECT COUNT(*) FROM audit_trail
+WHERE chain_seq IS NULL;
+
+-- name: CountUnchainedEntries :one
+-- CountUnchainedEntries counts committed entries that were never linked into the chain
+SELECT COUNT(*) FROM entries
+WHERE chain_seq IS NULL;
+
+-- name: CreateHashChainCheckpoint :one
+INSERT INTO hash_chain_checkpoints (
+    chain_name,
+    chain_seq,
+    row_hash,
+    signature
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetLatestHashChainCheckpoint :one
+SELECT * FROM hash_chain_checkpoints
+WHERE chain_name = $1
+ORDER BY chain_seq DESC
+LIMIT 1;
+
+-- name: ListHashChainCheckpoints :many
+SELECT * FROM hash_chain_checkpoints
+WHERE chain_name = $1
+ORDER BY chain_seq;
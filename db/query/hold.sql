@@ -0,0 +1,41 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+    account_id,
+    merchant_account_id,
+    transaction_id,
+    amount,
+    currency_code,
+    description,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE hold_number = $1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE hold_number = $1
+FOR UPDATE;
+
+-- name: ListOpenHoldsByAccount :many
+SELECT * FROM holds
+WHERE account_id = $1 AND status = 'OPEN'
+ORDER BY created_at;
+
+-- name: ListExpiredHoldsForUpdate :many
+SELECT * FROM holds
+WHERE status = 'OPEN' AND expires_at < CURRENT_TIMESTAMP
+ORDER BY expires_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: CloseHold :one
+UPDATE holds
+SET status = sqlc.arg('status'),
+    captured_amount = sqlc.arg('captured_amount'),
+    closed_at = CURRENT_TIMESTAMP
+WHERE hold_id = sqlc.arg('hold_id')
+RETURNING *;
@@ -0,0 +1,41 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    client_id,
+    idempotency_key,
+    request_method,
+    request_path,
+    request_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (client_id, idempotency_key) DO NOTHING
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE client_id = $1 AND idempotency_key = $2;
+
+-- name: CompleteIdempotencyKey :one
+UPDATE idempotency_keys
+SET response_status = sqlc.arg('response_status'),
+    response_body = sqlc.arg('response_body'),
+    completed_at = CURRENT_TIMESTAMP
+WHERE client_id = sqlc.arg('client_id') AND idempotency_key = sqlc.arg('idempotency_key')
+RETURNING *;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE client_id = $1 AND idempotency_key = $2;
+
+-- name: DeleteStaleIdempotencyKey :execrows
+-- A key reserved before created_before and never completed was left by a request that crashed
+DELETE FROM idempotency_keys
+WHERE client_id = sqlc.arg('client_id')
+  AND idempotency_key = sqlc.arg('idempotency_key')
+  AND completed_at IS NULL
+  AND created_at < sqlc.arg('created_before');
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at < CURRENT_TIMESTAMP;
@@ -0,0 +1,110 @@
+-- name: UpsertInterestSettings :one
+INSERT INTO interest_settings (
+    account_type,
+    day_count_convention,
+    posting_period,
+    is_active
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (account_type) DO UPDATE
+SET day_count_convention = EXCLUDED.day_count_convention,
+    posting_period = EXCLUDED.posting_period,
+    is_active = EXCLUDED.is_active
+RETURNING *;
+
+-- name: GetInterestSettings :one
+SELECT * FROM interest_settings
+WHERE account_type = $1;
+
+-- name: ListInterestSettings :many
+SELECT * FROM interest_settings
+ORDER BY account_type;
+
+-- name: ListInterestBearingAccounts :many
+-- Accounts that earn interest on the given day and have not accrued it yet, with their end of day
+-- (UTC) ledger balance. Fixed deposits are left out, they earn their interest at maturity.
+SELECT a.account_id,
+       a.interest_rate::DECIMAL(5, 2) AS interest_rate,
+       s.day_count_convention,
+       COALESCE((
+           SELECT SUM(e.amount) FROM entries e
+           WHERE e.account_id = a.account_id
+             AND e.created_at < (sqlc.arg(accrual_date)::DATE + 1)::TIMESTAMP AT TIME ZONE 'UTC'
+       ), 0)::DECIMAL(16, 3) AS balance
+FROM accounts a
+JOIN interest_settings s ON s.account_type = a.account_type
+WHERE s.is_active
+  AND a.is_active
+  AND a.interest_rate > 0
+  AND a.created_at < (sqlc.arg(accrual_date)::DATE + 1)::TIMESTAMP AT TIME ZONE 'UTC'
+  AND NOT EXISTS (SELECT 1 FROM fixed_deposits fd WHERE fd.account_id = a.account_id)
+  AND NOT EXISTS (
+      SELECT 1 FROM interest_accruals ia
+      WHERE ia.account_id = a.account_id AND ia.accrual_date = sqlc.arg(accrual_date)::DATE
+  )
+ORDER BY a.account_id;
+
+-- name: CreateInterestAccrual :execrows
+INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    balance,
+    interest_rate,
+    day_count_convention,
+    amount
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (account_id, accrual_date) DO NOTHING;
+
+-- name: GetLatestInterestAccrualDate :one
+SELECT accrual_date FROM interest_accruals
+ORDER BY accrual_date DESC
+LIMIT 1;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListUnpostedInterestAccounts :many
+-- Accounts with accruals left to post up to the end of a posting period
+SELECT DISTINCT ia.account_id
+FROM interest_accruals ia
+JOIN accounts a ON a.account_id = ia.account_id
+JOIN interest_settings s ON s.account_type = a.account_type
+WHERE ia.transaction_id IS NULL
+  AND s.posting_period = $1
+  AND ia.accrual_date <= $2
+ORDER BY ia.account_id;
+
+-- name: ListUnpostedInterestAccrualsForUpdate :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+  AND accrual_date <= $2
+  AND transaction_id IS NULL
+ORDER BY accrual_date
+FOR UPDATE;
+
+-- name: MarkInterestAccrualsPosted :execrows
+UPDATE interest_accruals
+SET transaction_id = $1,
+    posted_at = CURRENT_TIMESTAMP
+WHERE account_id = $2
+  AND accrual_date <= $3
+  AND transaction_id IS NULL;
+
+-- name: GetInterestResidue :one
+-- What the latest posting of an account left out when it was rounded to the minor unit
+SELECT residue FROM interest_accruals
+WHERE account_id = $1
+  AND transaction_id IS NOT NULL
+ORDER BY accrual_date DESC
+LIMIT 1;
+
+-- name: SetInterestAccrualResidue :exec
+UPDATE interest_accruals
+SET residue = $2
+WHERE accrual_id = $1;
@@ -0,0 +1,126 @@
+-- name: CreateReconciliationRun :one
+INSERT INTO reconciliation_runs (
+    account_ids
+) VALUES (
+    $1
+) RETURNING *;
+
+-- name: CompleteReconciliationRun :one
+UPDATE reconciliation_runs
+SET status = $1,
+    accounts_checked = $2,
+    transactions_checked = $3,
+    discrepancy_count = $4,
+    error_message = $5,
+    completed_at = CURRENT_TIMESTAMP
+WHERE run_id = $6
+RETURNING *;
+
+-- name: GetReconciliationRun :one
+SELECT * FROM reconciliation_runs
+WHERE run_number = $1;
+
+-- name: ListReconciliationRuns :many
+SELECT * FROM reconciliation_runs
+ORDER BY started_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CreateLedgerDiscrepancy :one
+INSERT INTO ledger_discrepancies (
+    run_id,
+    discrepancy_type,
+    account_id,
+    transaction_id,
+    currency_code,
+    expected_amount,
+    actual_amount,
+    difference,
+    details
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: ListLedgerDiscrepancies :many
+SELECT * FROM ledger_discrepancies
+WHERE run_id = $1
+ORDER BY discrepancy_id
+LIMIT $2 OFFSET $3;
+
+-- name: CountReconciliationAccounts :one
+SELECT COUNT(*) FROM accounts
+WHERE sqlc.narg(account_ids)::INTEGER[] IS NULL
+   OR account_id = ANY(sqlc.narg(account_ids)::INTEGER[]);
+
+-- name: CountReconciliationTransactions :one
+SELECT COUNT(*) FROM transactions
+WHERE status_code IN ('COMPLETED', 'REVERSED')
+  AND (sqlc.narg(account_ids)::INTEGER[] IS NULL
+   OR from_account_id = ANY(sqlc.narg(account_ids)::INTEGER[])
+   OR to_account_id = ANY(sqlc.narg(account_ids)::INTEGER[]));
+
+-- name: ListAccountBalanceDrift :many
+-- Accounts whose stored balance differs from the sum of their entries
+SELECT a.account_id,
+       a.currency_code,
+       a.balance,
+       COALESCE(e.total, 0)::DECIMAL(32, 3) AS entries_balance
+FROM accounts a
+LEFT JOIN (
+    SELECT account_id, SUM(amount) AS total
+    FROM entries
+    GROUP BY account_id
+) e ON e.account_id = a.account_id
+WHERE (sqlc.narg(account_ids)::INTEGER[] IS NULL OR a.account_id = ANY(sqlc.narg(account_ids)::INTEGER[]))
+  AND a.balance <> COALESCE(e.total, 0)
+ORDER BY a.account_id;
+
+-- name: ListUnbalancedTransactions :many
+-- Transactions between two accounts whose entries do not net to zero. The debit leg is converted at
+-- the entry's rate so cross-currency transfers compare in the credited currency, allowing for the
+-- rounding of the credit to half a minor unit of that currency. Deposits, interest and other flows
+-- with one side outside the ledger have a single entry and are only checked for missing entries.
+SELECT t.transaction_id,
+       COALESCE(t.converted_currency_code, t.currency_code)::VARCHAR(3) AS currency_code,
+       COUNT(e.id) AS entry_count,
+       SUM(CASE WHEN e.amount < 0 THEN e.amount * COALESCE(e.exchange_rate, 1) ELSE e.amount END)::DECIMAL(32, 4) AS net_amount
+FROM transactions t
+JOIN entries e ON e.transaction_id = t.transaction_id
+JOIN account_currencies c ON c.currency_code = COALESCE(t.converted_currency_code, t.currency_code)
+WHERE t.from_account_id IS NOT NULL
+  AND t.to_account_id IS NOT NULL
+  AND (sqlc.narg(account_ids)::INTEGER[] IS NULL
+   OR t.from_account_id = ANY(sqlc.narg(account_ids)::INTEGER[])
+   OR t.to_account_id = ANY(sqlc.narg(account_ids)::INTEGER[]))
+GROUP BY t.transaction_id, c.minor_units
+HAVING ABS(SUM(CASE WHEN e.amount < 0 THEN e.amount * COALESCE(e.exchange_rate, 1) ELSE e.amount END))
+     > power(10::NUMERIC, -c.minor_units) / 2
+ORDER BY t.transaction_id;
+
+-- name: ListTransactionsMissingEntries :many
+-- Booked transactions lacking the debit entry on the sender or the credit entry on the receiver
+SELECT t.transaction_id,
+       t.from_account_id,
+       t.to_account_id,
+       t.amount,
+       t.currency_code,
+       (SELECT COUNT(*) FROM entries e WHERE e.transaction_id = t.transaction_id) AS entry_count
+FROM transactions t
+WHERE t.status_code IN ('COMPLETED', 'REVERSED')
+  AND (sqlc.narg(account_ids)::INTEGER[] IS NULL
+   OR t.from_account_id = ANY(sqlc.narg(account_ids)::INTEGER[])
+   OR t.to_account_id = ANY(sqlc.narg(account_ids)::INTEGER[]))
+  AND (
+    (t.from_account_id IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM entries e
+        WHERE e.transaction_id = t.transaction_id
+          AND e.account_id = t.from_account_id
+          AND e.amount = -t.amount
+    ))
+    OR (t.to_account_id IS NOT NULL AND NOT EXISTS (
+        SELECT 1 FROM entries e
+        WHERE e.transaction_id = t.transaction_id
+          AND e.account_id = t.to_account_id
+          AND e.amount = COALESCE(t.converted_amount, t.amount)
+    ))
+  )
+ORDER BY t.transaction_id;
@@ -0,0 +1,67 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+    from_account_id,
+    to_account_id,
+    amount,
+    currency_code,
+    description,
+    frequency,
+    cron_expression,
+    start_at,
+    end_at,
+    next_run_at,
+    due_at,
+    max_retries,
+    user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE schedule_number = $1;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE schedule_number = $1
+FOR UPDATE;
+
+-- name: ListScheduledTransfersByAccount :many
+SELECT * FROM scheduled_transfers
+WHERE from_account_id = $1
+ORDER BY created_at;
+
+-- name: ListDueScheduledTransfersForUpdate :many
+SELECT * FROM scheduled_transfers
+WHERE status = 'ACTIVE' AND due_at <= CURRENT_TIMESTAMP
+ORDER BY due_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateScheduledTransferRun :one
+UPDATE scheduled_transfers
+SET status = sqlc.arg('status'),
+    next_run_at = sqlc.arg('next_run_at'),
+    due_at = sqlc.arg('due_at'),
+    retry_count = sqlc.arg('retry_count'),
+    last_run_at = sqlc.arg('last_run_at')
+WHERE scheduled_transfer_id = sqlc.arg('scheduled_transfer_id')
+RETURNING *;
+
+-- name: CreateScheduledTransferExecution :one
+INSERT INTO scheduled_transfer_executions (
+    scheduled_transfer_id,
+    scheduled_for,
+    attempt,
+    status,
+    transaction_id,
+    error_message
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListScheduledTransferExecutions :many
+SELECT * FROM scheduled_transfer_executions
+WHERE scheduled_transfer_id = $1
+ORDER BY executed_at DESC
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,84 @@
+-- name: CreateStatement :one
+INSERT INTO statements (
+    account_id,
+    format,
+    period_start,
+    period_end
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetStatement :one
+SELECT * FROM statements
+WHERE statement_number = $1 LIMIT 1;
+
+-- name: ListStatementsByAccount :many
+SELECT * FROM statements
+WHERE account_id = $1
+ORDER BY statement_id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimPendingStatements :many
+-- Marks the oldest pending statements as PROCESSING and returns them. Statements left PROCESSING
+-- since before stale_before belonged to a worker that stopped, they are claimed again.
+UPDATE statements
+SET status = 'PROCESSING',
+    attempts = attempts + 1
+WHERE statement_id IN (
+    SELECT s.statement_id FROM statements s
+    WHERE s.status = 'PENDING'
+       OR (s.status = 'PROCESSING' AND s.updated_at < sqlc.arg(stale_before))
+    ORDER BY s.statement_id
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteStatement :one
+UPDATE statements
+SET status = 'COMPLETED',
+    entry_count = $2,
+    file_url = $3,
+    failure_reason = NULL,
+    completed_at = CURRENT_TIMESTAMP
+WHERE statement_id = $1
+RETURNING *;
+
+-- name: FailStatement :one
+UPDATE statements
+SET status = 'FAILED',
+    failure_reason = $2,
+    completed_at = CURRENT_TIMESTAMP
+WHERE statement_id = $1
+RETURNING *;
+
+-- name: GetAccountBalanceBefore :one
+-- The balance of the account's entries booked before the given time, the opening balance of a statement
+SELECT COALESCE(SUM(amount), 0)::DECIMAL(32, 3) AS balance
+FROM entries
+WHERE account_id = $1 AND created_at < $2;
+
+-- name: CountStatementEntries :one
+SELECT COUNT(*) FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time);
+
+-- name: ListStatementEntries :many
+-- The entries booked on an account in [from_time, to_time) with the transaction behind each, in
+-- booking order. Entries written before transactions were linked have no transaction details.
+SELECT e.id AS entry_id,
+       e.amount,
+       e.created_at,
+       t.transaction_number,
+       t.type_code,
+       t.description,
+       t.reference_number,
+       CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END AS counterparty_account_id
+FROM entries e
+LEFT JOIN transactions t ON t.transaction_id = e.transaction_id
+WHERE e.account_id = sqlc.arg(account_id)
+  AND e.created_at >= sqlc.arg(from_time)
+  AND e.created_at < sqlc.arg(to_time)
+ORDER BY e.created_at, e.id;
@@ -9,15 +9,35 @@ INSERT INTO transactions (
     status_code,
     description,
     reference_number,
-    transaction_date
+    transaction_date,
+    converted_amount,
+    converted_currency_code,
+    original_transaction_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 ) RETURNING *;
 
 -- name: GetTransaction :one
 SELECT * FROM transactions
 WHERE transaction_id = $1;
 
+-- name: GetTransactionByNumber :one
+SELECT * FROM transactions
+WHERE transaction_number = $1;
+
+-- name: GetTransactionByNumberForUpdate :one
+SELECT * FROM transactions
+WHERE transaction_number = $1
+FOR UPDATE;
+
+-- name: GetCompensatedTotals :one
+SELECT
+    COALESCE(SUM(amount), 0)::DECIMAL(16, 3) AS debited,
+    COALESCE(SUM(COALESCE(converted_amount, amount)), 0)::DECIMAL(16, 3) AS credited
+FROM transactions
+WHERE original_transaction_id = $1
+    AND status_code = 'COMPLETED';
+
 -- name: ListTransactionsByAccount :many
 SELECT * FROM transactions
 WHERE from_account_id = $1 OR to_account_id = $1
@@ -30,6 +50,16 @@ SET status_code = $2
 WHERE transaction_id = $1
 RETURNING *;
 
+-- name: SettleTransaction :one
+UPDATE transactions
+SET amount = sqlc.arg('amount'),
+    exchange_rate = sqlc.arg('exchange_rate'),
+    converted_amount = sqlc.arg('converted_amount'),
+    converted_currency_code = sqlc.arg('converted_currency_code'),
+    status_code = sqlc.arg('status_code')
+WHERE transaction_id = sqlc.arg('transaction_id')
+RETURNING *;
+
 -- name: GetTransactionsByDateRange :many
 SELECT * FROM transactions
 WHERE transaction_date BETWEEN sqlc.arg('start_date') AND sqlc.arg('end_date')
@@ -67,6 +97,16 @@ WHERE from_account_id = $1 OR to_account_id = $1
 ORDER BY transaction_date DESC
 LIMIT $2 OFFSET $3;
 
+-- name: HasTransferredTo :one
+-- Whether any account of the user has completed a transaction to the account before
+SELECT EXISTS (
+    SELECT 1 FROM transactions t
+    JOIN accounts a ON a.account_id = t.from_account_id
+    WHERE a.user_id = sqlc.arg(user_id)
+        AND t.to_account_id = sqlc.arg(to_account_id)::INTEGER
+        AND t.status_code = 'COMPLETED'
+);
+
 -- name: DeleteTransaction :exec
 DELETE FROM transactions 
 WHERE transaction_number = $1;
@@ -0,0 +1,96 @@
+-- name: CreateTransferBatch :one
+INSERT INTO transfer_batches (
+    mode,
+    status,
+    reference_number,
+    upload_id,
+    total_items,
+    user_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: CreateTransferBatchItem :one
+INSERT INTO transfer_batch_items (
+    batch_id,
+    item_index,
+    from_account_id,
+    to_account_id,
+    amount,
+    currency_code,
+    description
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetTransferBatch :one
+SELECT * FROM transfer_batches
+WHERE batch_number = $1;
+
+-- name: GetTransferBatchByUploadForUpdate :one
+SELECT * FROM transfer_batches
+WHERE upload_id = $1
+FOR UPDATE;
+
+-- name: ClaimTransferBatch :one
+-- Takes the oldest pending batch, or a processing one whose worker stopped reporting progress
+UPDATE transfer_batches
+SET status = 'PROCESSING',
+    started_at = COALESCE(started_at, CURRENT_TIMESTAMP)
+WHERE batch_id = (
+    SELECT batch_id FROM transfer_batches
+    WHERE status = 'PENDING'
+       OR (status = 'PROCESSING' AND updated_at < sqlc.arg('stale_before'))
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkTransferBatchImported :one
+UPDATE transfer_batches
+SET status = 'PENDING',
+    total_items = sqlc.arg('total_items')
+WHERE batch_id = sqlc.arg('batch_id')
+RETURNING *;
+
+-- name: RefreshTransferBatchProgress :one
+UPDATE transfer_batches b
+SET processed_items = counts.processed,
+    succeeded_items = counts.succeeded,
+    failed_items = counts.failed
+FROM (
+    SELECT COUNT(*) FILTER (WHERE status != 'PENDING')::INTEGER AS processed,
+           COUNT(*) FILTER (WHERE status = 'SUCCEEDED')::INTEGER AS succeeded,
+           COUNT(*) FILTER (WHERE status = 'FAILED')::INTEGER AS failed
+    FROM transfer_batch_items
+    WHERE batch_id = sqlc.arg('batch_id')
+) counts
+WHERE b.batch_id = sqlc.arg('batch_id')
+RETURNING b.*;
+
+-- name: CompleteTransferBatch :one
+UPDATE transfer_batches
+SET status = sqlc.arg('status'),
+    error_message = sqlc.arg('error_message'),
+    completed_at = CURRENT_TIMESTAMP
+WHERE batch_id = sqlc.arg('batch_id')
+RETURNING *;
+
+-- name: ListTransferBatchItems :many
+SELECT * FROM transfer_batch_items
+WHERE batch_id = $1
+ORDER BY item_index;
+
+-- name: ListPendingTransferBatchItems :many
+SELECT * FROM transfer_batch_items
+WHERE batch_id = $1 AND status = 'PENDING'
+ORDER BY item_index;
+
+-- name: UpdateTransferBatchItemResult :exec
+UPDATE transfer_batch_items
+SET status = sqlc.arg('status'),
+    transaction_id = sqlc.arg('transaction_id'),
+    error_message = sqlc.arg('error_message'),
+    processed_at = CURRENT_TIMESTAMP
+WHERE item_id = sqlc.arg('item_id');
@@ -0,0 +1,72 @@
+-- name: StartTwoFactorEnrollment :one
+-- StartTwoFactorEnrollment stores a new secret for a user who has not enabled two-factor
+-- authentication, replacing the secret of an enrollment that was never confirmed
+INSERT INTO user_two_factor (
+    user_id,
+    secret
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    recovery_code_hashes = '{}',
+    last_used_step = 0,
+    failed_attempts = 0,
+    last_failed_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_two_factor.enabled_at IS NULL
+RETURNING *;
+
+-- name: GetTwoFactor :one
+SELECT * FROM user_two_factor
+WHERE user_id = $1;
+
+-- name: EnableTwoFactor :one
+UPDATE user_two_factor
+SET enabled_at = CURRENT_TIMESTAMP,
+    recovery_code_hashes = sqlc.arg(recovery_code_hashes),
+    last_used_step = sqlc.arg(last_used_step),
+    failed_attempts = 0,
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id)
+  AND enabled_at IS NULL
+RETURNING *;
+
+-- name: UseTwoFactorStep :one
+-- UseTwoFactorStep accepts the code of a time step later than the last accepted one, two requests
+-- racing with the same code cannot both succeed
+UPDATE user_two_factor
+SET last_used_step = sqlc.arg(step),
+    failed_attempts = 0,
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id)
+  AND enabled_at IS NOT NULL
+  AND last_used_step < sqlc.arg(step)
+RETURNING *;
+
+-- name: UseRecoveryCode :one
+-- UseRecoveryCode removes a recovery code as it is used, it does not work a second time
+UPDATE user_two_factor
+SET recovery_code_hashes = array_remove(recovery_code_hashes, sqlc.arg(code_hash)::VARCHAR),
+    failed_attempts = 0,
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id)
+  AND enabled_at IS NOT NULL
+  AND sqlc.arg(code_hash)::VARCHAR = ANY(recovery_code_hashes)
+RETURNING *;
+
+-- name: RecordTwoFactorFailure :one
+-- RecordTwoFactorFailure counts a wrong code. Failures from before window_start are forgotten, the
+-- count starts again at one.
+UPDATE user_two_factor
+SET failed_attempts = CASE
+        WHEN last_failed_at IS NULL OR last_failed_at < sqlc.arg(window_start) THEN 1
+        ELSE failed_attempts + 1
+    END,
+    last_failed_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: DeleteTwoFactor :exec
+DELETE FROM user_two_factor
+WHERE user_id = $1;
@@ -40,4 +40,24 @@ WHERE user_id = $1;
 
 -- name: HardDeleteUser :exec
 DELETE FROM users
-WHERE user_id = $1;
\ No newline at end of file
+WHERE user_id = $1;
+
+-- name: UpdateLastLogin :exec
+UPDATE users
+SET last_login = sqlc.arg('last_login')
+WHERE user_id = sqlc.arg('user_id');
+
+-- name: GetUserByUsername :one
+SELECT * FROM users
+WHERE username = $1;
+
+-- name: GetUserAccess :one
+-- GetUserAccess returns what access control needs to know about a user, it is read on every request
+SELECT role, is_active FROM users
+WHERE user_id = $1;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = sqlc.arg('role')
+WHERE user_id = sqlc.arg('user_id')
+RETURNING *;
@@ -11,6 +11,37 @@ import (
 	"github.com/jackc/pgtype"
 )
 
+const closeAccount = `-- name: CloseAccount :execrows
+UPDATE accounts
+SET is_active = false
+WHERE account_id = $1
+  AND balance = 0
+  AND held_amount = 0
+  AND NOT EXISTS (
+      SELECT 1 FROM holds h
+      WHERE h.merchant_account_id = accounts.account_id AND h.status = 'OPEN'
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM scheduled_transfers s
+      WHERE accounts.account_id IN (s.from_account_id, s.to_account_id)
+        AND s.status IN ('ACTIVE', 'PAUSED')
+  )
+  AND NOT EXISTS (
+      SELECT 1 FROM fixed_deposits fd
+      WHERE accounts.account_id IN (fd.account_id, fd.linked_account_id) AND fd.status = 'ACTIVE'
+  )
+`
+
+// Deactivates an account that holds no money and that no hold, schedule or deposit still refers
+// to, it updates no row otherwise
+func (q *Queries) CloseAccount(ctx context.Context, accountID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, closeAccount, accountID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
     user_id,
@@ -21,7 +52,7 @@ INSERT INTO accounts (
     overdraft_limit
 ) VALUES (
     $1, $2, $3, $4, $5, $6
-) RETURNING account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at
+) RETURNING account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at, held_amount
 `
 
 type CreateAccountParams struct {
@@ -55,6 +86,7 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldAmount,
 	)
 	return i, err
 }
@@ -71,7 +103,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, accountID int32) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at FROM accounts
+SELECT account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at, held_amount FROM accounts
 WHERE account_id = $1
 `
 
@@ -90,12 +122,38 @@ func (q *Queries) GetAccount(ctx context.Context, accountID int32) (Account, err
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+SELECT account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at, held_amount FROM accounts
+WHERE account_number = $1
+`
+
+func (q *Queries) GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountByNumber, accountNumber)
+	var i Account
+	err := row.Scan(
+		&i.AccountID,
+		&i.UserID,
+		&i.AccountNumber,
+		&i.AccountType,
+		&i.Balance,
+		&i.CurrencyCode,
+		&i.InterestRate,
+		&i.OverdraftLimit,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HeldAmount,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at FROM accounts
+SELECT account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at, held_amount FROM accounts
 WHERE account_id = $1
 FOR NO KEY UPDATE
 `
@@ -115,6 +173,7 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, accountID int32) (Acc
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldAmount,
 	)
 	return i, err
 }
@@ -130,7 +189,7 @@ func (q *Queries) HardDeleteAccount(ctx context.Context, accountID int32) error
 }
 
 const listAccountsByUser = `-- name: ListAccountsByUser :many
-SELECT account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at FROM accounts
+SELECT account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at, held_amount FROM accounts
 WHERE user_id = $1
 ORDER BY account_id
 `
@@ -156,6 +215,7 @@ func (q *Queries) ListAccountsByUser(ctx context.Context, userID int32) ([]Accou
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.HeldAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -171,7 +231,7 @@ const updateAccountBalance = `-- name: UpdateAccountBalance :one
 UPDATE accounts 
 SET balance = balance + $1
 WHERE account_id = $2
-RETURNING account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at
+RETURNING account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at, held_amount
 `
 
 type UpdateAccountBalanceParams struct {
@@ -194,6 +254,81 @@ func (q *Queries) UpdateAccountBalance(ctx context.Context, arg UpdateAccountBal
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
+const updateAccountHeldAmount = `-- name: UpdateAccountHeldAmount :one
+UPDATE accounts
+SET held_amount = held_amount + $1
+WHERE account_id = $2
+RETURNING account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at, held_amount
+`
+
+type UpdateAccountHeldAmountParams struct {
+	Amount    pgtype.Numeric `json:"amount"`
+	AccountID int32          `json:"account_id"`
+}
+
+func (q *Queries) UpdateAccountHeldAmount(ctx context.Context, arg UpdateAccountHeldAmountParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountHeldAmount,
+		arg.Amount,
+		arg.AccountID,
+	)
+	var i Account
+	err := row.Scan(
+		&i.AccountID,
+		&i.UserID,
+		&i.AccountNumber,
+		&i.AccountType,
+		&i.Balance,
+		&i.CurrencyCode,
+		&i.InterestRate,
+		&i.OverdraftLimit,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HeldAmount,
+	)
+	return i, err
+}
+
+const updateAccountTerms = `-- name: UpdateAccountTerms :one
+UPDATE accounts
+SET interest_rate = COALESCE($1, interest_rate),
+    overdraft_limit = COALESCE($2, overdraft_limit)
+WHERE account_id = $3
+RETURNING account_id, user_id, account_number, account_type, balance, currency_code, interest_rate, overdraft_limit, is_active, created_at, updated_at, held_amount
+`
+
+type UpdateAccountTermsParams struct {
+	InterestRate   pgtype.Numeric `json:"interest_rate"`
+	OverdraftLimit pgtype.Numeric `json:"overdraft_limit"`
+	AccountID      int32          `json:"account_id"`
+}
+
+// Sets the interest rate and overdraft limit of an account, a NULL keeps the current value
+func (q *Queries) UpdateAccountTerms(ctx context.Context, arg UpdateAccountTermsParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountTerms,
+		arg.InterestRate,
+		arg.OverdraftLimit,
+		arg.AccountID,
+	)
+	var i Account
+	err := row.Scan(
+		&i.AccountID,
+		&i.UserID,
+		&i.AccountNumber,
+		&i.AccountType,
+		&i.Balance,
+		&i.CurrencyCode,
+		&i.InterestRate,
+		&i.OverdraftLimit,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HeldAmount,
 	)
 	return i, err
 }
@@ -12,20 +12,36 @@ import (
 	"github.com/jackc/pgtype"
 )
 
+const countAccountsByCurrency = `-- name: CountAccountsByCurrency :one
+SELECT COUNT(*) FROM accounts
+WHERE currency_code = $1
+`
+
+func (q *Queries) CountAccountsByCurrency(ctx context.Context, currencyCode string) (int64, error) {
+	row := q.db.QueryRow(ctx, countAccountsByCurrency, currencyCode)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createCurrency = `-- name: CreateCurrency :one
 INSERT INTO account_currencies (
     currency_code,
     currency_name,
     symbol,
     exchange_rate,
+    minor_units,
+    is_active,
     last_updated_at
 ) VALUES (
     $1,
     $2,
     $3,
     $4,
+    COALESCE($5, 2),
+    COALESCE($6, true),
     CURRENT_TIMESTAMP
-) RETURNING currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at
+) RETURNING currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at, minor_units
 `
 
 type CreateCurrencyParams struct {
@@ -33,6 +49,8 @@ type CreateCurrencyParams struct {
 	CurrencyName string         `json:"currency_name"`
 	Symbol       sql.NullString `json:"symbol"`
 	ExchangeRate pgtype.Numeric `json:"exchange_rate"`
+	MinorUnits   sql.NullInt16  `json:"minor_units"`
+	IsActive     sql.NullBool   `json:"is_active"`
 }
 
 func (q *Queries) CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (AccountCurrency, error) {
@@ -41,6 +59,8 @@ func (q *Queries) CreateCurrency(ctx context.Context, arg CreateCurrencyParams)
 		arg.CurrencyName,
 		arg.Symbol,
 		arg.ExchangeRate,
+		arg.MinorUnits,
+		arg.IsActive,
 	)
 	var i AccountCurrency
 	err := row.Scan(
@@ -52,6 +72,7 @@ func (q *Queries) CreateCurrency(ctx context.Context, arg CreateCurrencyParams)
 		&i.LastUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MinorUnits,
 	)
 	return i, err
 }
@@ -68,7 +89,7 @@ func (q *Queries) DeleteCurrency(ctx context.Context, currencyCode string) error
 }
 
 const getCurrency = `-- name: GetCurrency :one
-SELECT currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at FROM account_currencies
+SELECT currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at, minor_units FROM account_currencies
 WHERE currency_code = $1
 `
 
@@ -84,6 +105,7 @@ func (q *Queries) GetCurrency(ctx context.Context, currencyCode string) (Account
 		&i.LastUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MinorUnits,
 	)
 	return i, err
 }
@@ -98,8 +120,43 @@ func (q *Queries) HardDeleteCurrency(ctx context.Context, currencyCode string) e
 	return err
 }
 
+const listAllCurrencies = `-- name: ListAllCurrencies :many
+SELECT currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at, minor_units FROM account_currencies
+ORDER BY currency_code
+`
+
+func (q *Queries) ListAllCurrencies(ctx context.Context) ([]AccountCurrency, error) {
+	rows, err := q.db.Query(ctx, listAllCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AccountCurrency{}
+	for rows.Next() {
+		var i AccountCurrency
+		if err := rows.Scan(
+			&i.CurrencyCode,
+			&i.CurrencyName,
+			&i.Symbol,
+			&i.IsActive,
+			&i.ExchangeRate,
+			&i.LastUpdatedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MinorUnits,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCurrencies = `-- name: ListCurrencies :many
-SELECT currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at FROM account_currencies
+SELECT currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at, minor_units FROM account_currencies
 WHERE is_active = true
 `
 
@@ -121,6 +178,7 @@ func (q *Queries) ListCurrencies(ctx context.Context) ([]AccountCurrency, error)
 			&i.LastUpdatedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MinorUnits,
 		); err != nil {
 			return nil, err
 		}
@@ -132,13 +190,114 @@ func (q *Queries) ListCurrencies(ctx context.Context) ([]AccountCurrency, error)
 	return items, nil
 }
 
+const seedCurrencies = `-- name: SeedCurrencies :execrows
+INSERT INTO account_currencies (
+    currency_code,
+    currency_name,
+    symbol,
+    minor_units,
+    is_active,
+    exchange_rate,
+    last_updated_at
+)
+SELECT
+    seed.currency_code,
+    seed.currency_name,
+    seed.symbol,
+    seed.minor_units,
+    seed.is_active,
+    seed.exchange_rate,
+    CASE WHEN seed.exchange_rate IS NULL THEN NULL ELSE CURRENT_TIMESTAMP END
+FROM unnest(
+    $1::VARCHAR[],
+    $2::VARCHAR[],
+    $3::VARCHAR[],
+    $4::SMALLINT[],
+    $5::BOOLEAN[],
+    $6::DECIMAL[]
+) AS seed(currency_code, currency_name, symbol, minor_units, is_active, exchange_rate)
+ON CONFLICT (currency_code) DO UPDATE
+SET minor_units = EXCLUDED.minor_units,
+    updated_at = CURRENT_TIMESTAMP
+WHERE account_currencies.minor_units <> EXCLUDED.minor_units
+`
+
+type SeedCurrenciesParams struct {
+	CurrencyCodes []string         `json:"currency_codes"`
+	CurrencyNames []string         `json:"currency_names"`
+	Symbols       []string         `json:"symbols"`
+	MinorUnits    []int16          `json:"minor_units"`
+	IsActive      []bool           `json:"is_active"`
+	ExchangeRates []pgtype.Numeric `json:"exchange_rates"`
+}
+
+// SeedCurrencies adds the currencies missing from the registry and corrects the minor units of
+// the ones already there; names, symbols, rates and active flags set since are kept
+func (q *Queries) SeedCurrencies(ctx context.Context, arg SeedCurrenciesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, seedCurrencies,
+		arg.CurrencyCodes,
+		arg.CurrencyNames,
+		arg.Symbols,
+		arg.MinorUnits,
+		arg.IsActive,
+		arg.ExchangeRates,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateCurrency = `-- name: UpdateCurrency :one
+UPDATE account_currencies
+SET
+    currency_name = COALESCE($1, currency_name),
+    symbol = COALESCE($2, symbol),
+    minor_units = COALESCE($3, minor_units),
+    is_active = COALESCE($4, is_active),
+    updated_at = CURRENT_TIMESTAMP
+WHERE currency_code = $5
+RETURNING currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at, minor_units
+`
+
+type UpdateCurrencyParams struct {
+	CurrencyName sql.NullString `json:"currency_name"`
+	Symbol       sql.NullString `json:"symbol"`
+	MinorUnits   sql.NullInt16  `json:"minor_units"`
+	IsActive     sql.NullBool   `json:"is_active"`
+	CurrencyCode string         `json:"currency_code"`
+}
+
+func (q *Queries) UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (AccountCurrency, error) {
+	row := q.db.QueryRow(ctx, updateCurrency,
+		arg.CurrencyName,
+		arg.Symbol,
+		arg.MinorUnits,
+		arg.IsActive,
+		arg.CurrencyCode,
+	)
+	var i AccountCurrency
+	err := row.Scan(
+		&i.CurrencyCode,
+		&i.CurrencyName,
+		&i.Symbol,
+		&i.IsActive,
+		&i.ExchangeRate,
+		&i.LastUpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MinorUnits,
+	)
+	return i, err
+}
+
 const updateExchangeRate = `-- name: UpdateExchangeRate :one
 UPDATE account_currencies
 SET 
     exchange_rate = $1,
     last_updated_at = CURRENT_TIMESTAMP
 WHERE currency_code = $2
-RETURNING currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at
+RETURNING currency_code, currency_name, symbol, is_active, exchange_rate, last_updated_at, created_at, updated_at, minor_units
 `
 
 type UpdateExchangeRateParams struct {
@@ -147,7 +306,10 @@ type UpdateExchangeRateParams struct {
 }
 
 func (q *Queries) UpdateExchangeRate(ctx context.Context, arg UpdateExchangeRateParams) (AccountCurrency, error) {
-	row := q.db.QueryRow(ctx, updateExchangeRate, arg.ExchangeRate, arg.CurrencyCode)
+	row := q.db.QueryRow(ctx, updateExchangeRate,
+		arg.ExchangeRate,
+		arg.CurrencyCode,
+	)
 	var i AccountCurrency
 	err := row.Scan(
 		&i.CurrencyCode,
@@ -158,6 +320,7 @@ func (q *Queries) UpdateExchangeRate(ctx context.Context, arg UpdateExchangeRate
 		&i.LastUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MinorUnits,
 	)
 	return i, err
 }
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// AuditActorContextKey is the context key the audit actor of a request is stored under. It is a
+// string so it can be set on a gin context with Set and still be found through Value.
+const AuditActorContextKey = "audit_actor"
+
+const setAuditActorSQL = `SELECT set_config('app.user_id', $1, false), set_config('app.ip_address', $2, false)`
+
+// AuditActor is who a database change is made for, as recorded by the audit_trail triggers
+type AuditActor struct {
+	UserID    int64
+	IPAddress string
+}
+
+// WithAuditActor returns a copy of ctx whose database changes are recorded as made by actor
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, AuditActorContextKey, actor)
+}
+
+// AuditActorFromContext returns the audit actor stored in ctx, if any
+func AuditActorFromContext(ctx context.Context) (AuditActor, bool) {
+	actor, ok := ctx.Value(AuditActorContextKey).(AuditActor)
+	return actor, ok
+}
+
+// ConfigureAuditSession makes pool connections carry the audit actor of the context they are
+// acquired with, so the audit_trail triggers can attribute changes. The actor is cleared again
+// before the connection goes back to the pool; a connection that cannot be cleared is closed.
+func ConfigureAuditSession(config *pgxpool.Config) {
+	var tagged sync.Map
+
+	config.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		actor, ok := AuditActorFromContext(ctx)
+		if !ok {
+			return true
+		}
+		userID := ""
+		if actor.UserID != 0 {
+			userID = strconv.FormatInt(actor.UserID, 10)
+		}
+		if _, err := conn.Exec(ctx, setAuditActorSQL, userID, actor.IPAddress); err != nil {
+			return false
+		}
+		tagged.Store(conn, struct{}{})
+		return true
+	}
+
+	config.AfterRelease = func(conn *pgx.Conn) bool {
+		if _, ok := tagged.LoadAndDelete(conn); !ok {
+			return true
+		}
+		_, err := conn.Exec(context.Background(), setAuditActorSQL, "", "")
+		return err == nil
+	}
+}
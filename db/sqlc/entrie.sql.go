@@ -16,25 +16,43 @@ import (
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
     account_id,
-    amount
+    amount,
+    transaction_id,
+    currency_code,
+    exchange_rate
 ) VALUES (
-    $1, $2
-) RETURNING id, account_id, amount, created_at
+    $1, $2, $3, $4, $5
+) RETURNING id, account_id, amount, created_at, transaction_id, currency_code, exchange_rate, chain_seq, prev_hash, row_hash
 `
 
 type CreateEntryParams struct {
-	AccountID sql.NullInt32  `json:"account_id"`
-	Amount    pgtype.Numeric `json:"amount"`
+	AccountID     sql.NullInt32  `json:"account_id"`
+	Amount        pgtype.Numeric `json:"amount"`
+	TransactionID sql.NullInt32  `json:"transaction_id"`
+	CurrencyCode  sql.NullString `json:"currency_code"`
+	ExchangeRate  pgtype.Numeric `json:"exchange_rate"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRow(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.TransactionID,
+		arg.CurrencyCode,
+		arg.ExchangeRate,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransactionID,
+		&i.CurrencyCode,
+		&i.ExchangeRate,
+		&i.ChainSeq,
+		&i.PrevHash,
+		&i.RowHash,
 	)
 	return i, err
 }
@@ -92,7 +110,7 @@ func (q *Queries) GetAccountStatement(ctx context.Context, arg GetAccountStateme
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transaction_id, currency_code, exchange_rate, chain_seq, prev_hash, row_hash FROM entries
 WHERE id = $1
 `
 
@@ -104,6 +122,12 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransactionID,
+		&i.CurrencyCode,
+		&i.ExchangeRate,
+		&i.ChainSeq,
+		&i.PrevHash,
+		&i.RowHash,
 	)
 	return i, err
 }
@@ -119,7 +143,7 @@ func (q *Queries) HardDeleteEntries(ctx context.Context, accountID sql.NullInt32
 }
 
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transaction_id, currency_code, exchange_rate, chain_seq, prev_hash, row_hash FROM entries
 WHERE account_id = $1
 ORDER BY id DESC
 LIMIT $2 OFFSET $3
@@ -145,6 +169,12 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransactionID,
+			&i.CurrencyCode,
+			&i.ExchangeRate,
+			&i.ChainSeq,
+			&i.PrevHash,
+			&i.RowHash,
 		); err != nil {
 			return nil, err
 		}
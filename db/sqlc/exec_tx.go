@@ -22,7 +22,7 @@ func (store *SQLStore) ExecTx(ctx context.Context, fn func(*Queries) error) erro
 
 	q := New(tx)
 	if err = fn(q); err != nil {
-		return fmt.Errorf("error executing transaction: %v", err)
+		return fmt.Errorf("error executing transaction: %w", err)
 	}
 	if err = tx.Commit(ctx); err != nil {
 		return fmt.Errorf("error committing transaction: %v", err)
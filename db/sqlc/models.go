@@ -14,6 +14,702 @@ import (
 	"github.com/jackc/pgtype"
 )
 
+type BatchItemStatus string
+
+const (
+	BatchItemStatusPENDING   BatchItemStatus = "PENDING"
+	BatchItemStatusSUCCEEDED BatchItemStatus = "SUCCEEDED"
+	BatchItemStatusFAILED    BatchItemStatus = "FAILED"
+)
+
+func (e *BatchItemStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BatchItemStatus(s)
+	case string:
+		*e = BatchItemStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BatchItemStatus: %T", src)
+	}
+	return nil
+}
+
+type NullBatchItemStatus struct {
+	BatchItemStatus BatchItemStatus `json:"batch_item_status"`
+	Valid           bool            `json:"valid"` // Valid is true if BatchItemStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBatchItemStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.BatchItemStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BatchItemStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBatchItemStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BatchItemStatus), nil
+}
+
+type BatchMode string
+
+const (
+	BatchModeALLORNOTHING BatchMode = "ALL_OR_NOTHING"
+	BatchModeBESTEFFORT   BatchMode = "BEST_EFFORT"
+)
+
+func (e *BatchMode) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BatchMode(s)
+	case string:
+		*e = BatchMode(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BatchMode: %T", src)
+	}
+	return nil
+}
+
+type NullBatchMode struct {
+	BatchMode BatchMode `json:"batch_mode"`
+	Valid     bool      `json:"valid"` // Valid is true if BatchMode is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBatchMode) Scan(value interface{}) error {
+	if value == nil {
+		ns.BatchMode, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BatchMode.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBatchMode) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BatchMode), nil
+}
+
+type BatchStatus string
+
+const (
+	BatchStatusUPLOADED           BatchStatus = "UPLOADED"
+	BatchStatusPENDING            BatchStatus = "PENDING"
+	BatchStatusPROCESSING         BatchStatus = "PROCESSING"
+	BatchStatusCOMPLETED          BatchStatus = "COMPLETED"
+	BatchStatusPARTIALLYCOMPLETED BatchStatus = "PARTIALLY_COMPLETED"
+	BatchStatusFAILED             BatchStatus = "FAILED"
+)
+
+func (e *BatchStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BatchStatus(s)
+	case string:
+		*e = BatchStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BatchStatus: %T", src)
+	}
+	return nil
+}
+
+type NullBatchStatus struct {
+	BatchStatus BatchStatus `json:"batch_status"`
+	Valid       bool        `json:"valid"` // Valid is true if BatchStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBatchStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.BatchStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BatchStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBatchStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BatchStatus), nil
+}
+
+type DayCountConvention string
+
+const (
+	DayCountConventionACT365 DayCountConvention = "ACT_365"
+	DayCountConvention30360  DayCountConvention = "30_360"
+)
+
+func (e *DayCountConvention) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DayCountConvention(s)
+	case string:
+		*e = DayCountConvention(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DayCountConvention: %T", src)
+	}
+	return nil
+}
+
+type NullDayCountConvention struct {
+	DayCountConvention DayCountConvention `json:"day_count_convention"`
+	Valid              bool               `json:"valid"` // Valid is true if DayCountConvention is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDayCountConvention) Scan(value interface{}) error {
+	if value == nil {
+		ns.DayCountConvention, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DayCountConvention.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDayCountConvention) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DayCountConvention), nil
+}
+
+type DiscrepancyType string
+
+const (
+	DiscrepancyTypeBALANCEMISMATCH       DiscrepancyType = "BALANCE_MISMATCH"
+	DiscrepancyTypeUNBALANCEDTRANSACTION DiscrepancyType = "UNBALANCED_TRANSACTION"
+	DiscrepancyTypeMISSINGENTRIES        DiscrepancyType = "MISSING_ENTRIES"
+)
+
+func (e *DiscrepancyType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DiscrepancyType(s)
+	case string:
+		*e = DiscrepancyType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DiscrepancyType: %T", src)
+	}
+	return nil
+}
+
+type NullDiscrepancyType struct {
+	DiscrepancyType DiscrepancyType `json:"discrepancy_type"`
+	Valid           bool            `json:"valid"` // Valid is true if DiscrepancyType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDiscrepancyType) Scan(value interface{}) error {
+	if value == nil {
+		ns.DiscrepancyType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DiscrepancyType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDiscrepancyType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DiscrepancyType), nil
+}
+
+type FeeType string
+
+const (
+	FeeTypeTRANSFER    FeeType = "TRANSFER"
+	FeeTypeFXMARKUP    FeeType = "FX_MARKUP"
+	FeeTypeMAINTENANCE FeeType = "MAINTENANCE"
+	FeeTypeOVERDRAFT   FeeType = "OVERDRAFT"
+	FeeTypeEARLYBREAK  FeeType = "EARLY_BREAK"
+)
+
+func (e *FeeType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = FeeType(s)
+	case string:
+		*e = FeeType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for FeeType: %T", src)
+	}
+	return nil
+}
+
+type NullFeeType struct {
+	FeeType FeeType `json:"fee_type"`
+	Valid   bool    `json:"valid"` // Valid is true if FeeType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullFeeType) Scan(value interface{}) error {
+	if value == nil {
+		ns.FeeType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.FeeType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullFeeType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.FeeType), nil
+}
+
+type FixedDepositStatus string
+
+const (
+	FixedDepositStatusACTIVE  FixedDepositStatus = "ACTIVE"
+	FixedDepositStatusMATURED FixedDepositStatus = "MATURED"
+	FixedDepositStatusBROKEN  FixedDepositStatus = "BROKEN"
+)
+
+func (e *FixedDepositStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = FixedDepositStatus(s)
+	case string:
+		*e = FixedDepositStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for FixedDepositStatus: %T", src)
+	}
+	return nil
+}
+
+type NullFixedDepositStatus struct {
+	FixedDepositStatus FixedDepositStatus `json:"fixed_deposit_status"`
+	Valid              bool               `json:"valid"` // Valid is true if FixedDepositStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullFixedDepositStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.FixedDepositStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.FixedDepositStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullFixedDepositStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.FixedDepositStatus), nil
+}
+
+type HoldStatus string
+
+const (
+	HoldStatusOPEN     HoldStatus = "OPEN"
+	HoldStatusCAPTURED HoldStatus = "CAPTURED"
+	HoldStatusRELEASED HoldStatus = "RELEASED"
+	HoldStatusEXPIRED  HoldStatus = "EXPIRED"
+)
+
+func (e *HoldStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = HoldStatus(s)
+	case string:
+		*e = HoldStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for HoldStatus: %T", src)
+	}
+	return nil
+}
+
+type NullHoldStatus struct {
+	HoldStatus HoldStatus `json:"hold_status"`
+	Valid      bool       `json:"valid"` // Valid is true if HoldStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullHoldStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.HoldStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.HoldStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullHoldStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.HoldStatus), nil
+}
+
+type InterestPostingPeriod string
+
+const (
+	InterestPostingPeriodMONTHLY   InterestPostingPeriod = "MONTHLY"
+	InterestPostingPeriodQUARTERLY InterestPostingPeriod = "QUARTERLY"
+	InterestPostingPeriodANNUALLY  InterestPostingPeriod = "ANNUALLY"
+)
+
+func (e *InterestPostingPeriod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InterestPostingPeriod(s)
+	case string:
+		*e = InterestPostingPeriod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InterestPostingPeriod: %T", src)
+	}
+	return nil
+}
+
+type NullInterestPostingPeriod struct {
+	InterestPostingPeriod InterestPostingPeriod `json:"interest_posting_period"`
+	Valid                 bool                  `json:"valid"` // Valid is true if InterestPostingPeriod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInterestPostingPeriod) Scan(value interface{}) error {
+	if value == nil {
+		ns.InterestPostingPeriod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InterestPostingPeriod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInterestPostingPeriod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InterestPostingPeriod), nil
+}
+
+type MaturityInstruction string
+
+const (
+	MaturityInstructionPAYOUT   MaturityInstruction = "PAYOUT"
+	MaturityInstructionROLLOVER MaturityInstruction = "ROLLOVER"
+)
+
+func (e *MaturityInstruction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MaturityInstruction(s)
+	case string:
+		*e = MaturityInstruction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MaturityInstruction: %T", src)
+	}
+	return nil
+}
+
+type NullMaturityInstruction struct {
+	MaturityInstruction MaturityInstruction `json:"maturity_instruction"`
+	Valid               bool                `json:"valid"` // Valid is true if MaturityInstruction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMaturityInstruction) Scan(value interface{}) error {
+	if value == nil {
+		ns.MaturityInstruction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MaturityInstruction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMaturityInstruction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MaturityInstruction), nil
+}
+
+type ReconciliationStatus string
+
+const (
+	ReconciliationStatusRUNNING   ReconciliationStatus = "RUNNING"
+	ReconciliationStatusCOMPLETED ReconciliationStatus = "COMPLETED"
+	ReconciliationStatusFAILED    ReconciliationStatus = "FAILED"
+)
+
+func (e *ReconciliationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReconciliationStatus(s)
+	case string:
+		*e = ReconciliationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReconciliationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReconciliationStatus struct {
+	ReconciliationStatus ReconciliationStatus `json:"reconciliation_status"`
+	Valid                bool                 `json:"valid"` // Valid is true if ReconciliationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReconciliationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReconciliationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReconciliationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReconciliationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReconciliationStatus), nil
+}
+
+type ScheduleFrequency string
+
+const (
+	ScheduleFrequencyONCE    ScheduleFrequency = "ONCE"
+	ScheduleFrequencyDAILY   ScheduleFrequency = "DAILY"
+	ScheduleFrequencyWEEKLY  ScheduleFrequency = "WEEKLY"
+	ScheduleFrequencyMONTHLY ScheduleFrequency = "MONTHLY"
+	ScheduleFrequencyCRON    ScheduleFrequency = "CRON"
+)
+
+func (e *ScheduleFrequency) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduleFrequency(s)
+	case string:
+		*e = ScheduleFrequency(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduleFrequency: %T", src)
+	}
+	return nil
+}
+
+type NullScheduleFrequency struct {
+	ScheduleFrequency ScheduleFrequency `json:"schedule_frequency"`
+	Valid             bool              `json:"valid"` // Valid is true if ScheduleFrequency is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduleFrequency) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduleFrequency, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduleFrequency.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduleFrequency) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduleFrequency), nil
+}
+
+type ScheduledExecutionStatus string
+
+const (
+	ScheduledExecutionStatusSUCCEEDED ScheduledExecutionStatus = "SUCCEEDED"
+	ScheduledExecutionStatusFAILED    ScheduledExecutionStatus = "FAILED"
+)
+
+func (e *ScheduledExecutionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduledExecutionStatus(s)
+	case string:
+		*e = ScheduledExecutionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduledExecutionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullScheduledExecutionStatus struct {
+	ScheduledExecutionStatus ScheduledExecutionStatus `json:"scheduled_execution_status"`
+	Valid                    bool                     `json:"valid"` // Valid is true if ScheduledExecutionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduledExecutionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduledExecutionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduledExecutionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduledExecutionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduledExecutionStatus), nil
+}
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusACTIVE    ScheduledTransferStatus = "ACTIVE"
+	ScheduledTransferStatusPAUSED    ScheduledTransferStatus = "PAUSED"
+	ScheduledTransferStatusCOMPLETED ScheduledTransferStatus = "COMPLETED"
+	ScheduledTransferStatusFAILED    ScheduledTransferStatus = "FAILED"
+	ScheduledTransferStatusCANCELLED ScheduledTransferStatus = "CANCELLED"
+)
+
+func (e *ScheduledTransferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduledTransferStatus(s)
+	case string:
+		*e = ScheduledTransferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduledTransferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullScheduledTransferStatus struct {
+	ScheduledTransferStatus ScheduledTransferStatus `json:"scheduled_transfer_status"`
+	Valid                   bool                    `json:"valid"` // Valid is true if ScheduledTransferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduledTransferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduledTransferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduledTransferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduledTransferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduledTransferStatus), nil
+}
+
+type StatementFormat string
+
+const (
+	StatementFormatCSV     StatementFormat = "CSV"
+	StatementFormatPDF     StatementFormat = "PDF"
+	StatementFormatOFX     StatementFormat = "OFX"
+	StatementFormatCAMT053 StatementFormat = "CAMT053"
+)
+
+func (e *StatementFormat) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StatementFormat(s)
+	case string:
+		*e = StatementFormat(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StatementFormat: %T", src)
+	}
+	return nil
+}
+
+type NullStatementFormat struct {
+	StatementFormat StatementFormat `json:"statement_format"`
+	Valid           bool            `json:"valid"` // Valid is true if StatementFormat is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStatementFormat) Scan(value interface{}) error {
+	if value == nil {
+		ns.StatementFormat, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StatementFormat.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStatementFormat) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StatementFormat), nil
+}
+
+type StatementStatus string
+
+const (
+	StatementStatusPENDING    StatementStatus = "PENDING"
+	StatementStatusPROCESSING StatementStatus = "PROCESSING"
+	StatementStatusCOMPLETED  StatementStatus = "COMPLETED"
+	StatementStatusFAILED     StatementStatus = "FAILED"
+)
+
+func (e *StatementStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StatementStatus(s)
+	case string:
+		*e = StatementStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StatementStatus: %T", src)
+	}
+	return nil
+}
+
+type NullStatementStatus struct {
+	StatementStatus StatementStatus `json:"statement_status"`
+	Valid           bool            `json:"valid"` // Valid is true if StatementStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStatementStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.StatementStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StatementStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStatementStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StatementStatus), nil
+}
+
 type UploadStatus string
 
 const (
@@ -59,6 +755,50 @@ func (ns NullUploadStatus) Value() (driver.Value, error) {
 	return string(ns.UploadStatus), nil
 }
 
+type UserRole string
+
+const (
+	UserRoleCUSTOMER UserRole = "CUSTOMER"
+	UserRoleSUPPORT  UserRole = "SUPPORT"
+	UserRoleADMIN    UserRole = "ADMIN"
+	UserRoleAUDITOR  UserRole = "AUDITOR"
+)
+
+func (e *UserRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UserRole(s)
+	case string:
+		*e = UserRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UserRole: %T", src)
+	}
+	return nil
+}
+
+type NullUserRole struct {
+	UserRole UserRole `json:"user_role"`
+	Valid    bool     `json:"valid"` // Valid is true if UserRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUserRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.UserRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UserRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUserRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UserRole), nil
+}
+
 type Account struct {
 	AccountID      int32          `json:"account_id"`
 	UserID         int32          `json:"user_id"`
@@ -71,6 +811,7 @@ type Account struct {
 	IsActive       bool           `json:"is_active"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
+	HeldAmount     pgtype.Numeric `json:"held_amount"`
 }
 
 type AccountCurrency struct {
@@ -82,6 +823,7 @@ type AccountCurrency struct {
 	LastUpdatedAt sql.NullTime   `json:"last_updated_at"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
+	MinorUnits    int16          `json:"minor_units"`
 }
 
 type AccountType struct {
@@ -92,23 +834,88 @@ type AccountType struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+type ApiKey struct {
+	ApiKeyID        int32         `json:"api_key_id"`
+	KeyPrefix       string        `json:"key_prefix"`
+	KeyHash         string        `json:"key_hash"`
+	Name            string        `json:"name"`
+	OwnerUserID     int32         `json:"owner_user_id"`
+	Scopes          []string      `json:"scopes"`
+	ExpiresAt       sql.NullTime  `json:"expires_at"`
+	LastUsedAt      sql.NullTime  `json:"last_used_at"`
+	RevokedAt       sql.NullTime  `json:"revoked_at"`
+	RotatedFrom     sql.NullInt32 `json:"rotated_from"`
+	CreatedBy       sql.NullInt32 `json:"created_by"`
+	CreatedAt       time.Time     `json:"created_at"`
+	SigningRequired bool          `json:"signing_required"`
+}
+
 type AuditTrail struct {
 	AuditID   int32          `json:"audit_id"`
 	TableName string         `json:"table_name"`
-	RecordID  int32          `json:"record_id"`
+	RecordID  string         `json:"record_id"`
 	Action    string         `json:"action"`
 	OldValues pgtype.JSONB   `json:"old_values"`
 	NewValues pgtype.JSONB   `json:"new_values"`
 	UserID    sql.NullInt32  `json:"user_id"`
 	IpAddress sql.NullString `json:"ip_address"`
 	CreatedAt time.Time      `json:"created_at"`
+	ChainSeq  sql.NullInt64  `json:"chain_seq"`
+	PrevHash  sql.NullString `json:"prev_hash"`
+	RowHash   sql.NullString `json:"row_hash"`
 }
 
 type Entry struct {
-	ID        int64          `json:"id"`
-	AccountID sql.NullInt32  `json:"account_id"`
-	Amount    pgtype.Numeric `json:"amount"`
-	CreatedAt time.Time      `json:"created_at"`
+	ID            int64          `json:"id"`
+	AccountID     sql.NullInt32  `json:"account_id"`
+	Amount        pgtype.Numeric `json:"amount"`
+	CreatedAt     time.Time      `json:"created_at"`
+	TransactionID sql.NullInt32  `json:"transaction_id"`
+	CurrencyCode  sql.NullString `json:"currency_code"`
+	ExchangeRate  pgtype.Numeric `json:"exchange_rate"`
+	ChainSeq      sql.NullInt64  `json:"chain_seq"`
+	PrevHash      sql.NullString `json:"prev_hash"`
+	RowHash       sql.NullString `json:"row_hash"`
+}
+
+type ExchangeRateHistory struct {
+	RateID       int64          `json:"rate_id"`
+	CurrencyCode string         `json:"currency_code"`
+	Rate         pgtype.Numeric `json:"rate"`
+	Source       string         `json:"source"`
+	ValidFrom    time.Time      `json:"valid_from"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type FeeCharge struct {
+	ChargeID             int64          `json:"charge_id"`
+	AccountID            int32          `json:"account_id"`
+	FeeType              FeeType        `json:"fee_type"`
+	Amount               pgtype.Numeric `json:"amount"`
+	CurrencyCode         string         `json:"currency_code"`
+	TransactionID        int32          `json:"transaction_id"`
+	RelatedTransactionID sql.NullInt32  `json:"related_transaction_id"`
+	PeriodStart          sql.NullTime   `json:"period_start"`
+	CreatedAt            time.Time      `json:"created_at"`
+}
+
+type FeeIncomeAccount struct {
+	CurrencyCode string    `json:"currency_code"`
+	AccountID    int32     `json:"account_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type FeeSchedule struct {
+	AccountType           string         `json:"account_type"`
+	TransferFeeFlat       pgtype.Numeric `json:"transfer_fee_flat"`
+	TransferFeePercent    pgtype.Numeric `json:"transfer_fee_percent"`
+	FxMarkupPercent       pgtype.Numeric `json:"fx_markup_percent"`
+	MonthlyMaintenanceFee pgtype.Numeric `json:"monthly_maintenance_fee"`
+	OverdraftFee          pgtype.Numeric `json:"overdraft_fee"`
+	IsActive              bool           `json:"is_active"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
 }
 
 type FileMetadatum struct {
@@ -124,22 +931,215 @@ type FileMetadatum struct {
 	UpdatedAt      time.Time     `json:"updated_at"`
 }
 
-type Transaction struct {
+type FixedDeposit struct {
+	DepositID                int32               `json:"deposit_id"`
+	DepositNumber            uuid.UUID           `json:"deposit_number"`
+	AccountID                int32               `json:"account_id"`
+	LinkedAccountID          int32               `json:"linked_account_id"`
+	Principal                pgtype.Numeric      `json:"principal"`
+	InterestRate             pgtype.Numeric      `json:"interest_rate"`
+	TermMonths               int32               `json:"term_months"`
+	StartDate                time.Time           `json:"start_date"`
+	MaturityDate             time.Time           `json:"maturity_date"`
+	MaturityInstruction      MaturityInstruction `json:"maturity_instruction"`
+	EarlyBreakPenaltyPercent pgtype.Numeric      `json:"early_break_penalty_percent"`
+	Status                   FixedDepositStatus  `json:"status"`
+	RolloverCount            int32               `json:"rollover_count"`
+	PayoutTransactionID      sql.NullInt32       `json:"payout_transaction_id"`
+	ClosedAt                 sql.NullTime        `json:"closed_at"`
+	CreatedAt                time.Time           `json:"created_at"`
+	UpdatedAt                time.Time           `json:"updated_at"`
+}
+
+type FixedDepositTerm struct {
+	TermMonths               int32          `json:"term_months"`
+	InterestRate             pgtype.Numeric `json:"interest_rate"`
+	EarlyBreakPenaltyPercent pgtype.Numeric `json:"early_break_penalty_percent"`
+	IsActive                 bool           `json:"is_active"`
+	CreatedAt                time.Time      `json:"created_at"`
+	UpdatedAt                time.Time      `json:"updated_at"`
+}
+
+type HashChainCheckpoint struct {
+	CheckpointID int32     `json:"checkpoint_id"`
+	ChainName    string    `json:"chain_name"`
+	ChainSeq     int64     `json:"chain_seq"`
+	RowHash      string    `json:"row_hash"`
+	Signature    string    `json:"signature"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type HashChainHead struct {
+	ChainName string    `json:"chain_name"`
+	LastSeq   int64     `json:"last_seq"`
+	LastHash  string    `json:"last_hash"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Hold struct {
+	HoldID            int32          `json:"hold_id"`
+	HoldNumber        uuid.UUID      `json:"hold_number"`
+	AccountID         int32          `json:"account_id"`
+	MerchantAccountID int32          `json:"merchant_account_id"`
 	TransactionID     int32          `json:"transaction_id"`
-	FromAccountID     sql.NullInt32  `json:"from_account_id"`
-	ToAccountID       sql.NullInt32  `json:"to_account_id"`
-	TypeCode          string         `json:"type_code"`
 	Amount            pgtype.Numeric `json:"amount"`
+	CapturedAmount    pgtype.Numeric `json:"captured_amount"`
 	CurrencyCode      string         `json:"currency_code"`
-	ExchangeRate      pgtype.Numeric `json:"exchange_rate"`
-	StatusCode        string         `json:"status_code"`
-	IsCompleted       bool           `json:"is_completed"`
+	Status            HoldStatus     `json:"status"`
 	Description       sql.NullString `json:"description"`
-	ReferenceNumber   sql.NullString `json:"reference_number"`
-	TransactionDate   time.Time      `json:"transaction_date"`
+	ExpiresAt         time.Time      `json:"expires_at"`
+	ClosedAt          sql.NullTime   `json:"closed_at"`
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
-	TransactionNumber uuid.UUID      `json:"transaction_number"`
+}
+
+type IdempotencyKey struct {
+	ID             int64         `json:"id"`
+	ClientID       string        `json:"client_id"`
+	IdempotencyKey string        `json:"idempotency_key"`
+	RequestMethod  string        `json:"request_method"`
+	RequestPath    string        `json:"request_path"`
+	RequestHash    string        `json:"request_hash"`
+	ResponseStatus sql.NullInt32 `json:"response_status"`
+	ResponseBody   pgtype.JSONB  `json:"response_body"`
+	CreatedAt      time.Time     `json:"created_at"`
+	CompletedAt    sql.NullTime  `json:"completed_at"`
+	ExpiresAt      time.Time     `json:"expires_at"`
+}
+
+type InterestAccrual struct {
+	AccrualID          int64              `json:"accrual_id"`
+	AccountID          int32              `json:"account_id"`
+	AccrualDate        time.Time          `json:"accrual_date"`
+	Balance            pgtype.Numeric     `json:"balance"`
+	InterestRate       pgtype.Numeric     `json:"interest_rate"`
+	DayCountConvention DayCountConvention `json:"day_count_convention"`
+	Amount             pgtype.Numeric     `json:"amount"`
+	Residue            pgtype.Numeric     `json:"residue"`
+	TransactionID      sql.NullInt32      `json:"transaction_id"`
+	PostedAt           sql.NullTime       `json:"posted_at"`
+	CreatedAt          time.Time          `json:"created_at"`
+}
+
+type InterestSetting struct {
+	AccountType        string                `json:"account_type"`
+	DayCountConvention DayCountConvention    `json:"day_count_convention"`
+	PostingPeriod      InterestPostingPeriod `json:"posting_period"`
+	IsActive           bool                  `json:"is_active"`
+	CreatedAt          time.Time             `json:"created_at"`
+	UpdatedAt          time.Time             `json:"updated_at"`
+}
+
+type LedgerDiscrepancy struct {
+	DiscrepancyID   int64           `json:"discrepancy_id"`
+	RunID           int32           `json:"run_id"`
+	DiscrepancyType DiscrepancyType `json:"discrepancy_type"`
+	AccountID       sql.NullInt32   `json:"account_id"`
+	TransactionID   sql.NullInt32   `json:"transaction_id"`
+	CurrencyCode    sql.NullString  `json:"currency_code"`
+	ExpectedAmount  pgtype.Numeric  `json:"expected_amount"`
+	ActualAmount    pgtype.Numeric  `json:"actual_amount"`
+	Difference      pgtype.Numeric  `json:"difference"`
+	Details         sql.NullString  `json:"details"`
+	DetectedAt      time.Time       `json:"detected_at"`
+}
+
+type ReconciliationRun struct {
+	RunID               int32                `json:"run_id"`
+	RunNumber           uuid.UUID            `json:"run_number"`
+	AccountIds          []int32              `json:"account_ids"`
+	Status              ReconciliationStatus `json:"status"`
+	AccountsChecked     int32                `json:"accounts_checked"`
+	TransactionsChecked int32                `json:"transactions_checked"`
+	DiscrepancyCount    int32                `json:"discrepancy_count"`
+	ErrorMessage        sql.NullString       `json:"error_message"`
+	StartedAt           time.Time            `json:"started_at"`
+	CompletedAt         sql.NullTime         `json:"completed_at"`
+}
+
+type RefreshToken struct {
+	TokenID    int64          `json:"token_id"`
+	UserID     int32          `json:"user_id"`
+	TokenHash  string         `json:"token_hash"`
+	FamilyID   uuid.UUID      `json:"family_id"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	RevokedAt  sql.NullTime   `json:"revoked_at"`
+	ReplacedBy sql.NullInt64  `json:"replaced_by"`
+	UserAgent  sql.NullString `json:"user_agent"`
+	IpAddress  sql.NullString `json:"ip_address"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type ScheduledTransfer struct {
+	ScheduledTransferID int32                   `json:"scheduled_transfer_id"`
+	ScheduleNumber      uuid.UUID               `json:"schedule_number"`
+	FromAccountID       int32                   `json:"from_account_id"`
+	ToAccountID         int32                   `json:"to_account_id"`
+	Amount              pgtype.Numeric          `json:"amount"`
+	CurrencyCode        string                  `json:"currency_code"`
+	Description         sql.NullString          `json:"description"`
+	Frequency           ScheduleFrequency       `json:"frequency"`
+	CronExpression      sql.NullString          `json:"cron_expression"`
+	Status              ScheduledTransferStatus `json:"status"`
+	StartAt             time.Time               `json:"start_at"`
+	EndAt               sql.NullTime            `json:"end_at"`
+	NextRunAt           time.Time               `json:"next_run_at"`
+	DueAt               time.Time               `json:"due_at"`
+	LastRunAt           sql.NullTime            `json:"last_run_at"`
+	RetryCount          int32                   `json:"retry_count"`
+	MaxRetries          int32                   `json:"max_retries"`
+	CreatedAt           time.Time               `json:"created_at"`
+	UpdatedAt           time.Time               `json:"updated_at"`
+	UserID              sql.NullInt32           `json:"user_id"`
+}
+
+type ScheduledTransferExecution struct {
+	ExecutionID         int64                    `json:"execution_id"`
+	ScheduledTransferID int32                    `json:"scheduled_transfer_id"`
+	ScheduledFor        time.Time                `json:"scheduled_for"`
+	Attempt             int32                    `json:"attempt"`
+	Status              ScheduledExecutionStatus `json:"status"`
+	TransactionID       sql.NullInt32            `json:"transaction_id"`
+	ErrorMessage        sql.NullString           `json:"error_message"`
+	ExecutedAt          time.Time                `json:"executed_at"`
+}
+
+type Statement struct {
+	StatementID     int32           `json:"statement_id"`
+	StatementNumber uuid.UUID       `json:"statement_number"`
+	AccountID       int32           `json:"account_id"`
+	Format          StatementFormat `json:"format"`
+	PeriodStart     time.Time       `json:"period_start"`
+	PeriodEnd       time.Time       `json:"period_end"`
+	Status          StatementStatus `json:"status"`
+	Attempts        int32           `json:"attempts"`
+	EntryCount      sql.NullInt32   `json:"entry_count"`
+	FileUrl         sql.NullString  `json:"file_url"`
+	FailureReason   sql.NullString  `json:"failure_reason"`
+	CompletedAt     sql.NullTime    `json:"completed_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+type Transaction struct {
+	TransactionID         int32          `json:"transaction_id"`
+	FromAccountID         sql.NullInt32  `json:"from_account_id"`
+	ToAccountID           sql.NullInt32  `json:"to_account_id"`
+	TypeCode              string         `json:"type_code"`
+	Amount                pgtype.Numeric `json:"amount"`
+	CurrencyCode          string         `json:"currency_code"`
+	ExchangeRate          pgtype.Numeric `json:"exchange_rate"`
+	StatusCode            string         `json:"status_code"`
+	IsCompleted           bool           `json:"is_completed"`
+	Description           sql.NullString `json:"description"`
+	ReferenceNumber       sql.NullString `json:"reference_number"`
+	TransactionDate       time.Time      `json:"transaction_date"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	TransactionNumber     uuid.UUID      `json:"transaction_number"`
+	ConvertedAmount       pgtype.Numeric `json:"converted_amount"`
+	ConvertedCurrencyCode sql.NullString `json:"converted_currency_code"`
+	OriginalTransactionID sql.NullInt32  `json:"original_transaction_id"`
 }
 
 type TransactionStatus struct {
@@ -158,6 +1158,40 @@ type TransactionType struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+type TransferBatch struct {
+	BatchID         int32          `json:"batch_id"`
+	BatchNumber     uuid.UUID      `json:"batch_number"`
+	Mode            BatchMode      `json:"mode"`
+	Status          BatchStatus    `json:"status"`
+	ReferenceNumber sql.NullString `json:"reference_number"`
+	UploadID        sql.NullString `json:"upload_id"`
+	TotalItems      int32          `json:"total_items"`
+	ProcessedItems  int32          `json:"processed_items"`
+	SucceededItems  int32          `json:"succeeded_items"`
+	FailedItems     int32          `json:"failed_items"`
+	ErrorMessage    sql.NullString `json:"error_message"`
+	StartedAt       sql.NullTime   `json:"started_at"`
+	CompletedAt     sql.NullTime   `json:"completed_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	UserID          sql.NullInt32  `json:"user_id"`
+}
+
+type TransferBatchItem struct {
+	ItemID        int64           `json:"item_id"`
+	BatchID       int32           `json:"batch_id"`
+	ItemIndex     int32           `json:"item_index"`
+	FromAccountID int32           `json:"from_account_id"`
+	ToAccountID   int32           `json:"to_account_id"`
+	Amount        pgtype.Numeric  `json:"amount"`
+	CurrencyCode  string          `json:"currency_code"`
+	Description   sql.NullString  `json:"description"`
+	Status        BatchItemStatus `json:"status"`
+	TransactionID sql.NullInt32   `json:"transaction_id"`
+	ErrorMessage  sql.NullString  `json:"error_message"`
+	ProcessedAt   sql.NullTime    `json:"processed_at"`
+}
+
 type UploadJob struct {
 	ID           string         `json:"id"`
 	FileName     string         `json:"file_name"`
@@ -186,4 +1220,17 @@ type User struct {
 	LastLogin       time.Time      `json:"last_login"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
+	Role            UserRole       `json:"role"`
+}
+
+type UserTwoFactor struct {
+	UserID             int32        `json:"user_id"`
+	Secret             string       `json:"secret"`
+	EnabledAt          sql.NullTime `json:"enabled_at"`
+	RecoveryCodeHashes []string     `json:"recovery_code_hashes"`
+	LastUsedStep       int64        `json:"last_used_step"`
+	FailedAttempts     int32        `json:"failed_attempts"`
+	LastFailedAt       sql.NullTime `json:"last_failed_at"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
 }
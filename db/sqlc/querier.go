@@ -7,52 +7,143 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
 )
 
 type Querier interface {
+	// Marks the oldest pending statements as PROCESSING and returns them. Statements left PROCESSING
+	// since before stale_before belonged to a worker that stopped, they are claimed again.
+	ClaimPendingStatements(ctx context.Context, arg ClaimPendingStatementsParams) ([]Statement, error)
+	// Takes the oldest pending batch, or a processing one whose worker stopped reporting progress
+	ClaimTransferBatch(ctx context.Context, staleBefore time.Time) (TransferBatch, error)
+	// Deactivates an account that holds no money and that no hold, schedule or deposit still refers
+	// to, it updates no row otherwise
+	CloseAccount(ctx context.Context, accountID int32) (int64, error)
+	// Ends a deposit, which unlocks its account so the balance can be paid out
+	CloseFixedDeposit(ctx context.Context, arg CloseFixedDepositParams) (FixedDeposit, error)
+	CloseHold(ctx context.Context, arg CloseHoldParams) (Hold, error)
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) (IdempotencyKey, error)
+	CompleteReconciliationRun(ctx context.Context, arg CompleteReconciliationRunParams) (ReconciliationRun, error)
+	CompleteStatement(ctx context.Context, arg CompleteStatementParams) (Statement, error)
+	CompleteTransferBatch(ctx context.Context, arg CompleteTransferBatchParams) (TransferBatch, error)
+	CountAccountsByCurrency(ctx context.Context, currencyCode string) (int64, error)
+	CountReconciliationAccounts(ctx context.Context, accountIds []int32) (int64, error)
+	CountReconciliationTransactions(ctx context.Context, accountIds []int32) (int64, error)
+	CountStatementEntries(ctx context.Context, arg CountStatementEntriesParams) (int64, error)
+	// CountUnchainedAuditTrail counts committed audit records that were never linked into the chain
+	CountUnchainedAuditTrail(ctx context.Context) (int64, error)
+	// CountUnchainedEntries counts committed entries that were never linked into the chain
+	CountUnchainedEntries(ctx context.Context) (int64, error)
 	CountUserUploads(ctx context.Context, userID int32) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	// CreateAccessDeniedAudit records a request refused by access control. The record is keyed by the
+	// route, details holds the method, path, role and why the request was refused.
+	CreateAccessDeniedAudit(ctx context.Context, arg CreateAccessDeniedAuditParams) error
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
 	CreateAccountType(ctx context.Context, arg CreateAccountTypeParams) (AccountType, error)
 	CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (AccountCurrency, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	// CreateExchangeRateHistory returns no rows when the currency already has a rate valid from the same time
+	CreateExchangeRateHistory(ctx context.Context, arg CreateExchangeRateHistoryParams) (ExchangeRateHistory, error)
+	CreateFeeCharge(ctx context.Context, arg CreateFeeChargeParams) (FeeCharge, error)
 	CreateFileMetadata(ctx context.Context, arg CreateFileMetadataParams) (FileMetadatum, error)
+	CreateFixedDeposit(ctx context.Context, arg CreateFixedDepositParams) (FixedDeposit, error)
+	CreateHashChainCheckpoint(ctx context.Context, arg CreateHashChainCheckpointParams) (HashChainCheckpoint, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (int64, error)
+	CreateLedgerDiscrepancy(ctx context.Context, arg CreateLedgerDiscrepancyParams) (LedgerDiscrepancy, error)
+	CreateReconciliationRun(ctx context.Context, accountIds []int32) (ReconciliationRun, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferExecution(ctx context.Context, arg CreateScheduledTransferExecutionParams) (ScheduledTransferExecution, error)
+	CreateStatement(ctx context.Context, arg CreateStatementParams) (Statement, error)
 	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
 	CreateTransactionStatus(ctx context.Context, arg CreateTransactionStatusParams) (TransactionStatus, error)
 	CreateTransactionType(ctx context.Context, arg CreateTransactionTypeParams) (TransactionType, error)
+	CreateTransferBatch(ctx context.Context, arg CreateTransferBatchParams) (TransferBatch, error)
+	CreateTransferBatchItem(ctx context.Context, arg CreateTransferBatchItemParams) (TransferBatchItem, error)
 	CreateUploadJob(ctx context.Context, arg CreateUploadJobParams) (UploadJob, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	DeleteAccount(ctx context.Context, accountID int32) error
 	DeleteAccountTransactions(ctx context.Context, fromAccountID sql.NullInt32) error
 	DeleteAccountType(ctx context.Context, accountType string) error
 	DeleteCurrency(ctx context.Context, currencyCode string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	// A key reserved before created_before and never completed was left by a request that crashed
+	DeleteStaleIdempotencyKey(ctx context.Context, arg DeleteStaleIdempotencyKeyParams) (int64, error)
 	DeleteTransaction(ctx context.Context, transactionNumber uuid.UUID) error
 	DeleteTransactionStatus(ctx context.Context, statusCode string) error
 	DeleteTransactionType(ctx context.Context, typeCode string) error
 	DeleteTransactionsByDateRange(ctx context.Context, arg DeleteTransactionsByDateRangeParams) error
 	DeleteTransactionsByStatus(ctx context.Context, statusCode string) error
+	DeleteTwoFactor(ctx context.Context, userID int32) error
 	DeleteUploadJob(ctx context.Context, id string) error
 	DeleteUser(ctx context.Context, userID int32) error
+	EnableTwoFactor(ctx context.Context, arg EnableTwoFactorParams) (UserTwoFactor, error)
+	FailStatement(ctx context.Context, arg FailStatementParams) (Statement, error)
+	GetAPIKey(ctx context.Context, apiKeyID int32) (ApiKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, keyPrefix string) (ApiKey, error)
+	GetAPIKeyForUpdate(ctx context.Context, apiKeyID int32) (ApiKey, error)
 	GetAccount(ctx context.Context, accountID int32) (Account, error)
 	GetAccountBalance(ctx context.Context, accountID sql.NullInt32) (interface{}, error)
+	// The balance of the account's entries booked before the given time, the opening balance of a statement
+	GetAccountBalanceBefore(ctx context.Context, arg GetAccountBalanceBeforeParams) (pgtype.Numeric, error)
+	GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error)
 	GetAccountForUpdate(ctx context.Context, accountID int32) (Account, error)
 	GetAccountStatement(ctx context.Context, arg GetAccountStatementParams) ([]GetAccountStatementRow, error)
 	GetAccountType(ctx context.Context, accountType string) (AccountType, error)
+	GetActiveFeeScheduleByAccount(ctx context.Context, accountID int32) (FeeSchedule, error)
 	GetActiveTransactionStatus(ctx context.Context, dollar_1 []string) ([]TransactionStatus, error)
+	GetAuditTrail(ctx context.Context, auditID int32) (AuditTrail, error)
+	GetCompensatedTotals(ctx context.Context, originalTransactionID sql.NullInt32) (GetCompensatedTotalsRow, error)
 	GetCurrency(ctx context.Context, currencyCode string) (AccountCurrency, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	// GetExchangeRateAt returns the rate of a currency that was valid at the given time
+	GetExchangeRateAt(ctx context.Context, arg GetExchangeRateAtParams) (ExchangeRateHistory, error)
+	GetFeeIncomeAccount(ctx context.Context, currencyCode string) (FeeIncomeAccount, error)
+	GetFeeSchedule(ctx context.Context, accountType string) (FeeSchedule, error)
 	GetFileMetadata(ctx context.Context, id int32) (FileMetadatum, error)
+	GetFixedDeposit(ctx context.Context, depositNumber uuid.UUID) (FixedDeposit, error)
+	GetFixedDepositForUpdate(ctx context.Context, depositNumber uuid.UUID) (FixedDeposit, error)
+	GetFixedDepositTerm(ctx context.Context, termMonths int32) (FixedDepositTerm, error)
+	GetHashChainHead(ctx context.Context, chainName string) (HashChainHead, error)
+	GetHold(ctx context.Context, holdNumber uuid.UUID) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, holdNumber uuid.UUID) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	// What the latest posting of an account left out when it was rounded to the minor unit
+	GetInterestResidue(ctx context.Context, accountID int32) (pgtype.Numeric, error)
+	GetInterestSettings(ctx context.Context, accountType string) (InterestSetting, error)
+	GetLatestHashChainCheckpoint(ctx context.Context, chainName string) (HashChainCheckpoint, error)
+	GetLatestInterestAccrualDate(ctx context.Context) (time.Time, error)
+	GetReconciliationRun(ctx context.Context, runNumber uuid.UUID) (ReconciliationRun, error)
+	GetRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetScheduledTransfer(ctx context.Context, scheduleNumber uuid.UUID) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, scheduleNumber uuid.UUID) (ScheduledTransfer, error)
+	GetStatement(ctx context.Context, statementNumber uuid.UUID) (Statement, error)
 	GetTransaction(ctx context.Context, transactionID int32) (Transaction, error)
 	GetTransactionBalance(ctx context.Context, fromAccountID sql.NullInt32) (interface{}, error)
+	GetTransactionByNumber(ctx context.Context, transactionNumber uuid.UUID) (Transaction, error)
+	GetTransactionByNumberForUpdate(ctx context.Context, transactionNumber uuid.UUID) (Transaction, error)
 	GetTransactionByReference(ctx context.Context, referenceNumber sql.NullString) (Transaction, error)
 	GetTransactionStatement(ctx context.Context, arg GetTransactionStatementParams) ([]GetTransactionStatementRow, error)
 	GetTransactionStatus(ctx context.Context, statusCode string) (TransactionStatus, error)
 	GetTransactionType(ctx context.Context, typeCode string) (TransactionType, error)
 	GetTransactionsByDateRange(ctx context.Context, arg GetTransactionsByDateRangeParams) ([]Transaction, error)
 	GetTransactionsByStatus(ctx context.Context, statusCode string) ([]Transaction, error)
+	GetTransferBatch(ctx context.Context, batchNumber uuid.UUID) (TransferBatch, error)
+	GetTransferBatchByUploadForUpdate(ctx context.Context, uploadID sql.NullString) (TransferBatch, error)
+	GetTwoFactor(ctx context.Context, userID int32) (UserTwoFactor, error)
 	GetUploadJob(ctx context.Context, id string) (UploadJob, error)
 	GetUser(ctx context.Context, userID int32) (User, error)
+	// GetUserAccess returns what access control needs to know about a user, it is read on every request
+	GetUserAccess(ctx context.Context, userID int32) (GetUserAccessRow, error)
+	GetUserByUsername(ctx context.Context, username string) (User, error)
 	HardDeleteAccount(ctx context.Context, accountID int32) error
 	HardDeleteAccountType(ctx context.Context, accountType string) error
 	HardDeleteCurrency(ctx context.Context, currencyCode string) error
@@ -60,29 +151,128 @@ type Querier interface {
 	HardDeleteTransactionStatus(ctx context.Context, statusCode string) error
 	HardDeleteTransactionType(ctx context.Context, typeCode string) error
 	HardDeleteUser(ctx context.Context, userID int32) error
+	// Whether any account of the user has completed a transaction to the account before
+	HasTransferredTo(ctx context.Context, arg HasTransferredToParams) (bool, error)
+	// Whether the account holds a fixed deposit that has not matured or been broken yet
+	IsAccountLockedByFixedDeposit(ctx context.Context, accountID int32) (bool, error)
+	// ListAPIKeys returns API keys, newest first. Keys of every owner are listed when owner_user_id is NULL.
+	ListAPIKeys(ctx context.Context, arg ListAPIKeysParams) ([]ApiKey, error)
+	// Accounts whose stored balance differs from the sum of their entries
+	ListAccountBalanceDrift(ctx context.Context, accountIds []int32) ([]ListAccountBalanceDriftRow, error)
 	ListAccountTransactions(ctx context.Context, arg ListAccountTransactionsParams) ([]Transaction, error)
 	ListAccountTypes(ctx context.Context) ([]AccountType, error)
 	ListAccountsByUser(ctx context.Context, userID int32) ([]Account, error)
+	// Accounts that owe the maintenance fee of the month starting on period_start
+	ListAccountsDueMaintenanceFee(ctx context.Context, periodStart time.Time) ([]ListAccountsDueMaintenanceFeeRow, error)
+	// Accounts whose ledger balance was negative at the end of the day (UTC) starting on period_start
+	// and that have not paid that day's overdraft fee
+	ListAccountsDueOverdraftFee(ctx context.Context, periodStart time.Time) ([]ListAccountsDueOverdraftFeeRow, error)
+	ListAllCurrencies(ctx context.Context) ([]AccountCurrency, error)
+	// ListAuditTrail returns audit records, newest first. Filters left NULL match every record.
+	ListAuditTrail(ctx context.Context, arg ListAuditTrailParams) ([]AuditTrail, error)
+	// ListAuditTrailChain returns the links of the audit_trail chain after a position, in chain order
+	ListAuditTrailChain(ctx context.Context, arg ListAuditTrailChainParams) ([]AuditTrail, error)
 	ListCompletedUploadJobs(ctx context.Context, limit int32) ([]UploadJob, error)
 	ListCurrencies(ctx context.Context) ([]AccountCurrency, error)
+	ListDueScheduledTransfersForUpdate(ctx context.Context, limit int32) ([]ScheduledTransfer, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	// ListEntriesChain returns the links of the entries chain after a position, in chain order
+	ListEntriesChain(ctx context.Context, arg ListEntriesChainParams) ([]Entry, error)
+	ListExchangeRateHistory(ctx context.Context, arg ListExchangeRateHistoryParams) ([]ExchangeRateHistory, error)
+	ListExpiredHoldsForUpdate(ctx context.Context, limit int32) ([]Hold, error)
 	ListFailedUploadJobs(ctx context.Context, limit int32) ([]UploadJob, error)
+	ListFeeChargesByAccount(ctx context.Context, arg ListFeeChargesByAccountParams) ([]FeeCharge, error)
+	ListFeeChargesByRelatedTransaction(ctx context.Context, relatedTransactionID sql.NullInt32) ([]FeeCharge, error)
+	ListFeeIncomeAccounts(ctx context.Context) ([]FeeIncomeAccount, error)
+	ListFeeSchedules(ctx context.Context) ([]FeeSchedule, error)
 	ListFilesByMimeType(ctx context.Context, arg ListFilesByMimeTypeParams) ([]FileMetadatum, error)
+	ListFixedDepositTerms(ctx context.Context) ([]FixedDepositTerm, error)
+	ListFixedDepositsByLinkedAccount(ctx context.Context, arg ListFixedDepositsByLinkedAccountParams) ([]FixedDeposit, error)
+	ListHashChainCheckpoints(ctx context.Context, chainName string) ([]HashChainCheckpoint, error)
+	ListInterestAccruals(ctx context.Context, arg ListInterestAccrualsParams) ([]InterestAccrual, error)
+	// Accounts that earn interest on the given day and have not accrued it yet, with their end of day
+	// (UTC) ledger balance. Fixed deposits are left out, they earn their interest at maturity.
+	ListInterestBearingAccounts(ctx context.Context, accrualDate time.Time) ([]ListInterestBearingAccountsRow, error)
+	ListInterestSettings(ctx context.Context) ([]InterestSetting, error)
+	ListLedgerDiscrepancies(ctx context.Context, arg ListLedgerDiscrepanciesParams) ([]LedgerDiscrepancy, error)
+	// Active deposits whose maturity date is on or before the given day, oldest maturity first
+	ListMaturedFixedDeposits(ctx context.Context, arg ListMaturedFixedDepositsParams) ([]uuid.UUID, error)
+	ListOpenHoldsByAccount(ctx context.Context, accountID int32) ([]Hold, error)
+	ListPendingTransferBatchItems(ctx context.Context, batchID int32) ([]TransferBatchItem, error)
 	ListPendingUploadJobs(ctx context.Context, limit int32) ([]UploadJob, error)
 	ListProcessingUploadJobs(ctx context.Context, limit int32) ([]UploadJob, error)
+	ListReconciliationRuns(ctx context.Context, arg ListReconciliationRunsParams) ([]ReconciliationRun, error)
+	ListScheduledTransferExecutions(ctx context.Context, arg ListScheduledTransferExecutionsParams) ([]ScheduledTransferExecution, error)
+	ListScheduledTransfersByAccount(ctx context.Context, fromAccountID int32) ([]ScheduledTransfer, error)
+	// The entries booked on an account in [from_time, to_time) with the transaction behind each, in
+	// booking order. Entries written before transactions were linked have no transaction details.
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	ListStatementsByAccount(ctx context.Context, arg ListStatementsByAccountParams) ([]Statement, error)
 	ListTransactionStatus(ctx context.Context) ([]TransactionStatus, error)
 	ListTransactionTypes(ctx context.Context) ([]TransactionType, error)
 	ListTransactionsByAccount(ctx context.Context, arg ListTransactionsByAccountParams) ([]Transaction, error)
+	// Booked transactions lacking the debit entry on the sender or the credit entry on the receiver
+	ListTransactionsMissingEntries(ctx context.Context, accountIds []int32) ([]ListTransactionsMissingEntriesRow, error)
+	ListTransferBatchItems(ctx context.Context, batchID int32) ([]TransferBatchItem, error)
+	// Transactions between two accounts whose entries do not net to zero. The debit leg is converted at
+	// the entry's rate so cross-currency transfers compare in the credited currency, allowing for the
+	// rounding of the credit to half a minor unit of that currency. Deposits, interest and other flows
+	// with one side outside the ledger have a single entry and are only checked for missing entries.
+	ListUnbalancedTransactions(ctx context.Context, accountIds []int32) ([]ListUnbalancedTransactionsRow, error)
+	// Accounts with accruals left to post up to the end of a posting period
+	ListUnpostedInterestAccounts(ctx context.Context, arg ListUnpostedInterestAccountsParams) ([]int32, error)
+	ListUnpostedInterestAccrualsForUpdate(ctx context.Context, arg ListUnpostedInterestAccrualsForUpdateParams) ([]InterestAccrual, error)
 	ListUseUrploadJobs(ctx context.Context, arg ListUseUrploadJobsParams) ([]UploadJob, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) (int64, error)
+	MarkTransferBatchImported(ctx context.Context, arg MarkTransferBatchImportedParams) (TransferBatch, error)
 	ModifyTransactionStatus(ctx context.Context, arg ModifyTransactionStatusParams) (TransactionStatus, error)
+	// RecordTwoFactorFailure counts a wrong code. Failures from before window_start are forgotten, the
+	// count starts again at one.
+	RecordTwoFactorFailure(ctx context.Context, arg RecordTwoFactorFailureParams) (UserTwoFactor, error)
+	RefreshTransferBatchProgress(ctx context.Context, batchID int32) (TransferBatch, error)
+	RevokeAPIKey(ctx context.Context, apiKeyID int32) (ApiKey, error)
+	RevokeRefreshToken(ctx context.Context, arg RevokeRefreshTokenParams) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) (int64, error)
+	// Starts the next term on the maturity date with the principal grown by the interest of the last one
+	RollOverFixedDeposit(ctx context.Context, arg RollOverFixedDepositParams) (FixedDeposit, error)
+	// SeedCurrencies adds the currencies missing from the registry and corrects the minor units of
+	// the ones already there; names, symbols, rates and active flags set since are kept
+	SeedCurrencies(ctx context.Context, arg SeedCurrenciesParams) (int64, error)
+	SetAPIKeyExpiry(ctx context.Context, arg SetAPIKeyExpiryParams) error
+	SetFixedDepositPayout(ctx context.Context, arg SetFixedDepositPayoutParams) (FixedDeposit, error)
+	SetInterestAccrualResidue(ctx context.Context, arg SetInterestAccrualResidueParams) error
+	SettleTransaction(ctx context.Context, arg SettleTransactionParams) (Transaction, error)
+	// StartTwoFactorEnrollment stores a new secret for a user who has not enabled two-factor
+	// authentication, replacing the secret of an enrollment that was never confirmed
+	StartTwoFactorEnrollment(ctx context.Context, arg StartTwoFactorEnrollmentParams) (UserTwoFactor, error)
+	// SyncCurrentExchangeRate sets the current rate of a currency to the latest rate in its history that is already valid
+	SyncCurrentExchangeRate(ctx context.Context, currencyCode string) (int64, error)
+	TouchAPIKey(ctx context.Context, arg TouchAPIKeyParams) error
 	UpdateAccountBalance(ctx context.Context, arg UpdateAccountBalanceParams) (Account, error)
+	UpdateAccountHeldAmount(ctx context.Context, arg UpdateAccountHeldAmountParams) (Account, error)
+	// Sets the interest rate and overdraft limit of an account, a NULL keeps the current value
+	UpdateAccountTerms(ctx context.Context, arg UpdateAccountTermsParams) (Account, error)
 	UpdateAccountType(ctx context.Context, arg UpdateAccountTypeParams) (AccountType, error)
+	UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (AccountCurrency, error)
 	UpdateExchangeRate(ctx context.Context, arg UpdateExchangeRateParams) (AccountCurrency, error)
+	UpdateLastLogin(ctx context.Context, arg UpdateLastLoginParams) error
+	UpdateScheduledTransferRun(ctx context.Context, arg UpdateScheduledTransferRunParams) (ScheduledTransfer, error)
 	UpdateTransactionStatus(ctx context.Context, arg UpdateTransactionStatusParams) (Transaction, error)
 	UpdateTransactionType(ctx context.Context, arg UpdateTransactionTypeParams) (TransactionType, error)
+	UpdateTransferBatchItemResult(ctx context.Context, arg UpdateTransferBatchItemResultParams) error
 	UpdateUploadJobStatus(ctx context.Context, arg UpdateUploadJobStatusParams) (UploadJob, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpsertFeeIncomeAccount(ctx context.Context, arg UpsertFeeIncomeAccountParams) (FeeIncomeAccount, error)
+	UpsertFeeSchedule(ctx context.Context, arg UpsertFeeScheduleParams) (FeeSchedule, error)
+	UpsertFixedDepositTerm(ctx context.Context, arg UpsertFixedDepositTermParams) (FixedDepositTerm, error)
+	UpsertInterestSettings(ctx context.Context, arg UpsertInterestSettingsParams) (InterestSetting, error)
+	// UseRecoveryCode removes a recovery code as it is used, it does not work a second time
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (UserTwoFactor, error)
+	// UseTwoFactorStep accepts the code of a time step later than the last accepted one, two requests
+	// racing with the same code cannot both succeed
+	UseTwoFactorStep(ctx context.Context, arg UseTwoFactorStepParams) (UserTwoFactor, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/jackc/pgtype"
 	db "github.com/riad/banksystemendtoend/db/sqlc"
 	"github.com/riad/banksystemendtoend/util/common"
 	"github.com/stretchr/testify/require"
@@ -12,6 +13,11 @@ import (
 
 // - TestCreateAccount: Verifies random account creation
 func createRandomAccount(t *testing.T) db.Account {
+	return createRandomAccountWithCurrency(t, createRandomCurrency(t).CurrencyCode)
+}
+
+// createRandomAccountWithCurrency creates a random account holding the given currency
+func createRandomAccountWithCurrency(t *testing.T, currencyCode string) db.Account {
 	sqlStore := SetupTestStore(t)
 
 	var account db.Account
@@ -29,7 +35,7 @@ func createRandomAccount(t *testing.T) db.Account {
 			UserID:         createRandomUser(t).UserID,
 			AccountNumber:  common.RandomString(15),
 			AccountType:    createRandomAccountType(t).AccountType,
-			CurrencyCode:   createRandomCurrency(t).CurrencyCode,
+			CurrencyCode:   currencyCode,
 			InterestRate:   interestRate,
 			OverdraftLimit: overdraftLimit,
 		}
@@ -68,6 +74,18 @@ func TestGetAccount(t *testing.T) {
 	defer CleanupDB(t)
 }
 
+// - TestGetAccountByNumber: Ensures lookup by account number matches created account
+func TestGetAccountByNumber(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	account1 := createRandomAccount(t)
+
+	account2, err := sqlStore.Queries.GetAccountByNumber(context.Background(), account1.AccountNumber)
+	require.NoError(t, err)
+	require.Equal(t, account1, account2)
+
+	defer CleanupDB(t)
+}
+
 // - TestUpdateAccountBalance: Checks balance updates work correctly
 func TestUpdateAccountBalance(t *testing.T) {
 	sqlStore := SetupTestStore(t)
@@ -88,6 +106,26 @@ func TestUpdateAccountBalance(t *testing.T) {
 	defer CleanupDB(t)
 }
 
+func TestUpdateAccountTerms(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	account1 := createRandomAccount(t)
+
+	overdraftLimit := pgtype.Numeric{}
+	require.NoError(t, overdraftLimit.Set("250.00"))
+	account2, err := sqlStore.Queries.UpdateAccountTerms(context.Background(), db.UpdateAccountTermsParams{
+		InterestRate:   pgtype.Numeric{Status: pgtype.Null},
+		OverdraftLimit: overdraftLimit,
+		AccountID:      account1.AccountID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 250.0, common.NumericToFloat64(account2.OverdraftLimit))
+
+	//? A NULL keeps the current value
+	require.Equal(t, common.NumericToFloat64(account1.InterestRate), common.NumericToFloat64(account2.InterestRate))
+
+	defer CleanupDB(t)
+}
+
 // - TestDeleteAccount: Verifies soft delete (IsActive flag)
 func TestDeleteAccount(t *testing.T) {
 	sqlStore := SetupTestStore(t)
@@ -103,6 +141,42 @@ func TestDeleteAccount(t *testing.T) {
 	defer CleanupDB(t)
 }
 
+// - TestCloseAccount: Verifies accounts holding money stay open
+func TestCloseAccount(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	account := createRandomAccount(t)
+
+	deposit, err := common.SetNumeric("10.00")
+	require.NoError(t, err)
+	_, err = sqlStore.Queries.UpdateAccountBalance(context.Background(), db.UpdateAccountBalanceParams{
+		Amount:    deposit,
+		AccountID: account.AccountID,
+	})
+	require.NoError(t, err)
+
+	closed, err := sqlStore.Queries.CloseAccount(context.Background(), account.AccountID)
+	require.NoError(t, err)
+	require.Zero(t, closed)
+
+	withdrawal, err := common.SetNumeric("-10.00")
+	require.NoError(t, err)
+	_, err = sqlStore.Queries.UpdateAccountBalance(context.Background(), db.UpdateAccountBalanceParams{
+		Amount:    withdrawal,
+		AccountID: account.AccountID,
+	})
+	require.NoError(t, err)
+
+	closed, err = sqlStore.Queries.CloseAccount(context.Background(), account.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), closed)
+
+	fetched, err := sqlStore.Queries.GetAccount(context.Background(), account.AccountID)
+	require.NoError(t, err)
+	require.False(t, fetched.IsActive)
+
+	defer CleanupDB(t)
+}
+
 // - TestHardDeleteAccount: Confirms permanent account delet
 func TestHardDeleteAccount(t *testing.T) {
 	sqlStore := SetupTestStore(t)
@@ -78,27 +78,28 @@ func TestListAccountTypes(t *testing.T) {
 }
 
 // TestUpdateAccountType verifies the updating of an account type.
-// It creates an account type and then updates its type field with a new random value.
+// It creates an account type and then updates its description with a new random value.
 func TestUpdateAccountType(t *testing.T) {
 	sqlStore := SetupTestStore(t)
 
 	accountType1 := createRandomAccountType(t)
 
-	newType := common.RandomString(15)
-	for newType == accountType1.AccountType {
-		newType = common.RandomString(15)
+	newDescription := common.RandomString(20)
+	for newDescription == accountType1.Description {
+		newDescription = common.RandomString(20)
 	}
 
 	arg := db.UpdateAccountTypeParams{
-		AccountType:   newType,
-		AccountType_2: accountType1.AccountType,
+		AccountType: accountType1.AccountType,
+		Description: newDescription,
+		IsActive:    accountType1.IsActive,
 	}
 	accountType2, err := sqlStore.Queries.UpdateAccountType(context.Background(), arg)
 	require.NoError(t, err)
 	require.NotEmpty(t, accountType2)
 
-	require.Equal(t, arg.AccountType, accountType2.AccountType)
-	require.Equal(t, accountType1.Description, accountType2.Description)
+	require.Equal(t, accountType1.AccountType, accountType2.AccountType)
+	require.Equal(t, arg.Description, accountType2.Description)
 	require.Equal(t, accountType1.IsActive, accountType2.IsActive)
 
 	defer CleanupDB(t)
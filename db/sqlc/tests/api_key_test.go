@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"testing"
+	"time"
+
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/apikey"
+	"github.com/riad/banksystemendtoend/util/common"
+	"github.com/riad/banksystemendtoend/util/signing"
+	"github.com/stretchr/testify/require"
+)
+
+// !createRandomAPIKey => issues a random API key for a new user and validates the stored fields.
+func createRandomAPIKey(t *testing.T, expiresAt sql.NullTime) (string, db.ApiKey) {
+	sqlStore := SetupTestStore(t)
+	user := createRandomUser(t)
+
+	key, prefix, hash, err := apikey.Generate()
+	require.NoError(t, err)
+
+	arg := db.CreateAPIKeyParams{
+		KeyPrefix:   prefix,
+		KeyHash:     hash,
+		Name:        "test client",
+		OwnerUserID: user.UserID,
+		Scopes:      []string{apikey.ScopeReadOnly, apikey.ScopeTransfers},
+		ExpiresAt:   expiresAt,
+	}
+
+	apiKey, err := sqlStore.Queries.CreateAPIKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, apiKey)
+
+	require.Equal(t, arg.KeyPrefix, apiKey.KeyPrefix)
+	require.Equal(t, arg.OwnerUserID, apiKey.OwnerUserID)
+	require.Equal(t, arg.Scopes, apiKey.Scopes)
+	require.Equal(t, arg.ExpiresAt.Valid, apiKey.ExpiresAt.Valid)
+	require.False(t, apiKey.LastUsedAt.Valid)
+	require.False(t, apiKey.RevokedAt.Valid)
+	require.True(t, apikey.Matches(key, apiKey.KeyHash))
+	return key, apiKey
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	key, apiKey := createRandomAPIKey(t, sql.NullTime{})
+
+	prefix, ok := apikey.Prefix(key)
+	require.True(t, ok)
+	require.Equal(t, apiKey.KeyPrefix, prefix)
+	require.False(t, apikey.Matches(key+"x", apiKey.KeyHash))
+	defer CleanupDB(t)
+}
+
+func TestInvalidAPIKeyScopes(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	user := createRandomUser(t)
+
+	_, prefix, hash, err := apikey.Generate()
+	require.NoError(t, err)
+
+	_, err = sqlStore.Queries.CreateAPIKey(context.Background(), db.CreateAPIKeyParams{
+		KeyPrefix:   prefix,
+		KeyHash:     hash,
+		Name:        "test client",
+		OwnerUserID: user.UserID,
+		Scopes:      []string{"EVERYTHING"},
+	})
+	require.Error(t, err)
+	defer CleanupDB(t)
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	_, current := createRandomAPIKey(t, sql.NullTime{Time: time.Now().Add(30 * 24 * time.Hour), Valid: true})
+
+	_, prefix, hash, err := apikey.Generate()
+	require.NoError(t, err)
+	overlapEnd := time.Now().Add(time.Hour)
+
+	rotated, previous, err := transaction.RotateAPIKey(context.Background(), current.ApiKeyID, db.CreateAPIKeyParams{
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+	}, overlapEnd)
+	require.NoError(t, err)
+	require.Equal(t, current.OwnerUserID, rotated.OwnerUserID)
+	require.Equal(t, current.Name, rotated.Name)
+	require.Equal(t, current.Scopes, rotated.Scopes)
+	require.Equal(t, current.ApiKeyID, rotated.RotatedFrom.Int32)
+	//? Without an expiry of its own the new key is valid as long as the old key was issued for
+	require.WithinDuration(t, time.Now().Add(30*24*time.Hour), rotated.ExpiresAt.Time, time.Minute)
+
+	//? Both keys work until the overlap ends
+	require.WithinDuration(t, overlapEnd, previous.ExpiresAt.Time, time.Second)
+	stored, err := sqlStore.Queries.GetAPIKey(context.Background(), current.ApiKeyID)
+	require.NoError(t, err)
+	require.False(t, stored.RevokedAt.Valid)
+	require.WithinDuration(t, overlapEnd, stored.ExpiresAt.Time, time.Second)
+	defer CleanupDB(t)
+}
+
+func TestRotateRevokedAPIKey(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	_, current := createRandomAPIKey(t, sql.NullTime{})
+
+	revoked, err := sqlStore.Queries.RevokeAPIKey(context.Background(), current.ApiKeyID)
+	require.NoError(t, err)
+	require.True(t, revoked.RevokedAt.Valid)
+
+	//? A revoked key is not revoked again
+	_, err = sqlStore.Queries.RevokeAPIKey(context.Background(), current.ApiKeyID)
+	require.Error(t, err)
+
+	_, prefix, hash, err := apikey.Generate()
+	require.NoError(t, err)
+	_, _, err = transaction.RotateAPIKey(context.Background(), current.ApiKeyID, db.CreateAPIKeyParams{
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+	}, time.Now().Add(time.Hour))
+	require.ErrorIs(t, err, transaction.ErrAPIKeyInactive)
+	defer CleanupDB(t)
+}
+
+func TestRotateSignedAPIKey(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	user := createRandomUser(t)
+
+	_, prefix, hash, err := apikey.Generate()
+	require.NoError(t, err)
+	current, err := sqlStore.Queries.CreateAPIKey(context.Background(), db.CreateAPIKeyParams{
+		KeyPrefix:       prefix,
+		KeyHash:         hash,
+		Name:            "partner",
+		OwnerUserID:     user.UserID,
+		Scopes:          []string{apikey.ScopeTransfers},
+		SigningRequired: true,
+	})
+	require.NoError(t, err)
+	require.True(t, current.SigningRequired)
+
+	_, prefix, hash, err = apikey.Generate()
+	require.NoError(t, err)
+	rotated, _, err := transaction.RotateAPIKey(context.Background(), current.ApiKeyID, db.CreateAPIKeyParams{
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+	}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, rotated.SigningRequired)
+
+	//? The signing secret follows the key, the secret of the old key does not sign for the new one
+	signer, err := signing.NewSigner(common.RandomString(32), time.Minute)
+	require.NoError(t, err)
+	req := signing.Request{
+		Method:    "POST",
+		Path:      "/api/v1/transfers",
+		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		Nonce:     common.RandomString(24),
+		Body:      []byte(`{"amount":"10.00"}`),
+	}
+	require.NoError(t, signer.Verify(rotated.KeyPrefix, req, signing.Sign(signer.ClientSecret(rotated.KeyPrefix), req)))
+	require.ErrorIs(t, signer.Verify(rotated.KeyPrefix, req, signing.Sign(signer.ClientSecret(current.KeyPrefix), req)),
+		signing.ErrInvalidSignature)
+	defer CleanupDB(t)
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/common"
+	"github.com/stretchr/testify/require"
+)
+
+func listAuditTrail(t *testing.T, tableName, recordID string) []db.AuditTrail {
+	sqlStore := SetupTestStore(t)
+
+	records, err := sqlStore.ListAuditTrail(context.Background(), db.ListAuditTrailParams{
+		TableName: sql.NullString{String: tableName, Valid: true},
+		RecordID:  sql.NullString{String: recordID, Valid: true},
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	return records
+}
+
+func TestAuditTrailRecordsUserChanges(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+
+	user := createRandomUser(t)
+	recordID := strconv.Itoa(int(user.UserID))
+
+	actor := db.AuditActor{UserID: 42, IPAddress: "203.0.113.7"}
+	ctx := db.WithAuditActor(context.Background(), actor)
+	updated, err := sqlStore.UpdateUser(ctx, db.UpdateUserParams{
+		FirstName: sql.NullString{String: common.RandomFirstName(), Valid: true},
+		UserID:    user.UserID,
+	})
+	require.NoError(t, err)
+
+	records := listAuditTrail(t, "users", recordID)
+	require.Len(t, records, 2)
+
+	update, insert := records[0], records[1]
+	require.Equal(t, "INSERT", insert.Action)
+	require.False(t, insert.UserID.Valid)
+	require.False(t, insert.IpAddress.Valid)
+
+	require.Equal(t, "UPDATE", update.Action)
+	require.Equal(t, int32(actor.UserID), update.UserID.Int32)
+	require.Equal(t, actor.IPAddress, update.IpAddress.String)
+
+	var oldValues, newValues map[string]any
+	require.NoError(t, json.Unmarshal(update.OldValues.Bytes, &oldValues))
+	require.NoError(t, json.Unmarshal(update.NewValues.Bytes, &newValues))
+	require.Equal(t, user.FirstName.String, oldValues["first_name"])
+	require.Equal(t, updated.FirstName.String, newValues["first_name"])
+	require.NotContains(t, oldValues, "password_hash")
+	require.NotContains(t, newValues, "password_hash")
+
+	// The actor is cleared before the connection is reused
+	_, err = sqlStore.UpdateUser(context.Background(), db.UpdateUserParams{
+		LastName: sql.NullString{String: common.RandomLastName(), Valid: true},
+		UserID:   user.UserID,
+	})
+	require.NoError(t, err)
+	records = listAuditTrail(t, "users", recordID)
+	require.Len(t, records, 3)
+	require.False(t, records[0].UserID.Valid)
+	require.False(t, records[0].IpAddress.Valid)
+}
+
+func TestAuditTrailRecordsCodeKeyedTables(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+
+	accountType := createRandomAccountType(t)
+	require.NoError(t, sqlStore.HardDeleteAccountType(context.Background(), accountType.AccountType))
+
+	records := listAuditTrail(t, "account_types", accountType.AccountType)
+	require.Len(t, records, 2)
+	require.Equal(t, "DELETE", records[0].Action)
+	require.NotEmpty(t, records[0].OldValues.Bytes)
+	require.Nil(t, records[0].NewValues.Bytes)
+	require.Equal(t, "INSERT", records[1].Action)
+}
+
+func TestCreateAccessDeniedAudit(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	user := createRandomUser(t)
+
+	details := []byte(`{"method":"DELETE","path":"/api/v1/users/1/hard","reason":"missing_permission"}`)
+	err := sqlStore.CreateAccessDeniedAudit(context.Background(), db.CreateAccessDeniedAuditParams{
+		Route:     "DELETE /api/v1/users/:user_id/hard",
+		Details:   pgtype.JSONB{Bytes: details, Status: pgtype.Present},
+		UserID:    sql.NullInt32{Int32: user.UserID, Valid: true},
+		IpAddress: sql.NullString{String: "127.0.0.1", Valid: true},
+	})
+	require.NoError(t, err)
+
+	records := listAuditTrail(t, "access_control", "DELETE /api/v1/users/:user_id/hard")
+	require.Len(t, records, 1)
+	require.Equal(t, "DENIED", records[0].Action)
+	require.Equal(t, user.UserID, records[0].UserID.Int32)
+	require.JSONEq(t, string(details), string(records[0].NewValues.Bytes))
+	defer CleanupDB(t)
+}
+
+func TestListAuditTrailTimeRange(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+
+	user := createRandomUser(t)
+
+	records, err := sqlStore.ListAuditTrail(context.Background(), db.ListAuditTrailParams{
+		RecordID: sql.NullString{String: strconv.Itoa(int(user.UserID)), Valid: true},
+		FromTime: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+		Limit:    10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/hashchain"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedCurrencies(t *testing.T) {
+	defer CleanupDB(t)
+	sqlStore := SetupTestStore(t)
+
+	_, err := transaction.SeedCurrencies(context.Background())
+	require.NoError(t, err)
+
+	jpy, err := sqlStore.GetCurrency(context.Background(), iso4217.JPY)
+	require.NoError(t, err)
+	require.Equal(t, "Yen", jpy.CurrencyName)
+	require.Equal(t, int16(0), jpy.MinorUnits)
+	require.True(t, jpy.IsActive)
+	require.Equal(t, pgtype.Present, jpy.ExchangeRate.Status)
+
+	//? Only the default currencies are active, the others wait for a rate
+	bhd, err := sqlStore.GetCurrency(context.Background(), "BHD")
+	require.NoError(t, err)
+	require.Equal(t, int16(3), bhd.MinorUnits)
+	require.False(t, bhd.IsActive)
+	require.Equal(t, pgtype.Null, bhd.ExchangeRate.Status)
+
+	seeded, err := transaction.SeedCurrencies(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, seeded)
+
+	//? Minor units drifting from ISO 4217 are put back, the rest of the row is kept
+	_, err = sqlStore.UpdateCurrency(context.Background(), db.UpdateCurrencyParams{
+		CurrencyName: sql.NullString{String: "Bahrain Dinar", Valid: true},
+		MinorUnits:   sql.NullInt16{Int16: 2, Valid: true},
+		CurrencyCode: "BHD",
+	})
+	require.NoError(t, err)
+
+	seeded, err = transaction.SeedCurrencies(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), seeded)
+
+	bhd, err = sqlStore.GetCurrency(context.Background(), "BHD")
+	require.NoError(t, err)
+	require.Equal(t, int16(3), bhd.MinorUnits)
+	require.Equal(t, "Bahrain Dinar", bhd.CurrencyName)
+}
+
+func TestCreateCurrencyCodeRejectsUnknownCode(t *testing.T) {
+	defer CleanupDB(t)
+
+	_, err := transaction.CreateCurrencyCode("ZZZ")
+	require.ErrorIs(t, err, transaction.ErrUnknownCurrency)
+
+	jpy, err := transaction.CreateCurrencyCode(iso4217.JPY)
+	require.NoError(t, err)
+	require.Equal(t, iso4217.JPY, jpy.CurrencyCode)
+	require.Equal(t, int16(0), jpy.MinorUnits)
+}
+
+func TestTransferAmountPrecision(t *testing.T) {
+	defer CleanupDB(t)
+
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+	jpy, err := transaction.CreateCurrencyCode(iso4217.JPY)
+	require.NoError(t, err)
+	usd, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	yenAccount := createRandomAccountWithCurrency(t, jpy.CurrencyCode)
+	otherYenAccount := createRandomAccountWithCurrency(t, jpy.CurrencyCode)
+	dollarAccount := createRandomAccountWithCurrency(t, usd.CurrencyCode)
+
+	transfer := func(sender, receiver db.Account, value string) (schemas.TransferTxResult, error) {
+		amount := pgtype.Numeric{}
+		require.NoError(t, amount.Set(value))
+		return transaction.TransferTx(context.Background(), schemas.TransferTxParams{
+			SenderAccountID:   sender.AccountID,
+			ReceiverAccountID: receiver.AccountID,
+			Amount:            amount,
+			CurrencyCode:      sender.CurrencyCode,
+			TypeCode:          transferType.TypeCode,
+			StatusCode:        completedStatus.StatusCode,
+		})
+	}
+
+	//? Yen have no minor unit
+	_, err = transfer(yenAccount, otherYenAccount, "10.50")
+	require.ErrorIs(t, err, transaction.ErrAmountPrecision)
+	_, err = transfer(yenAccount, otherYenAccount, "10")
+	require.NoError(t, err)
+
+	//? 1 USD = 1 / 0.0067 JPY, so 10.00 USD credits 1492.54 JPY, rounded to 1493
+	result, err := transfer(dollarAccount, yenAccount, "10.00")
+	require.NoError(t, err)
+	var credit float64
+	require.NoError(t, result.ToEntry.Amount.AssignTo(&credit))
+	require.Equal(t, 1493.0, credit)
+}
+
+func TestTransferKeepsThreeDecimals(t *testing.T) {
+	defer CleanupDB(t)
+
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+	bhd, err := transaction.CreateCurrencyCode("BHD")
+	require.NoError(t, err)
+
+	sender := createRandomAccountWithCurrency(t, bhd.CurrencyCode)
+	receiver := createRandomAccountWithCurrency(t, bhd.CurrencyCode)
+
+	amount := pgtype.Numeric{}
+	require.NoError(t, amount.Set("10.125"))
+	result, err := transaction.TransferTx(context.Background(), schemas.TransferTxParams{
+		SenderAccountID:   sender.AccountID,
+		ReceiverAccountID: receiver.AccountID,
+		Amount:            amount,
+		CurrencyCode:      bhd.CurrencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        completedStatus.StatusCode,
+	})
+	require.NoError(t, err)
+
+	//? Fils are kept, not rounded to two places
+	var credit float64
+	require.NoError(t, result.ToEntry.Amount.AssignTo(&credit))
+	require.Equal(t, 10.125, credit)
+
+	verified, err := transaction.VerifyHashChain(context.Background(), hashchain.ChainEntries, nil)
+	require.NoError(t, err)
+	require.Nil(t, verified.Break)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgtype"
 	db "github.com/riad/banksystemendtoend/db/sqlc"
 	"github.com/riad/banksystemendtoend/util/common"
 	"github.com/stretchr/testify/require"
@@ -19,8 +20,9 @@ func createRandomEntry(t *testing.T) db.Entry {
 	require.NoError(t, err)
 
 	arg := db.CreateEntryParams{
-		AccountID: sql.NullInt32{Int32: createRandomAccount(t).AccountID, Valid: true},
-		Amount:    amount,
+		AccountID:    sql.NullInt32{Int32: createRandomAccount(t).AccountID, Valid: true},
+		Amount:       amount,
+		ExchangeRate: pgtype.Numeric{Status: pgtype.Null},
 	}
 
 	entry, err := sqlStore.Queries.CreateEntry(context.Background(), arg)
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/exchangerate"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRatesFile writes a rates file in the FileProvider format and returns a provider reading it
+func writeRatesFile(t *testing.T, rows ...string) *exchangerate.FileProvider {
+	path := filepath.Join(t.TempDir(), "rates.csv")
+	content := "currency_code,rate,valid_from\n"
+	for _, row := range rows {
+		content += row + "\n"
+	}
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return exchangerate.NewFileProvider(path)
+}
+
+func TestExchangeRateHistoryRecordsManualChanges(t *testing.T) {
+	defer CleanupDB(t)
+	sqlStore := SetupTestStore(t)
+
+	currency := createRandomCurrency(t)
+	rate := pgtype.Numeric{}
+	require.NoError(t, rate.Set("2.5"))
+	_, err := sqlStore.UpdateExchangeRate(context.Background(), db.UpdateExchangeRateParams{
+		CurrencyCode: currency.CurrencyCode,
+		ExchangeRate: rate,
+	})
+	require.NoError(t, err)
+
+	history, err := sqlStore.ListExchangeRateHistory(context.Background(), db.ListExchangeRateHistoryParams{
+		CurrencyCode: currency.CurrencyCode,
+		Limit:        10,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	var latest float64
+	require.NoError(t, history[0].Rate.AssignTo(&latest))
+	require.Equal(t, 2.5, latest)
+	require.Equal(t, "manual", history[0].Source)
+}
+
+func TestExchangeRateKeepsSmallRates(t *testing.T) {
+	defer CleanupDB(t)
+	sqlStore := SetupTestStore(t)
+	ctx := context.Background()
+
+	//? A rupiah is worth a tiny fraction of the base currency, its pair rate is above 10000
+	rupiah := createRandomCurrency(t)
+	base := createRandomCurrency(t)
+	for code, value := range map[string]string{rupiah.CurrencyCode: "0.0000612345", base.CurrencyCode: "1"} {
+		rate := pgtype.Numeric{}
+		require.NoError(t, rate.Set(value))
+		_, err := sqlStore.UpdateExchangeRate(ctx, db.UpdateExchangeRateParams{
+			CurrencyCode: code,
+			ExchangeRate: rate,
+		})
+		require.NoError(t, err)
+	}
+
+	stored, err := sqlStore.GetCurrency(ctx, rupiah.CurrencyCode)
+	require.NoError(t, err)
+	require.Equal(t, "0.0000612345", decimal.NewFromBigInt(stored.ExchangeRate.Int, stored.ExchangeRate.Exp).String())
+
+	rate, err := transaction.ExchangeRateAt(ctx, base.CurrencyCode, rupiah.CurrencyCode, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "16330.6632698887", rate.StringFixed(transaction.ExchangeRateScale))
+}
+
+func TestRecordExchangeRates(t *testing.T) {
+	defer CleanupDB(t)
+	ctx := context.Background()
+	sqlStore := SetupTestStore(t)
+
+	gbp, err := transaction.CreateCurrencyCode(iso4217.GBP)
+	require.NoError(t, err)
+	usd, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	at := func(offset time.Duration) string { return now.Add(offset).Format(time.RFC3339) }
+	provider := writeRatesFile(t,
+		fmt.Sprintf("%s,1,%s", usd.CurrencyCode, at(-48*time.Hour)),
+		fmt.Sprintf("%s,1.20,%s", gbp.CurrencyCode, at(-48*time.Hour)),
+		fmt.Sprintf("%s,1.30,%s", gbp.CurrencyCode, at(-24*time.Hour)),
+		fmt.Sprintf("%s,1.40,%s", gbp.CurrencyCode, at(24*time.Hour)),
+		"ZZZ,3",
+	)
+	rates, err := provider.FetchRates(ctx)
+	require.NoError(t, err)
+
+	result, err := transaction.RecordExchangeRates(ctx, provider.Name(), rates)
+	require.NoError(t, err)
+	require.Equal(t, 4, result.Recorded)
+	require.Equal(t, []string{"ZZZ"}, result.Skipped)
+	//? The rate set when the currency was created is newer than the fetched ones, the future one is not valid yet
+	require.Zero(t, result.Updated)
+
+	current, err := sqlStore.GetCurrency(ctx, gbp.CurrencyCode)
+	require.NoError(t, err)
+	require.Equal(t, gbp.ExchangeRate, current.ExchangeRate)
+
+	for offset, want := range map[time.Duration]float64{
+		-36 * time.Hour: 1.2,
+		-12 * time.Hour: 1.3,
+		48 * time.Hour:  1.4,
+	} {
+		rate, err := transaction.ExchangeRateAt(ctx, gbp.CurrencyCode, usd.CurrencyCode, now.Add(offset))
+		require.NoError(t, err)
+		require.Equal(t, want, rate.InexactFloat64(), offset)
+	}
+	_, err = transaction.ExchangeRateAt(ctx, gbp.CurrencyCode, usd.CurrencyCode, now.Add(-72*time.Hour))
+	require.ErrorIs(t, err, transaction.ErrExchangeRateUnavailable)
+
+	// Fetching the same rates again records nothing
+	again, err := transaction.RecordExchangeRates(ctx, provider.Name(), rates)
+	require.NoError(t, err)
+	require.Zero(t, again.Recorded)
+	require.Equal(t, 4, again.Unchanged)
+}
+
+func TestTransferAtHistoricalRate(t *testing.T) {
+	defer CleanupDB(t)
+	ctx := context.Background()
+
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+	gbp, err := transaction.CreateCurrencyCode(iso4217.GBP)
+	require.NoError(t, err)
+	usd, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	rateAt := time.Now().Add(-time.Hour)
+	_, err = transaction.RecordExchangeRates(ctx, "file", []exchangerate.Rate{
+		{CurrencyCode: usd.CurrencyCode, Rate: decimal.RequireFromString("1"), ValidFrom: rateAt.Add(-time.Hour)},
+		{CurrencyCode: gbp.CurrencyCode, Rate: decimal.RequireFromString("1.20"), ValidFrom: rateAt.Add(-time.Hour)},
+	})
+	require.NoError(t, err)
+
+	sender := createRandomAccountWithCurrency(t, gbp.CurrencyCode)
+	receiver := createRandomAccountWithCurrency(t, usd.CurrencyCode)
+	amount := pgtype.Numeric{}
+	require.NoError(t, amount.Set("10.00"))
+
+	//? 1 GBP = 1.20 USD an hour ago, 1.26 now
+	result, err := transaction.TransferTx(ctx, schemas.TransferTxParams{
+		SenderAccountID:   sender.AccountID,
+		ReceiverAccountID: receiver.AccountID,
+		Amount:            amount,
+		CurrencyCode:      gbp.CurrencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        completedStatus.StatusCode,
+		RateAt:            rateAt,
+	})
+	require.NoError(t, err)
+
+	var rate, converted float64
+	require.NoError(t, result.Transaction.ExchangeRate.AssignTo(&rate))
+	require.NoError(t, result.Transaction.ConvertedAmount.AssignTo(&converted))
+	require.Equal(t, 1.2, rate)
+	require.Equal(t, 12.0, converted)
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// createFeeSchedule charges the accounts of the account type with the given fees
+func createFeeSchedule(t *testing.T, accountType, flat, percent, maintenance string) db.FeeSchedule {
+	sqlStore := SetupTestStore(t)
+
+	arg := db.UpsertFeeScheduleParams{AccountType: accountType, IsActive: true}
+	require.NoError(t, arg.TransferFeeFlat.Set(flat))
+	require.NoError(t, arg.TransferFeePercent.Set(percent))
+	require.NoError(t, arg.FxMarkupPercent.Set("0"))
+	require.NoError(t, arg.MonthlyMaintenanceFee.Set(maintenance))
+	require.NoError(t, arg.OverdraftFee.Set("0"))
+
+	schedule, err := sqlStore.Queries.UpsertFeeSchedule(context.Background(), arg)
+	require.NoError(t, err)
+	return schedule
+}
+
+// createFeeIncomeAccount points the fees of the currency at a new account
+func createFeeIncomeAccount(t *testing.T, currencyCode string) db.Account {
+	sqlStore := SetupTestStore(t)
+	account := createRandomAccountWithCurrency(t, currencyCode)
+
+	_, err := sqlStore.Queries.UpsertFeeIncomeAccount(context.Background(), db.UpsertFeeIncomeAccountParams{
+		CurrencyCode: currencyCode,
+		AccountID:    account.AccountID,
+	})
+	require.NoError(t, err)
+	return account
+}
+
+func TestTransferFees(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	sender := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	receiver := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	income := createFeeIncomeAccount(t, currency.CurrencyCode)
+	createFeeSchedule(t, sender.AccountType, "1.50", "1", "0")
+
+	amount := pgtype.Numeric{}
+	require.NoError(t, amount.Set("10.00"))
+	arg := schemas.TransferTxParams{
+		SenderAccountID:   sender.AccountID,
+		ReceiverAccountID: receiver.AccountID,
+		Amount:            amount,
+		CurrencyCode:      currency.CurrencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        completedStatus.StatusCode,
+	}
+
+	// 1.50 flat plus 1% of 10.00
+	fee := decimal.RequireFromString("1.60")
+	preview, err := transaction.PreviewTransferFees(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, preview.Fees, 1)
+	require.Equal(t, db.FeeTypeTRANSFER, preview.Fees[0].Type)
+	require.True(t, fee.Equal(preview.TotalFees), "expected %s, got %s", fee, preview.TotalFees)
+	require.True(t, decimal.RequireFromString("11.60").Equal(preview.TotalDebit))
+
+	result, err := transaction.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Fees, 1)
+
+	charge := result.Fees[0]
+	require.Equal(t, db.FeeTypeTRANSFER, charge.FeeType)
+	require.Equal(t, sender.AccountID, charge.AccountID)
+	require.Equal(t, result.Transaction.TransactionID, charge.RelatedTransactionID.Int32)
+	require.True(t, fee.Equal(numericDecimal(charge.Amount)))
+
+	feeTransaction, err := sqlStore.Queries.GetTransaction(context.Background(), charge.TransactionID)
+	require.NoError(t, err)
+	require.Equal(t, config.TransactionTypes.FEE, feeTransaction.TypeCode)
+	require.Equal(t, sender.AccountID, feeTransaction.FromAccountID.Int32)
+	require.Equal(t, income.AccountID, feeTransaction.ToAccountID.Int32)
+
+	// The sender paid the amount and the fee, the income account collected the fee
+	expectedSender := numericDecimal(sender.Balance).Sub(preview.TotalDebit)
+	require.True(t, expectedSender.Equal(numericDecimal(result.FromAccount.Balance)))
+	collected, err := sqlStore.Queries.GetAccount(context.Background(), income.AccountID)
+	require.NoError(t, err)
+	require.True(t, numericDecimal(income.Balance).Add(fee).Equal(numericDecimal(collected.Balance)))
+
+	charges, err := sqlStore.Queries.ListFeeChargesByRelatedTransaction(context.Background(), charge.RelatedTransactionID)
+	require.NoError(t, err)
+	require.Len(t, charges, 1)
+
+	reconciliation, err := transaction.RunReconciliation(context.Background(),
+		[]int32{sender.AccountID, receiver.AccountID, income.AccountID})
+	require.NoError(t, err)
+	require.Zero(t, reconciliation.Run.DiscrepancyCount)
+}
+
+func TestMaintenanceFee(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+
+	_, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	account := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	createFeeIncomeAccount(t, currency.CurrencyCode)
+	createFeeSchedule(t, account.AccountType, "0", "0", "5.00")
+
+	// A second run in the same month charges nothing new
+	today := interest.Date(time.Now())
+	for i := 0; i < 2; i++ {
+		_, err := transaction.RunPeriodicFees(context.Background(), today)
+		require.NoError(t, err)
+	}
+
+	charges, err := sqlStore.Queries.ListFeeChargesByAccount(context.Background(), db.ListFeeChargesByAccountParams{
+		AccountID: account.AccountID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, charges, 1)
+	require.Equal(t, db.FeeTypeMAINTENANCE, charges[0].FeeType)
+	require.Equal(t, today.AddDate(0, 0, 1-today.Day()), charges[0].PeriodStart.Time.UTC())
+	require.True(t, decimal.RequireFromString("5.00").Equal(numericDecimal(charges[0].Amount)))
+
+	charged, err := sqlStore.Queries.GetAccount(context.Background(), account.AccountID)
+	require.NoError(t, err)
+	require.True(t, numericDecimal(account.Balance).Sub(decimal.RequireFromString("5.00")).Equal(numericDecimal(charged.Balance)))
+}
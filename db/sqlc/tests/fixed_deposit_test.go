@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/common"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// offerFixedDepositTerm configures a term with the given rates, in percent
+func offerFixedDepositTerm(t *testing.T, termMonths int32, interestRate, penalty string, active bool) db.FixedDepositTerm {
+	sqlStore := SetupTestStore(t)
+	arg := db.UpsertFixedDepositTermParams{TermMonths: termMonths, IsActive: active}
+	require.NoError(t, arg.InterestRate.Set(interestRate))
+	require.NoError(t, arg.EarlyBreakPenaltyPercent.Set(penalty))
+
+	term, err := sqlStore.Queries.UpsertFixedDepositTerm(context.Background(), arg)
+	require.NoError(t, err)
+	return term
+}
+
+// openFixedDeposit moves principal from the linked account into a new 12 month deposit earning 5%,
+// with a 10% early break penalty
+func openFixedDeposit(t *testing.T, linked db.Account, principal string) schemas.FixedDepositTxResult {
+	offerFixedDepositTerm(t, 12, "5.00", "10.00", true)
+	arg := schemas.OpenFixedDepositParams{
+		LinkedAccountID:     linked.AccountID,
+		AccountNumber:       common.RandomString(15),
+		TermMonths:          12,
+		MaturityInstruction: db.MaturityInstructionPAYOUT,
+	}
+	require.NoError(t, arg.Principal.Set(principal))
+
+	result, err := transaction.OpenFixedDeposit(context.Background(), arg)
+	require.NoError(t, err)
+	return result
+}
+
+func TestOpenFixedDeposit(t *testing.T) {
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+
+	linked := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	result := openFixedDeposit(t, linked, "5.00")
+
+	deposit := result.Deposit
+	require.Equal(t, db.FixedDepositStatusACTIVE, deposit.Status)
+	require.Equal(t, linked.AccountID, deposit.LinkedAccountID)
+	require.Equal(t, result.Account.AccountID, deposit.AccountID)
+	require.Equal(t, interest.AddMonths(deposit.StartDate, 12), deposit.MaturityDate)
+	require.Equal(t, config.AccountTypes.FIXED_DEPOSIT, result.Account.AccountType)
+	require.Equal(t, linked.UserID, result.Account.UserID)
+	require.Equal(t, config.TransactionTypes.DEPOSIT, result.Transaction.TypeCode)
+
+	require.InDelta(t, 5.0, numericFloat(t, result.Account.Balance), 0.001)
+	require.InDelta(t, numericFloat(t, linked.Balance)-5.0, numericFloat(t, result.LinkedAccount.Balance), 0.001)
+
+	// The principal stays put until the deposit matures
+	amount := pgtype.Numeric{}
+	require.NoError(t, amount.Set("1.00"))
+	_, err = transaction.TransferTx(context.Background(), schemas.TransferTxParams{
+		SenderAccountID:   result.Account.AccountID,
+		ReceiverAccountID: linked.AccountID,
+		Amount:            amount,
+		CurrencyCode:      currency.CurrencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        config.TransactionStatuses.COMPLETED,
+	})
+	require.ErrorIs(t, err, transaction.ErrFixedDepositLocked)
+
+	//? The rates come from the term, not from whoever opens the deposit
+	require.InDelta(t, 5.0, numericFloat(t, deposit.InterestRate), 0.001)
+	require.InDelta(t, 10.0, numericFloat(t, deposit.EarlyBreakPenaltyPercent), 0.001)
+}
+
+func TestOpenFixedDepositTermNotOffered(t *testing.T) {
+	defer CleanupDB(t)
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+	linked := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+
+	arg := schemas.OpenFixedDepositParams{
+		LinkedAccountID:     linked.AccountID,
+		AccountNumber:       common.RandomString(15),
+		TermMonths:          7,
+		MaturityInstruction: db.MaturityInstructionPAYOUT,
+	}
+	require.NoError(t, arg.Principal.Set("5.00"))
+
+	_, err = transaction.OpenFixedDeposit(context.Background(), arg)
+	require.ErrorIs(t, err, transaction.ErrFixedDepositTerm)
+
+	//? A withdrawn term is refused as well
+	offerFixedDepositTerm(t, 7, "3.00", "1.00", false)
+	_, err = transaction.OpenFixedDeposit(context.Background(), arg)
+	require.ErrorIs(t, err, transaction.ErrFixedDepositTerm)
+}
+
+func TestBreakFixedDeposit(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	linked := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	income := createFeeIncomeAccount(t, currency.CurrencyCode)
+	opened := openFixedDeposit(t, linked, "5.00")
+
+	result, err := transaction.BreakFixedDeposit(context.Background(), opened.Deposit.DepositNumber)
+	require.NoError(t, err)
+	require.Equal(t, db.FixedDepositStatusBROKEN, result.Deposit.Status)
+	require.True(t, result.Deposit.ClosedAt.Valid)
+	require.Nil(t, result.Interest)
+
+	// 10% of the principal is kept as the penalty, the rest goes back to the linked account
+	require.NotNil(t, result.Penalty)
+	require.Equal(t, db.FeeTypeEARLYBREAK, result.Penalty.FeeType)
+	require.True(t, decimal.RequireFromString("0.50").Equal(numericDecimal(result.Penalty.Amount)))
+	require.Equal(t, result.Transaction.TransactionID, result.Deposit.PayoutTransactionID.Int32)
+
+	require.InDelta(t, 0.0, numericFloat(t, result.Account.Balance), 0.001)
+	require.False(t, result.Account.IsActive)
+	require.InDelta(t, numericFloat(t, linked.Balance)-0.5, numericFloat(t, result.LinkedAccount.Balance), 0.001)
+
+	collected, err := sqlStore.Queries.GetAccount(context.Background(), income.AccountID)
+	require.NoError(t, err)
+	require.True(t, numericDecimal(income.Balance).Add(decimal.RequireFromString("0.50")).Equal(numericDecimal(collected.Balance)))
+
+	_, err = transaction.BreakFixedDeposit(context.Background(), opened.Deposit.DepositNumber)
+	require.ErrorIs(t, err, transaction.ErrFixedDepositNotActive)
+
+	reconciliation, err := transaction.RunReconciliation(context.Background(),
+		[]int32{linked.AccountID, opened.Account.AccountID, income.AccountID})
+	require.NoError(t, err)
+	require.Zero(t, reconciliation.Run.DiscrepancyCount)
+}
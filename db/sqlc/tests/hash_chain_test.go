@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"testing"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/hashchain"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T, seed byte) *hashchain.Signer {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = seed
+	}
+	t.Setenv(hashchain.CheckpointKeyEnv, base64.StdEncoding.EncodeToString(key))
+	signer, err := hashchain.SignerFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, signer)
+	return signer
+}
+
+func TestVerifyHashChain(t *testing.T) {
+	defer CleanupDB(t)
+	createRandomTransfer(t, "10.00")
+
+	for _, chain := range hashchain.Chains {
+		result, err := transaction.VerifyHashChain(context.Background(), chain, nil)
+		require.NoError(t, err)
+		require.Nil(t, result.Break, chain)
+		require.NotZero(t, result.LinksChecked)
+		require.Equal(t, result.Head.LastSeq, result.LinksChecked)
+	}
+}
+
+func TestHashChainRefusesChanges(t *testing.T) {
+	defer CleanupDB(t)
+	sqlStore := SetupTestStore(t)
+	transfer := createRandomTransfer(t, "10.00")
+
+	_, err := sqlStore.Pool.Exec(context.Background(),
+		"UPDATE entries SET amount = amount * 2 WHERE id = $1", transfer.FromEntry.ID)
+	require.ErrorContains(t, err, "hash chain")
+
+	_, err = sqlStore.Pool.Exec(context.Background(), "DELETE FROM entries WHERE id = $1", transfer.FromEntry.ID)
+	require.ErrorContains(t, err, "hash chain")
+	//? The API maps restrict violations to a conflict
+	require.ErrorContains(t, err, "SQLSTATE 23001")
+}
+
+func TestVerifyHashChainFindsChangedRow(t *testing.T) {
+	defer CleanupDB(t)
+	sqlStore := SetupTestStore(t)
+	transfer := createRandomTransfer(t, "10.00")
+
+	// Tamper the way only a table owner could, around the trigger that refuses changes
+	_, err := sqlStore.Pool.Exec(context.Background(), "ALTER TABLE entries DISABLE TRIGGER protect_entries_chain")
+	require.NoError(t, err)
+	defer func() {
+		_, err := sqlStore.Pool.Exec(context.Background(), "ALTER TABLE entries ENABLE TRIGGER protect_entries_chain")
+		require.NoError(t, err)
+	}()
+	_, err = sqlStore.Pool.Exec(context.Background(),
+		"UPDATE entries SET amount = amount * 2 WHERE id = $1", transfer.ToEntry.ID)
+	require.NoError(t, err)
+
+	result, err := transaction.VerifyHashChain(context.Background(), hashchain.ChainEntries, nil)
+	require.NoError(t, err)
+	require.NotNil(t, result.Break)
+	require.Equal(t, strconv.FormatInt(transfer.ToEntry.ID, 10), result.Break.RecordID)
+	require.Contains(t, result.Break.Reason, "row_hash")
+}
+
+func TestHashChainCheckpoints(t *testing.T) {
+	defer CleanupDB(t)
+	createRandomTransfer(t, "10.00")
+	signer := newTestSigner(t, 7)
+
+	checkpoints, err := transaction.CreateHashChainCheckpoints(context.Background(), signer)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, len(hashchain.Chains))
+
+	// Nothing moved, so nothing new is signed
+	again, err := transaction.CreateHashChainCheckpoints(context.Background(), signer)
+	require.NoError(t, err)
+	require.Empty(t, again)
+
+	result, err := transaction.VerifyHashChain(context.Background(), hashchain.ChainEntries, signer.PublicKey())
+	require.NoError(t, err)
+	require.Nil(t, result.Break)
+	require.Equal(t, 1, result.CheckpointsChecked)
+
+	other := newTestSigner(t, 9)
+	result, err = transaction.VerifyHashChain(context.Background(), hashchain.ChainEntries, other.PublicKey())
+	require.NoError(t, err)
+	require.NotNil(t, result.Break)
+	require.Contains(t, result.Break.Reason, "invalid signature")
+}
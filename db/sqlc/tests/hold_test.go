@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/api/utils"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/stretchr/testify/require"
+)
+
+// createRandomHold authorizes the amount from a new account to a new merchant account
+func createRandomHold(t *testing.T, amount string, expiresAt time.Time) schemas.HoldTxResult {
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	account := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	merchant := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+
+	holdAmount := pgtype.Numeric{}
+	require.NoError(t, holdAmount.Set(amount))
+
+	result, err := transaction.AuthorizeHold(context.Background(), schemas.AuthorizeHoldParams{
+		AccountID:         account.AccountID,
+		MerchantAccountID: merchant.AccountID,
+		Amount:            holdAmount,
+		CurrencyCode:      currency.CurrencyCode,
+		Description:       "card authorization",
+		ExpiresAt:         expiresAt,
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func numericFloat(t *testing.T, num pgtype.Numeric) float64 {
+	var value float64
+	require.NoError(t, num.AssignTo(&value))
+	return value
+}
+
+func TestAuthorizeHold(t *testing.T) {
+	result := createRandomHold(t, "5.00", time.Now().Add(time.Hour))
+
+	require.Equal(t, db.HoldStatusOPEN, result.Hold.Status)
+	require.Equal(t, config.TransactionStatuses.PENDING, result.Transaction.StatusCode)
+	require.Equal(t, config.TransactionTypes.PAYMENT, result.Transaction.TypeCode)
+
+	//? Funds are reserved but the ledger balance does not move
+	require.InDelta(t, 5.0, numericFloat(t, result.Account.HeldAmount), 0.001)
+	require.InDelta(t, 0.0, numericFloat(t, result.Account.Balance), 0.001)
+
+	defer CleanupDB(t)
+}
+
+func TestAuthorizeHoldExceedsAvailable(t *testing.T) {
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+	account := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	merchant := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+
+	//? One cent more than the overdraft limit allows
+	holdAmount := pgtype.Numeric{}
+	require.NoError(t, holdAmount.Set(numericFloat(t, account.OverdraftLimit)+0.01))
+
+	_, err = transaction.AuthorizeHold(context.Background(), schemas.AuthorizeHoldParams{
+		AccountID:         account.AccountID,
+		MerchantAccountID: merchant.AccountID,
+		Amount:            holdAmount,
+		CurrencyCode:      currency.CurrencyCode,
+		ExpiresAt:         time.Now().Add(time.Hour),
+	})
+	require.Error(t, err)
+	require.True(t, utils.IsCheckViolationError(err))
+
+	defer CleanupDB(t)
+}
+
+func TestCaptureHoldPartial(t *testing.T) {
+	hold := createRandomHold(t, "5.00", time.Now().Add(time.Hour))
+
+	captureAmount := pgtype.Numeric{}
+	require.NoError(t, captureAmount.Set("3.50"))
+
+	result, err := transaction.CaptureHold(context.Background(), schemas.CaptureHoldParams{
+		HoldNumber: hold.Hold.HoldNumber,
+		Amount:     captureAmount,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, db.HoldStatusCAPTURED, result.Hold.Status)
+	require.InDelta(t, 3.5, numericFloat(t, result.Hold.CapturedAmount), 0.001)
+	require.Equal(t, config.TransactionStatuses.COMPLETED, result.Transaction.StatusCode)
+	require.Equal(t, hold.Transaction.TransactionID, result.Transaction.TransactionID)
+
+	//? The rest of the reservation is released and only the captured amount moves
+	require.InDelta(t, 0.0, numericFloat(t, result.Account.HeldAmount), 0.001)
+	require.InDelta(t, -3.5, numericFloat(t, result.Account.Balance), 0.001)
+	require.InDelta(t, 3.5, numericFloat(t, result.MerchantAccount.Balance), 0.001)
+	require.InDelta(t, -3.5, numericFloat(t, result.FromEntry.Amount), 0.001)
+
+	//? A hold is captured only once
+	_, err = transaction.CaptureHold(context.Background(), schemas.CaptureHoldParams{
+		HoldNumber: hold.Hold.HoldNumber,
+	})
+	require.ErrorIs(t, err, transaction.ErrHoldNotOpen)
+
+	defer CleanupDB(t)
+}
+
+func TestCaptureHoldExceedsHold(t *testing.T) {
+	hold := createRandomHold(t, "5.00", time.Now().Add(time.Hour))
+
+	captureAmount := pgtype.Numeric{}
+	require.NoError(t, captureAmount.Set("5.01"))
+
+	_, err := transaction.CaptureHold(context.Background(), schemas.CaptureHoldParams{
+		HoldNumber: hold.Hold.HoldNumber,
+		Amount:     captureAmount,
+	})
+	require.ErrorIs(t, err, transaction.ErrCaptureExceedsHold)
+
+	defer CleanupDB(t)
+}
+
+func TestReleaseHold(t *testing.T) {
+	hold := createRandomHold(t, "5.00", time.Now().Add(time.Hour))
+
+	result, err := transaction.ReleaseHold(context.Background(), hold.Hold.HoldNumber)
+	require.NoError(t, err)
+
+	require.Equal(t, db.HoldStatusRELEASED, result.Hold.Status)
+	require.Equal(t, config.TransactionStatuses.CANCELLED, result.Transaction.StatusCode)
+	require.InDelta(t, 0.0, numericFloat(t, result.Account.HeldAmount), 0.001)
+	require.InDelta(t, 0.0, numericFloat(t, result.Account.Balance), 0.001)
+
+	defer CleanupDB(t)
+}
+
+func TestExpireHolds(t *testing.T) {
+	expired := createRandomHold(t, "5.00", time.Now().Add(-time.Minute))
+	open := createRandomHold(t, "5.00", time.Now().Add(time.Hour))
+
+	count, err := transaction.ExpireHolds(context.Background(), 100)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	sqlStore := SetupTestStore(t)
+	hold, err := sqlStore.Queries.GetHold(context.Background(), expired.Hold.HoldNumber)
+	require.NoError(t, err)
+	require.Equal(t, db.HoldStatusEXPIRED, hold.Status)
+
+	hold, err = sqlStore.Queries.GetHold(context.Background(), open.Hold.HoldNumber)
+	require.NoError(t, err)
+	require.Equal(t, db.HoldStatusOPEN, hold.Status)
+
+	//? An expired hold can no longer be captured
+	_, err = transaction.CaptureHold(context.Background(), schemas.CaptureHoldParams{
+		HoldNumber: expired.Hold.HoldNumber,
+	})
+	require.ErrorIs(t, err, transaction.ErrHoldNotOpen)
+
+	defer CleanupDB(t)
+}
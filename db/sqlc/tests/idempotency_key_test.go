@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/common"
+	"github.com/stretchr/testify/require"
+)
+
+// !createRandomIdempotencyKey => reserves a random idempotency key and validates the stored fields.
+func createRandomIdempotencyKey(t *testing.T) db.IdempotencyKey {
+	sqlStore := SetupTestStore(t)
+
+	arg := db.CreateIdempotencyKeyParams{
+		ClientID:       "apikey:" + common.RandomString(20),
+		IdempotencyKey: common.RandomString(32),
+		RequestMethod:  "POST",
+		RequestPath:    "/api/v1/transfers",
+		RequestHash:    common.RandomString(64),
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+
+	key, err := sqlStore.Queries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, key)
+
+	require.Equal(t, arg.ClientID, key.ClientID)
+	require.Equal(t, arg.IdempotencyKey, key.IdempotencyKey)
+	require.Equal(t, arg.RequestHash, key.RequestHash)
+	require.False(t, key.ResponseStatus.Valid)
+	require.False(t, key.CompletedAt.Valid)
+	require.WithinDuration(t, arg.ExpiresAt, key.ExpiresAt, time.Second)
+	return key
+}
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	createRandomIdempotencyKey(t)
+	defer CleanupDB(t)
+}
+
+func TestCreateIdempotencyKeyConflict(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	key := createRandomIdempotencyKey(t)
+
+	//? A second reservation of the same key returns no row
+	_, err := sqlStore.Queries.CreateIdempotencyKey(context.Background(), db.CreateIdempotencyKeyParams{
+		ClientID:       key.ClientID,
+		IdempotencyKey: key.IdempotencyKey,
+		RequestMethod:  key.RequestMethod,
+		RequestPath:    key.RequestPath,
+		RequestHash:    key.RequestHash,
+		ExpiresAt:      key.ExpiresAt,
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	defer CleanupDB(t)
+}
+
+func TestCompleteIdempotencyKey(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	key := createRandomIdempotencyKey(t)
+
+	body := []byte(`{"data":{"transaction_id":1}}`)
+	completed, err := sqlStore.Queries.CompleteIdempotencyKey(context.Background(), db.CompleteIdempotencyKeyParams{
+		ResponseStatus: sql.NullInt32{Int32: 201, Valid: true},
+		ResponseBody:   pgtype.JSONB{Bytes: body, Status: pgtype.Present},
+		ClientID:       key.ClientID,
+		IdempotencyKey: key.IdempotencyKey,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(201), completed.ResponseStatus.Int32)
+	require.JSONEq(t, string(body), string(completed.ResponseBody.Bytes))
+	require.True(t, completed.CompletedAt.Valid)
+
+	fetched, err := sqlStore.Queries.GetIdempotencyKey(context.Background(), db.GetIdempotencyKeyParams{
+		ClientID:       key.ClientID,
+		IdempotencyKey: key.IdempotencyKey,
+	})
+	require.NoError(t, err)
+	require.Equal(t, completed.ID, fetched.ID)
+	defer CleanupDB(t)
+}
+
+func TestDeleteIdempotencyKey(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	key := createRandomIdempotencyKey(t)
+
+	err := sqlStore.Queries.DeleteIdempotencyKey(context.Background(), db.DeleteIdempotencyKeyParams{
+		ClientID:       key.ClientID,
+		IdempotencyKey: key.IdempotencyKey,
+	})
+	require.NoError(t, err)
+
+	_, err = sqlStore.Queries.GetIdempotencyKey(context.Background(), db.GetIdempotencyKeyParams{
+		ClientID:       key.ClientID,
+		IdempotencyKey: key.IdempotencyKey,
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	defer CleanupDB(t)
+}
+
+func TestDeleteStaleIdempotencyKey(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	key := createRandomIdempotencyKey(t)
+
+	arg := db.DeleteStaleIdempotencyKeyParams{
+		ClientID:       key.ClientID,
+		IdempotencyKey: key.IdempotencyKey,
+		CreatedBefore:  key.CreatedAt.Add(-time.Minute),
+	}
+	//? A key reserved after the cutoff belongs to a request that may still be running
+	deleted, err := sqlStore.Queries.DeleteStaleIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+
+	arg.CreatedBefore = key.CreatedAt.Add(time.Minute)
+	deleted, err = sqlStore.Queries.DeleteStaleIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	//? Completed keys are never taken over
+	completed := createRandomIdempotencyKey(t)
+	_, err = sqlStore.Queries.CompleteIdempotencyKey(context.Background(), db.CompleteIdempotencyKeyParams{
+		ResponseStatus: sql.NullInt32{Int32: 201, Valid: true},
+		ResponseBody:   pgtype.JSONB{Status: pgtype.Null},
+		ClientID:       completed.ClientID,
+		IdempotencyKey: completed.IdempotencyKey,
+	})
+	require.NoError(t, err)
+	deleted, err = sqlStore.Queries.DeleteStaleIdempotencyKey(context.Background(), db.DeleteStaleIdempotencyKeyParams{
+		ClientID:       completed.ClientID,
+		IdempotencyKey: completed.IdempotencyKey,
+		CreatedBefore:  completed.CreatedAt.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+	defer CleanupDB(t)
+}
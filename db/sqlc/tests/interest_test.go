@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func numericDecimal(num pgtype.Numeric) decimal.Decimal {
+	return decimal.NewFromBigInt(num.Int, num.Exp)
+}
+
+func TestAccrueAndPostInterest(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	transfer := createRandomTransfer(t, "1000.00")
+	account := transfer.ToAccount
+
+	_, err := sqlStore.Queries.UpsertInterestSettings(context.Background(), db.UpsertInterestSettingsParams{
+		AccountType:        account.AccountType,
+		DayCountConvention: db.DayCountConventionACT365,
+		PostingPeriod:      db.InterestPostingPeriodMONTHLY,
+		IsActive:           true,
+	})
+	require.NoError(t, err)
+
+	// Accruing the same day twice records it once
+	today := interest.Date(time.Now())
+	for i := 0; i < 2; i++ {
+		_, err = transaction.AccrueInterest(context.Background(), today)
+		require.NoError(t, err)
+	}
+
+	accruals, err := sqlStore.Queries.ListInterestAccruals(context.Background(), db.ListInterestAccrualsParams{
+		AccountID: account.AccountID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+
+	accrual := accruals[0]
+	require.Equal(t, today, accrual.AccrualDate.UTC())
+	require.Equal(t, 1000.0, numericDecimal(accrual.Balance).InexactFloat64())
+	expected := decimal.NewFromInt(1000).Mul(numericDecimal(account.InterestRate)).
+		Div(decimal.NewFromInt(100)).Div(decimal.NewFromInt(365)).RoundBank(interest.AccrualScale)
+	require.True(t, expected.Equal(numericDecimal(accrual.Amount)), "expected %s, got %s", expected, numericDecimal(accrual.Amount))
+	require.False(t, accrual.TransactionID.Valid)
+
+	// Nothing is posted before the month ends
+	_, err = transaction.PostInterest(context.Background(), today.AddDate(0, 0, -today.Day()))
+	require.NoError(t, err)
+	accruals, err = sqlStore.Queries.ListInterestAccruals(context.Background(), db.ListInterestAccrualsParams{
+		AccountID: account.AccountID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.False(t, accruals[0].TransactionID.Valid)
+
+	monthEnd := time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		_, err = transaction.PostInterest(context.Background(), monthEnd)
+		require.NoError(t, err)
+	}
+
+	accruals, err = sqlStore.Queries.ListInterestAccruals(context.Background(), db.ListInterestAccrualsParams{
+		AccountID: account.AccountID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.True(t, accruals[0].TransactionID.Valid)
+	require.True(t, accruals[0].PostedAt.Valid)
+
+	posting, err := sqlStore.Queries.GetTransaction(context.Background(), accruals[0].TransactionID.Int32)
+	require.NoError(t, err)
+	require.Equal(t, config.TransactionTypes.INTEREST, posting.TypeCode)
+	require.Equal(t, config.TransactionStatuses.COMPLETED, posting.StatusCode)
+	require.False(t, posting.FromAccountID.Valid)
+	require.Equal(t, account.AccountID, posting.ToAccountID.Int32)
+	require.True(t, expected.RoundBank(2).Equal(numericDecimal(posting.Amount)))
+
+	//? What rounding left out is kept for the next posting
+	require.True(t, expected.Sub(expected.RoundBank(2)).Equal(numericDecimal(accruals[0].Residue)))
+	residue, err := sqlStore.Queries.GetInterestResidue(context.Background(), account.AccountID)
+	require.NoError(t, err)
+	require.True(t, numericDecimal(accruals[0].Residue).Equal(numericDecimal(residue)))
+
+	credited, err := sqlStore.Queries.GetAccount(context.Background(), account.AccountID)
+	require.NoError(t, err)
+	require.True(t, numericDecimal(account.Balance).Add(expected.RoundBank(2)).Equal(numericDecimal(credited.Balance)))
+
+	// The one-sided posting keeps the ledger consistent
+	result, err := transaction.RunReconciliation(context.Background(), []int32{account.AccountID})
+	require.NoError(t, err)
+	require.Zero(t, result.Run.DiscrepancyCount)
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyNumericRoundTrip(t *testing.T) {
+	defer CleanupDB(t)
+
+	amount, err := money.Parse("1234.56", iso4217.USD)
+	require.NoError(t, err)
+	transfer := createRandomTransfer(t, amount.Text())
+
+	debit := money.FromNumeric(transfer.FromEntry.Amount, transfer.FromEntry.CurrencyCode.String)
+	require.True(t, debit.Amount.Equal(amount.Neg().Amount))
+	require.Equal(t, iso4217.USD, debit.Currency)
+
+	data, err := json.Marshal(debit)
+	require.NoError(t, err)
+	require.JSONEq(t, `"-1234.56"`, string(data))
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/common"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliationCleanLedger(t *testing.T) {
+	transfer := createRandomTransfer(t, "25.00")
+
+	result, err := transaction.RunReconciliation(context.Background(), []int32{
+		transfer.FromAccount.AccountID,
+		transfer.ToAccount.AccountID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.ReconciliationStatusCOMPLETED, result.Run.Status)
+	require.Equal(t, int32(2), result.Run.AccountsChecked)
+	require.Equal(t, int32(1), result.Run.TransactionsChecked)
+	require.Zero(t, result.Run.DiscrepancyCount)
+	require.Empty(t, result.Discrepancies)
+	require.True(t, result.Run.CompletedAt.Valid)
+}
+
+func TestReconciliationBalanceMismatch(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	transfer := createRandomTransfer(t, "25.00")
+
+	// Moves money without writing an entry
+	tamper := pgtype.Numeric{}
+	require.NoError(t, tamper.Set("7.50"))
+	_, err := sqlStore.Queries.UpdateAccountBalance(context.Background(), db.UpdateAccountBalanceParams{
+		Amount:    tamper,
+		AccountID: transfer.ToAccount.AccountID,
+	})
+	require.NoError(t, err)
+
+	result, err := transaction.RunReconciliation(context.Background(), []int32{transfer.ToAccount.AccountID})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.Run.DiscrepancyCount)
+	require.Len(t, result.Discrepancies, 1)
+
+	discrepancy := result.Discrepancies[0]
+	require.Equal(t, db.DiscrepancyTypeBALANCEMISMATCH, discrepancy.DiscrepancyType)
+	require.Equal(t, transfer.ToAccount.AccountID, discrepancy.AccountID.Int32)
+	require.Equal(t, 7.5, common.NumericToFloat64(discrepancy.Difference))
+	require.Equal(t, 25.0, common.NumericToFloat64(discrepancy.ExpectedAmount))
+	require.Equal(t, 7.5, result.Drift[discrepancy.CurrencyCode.String].InexactFloat64())
+
+	stored, err := sqlStore.Queries.ListLedgerDiscrepancies(context.Background(), db.ListLedgerDiscrepanciesParams{
+		RunID:  result.Run.RunID,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, discrepancy.DiscrepancyID, stored[0].DiscrepancyID)
+}
+
+func TestReconciliationMissingEntries(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	transfer := createRandomTransfer(t, "10.00")
+
+	exchangeRate := pgtype.Numeric{}
+	require.NoError(t, exchangeRate.Set("1"))
+
+	// A completed transaction that was never posted to the ledger
+	unposted, err := sqlStore.Queries.CreateTransaction(context.Background(), db.CreateTransactionParams{
+		FromAccountID:   sql.NullInt32{Int32: transfer.FromAccount.AccountID, Valid: true},
+		ToAccountID:     sql.NullInt32{Int32: transfer.ToAccount.AccountID, Valid: true},
+		TypeCode:        transfer.Transaction.TypeCode,
+		Amount:          transfer.Transaction.Amount,
+		CurrencyCode:    transfer.Transaction.CurrencyCode,
+		ExchangeRate:    exchangeRate,
+		StatusCode:      transfer.Transaction.StatusCode,
+		ReferenceNumber: sql.NullString{String: common.RandomString(10), Valid: true},
+		TransactionDate: time.Now(),
+		ConvertedAmount: pgtype.Numeric{Status: pgtype.Null},
+	})
+	require.NoError(t, err)
+
+	result, err := transaction.RunReconciliation(context.Background(), []int32{transfer.FromAccount.AccountID})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), result.Run.TransactionsChecked)
+	require.Len(t, result.Discrepancies, 1)
+
+	discrepancy := result.Discrepancies[0]
+	require.Equal(t, db.DiscrepancyTypeMISSINGENTRIES, discrepancy.DiscrepancyType)
+	require.Equal(t, unposted.TransactionID, discrepancy.TransactionID.Int32)
+	require.Equal(t, -10.0, common.NumericToFloat64(discrepancy.Difference))
+
+	run, err := sqlStore.Queries.GetReconciliationRun(context.Background(), result.Run.RunNumber)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), run.DiscrepancyCount)
+}
+
+func TestReconciliationRoundedCredit(t *testing.T) {
+	defer CleanupDB(t)
+
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+	jpy, err := transaction.CreateCurrencyCode(iso4217.JPY)
+	require.NoError(t, err)
+	usd, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	dollarAccount := createRandomAccountWithCurrency(t, usd.CurrencyCode)
+	yenAccount := createRandomAccountWithCurrency(t, jpy.CurrencyCode)
+
+	amount := pgtype.Numeric{}
+	require.NoError(t, amount.Set("10.00"))
+	_, err = transaction.TransferTx(context.Background(), schemas.TransferTxParams{
+		SenderAccountID:   dollarAccount.AccountID,
+		ReceiverAccountID: yenAccount.AccountID,
+		Amount:            amount,
+		CurrencyCode:      usd.CurrencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        completedStatus.StatusCode,
+	})
+	require.NoError(t, err)
+
+	//? 1492.54 JPY credited as 1493 is within half a yen, not a discrepancy
+	result, err := transaction.RunReconciliation(context.Background(), []int32{
+		dollarAccount.AccountID,
+		yenAccount.AccountID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.Run.TransactionsChecked)
+	require.Empty(t, result.Discrepancies)
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/common"
+	"github.com/stretchr/testify/require"
+)
+
+// !createRandomRefreshToken => stores a random refresh token for a new user and validates the stored fields.
+func createRandomRefreshToken(t *testing.T) db.RefreshToken {
+	sqlStore := SetupTestStore(t)
+	user := createRandomUser(t)
+
+	arg := db.CreateRefreshTokenParams{
+		UserID:    user.UserID,
+		TokenHash: common.RandomString(64),
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	refreshToken, err := sqlStore.Queries.CreateRefreshToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, refreshToken)
+
+	require.Equal(t, arg.UserID, refreshToken.UserID)
+	require.Equal(t, arg.TokenHash, refreshToken.TokenHash)
+	require.Equal(t, arg.FamilyID, refreshToken.FamilyID)
+	require.False(t, refreshToken.RevokedAt.Valid)
+	require.False(t, refreshToken.ReplacedBy.Valid)
+	require.WithinDuration(t, arg.ExpiresAt, refreshToken.ExpiresAt, time.Second)
+	return refreshToken
+}
+
+func TestCreateRefreshToken(t *testing.T) {
+	createRandomRefreshToken(t)
+	defer CleanupDB(t)
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	current := createRandomRefreshToken(t)
+
+	rotated, err := transaction.RotateRefreshToken(context.Background(), current.TokenHash, db.CreateRefreshTokenParams{
+		TokenHash: common.RandomString(64),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, current.UserID, rotated.UserID)
+	require.Equal(t, current.FamilyID, rotated.FamilyID)
+
+	replaced, err := sqlStore.Queries.GetRefreshToken(context.Background(), current.TokenHash)
+	require.NoError(t, err)
+	require.True(t, replaced.RevokedAt.Valid)
+	require.Equal(t, rotated.TokenID, replaced.ReplacedBy.Int64)
+	defer CleanupDB(t)
+}
+
+func TestRotateRefreshTokenReuse(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	current := createRandomRefreshToken(t)
+
+	rotated, err := transaction.RotateRefreshToken(context.Background(), current.TokenHash, db.CreateRefreshTokenParams{
+		TokenHash: common.RandomString(64),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	//? Presenting the replaced token again revokes the token that replaced it too
+	_, err = transaction.RotateRefreshToken(context.Background(), current.TokenHash, db.CreateRefreshTokenParams{
+		TokenHash: common.RandomString(64),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, transaction.ErrRefreshTokenReused)
+
+	revoked, err := sqlStore.Queries.GetRefreshToken(context.Background(), rotated.TokenHash)
+	require.NoError(t, err)
+	require.True(t, revoked.RevokedAt.Valid)
+	defer CleanupDB(t)
+}
+
+func TestRotateExpiredRefreshToken(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	user := createRandomUser(t)
+
+	expired, err := sqlStore.Queries.CreateRefreshToken(context.Background(), db.CreateRefreshTokenParams{
+		UserID:    user.UserID,
+		TokenHash: common.RandomString(64),
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	_, err = transaction.RotateRefreshToken(context.Background(), expired.TokenHash, db.CreateRefreshTokenParams{
+		TokenHash: common.RandomString(64),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, transaction.ErrRefreshTokenInvalid)
+	defer CleanupDB(t)
+}
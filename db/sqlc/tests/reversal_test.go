@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/stretchr/testify/require"
+)
+
+// createRandomTransfer moves the amount between two new accounts of the same currency
+func createRandomTransfer(t *testing.T, amount string) schemas.TransferTxResult {
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	sender := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	receiver := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+
+	transferAmount := pgtype.Numeric{}
+	require.NoError(t, transferAmount.Set(amount))
+
+	result, err := transaction.TransferTx(context.Background(), schemas.TransferTxParams{
+		SenderAccountID:   sender.AccountID,
+		ReceiverAccountID: receiver.AccountID,
+		Amount:            transferAmount,
+		CurrencyCode:      currency.CurrencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        completedStatus.StatusCode,
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestReverseTx(t *testing.T) {
+	transfer := createRandomTransfer(t, "10.00")
+
+	result, err := transaction.ReverseTx(context.Background(), schemas.ReverseTxParams{
+		TransactionNumber: transfer.Transaction.TransactionNumber,
+		Description:       "customer dispute",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, config.TransactionTypes.REVERSAL, result.Transaction.TypeCode)
+	require.Equal(t, transfer.Transaction.TransactionID, result.Transaction.OriginalTransactionID.Int32)
+	require.Equal(t, transfer.Transaction.ToAccountID, result.Transaction.FromAccountID)
+	require.Equal(t, transfer.Transaction.FromAccountID, result.Transaction.ToAccountID)
+	require.Equal(t, config.TransactionStatuses.REVERSED, result.Original.StatusCode)
+
+	//? Both accounts are back where they started
+	var senderBefore, senderAfter, receiverBefore, receiverAfter float64
+	require.NoError(t, transfer.FromAccount.Balance.AssignTo(&senderBefore))
+	require.NoError(t, result.ToAccount.Balance.AssignTo(&senderAfter))
+	require.NoError(t, transfer.ToAccount.Balance.AssignTo(&receiverBefore))
+	require.NoError(t, result.FromAccount.Balance.AssignTo(&receiverAfter))
+	require.InDelta(t, 10.0, senderAfter-senderBefore, 0.001)
+	require.InDelta(t, -10.0, receiverAfter-receiverBefore, 0.001)
+
+	//? A reversed transaction cannot be reversed again
+	_, err = transaction.ReverseTx(context.Background(), schemas.ReverseTxParams{
+		TransactionNumber: transfer.Transaction.TransactionNumber,
+	})
+	require.ErrorIs(t, err, transaction.ErrAlreadyReversed)
+
+	defer CleanupDB(t)
+}
+
+func TestRefundTxPartial(t *testing.T) {
+	transfer := createRandomTransfer(t, "10.00")
+
+	refund := func(amount string) (schemas.ReversalTxResult, error) {
+		refundAmount := pgtype.Numeric{}
+		require.NoError(t, refundAmount.Set(amount))
+		return transaction.RefundTx(context.Background(), schemas.RefundTxParams{
+			TransactionNumber: transfer.Transaction.TransactionNumber,
+			Amount:            refundAmount,
+		})
+	}
+
+	result, err := refund("4.00")
+	require.NoError(t, err)
+	require.Equal(t, config.TransactionTypes.REFUND, result.Transaction.TypeCode)
+	require.Equal(t, config.TransactionStatuses.COMPLETED, result.Original.StatusCode)
+
+	//? More than what is left is rejected
+	_, err = refund("6.01")
+	require.ErrorIs(t, err, transaction.ErrRefundExceedsOriginal)
+
+	//? Refunding the rest reverses the original
+	result, err = refund("6.00")
+	require.NoError(t, err)
+	require.Equal(t, config.TransactionStatuses.REVERSED, result.Original.StatusCode)
+
+	_, err = refund("0.01")
+	require.ErrorIs(t, err, transaction.ErrAlreadyReversed)
+
+	defer CleanupDB(t)
+}
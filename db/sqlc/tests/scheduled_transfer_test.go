@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/stretchr/testify/require"
+)
+
+// createDueScheduledTransfer stores a scheduled transfer between two new accounts that is already due
+func createDueScheduledTransfer(t *testing.T, frequency db.ScheduleFrequency, amount string) db.ScheduledTransfer {
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	sender := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	receiver := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+
+	transferAmount := pgtype.Numeric{}
+	require.NoError(t, transferAmount.Set(amount))
+
+	startAt := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	sqlStore := SetupTestStore(t)
+	scheduled, err := sqlStore.Queries.CreateScheduledTransfer(context.Background(), db.CreateScheduledTransferParams{
+		FromAccountID: sender.AccountID,
+		ToAccountID:   receiver.AccountID,
+		Amount:        transferAmount,
+		CurrencyCode:  currency.CurrencyCode,
+		Description:   sql.NullString{String: "rent", Valid: true},
+		Frequency:     frequency,
+		StartAt:       startAt,
+		NextRunAt:     startAt,
+		DueAt:         startAt,
+		MaxRetries:    1,
+		UserID:        sql.NullInt32{Int32: sender.UserID, Valid: true},
+	})
+	require.NoError(t, err)
+	return scheduled
+}
+
+func TestExecuteDueScheduledTransfersRecurring(t *testing.T) {
+	scheduled := createDueScheduledTransfer(t, db.ScheduleFrequencyDAILY, "1.00")
+
+	result, err := transaction.ExecuteDueScheduledTransfers(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Executed)
+	require.Equal(t, 0, result.Failed)
+
+	sqlStore := SetupTestStore(t)
+	updated, err := sqlStore.Queries.GetScheduledTransfer(context.Background(), scheduled.ScheduleNumber)
+	require.NoError(t, err)
+	require.Equal(t, db.ScheduledTransferStatusACTIVE, updated.Status)
+	require.WithinDuration(t, scheduled.StartAt.AddDate(0, 0, 1), updated.NextRunAt, time.Second)
+	require.Equal(t, updated.NextRunAt, updated.DueAt)
+	require.True(t, updated.LastRunAt.Valid)
+
+	executions, err := sqlStore.Queries.ListScheduledTransferExecutions(context.Background(),
+		db.ListScheduledTransferExecutionsParams{ScheduledTransferID: scheduled.ScheduledTransferID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+	require.Equal(t, db.ScheduledExecutionStatusSUCCEEDED, executions[0].Status)
+	require.True(t, executions[0].TransactionID.Valid)
+
+	//? Nothing is due anymore
+	result, err = transaction.ExecuteDueScheduledTransfers(context.Background(), 10)
+	require.NoError(t, err)
+	require.Zero(t, result.Executed+result.Failed)
+
+	defer CleanupDB(t)
+}
+
+func TestExecuteDueScheduledTransfersOnce(t *testing.T) {
+	scheduled := createDueScheduledTransfer(t, db.ScheduleFrequencyONCE, "1.00")
+
+	result, err := transaction.ExecuteDueScheduledTransfers(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Executed)
+
+	sqlStore := SetupTestStore(t)
+	updated, err := sqlStore.Queries.GetScheduledTransfer(context.Background(), scheduled.ScheduleNumber)
+	require.NoError(t, err)
+	require.Equal(t, db.ScheduledTransferStatusCOMPLETED, updated.Status)
+
+	defer CleanupDB(t)
+}
+
+func TestExecuteDueScheduledTransfersRetry(t *testing.T) {
+	//? Far above any random overdraft limit
+	scheduled := createDueScheduledTransfer(t, db.ScheduleFrequencyONCE, "100000.00")
+
+	result, err := transaction.ExecuteDueScheduledTransfers(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Failed)
+
+	sqlStore := SetupTestStore(t)
+	updated, err := sqlStore.Queries.GetScheduledTransfer(context.Background(), scheduled.ScheduleNumber)
+	require.NoError(t, err)
+	require.Equal(t, db.ScheduledTransferStatusACTIVE, updated.Status)
+	require.Equal(t, int32(1), updated.RetryCount)
+	require.True(t, updated.DueAt.After(time.Now()))
+	require.Equal(t, scheduled.NextRunAt.Unix(), updated.NextRunAt.Unix())
+
+	executions, err := sqlStore.Queries.ListScheduledTransferExecutions(context.Background(),
+		db.ListScheduledTransferExecutionsParams{ScheduledTransferID: scheduled.ScheduledTransferID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+	require.Equal(t, db.ScheduledExecutionStatusFAILED, executions[0].Status)
+	require.True(t, executions[0].ErrorMessage.Valid)
+
+	defer CleanupDB(t)
+}
+
+func TestExecuteDueScheduledTransfersClosedAccount(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	scheduled := createDueScheduledTransfer(t, db.ScheduleFrequencyDAILY, "1.00")
+	require.NoError(t, sqlStore.Queries.DeleteAccount(context.Background(), scheduled.ToAccountID))
+
+	result, err := transaction.ExecuteDueScheduledTransfers(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Failed)
+
+	//? Retrying would not bring the account back, the schedule is cancelled
+	updated, err := sqlStore.Queries.GetScheduledTransfer(context.Background(), scheduled.ScheduleNumber)
+	require.NoError(t, err)
+	require.Equal(t, db.ScheduledTransferStatusCANCELLED, updated.Status)
+
+	executions, err := sqlStore.Queries.ListScheduledTransferExecutions(context.Background(),
+		db.ListScheduledTransferExecutionsParams{ScheduledTransferID: scheduled.ScheduledTransferID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+	require.Equal(t, db.ScheduledExecutionStatusFAILED, executions[0].Status)
+	require.Equal(t, transaction.ErrScheduledAccountInactive.Error(), executions[0].ErrorMessage.String)
+	require.False(t, executions[0].TransactionID.Valid)
+
+	defer CleanupDB(t)
+}
+
+func TestExecuteDueScheduledTransfersSenderChangedHands(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	scheduled := createDueScheduledTransfer(t, db.ScheduleFrequencyDAILY, "1.00")
+	_, err := sqlStore.Pool.Exec(context.Background(),
+		"UPDATE accounts SET user_id = $1 WHERE account_id = $2", createRandomUser(t).UserID, scheduled.FromAccountID)
+	require.NoError(t, err)
+
+	result, err := transaction.ExecuteDueScheduledTransfers(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Failed)
+
+	updated, err := sqlStore.Queries.GetScheduledTransfer(context.Background(), scheduled.ScheduleNumber)
+	require.NoError(t, err)
+	require.Equal(t, db.ScheduledTransferStatusCANCELLED, updated.Status)
+
+	executions, err := sqlStore.Queries.ListScheduledTransferExecutions(context.Background(),
+		db.ListScheduledTransferExecutionsParams{ScheduledTransferID: scheduled.ScheduledTransferID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+	require.Equal(t, transaction.ErrScheduledAccountNotOwned.Error(), executions[0].ErrorMessage.String)
+
+	defer CleanupDB(t)
+}
+
+func TestPauseScheduledTransfer(t *testing.T) {
+	scheduled := createDueScheduledTransfer(t, db.ScheduleFrequencyDAILY, "1.00")
+
+	paused, err := transaction.UpdateScheduledTransferStatus(context.Background(),
+		scheduled.ScheduleNumber, db.ScheduledTransferStatusPAUSED)
+	require.NoError(t, err)
+	require.Equal(t, db.ScheduledTransferStatusPAUSED, paused.Status)
+
+	//? Paused transfers are skipped by the scheduler
+	result, err := transaction.ExecuteDueScheduledTransfers(context.Background(), 10)
+	require.NoError(t, err)
+	require.Zero(t, result.Executed+result.Failed)
+
+	//? Resuming skips the occurrence missed while paused
+	resumed, err := transaction.UpdateScheduledTransferStatus(context.Background(),
+		scheduled.ScheduleNumber, db.ScheduledTransferStatusACTIVE)
+	require.NoError(t, err)
+	require.Equal(t, db.ScheduledTransferStatusACTIVE, resumed.Status)
+	require.True(t, resumed.NextRunAt.After(time.Now()))
+
+	_, err = transaction.UpdateScheduledTransferStatus(context.Background(),
+		scheduled.ScheduleNumber, db.ScheduledTransferStatusACTIVE)
+	require.ErrorIs(t, err, transaction.ErrInvalidScheduleTransition)
+
+	defer CleanupDB(t)
+}
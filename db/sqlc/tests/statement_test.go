@@ -0,0 +1,123 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/riad/banksystemendtoend/util/statement"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStatement(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	sender := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	receiver := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+
+	for _, value := range []string{"3.00", "2.00"} {
+		amount := pgtype.Numeric{}
+		require.NoError(t, amount.Set(value))
+		_, err := transaction.TransferTx(context.Background(), schemas.TransferTxParams{
+			SenderAccountID:   sender.AccountID,
+			ReceiverAccountID: receiver.AccountID,
+			Amount:            amount,
+			CurrencyCode:      currency.CurrencyCode,
+			TypeCode:          transferType.TypeCode,
+			StatusCode:        completedStatus.StatusCode,
+			Description:       "statement test",
+		})
+		require.NoError(t, err)
+	}
+
+	today := interest.Date(time.Now())
+	built, err := transaction.BuildStatement(context.Background(), receiver.AccountID, today, today)
+	require.NoError(t, err)
+	require.Len(t, built.Lines, 2)
+	require.True(t, built.OpeningBalance.IsZero())
+	require.True(t, decimal.RequireFromString("3.00").Equal(built.Lines[0].Balance))
+	require.True(t, decimal.RequireFromString("5.00").Equal(built.Lines[1].Balance))
+	require.True(t, decimal.RequireFromString("5.00").Equal(built.TotalCredits))
+	require.True(t, built.TotalDebits.IsZero())
+	require.Equal(t, sender.AccountID, built.Lines[0].CounterpartyAccountID)
+	require.Equal(t, config.TransactionTypes.TRANSFER, built.Lines[0].TypeCode)
+
+	// The closing balance is the stored balance, nothing was booked after the period
+	account, err := sqlStore.Queries.GetAccount(context.Background(), receiver.AccountID)
+	require.NoError(t, err)
+	require.True(t, numericDecimal(account.Balance).Equal(built.ClosingBalance))
+
+	for _, format := range []db.StatementFormat{
+		db.StatementFormatCSV, db.StatementFormatPDF, db.StatementFormatOFX, db.StatementFormatCAMT053,
+	} {
+		file, err := statement.Render(format, built)
+		require.NoError(t, err)
+		require.NotEmpty(t, file.Data)
+		require.NotEmpty(t, file.ContentType)
+	}
+
+	// A header, the opening and closing balance rows and one row per entry
+	file, err := statement.Render(db.StatementFormatCSV, built)
+	require.NoError(t, err)
+	rows, err := csv.NewReader(bytes.NewReader(file.Data)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+}
+
+func TestClaimPendingStatements(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	account := createRandomAccount(t)
+
+	today := interest.Date(time.Now())
+	created, err := sqlStore.Queries.CreateStatement(context.Background(), db.CreateStatementParams{
+		AccountID:   account.AccountID,
+		Format:      db.StatementFormatOFX,
+		PeriodStart: today.AddDate(0, -1, 0),
+		PeriodEnd:   today,
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.StatementStatusPENDING, created.Status)
+
+	claimed, err := sqlStore.Queries.ClaimPendingStatements(context.Background(), db.ClaimPendingStatementsParams{
+		StaleBefore: time.Now().Add(-time.Hour),
+		BatchSize:   1000,
+	})
+	require.NoError(t, err)
+
+	var found bool
+	for _, stmt := range claimed {
+		if stmt.StatementID == created.StatementID {
+			found = true
+			require.Equal(t, db.StatementStatusPROCESSING, stmt.Status)
+			require.Equal(t, int32(1), stmt.Attempts)
+		}
+	}
+	require.True(t, found)
+
+	completed, err := sqlStore.Queries.CompleteStatement(context.Background(), db.CompleteStatementParams{
+		StatementID: created.StatementID,
+		EntryCount:  sql.NullInt32{Int32: 0, Valid: true},
+		FileUrl:     sql.NullString{String: "https://bucket.s3.amazonaws.com/statements/test.ofx", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.StatementStatusCOMPLETED, completed.Status)
+	require.True(t, completed.CompletedAt.Valid)
+}
@@ -42,6 +42,7 @@ func createRandomTransaction(t *testing.T) db.Transaction {
 			Description:     sql.NullString{String: common.RandomString(20), Valid: true},
 			ReferenceNumber: sql.NullString{String: common.RandomString(10), Valid: true},
 			TransactionDate: time.Now(),
+			ConvertedAmount: pgtype.Numeric{Status: pgtype.Null},
 		}
 
 		transaction, err = sqlStore.CreateTransaction(context.Background(), arg)
@@ -106,6 +107,7 @@ func TestListTransactionsByAccount(t *testing.T) {
 			Description:     sql.NullString{String: "Test transaction", Valid: true},
 			ReferenceNumber: sql.NullString{String: common.RandomString(10), Valid: true},
 			TransactionDate: time.Now(),
+			ConvertedAmount: pgtype.Numeric{Status: pgtype.Null},
 		}
 		_, err := sqlStore.CreateTransaction(context.Background(), arg)
 		require.NoError(t, err)
@@ -198,6 +200,7 @@ func TestGetTransactionBalance(t *testing.T) {
 			Description:     sql.NullString{String: "Test transaction", Valid: true},
 			ReferenceNumber: sql.NullString{String: common.RandomString(10), Valid: true},
 			TransactionDate: time.Now(),
+			ConvertedAmount: pgtype.Numeric{Status: pgtype.Null},
 		}
 		_, err := sqlStore.CreateTransaction(context.Background(), arg)
 		require.NoError(t, err)
@@ -238,6 +241,7 @@ func TestGetTransactionStatement(t *testing.T) {
 			Description:     sql.NullString{String: "Test transaction", Valid: true},
 			ReferenceNumber: sql.NullString{String: common.RandomString(10), Valid: true},
 			TransactionDate: time.Now(),
+			ConvertedAmount: pgtype.Numeric{Status: pgtype.Null},
 		}
 		_, err := sqlStore.CreateTransaction(context.Background(), arg)
 		require.NoError(t, err)
@@ -288,6 +292,7 @@ func TestGetTransactionsByStatus(t *testing.T) {
 			Description:     sql.NullString{String: "Test transaction", Valid: true},
 			ReferenceNumber: sql.NullString{String: common.RandomString(10), Valid: true},
 			TransactionDate: time.Now(),
+			ConvertedAmount: pgtype.Numeric{Status: pgtype.Null},
 		}
 		_, err := sqlStore.CreateTransaction(context.Background(), arg)
 		require.NoError(t, err)
@@ -362,6 +367,7 @@ func TestDeleteAccountTransactions(t *testing.T) {
 			Description:     sql.NullString{String: "Test transaction", Valid: true},
 			ReferenceNumber: sql.NullString{String: common.RandomString(10), Valid: true},
 			TransactionDate: time.Now(),
+			ConvertedAmount: pgtype.Numeric{Status: pgtype.Null},
 		}
 		_, err := sqlStore.CreateTransaction(context.Background(), arg)
 		require.NoError(t, err)
@@ -437,6 +443,7 @@ func TestDeleteTransactionsByStatus(t *testing.T) {
 			Description:     sql.NullString{String: "Test transaction", Valid: true},
 			ReferenceNumber: sql.NullString{String: common.RandomString(10), Valid: true},
 			TransactionDate: time.Now(),
+			ConvertedAmount: pgtype.Numeric{Status: pgtype.Null},
 		}
 		_, err := sqlStore.CreateTransaction(context.Background(), arg)
 		require.NoError(t, err)
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/util/common"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/stretchr/testify/require"
+)
+
+// missingAccountID does not belong to any account created by the tests
+const missingAccountID = 2147483000
+
+func batchItem(t *testing.T, from, to db.Account, amount string) schemas.TransferBatchItemParams {
+	transferAmount := pgtype.Numeric{}
+	require.NoError(t, transferAmount.Set(amount))
+	return schemas.TransferBatchItemParams{
+		FromAccountID: from.AccountID,
+		ToAccountID:   to.AccountID,
+		Amount:        transferAmount,
+		CurrencyCode:  from.CurrencyCode,
+		Description:   "payroll",
+	}
+}
+
+// runTransferBatch creates a batch and lets the worker process it
+func runTransferBatch(t *testing.T, mode db.BatchMode, items []schemas.TransferBatchItemParams) (db.TransferBatch, []db.TransferBatchItem) {
+	batch, err := transaction.CreateTransferBatch(context.Background(), schemas.CreateTransferBatchParams{
+		Mode:            mode,
+		ReferenceNumber: common.RandomString(20),
+		Items:           items,
+	})
+	require.NoError(t, err)
+	require.Equal(t, db.BatchStatusPENDING, batch.Status)
+	require.Equal(t, int32(len(items)), batch.TotalItems)
+
+	processed, ok, err := transaction.ProcessNextTransferBatch(context.Background())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, batch.BatchID, processed.BatchID)
+	require.Equal(t, batch.TotalItems, processed.ProcessedItems)
+	require.True(t, processed.CompletedAt.Valid)
+
+	sqlStore := SetupTestStore(t)
+	batchItems, err := sqlStore.Queries.ListTransferBatchItems(context.Background(), batch.BatchID)
+	require.NoError(t, err)
+	require.Len(t, batchItems, len(items))
+	return processed, batchItems
+}
+
+func createBatchAccounts(t *testing.T, n int) []db.Account {
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	accounts := make([]db.Account, n)
+	for i := range accounts {
+		accounts[i] = createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	}
+	return accounts
+}
+
+func TestTransferBatchAllOrNothingCompleted(t *testing.T) {
+	accounts := createBatchAccounts(t, 3)
+
+	batch, items := runTransferBatch(t, db.BatchModeALLORNOTHING, []schemas.TransferBatchItemParams{
+		batchItem(t, accounts[0], accounts[1], "1.00"),
+		batchItem(t, accounts[0], accounts[2], "2.00"),
+	})
+	require.Equal(t, db.BatchStatusCOMPLETED, batch.Status)
+	require.Equal(t, int32(2), batch.SucceededItems)
+	require.Zero(t, batch.FailedItems)
+
+	sqlStore := SetupTestStore(t)
+	for _, item := range items {
+		require.Equal(t, db.BatchItemStatusSUCCEEDED, item.Status)
+		require.True(t, item.TransactionID.Valid)
+
+		transfer, err := sqlStore.Queries.GetTransaction(context.Background(), item.TransactionID.Int32)
+		require.NoError(t, err)
+		require.Equal(t, item.FromAccountID, transfer.FromAccountID.Int32)
+	}
+
+	sender, err := sqlStore.Queries.GetAccount(context.Background(), accounts[0].AccountID)
+	require.NoError(t, err)
+	require.InDelta(t, numericFloat(t, accounts[0].Balance)-3, numericFloat(t, sender.Balance), 0.001)
+
+	defer CleanupDB(t)
+}
+
+func TestTransferBatchAllOrNothingRollsBack(t *testing.T) {
+	accounts := createBatchAccounts(t, 2)
+	missing := db.Account{AccountID: missingAccountID, CurrencyCode: accounts[0].CurrencyCode}
+
+	batch, items := runTransferBatch(t, db.BatchModeALLORNOTHING, []schemas.TransferBatchItemParams{
+		batchItem(t, accounts[0], accounts[1], "1.00"),
+		batchItem(t, accounts[0], missing, "1.00"),
+	})
+	require.Equal(t, db.BatchStatusFAILED, batch.Status)
+	require.Zero(t, batch.SucceededItems)
+	require.Equal(t, int32(2), batch.FailedItems)
+
+	for _, item := range items {
+		require.Equal(t, db.BatchItemStatusFAILED, item.Status)
+		require.False(t, item.TransactionID.Valid)
+	}
+	require.Equal(t, transaction.ErrBatchAccountNotFound.Error(), items[1].ErrorMessage.String)
+
+	//? The first item was rolled back together with the failing one
+	sqlStore := SetupTestStore(t)
+	sender, err := sqlStore.Queries.GetAccount(context.Background(), accounts[0].AccountID)
+	require.NoError(t, err)
+	require.InDelta(t, numericFloat(t, accounts[0].Balance), numericFloat(t, sender.Balance), 0.001)
+
+	defer CleanupDB(t)
+}
+
+func TestTransferBatchBestEffortPartiallyCompleted(t *testing.T) {
+	accounts := createBatchAccounts(t, 2)
+	missing := db.Account{AccountID: missingAccountID, CurrencyCode: accounts[0].CurrencyCode}
+
+	batch, items := runTransferBatch(t, db.BatchModeBESTEFFORT, []schemas.TransferBatchItemParams{
+		batchItem(t, accounts[0], accounts[1], "1.00"),
+		batchItem(t, accounts[0], missing, "1.00"),
+	})
+	require.Equal(t, db.BatchStatusPARTIALLYCOMPLETED, batch.Status)
+	require.Equal(t, int32(1), batch.SucceededItems)
+	require.Equal(t, int32(1), batch.FailedItems)
+
+	require.Equal(t, db.BatchItemStatusSUCCEEDED, items[0].Status)
+	require.True(t, items[0].TransactionID.Valid)
+	require.Equal(t, db.BatchItemStatusFAILED, items[1].Status)
+	require.Equal(t, transaction.ErrBatchAccountNotFound.Error(), items[1].ErrorMessage.String)
+
+	sqlStore := SetupTestStore(t)
+	sender, err := sqlStore.Queries.GetAccount(context.Background(), accounts[0].AccountID)
+	require.NoError(t, err)
+	require.InDelta(t, numericFloat(t, accounts[0].Balance)-1, numericFloat(t, sender.Balance), 0.001)
+
+	//? Nothing is left to process
+	_, ok, err := transaction.ProcessNextTransferBatch(context.Background())
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	defer CleanupDB(t)
+}
+
+func TestCreateTransferBatchDuplicateReference(t *testing.T) {
+	accounts := createBatchAccounts(t, 2)
+	arg := schemas.CreateTransferBatchParams{
+		Mode:            db.BatchModeBESTEFFORT,
+		ReferenceNumber: common.RandomString(20),
+		Items:           []schemas.TransferBatchItemParams{batchItem(t, accounts[0], accounts[1], "1.00")},
+	}
+
+	_, err := transaction.CreateTransferBatch(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = transaction.CreateTransferBatch(context.Background(), arg)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "SQLSTATE 23505")
+
+	defer CleanupDB(t)
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
 	"github.com/riad/banksystemendtoend/util/config"
 	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/iso4217"
 	"github.com/riad/banksystemendtoend/util/schemas"
 	"github.com/stretchr/testify/require"
 )
@@ -25,19 +27,19 @@ func TestTransfer(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, store)
 
-	sender := createRandomAccount(t)
-	receiver := createRandomAccount(t)
-	fmt.Println(">> before:", sender.Balance, receiver.Balance)
-
 	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
 	require.NoError(t, err)
 
 	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
 	require.NoError(t, err)
 
-	currency, err := transaction.CreateCurrencyCode(config.TransactionCurrencies.USD.CODE)
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
 	require.NoError(t, err)
 
+	sender := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	receiver := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	fmt.Println(">> before:", sender.Balance, receiver.Balance)
+
 	amount := pgtype.Numeric{}
 	err = amount.Set(10)
 	require.NoError(t, err)
@@ -192,3 +194,137 @@ func TestTransfer(t *testing.T) {
 	fmt.Println(">> after:", updatedSender.Balance, updatedReceiver.Balance)
 	defer CleanupDB(t)
 }
+
+func TestTransferInsufficientFunds(t *testing.T) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	require.NoError(t, err)
+
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+
+	currency, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	sender := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+	receiver := createRandomAccountWithCurrency(t, currency.CurrencyCode)
+
+	//? Far beyond the balance plus overdraft limit of a random account
+	amount := pgtype.Numeric{}
+	err = amount.Set(1000000000)
+	require.NoError(t, err)
+
+	_, err = transaction.TransferTx(context.Background(), schemas.TransferTxParams{
+		SenderAccountID:   sender.AccountID,
+		ReceiverAccountID: receiver.AccountID,
+		Amount:            amount,
+		CurrencyCode:      currency.CurrencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        completedStatus.StatusCode,
+	})
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "accounts_balance_check"))
+
+	//? The whole transfer is rolled back
+	updatedSender, err := store.GetAccount(context.Background(), sender.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, sender.Balance, updatedSender.Balance)
+
+	updatedReceiver, err := store.GetAccount(context.Background(), receiver.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, receiver.Balance, updatedReceiver.Balance)
+
+	defer CleanupDB(t)
+}
+
+func TestTransferCrossCurrency(t *testing.T) {
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+
+	//? 1 GBP = 1.26 USD, so 10.00 GBP credits 12.60 USD
+	gbp, err := transaction.CreateCurrencyCode(iso4217.GBP)
+	require.NoError(t, err)
+	usd, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	sender := createRandomAccountWithCurrency(t, gbp.CurrencyCode)
+	receiver := createRandomAccountWithCurrency(t, usd.CurrencyCode)
+
+	amount := pgtype.Numeric{}
+	err = amount.Set("10.00")
+	require.NoError(t, err)
+
+	result, err := transaction.TransferTx(context.Background(), schemas.TransferTxParams{
+		SenderAccountID:   sender.AccountID,
+		ReceiverAccountID: receiver.AccountID,
+		Amount:            amount,
+		CurrencyCode:      gbp.CurrencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        completedStatus.StatusCode,
+	})
+	require.NoError(t, err)
+
+	var rate, converted, debit, credit float64
+	require.NoError(t, result.Transaction.ExchangeRate.AssignTo(&rate))
+	require.NoError(t, result.Transaction.ConvertedAmount.AssignTo(&converted))
+	require.NoError(t, result.FromEntry.Amount.AssignTo(&debit))
+	require.NoError(t, result.ToEntry.Amount.AssignTo(&credit))
+
+	require.Equal(t, 1.26, rate)
+	require.Equal(t, 12.6, converted)
+	require.Equal(t, gbp.CurrencyCode, result.Transaction.CurrencyCode)
+	require.Equal(t, usd.CurrencyCode, result.Transaction.ConvertedCurrencyCode.String)
+
+	//? Each entry is booked in its own account currency
+	require.Equal(t, -10.0, debit)
+	require.Equal(t, gbp.CurrencyCode, result.FromEntry.CurrencyCode.String)
+	require.Equal(t, 12.6, credit)
+	require.Equal(t, usd.CurrencyCode, result.ToEntry.CurrencyCode.String)
+	require.Equal(t, result.Transaction.TransactionID, result.ToEntry.TransactionID.Int32)
+
+	//? Balances move by the amount of their own leg
+	var senderBefore, senderAfter, receiverBefore, receiverAfter float64
+	require.NoError(t, sender.Balance.AssignTo(&senderBefore))
+	require.NoError(t, result.FromAccount.Balance.AssignTo(&senderAfter))
+	require.NoError(t, receiver.Balance.AssignTo(&receiverBefore))
+	require.NoError(t, result.ToAccount.Balance.AssignTo(&receiverAfter))
+	require.InDelta(t, 10.0, senderBefore-senderAfter, 0.001)
+	require.InDelta(t, 12.6, receiverAfter-receiverBefore, 0.001)
+
+	defer CleanupDB(t)
+}
+
+func TestTransferCurrencyMismatch(t *testing.T) {
+	completedStatus, err := transaction.CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+	require.NoError(t, err)
+
+	transferType, err := transaction.CreateTransactionType(config.TransactionTypes.TRANSFER)
+	require.NoError(t, err)
+
+	usd, err := transaction.CreateCurrencyCode(iso4217.USD)
+	require.NoError(t, err)
+
+	sender := createRandomAccount(t)
+	receiver := createRandomAccountWithCurrency(t, usd.CurrencyCode)
+
+	amount := pgtype.Numeric{}
+	err = amount.Set(10)
+	require.NoError(t, err)
+
+	_, err = transaction.TransferTx(context.Background(), schemas.TransferTxParams{
+		SenderAccountID:   sender.AccountID,
+		ReceiverAccountID: receiver.AccountID,
+		Amount:            amount,
+		CurrencyCode:      usd.CurrencyCode,
+		TypeCode:          transferType.TypeCode,
+		StatusCode:        completedStatus.StatusCode,
+	})
+	require.ErrorIs(t, err, transaction.ErrCurrencyMismatch)
+
+	defer CleanupDB(t)
+}
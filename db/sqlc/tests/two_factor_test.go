@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/totp"
+	"github.com/stretchr/testify/require"
+)
+
+// !createEnabledTwoFactor => enrolls a new user, confirms the enrollment and returns the recovery codes.
+func createEnabledTwoFactor(t *testing.T) (db.UserTwoFactor, []string) {
+	sqlStore := SetupTestStore(t)
+	user := createRandomUser(t)
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	enrolled, err := sqlStore.Queries.StartTwoFactorEnrollment(context.Background(), db.StartTwoFactorEnrollmentParams{
+		UserID: user.UserID,
+		Secret: secret,
+	})
+	require.NoError(t, err)
+	require.Equal(t, secret, enrolled.Secret)
+	require.False(t, enrolled.EnabledAt.Valid)
+
+	codes, err := totp.GenerateRecoveryCodes()
+	require.NoError(t, err)
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, totp.HashRecoveryCode(code))
+	}
+	step := totp.Step(time.Now())
+	enabled, err := sqlStore.Queries.EnableTwoFactor(context.Background(), db.EnableTwoFactorParams{
+		RecoveryCodeHashes: hashes,
+		LastUsedStep:       step,
+		UserID:             user.UserID,
+	})
+	require.NoError(t, err)
+	require.True(t, enabled.EnabledAt.Valid)
+	require.Equal(t, hashes, enabled.RecoveryCodeHashes)
+	require.Equal(t, step, enabled.LastUsedStep)
+	return enabled, codes
+}
+
+func TestStartTwoFactorEnrollment(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	user := createRandomUser(t)
+
+	first, err := sqlStore.Queries.StartTwoFactorEnrollment(context.Background(), db.StartTwoFactorEnrollmentParams{
+		UserID: user.UserID,
+		Secret: "JBSWY3DPEHPK3PXP",
+	})
+	require.NoError(t, err)
+
+	//? An enrollment that was never confirmed is replaced
+	second, err := sqlStore.Queries.StartTwoFactorEnrollment(context.Background(), db.StartTwoFactorEnrollmentParams{
+		UserID: user.UserID,
+		Secret: "KRSXG5CTMVRXEZLU",
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, first.Secret, second.Secret)
+
+	//? An enabled second factor is left alone
+	enabled, _ := createEnabledTwoFactor(t)
+	_, err = sqlStore.Queries.StartTwoFactorEnrollment(context.Background(), db.StartTwoFactorEnrollmentParams{
+		UserID: enabled.UserID,
+		Secret: "KRSXG5CTMVRXEZLU",
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	stored, err := sqlStore.Queries.GetTwoFactor(context.Background(), enabled.UserID)
+	require.NoError(t, err)
+	require.Equal(t, enabled.Secret, stored.Secret)
+	defer CleanupDB(t)
+}
+
+func TestUseTwoFactorStep(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	enabled, _ := createEnabledTwoFactor(t)
+
+	used, err := sqlStore.Queries.UseTwoFactorStep(context.Background(), db.UseTwoFactorStepParams{
+		Step:   enabled.LastUsedStep + 1,
+		UserID: enabled.UserID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, enabled.LastUsedStep+1, used.LastUsedStep)
+
+	//? A code is accepted once, and never one of an earlier step
+	for _, step := range []int64{used.LastUsedStep, used.LastUsedStep - 1} {
+		_, err = sqlStore.Queries.UseTwoFactorStep(context.Background(), db.UseTwoFactorStepParams{
+			Step:   step,
+			UserID: enabled.UserID,
+		})
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+	}
+	defer CleanupDB(t)
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	enabled, codes := createEnabledTwoFactor(t)
+
+	used, err := sqlStore.Queries.UseRecoveryCode(context.Background(), db.UseRecoveryCodeParams{
+		CodeHash: totp.HashRecoveryCode(codes[0]),
+		UserID:   enabled.UserID,
+	})
+	require.NoError(t, err)
+	require.Len(t, used.RecoveryCodeHashes, len(codes)-1)
+	require.NotContains(t, used.RecoveryCodeHashes, totp.HashRecoveryCode(codes[0]))
+
+	//? A recovery code works once
+	_, err = sqlStore.Queries.UseRecoveryCode(context.Background(), db.UseRecoveryCodeParams{
+		CodeHash: totp.HashRecoveryCode(codes[0]),
+		UserID:   enabled.UserID,
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	//? Codes are matched however they are typed
+	_, err = sqlStore.Queries.UseRecoveryCode(context.Background(), db.UseRecoveryCodeParams{
+		CodeHash: totp.HashRecoveryCode(" " + strings.ToUpper(codes[1]) + " "),
+		UserID:   enabled.UserID,
+	})
+	require.NoError(t, err)
+	defer CleanupDB(t)
+}
+
+func TestRecordTwoFactorFailure(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	enabled, _ := createEnabledTwoFactor(t)
+	windowStart := sql.NullTime{Time: time.Now().Add(-15 * time.Minute), Valid: true}
+
+	var failed db.UserTwoFactor
+	var err error
+	for range 3 {
+		failed, err = sqlStore.Queries.RecordTwoFactorFailure(context.Background(), db.RecordTwoFactorFailureParams{
+			WindowStart: windowStart,
+			UserID:      enabled.UserID,
+		})
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 3, failed.FailedAttempts)
+	require.True(t, failed.LastFailedAt.Valid)
+
+	//? Failures from before the window are forgotten
+	failed, err = sqlStore.Queries.RecordTwoFactorFailure(context.Background(), db.RecordTwoFactorFailureParams{
+		WindowStart: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true},
+		UserID:      enabled.UserID,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, failed.FailedAttempts)
+
+	//? An accepted code clears the count
+	used, err := sqlStore.Queries.UseTwoFactorStep(context.Background(), db.UseTwoFactorStepParams{
+		Step:   enabled.LastUsedStep + 1,
+		UserID: enabled.UserID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, used.FailedAttempts)
+	defer CleanupDB(t)
+}
+
+func TestHasTransferredTo(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+	transfer := createRandomTransfer(t, "10.00")
+
+	known, err := sqlStore.Queries.HasTransferredTo(context.Background(), db.HasTransferredToParams{
+		UserID:      transfer.FromAccount.UserID,
+		ToAccountID: transfer.ToAccount.AccountID,
+	})
+	require.NoError(t, err)
+	require.True(t, known)
+
+	//? The receiver never sent anything back
+	known, err = sqlStore.Queries.HasTransferredTo(context.Background(), db.HasTransferredToParams{
+		UserID:      transfer.ToAccount.UserID,
+		ToAccountID: transfer.FromAccount.AccountID,
+	})
+	require.NoError(t, err)
+	require.False(t, known)
+	defer CleanupDB(t)
+}
@@ -54,6 +54,7 @@ func createRandomUser(t *testing.T) db.User {
 	require.Equal(t, arg.ProfileImageUrl, user.ProfileImageUrl)
 
 	require.True(t, user.IsActive)
+	require.Equal(t, db.UserRoleCUSTOMER, user.Role)
 	require.NotZero(t, user.UserID)
 	require.NotZero(t, user.CreatedAt)
 	require.NotZero(t, user.UpdatedAt)
@@ -233,6 +234,27 @@ func TestUpdateUserNullFields(t *testing.T) {
 	defer CleanupDB(t)
 }
 
+// ! TestUpdateLastLogin => validates that the last login timestamp
+// ! of a user is updated to the given time.
+func TestUpdateLastLogin(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+
+	user1 := createRandomUser(t)
+	loginTime := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	err := sqlStore.Queries.UpdateLastLogin(context.Background(), db.UpdateLastLoginParams{
+		LastLogin: loginTime,
+		UserID:    user1.UserID,
+	})
+	require.NoError(t, err)
+
+	user2, err := sqlStore.Queries.GetUser(context.Background(), user1.UserID)
+	require.NoError(t, err)
+	require.WithinDuration(t, loginTime, user2.LastLogin, time.Second)
+
+	defer CleanupDB(t)
+}
+
 // ! TestDeleteUser => validates the soft deletion of a user.
 // ! It creates a user, soft deletes it, and verifies that the user
 // ! still exists but is marked as inactive.
@@ -269,3 +291,26 @@ func TestHardDeleteUser(t *testing.T) {
 
 	defer CleanupDB(t)
 }
+
+// ! TestUpdateUserRole => validates that a user's role is changed and that
+// ! GetUserAccess reads the new role.
+func TestUpdateUserRole(t *testing.T) {
+	sqlStore := SetupTestStore(t)
+
+	user1 := createRandomUser(t)
+
+	user2, err := sqlStore.Queries.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{
+		Role:   db.UserRoleAUDITOR,
+		UserID: user1.UserID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user1.UserID, user2.UserID)
+	require.Equal(t, db.UserRoleAUDITOR, user2.Role)
+
+	access, err := sqlStore.Queries.GetUserAccess(context.Background(), user1.UserID)
+	require.NoError(t, err)
+	require.Equal(t, db.UserRoleAUDITOR, access.Role)
+	require.True(t, access.IsActive)
+
+	defer CleanupDB(t)
+}
@@ -0,0 +1,101 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/config"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrAmountPrecision = errors.New("amount has more decimal places than the currency allows")
+)
+
+// currencyScale looks up the scale of a currency in the registry, it registers the minor units it
+// reads so the API checks amounts against the same scale as the ledger
+func currencyScale(ctx context.Context, q *db.Queries, currencyCode string) (int32, error) {
+	currency, err := q.GetCurrency(ctx, currencyCode)
+	if err != nil {
+		return 0, fmt.Errorf("error getting currency %s: %w", currencyCode, err)
+	}
+	money.RegisterMinorUnits(currency.CurrencyCode, currency.MinorUnits)
+	return money.Scale(currency.CurrencyCode), nil
+}
+
+// checkAmountScale refuses an amount written with more decimals than its currency has, so no
+// amount is ever rounded on its way into the ledger. It returns the scale of the currency.
+func checkAmountScale(ctx context.Context, q *db.Queries, currencyCode string, amount pgtype.Numeric) (int32, error) {
+	scale, err := currencyScale(ctx, q, currencyCode)
+	if err != nil {
+		return 0, err
+	}
+	return scale, checkDecimals(currencyCode, numericToDecimal(amount), scale)
+}
+
+// CheckAmount returns ErrAmountPrecision when amount has more decimals than its currency allows
+func CheckAmount(ctx context.Context, currencyCode string, amount pgtype.Numeric) error {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	_, err = checkAmountScale(ctx, store.Queries, currencyCode, amount)
+	return err
+}
+
+// checkDecimals refuses a value of a currency that has more than scale decimals
+func checkDecimals(currencyCode string, value decimal.Decimal, scale int32) error {
+	if !value.Equal(value.Truncate(scale)) {
+		return fmt.Errorf("%w: %s amounts have at most %d decimals", ErrAmountPrecision, currencyCode, scale)
+	}
+	return nil
+}
+
+// SeedCurrencies adds the ISO 4217 currencies missing from the registry and brings the minor units
+// of the registered ones in line with ISO 4217. The config.DefaultCurrencies are added active with
+// their starting rate, every other currency is added inactive and without a rate. The minor units
+// of every currency are then registered with the money package.
+func SeedCurrencies(ctx context.Context) (int64, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	var arg db.SeedCurrenciesParams
+	for _, currency := range iso4217.Currencies {
+		rate := pgtype.Numeric{Status: pgtype.Null}
+		defaultRate, active := config.DefaultCurrencies[currency.Code]
+		if active {
+			if rate, err = decimalToNumeric(decimal.NewFromFloat(defaultRate), ExchangeRateScale); err != nil {
+				return 0, err
+			}
+		}
+		arg.CurrencyCodes = append(arg.CurrencyCodes, currency.Code)
+		arg.CurrencyNames = append(arg.CurrencyNames, currency.Name)
+		arg.Symbols = append(arg.Symbols, currency.Symbol)
+		arg.MinorUnits = append(arg.MinorUnits, currency.MinorUnits)
+		arg.IsActive = append(arg.IsActive, active)
+		arg.ExchangeRates = append(arg.ExchangeRates, rate)
+	}
+
+	seeded, err := store.SeedCurrencies(ctx, arg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to seed currencies: %w", err)
+	}
+
+	currencies, err := store.ListAllCurrencies(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list currencies: %w", err)
+	}
+	for _, currency := range currencies {
+		money.RegisterMinorUnits(currency.CurrencyCode, currency.MinorUnits)
+	}
+	return seeded, nil
+}
@@ -0,0 +1,186 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+)
+
+// Rounding rules for currency conversion. Both the applied rate and the converted amount are
+// rounded half to even, to the scale of the column they are stored in.
+const (
+	// ExchangeRateScale matches exchange_rate NUMERIC(20, 10)
+	ExchangeRateScale = 10
+	// AmountScale matches the DECIMAL(16, 3) amount and balance columns
+	AmountScale = money.MaxScale
+)
+
+var (
+	ErrCurrencyMismatch        = errors.New("transfer currency must match the sender account currency")
+	ErrExchangeRateUnavailable = errors.New("no exchange rate available for the currency pair")
+)
+
+// transferLegs holds what each side of a transfer is booked with, in its own account currency
+type transferLegs struct {
+	DebitAmount    pgtype.Numeric
+	DebitCurrency  string
+	CreditAmount   pgtype.Numeric
+	CreditCurrency string
+	ExchangeRate   pgtype.Numeric
+	// DebitScale is the number of decimals amounts in the debit currency are kept to
+	DebitScale int32
+}
+
+// resolveTransferLegs works out the debit and credit amounts of a transfer. The amount is in the
+// sender's currency and may not have more decimals than it; when the receiver holds another currency it is converted with arg.ExchangeRate
+// if given, otherwise with the rates valid at arg.RateAt when set, or the current rates stored in
+// account_currencies.
+func resolveTransferLegs(ctx context.Context, q *db.Queries, arg schemas.TransferTxParams) (transferLegs, error) {
+	sender, err := q.GetAccount(ctx, arg.SenderAccountID)
+	if err != nil {
+		return transferLegs{}, fmt.Errorf("error getting sender account: %w", err)
+	}
+	receiver, err := q.GetAccount(ctx, arg.ReceiverAccountID)
+	if err != nil {
+		return transferLegs{}, fmt.Errorf("error getting receiver account: %w", err)
+	}
+	if arg.CurrencyCode != "" && arg.CurrencyCode != sender.CurrencyCode {
+		return transferLegs{}, ErrCurrencyMismatch
+	}
+	if err := ensureDebitable(ctx, q, sender.AccountID); err != nil {
+		return transferLegs{}, err
+	}
+	debitScale, err := checkAmountScale(ctx, q, sender.CurrencyCode, arg.Amount)
+	if err != nil {
+		return transferLegs{}, err
+	}
+
+	legs := transferLegs{
+		DebitAmount:    arg.Amount,
+		DebitCurrency:  sender.CurrencyCode,
+		CreditAmount:   arg.Amount,
+		CreditCurrency: receiver.CurrencyCode,
+		DebitScale:     debitScale,
+	}
+
+	if sender.CurrencyCode == receiver.CurrencyCode {
+		legs.ExchangeRate, err = decimalToNumeric(decimal.NewFromInt(1), ExchangeRateScale)
+		return legs, err
+	}
+
+	var rate decimal.Decimal
+	switch {
+	case arg.ExchangeRate.Status == pgtype.Present:
+		rate = numericToDecimal(arg.ExchangeRate)
+	case !arg.RateAt.IsZero():
+		rate, err = historicalExchangeRate(ctx, q, sender.CurrencyCode, receiver.CurrencyCode, arg.RateAt)
+	default:
+		rate, err = storedExchangeRate(ctx, q, sender.CurrencyCode, receiver.CurrencyCode)
+	}
+	if err != nil {
+		return transferLegs{}, err
+	}
+	rate = rate.RoundBank(ExchangeRateScale)
+	if !rate.IsPositive() {
+		return transferLegs{}, ErrExchangeRateUnavailable
+	}
+
+	legs.ExchangeRate, err = decimalToNumeric(rate, ExchangeRateScale)
+	if err != nil {
+		return transferLegs{}, err
+	}
+	creditScale, err := currencyScale(ctx, q, receiver.CurrencyCode)
+	if err != nil {
+		return transferLegs{}, err
+	}
+	legs.CreditAmount, err = ConvertAmount(arg.Amount, rate, creditScale)
+	if err != nil {
+		return transferLegs{}, err
+	}
+	return legs, nil
+}
+
+// storedExchangeRate returns the rate from one currency to another. account_currencies stores the
+// value of one unit of each currency in the base currency, so the pair rate is from / to.
+func storedExchangeRate(ctx context.Context, q *db.Queries, fromCurrency, toCurrency string) (decimal.Decimal, error) {
+	from, err := q.GetCurrency(ctx, fromCurrency)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("error getting currency %s: %w", fromCurrency, err)
+	}
+	to, err := q.GetCurrency(ctx, toCurrency)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("error getting currency %s: %w", toCurrency, err)
+	}
+	return pairRate(from.ExchangeRate, to.ExchangeRate)
+}
+
+// historicalExchangeRate returns the rate from one currency to another that was valid at a given
+// time, from the rates kept in exchange_rate_history
+func historicalExchangeRate(ctx context.Context, q *db.Queries, fromCurrency, toCurrency string, at time.Time) (decimal.Decimal, error) {
+	from, err := exchangeRateAt(ctx, q, fromCurrency, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	to, err := exchangeRateAt(ctx, q, toCurrency, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return pairRate(from, to)
+}
+
+// exchangeRateAt returns the value of a currency in the base currency at a given time
+func exchangeRateAt(ctx context.Context, q *db.Queries, currency string, at time.Time) (pgtype.Numeric, error) {
+	history, err := q.GetExchangeRateAt(ctx, db.GetExchangeRateAtParams{CurrencyCode: currency, ValidAt: at})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.Numeric{}, ErrExchangeRateUnavailable
+	}
+	if err != nil {
+		return pgtype.Numeric{}, fmt.Errorf("error getting %s rate at %s: %w", currency, at.Format(time.RFC3339), err)
+	}
+	return history.Rate, nil
+}
+
+// pairRate turns the base currency values of two currencies into the rate from one to the other
+func pairRate(from, to pgtype.Numeric) (decimal.Decimal, error) {
+	if from.Status != pgtype.Present || to.Status != pgtype.Present {
+		return decimal.Zero, ErrExchangeRateUnavailable
+	}
+
+	fromRate := numericToDecimal(from)
+	toRate := numericToDecimal(to)
+	if !fromRate.IsPositive() || !toRate.IsPositive() {
+		return decimal.Zero, ErrExchangeRateUnavailable
+	}
+	// Keep extra digits so the single rounding step happens on the final rate
+	return fromRate.DivRound(toRate, ExchangeRateScale+4), nil
+}
+
+// ConvertAmount converts an amount with the given rate, rounding half to even to the scale of the
+// target currency
+func ConvertAmount(amount pgtype.Numeric, rate decimal.Decimal, scale int32) (pgtype.Numeric, error) {
+	converted := numericToDecimal(amount).Mul(rate).RoundBank(scale)
+	return decimalToNumeric(converted, scale)
+}
+
+func numericToDecimal(num pgtype.Numeric) decimal.Decimal {
+	if num.Status != pgtype.Present || num.Int == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(num.Int, num.Exp)
+}
+
+func decimalToNumeric(value decimal.Decimal, scale int32) (pgtype.Numeric, error) {
+	var num pgtype.Numeric
+	if err := num.Set(value.StringFixed(scale)); err != nil {
+		return num, fmt.Errorf("error converting %s to numeric: %w", value.String(), err)
+	}
+	return num, nil
+}
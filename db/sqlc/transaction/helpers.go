@@ -10,6 +10,7 @@ import (
 	db "github.com/riad/banksystemendtoend/db/sqlc"
 	"github.com/riad/banksystemendtoend/util/config"
 	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/iso4217"
 )
 
 var ctx = context.Background()
@@ -59,7 +60,8 @@ func CreateTransactionStatus(transactionStatus string) (db.TransactionStatus, er
 	return status, nil
 }
 
-// CreateCurrencyCode creates a new currency or returns an existing one.
+// CreateCurrencyCode returns a currency of the registry, adding it from the ISO 4217 data when it
+// is missing. Codes that are neither registered nor ISO 4217 currencies are refused.
 func CreateCurrencyCode(currencyCode string) (db.AccountCurrency, error) {
 	store, err := db.GetSQLStore(setup.GetStore())
 	if err != nil {
@@ -67,61 +69,26 @@ func CreateCurrencyCode(currencyCode string) (db.AccountCurrency, error) {
 	}
 
 	existingCurrency, err := store.Queries.GetCurrency(context.Background(), currencyCode)
-	if err == nil && existingCurrency.CurrencyCode == currencyCode {
+	if err == nil {
 		return existingCurrency, nil
 	}
 
-	var currencyName, symbol sql.NullString
-	var rate sql.NullFloat64
-
-	currencies := map[string]struct {
-		name   string
-		symbol string
-		rate   float64
-	}{
-		config.TransactionCurrencies.USD.CODE: {
-			name:   config.TransactionCurrencies.USD.NAME,
-			symbol: config.TransactionCurrencies.USD.SYMBOL,
-			rate:   config.TransactionCurrencies.USD.RATE,
-		},
-		config.TransactionCurrencies.EUR.CODE: {
-			name:   config.TransactionCurrencies.EUR.NAME,
-			symbol: config.TransactionCurrencies.EUR.SYMBOL,
-			rate:   config.TransactionCurrencies.EUR.RATE,
-		},
-		config.TransactionCurrencies.GBP.CODE: {
-			name:   config.TransactionCurrencies.GBP.NAME,
-			symbol: config.TransactionCurrencies.GBP.SYMBOL,
-			rate:   config.TransactionCurrencies.GBP.RATE,
-		},
-		config.TransactionCurrencies.JPY.CODE: {
-			name:   config.TransactionCurrencies.JPY.NAME,
-			symbol: config.TransactionCurrencies.JPY.SYMBOL,
-			rate:   config.TransactionCurrencies.JPY.RATE,
-		},
-	}
-
-	if currency, ok := currencies[currencyCode]; ok {
-		currencyName = sql.NullString{String: currency.name, Valid: true}
-		symbol = sql.NullString{String: currency.symbol, Valid: true}
-		rate = sql.NullFloat64{Float64: currency.rate, Valid: true}
-	} else {
-		currencyName = sql.NullString{String: config.TransactionCurrencies.USD.NAME, Valid: true}
-		symbol = sql.NullString{String: config.TransactionCurrencies.USD.SYMBOL, Valid: true}
-		rate = sql.NullFloat64{Float64: config.TransactionCurrencies.USD.RATE, Valid: true}
-	}
-
-	var exchangeRate pgtype.Numeric
-	err = exchangeRate.Set(rate.Float64)
-	if err != nil {
-		return db.AccountCurrency{}, fmt.Errorf("failed to convert exchange rate: %v", err)
+	iso, ok := iso4217.Lookup(currencyCode)
+	if !ok {
+		return db.AccountCurrency{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, currencyCode)
 	}
 
 	arg := db.CreateCurrencyParams{
-		CurrencyCode: currencyCode,
-		CurrencyName: currencyName.String,
-		Symbol:       sql.NullString{String: symbol.String, Valid: true},
-		ExchangeRate: exchangeRate,
+		CurrencyCode: iso.Code,
+		CurrencyName: iso.Name,
+		Symbol:       sql.NullString{String: iso.Symbol, Valid: true},
+		MinorUnits:   sql.NullInt16{Int16: iso.MinorUnits, Valid: true},
+		ExchangeRate: pgtype.Numeric{Status: pgtype.Null},
+	}
+	if rate, ok := config.DefaultCurrencies[iso.Code]; ok {
+		if err := arg.ExchangeRate.Set(rate); err != nil {
+			return db.AccountCurrency{}, fmt.Errorf("failed to convert exchange rate: %v", err)
+		}
 	}
 
 	currency, err := store.Queries.CreateCurrency(context.Background(), arg)
@@ -154,6 +121,7 @@ func CreateTransactionType(transactionType string) (db.TransactionType, error) {
 		config.TransactionTypes.ADJUSTMENT: "Account balance adjustment",
 		config.TransactionTypes.FEE:        "Service or transaction fee",
 		config.TransactionTypes.INTEREST:   "Interest earned or charged",
+		config.TransactionTypes.REVERSAL:   "Full reversal of a previous transaction",
 	}
 
 	arg := db.CreateTransactionTypeParams{
@@ -173,3 +141,40 @@ func CreateTransactionType(transactionType string) (db.TransactionType, error) {
 	}
 	return transType, nil
 }
+
+// CreateAccountType creates a new account type or returns an existing one.
+func CreateAccountType(accountType string) (db.AccountType, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return db.AccountType{}, fmt.Errorf("failed to get SQL store: %v", err)
+	}
+
+	existingType, err := store.Queries.GetAccountType(context.Background(), accountType)
+	if err == nil && existingType.AccountType == accountType {
+		return existingType, nil
+	}
+
+	descriptions := map[string]string{
+		config.AccountTypes.SAVINGS:       "Savings account",
+		config.AccountTypes.CHECKING:      "Checking account",
+		config.AccountTypes.FIXED_DEPOSIT: "Fixed term deposit, locked until maturity",
+		config.AccountTypes.MONEY_MARKET:  "Money market account",
+	}
+
+	description, ok := descriptions[accountType]
+	if !ok {
+		description = accountType
+	}
+	accType, err := store.Queries.CreateAccountType(context.Background(), db.CreateAccountTypeParams{
+		AccountType: accountType,
+		Description: description,
+	})
+	if err != nil {
+		return db.AccountType{}, fmt.Errorf("failed to create account type: %v", err)
+	}
+	return accType, nil
+}
+
+func sqlNullInt32(value int32) sql.NullInt32 {
+	return sql.NullInt32{Int32: value, Valid: true}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"math/big"
 	"time"
 
 	"github.com/jackc/pgtype"
@@ -13,61 +12,81 @@ import (
 	"github.com/riad/banksystemendtoend/util/schemas"
 )
 
-func createTransferEntries(ctx context.Context, q *db.Queries, arg schemas.TransferTxParams) (fromEntry db.Entry, toEntry db.Entry, err error) {
-	debitAmount := NegateNumeric(arg.Amount)
+func createTransferEntries(ctx context.Context, q *db.Queries, transactionID int32,
+	arg schemas.TransferTxParams, legs transferLegs) (fromEntry db.Entry, toEntry db.Entry, err error) {
 
 	fromEntry, err = q.CreateEntry(ctx, db.CreateEntryParams{
-		AccountID: sql.NullInt32{Int32: arg.SenderAccountID, Valid: true},
-		Amount:    debitAmount,
+		AccountID:     sql.NullInt32{Int32: arg.SenderAccountID, Valid: true},
+		Amount:        NegateNumeric(legs.DebitAmount),
+		TransactionID: sql.NullInt32{Int32: transactionID, Valid: true},
+		CurrencyCode:  sql.NullString{String: legs.DebitCurrency, Valid: true},
+		ExchangeRate:  legs.ExchangeRate,
 	})
 	if err != nil {
-		return fromEntry, toEntry, fmt.Errorf("error creating debit entry: %v", err)
+		return fromEntry, toEntry, fmt.Errorf("error creating debit entry: %w", err)
 	}
 
 	toEntry, err = q.CreateEntry(ctx, db.CreateEntryParams{
-		AccountID: sql.NullInt32{Int32: arg.ReceiverAccountID, Valid: true},
-		Amount:    arg.Amount,
+		AccountID:     sql.NullInt32{Int32: arg.ReceiverAccountID, Valid: true},
+		Amount:        legs.CreditAmount,
+		TransactionID: sql.NullInt32{Int32: transactionID, Valid: true},
+		CurrencyCode:  sql.NullString{String: legs.CreditCurrency, Valid: true},
+		ExchangeRate:  legs.ExchangeRate,
 	})
 	if err != nil {
-		return fromEntry, toEntry, fmt.Errorf("error creating credit entry: %v", err)
+		return fromEntry, toEntry, fmt.Errorf("error creating credit entry: %w", err)
 	}
 	return fromEntry, toEntry, nil
 }
 
-func createTransferTransaction(ctx context.Context, q *db.Queries, arg schemas.TransferTxParams) (db.Transaction, error) {
+func createTransferTransaction(ctx context.Context, q *db.Queries, arg schemas.TransferTxParams, legs transferLegs) (db.Transaction, error) {
+	referenceNumber := arg.ReferenceNumber
+	if referenceNumber == "" {
+		referenceNumber = common.RandomString(10)
+	}
+
 	transaction, err := q.CreateTransaction(ctx, db.CreateTransactionParams{
 		FromAccountID:   sql.NullInt32{Int32: arg.SenderAccountID, Valid: true},
 		ToAccountID:     sql.NullInt32{Int32: arg.ReceiverAccountID, Valid: true},
 		TypeCode:        arg.TypeCode,
-		Amount:          arg.Amount,
-		CurrencyCode:    arg.CurrencyCode,
-		ExchangeRate:    pgtype.Numeric{Int: big.NewInt(1), Status: pgtype.Present},
+		Amount:          legs.DebitAmount,
+		CurrencyCode:    legs.DebitCurrency,
+		ExchangeRate:    legs.ExchangeRate,
 		StatusCode:      arg.StatusCode,
 		Description:     sql.NullString{String: arg.Description, Valid: true},
-		ReferenceNumber: sql.NullString{String: common.RandomString(10), Valid: true},
+		ReferenceNumber: sql.NullString{String: referenceNumber, Valid: true},
 		TransactionDate: time.Now(),
+		ConvertedAmount: legs.CreditAmount,
+		ConvertedCurrencyCode: sql.NullString{
+			String: legs.CreditCurrency,
+			Valid:  legs.CreditCurrency != legs.DebitCurrency,
+		},
+		OriginalTransactionID: sql.NullInt32{
+			Int32: arg.OriginalTransactionID,
+			Valid: arg.OriginalTransactionID != 0,
+		},
 	})
 	if err != nil {
-		return db.Transaction{}, fmt.Errorf("error creating transfer transaction: %v", err)
+		return db.Transaction{}, fmt.Errorf("error creating transfer transaction: %w", err)
 	}
 	return transaction, nil
 }
 
 func updateAccountBalances(ctx context.Context, q *db.Queries,
-	arg schemas.TransferTxParams) (senderAccount db.Account, receiverAccount db.Account, err error) {
+	arg schemas.TransferTxParams, legs transferLegs) (senderAccount db.Account, receiverAccount db.Account, err error) {
 
 	if arg.SenderAccountID < arg.ReceiverAccountID {
 		senderAccount, receiverAccount, err = addMoney(ctx, q,
-			arg.SenderAccountID, NegateNumeric(arg.Amount),
-			arg.ReceiverAccountID, arg.Amount)
+			arg.SenderAccountID, NegateNumeric(legs.DebitAmount),
+			arg.ReceiverAccountID, legs.CreditAmount)
 	} else {
 		receiverAccount, senderAccount, err = addMoney(ctx, q,
-			arg.ReceiverAccountID, arg.Amount,
-			arg.SenderAccountID, NegateNumeric(arg.Amount))
+			arg.ReceiverAccountID, legs.CreditAmount,
+			arg.SenderAccountID, NegateNumeric(legs.DebitAmount))
 	}
 
 	if err != nil {
-		return senderAccount, receiverAccount, fmt.Errorf("error updating account balances: %v", err)
+		return senderAccount, receiverAccount, fmt.Errorf("error updating account balances: %w", err)
 	}
 
 	return senderAccount, receiverAccount, nil
@@ -82,7 +101,7 @@ func addMoney(ctx context.Context, q *db.Queries,
 		Amount:    amount1,
 	})
 	if err != nil {
-		return account1, account2, fmt.Errorf("error updating first account balance: %v", err)
+		return account1, account2, fmt.Errorf("error updating first account balance: %w", err)
 	}
 
 	account2, err = q.UpdateAccountBalance(ctx, db.UpdateAccountBalanceParams{
@@ -90,7 +109,7 @@ func addMoney(ctx context.Context, q *db.Queries,
 		Amount:    amount2,
 	})
 	if err != nil {
-		return account1, account2, fmt.Errorf("error updating second account balance: %v", err)
+		return account1, account2, fmt.Errorf("error updating second account balance: %w", err)
 	}
 
 	return account1, account2, nil
@@ -0,0 +1,97 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	setup "github.com/riad/banksystemendtoend/util/db"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key does not exist")
+	ErrAPIKeyInactive = errors.New("API key is revoked or expired")
+)
+
+// IssueAPIKey stores arg as a new API key owned by the user with username
+func IssueAPIKey(ctx context.Context, username string, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return db.ApiKey{}, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	owner, err := store.GetUserByUsername(ctx, username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.ApiKey{}, ErrUserNotFound
+	}
+	if err != nil {
+		return db.ApiKey{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	arg.OwnerUserID = owner.UserID
+	key, err := store.CreateAPIKey(ctx, arg)
+	if err != nil {
+		return db.ApiKey{}, fmt.Errorf("failed to create API key: %w", err)
+	}
+	return key, nil
+}
+
+// RotateAPIKey stores next as the replacement of the key apiKeyID, with the same owner, name, scopes
+// and signing requirement. Without an expiry of its own next is valid as long as the old key was
+// issued for. The old key keeps working until overlapEnd, or until it expires if that is sooner, so
+// clients can switch keys without downtime. It returns the new key and the old key as updated.
+func RotateAPIKey(ctx context.Context, apiKeyID int32, next db.CreateAPIKeyParams, overlapEnd time.Time) (db.ApiKey, db.ApiKey, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return db.ApiKey{}, db.ApiKey{}, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	var rotated, previous db.ApiKey
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		current, err := q.GetAPIKeyForUpdate(ctx, apiKeyID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAPIKeyNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+		if current.RevokedAt.Valid || (current.ExpiresAt.Valid && !time.Now().Before(current.ExpiresAt.Time)) {
+			return ErrAPIKeyInactive
+		}
+
+		next.Name = current.Name
+		next.OwnerUserID = current.OwnerUserID
+		next.Scopes = current.Scopes
+		next.SigningRequired = current.SigningRequired
+		next.RotatedFrom = sql.NullInt32{Int32: current.ApiKeyID, Valid: true}
+		if !next.ExpiresAt.Valid && current.ExpiresAt.Valid {
+			lifetime := current.ExpiresAt.Time.Sub(current.CreatedAt)
+			next.ExpiresAt = sql.NullTime{Time: time.Now().Add(lifetime), Valid: true}
+		}
+		rotated, err = q.CreateAPIKey(ctx, next)
+		if err != nil {
+			return fmt.Errorf("failed to create API key: %w", err)
+		}
+
+		if !current.ExpiresAt.Valid || overlapEnd.Before(current.ExpiresAt.Time) {
+			current.ExpiresAt = sql.NullTime{Time: overlapEnd, Valid: true}
+			err = q.SetAPIKeyExpiry(ctx, db.SetAPIKeyExpiryParams{
+				ExpiresAt: current.ExpiresAt,
+				ApiKeyID:  current.ApiKeyID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set API key expiry: %w", err)
+			}
+		}
+		previous = current
+		return nil
+	})
+	if err != nil {
+		return db.ApiKey{}, db.ApiKey{}, err
+	}
+	return rotated, previous, nil
+}
@@ -0,0 +1,372 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/config"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+var (
+	ErrBatchAccountNotFound = errors.New("sender or receiver account does not exist")
+	ErrBatchAccountInactive = errors.New("sender or receiver account is not active")
+	ErrBatchAccountNotOwned = errors.New("sender account does not belong to the user who submitted the batch")
+)
+
+// batchStaleAfter is how long a processing batch may go without progress before another worker takes it over
+const batchStaleAfter = 10 * time.Minute
+
+// CreateTransferBatch stores a batch together with its items. A batch created for an upload
+// without items is left UPLOADED until ImportTransferBatch adds them.
+func CreateTransferBatch(ctx context.Context, arg schemas.CreateTransferBatchParams) (db.TransferBatch, error) {
+	var batch db.TransferBatch
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return batch, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	status := db.BatchStatusPENDING
+	if arg.UploadID != "" && len(arg.Items) == 0 {
+		status = db.BatchStatusUPLOADED
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		batch, err = q.CreateTransferBatch(ctx, db.CreateTransferBatchParams{
+			Mode:            arg.Mode,
+			Status:          status,
+			ReferenceNumber: sql.NullString{String: arg.ReferenceNumber, Valid: arg.ReferenceNumber != ""},
+			UploadID:        sql.NullString{String: arg.UploadID, Valid: arg.UploadID != ""},
+			TotalItems:      int32(len(arg.Items)),
+			UserID:          sql.NullInt32{Int32: arg.UserID, Valid: arg.UserID != 0},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create batch: %w", err)
+		}
+		return createBatchItems(ctx, q, batch.BatchID, arg.Items)
+	})
+	if err != nil {
+		return batch, fmt.Errorf("create transfer batch failed: %w", err)
+	}
+	return batch, nil
+}
+
+// ImportTransferBatch adds the items read from an uploaded file to the batch waiting for it.
+// Importing the same upload twice leaves the batch untouched, so queue redeliveries are harmless.
+func ImportTransferBatch(ctx context.Context, uploadID string, items []schemas.TransferBatchItemParams) (db.TransferBatch, error) {
+	var batch db.TransferBatch
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return batch, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		batch, err = q.GetTransferBatchByUploadForUpdate(ctx, sql.NullString{String: uploadID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("error getting batch: %w", err)
+		}
+		if batch.Status != db.BatchStatusUPLOADED {
+			return nil
+		}
+		if err := createBatchItems(ctx, q, batch.BatchID, items); err != nil {
+			return err
+		}
+		batch, err = q.MarkTransferBatchImported(ctx, db.MarkTransferBatchImportedParams{
+			TotalItems: int32(len(items)),
+			BatchID:    batch.BatchID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to mark batch imported: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return batch, fmt.Errorf("import transfer batch failed: %w", err)
+	}
+	return batch, nil
+}
+
+// FailTransferBatchImport closes a batch whose uploaded file could not be imported
+func FailTransferBatchImport(ctx context.Context, uploadID string, reason string) (db.TransferBatch, error) {
+	var batch db.TransferBatch
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return batch, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		batch, err = q.GetTransferBatchByUploadForUpdate(ctx, sql.NullString{String: uploadID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("error getting batch: %w", err)
+		}
+		if batch.Status != db.BatchStatusUPLOADED {
+			return nil
+		}
+		batch, err = q.CompleteTransferBatch(ctx, db.CompleteTransferBatchParams{
+			Status:       db.BatchStatusFAILED,
+			ErrorMessage: sql.NullString{String: reason, Valid: true},
+			BatchID:      batch.BatchID,
+		})
+		return err
+	})
+	if err != nil {
+		return batch, fmt.Errorf("fail transfer batch import failed: %w", err)
+	}
+	return batch, nil
+}
+
+// ProcessNextTransferBatch claims the oldest pending batch and executes its items. It returns false
+// when there was nothing to process. ALL_OR_NOTHING batches run every item in a single database
+// transaction; BEST_EFFORT batches run each item through TransferTx and record its own outcome.
+// Every item is booked under a reference derived from the batch and item index, so a batch taken
+// over from a crashed worker never pays an item twice.
+func ProcessNextTransferBatch(ctx context.Context) (db.TransferBatch, bool, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return db.TransferBatch{}, false, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	// TransferTx expects the reference rows to exist already
+	if _, err := CreateTransactionType(config.TransactionTypes.TRANSFER); err != nil {
+		return db.TransferBatch{}, false, err
+	}
+	if _, err := CreateTransactionStatus(config.TransactionStatuses.PENDING); err != nil {
+		return db.TransferBatch{}, false, err
+	}
+
+	batch, err := store.ClaimTransferBatch(ctx, time.Now().Add(-batchStaleAfter))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.TransferBatch{}, false, nil
+	}
+	if err != nil {
+		return db.TransferBatch{}, false, fmt.Errorf("failed to claim batch: %w", err)
+	}
+
+	items, err := store.ListPendingTransferBatchItems(ctx, batch.BatchID)
+	if err != nil {
+		return batch, true, fmt.Errorf("failed to list batch items: %w", err)
+	}
+
+	if batch.Mode == db.BatchModeALLORNOTHING {
+		err = runAllOrNothingBatch(ctx, store, batch, items)
+	} else {
+		err = runBestEffortBatch(ctx, store, batch, items)
+	}
+	if err != nil {
+		return batch, true, err
+	}
+
+	batch, err = finishTransferBatch(ctx, store.Queries, batch)
+	return batch, true, err
+}
+
+func runBestEffortBatch(ctx context.Context, store *db.SQLStore, batch db.TransferBatch, items []db.TransferBatchItem) error {
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			// Left PROCESSING, another worker resumes it once it goes stale
+			return err
+		}
+
+		reference := sql.NullString{String: batchItemReference(batch, item), Valid: true}
+		transaction, err := store.GetTransactionByReference(ctx, reference)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if err = validateBatchItem(ctx, store.Queries, batch, item); err == nil {
+				var result schemas.TransferTxResult
+				result, err = TransferTx(ctx, batchItemTransfer(item, reference.String))
+				transaction = result.Transaction
+			}
+		} else if err != nil {
+			return fmt.Errorf("failed to look up batch item reference: %w", err)
+		}
+		if err != nil && isTransientBatchError(err) {
+			return fmt.Errorf("batch item %d: %w", item.ItemIndex, err)
+		}
+
+		if err := recordBatchItem(ctx, store.Queries, item, transaction, err); err != nil {
+			return err
+		}
+		// Also serves as the heartbeat that keeps the claim alive
+		if _, err := store.RefreshTransferBatchProgress(ctx, batch.BatchID); err != nil {
+			return fmt.Errorf("failed to update batch progress: %w", err)
+		}
+	}
+	return nil
+}
+
+func runAllOrNothingBatch(ctx context.Context, store *db.SQLStore, batch db.TransferBatch, items []db.TransferBatchItem) error {
+	var failedItem *db.TransferBatchItem
+	var failure error
+
+	err := store.ExecTx(ctx, func(q *db.Queries) error {
+		for i := range items {
+			item := items[i]
+			if err := validateBatchItem(ctx, q, batch, item); err != nil {
+				failedItem, failure = &item, err
+				return err
+			}
+			result, err := transfer(ctx, q, batchItemTransfer(item, batchItemReference(batch, item)))
+			if err != nil {
+				failedItem, failure = &item, err
+				return err
+			}
+			if err := recordBatchItem(ctx, q, item, result.Transaction, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+	//? Left PROCESSING on errors that say nothing about the items, another worker retries the batch
+	// once it goes stale
+	if failedItem == nil || isTransientBatchError(failure) {
+		return fmt.Errorf("all-or-nothing batch failed: %w", err)
+	}
+
+	// Nothing was booked, the failing item carries the reason and the others point at it
+	for _, item := range items {
+		itemErr := fmt.Errorf("batch rolled back: item %d failed", failedItem.ItemIndex)
+		if item.ItemID == failedItem.ItemID {
+			itemErr = failure
+		}
+		if err := recordBatchItem(ctx, store.Queries, item, db.Transaction{}, itemErr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isTransientBatchError reports whether err comes from the database or the connection rather than
+// from an item, such as a deadlock, a serialization failure or a lost connection. Such errors fail
+// no item, the batch is retried instead.
+func isTransientBatchError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// 40001 serialization_failure, 40P01 deadlock_detected, class 08 connection exceptions and
+		// class 57 operator interventions such as a server shutdown
+		return pgErr.Code == "40001" || pgErr.Code == "40P01" ||
+			strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "57")
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || pgconn.Timeout(err) || pgconn.SafeToRetry(err) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// finishTransferBatch refreshes the counters and closes the batch according to its item outcomes
+func finishTransferBatch(ctx context.Context, q *db.Queries, batch db.TransferBatch) (db.TransferBatch, error) {
+	batch, err := q.RefreshTransferBatchProgress(ctx, batch.BatchID)
+	if err != nil {
+		return batch, fmt.Errorf("failed to update batch progress: %w", err)
+	}
+
+	status := db.BatchStatusPARTIALLYCOMPLETED
+	switch {
+	case batch.FailedItems == 0:
+		status = db.BatchStatusCOMPLETED
+	case batch.SucceededItems == 0:
+		status = db.BatchStatusFAILED
+	}
+
+	batch, err = q.CompleteTransferBatch(ctx, db.CompleteTransferBatchParams{
+		Status:  status,
+		BatchID: batch.BatchID,
+	})
+	if err != nil {
+		return batch, fmt.Errorf("failed to complete batch: %w", err)
+	}
+	return batch, nil
+}
+
+func createBatchItems(ctx context.Context, q *db.Queries, batchID int32, items []schemas.TransferBatchItemParams) error {
+	for i, item := range items {
+		_, err := q.CreateTransferBatchItem(ctx, db.CreateTransferBatchItemParams{
+			BatchID:       batchID,
+			ItemIndex:     int32(i + 1),
+			FromAccountID: item.FromAccountID,
+			ToAccountID:   item.ToAccountID,
+			Amount:        item.Amount,
+			CurrencyCode:  strings.ToUpper(item.CurrencyCode),
+			Description:   sql.NullString{String: item.Description, Valid: item.Description != ""},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create batch item %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validateBatchItem applies the account checks the transfer API makes before calling TransferTx
+func validateBatchItem(ctx context.Context, q *db.Queries, batch db.TransferBatch, item db.TransferBatchItem) error {
+	for _, accountID := range []int32{item.FromAccountID, item.ToAccountID} {
+		account, err := q.GetAccount(ctx, accountID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrBatchAccountNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error getting account: %w", err)
+		}
+		if !account.IsActive {
+			return ErrBatchAccountInactive
+		}
+		if accountID == item.FromAccountID && batch.UserID.Valid && account.UserID != batch.UserID.Int32 {
+			return ErrBatchAccountNotOwned
+		}
+	}
+	return nil
+}
+
+func recordBatchItem(ctx context.Context, q *db.Queries, item db.TransferBatchItem,
+	transaction db.Transaction, itemErr error) error {
+
+	arg := db.UpdateTransferBatchItemResultParams{
+		Status: db.BatchItemStatusSUCCEEDED,
+		ItemID: item.ItemID,
+	}
+	if itemErr != nil {
+		arg.Status = db.BatchItemStatusFAILED
+		arg.ErrorMessage = sql.NullString{String: itemErr.Error(), Valid: true}
+	} else {
+		arg.TransactionID = sql.NullInt32{Int32: transaction.TransactionID, Valid: true}
+	}
+	if err := q.UpdateTransferBatchItemResult(ctx, arg); err != nil {
+		return fmt.Errorf("failed to record batch item %d: %w", item.ItemIndex, err)
+	}
+	return nil
+}
+
+func batchItemTransfer(item db.TransferBatchItem, reference string) schemas.TransferTxParams {
+	return schemas.TransferTxParams{
+		SenderAccountID:   item.FromAccountID,
+		ReceiverAccountID: item.ToAccountID,
+		Amount:            item.Amount,
+		CurrencyCode:      item.CurrencyCode,
+		TypeCode:          config.TransactionTypes.TRANSFER,
+		StatusCode:        config.TransactionStatuses.PENDING,
+		Description:       item.Description.String,
+		ReferenceNumber:   reference,
+	}
+}
+
+// batchItemReference identifies one item of a batch, it fits reference_number's 50 characters
+func batchItemReference(batch db.TransferBatch, item db.TransferBatchItem) string {
+	return fmt.Sprintf("BAT-%s-%d", strings.ReplaceAll(batch.BatchNumber.String(), "-", ""), item.ItemIndex)
+}
@@ -0,0 +1,113 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/exchangerate"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+)
+
+// RecordExchangeRates adds the rates fetched from a provider to exchange_rate_history and moves
+// the current rate in account_currencies to the latest rate that is already valid. A rate equal
+// to the one already valid at the same time is not recorded again, so the history only grows
+// when a rate changes.
+func RecordExchangeRates(ctx context.Context, source string, rates []exchangerate.Rate) (schemas.ExchangeRateRefresh, error) {
+	result := schemas.ExchangeRateRefresh{Source: source}
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		result = schemas.ExchangeRateRefresh{Source: source}
+		var recorded []string
+		seen := map[string]bool{}
+
+		for _, rate := range rates {
+			if _, err := q.GetCurrency(ctx, rate.CurrencyCode); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					result.Skipped = append(result.Skipped, rate.CurrencyCode)
+					continue
+				}
+				return fmt.Errorf("error getting currency %s: %w", rate.CurrencyCode, err)
+			}
+			value := rate.Rate.RoundBank(ExchangeRateScale)
+			if !value.IsPositive() {
+				result.Skipped = append(result.Skipped, rate.CurrencyCode)
+				continue
+			}
+
+			current, err := exchangeRateAt(ctx, q, rate.CurrencyCode, rate.ValidFrom)
+			switch {
+			case err == nil && numericToDecimal(current).Equal(value):
+				result.Unchanged++
+				continue
+			case err != nil && !errors.Is(err, ErrExchangeRateUnavailable):
+				return err
+			}
+
+			numeric, err := decimalToNumeric(value, ExchangeRateScale)
+			if err != nil {
+				return err
+			}
+			_, err = q.CreateExchangeRateHistory(ctx, db.CreateExchangeRateHistoryParams{
+				CurrencyCode: rate.CurrencyCode,
+				Rate:         numeric,
+				Source:       source,
+				ValidFrom:    rate.ValidFrom,
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Another rate is already recorded for exactly this time
+				result.Unchanged++
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("error recording %s rate: %w", rate.CurrencyCode, err)
+			}
+			result.Recorded++
+			if !seen[rate.CurrencyCode] {
+				seen[rate.CurrencyCode] = true
+				recorded = append(recorded, rate.CurrencyCode)
+			}
+		}
+
+		for _, currency := range recorded {
+			updated, err := q.SyncCurrentExchangeRate(ctx, currency)
+			if err != nil {
+				return fmt.Errorf("error updating current %s rate: %w", currency, err)
+			}
+			result.Updated += updated
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("exchange rate refresh failed: %w", err)
+	}
+	return result, nil
+}
+
+// ExchangeRateAt returns the rate from one currency to another that was valid at a given time,
+// rounded to ExchangeRateScale. It returns ErrExchangeRateUnavailable when either currency had no
+// rate yet.
+func ExchangeRateAt(ctx context.Context, fromCurrency, toCurrency string, at time.Time) (decimal.Decimal, error) {
+	if fromCurrency == toCurrency {
+		return decimal.NewFromInt(1), nil
+	}
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	rate, err := historicalExchangeRate(ctx, store.Queries, fromCurrency, toCurrency, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return rate.RoundBank(ExchangeRateScale), nil
+}
@@ -0,0 +1,255 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/config"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+)
+
+// ErrFeeIncomeAccountMissing is returned when a fee is due in a currency without a usable fee income account
+var ErrFeeIncomeAccountMissing = errors.New("no fee income account is configured for the currency")
+
+var hundred = decimal.NewFromInt(100)
+
+// chargeFeeParams describes one fee to book from an account into the fee income account
+type chargeFeeParams struct {
+	AccountID    int32
+	FeeType      db.FeeType
+	Amount       decimal.Decimal
+	CurrencyCode string
+	Description  string
+	// RelatedTransactionID is the transfer a transfer fee was charged for
+	RelatedTransactionID int32
+	// PeriodStart is the day or month a periodic fee pays for
+	PeriodStart time.Time
+}
+
+// TransferFees works out the fees the schedule charges for sending amount, in the sender's currency,
+// rounded to scale decimals. Fees that round to zero are left out.
+func TransferFees(schedule db.FeeSchedule, amount decimal.Decimal, crossCurrency bool, scale int32) []schemas.Fee {
+	var fees []schemas.Fee
+
+	transferFee := numericToDecimal(schedule.TransferFeeFlat).
+		Add(amount.Mul(numericToDecimal(schedule.TransferFeePercent)).Div(hundred)).
+		RoundBank(scale)
+	if transferFee.IsPositive() {
+		fees = append(fees, schemas.Fee{Type: db.FeeTypeTRANSFER, Amount: transferFee})
+	}
+
+	if crossCurrency {
+		markup := amount.Mul(numericToDecimal(schedule.FxMarkupPercent)).Div(hundred).RoundBank(scale)
+		if markup.IsPositive() {
+			fees = append(fees, schemas.Fee{Type: db.FeeTypeFXMARKUP, Amount: markup})
+		}
+	}
+	return fees
+}
+
+// PreviewTransferFees returns what a transfer would debit, credit and cost in fees, without booking it
+func PreviewTransferFees(ctx context.Context, arg schemas.TransferTxParams) (schemas.FeePreview, error) {
+	var preview schemas.FeePreview
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return preview, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	legs, err := resolveTransferLegs(ctx, store.Queries, arg)
+	if err != nil {
+		return preview, fmt.Errorf("failed to resolve transfer amounts: %w", err)
+	}
+
+	preview = schemas.FeePreview{
+		DebitAmount:    numericToDecimal(legs.DebitAmount),
+		DebitCurrency:  legs.DebitCurrency,
+		CreditAmount:   numericToDecimal(legs.CreditAmount),
+		CreditCurrency: legs.CreditCurrency,
+		ExchangeRate:   numericToDecimal(legs.ExchangeRate),
+		TotalFees:      decimal.Zero,
+	}
+
+	schedule, err := store.GetActiveFeeScheduleByAccount(ctx, arg.SenderAccountID)
+	switch {
+	case err == nil:
+		preview.Fees = TransferFees(schedule, preview.DebitAmount, legs.DebitCurrency != legs.CreditCurrency, legs.DebitScale)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return preview, fmt.Errorf("failed to get fee schedule: %w", err)
+	}
+
+	for _, fee := range preview.Fees {
+		preview.TotalFees = preview.TotalFees.Add(fee.Amount)
+	}
+	preview.TotalDebit = preview.DebitAmount.Add(preview.TotalFees)
+	return preview, nil
+}
+
+// chargeTransferFees books the fees of a completed transfer on q, as part of the transfer's database
+// transaction. result.FromAccount is refreshed to include them.
+func chargeTransferFees(ctx context.Context, q *db.Queries, result *schemas.TransferTxResult, legs transferLegs) error {
+	transfer := result.Transaction
+
+	schedule, err := q.GetActiveFeeScheduleByAccount(ctx, transfer.FromAccountID.Int32)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get fee schedule: %w", err)
+	}
+
+	fees := TransferFees(schedule, numericToDecimal(legs.DebitAmount), legs.DebitCurrency != legs.CreditCurrency, legs.DebitScale)
+	for _, fee := range fees {
+		charge, feeResult, err := chargeFee(ctx, q, chargeFeeParams{
+			AccountID:            transfer.FromAccountID.Int32,
+			FeeType:              fee.Type,
+			Amount:               fee.Amount,
+			CurrencyCode:         legs.DebitCurrency,
+			Description:          fmt.Sprintf("%s fee for transaction %s", fee.Type, transfer.TransactionNumber),
+			RelatedTransactionID: transfer.TransactionID,
+		})
+		if err != nil {
+			return err
+		}
+		result.Fees = append(result.Fees, charge)
+		result.FromAccount = feeResult.FromAccount
+	}
+	return nil
+}
+
+// chargeFee books a FEE transaction from the account into the fee income account of the currency
+// and records the charge
+func chargeFee(ctx context.Context, q *db.Queries, arg chargeFeeParams) (db.FeeCharge, schemas.TransferTxResult, error) {
+	income, err := q.GetFeeIncomeAccount(ctx, arg.CurrencyCode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.FeeCharge{}, schemas.TransferTxResult{}, fmt.Errorf("%w: %s", ErrFeeIncomeAccountMissing, arg.CurrencyCode)
+		}
+		return db.FeeCharge{}, schemas.TransferTxResult{}, fmt.Errorf("failed to get fee income account: %w", err)
+	}
+	incomeAccount, err := q.GetAccount(ctx, income.AccountID)
+	if err != nil {
+		return db.FeeCharge{}, schemas.TransferTxResult{}, fmt.Errorf("failed to get fee income account: %w", err)
+	}
+	if incomeAccount.CurrencyCode != arg.CurrencyCode || income.AccountID == arg.AccountID {
+		return db.FeeCharge{}, schemas.TransferTxResult{}, fmt.Errorf("%w: %s", ErrFeeIncomeAccountMissing, arg.CurrencyCode)
+	}
+
+	if _, err := CreateTransactionType(config.TransactionTypes.FEE); err != nil {
+		return db.FeeCharge{}, schemas.TransferTxResult{}, err
+	}
+	// Fees are configured per account type, whatever the currency, so they are rounded to it here
+	scale, err := currencyScale(ctx, q, arg.CurrencyCode)
+	if err != nil {
+		return db.FeeCharge{}, schemas.TransferTxResult{}, err
+	}
+	amount, err := decimalToNumeric(arg.Amount.RoundBank(scale), scale)
+	if err != nil {
+		return db.FeeCharge{}, schemas.TransferTxResult{}, err
+	}
+
+	result, err := transfer(ctx, q, schemas.TransferTxParams{
+		SenderAccountID:   arg.AccountID,
+		ReceiverAccountID: income.AccountID,
+		Amount:            amount,
+		CurrencyCode:      arg.CurrencyCode,
+		TypeCode:          config.TransactionTypes.FEE,
+		StatusCode:        config.TransactionStatuses.COMPLETED,
+		Description:       arg.Description,
+		ReferenceNumber:   feeReference(arg),
+	})
+	if err != nil {
+		return db.FeeCharge{}, result, fmt.Errorf("failed to book %s fee: %w", arg.FeeType, err)
+	}
+
+	charge, err := q.CreateFeeCharge(ctx, db.CreateFeeChargeParams{
+		AccountID:     arg.AccountID,
+		FeeType:       arg.FeeType,
+		Amount:        amount,
+		CurrencyCode:  arg.CurrencyCode,
+		TransactionID: result.Transaction.TransactionID,
+		RelatedTransactionID: sql.NullInt32{
+			Int32: arg.RelatedTransactionID,
+			Valid: arg.RelatedTransactionID != 0,
+		},
+		PeriodStart: sql.NullTime{Time: arg.PeriodStart, Valid: !arg.PeriodStart.IsZero()},
+	})
+	if err != nil {
+		return db.FeeCharge{}, result, fmt.Errorf("failed to record %s fee: %w", arg.FeeType, err)
+	}
+	return charge, result, nil
+}
+
+// feeReference is unique per fee: transfer fees by their transfer, periodic fees by account and period
+func feeReference(arg chargeFeeParams) string {
+	if arg.RelatedTransactionID != 0 {
+		return fmt.Sprintf("FEE-%s-%d", arg.FeeType, arg.RelatedTransactionID)
+	}
+	return fmt.Sprintf("FEE-%s-%d-%s", arg.FeeType, arg.AccountID, arg.PeriodStart.Format("20060102"))
+}
+
+// RunPeriodicFees charges the maintenance fee of the month day falls in and the overdraft fee of
+// day itself. Every charge commits on its own and a charged period is never charged again, so the
+// run can be repeated; a charge the account cannot afford is reported and retried next time.
+func RunPeriodicFees(ctx context.Context, day time.Time) (schemas.PeriodicFeeResult, error) {
+	var result schemas.PeriodicFeeResult
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	day = interest.Date(day)
+	monthStart := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	maintenance, err := store.ListAccountsDueMaintenanceFee(ctx, monthStart)
+	if err != nil {
+		return result, fmt.Errorf("failed to list accounts due a maintenance fee: %w", err)
+	}
+	for _, due := range maintenance {
+		chargePeriodicFee(ctx, store, &result, chargeFeeParams{
+			AccountID:    due.AccountID,
+			FeeType:      db.FeeTypeMAINTENANCE,
+			Amount:       numericToDecimal(due.Amount),
+			CurrencyCode: due.CurrencyCode,
+			Description:  fmt.Sprintf("Monthly maintenance fee for %s", monthStart.Format("January 2006")),
+			PeriodStart:  monthStart,
+		})
+	}
+
+	overdrafts, err := store.ListAccountsDueOverdraftFee(ctx, day)
+	if err != nil {
+		return result, fmt.Errorf("failed to list accounts due an overdraft fee: %w", err)
+	}
+	for _, due := range overdrafts {
+		chargePeriodicFee(ctx, store, &result, chargeFeeParams{
+			AccountID:    due.AccountID,
+			FeeType:      db.FeeTypeOVERDRAFT,
+			Amount:       numericToDecimal(due.Amount),
+			CurrencyCode: due.CurrencyCode,
+			Description:  fmt.Sprintf("Overdraft fee for %s", day.Format(time.DateOnly)),
+			PeriodStart:  day,
+		})
+	}
+	return result, nil
+}
+
+func chargePeriodicFee(ctx context.Context, store *db.SQLStore, result *schemas.PeriodicFeeResult, arg chargeFeeParams) {
+	err := store.ExecTx(ctx, func(q *db.Queries) error {
+		_, _, err := chargeFee(ctx, q, arg)
+		return err
+	})
+	if err != nil {
+		result.Failures = append(result.Failures, fmt.Errorf("account %d: %w", arg.AccountID, err))
+		return
+	}
+	result.Charged++
+}
@@ -0,0 +1,365 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/config"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrFixedDepositLocked    = errors.New("account holds a fixed deposit that has not matured yet")
+	ErrFixedDepositNotActive = errors.New("fixed deposit has already matured or been broken")
+	ErrFixedDepositMatured   = errors.New("fixed deposit has reached maturity and is paid out by the maturity job")
+	ErrFixedDepositTerm      = errors.New("no fixed deposit is offered for this term")
+)
+
+// ensureDebitable refuses debits from an account whose fixed deposit is still running
+func ensureDebitable(ctx context.Context, q *db.Queries, accountID int32) error {
+	locked, err := q.IsAccountLockedByFixedDeposit(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("error checking fixed deposit lock: %w", err)
+	}
+	if locked {
+		return ErrFixedDepositLocked
+	}
+	return nil
+}
+
+// OpenFixedDeposit opens a FIXED_DEPOSIT account next to the linked account and moves the principal
+// into it. The term starts today (UTC) and the deposit stays locked until its maturity date. The
+// interest rate and early break penalty are those configured for the term in fixed_deposit_terms.
+func OpenFixedDeposit(ctx context.Context, arg schemas.OpenFixedDepositParams) (schemas.FixedDepositTxResult, error) {
+	var result schemas.FixedDepositTxResult
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	if _, err := CreateAccountType(config.AccountTypes.FIXED_DEPOSIT); err != nil {
+		return result, err
+	}
+	if _, err := CreateTransactionType(config.TransactionTypes.DEPOSIT); err != nil {
+		return result, err
+	}
+	if _, err := CreateTransactionStatus(config.TransactionStatuses.COMPLETED); err != nil {
+		return result, err
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		term, err := q.GetFixedDepositTerm(ctx, arg.TermMonths)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && !term.IsActive) {
+			return ErrFixedDepositTerm
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get fixed deposit term: %w", err)
+		}
+
+		linked, err := q.GetAccount(ctx, arg.LinkedAccountID)
+		if err != nil {
+			return fmt.Errorf("failed to get linked account: %w", err)
+		}
+
+		var overdraftLimit pgtype.Numeric
+		if err := overdraftLimit.Set("0"); err != nil {
+			return err
+		}
+		account, err := q.CreateAccount(ctx, db.CreateAccountParams{
+			UserID:         linked.UserID,
+			AccountNumber:  arg.AccountNumber,
+			AccountType:    config.AccountTypes.FIXED_DEPOSIT,
+			CurrencyCode:   linked.CurrencyCode,
+			InterestRate:   term.InterestRate,
+			OverdraftLimit: overdraftLimit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open fixed deposit account: %w", err)
+		}
+
+		start := interest.Date(time.Now())
+		result.Deposit, err = q.CreateFixedDeposit(ctx, db.CreateFixedDepositParams{
+			AccountID:                account.AccountID,
+			LinkedAccountID:          linked.AccountID,
+			Principal:                arg.Principal,
+			InterestRate:             term.InterestRate,
+			TermMonths:               arg.TermMonths,
+			StartDate:                start,
+			MaturityDate:             interest.AddMonths(start, int(arg.TermMonths)),
+			MaturityInstruction:      arg.MaturityInstruction,
+			EarlyBreakPenaltyPercent: term.EarlyBreakPenaltyPercent,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create fixed deposit: %w", err)
+		}
+
+		funding, err := transfer(ctx, q, schemas.TransferTxParams{
+			SenderAccountID:   linked.AccountID,
+			ReceiverAccountID: account.AccountID,
+			Amount:            arg.Principal,
+			CurrencyCode:      linked.CurrencyCode,
+			TypeCode:          config.TransactionTypes.DEPOSIT,
+			StatusCode:        config.TransactionStatuses.COMPLETED,
+			Description:       fmt.Sprintf("Fixed deposit %s", result.Deposit.DepositNumber),
+			ReferenceNumber:   arg.ReferenceNumber,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fund fixed deposit: %w", err)
+		}
+
+		result.Transaction = funding.Transaction
+		result.Account = funding.ToAccount
+		result.LinkedAccount = funding.FromAccount
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("open fixed deposit failed: %w", err)
+	}
+	return result, nil
+}
+
+// BreakFixedDeposit ends a deposit before its maturity date. It earns no interest, the penalty is
+// charged to the fee income account and the rest of the balance goes back to the linked account.
+func BreakFixedDeposit(ctx context.Context, depositNumber uuid.UUID) (schemas.FixedDepositTxResult, error) {
+	var result schemas.FixedDepositTxResult
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	if _, err := CreateTransactionType(config.TransactionTypes.WITHDRAWAL); err != nil {
+		return result, err
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		deposit, err := lockActiveFixedDeposit(ctx, q, depositNumber)
+		if err != nil {
+			return err
+		}
+		if !interest.Date(time.Now()).Before(deposit.MaturityDate) {
+			return ErrFixedDepositMatured
+		}
+
+		if _, err := q.CloseFixedDeposit(ctx, db.CloseFixedDepositParams{
+			DepositID: deposit.DepositID,
+			Status:    db.FixedDepositStatusBROKEN,
+		}); err != nil {
+			return fmt.Errorf("failed to close fixed deposit: %w", err)
+		}
+
+		account, err := q.GetAccount(ctx, deposit.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to get fixed deposit account: %w", err)
+		}
+		scale, err := currencyScale(ctx, q, account.CurrencyCode)
+		if err != nil {
+			return err
+		}
+		balance := numericToDecimal(account.Balance)
+		penalty := decimal.Min(balance, numericToDecimal(deposit.Principal).
+			Mul(numericToDecimal(deposit.EarlyBreakPenaltyPercent)).Div(hundred).RoundBank(scale))
+
+		if penalty.IsPositive() {
+			charge, _, err := chargeFee(ctx, q, chargeFeeParams{
+				AccountID:    account.AccountID,
+				FeeType:      db.FeeTypeEARLYBREAK,
+				Amount:       penalty,
+				CurrencyCode: account.CurrencyCode,
+				Description:  fmt.Sprintf("Early break of fixed deposit %s", deposit.DepositNumber),
+				PeriodStart:  deposit.StartDate,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to charge early break penalty: %w", err)
+			}
+			result.Penalty = &charge
+		}
+
+		return payOutFixedDeposit(ctx, q, &result, deposit, balance.Sub(penalty), "BREAK")
+	})
+	if err != nil {
+		return result, fmt.Errorf("break fixed deposit failed: %w", err)
+	}
+	return result, nil
+}
+
+// MatureFixedDeposits credits the interest of every deposit that matured on or before asOf, then
+// pays it out or rolls it over as instructed. Each deposit commits on its own; it returns the
+// number of deposits processed.
+func MatureFixedDeposits(ctx context.Context, asOf time.Time, limit int32) (int, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	for _, transactionType := range []string{config.TransactionTypes.INTEREST, config.TransactionTypes.WITHDRAWAL} {
+		if _, err := CreateTransactionType(transactionType); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := CreateTransactionStatus(config.TransactionStatuses.COMPLETED); err != nil {
+		return 0, err
+	}
+
+	asOf = interest.Date(asOf)
+	deposits, err := store.ListMaturedFixedDeposits(ctx, db.ListMaturedFixedDepositsParams{
+		MaturityDate: asOf,
+		Limit:        limit,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list matured fixed deposits: %w", err)
+	}
+
+	matured := 0
+	for _, depositNumber := range deposits {
+		err := store.ExecTx(ctx, func(q *db.Queries) error {
+			_, err := matureFixedDeposit(ctx, q, depositNumber, asOf)
+			return err
+		})
+		if err != nil {
+			return matured, fmt.Errorf("failed to mature fixed deposit %s: %w", depositNumber, err)
+		}
+		matured++
+	}
+	return matured, nil
+}
+
+// matureFixedDeposit credits the interest of the term that ended and applies the maturity instruction
+func matureFixedDeposit(ctx context.Context, q *db.Queries, depositNumber uuid.UUID,
+	asOf time.Time) (schemas.FixedDepositTxResult, error) {
+
+	var result schemas.FixedDepositTxResult
+
+	deposit, err := lockActiveFixedDeposit(ctx, q, depositNumber)
+	if err != nil {
+		return result, err
+	}
+	if deposit.MaturityDate.After(asOf) {
+		return result, nil
+	}
+
+	account, err := q.GetAccount(ctx, deposit.AccountID)
+	if err != nil {
+		return result, fmt.Errorf("failed to get fixed deposit account: %w", err)
+	}
+
+	scale, err := currencyScale(ctx, q, account.CurrencyCode)
+	if err != nil {
+		return result, err
+	}
+	earned := interest.SimpleInterest(numericToDecimal(deposit.Principal), numericToDecimal(deposit.InterestRate),
+		deposit.StartDate, deposit.MaturityDate).RoundBank(scale)
+	if earned.IsPositive() {
+		amount, err := decimalToNumeric(earned, scale)
+		if err != nil {
+			return result, err
+		}
+		credited, err := creditInterest(ctx, q, account, amount,
+			fmt.Sprintf("Interest of fixed deposit %s for the term ending %s",
+				deposit.DepositNumber, deposit.MaturityDate.Format(time.DateOnly)),
+			fmt.Sprintf("FD-INT-%s-%s", deposit.DepositNumber, deposit.MaturityDate.Format("20060102")))
+		if err != nil {
+			return result, err
+		}
+		result.Interest = &credited
+	}
+	balance := numericToDecimal(account.Balance).Add(earned)
+
+	if deposit.MaturityInstruction == db.MaturityInstructionROLLOVER {
+		principal, err := decimalToNumeric(balance, AmountScale)
+		if err != nil {
+			return result, err
+		}
+		result.Deposit, err = q.RollOverFixedDeposit(ctx, db.RollOverFixedDepositParams{
+			DepositID:    deposit.DepositID,
+			Principal:    principal,
+			StartDate:    deposit.MaturityDate,
+			MaturityDate: interest.AddMonths(deposit.MaturityDate, int(deposit.TermMonths)),
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to roll over fixed deposit: %w", err)
+		}
+		result.Account, err = q.GetAccount(ctx, deposit.AccountID)
+		if err != nil {
+			return result, fmt.Errorf("failed to get fixed deposit account: %w", err)
+		}
+		return result, nil
+	}
+
+	if _, err := q.CloseFixedDeposit(ctx, db.CloseFixedDepositParams{
+		DepositID: deposit.DepositID,
+		Status:    db.FixedDepositStatusMATURED,
+	}); err != nil {
+		return result, fmt.Errorf("failed to close fixed deposit: %w", err)
+	}
+	return result, payOutFixedDeposit(ctx, q, &result, deposit, balance, "PAYOUT")
+}
+
+// payOutFixedDeposit moves what is left of a closed deposit to its linked account and deactivates
+// the deposit account. reason, PAYOUT or BREAK, names the payout in its reference.
+func payOutFixedDeposit(ctx context.Context, q *db.Queries, result *schemas.FixedDepositTxResult,
+	deposit db.FixedDeposit, amount decimal.Decimal, reason string) error {
+
+	var err error
+	if amount.IsPositive() {
+		payout, err := decimalToNumeric(amount, AmountScale)
+		if err != nil {
+			return err
+		}
+		transferred, err := transfer(ctx, q, schemas.TransferTxParams{
+			SenderAccountID:   deposit.AccountID,
+			ReceiverAccountID: deposit.LinkedAccountID,
+			Amount:            payout,
+			TypeCode:          config.TransactionTypes.WITHDRAWAL,
+			StatusCode:        config.TransactionStatuses.COMPLETED,
+			Description:       fmt.Sprintf("Fixed deposit %s %s", deposit.DepositNumber, strings.ToLower(reason)),
+			ReferenceNumber:   fmt.Sprintf("FD-%s-%s", reason, deposit.DepositNumber),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to pay out fixed deposit: %w", err)
+		}
+		result.Transaction = transferred.Transaction
+		result.LinkedAccount = transferred.ToAccount
+
+		deposit, err = q.SetFixedDepositPayout(ctx, db.SetFixedDepositPayoutParams{
+			DepositID:           deposit.DepositID,
+			PayoutTransactionID: sqlNullInt32(transferred.Transaction.TransactionID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record fixed deposit payout: %w", err)
+		}
+	}
+
+	if err = q.DeleteAccount(ctx, deposit.AccountID); err != nil {
+		return fmt.Errorf("failed to deactivate fixed deposit account: %w", err)
+	}
+	result.Deposit, err = q.GetFixedDeposit(ctx, deposit.DepositNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get fixed deposit: %w", err)
+	}
+	result.Account, err = q.GetAccount(ctx, deposit.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get fixed deposit account: %w", err)
+	}
+	return nil
+}
+
+// lockActiveFixedDeposit locks a deposit for the rest of the database transaction, it must still be running
+func lockActiveFixedDeposit(ctx context.Context, q *db.Queries, depositNumber uuid.UUID) (db.FixedDeposit, error) {
+	deposit, err := q.GetFixedDepositForUpdate(ctx, depositNumber)
+	if err != nil {
+		return deposit, fmt.Errorf("failed to lock fixed deposit: %w", err)
+	}
+	if deposit.Status != db.FixedDepositStatusACTIVE {
+		return deposit, ErrFixedDepositNotActive
+	}
+	return deposit, nil
+}
@@ -0,0 +1,297 @@
+package transaction
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/hashchain"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+// hashChainBatchSize is how many links are read at a time while walking a chain
+const hashChainBatchSize = 1000
+
+// ErrHashChainBroken is returned when a checkpoint is refused because the chain does not verify
+var ErrHashChainBroken = errors.New("hash chain is broken")
+
+// ErrUnknownHashChain is returned for chain names other than hashchain.Chains
+var ErrUnknownHashChain = errors.New("unknown hash chain")
+
+// chainLink is one row of a hash chain, whichever table it is stored in
+type chainLink struct {
+	seq      int64
+	recordID string
+	prevHash string
+	rowHash  string
+	payload  string
+}
+
+// chainTable reads the links of one chain and counts the rows missing from it
+type chainTable struct {
+	read      func(ctx context.Context, q *db.Queries, afterSeq int64) ([]chainLink, error)
+	unchained func(ctx context.Context, q *db.Queries) (int64, error)
+}
+
+var chainTables = map[string]chainTable{
+	hashchain.ChainAuditTrail: {
+		read: func(ctx context.Context, q *db.Queries, afterSeq int64) ([]chainLink, error) {
+			records, err := q.ListAuditTrailChain(ctx, db.ListAuditTrailChainParams{
+				AfterSeq: sql.NullInt64{Int64: afterSeq, Valid: true},
+				Limit:    hashChainBatchSize,
+			})
+			if err != nil {
+				return nil, err
+			}
+			links := make([]chainLink, 0, len(records))
+			for _, record := range records {
+				links = append(links, chainLink{
+					seq:      record.ChainSeq.Int64,
+					recordID: strconv.Itoa(int(record.AuditID)),
+					prevHash: record.PrevHash.String,
+					rowHash:  record.RowHash.String,
+					payload:  hashchain.AuditTrailPayload(record),
+				})
+			}
+			return links, nil
+		},
+		unchained: func(ctx context.Context, q *db.Queries) (int64, error) {
+			return q.CountUnchainedAuditTrail(ctx)
+		},
+	},
+	hashchain.ChainEntries: {
+		read: func(ctx context.Context, q *db.Queries, afterSeq int64) ([]chainLink, error) {
+			entries, err := q.ListEntriesChain(ctx, db.ListEntriesChainParams{
+				AfterSeq: sql.NullInt64{Int64: afterSeq, Valid: true},
+				Limit:    hashChainBatchSize,
+			})
+			if err != nil {
+				return nil, err
+			}
+			links := make([]chainLink, 0, len(entries))
+			for _, entry := range entries {
+				links = append(links, chainLink{
+					seq:      entry.ChainSeq.Int64,
+					recordID: strconv.FormatInt(entry.ID, 10),
+					prevHash: entry.PrevHash.String,
+					rowHash:  entry.RowHash.String,
+					payload:  hashchain.EntryPayload(entry),
+				})
+			}
+			return links, nil
+		},
+		unchained: func(ctx context.Context, q *db.Queries) (int64, error) {
+			return q.CountUnchainedEntries(ctx)
+		},
+	},
+}
+
+// chainWalk follows a chain link by link from a known position
+type chainWalk struct {
+	seq   int64
+	hash  string
+	links int64
+}
+
+// next checks that link follows on from the walk and moves the walk onto it
+func (w *chainWalk) next(link chainLink) *schemas.HashChainBreak {
+	switch {
+	case link.seq != w.seq+1:
+		reason := fmt.Sprintf("link %d is missing", w.seq+1)
+		if link.seq > w.seq+2 {
+			reason = fmt.Sprintf("links %d to %d are missing", w.seq+1, link.seq-1)
+		}
+		return &schemas.HashChainBreak{Seq: w.seq + 1, Reason: reason}
+	case link.prevHash != w.hash:
+		return &schemas.HashChainBreak{Seq: link.seq, RecordID: link.recordID,
+			Reason: "prev_hash does not match the row_hash of the previous link"}
+	case hashchain.Link(link.prevHash, link.seq, link.payload) != link.rowHash:
+		return &schemas.HashChainBreak{Seq: link.seq, RecordID: link.recordID,
+			Reason: "row content does not match its row_hash"}
+	}
+	w.seq, w.hash = link.seq, link.rowHash
+	w.links++
+	return nil
+}
+
+// walkChain walks the links after w up to and including the link at last, calling check on every
+// link that follows on. It stops at the first break.
+func walkChain(ctx context.Context, q *db.Queries, table chainTable, w *chainWalk, last int64,
+	check func(chainLink) *schemas.HashChainBreak) (*schemas.HashChainBreak, error) {
+	for w.seq < last {
+		links, err := table.read(ctx, q, w.seq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chain links: %w", err)
+		}
+		if len(links) == 0 {
+			return nil, nil
+		}
+		for _, link := range links {
+			if link.seq > last {
+				return nil, nil
+			}
+			if brk := w.next(link); brk != nil {
+				return brk, nil
+			}
+			if check != nil {
+				if brk := check(link); brk != nil {
+					return brk, nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// getHashChainHead returns the head of a chain, a chain nothing was written to yet is empty
+func getHashChainHead(ctx context.Context, q *db.Queries, chain string) (db.HashChainHead, error) {
+	head, err := q.GetHashChainHead(ctx, chain)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.HashChainHead{ChainName: chain, LastHash: hashchain.GenesisHash}, nil
+	}
+	if err != nil {
+		return head, fmt.Errorf("failed to get chain head: %w", err)
+	}
+	return head, nil
+}
+
+// headBreak reports a walk that did not end on the chain head
+func headBreak(w chainWalk, head db.HashChainHead) *schemas.HashChainBreak {
+	if w.seq < head.LastSeq {
+		return &schemas.HashChainBreak{Seq: w.seq + 1,
+			Reason: fmt.Sprintf("the chain ends at link %d but its head is at link %d", w.seq, head.LastSeq)}
+	}
+	if w.hash != head.LastHash {
+		return &schemas.HashChainBreak{Seq: w.seq, Reason: "the last link does not match the chain head"}
+	}
+	return nil
+}
+
+// VerifyHashChain walks a chain from its first link and reports the first link that does not hold:
+// a missing link, a changed row, a link that does not point at the one before it, a chain shorter
+// than its head, or a link that contradicts a signed checkpoint. Checkpoint signatures are checked
+// when publicKey is set. Links committed while the walk runs are left for the next one.
+func VerifyHashChain(ctx context.Context, chain string, publicKey ed25519.PublicKey) (schemas.HashChainVerification, error) {
+	result := schemas.HashChainVerification{Chain: chain}
+
+	table, ok := chainTables[chain]
+	if !ok {
+		return result, fmt.Errorf("%w: %s", ErrUnknownHashChain, chain)
+	}
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	// Checkpoints first, so none of them can be newer than the head
+	checkpoints, err := store.ListHashChainCheckpoints(ctx, chain)
+	if err != nil {
+		return result, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	result.Head, err = getHashChainHead(ctx, store.Queries, chain)
+	if err != nil {
+		return result, err
+	}
+
+	next := 0
+	checkCheckpoints := func(link chainLink) *schemas.HashChainBreak {
+		for ; next < len(checkpoints) && checkpoints[next].ChainSeq <= link.seq; next++ {
+			checkpoint := checkpoints[next]
+			if publicKey != nil && !hashchain.VerifyCheckpoint(publicKey, chain, checkpoint.ChainSeq,
+				checkpoint.RowHash, checkpoint.Signature) {
+				return &schemas.HashChainBreak{Seq: checkpoint.ChainSeq,
+					Reason: fmt.Sprintf("checkpoint %d has an invalid signature", checkpoint.CheckpointID)}
+			}
+			if checkpoint.ChainSeq != link.seq || checkpoint.RowHash != link.rowHash {
+				return &schemas.HashChainBreak{Seq: link.seq, RecordID: link.recordID,
+					Reason: fmt.Sprintf("link does not match signed checkpoint %d", checkpoint.CheckpointID)}
+			}
+			result.CheckpointsChecked++
+		}
+		return nil
+	}
+
+	walk := chainWalk{hash: hashchain.GenesisHash}
+	result.Break, err = walkChain(ctx, store.Queries, table, &walk, result.Head.LastSeq, checkCheckpoints)
+	result.LinksChecked = walk.links
+	if err != nil || result.Break != nil {
+		return result, err
+	}
+
+	if result.Break = headBreak(walk, result.Head); result.Break != nil {
+		return result, nil
+	}
+	if next < len(checkpoints) {
+		result.Break = &schemas.HashChainBreak{Seq: checkpoints[next].ChainSeq,
+			Reason: fmt.Sprintf("signed checkpoint %d is beyond the end of the chain", checkpoints[next].CheckpointID)}
+		return result, nil
+	}
+
+	unchained, err := table.unchained(ctx, store.Queries)
+	if err != nil {
+		return result, fmt.Errorf("failed to count unchained rows: %w", err)
+	}
+	if unchained > 0 {
+		result.Break = &schemas.HashChainBreak{Seq: walk.seq + 1,
+			Reason: fmt.Sprintf("%d rows were written without being linked into the chain", unchained)}
+	}
+	return result, nil
+}
+
+// CreateHashChainCheckpoints signs the head of every chain that moved since its last checkpoint.
+// The links added since that checkpoint are verified first, a broken chain is never signed.
+func CreateHashChainCheckpoints(ctx context.Context, signer *hashchain.Signer) ([]db.HashChainCheckpoint, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	var checkpoints []db.HashChainCheckpoint
+	for _, chain := range hashchain.Chains {
+		head, err := getHashChainHead(ctx, store.Queries, chain)
+		if err != nil {
+			return checkpoints, err
+		}
+
+		walk := chainWalk{hash: hashchain.GenesisHash}
+		latest, err := store.GetLatestHashChainCheckpoint(ctx, chain)
+		switch {
+		case err == nil:
+			walk.seq, walk.hash = latest.ChainSeq, latest.RowHash
+		case !errors.Is(err, pgx.ErrNoRows):
+			return checkpoints, fmt.Errorf("failed to get latest %s checkpoint: %w", chain, err)
+		}
+		if head.LastSeq <= walk.seq {
+			continue
+		}
+
+		brk, err := walkChain(ctx, store.Queries, chainTables[chain], &walk, head.LastSeq, nil)
+		if err != nil {
+			return checkpoints, err
+		}
+		if brk == nil {
+			brk = headBreak(walk, head)
+		}
+		if brk != nil {
+			return checkpoints, fmt.Errorf("%w: %s link %d: %s", ErrHashChainBroken, chain, brk.Seq, brk.Reason)
+		}
+
+		checkpoint, err := store.CreateHashChainCheckpoint(ctx, db.CreateHashChainCheckpointParams{
+			ChainName: chain,
+			ChainSeq:  head.LastSeq,
+			RowHash:   head.LastHash,
+			Signature: signer.Sign(chain, head.LastSeq, head.LastHash),
+		})
+		if err != nil {
+			return checkpoints, fmt.Errorf("failed to store %s checkpoint: %w", chain, err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, nil
+}
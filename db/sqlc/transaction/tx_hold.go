@@ -0,0 +1,298 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/config"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+var (
+	ErrHoldNotOpen        = errors.New("hold has already been captured, released or expired")
+	ErrHoldExpired        = errors.New("hold has expired")
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds the held amount")
+	ErrInvalidHoldAmount  = errors.New("hold amount must be greater than zero")
+)
+
+// AuthorizeHold reserves funds on an account without moving them. The reservation lowers the
+// available balance right away and is checked against the overdraft limit by accounts_balance_check.
+// The payment transaction is created with the PENDING status until the hold is captured.
+func AuthorizeHold(ctx context.Context, arg schemas.AuthorizeHoldParams) (schemas.HoldTxResult, error) {
+	var result schemas.HoldTxResult
+
+	if !numericToDecimal(arg.Amount).IsPositive() {
+		return result, ErrInvalidHoldAmount
+	}
+	store, err := prepareHoldStore(config.TransactionStatuses.PENDING)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		transferArg := schemas.TransferTxParams{
+			SenderAccountID:   arg.AccountID,
+			ReceiverAccountID: arg.MerchantAccountID,
+			Amount:            arg.Amount,
+			CurrencyCode:      arg.CurrencyCode,
+			TypeCode:          config.TransactionTypes.PAYMENT,
+			StatusCode:        config.TransactionStatuses.PENDING,
+			Description:       arg.Description,
+			ReferenceNumber:   arg.ReferenceNumber,
+		}
+
+		// Step 1: Price the payment, converted amounts are an estimate until capture
+		legs, err := resolveTransferLegs(ctx, q, transferArg)
+		if err != nil {
+			return fmt.Errorf("failed to resolve hold amounts: %w", err)
+		}
+
+		// Step 2: Reserve the funds
+		result.Account, err = q.UpdateAccountHeldAmount(ctx, db.UpdateAccountHeldAmountParams{
+			Amount:    legs.DebitAmount,
+			AccountID: arg.AccountID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reserve funds: %w", err)
+		}
+
+		// Step 3: Record the pending payment and the hold
+		result.Transaction, err = createTransferTransaction(ctx, q, transferArg, legs)
+		if err != nil {
+			return fmt.Errorf("failed to create pending transaction: %w", err)
+		}
+		result.Hold, err = q.CreateHold(ctx, db.CreateHoldParams{
+			AccountID:         arg.AccountID,
+			MerchantAccountID: arg.MerchantAccountID,
+			TransactionID:     result.Transaction.TransactionID,
+			Amount:            legs.DebitAmount,
+			CurrencyCode:      legs.DebitCurrency,
+			Description:       sql.NullString{String: arg.Description, Valid: arg.Description != ""},
+			ExpiresAt:         arg.ExpiresAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create hold: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("authorize hold failed: %w", err)
+	}
+	return result, nil
+}
+
+// CaptureHold settles an open hold for its full amount or less. Whatever is not captured goes back
+// to the available balance, a hold can only be captured once.
+func CaptureHold(ctx context.Context, arg schemas.CaptureHoldParams) (schemas.HoldTxResult, error) {
+	var result schemas.HoldTxResult
+
+	store, err := prepareHoldStore(config.TransactionStatuses.COMPLETED)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		hold, err := lockOpenHold(ctx, q, arg.HoldNumber)
+		if err != nil {
+			return err
+		}
+		if time.Now().After(hold.ExpiresAt) {
+			return ErrHoldExpired
+		}
+
+		capture := hold.Amount
+		if arg.Amount.Status == pgtype.Present {
+			scale, err := checkAmountScale(ctx, q, hold.CurrencyCode, arg.Amount)
+			if err != nil {
+				return err
+			}
+			requested := numericToDecimal(arg.Amount)
+			if !requested.IsPositive() {
+				return ErrInvalidHoldAmount
+			}
+			if requested.GreaterThan(numericToDecimal(hold.Amount)) {
+				return ErrCaptureExceedsHold
+			}
+			if capture, err = decimalToNumeric(requested, scale); err != nil {
+				return err
+			}
+		}
+
+		// Step 1: Free the whole reservation, the captured part is debited below
+		result.Account, err = q.UpdateAccountHeldAmount(ctx, db.UpdateAccountHeldAmountParams{
+			Amount:    NegateNumeric(hold.Amount),
+			AccountID: hold.AccountID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to release reserved funds: %w", err)
+		}
+
+		// Step 2: Move the captured amount like a transfer, at the rates of the capture
+		transferArg := schemas.TransferTxParams{
+			SenderAccountID:   hold.AccountID,
+			ReceiverAccountID: hold.MerchantAccountID,
+			Amount:            capture,
+			CurrencyCode:      hold.CurrencyCode,
+		}
+		legs, err := resolveTransferLegs(ctx, q, transferArg)
+		if err != nil {
+			return fmt.Errorf("failed to resolve capture amounts: %w", err)
+		}
+		result.FromEntry, result.ToEntry, err = createTransferEntries(ctx, q, hold.TransactionID, transferArg, legs)
+		if err != nil {
+			return fmt.Errorf("failed to create capture entries: %w", err)
+		}
+		result.Account, result.MerchantAccount, err = updateAccountBalances(ctx, q, transferArg, legs)
+		if err != nil {
+			return fmt.Errorf("failed to update account balances: %w", err)
+		}
+
+		// Step 3: Settle the pending transaction and close the hold
+		result.Transaction, err = q.SettleTransaction(ctx, db.SettleTransactionParams{
+			Amount:          legs.DebitAmount,
+			ExchangeRate:    legs.ExchangeRate,
+			ConvertedAmount: legs.CreditAmount,
+			ConvertedCurrencyCode: sql.NullString{
+				String: legs.CreditCurrency,
+				Valid:  legs.CreditCurrency != legs.DebitCurrency,
+			},
+			StatusCode:    config.TransactionStatuses.COMPLETED,
+			TransactionID: hold.TransactionID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to settle transaction: %w", err)
+		}
+		result.Hold, err = q.CloseHold(ctx, db.CloseHoldParams{
+			Status:         db.HoldStatusCAPTURED,
+			CapturedAmount: capture,
+			HoldID:         hold.HoldID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to close hold: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("capture hold failed: %w", err)
+	}
+	return result, nil
+}
+
+// ReleaseHold cancels an open hold and gives the reserved funds back to the available balance
+func ReleaseHold(ctx context.Context, holdNumber uuid.UUID) (schemas.HoldTxResult, error) {
+	var result schemas.HoldTxResult
+
+	store, err := prepareHoldStore(config.TransactionStatuses.CANCELLED)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		hold, err := lockOpenHold(ctx, q, holdNumber)
+		if err != nil {
+			return err
+		}
+		result, err = closeOpenHold(ctx, q, hold, db.HoldStatusRELEASED)
+		return err
+	})
+	if err != nil {
+		return result, fmt.Errorf("release hold failed: %w", err)
+	}
+	return result, nil
+}
+
+// ExpireHolds releases up to limit open holds whose expiry has passed and returns how many it closed.
+// Holds locked by a concurrent capture or release are skipped and picked up on the next run.
+func ExpireHolds(ctx context.Context, limit int32) (int, error) {
+	store, err := prepareHoldStore(config.TransactionStatuses.CANCELLED)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		holds, err := q.ListExpiredHoldsForUpdate(ctx, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list expired holds: %w", err)
+		}
+		for _, hold := range holds {
+			if _, err := closeOpenHold(ctx, q, hold, db.HoldStatusEXPIRED); err != nil {
+				return err
+			}
+		}
+		expired = len(holds)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("expire holds failed: %w", err)
+	}
+	return expired, nil
+}
+
+// prepareHoldStore returns the SQL store after making sure the reference rows used by holds exist
+func prepareHoldStore(statusCode string) (*db.SQLStore, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	if _, err := CreateTransactionType(config.TransactionTypes.PAYMENT); err != nil {
+		return nil, err
+	}
+	if _, err := CreateTransactionStatus(statusCode); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func lockOpenHold(ctx context.Context, q *db.Queries, holdNumber uuid.UUID) (db.Hold, error) {
+	hold, err := q.GetHoldForUpdate(ctx, holdNumber)
+	if err != nil {
+		return hold, fmt.Errorf("error getting hold: %w", err)
+	}
+	if hold.Status != db.HoldStatusOPEN {
+		return hold, ErrHoldNotOpen
+	}
+	return hold, nil
+}
+
+// closeOpenHold releases the reserved funds and cancels the pending transaction of a locked hold
+func closeOpenHold(ctx context.Context, q *db.Queries, hold db.Hold, status db.HoldStatus) (schemas.HoldTxResult, error) {
+	var result schemas.HoldTxResult
+	var err error
+
+	result.Account, err = q.UpdateAccountHeldAmount(ctx, db.UpdateAccountHeldAmountParams{
+		Amount:    NegateNumeric(hold.Amount),
+		AccountID: hold.AccountID,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to release reserved funds: %w", err)
+	}
+	result.Transaction, err = q.UpdateTransactionStatus(ctx, db.UpdateTransactionStatusParams{
+		TransactionID: hold.TransactionID,
+		StatusCode:    config.TransactionStatuses.CANCELLED,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to cancel pending transaction: %w", err)
+	}
+
+	var zero pgtype.Numeric
+	if err := zero.Set("0"); err != nil {
+		return result, err
+	}
+	result.Hold, err = q.CloseHold(ctx, db.CloseHoldParams{
+		Status:         status,
+		CapturedAmount: zero,
+		HoldID:         hold.HoldID,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to close hold: %w", err)
+	}
+	return result, nil
+}
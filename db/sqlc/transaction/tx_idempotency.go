@@ -0,0 +1,23 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	setup "github.com/riad/banksystemendtoend/util/db"
+)
+
+// DeleteExpiredIdempotencyKeys removes the idempotency keys whose responses can no longer be replayed
+// and returns how many it removed
+func DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	deleted, err := store.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys failed: %w", err)
+	}
+	return deleted, nil
+}
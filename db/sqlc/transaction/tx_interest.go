@@ -0,0 +1,277 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/config"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+// MaxInterestCatchUpDays bounds how many missed days a single interest run accrues, the days after
+// them are left to the next run
+const MaxInterestCatchUpDays = 62
+
+// RunInterest accrues interest for every day since the last accrued one up to and including
+// through, then posts every period that has ended by then. Each day and each posting commits on
+// its own, so a run that stops halfway is completed by the next one. A run that is more than
+// MaxInterestCatchUpDays behind stops early and reports the days it left in Remaining.
+func RunInterest(ctx context.Context, through time.Time) (schemas.InterestRunResult, error) {
+	var result schemas.InterestRunResult
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	through = interest.Date(through)
+	day := through
+	latest, err := store.GetLatestInterestAccrualDate(ctx)
+	if err == nil {
+		day = interest.Date(latest).AddDate(0, 0, 1)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return result, fmt.Errorf("failed to get latest accrual date: %w", err)
+	}
+	if last := day.AddDate(0, 0, MaxInterestCatchUpDays-1); last.Before(through) {
+		result.Remaining = int(through.Sub(last).Hours() / 24)
+		through = last
+	}
+	result.Through = through
+
+	for ; !day.After(through); day = day.AddDate(0, 0, 1) {
+		accrued, err := AccrueInterest(ctx, day)
+		if err != nil {
+			return result, err
+		}
+		result.Days++
+		result.Accrued += accrued
+	}
+
+	result.Posted, err = PostInterest(ctx, through)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// AccrueInterest records one day of interest for every interest bearing account, based on the
+// account's ledger balance at the end of that day. Accounts that already accrued for the day are
+// skipped, so it is safe to call again for the same day. It returns the number of new accruals.
+func AccrueInterest(ctx context.Context, day time.Time) (int, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	day = interest.Date(day)
+	accrued := 0
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		accounts, err := q.ListInterestBearingAccounts(ctx, day)
+		if err != nil {
+			return fmt.Errorf("failed to list interest bearing accounts: %w", err)
+		}
+
+		for _, account := range accounts {
+			amount, err := interest.DailyAccrual(numericToDecimal(account.Balance),
+				numericToDecimal(account.InterestRate), string(account.DayCountConvention), day)
+			if err != nil {
+				return fmt.Errorf("failed to accrue interest for account %d: %w", account.AccountID, err)
+			}
+			accrual, err := decimalToNumeric(amount, interest.AccrualScale)
+			if err != nil {
+				return err
+			}
+
+			rows, err := q.CreateInterestAccrual(ctx, db.CreateInterestAccrualParams{
+				AccountID:          account.AccountID,
+				AccrualDate:        day,
+				Balance:            account.Balance,
+				InterestRate:       account.InterestRate,
+				DayCountConvention: account.DayCountConvention,
+				Amount:             accrual,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record accrual for account %d: %w", account.AccountID, err)
+			}
+			accrued += int(rows)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("interest accrual for %s failed: %w", day.Format(time.DateOnly), err)
+	}
+	return accrued, nil
+}
+
+// PostInterest credits the accruals of every posting period that ended on or before asOf as one
+// INTEREST transaction per account and period. It returns the number of transactions booked.
+func PostInterest(ctx context.Context, asOf time.Time) (int, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	if _, err := CreateTransactionType(config.TransactionTypes.INTEREST); err != nil {
+		return 0, err
+	}
+	if _, err := CreateTransactionStatus(config.TransactionStatuses.COMPLETED); err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, period := range interest.Periods {
+		periodEnd, err := interest.LastPeriodEnd(period, asOf)
+		if err != nil {
+			return posted, err
+		}
+
+		accountIDs, err := store.ListUnpostedInterestAccounts(ctx, db.ListUnpostedInterestAccountsParams{
+			PostingPeriod: db.InterestPostingPeriod(period),
+			AccrualDate:   periodEnd,
+		})
+		if err != nil {
+			return posted, fmt.Errorf("failed to list accounts with unposted interest: %w", err)
+		}
+
+		for _, accountID := range accountIDs {
+			var booked bool
+			err := store.ExecTx(ctx, func(q *db.Queries) error {
+				var err error
+				booked, err = postAccountInterest(ctx, q, accountID, periodEnd)
+				return err
+			})
+			if err != nil {
+				return posted, fmt.Errorf("failed to post interest for account %d: %w", accountID, err)
+			}
+			if booked {
+				posted++
+			}
+		}
+	}
+	return posted, nil
+}
+
+// postAccountInterest books the unposted accruals of an account up to periodEnd, together with the
+// residue of its previous posting, rounded half to even to the minor unit of the account currency
+// like every other amount. What the rounding leaves out is kept as the residue of the last accrual
+// posted. Totals that round to less than the minor unit stay unposted and are carried into the next
+// period.
+func postAccountInterest(ctx context.Context, q *db.Queries, accountID int32, periodEnd time.Time) (bool, error) {
+	accruals, err := q.ListUnpostedInterestAccrualsForUpdate(ctx, db.ListUnpostedInterestAccrualsForUpdateParams{
+		AccountID:   accountID,
+		AccrualDate: periodEnd,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to lock accruals: %w", err)
+	}
+
+	account, err := q.GetAccount(ctx, accountID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get account: %w", err)
+	}
+	scale, err := currencyScale(ctx, q, account.CurrencyCode)
+	if err != nil {
+		return false, err
+	}
+
+	residue, err := q.GetInterestResidue(ctx, accountID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return false, fmt.Errorf("failed to get interest residue: %w", err)
+	}
+	total := numericToDecimal(residue)
+	for _, accrual := range accruals {
+		total = total.Add(numericToDecimal(accrual.Amount))
+	}
+	posted := total.RoundBank(scale)
+	if !posted.IsPositive() {
+		return false, nil
+	}
+	amount, err := decimalToNumeric(posted, scale)
+	if err != nil {
+		return false, err
+	}
+
+	transaction, err := creditInterest(ctx, q, account, amount,
+		fmt.Sprintf("Interest for the period ending %s", periodEnd.Format(time.DateOnly)),
+		interestReference(accountID, periodEnd, accruals[0].AccrualID))
+	if err != nil {
+		return false, err
+	}
+
+	_, err = q.MarkInterestAccrualsPosted(ctx, db.MarkInterestAccrualsPostedParams{
+		TransactionID: sqlNullInt32(transaction.TransactionID),
+		AccountID:     accountID,
+		AccrualDate:   periodEnd,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to mark accruals posted: %w", err)
+	}
+
+	left, err := decimalToNumeric(total.Sub(posted), interest.AccrualScale)
+	if err != nil {
+		return false, err
+	}
+	err = q.SetInterestAccrualResidue(ctx, db.SetInterestAccrualResidueParams{
+		AccrualID: accruals[len(accruals)-1].AccrualID,
+		Residue:   left,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record interest residue: %w", err)
+	}
+	return true, nil
+}
+
+// creditInterest books amount as a one-sided INTEREST transaction crediting the account
+func creditInterest(ctx context.Context, q *db.Queries, account db.Account, amount pgtype.Numeric,
+	description, reference string) (db.Transaction, error) {
+
+	transaction, err := q.CreateTransaction(ctx, db.CreateTransactionParams{
+		ToAccountID:     sqlNullInt32(account.AccountID),
+		TypeCode:        config.TransactionTypes.INTEREST,
+		Amount:          amount,
+		CurrencyCode:    account.CurrencyCode,
+		ExchangeRate:    pgtype.Numeric{Status: pgtype.Null},
+		StatusCode:      config.TransactionStatuses.COMPLETED,
+		Description:     sql.NullString{String: description, Valid: true},
+		ReferenceNumber: sql.NullString{String: reference, Valid: true},
+		TransactionDate: time.Now(),
+		ConvertedAmount: pgtype.Numeric{Status: pgtype.Null},
+	})
+	if err != nil {
+		return db.Transaction{}, fmt.Errorf("failed to create interest transaction: %w", err)
+	}
+
+	_, err = q.CreateEntry(ctx, db.CreateEntryParams{
+		AccountID:     sqlNullInt32(account.AccountID),
+		Amount:        amount,
+		TransactionID: sqlNullInt32(transaction.TransactionID),
+		CurrencyCode:  sql.NullString{String: account.CurrencyCode, Valid: true},
+		ExchangeRate:  pgtype.Numeric{Status: pgtype.Null},
+	})
+	if err != nil {
+		return db.Transaction{}, fmt.Errorf("failed to create interest entry: %w", err)
+	}
+
+	_, err = q.UpdateAccountBalance(ctx, db.UpdateAccountBalanceParams{
+		Amount:    amount,
+		AccountID: account.AccountID,
+	})
+	if err != nil {
+		return db.Transaction{}, fmt.Errorf("failed to credit interest: %w", err)
+	}
+	return transaction, nil
+}
+
+// interestReference names a posting by account, period and its first accrual, which no other
+// posting can include
+func interestReference(accountID int32, periodEnd time.Time, firstAccrualID int64) string {
+	return fmt.Sprintf("INT-%d-%s-%d", accountID, periodEnd.Format("20060102"), firstAccrualID)
+}
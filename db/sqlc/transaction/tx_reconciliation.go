@@ -0,0 +1,200 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/pkg/metrics"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+)
+
+// RunReconciliation checks the ledger of the given accounts, or of every account when accountIDs is
+// empty. It compares accounts.balance with the sum of entries, checks that the entries of every
+// transfer net to zero and that every booked transaction has its debit and credit entries.
+// Each finding is stored as a discrepancy of a new run; a full run also refreshes the drift metrics.
+func RunReconciliation(ctx context.Context, accountIDs []int32) (schemas.ReconciliationResult, error) {
+	var result schemas.ReconciliationResult
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	var scope []int32
+	if len(accountIDs) > 0 {
+		scope = accountIDs
+	}
+
+	result.Run, err = store.CreateReconciliationRun(ctx, scope)
+	if err != nil {
+		return result, fmt.Errorf("failed to create reconciliation run: %w", err)
+	}
+
+	findings, drift, err := findDiscrepancies(ctx, store.Queries, result.Run.RunID, scope)
+	if err != nil {
+		return result, failReconciliation(ctx, store.Queries, result.Run, err)
+	}
+	accountsChecked, err := store.CountReconciliationAccounts(ctx, scope)
+	if err != nil {
+		return result, failReconciliation(ctx, store.Queries, result.Run, err)
+	}
+	transactionsChecked, err := store.CountReconciliationTransactions(ctx, scope)
+	if err != nil {
+		return result, failReconciliation(ctx, store.Queries, result.Run, err)
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		result.Discrepancies = make([]db.LedgerDiscrepancy, 0, len(findings))
+		for _, finding := range findings {
+			discrepancy, err := q.CreateLedgerDiscrepancy(ctx, finding)
+			if err != nil {
+				return fmt.Errorf("failed to record discrepancy: %w", err)
+			}
+			result.Discrepancies = append(result.Discrepancies, discrepancy)
+		}
+
+		var err error
+		result.Run, err = q.CompleteReconciliationRun(ctx, db.CompleteReconciliationRunParams{
+			Status:              db.ReconciliationStatusCOMPLETED,
+			AccountsChecked:     int32(accountsChecked),
+			TransactionsChecked: int32(transactionsChecked),
+			DiscrepancyCount:    int32(len(findings)),
+			RunID:               result.Run.RunID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to complete reconciliation run: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return result, failReconciliation(ctx, store.Queries, result.Run, err)
+	}
+
+	result.Drift = drift
+	if scope == nil {
+		observeReconciliation(result)
+	}
+	return result, nil
+}
+
+// findDiscrepancies runs the three ledger checks and returns what they found, together with the
+// absolute balance drift per currency
+func findDiscrepancies(ctx context.Context, q *db.Queries, runID int32,
+	scope []int32) ([]db.CreateLedgerDiscrepancyParams, map[string]decimal.Decimal, error) {
+
+	var findings []db.CreateLedgerDiscrepancyParams
+	drift := make(map[string]decimal.Decimal)
+
+	balances, err := q.ListAccountBalanceDrift(ctx, scope)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compare account balances: %w", err)
+	}
+	for _, row := range balances {
+		difference := numericToDecimal(row.Balance).Sub(numericToDecimal(row.EntriesBalance))
+		drift[row.CurrencyCode] = drift[row.CurrencyCode].Add(difference.Abs())
+
+		finding, err := newDiscrepancy(runID, db.DiscrepancyTypeBALANCEMISMATCH, row.CurrencyCode,
+			numericToDecimal(row.EntriesBalance), numericToDecimal(row.Balance),
+			"accounts.balance differs from the sum of the account's entries")
+		if err != nil {
+			return nil, nil, err
+		}
+		finding.AccountID = sql.NullInt32{Int32: row.AccountID, Valid: true}
+		findings = append(findings, finding)
+	}
+
+	unbalanced, err := q.ListUnbalancedTransactions(ctx, scope)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check transaction entries: %w", err)
+	}
+	for _, row := range unbalanced {
+		finding, err := newDiscrepancy(runID, db.DiscrepancyTypeUNBALANCEDTRANSACTION, row.CurrencyCode,
+			decimal.Zero, numericToDecimal(row.NetAmount),
+			fmt.Sprintf("the %d entries of the transaction do not net to zero", row.EntryCount))
+		if err != nil {
+			return nil, nil, err
+		}
+		finding.TransactionID = sql.NullInt32{Int32: row.TransactionID, Valid: true}
+		findings = append(findings, finding)
+	}
+
+	missing, err := q.ListTransactionsMissingEntries(ctx, scope)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check for missing entries: %w", err)
+	}
+	for _, row := range missing {
+		finding, err := newDiscrepancy(runID, db.DiscrepancyTypeMISSINGENTRIES, row.CurrencyCode,
+			numericToDecimal(row.Amount), decimal.Zero,
+			fmt.Sprintf("booked transaction has %d entries but lacks the debit of account %d or the credit of account %d",
+				row.EntryCount, row.FromAccountID.Int32, row.ToAccountID.Int32))
+		if err != nil {
+			return nil, nil, err
+		}
+		finding.TransactionID = sql.NullInt32{Int32: row.TransactionID, Valid: true}
+		findings = append(findings, finding)
+	}
+
+	return findings, drift, nil
+}
+
+// newDiscrepancy builds a finding, expected is what the ledger requires and actual what was found
+func newDiscrepancy(runID int32, discrepancyType db.DiscrepancyType, currencyCode string,
+	expected, actual decimal.Decimal, details string) (db.CreateLedgerDiscrepancyParams, error) {
+
+	arg := db.CreateLedgerDiscrepancyParams{
+		RunID:           runID,
+		DiscrepancyType: discrepancyType,
+		CurrencyCode:    sql.NullString{String: currencyCode, Valid: currencyCode != ""},
+		Details:         sql.NullString{String: details, Valid: true},
+	}
+
+	var err error
+	if arg.ExpectedAmount, err = decimalToNumeric(expected, AmountScale); err != nil {
+		return arg, err
+	}
+	if arg.ActualAmount, err = decimalToNumeric(actual, AmountScale); err != nil {
+		return arg, err
+	}
+	if arg.Difference, err = decimalToNumeric(actual.Sub(expected), AmountScale); err != nil {
+		return arg, err
+	}
+	return arg, nil
+}
+
+// failReconciliation closes a run that could not finish and returns the original error
+func failReconciliation(ctx context.Context, q *db.Queries, run db.ReconciliationRun, cause error) error {
+	_, err := q.CompleteReconciliationRun(ctx, db.CompleteReconciliationRunParams{
+		Status:       db.ReconciliationStatusFAILED,
+		ErrorMessage: sql.NullString{String: cause.Error(), Valid: true},
+		RunID:        run.RunID,
+	})
+	if err != nil {
+		return fmt.Errorf("reconciliation failed: %w (and the run could not be closed: %v)", cause, err)
+	}
+	return fmt.Errorf("reconciliation failed: %w", cause)
+}
+
+func observeReconciliation(result schemas.ReconciliationResult) {
+	drift := make(map[string]float64, len(result.Drift))
+	for currency, value := range result.Drift {
+		drift[currency] = value.InexactFloat64()
+	}
+	discrepancies := map[string]int64{
+		string(db.DiscrepancyTypeBALANCEMISMATCH):       0,
+		string(db.DiscrepancyTypeUNBALANCEDTRANSACTION): 0,
+		string(db.DiscrepancyTypeMISSINGENTRIES):        0,
+	}
+	for _, discrepancy := range result.Discrepancies {
+		discrepancies[string(discrepancy.DiscrepancyType)]++
+	}
+	completedAt := time.Now()
+	if result.Run.CompletedAt.Valid {
+		completedAt = result.Run.CompletedAt.Time
+	}
+	metrics.ObserveReconciliation(drift, discrepancies, completedAt)
+}
@@ -0,0 +1,79 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	setup "github.com/riad/banksystemendtoend/util/db"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid, expired or revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+// RotateRefreshToken exchanges the refresh token stored as tokenHash for next, which joins the same
+// family and user. The old token is locked while it is replaced, so two refreshes racing with the
+// same token cannot both succeed. A token that was already replaced is presented again only when it
+// was copied, so the whole family is revoked and ErrRefreshTokenReused returned.
+func RotateRefreshToken(ctx context.Context, tokenHash string, next db.CreateRefreshTokenParams) (db.RefreshToken, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return db.RefreshToken{}, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	var rotated db.RefreshToken
+	var family uuid.UUID
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		current, err := q.GetRefreshTokenForUpdate(ctx, tokenHash)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRefreshTokenInvalid
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get refresh token: %w", err)
+		}
+		family = current.FamilyID
+
+		if current.RevokedAt.Valid {
+			if current.ReplacedBy.Valid {
+				return ErrRefreshTokenReused
+			}
+			return ErrRefreshTokenInvalid
+		}
+		if !time.Now().Before(current.ExpiresAt) {
+			return ErrRefreshTokenInvalid
+		}
+
+		next.UserID = current.UserID
+		next.FamilyID = current.FamilyID
+		rotated, err = q.CreateRefreshToken(ctx, next)
+		if err != nil {
+			return fmt.Errorf("failed to create refresh token: %w", err)
+		}
+		err = q.RevokeRefreshToken(ctx, db.RevokeRefreshTokenParams{
+			ReplacedBy: sql.NullInt64{Int64: rotated.TokenID, Valid: true},
+			TokenID:    current.TokenID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to revoke refresh token: %w", err)
+		}
+		return nil
+	})
+
+	// The family is revoked outside the rolled back transaction so the revocation sticks
+	if errors.Is(err, ErrRefreshTokenReused) {
+		if _, revokeErr := store.RevokeRefreshTokenFamily(ctx, family); revokeErr != nil {
+			return db.RefreshToken{}, fmt.Errorf("failed to revoke refresh token family: %w", revokeErr)
+		}
+	}
+	if err != nil {
+		return db.RefreshToken{}, err
+	}
+	return rotated, nil
+}
@@ -0,0 +1,199 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/config"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrTransactionNotReversible = errors.New("only completed transfers between two accounts can be reversed or refunded")
+	ErrAlreadyReversed          = errors.New("transaction has already been reversed or fully refunded")
+	ErrRefundExceedsOriginal    = errors.New("refund exceeds the amount left on the original transaction")
+	ErrInvalidRefundAmount      = errors.New("refund amount must be greater than zero")
+)
+
+// ReverseTx books a compensating transaction for everything not yet refunded on the original
+// transaction and marks the original REVERSED.
+func ReverseTx(ctx context.Context, arg schemas.ReverseTxParams) (schemas.ReversalTxResult, error) {
+	return compensateTx(ctx, config.TransactionTypes.REVERSAL, arg.TransactionNumber.String(), func(q *db.Queries) (schemas.ReversalTxResult, error) {
+		original, err := q.GetTransactionByNumberForUpdate(ctx, arg.TransactionNumber)
+		if err != nil {
+			return schemas.ReversalTxResult{}, fmt.Errorf("error getting original transaction: %w", err)
+		}
+		return bookCompensation(ctx, q, original, nil, config.TransactionTypes.REVERSAL, arg.Description, arg.ReferenceNumber)
+	})
+}
+
+// RefundTx returns part or all of the original transaction to its sender. The amount is in the
+// original transaction currency, and all refunds together can never exceed the original amount.
+func RefundTx(ctx context.Context, arg schemas.RefundTxParams) (schemas.ReversalTxResult, error) {
+	amount := numericToDecimal(arg.Amount)
+	if !amount.IsPositive() {
+		return schemas.ReversalTxResult{}, ErrInvalidRefundAmount
+	}
+	return compensateTx(ctx, config.TransactionTypes.REFUND, arg.TransactionNumber.String(), func(q *db.Queries) (schemas.ReversalTxResult, error) {
+		original, err := q.GetTransactionByNumberForUpdate(ctx, arg.TransactionNumber)
+		if err != nil {
+			return schemas.ReversalTxResult{}, fmt.Errorf("error getting original transaction: %w", err)
+		}
+		return bookCompensation(ctx, q, original, &amount, config.TransactionTypes.REFUND, arg.Description, arg.ReferenceNumber)
+	})
+}
+
+// compensateTx makes sure the reference rows exist and runs fn in a database transaction
+func compensateTx(ctx context.Context, typeCode, transactionNumber string,
+	fn func(q *db.Queries) (schemas.ReversalTxResult, error)) (schemas.ReversalTxResult, error) {
+
+	var result schemas.ReversalTxResult
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	if _, err := CreateTransactionType(typeCode); err != nil {
+		return result, err
+	}
+	for _, status := range []string{config.TransactionStatuses.COMPLETED, config.TransactionStatuses.REVERSED} {
+		if _, err := CreateTransactionStatus(status); err != nil {
+			return result, err
+		}
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		result, err = fn(q)
+		return err
+	})
+	if err != nil {
+		return result, fmt.Errorf("%s of transaction %s failed: %w", typeCode, transactionNumber, err)
+	}
+	return result, nil
+}
+
+// bookCompensation moves money back from the original receiver to the original sender. A nil
+// amount compensates whatever is left. The original row must be locked by the caller.
+func bookCompensation(ctx context.Context, q *db.Queries, original db.Transaction, amount *decimal.Decimal,
+	typeCode, description, referenceNumber string) (schemas.ReversalTxResult, error) {
+
+	result := schemas.ReversalTxResult{Original: original}
+
+	if original.StatusCode == config.TransactionStatuses.REVERSED {
+		return result, ErrAlreadyReversed
+	}
+	if original.StatusCode != config.TransactionStatuses.COMPLETED ||
+		!original.FromAccountID.Valid || !original.ToAccountID.Valid ||
+		original.OriginalTransactionID.Valid {
+		return result, ErrTransactionNotReversible
+	}
+
+	// Compensating debits the original receiver, which may be a running fixed deposit
+	if err := ensureDebitable(ctx, q, original.ToAccountID.Int32); err != nil {
+		return result, err
+	}
+
+	totals, err := q.GetCompensatedTotals(ctx, sqlNullInt32(original.TransactionID))
+	if err != nil {
+		return result, fmt.Errorf("error getting refunded totals: %w", err)
+	}
+
+	// Sender side is in the original currency, receiver side in the converted currency
+	originalDebit := numericToDecimal(original.Amount)
+	originalCredit := originalDebit
+	if original.ConvertedAmount.Status == pgtype.Present {
+		originalCredit = numericToDecimal(original.ConvertedAmount)
+	}
+	remainingToSender := originalDebit.Sub(numericToDecimal(totals.Credited))
+	remainingFromReceiver := originalCredit.Sub(numericToDecimal(totals.Debited))
+	if !remainingToSender.IsPositive() {
+		return result, ErrAlreadyReversed
+	}
+
+	receiverCurrency := original.CurrencyCode
+	if original.ConvertedCurrencyCode.Valid {
+		receiverCurrency = original.ConvertedCurrencyCode.String
+	}
+	senderScale, err := currencyScale(ctx, q, original.CurrencyCode)
+	if err != nil {
+		return result, err
+	}
+	receiverScale, err := currencyScale(ctx, q, receiverCurrency)
+	if err != nil {
+		return result, err
+	}
+
+	refund := remainingToSender
+	if amount != nil {
+		refund = *amount
+		if err := checkDecimals(original.CurrencyCode, refund, senderScale); err != nil {
+			return result, err
+		}
+		if refund.GreaterThan(remainingToSender) {
+			return result, ErrRefundExceedsOriginal
+		}
+	}
+
+	rate := decimal.NewFromInt(1)
+	if original.ExchangeRate.Status == pgtype.Present && numericToDecimal(original.ExchangeRate).IsPositive() {
+		rate = numericToDecimal(original.ExchangeRate)
+	}
+
+	// The last refund takes whatever is left on the receiver side, so rounding never leaves a residue
+	fullyCompensated := refund.Equal(remainingToSender)
+	debit := remainingFromReceiver
+	if !fullyCompensated {
+		debit = decimal.Min(refund.Mul(rate).RoundBank(receiverScale), remainingFromReceiver)
+	}
+
+	legs := transferLegs{DebitCurrency: receiverCurrency, CreditCurrency: original.CurrencyCode, DebitScale: receiverScale}
+	if legs.DebitAmount, err = decimalToNumeric(debit, receiverScale); err != nil {
+		return result, err
+	}
+	if legs.CreditAmount, err = decimalToNumeric(refund, senderScale); err != nil {
+		return result, err
+	}
+	if legs.ExchangeRate, err = decimalToNumeric(decimal.NewFromInt(1).DivRound(rate, ExchangeRateScale), ExchangeRateScale); err != nil {
+		return result, err
+	}
+
+	arg := schemas.TransferTxParams{
+		SenderAccountID:       original.ToAccountID.Int32,
+		ReceiverAccountID:     original.FromAccountID.Int32,
+		TypeCode:              typeCode,
+		StatusCode:            config.TransactionStatuses.COMPLETED,
+		Description:           description,
+		ReferenceNumber:       referenceNumber,
+		OriginalTransactionID: original.TransactionID,
+	}
+
+	result.Transaction, err = createTransferTransaction(ctx, q, arg, legs)
+	if err != nil {
+		return result, err
+	}
+	result.FromEntry, result.ToEntry, err = createTransferEntries(ctx, q, result.Transaction.TransactionID, arg, legs)
+	if err != nil {
+		return result, err
+	}
+	result.FromAccount, result.ToAccount, err = updateAccountBalances(ctx, q, arg, legs)
+	if err != nil {
+		return result, err
+	}
+
+	if fullyCompensated {
+		result.Original, err = q.UpdateTransactionStatus(ctx, db.UpdateTransactionStatusParams{
+			TransactionID: original.TransactionID,
+			StatusCode:    config.TransactionStatuses.REVERSED,
+		})
+		if err != nil {
+			return result, fmt.Errorf("error marking original transaction reversed: %w", err)
+		}
+	}
+	return result, nil
+}
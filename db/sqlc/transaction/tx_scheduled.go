@@ -0,0 +1,266 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/config"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/schedule"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+var ErrInvalidScheduleTransition = errors.New("scheduled transfer cannot change to the requested status")
+
+// Reasons a scheduled transfer is cancelled when it runs, retrying would not help
+var (
+	ErrScheduledAccountNotFound = errors.New("sender or receiver account no longer exists")
+	ErrScheduledAccountInactive = errors.New("sender or receiver account is no longer active")
+	ErrScheduledAccountNotOwned = errors.New("sender account no longer belongs to the user who scheduled the transfer")
+)
+
+// scheduledRetryBackoff is the wait before the first retry of a failed occurrence, it doubles on every retry
+const scheduledRetryBackoff = 5 * time.Minute
+
+// ExecuteDueScheduledTransfers runs up to limit active scheduled transfers whose due time has passed.
+// Rows are claimed with FOR UPDATE SKIP LOCKED, so several schedulers can run side by side without
+// executing the same occurrence twice. Each occurrence is booked through TransferTx under a reference
+// derived from the schedule and occurrence time; an occurrence that was already booked before a crash
+// is recognised by that reference and recorded instead of being paid again.
+func ExecuteDueScheduledTransfers(ctx context.Context, limit int32) (schemas.ScheduledRunResult, error) {
+	var result schemas.ScheduledRunResult
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+	// TransferTx expects the reference rows to exist already
+	if _, err := CreateTransactionType(config.TransactionTypes.TRANSFER); err != nil {
+		return result, err
+	}
+	if _, err := CreateTransactionStatus(config.TransactionStatuses.PENDING); err != nil {
+		return result, err
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		due, err := q.ListDueScheduledTransfersForUpdate(ctx, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list due scheduled transfers: %w", err)
+		}
+		for _, scheduled := range due {
+			succeeded, err := runScheduledTransfer(ctx, q, scheduled)
+			if err != nil {
+				return err
+			}
+			if succeeded {
+				result.Executed++
+			} else {
+				result.Failed++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return schemas.ScheduledRunResult{}, fmt.Errorf("scheduled transfer run failed: %w", err)
+	}
+	return result, nil
+}
+
+// runScheduledTransfer executes one occurrence and records it. The returned error is only set when
+// the outcome could not be recorded; a failed transfer is recorded and reported as not succeeded.
+// A schedule whose accounts were closed, or whose sender changed hands, is cancelled.
+func runScheduledTransfer(ctx context.Context, q *db.Queries, scheduled db.ScheduledTransfer) (bool, error) {
+	reference := sql.NullString{String: scheduledReference(scheduled), Valid: true}
+	attempt := scheduled.RetryCount + 1
+
+	// An occurrence booked by a run that crashed before recording it is not paid again
+	var invalid error
+	transaction, err := q.GetTransactionByReference(ctx, reference)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if invalid, err = validateScheduledTransfer(ctx, q, scheduled); err != nil {
+			return false, err
+		}
+		if invalid != nil {
+			err = invalid
+		} else {
+			var transfer schemas.TransferTxResult
+			transfer, err = TransferTx(ctx, schemas.TransferTxParams{
+				SenderAccountID:   scheduled.FromAccountID,
+				ReceiverAccountID: scheduled.ToAccountID,
+				Amount:            scheduled.Amount,
+				CurrencyCode:      scheduled.CurrencyCode,
+				TypeCode:          config.TransactionTypes.TRANSFER,
+				StatusCode:        config.TransactionStatuses.PENDING,
+				Description:       scheduled.Description.String,
+				ReferenceNumber:   reference.String,
+			})
+			transaction = transfer.Transaction
+		}
+	} else if err != nil {
+		return false, fmt.Errorf("failed to look up scheduled transfer reference: %w", err)
+	}
+
+	now := time.Now().UTC()
+	execution := db.CreateScheduledTransferExecutionParams{
+		ScheduledTransferID: scheduled.ScheduledTransferID,
+		ScheduledFor:        scheduled.NextRunAt,
+		Attempt:             attempt,
+	}
+	run := db.UpdateScheduledTransferRunParams{
+		Status:              db.ScheduledTransferStatusACTIVE,
+		NextRunAt:           scheduled.NextRunAt,
+		DueAt:               scheduled.DueAt,
+		LastRunAt:           sql.NullTime{Time: now, Valid: true},
+		ScheduledTransferID: scheduled.ScheduledTransferID,
+	}
+
+	if err == nil {
+		execution.Status = db.ScheduledExecutionStatusSUCCEEDED
+		execution.TransactionID = sql.NullInt32{Int32: transaction.TransactionID, Valid: true}
+		if err := advanceSchedule(&run, scheduled, now, db.ScheduledTransferStatusCOMPLETED); err != nil {
+			return false, err
+		}
+	} else {
+		execution.Status = db.ScheduledExecutionStatusFAILED
+		execution.ErrorMessage = sql.NullString{String: err.Error(), Valid: true}
+		if invalid != nil {
+			run.Status = db.ScheduledTransferStatusCANCELLED
+		} else if scheduled.RetryCount < scheduled.MaxRetries {
+			run.RetryCount = attempt
+			run.DueAt = now.Add(scheduledRetryBackoff << scheduled.RetryCount)
+		} else if err := advanceSchedule(&run, scheduled, now, db.ScheduledTransferStatusFAILED); err != nil {
+			// Out of retries, the occurrence is given up and the schedule moves on
+			return false, err
+		}
+	}
+
+	if _, err := q.CreateScheduledTransferExecution(ctx, execution); err != nil {
+		return false, fmt.Errorf("failed to record scheduled transfer execution: %w", err)
+	}
+	if _, err := q.UpdateScheduledTransferRun(ctx, run); err != nil {
+		return false, fmt.Errorf("failed to update scheduled transfer: %w", err)
+	}
+	return execution.Status == db.ScheduledExecutionStatusSUCCEEDED, nil
+}
+
+// validateScheduledTransfer checks that both accounts are still active and that the sender still
+// belongs to the user who owned it when the schedule was created. It returns why the schedule can no longer run, the
+// error is only set when the accounts could not be read.
+func validateScheduledTransfer(ctx context.Context, q *db.Queries, scheduled db.ScheduledTransfer) (error, error) {
+	for _, accountID := range []int32{scheduled.FromAccountID, scheduled.ToAccountID} {
+		account, err := q.GetAccount(ctx, accountID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrScheduledAccountNotFound, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting account: %w", err)
+		}
+		if !account.IsActive {
+			return ErrScheduledAccountInactive, nil
+		}
+		if accountID == scheduled.FromAccountID && scheduled.UserID.Valid && account.UserID != scheduled.UserID.Int32 {
+			return ErrScheduledAccountNotOwned, nil
+		}
+	}
+	return nil, nil
+}
+
+// advanceSchedule moves run to the next occurrence after now, skipping the ones missed while the
+// scheduler was down. Without a next occurrence the schedule ends with finalStatus.
+func advanceSchedule(run *db.UpdateScheduledTransferRunParams, scheduled db.ScheduledTransfer,
+	now time.Time, finalStatus db.ScheduledTransferStatus) error {
+
+	after := scheduled.NextRunAt
+	if now.After(after) {
+		after = now
+	}
+	next, ok, err := scheduleOf(scheduled).Next(after)
+	if err != nil {
+		return fmt.Errorf("failed to compute next occurrence: %w", err)
+	}
+
+	run.RetryCount = 0
+	if !ok || (scheduled.EndAt.Valid && next.After(scheduled.EndAt.Time)) {
+		run.Status = finalStatus
+		return nil
+	}
+	run.NextRunAt = next
+	run.DueAt = next
+	return nil
+}
+
+// UpdateScheduledTransferStatus pauses, resumes or cancels a scheduled transfer. Resuming a
+// recurring transfer skips the occurrences that fell due while it was paused.
+func UpdateScheduledTransferStatus(ctx context.Context, scheduleNumber uuid.UUID,
+	status db.ScheduledTransferStatus) (db.ScheduledTransfer, error) {
+
+	var result db.ScheduledTransfer
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		scheduled, err := q.GetScheduledTransferForUpdate(ctx, scheduleNumber)
+		if err != nil {
+			return fmt.Errorf("error getting scheduled transfer: %w", err)
+		}
+
+		run := db.UpdateScheduledTransferRunParams{
+			Status:              status,
+			NextRunAt:           scheduled.NextRunAt,
+			DueAt:               scheduled.DueAt,
+			RetryCount:          scheduled.RetryCount,
+			LastRunAt:           scheduled.LastRunAt,
+			ScheduledTransferID: scheduled.ScheduledTransferID,
+		}
+
+		switch {
+		case status == db.ScheduledTransferStatusPAUSED && scheduled.Status == db.ScheduledTransferStatusACTIVE:
+		case status == db.ScheduledTransferStatusCANCELLED &&
+			(scheduled.Status == db.ScheduledTransferStatusACTIVE || scheduled.Status == db.ScheduledTransferStatusPAUSED):
+		case status == db.ScheduledTransferStatusACTIVE && scheduled.Status == db.ScheduledTransferStatusPAUSED:
+			now := time.Now().UTC()
+			if scheduled.Frequency != db.ScheduleFrequencyONCE && scheduled.NextRunAt.Before(now) {
+				if err := advanceSchedule(&run, scheduled, now, db.ScheduledTransferStatusCOMPLETED); err != nil {
+					return err
+				}
+			}
+		default:
+			return ErrInvalidScheduleTransition
+		}
+
+		result, err = q.UpdateScheduledTransferRun(ctx, run)
+		if err != nil {
+			return fmt.Errorf("failed to update scheduled transfer: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("update scheduled transfer status failed: %w", err)
+	}
+	return result, nil
+}
+
+func scheduleOf(scheduled db.ScheduledTransfer) schedule.Schedule {
+	return schedule.Schedule{
+		Frequency: string(scheduled.Frequency),
+		CronExpr:  scheduled.CronExpression.String,
+		Start:     scheduled.StartAt,
+	}
+}
+
+// scheduledReference identifies one occurrence of a scheduled transfer, it fits reference_number's 50 characters
+func scheduledReference(scheduled db.ScheduledTransfer) string {
+	return fmt.Sprintf("SCH-%s-%d",
+		strings.ReplaceAll(scheduled.ScheduleNumber.String(), "-", ""),
+		scheduled.NextRunAt.Unix())
+}
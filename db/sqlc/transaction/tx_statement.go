@@ -0,0 +1,102 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+)
+
+// StatementBounds returns the booking times a statement from periodStart through periodEnd covers,
+// whole days in UTC with the end exclusive
+func StatementBounds(periodStart, periodEnd time.Time) (time.Time, time.Time) {
+	from := time.Date(periodStart.Year(), periodStart.Month(), periodStart.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(periodEnd.Year(), periodEnd.Month(), periodEnd.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	return from, to
+}
+
+// BuildStatement reads the entries of an account for a period and works out the opening, running and
+// closing balances. The balance and the entries are read in one database transaction.
+func BuildStatement(ctx context.Context, accountID int32, periodStart, periodEnd time.Time) (schemas.Statement, error) {
+	statement := schemas.Statement{GeneratedAt: time.Now().UTC()}
+
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return statement, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	from, to := StatementBounds(periodStart, periodEnd)
+	statement.PeriodStart = from
+	statement.PeriodEnd = to.AddDate(0, 0, -1)
+	account := sql.NullInt32{Int32: accountID, Valid: true}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		statement.Account, err = q.GetAccount(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to get account: %w", err)
+		}
+		statement.Holder, err = q.GetUser(ctx, statement.Account.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get account holder: %w", err)
+		}
+
+		opening, err := q.GetAccountBalanceBefore(ctx, db.GetAccountBalanceBeforeParams{
+			AccountID: account,
+			CreatedAt: from,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get opening balance: %w", err)
+		}
+
+		entries, err := q.ListStatementEntries(ctx, db.ListStatementEntriesParams{
+			AccountID: account,
+			FromTime:  from,
+			ToTime:    to,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list statement entries: %w", err)
+		}
+
+		statement.OpeningBalance = numericToDecimal(opening)
+		statement.TotalCredits = decimal.Zero
+		statement.TotalDebits = decimal.Zero
+		balance := statement.OpeningBalance
+		statement.Lines = make([]schemas.StatementLine, 0, len(entries))
+		for _, entry := range entries {
+			amount := numericToDecimal(entry.Amount)
+			balance = balance.Add(amount)
+			if amount.IsNegative() {
+				statement.TotalDebits = statement.TotalDebits.Add(amount.Neg())
+			} else {
+				statement.TotalCredits = statement.TotalCredits.Add(amount)
+			}
+
+			line := schemas.StatementLine{
+				EntryID:               entry.EntryID,
+				BookedAt:              entry.CreatedAt.UTC(),
+				TypeCode:              entry.TypeCode.String,
+				Description:           entry.Description.String,
+				ReferenceNumber:       entry.ReferenceNumber.String,
+				CounterpartyAccountID: entry.CounterpartyAccountID.Int32,
+				Amount:                amount,
+				Balance:               balance,
+			}
+			if entry.TransactionNumber.Valid {
+				line.TransactionNumber = entry.TransactionNumber.UUID.String()
+			}
+			statement.Lines = append(statement.Lines, line)
+		}
+		statement.ClosingBalance = balance
+		return nil
+	})
+	if err != nil {
+		return statement, fmt.Errorf("build statement failed: %w", err)
+	}
+	return statement, nil
+}
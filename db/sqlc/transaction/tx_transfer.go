@@ -10,54 +10,85 @@ import (
 	"github.com/riad/banksystemendtoend/util/schemas"
 )
 
-// TransferTx executes a money transfer transaction between two accounts// TransferTx executes a money transfer transaction between two accounts
-// TransferTx executes a money transfer transaction between two accounts
+// TransferTx executes a money transfer transaction between two accounts.
+// Errors are wrapped, so callers can still inspect the underlying database error.
 func TransferTx(ctx context.Context, arg schemas.TransferTxParams) (schemas.TransferTxResult, error) {
 	var result schemas.TransferTxResult
 
 	store, err := db.GetSQLStore(setup.GetStore())
 	if err != nil {
-		return result, fmt.Errorf("failed to get SQL store: %v", err)
+		return result, fmt.Errorf("failed to get SQL store: %w", err)
 	}
 
 	// Execute transaction with proper error handling
 	err = store.ExecTx(ctx, func(q *db.Queries) error {
 		var err error
-		transaction_status, _ := CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
+		result, err = transfer(ctx, q, arg)
+		return err
+	})
 
-		// Step 1: Create Transaction with initial PENDING status
-		result.Transaction, err = createTransferTransaction(ctx, q, arg)
-		if err != nil {
-			return fmt.Errorf("failed to create transfer transaction: %v", err)
-		}
+	if err != nil {
+		return result, fmt.Errorf("transfer transaction failed: %w", err)
+	}
 
-		// Step 2: Create Entries for both accounts
-		result.FromEntry, result.ToEntry, err = createTransferEntries(ctx, q, arg)
-		if err != nil {
-			// Rollback will happen automatically due to ExecTx
-			return fmt.Errorf("failed to create transfer entries: %v", err)
-		}
+	return result, nil
+}
 
-		// Step 3: Update Account Balances atomically
-		result.FromAccount, result.ToAccount, err = updateAccountBalances(ctx, q, arg)
-		if err != nil {
-			return fmt.Errorf("failed to update account balances: %v", err)
-		}
+// transfer runs the steps of TransferTx on q, so several transfers can share one database transaction
+func transfer(ctx context.Context, q *db.Queries, arg schemas.TransferTxParams) (schemas.TransferTxResult, error) {
+	var result schemas.TransferTxResult
+	transaction_status, _ := CreateTransactionStatus(config.TransactionStatuses.COMPLETED)
 
-		// Step 4: Update Transaction Status to COMPLETED
-		result.Transaction, err = q.UpdateTransactionStatus(ctx, db.UpdateTransactionStatusParams{
-			TransactionID: result.Transaction.TransactionID,
-			StatusCode:    transaction_status.StatusCode,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to update transaction status: %v", err)
-		}
+	// Step 0: Work out both legs, converting when the accounts hold different currencies
+	legs, err := resolveTransferLegs(ctx, q, arg)
+	if err != nil {
+		return result, fmt.Errorf("failed to resolve transfer amounts: %w", err)
+	}
+
+	// Step 1: Create Transaction with initial PENDING status
+	result.Transaction, err = createTransferTransaction(ctx, q, arg, legs)
+	if err != nil {
+		return result, fmt.Errorf("failed to create transfer transaction: %w", err)
+	}
+
+	// Step 2: Create Entries for both accounts
+	result.FromEntry, result.ToEntry, err = createTransferEntries(ctx, q, result.Transaction.TransactionID, arg, legs)
+	if err != nil {
+		// Rollback will happen automatically due to ExecTx
+		return result, fmt.Errorf("failed to create transfer entries: %w", err)
+	}
+
+	// Step 3: Update Account Balances atomically
+	result.FromAccount, result.ToAccount, err = updateAccountBalances(ctx, q, arg, legs)
+	if err != nil {
+		return result, fmt.Errorf("failed to update account balances: %w", err)
+	}
 
-		return nil
+	// Step 4: Update Transaction Status to COMPLETED
+	result.Transaction, err = q.UpdateTransactionStatus(ctx, db.UpdateTransactionStatusParams{
+		TransactionID: result.Transaction.TransactionID,
+		StatusCode:    transaction_status.StatusCode,
 	})
+	if err != nil {
+		return result, fmt.Errorf("failed to update transaction status: %w", err)
+	}
 
+	// Step 5: Attach the reference rows the transaction points to
+	result.Status = transaction_status
+	result.Type, err = q.GetTransactionType(ctx, result.Transaction.TypeCode)
+	if err != nil {
+		return result, fmt.Errorf("failed to get transaction type: %w", err)
+	}
+	result.Currency, err = q.GetCurrency(ctx, result.Transaction.CurrencyCode)
 	if err != nil {
-		return result, fmt.Errorf("transfer transaction failed: %v", err)
+		return result, fmt.Errorf("failed to get transaction currency: %w", err)
+	}
+
+	// Step 6: Charge the sender's transfer fees in the same database transaction
+	if arg.TypeCode == config.TransactionTypes.TRANSFER {
+		if err := chargeTransferFees(ctx, q, &result, legs); err != nil {
+			return result, fmt.Errorf("failed to charge transfer fees: %w", err)
+		}
 	}
 
 	return result, nil
@@ -0,0 +1,36 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	setup "github.com/riad/banksystemendtoend/util/db"
+)
+
+var ErrUserNotFound = errors.New("user does not exist")
+
+// SetUserRole gives the user with username a role. It is how the first admin is appointed, later
+// roles can be granted through the API by an admin.
+func SetUserRole(ctx context.Context, username string, role db.UserRole) (db.User, error) {
+	store, err := db.GetSQLStore(setup.GetStore())
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to get SQL store: %w", err)
+	}
+
+	user, err := store.GetUserByUsername(ctx, username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user, err = store.UpdateUserRole(ctx, db.UpdateUserRoleParams{Role: role, UserID: user.UserID})
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to update user role: %w", err)
+	}
+	return user, nil
+}
@@ -25,23 +25,29 @@ INSERT INTO transactions (
     status_code,
     description,
     reference_number,
-    transaction_date
+    transaction_date,
+    converted_amount,
+    converted_currency_code,
+    original_transaction_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-) RETURNING transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) RETURNING transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id
 `
 
 type CreateTransactionParams struct {
-	FromAccountID   sql.NullInt32  `json:"from_account_id"`
-	ToAccountID     sql.NullInt32  `json:"to_account_id"`
-	TypeCode        string         `json:"type_code"`
-	Amount          pgtype.Numeric `json:"amount"`
-	CurrencyCode    string         `json:"currency_code"`
-	ExchangeRate    pgtype.Numeric `json:"exchange_rate"`
-	StatusCode      string         `json:"status_code"`
-	Description     sql.NullString `json:"description"`
-	ReferenceNumber sql.NullString `json:"reference_number"`
-	TransactionDate time.Time      `json:"transaction_date"`
+	FromAccountID         sql.NullInt32  `json:"from_account_id"`
+	ToAccountID           sql.NullInt32  `json:"to_account_id"`
+	TypeCode              string         `json:"type_code"`
+	Amount                pgtype.Numeric `json:"amount"`
+	CurrencyCode          string         `json:"currency_code"`
+	ExchangeRate          pgtype.Numeric `json:"exchange_rate"`
+	StatusCode            string         `json:"status_code"`
+	Description           sql.NullString `json:"description"`
+	ReferenceNumber       sql.NullString `json:"reference_number"`
+	TransactionDate       time.Time      `json:"transaction_date"`
+	ConvertedAmount       pgtype.Numeric `json:"converted_amount"`
+	ConvertedCurrencyCode sql.NullString `json:"converted_currency_code"`
+	OriginalTransactionID sql.NullInt32  `json:"original_transaction_id"`
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -56,6 +62,9 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.Description,
 		arg.ReferenceNumber,
 		arg.TransactionDate,
+		arg.ConvertedAmount,
+		arg.ConvertedCurrencyCode,
+		arg.OriginalTransactionID,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -74,6 +83,9 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TransactionNumber,
+		&i.ConvertedAmount,
+		&i.ConvertedCurrencyCode,
+		&i.OriginalTransactionID,
 	)
 	return i, err
 }
@@ -123,8 +135,29 @@ func (q *Queries) DeleteTransactionsByStatus(ctx context.Context, statusCode str
 	return err
 }
 
+const getCompensatedTotals = `-- name: GetCompensatedTotals :one
+SELECT
+    COALESCE(SUM(amount), 0)::DECIMAL(16, 3) AS debited,
+    COALESCE(SUM(COALESCE(converted_amount, amount)), 0)::DECIMAL(16, 3) AS credited
+FROM transactions
+WHERE original_transaction_id = $1
+    AND status_code = 'COMPLETED'
+`
+
+type GetCompensatedTotalsRow struct {
+	Debited  pgtype.Numeric `json:"debited"`
+	Credited pgtype.Numeric `json:"credited"`
+}
+
+func (q *Queries) GetCompensatedTotals(ctx context.Context, originalTransactionID sql.NullInt32) (GetCompensatedTotalsRow, error) {
+	row := q.db.QueryRow(ctx, getCompensatedTotals, originalTransactionID)
+	var i GetCompensatedTotalsRow
+	err := row.Scan(&i.Debited, &i.Credited)
+	return i, err
+}
+
 const getTransaction = `-- name: GetTransaction :one
-SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number FROM transactions
+SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id FROM transactions
 WHERE transaction_id = $1
 `
 
@@ -147,6 +180,9 @@ func (q *Queries) GetTransaction(ctx context.Context, transactionID int32) (Tran
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TransactionNumber,
+		&i.ConvertedAmount,
+		&i.ConvertedCurrencyCode,
+		&i.OriginalTransactionID,
 	)
 	return i, err
 }
@@ -164,8 +200,71 @@ func (q *Queries) GetTransactionBalance(ctx context.Context, fromAccountID sql.N
 	return balance, err
 }
 
+const getTransactionByNumber = `-- name: GetTransactionByNumber :one
+SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id FROM transactions
+WHERE transaction_number = $1
+`
+
+func (q *Queries) GetTransactionByNumber(ctx context.Context, transactionNumber uuid.UUID) (Transaction, error) {
+	row := q.db.QueryRow(ctx, getTransactionByNumber, transactionNumber)
+	var i Transaction
+	err := row.Scan(
+		&i.TransactionID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.TypeCode,
+		&i.Amount,
+		&i.CurrencyCode,
+		&i.ExchangeRate,
+		&i.StatusCode,
+		&i.IsCompleted,
+		&i.Description,
+		&i.ReferenceNumber,
+		&i.TransactionDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TransactionNumber,
+		&i.ConvertedAmount,
+		&i.ConvertedCurrencyCode,
+		&i.OriginalTransactionID,
+	)
+	return i, err
+}
+
+const getTransactionByNumberForUpdate = `-- name: GetTransactionByNumberForUpdate :one
+SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id FROM transactions
+WHERE transaction_number = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetTransactionByNumberForUpdate(ctx context.Context, transactionNumber uuid.UUID) (Transaction, error) {
+	row := q.db.QueryRow(ctx, getTransactionByNumberForUpdate, transactionNumber)
+	var i Transaction
+	err := row.Scan(
+		&i.TransactionID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.TypeCode,
+		&i.Amount,
+		&i.CurrencyCode,
+		&i.ExchangeRate,
+		&i.StatusCode,
+		&i.IsCompleted,
+		&i.Description,
+		&i.ReferenceNumber,
+		&i.TransactionDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TransactionNumber,
+		&i.ConvertedAmount,
+		&i.ConvertedCurrencyCode,
+		&i.OriginalTransactionID,
+	)
+	return i, err
+}
+
 const getTransactionByReference = `-- name: GetTransactionByReference :one
-SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number
+SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id
 FROM transactions
 WHERE reference_number = $1
 `
@@ -189,6 +288,9 @@ func (q *Queries) GetTransactionByReference(ctx context.Context, referenceNumber
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TransactionNumber,
+		&i.ConvertedAmount,
+		&i.ConvertedCurrencyCode,
+		&i.OriginalTransactionID,
 	)
 	return i, err
 }
@@ -250,7 +352,7 @@ func (q *Queries) GetTransactionStatement(ctx context.Context, arg GetTransactio
 }
 
 const getTransactionsByDateRange = `-- name: GetTransactionsByDateRange :many
-SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number FROM transactions
+SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id FROM transactions
 WHERE transaction_date BETWEEN $1 AND $2
 ORDER BY transaction_date DESC
 `
@@ -285,6 +387,9 @@ func (q *Queries) GetTransactionsByDateRange(ctx context.Context, arg GetTransac
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.TransactionNumber,
+			&i.ConvertedAmount,
+			&i.ConvertedCurrencyCode,
+			&i.OriginalTransactionID,
 		); err != nil {
 			return nil, err
 		}
@@ -297,7 +402,7 @@ func (q *Queries) GetTransactionsByDateRange(ctx context.Context, arg GetTransac
 }
 
 const getTransactionsByStatus = `-- name: GetTransactionsByStatus :many
-SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number
+SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id
 FROM transactions
 WHERE status_code = $1
 ORDER BY transaction_date DESC
@@ -328,6 +433,9 @@ func (q *Queries) GetTransactionsByStatus(ctx context.Context, statusCode string
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.TransactionNumber,
+			&i.ConvertedAmount,
+			&i.ConvertedCurrencyCode,
+			&i.OriginalTransactionID,
 		); err != nil {
 			return nil, err
 		}
@@ -339,8 +447,31 @@ func (q *Queries) GetTransactionsByStatus(ctx context.Context, statusCode string
 	return items, nil
 }
 
+const hasTransferredTo = `-- name: HasTransferredTo :one
+SELECT EXISTS (
+    SELECT 1 FROM transactions t
+    JOIN accounts a ON a.account_id = t.from_account_id
+    WHERE a.user_id = $1
+        AND t.to_account_id = $2::INTEGER
+        AND t.status_code = 'COMPLETED'
+)
+`
+
+type HasTransferredToParams struct {
+	UserID      int32 `json:"user_id"`
+	ToAccountID int32 `json:"to_account_id"`
+}
+
+// Whether any account of the user has completed a transaction to the account before
+func (q *Queries) HasTransferredTo(ctx context.Context, arg HasTransferredToParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasTransferredTo, arg.UserID, arg.ToAccountID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const listAccountTransactions = `-- name: ListAccountTransactions :many
-SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number
+SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id
 FROM transactions
 WHERE from_account_id = $1 OR to_account_id = $1
 ORDER BY transaction_date DESC
@@ -378,6 +509,9 @@ func (q *Queries) ListAccountTransactions(ctx context.Context, arg ListAccountTr
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.TransactionNumber,
+			&i.ConvertedAmount,
+			&i.ConvertedCurrencyCode,
+			&i.OriginalTransactionID,
 		); err != nil {
 			return nil, err
 		}
@@ -390,7 +524,7 @@ func (q *Queries) ListAccountTransactions(ctx context.Context, arg ListAccountTr
 }
 
 const listTransactionsByAccount = `-- name: ListTransactionsByAccount :many
-SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number FROM transactions
+SELECT transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id FROM transactions
 WHERE from_account_id = $1 OR to_account_id = $1
 ORDER BY transaction_date DESC
 LIMIT $2 OFFSET $3
@@ -427,6 +561,9 @@ func (q *Queries) ListTransactionsByAccount(ctx context.Context, arg ListTransac
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.TransactionNumber,
+			&i.ConvertedAmount,
+			&i.ConvertedCurrencyCode,
+			&i.OriginalTransactionID,
 		); err != nil {
 			return nil, err
 		}
@@ -438,11 +575,64 @@ func (q *Queries) ListTransactionsByAccount(ctx context.Context, arg ListTransac
 	return items, nil
 }
 
+const settleTransaction = `-- name: SettleTransaction :one
+UPDATE transactions
+SET amount = $1,
+    exchange_rate = $2,
+    converted_amount = $3,
+    converted_currency_code = $4,
+    status_code = $5
+WHERE transaction_id = $6
+RETURNING transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id
+`
+
+type SettleTransactionParams struct {
+	Amount                pgtype.Numeric `json:"amount"`
+	ExchangeRate          pgtype.Numeric `json:"exchange_rate"`
+	ConvertedAmount       pgtype.Numeric `json:"converted_amount"`
+	ConvertedCurrencyCode sql.NullString `json:"converted_currency_code"`
+	StatusCode            string         `json:"status_code"`
+	TransactionID         int32          `json:"transaction_id"`
+}
+
+func (q *Queries) SettleTransaction(ctx context.Context, arg SettleTransactionParams) (Transaction, error) {
+	row := q.db.QueryRow(ctx, settleTransaction,
+		arg.Amount,
+		arg.ExchangeRate,
+		arg.ConvertedAmount,
+		arg.ConvertedCurrencyCode,
+		arg.StatusCode,
+		arg.TransactionID,
+	)
+	var i Transaction
+	err := row.Scan(
+		&i.TransactionID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.TypeCode,
+		&i.Amount,
+		&i.CurrencyCode,
+		&i.ExchangeRate,
+		&i.StatusCode,
+		&i.IsCompleted,
+		&i.Description,
+		&i.ReferenceNumber,
+		&i.TransactionDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TransactionNumber,
+		&i.ConvertedAmount,
+		&i.ConvertedCurrencyCode,
+		&i.OriginalTransactionID,
+	)
+	return i, err
+}
+
 const updateTransactionStatus = `-- name: UpdateTransactionStatus :one
 UPDATE transactions
 SET status_code = $2
 WHERE transaction_id = $1
-RETURNING transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number
+RETURNING transaction_id, from_account_id, to_account_id, type_code, amount, currency_code, exchange_rate, status_code, is_completed, description, reference_number, transaction_date, created_at, updated_at, transaction_number, converted_amount, converted_currency_code, original_transaction_id
 `
 
 type UpdateTransactionStatusParams struct {
@@ -469,6 +659,9 @@ func (q *Queries) UpdateTransactionStatus(ctx context.Context, arg UpdateTransac
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TransactionNumber,
+		&i.ConvertedAmount,
+		&i.ConvertedCurrencyCode,
+		&i.OriginalTransactionID,
 	)
 	return i, err
 }
@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -21,7 +22,7 @@ INSERT INTO users (
     profile_image_url
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7
-) RETURNING user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at
+) RETURNING user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at, role
 `
 
 type CreateUserParams struct {
@@ -58,6 +59,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.LastLogin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -74,7 +76,7 @@ func (q *Queries) DeleteUser(ctx context.Context, userID int32) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at FROM users
+SELECT user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at, role FROM users
 WHERE user_id = $1
 `
 
@@ -94,6 +96,51 @@ func (q *Queries) GetUser(ctx context.Context, userID int32) (User, error) {
 		&i.LastLogin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const getUserAccess = `-- name: GetUserAccess :one
+SELECT role, is_active FROM users
+WHERE user_id = $1
+`
+
+type GetUserAccessRow struct {
+	Role     UserRole `json:"role"`
+	IsActive bool     `json:"is_active"`
+}
+
+// GetUserAccess returns what access control needs to know about a user, it is read on every request
+func (q *Queries) GetUserAccess(ctx context.Context, userID int32) (GetUserAccessRow, error) {
+	row := q.db.QueryRow(ctx, getUserAccess, userID)
+	var i GetUserAccessRow
+	err := row.Scan(&i.Role, &i.IsActive)
+	return i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+SELECT user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at, role FROM users
+WHERE username = $1
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.PhoneNumber,
+		&i.ProfileImageUrl,
+		&i.IsActive,
+		&i.LastLogin,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -109,7 +156,7 @@ func (q *Queries) HardDeleteUser(ctx context.Context, userID int32) error {
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at FROM users
+SELECT user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at, role FROM users
 ORDER BY user_id
 LIMIT $1 OFFSET $2
 `
@@ -141,6 +188,7 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.LastLogin,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -152,6 +200,22 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const updateLastLogin = `-- name: UpdateLastLogin :exec
+UPDATE users
+SET last_login = $1
+WHERE user_id = $2
+`
+
+type UpdateLastLoginParams struct {
+	LastLogin time.Time `json:"last_login"`
+	UserID    int32     `json:"user_id"`
+}
+
+func (q *Queries) UpdateLastLogin(ctx context.Context, arg UpdateLastLoginParams) error {
+	_, err := q.db.Exec(ctx, updateLastLogin, arg.LastLogin, arg.UserID)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users 
 SET 
@@ -163,7 +227,7 @@ SET
     profile_image_url = COALESCE($6, profile_image_url),
     is_active = COALESCE($7, is_active)
 WHERE user_id = $8
-RETURNING user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at
+RETURNING user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at, role
 `
 
 type UpdateUserParams struct {
@@ -202,6 +266,43 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.LastLogin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET role = $1
+WHERE user_id = $2
+RETURNING user_id, username, password_hash, email, first_name, last_name, phone_number, profile_image_url, is_active, last_login, created_at, updated_at, role
+`
+
+type UpdateUserRoleParams struct {
+	Role   UserRole `json:"role"`
+	UserID int32    `json:"user_id"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole,
+		arg.Role,
+		arg.UserID,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.PhoneNumber,
+		&i.ProfileImageUrl,
+		&i.IsActive,
+		&i.LastLogin,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
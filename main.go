@@ -1,21 +1,29 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/riad/banksystemendtoend/api"
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	"github.com/riad/banksystemendtoend/pkg/jobs"
 	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/common"
 	environment_config "github.com/riad/banksystemendtoend/util/config"
 	setup "github.com/riad/banksystemendtoend/util/db"
+	"github.com/riad/banksystemendtoend/util/exchangerate"
+	"github.com/riad/banksystemendtoend/util/hashchain"
 	"go.uber.org/zap"
 )
 
 // ! Application represents the main application configuration
 type Application struct {
 	server *api.Server
+	jobs   *jobs.Runner
 }
 
 // ! NewApplication initializes a new application instance
@@ -27,8 +35,48 @@ func NewApplication() (*Application, error) {
 		return nil, fmt.Errorf("failed to initialize server: %w", err)
 	}
 
+	seeded, err := transaction.SeedCurrencies(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to seed currencies: %w", err)
+	}
+	logger.GetLogger().Info("Currency registry is up to date", zap.Int64("seeded", seeded))
+
+	runner := jobs.NewRunner(
+		jobs.NewHoldExpiryJob(jobs.DefaultHoldExpiryInterval),
+		jobs.NewIdempotencyCleanupJob(jobs.DefaultIdempotencyCleanupInterval),
+		jobs.NewScheduledTransferJob(jobs.DefaultScheduledTransferInterval),
+		jobs.NewTransferBatchJob(jobs.DefaultTransferBatchInterval),
+		jobs.NewReconciliationJob(jobs.DefaultReconciliationInterval),
+		jobs.NewInterestJob(jobs.DefaultInterestInterval),
+		jobs.NewFeeJob(jobs.DefaultFeeInterval),
+		jobs.NewFixedDepositJob(jobs.DefaultFixedDepositInterval),
+	)
+	for _, job := range server.BackgroundJobs() {
+		runner.Register(job)
+	}
+
+	// Checkpoints are optional, the chains are still verifiable without them
+	signer, err := hashchain.SignerFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint key: %w", err)
+	}
+	if signer != nil {
+		runner.Register(jobs.NewHashChainCheckpointJob(jobs.DefaultHashChainCheckpointInterval, signer))
+	} else {
+		logger.GetLogger().Warn("AUDIT_CHECKPOINT_KEY is not set, hash chain checkpoints are disabled")
+	}
+
+	// Without a provider, rates are only changed by hand
+	if provider := exchangerate.ProviderFromEnv(); provider != nil {
+		interval := common.GetEnvAsDuration(exchangerate.RefreshIntervalEnv, jobs.DefaultExchangeRateInterval)
+		runner.Register(jobs.NewExchangeRateJob(interval, provider))
+	} else {
+		logger.GetLogger().Warn("No exchange rate provider is configured, exchange rates are not refreshed")
+	}
+
 	return &Application{
 		server: server,
+		jobs:   runner,
 	}, nil
 }
 
@@ -45,6 +93,10 @@ func run() error {
 
 	logger.GetLogger().Info("✅ Database connection established")
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	app.jobs.Start(ctx)
+
 	go func() {
 		port := os.Getenv("PORT")
 		if port == "" {
@@ -56,7 +108,10 @@ func run() error {
 		}
 	}()
 
-	return app.waitForShutdown()
+	err = app.waitForShutdown()
+	cancel()
+	app.jobs.Wait()
+	return err
 }
 
 // ! waitForShutdown handles graceful shutdown logic
@@ -72,6 +127,22 @@ func (app *Application) waitForShutdown() error {
 
 // !main is the entry point of the application
 func main() {
+	if len(os.Args) > 1 {
+		command, ok := commands[os.Args[1]]
+		if !ok {
+			fmt.Printf("❌ Unknown command: %s\n", os.Args[1])
+			os.Exit(1)
+		}
+		if err := command(os.Args[2:]); err != nil {
+			fmt.Printf("❌ Error occurred: %v\n", err)
+			if errors.Is(err, errDriftFound) || errors.Is(err, errChainBroken) {
+				os.Exit(2)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(); err != nil {
 		fmt.Printf("❌ Error occurred: %v\n", err)
 		os.Exit(1)
@@ -108,6 +108,26 @@ func (s *Service) SetWithTTL(ctx context.Context, key string, value interface{},
 	return nil
 }
 
+// SetIfAbsent stores a value in the cache with a custom TTL unless the key exists, and reports whether
+// it was stored. Unlike Set it fails while Redis is down, its callers rely on the answer.
+func (s *Service) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	if err := s.checkConnection(ctx); apperrors.IsRedisConnectionError(err) {
+		return false, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value for key %s: %w", key, err)
+	}
+	stored, err := s.redisClient.SetNX(ctx, s.buildKey(key), data, ttl)
+	if err != nil {
+		logger.GetLogger().Error("Failed to set key in cache",
+			zap.String("key", key),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to set key %s in cache: %w", key, err)
+	}
+	return stored, nil
+}
+
 // Delete removes a value from the cache
 func (s *Service) Delete(ctx context.Context, key string) error {
 	err := s.checkConnection(ctx)
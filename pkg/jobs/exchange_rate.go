@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/exchangerate"
+	"go.uber.org/zap"
+)
+
+// DefaultExchangeRateInterval is how often exchange rates are fetched from the provider
+const DefaultExchangeRateInterval = time.Hour
+
+// ExchangeRateJob fetches the rates of an exchange rate provider and records them in the rate history
+type ExchangeRateJob struct {
+	interval time.Duration
+	provider exchangerate.ExchangeRateProvider
+}
+
+// NewExchangeRateJob creates the exchange rate refresher, a zero interval uses DefaultExchangeRateInterval
+func NewExchangeRateJob(interval time.Duration, provider exchangerate.ExchangeRateProvider) *ExchangeRateJob {
+	if interval <= 0 {
+		interval = DefaultExchangeRateInterval
+	}
+	return &ExchangeRateJob{interval: interval, provider: provider}
+}
+
+func (j *ExchangeRateJob) Name() string {
+	return "exchange_rates"
+}
+
+func (j *ExchangeRateJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run fetches the current rates and records the ones that changed
+func (j *ExchangeRateJob) Run(ctx context.Context) error {
+	rates, err := j.provider.FetchRates(ctx)
+	if err != nil {
+		return fmt.Errorf("%s provider: %w", j.provider.Name(), err)
+	}
+
+	result, err := transaction.RecordExchangeRates(ctx, j.provider.Name(), rates)
+	if err != nil {
+		return err
+	}
+	if len(result.Skipped) > 0 {
+		logger.GetLogger().Warn("Skipped exchange rates",
+			zap.String("source", result.Source),
+			zap.Strings("currencies", result.Skipped))
+	}
+	if result.Recorded > 0 {
+		logger.GetLogger().Info("Recorded exchange rates",
+			zap.String("source", result.Source),
+			zap.Int("recorded", result.Recorded),
+			zap.Int("unchanged", result.Unchanged),
+			zap.Int64("currencies_updated", result.Updated))
+	}
+	return nil
+}
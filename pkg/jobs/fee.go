@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+// DefaultFeeInterval is how often the fee job looks for maintenance and overdraft fees to charge
+const DefaultFeeInterval = time.Hour
+
+// FeeJob charges the monthly maintenance fee and the daily overdraft fee
+type FeeJob struct {
+	interval time.Duration
+}
+
+// NewFeeJob creates the fee job, a zero interval uses DefaultFeeInterval
+func NewFeeJob(interval time.Duration) *FeeJob {
+	if interval <= 0 {
+		interval = DefaultFeeInterval
+	}
+	return &FeeJob{interval: interval}
+}
+
+func (j *FeeJob) Name() string {
+	return "periodic_fees"
+}
+
+func (j *FeeJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run charges the fees of yesterday (UTC), the last day whose end of day balances are final.
+// Charges that fail are logged and retried on the next run.
+func (j *FeeJob) Run(ctx context.Context) error {
+	result, err := transaction.RunPeriodicFees(ctx, time.Now().UTC().AddDate(0, 0, -1))
+	if err != nil {
+		return err
+	}
+	for _, failure := range result.Failures {
+		logger.GetLogger().Warn("Failed to charge periodic fee", zap.Error(failure))
+	}
+	if result.Charged > 0 {
+		logger.GetLogger().Info("Charged periodic fees", zap.Int("charged", result.Charged))
+	}
+	return nil
+}
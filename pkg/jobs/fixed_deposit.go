@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultFixedDepositInterval is how often matured fixed deposits are paid out or rolled over
+	DefaultFixedDepositInterval = time.Hour
+	fixedDepositBatchSize       = 100
+)
+
+// FixedDepositJob credits the interest of matured fixed deposits and applies their maturity instruction
+type FixedDepositJob struct {
+	interval time.Duration
+}
+
+// NewFixedDepositJob creates the fixed deposit maturity job, a zero interval uses DefaultFixedDepositInterval
+func NewFixedDepositJob(interval time.Duration) *FixedDepositJob {
+	if interval <= 0 {
+		interval = DefaultFixedDepositInterval
+	}
+	return &FixedDepositJob{interval: interval}
+}
+
+func (j *FixedDepositJob) Name() string {
+	return "fixed_deposit_maturity"
+}
+
+func (j *FixedDepositJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run matures every deposit due today (UTC) or earlier, in batches until none are left
+func (j *FixedDepositJob) Run(ctx context.Context) error {
+	total := 0
+	for ctx.Err() == nil {
+		matured, err := transaction.MatureFixedDeposits(ctx, time.Now(), fixedDepositBatchSize)
+		total += matured
+		if err != nil {
+			return err
+		}
+		if matured < fixedDepositBatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		logger.GetLogger().Info("Matured fixed deposits", zap.Int("count", total))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/util/hashchain"
+	"go.uber.org/zap"
+)
+
+// DefaultHashChainCheckpointInterval is how often the hash chain heads are signed
+const DefaultHashChainCheckpointInterval = time.Hour
+
+// HashChainCheckpointJob signs the heads of the audit_trail and entries hash chains, so a chain
+// rewritten after a checkpoint no longer verifies
+type HashChainCheckpointJob struct {
+	interval time.Duration
+	signer   *hashchain.Signer
+}
+
+// NewHashChainCheckpointJob creates the checkpoint job, a zero interval uses DefaultHashChainCheckpointInterval
+func NewHashChainCheckpointJob(interval time.Duration, signer *hashchain.Signer) *HashChainCheckpointJob {
+	if interval <= 0 {
+		interval = DefaultHashChainCheckpointInterval
+	}
+	return &HashChainCheckpointJob{interval: interval, signer: signer}
+}
+
+func (j *HashChainCheckpointJob) Name() string {
+	return "hash_chain_checkpoints"
+}
+
+func (j *HashChainCheckpointJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run signs every chain that moved since its last checkpoint
+func (j *HashChainCheckpointJob) Run(ctx context.Context) error {
+	checkpoints, err := transaction.CreateHashChainCheckpoints(ctx, j.signer)
+	for _, checkpoint := range checkpoints {
+		logger.GetLogger().Info("Signed hash chain checkpoint",
+			zap.String("chain", checkpoint.ChainName),
+			zap.Int64("chain_seq", checkpoint.ChainSeq))
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHoldExpiryInterval is how often expired authorization holds are released
+	DefaultHoldExpiryInterval = time.Minute
+	holdExpiryBatchSize       = 100
+)
+
+// HoldExpiryJob releases authorization holds whose expiry has passed
+type HoldExpiryJob struct {
+	interval time.Duration
+}
+
+// NewHoldExpiryJob creates the hold expiry job, a zero interval uses DefaultHoldExpiryInterval
+func NewHoldExpiryJob(interval time.Duration) *HoldExpiryJob {
+	if interval <= 0 {
+		interval = DefaultHoldExpiryInterval
+	}
+	return &HoldExpiryJob{interval: interval}
+}
+
+func (j *HoldExpiryJob) Name() string {
+	return "hold_expiry"
+}
+
+func (j *HoldExpiryJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run releases expired holds in batches until none are left
+func (j *HoldExpiryJob) Run(ctx context.Context) error {
+	total := 0
+	for ctx.Err() == nil {
+		expired, err := transaction.ExpireHolds(ctx, holdExpiryBatchSize)
+		if err != nil {
+			return err
+		}
+		total += expired
+		if expired < holdExpiryBatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		logger.GetLogger().Info("Released expired holds", zap.Int("count", total))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+// DefaultIdempotencyCleanupInterval is how often expired idempotency keys are deleted
+const DefaultIdempotencyCleanupInterval = time.Hour
+
+// IdempotencyCleanupJob deletes the idempotency keys whose responses can no longer be replayed
+type IdempotencyCleanupJob struct {
+	interval time.Duration
+}
+
+// NewIdempotencyCleanupJob creates the cleanup job, a zero interval uses DefaultIdempotencyCleanupInterval
+func NewIdempotencyCleanupJob(interval time.Duration) *IdempotencyCleanupJob {
+	if interval <= 0 {
+		interval = DefaultIdempotencyCleanupInterval
+	}
+	return &IdempotencyCleanupJob{interval: interval}
+}
+
+func (j *IdempotencyCleanupJob) Name() string {
+	return "idempotency_cleanup"
+}
+
+func (j *IdempotencyCleanupJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run deletes every expired idempotency key
+func (j *IdempotencyCleanupJob) Run(ctx context.Context) error {
+	deleted, err := transaction.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		logger.GetLogger().Info("Deleted expired idempotency keys", zap.Int64("count", deleted))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+// DefaultInterestInterval is how often the interest job looks for days to accrue and periods to post
+const DefaultInterestInterval = time.Hour
+
+// InterestJob accrues interest for every completed day and posts it when a period ends
+type InterestJob struct {
+	interval time.Duration
+}
+
+// NewInterestJob creates the interest job, a zero interval uses DefaultInterestInterval
+func NewInterestJob(interval time.Duration) *InterestJob {
+	if interval <= 0 {
+		interval = DefaultInterestInterval
+	}
+	return &InterestJob{interval: interval}
+}
+
+func (j *InterestJob) Name() string {
+	return "interest_accrual"
+}
+
+func (j *InterestJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run accrues up to yesterday (UTC), the last day whose end of day balances are final
+func (j *InterestJob) Run(ctx context.Context) error {
+	result, err := transaction.RunInterest(ctx, time.Now().UTC().AddDate(0, 0, -1))
+	if err != nil {
+		return err
+	}
+	if result.Remaining > 0 {
+		logger.GetLogger().Warn("Interest is behind, the next run catches up the remaining days",
+			zap.String("through", result.Through.Format(time.DateOnly)),
+			zap.Int("remaining", result.Remaining))
+	}
+	if result.Accrued+result.Posted > 0 {
+		logger.GetLogger().Info("Processed interest",
+			zap.Int("days", result.Days),
+			zap.Int("accrued", result.Accrued),
+			zap.Int("posted", result.Posted))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+// DefaultReconciliationInterval is how often the whole ledger is reconciled
+const DefaultReconciliationInterval = 24 * time.Hour
+
+// ReconciliationJob reconciles every account against its entries and refreshes the drift metrics
+type ReconciliationJob struct {
+	interval time.Duration
+}
+
+// NewReconciliationJob creates the reconciliation job, a zero interval uses DefaultReconciliationInterval
+func NewReconciliationJob(interval time.Duration) *ReconciliationJob {
+	if interval <= 0 {
+		interval = DefaultReconciliationInterval
+	}
+	return &ReconciliationJob{interval: interval}
+}
+
+func (j *ReconciliationJob) Name() string {
+	return "ledger_reconciliation"
+}
+
+func (j *ReconciliationJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run reconciles the full ledger, discrepancies are logged as warnings
+func (j *ReconciliationJob) Run(ctx context.Context) error {
+	result, err := transaction.RunReconciliation(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	log := logger.GetLogger().Info
+	if result.Run.DiscrepancyCount > 0 {
+		log = logger.GetLogger().Warn
+	}
+	log("Reconciled ledger",
+		zap.String("run_number", result.Run.RunNumber.String()),
+		zap.Int32("accounts_checked", result.Run.AccountsChecked),
+		zap.Int32("transactions_checked", result.Run.TransactionsChecked),
+		zap.Int32("discrepancies", result.Run.DiscrepancyCount))
+	return nil
+}
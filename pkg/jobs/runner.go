@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+// Job is a unit of background work executed periodically by the Runner
+type Job interface {
+	// Name identifies the job in logs
+	Name() string
+	// Interval is the time to wait between two runs
+	Interval() time.Duration
+	// Run executes the job once, it should return when ctx is cancelled
+	Run(ctx context.Context) error
+}
+
+// Runner executes registered jobs on their own interval until its context is cancelled
+type Runner struct {
+	jobs []Job
+	wg   sync.WaitGroup
+}
+
+// NewRunner creates a runner for the given jobs
+func NewRunner(jobs ...Job) *Runner {
+	return &Runner{jobs: jobs}
+}
+
+// Register adds a job to the runner, it must be called before Start
+func (r *Runner) Register(job Job) {
+	r.jobs = append(r.jobs, job)
+}
+
+// Start launches every registered job in its own goroutine. Each job runs once immediately and
+// then every Interval; runs of the same job never overlap.
+func (r *Runner) Start(ctx context.Context) {
+	for _, job := range r.jobs {
+		r.wg.Add(1)
+		go func(job Job) {
+			defer r.wg.Done()
+			r.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Wait blocks until every job has returned after the context passed to Start is cancelled
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval())
+	defer ticker.Stop()
+
+	for {
+		r.runOnce(ctx, job)
+		select {
+		case <-ctx.Done():
+			logger.GetLogger().Info("Job stopped", zap.String("job", job.Name()))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.GetLogger().Error("Job panicked",
+				zap.String("job", job.Name()),
+				zap.Any("panic", rec))
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+		logger.GetLogger().Error("Job failed",
+			zap.String("job", job.Name()),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err))
+	}
+}
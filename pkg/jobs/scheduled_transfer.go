@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultScheduledTransferInterval is how often the scheduler looks for due transfers
+	DefaultScheduledTransferInterval = time.Minute
+	scheduledTransferBatchSize       = 50
+)
+
+// ScheduledTransferJob executes standing orders and one-off transfers once they fall due
+type ScheduledTransferJob struct {
+	interval time.Duration
+}
+
+// NewScheduledTransferJob creates the scheduler job, a zero interval uses DefaultScheduledTransferInterval
+func NewScheduledTransferJob(interval time.Duration) *ScheduledTransferJob {
+	if interval <= 0 {
+		interval = DefaultScheduledTransferInterval
+	}
+	return &ScheduledTransferJob{interval: interval}
+}
+
+func (j *ScheduledTransferJob) Name() string {
+	return "scheduled_transfers"
+}
+
+func (j *ScheduledTransferJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run executes due transfers in batches until none are left
+func (j *ScheduledTransferJob) Run(ctx context.Context) error {
+	executed, failed := 0, 0
+	for ctx.Err() == nil {
+		result, err := transaction.ExecuteDueScheduledTransfers(ctx, scheduledTransferBatchSize)
+		if err != nil {
+			return err
+		}
+		executed += result.Executed
+		failed += result.Failed
+		if result.Executed+result.Failed < scheduledTransferBatchSize {
+			break
+		}
+	}
+	if executed+failed > 0 {
+		logger.GetLogger().Info("Processed scheduled transfers",
+			zap.Int("executed", executed),
+			zap.Int("failed", failed))
+	}
+	return nil
+}
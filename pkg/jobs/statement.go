@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultStatementInterval is how often queued statements are picked up
+const DefaultStatementInterval = 30 * time.Second
+
+// StatementJob generates the statements that were requested in the background
+type StatementJob struct {
+	interval time.Duration
+	process  func(ctx context.Context) error
+}
+
+// NewStatementJob creates the statement job around process, a zero interval uses DefaultStatementInterval
+func NewStatementJob(interval time.Duration, process func(ctx context.Context) error) *StatementJob {
+	if interval <= 0 {
+		interval = DefaultStatementInterval
+	}
+	return &StatementJob{interval: interval, process: process}
+}
+
+func (j *StatementJob) Name() string {
+	return "statement_exports"
+}
+
+func (j *StatementJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *StatementJob) Run(ctx context.Context) error {
+	return j.process(ctx)
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/riad/banksystemendtoend/db/sqlc/transaction"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"go.uber.org/zap"
+)
+
+// DefaultTransferBatchInterval is how often the batch worker looks for pending batches
+const DefaultTransferBatchInterval = 15 * time.Second
+
+// TransferBatchJob executes pending batch transfers one batch at a time
+type TransferBatchJob struct {
+	interval time.Duration
+}
+
+// NewTransferBatchJob creates the batch worker job, a zero interval uses DefaultTransferBatchInterval
+func NewTransferBatchJob(interval time.Duration) *TransferBatchJob {
+	if interval <= 0 {
+		interval = DefaultTransferBatchInterval
+	}
+	return &TransferBatchJob{interval: interval}
+}
+
+func (j *TransferBatchJob) Name() string {
+	return "transfer_batches"
+}
+
+func (j *TransferBatchJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run processes batches until none are pending
+func (j *TransferBatchJob) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		batch, ok, err := transaction.ProcessNextTransferBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		logger.GetLogger().Info("Processed transfer batch",
+			zap.String("batch_number", batch.BatchNumber.String()),
+			zap.String("status", string(batch.Status)),
+			zap.Int32("succeeded", batch.SucceededItems),
+			zap.Int32("failed", batch.FailedItems))
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	upload_service "github.com/riad/banksystemendtoend/pkg/service"
+)
+
+// uploadConsumerRestartDelay is how long the runner waits before consuming again after the queue closed
+const uploadConsumerRestartDelay = 5 * time.Second
+
+// UploadConsumerJob hands queued upload jobs to a handler. Run blocks while the queue is open, the
+// runner restarts it after a disconnect.
+type UploadConsumerJob struct {
+	name    string
+	uploads *upload_service.UploadService
+	handler upload_service.UploadHandler
+}
+
+// NewUploadConsumerJob creates a consumer for the queue of uploads, name also tags the consumer
+func NewUploadConsumerJob(name string, uploads *upload_service.UploadService,
+	handler upload_service.UploadHandler) *UploadConsumerJob {
+	return &UploadConsumerJob{name: name, uploads: uploads, handler: handler}
+}
+
+func (j *UploadConsumerJob) Name() string {
+	return j.name
+}
+
+func (j *UploadConsumerJob) Interval() time.Duration {
+	return uploadConsumerRestartDelay
+}
+
+func (j *UploadConsumerJob) Run(ctx context.Context) error {
+	return j.uploads.Consume(ctx, j.name, j.handler)
+}
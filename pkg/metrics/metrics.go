@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ledger gauges, refreshed by every full reconciliation run
+var (
+	ledgerBalanceDrift  = expvar.NewMap("ledger_balance_drift")
+	ledgerDiscrepancies = expvar.NewMap("ledger_discrepancies")
+	ledgerLastRun       = expvar.NewInt("ledger_reconciliation_last_run_unix")
+)
+
+// ObserveReconciliation replaces the ledger gauges: drift is the absolute balance mismatch per
+// currency and discrepancies the number of findings per discrepancy type
+func ObserveReconciliation(drift map[string]float64, discrepancies map[string]int64, at time.Time) {
+	ledgerBalanceDrift.Init()
+	for currency, value := range drift {
+		gauge := new(expvar.Float)
+		gauge.Set(value)
+		ledgerBalanceDrift.Set(currency, gauge)
+	}
+
+	ledgerDiscrepancies.Init()
+	for discrepancyType, count := range discrepancies {
+		gauge := new(expvar.Int)
+		gauge.Set(count)
+		ledgerDiscrepancies.Set(discrepancyType, gauge)
+	}
+
+	ledgerLastRun.Set(at.Unix())
+}
+
+// Handler serves every published metric as JSON
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(expvar.Handler())
+}
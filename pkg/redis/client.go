@@ -65,6 +65,14 @@ func (c *Client) Set(ctx context.Context, key string, value interface{}, expirat
 	return c.client.Set(ctx, key, value, expiration).Err()
 }
 
+// SetNX stores a value in Redis with an expiration time unless the key exists, and reports whether it was stored
+func (c *Client) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	if c == nil || c.client == nil {
+		return false, fmt.Errorf("redis client is nil")
+	}
+	return c.client.SetNX(ctx, key, value, expiration).Result()
+}
+
 // Delete removes a key from Redis
 func (c *Client) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, key).Err()
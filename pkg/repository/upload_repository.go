@@ -77,7 +77,7 @@ func (r *SQLUploadRepository) CreateUploadJob(ctx context.Context, job *model.Up
 }
 
 // ! GetUploadJob retrieves an upload job by its ID
-func (r *SQLUploadRepository) GetUploadJob(ctx context.Context, id int64) (*model.UploadJob, error) {
+func (r *SQLUploadRepository) GetUploadJob(ctx context.Context, id string) (*model.UploadJob, error) {
 	// Try to get from cache first
 	cacheKey := fmt.Sprintf(uploadJobCacheKey, id)
 
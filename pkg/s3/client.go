@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -43,6 +44,21 @@ func DefaultS3Config() S3ServiceConfig {
 	}
 }
 
+// ! S3ConfigFromEnv builds the S3 configuration from AWS_* environment variables, falling back to defaults
+func S3ConfigFromEnv() S3ServiceConfig {
+	cfg := DefaultS3Config()
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		cfg.Region = region
+	}
+	if bucket := os.Getenv("AWS_BUCKET"); bucket != "" {
+		cfg.Bucket = bucket
+	}
+	if prefix := os.Getenv("AWS_PREFIX"); prefix != "" {
+		cfg.Prefix = prefix
+	}
+	return cfg
+}
+
 // ! S3Service provides operations with AWS S3
 type S3Service struct {
 	client          *s3.Client
@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
 	pkg_interface "github.com/riad/banksystemendtoend/pkg/interface"
 	logger "github.com/riad/banksystemendtoend/pkg/log"
 	"github.com/riad/banksystemendtoend/pkg/model"
@@ -32,6 +35,16 @@ Users can query job status via GetUploadStatus
 
 */
 
+// uploadRetryDelay is how long a job that is not ready waits before it is put back on the queue
+const uploadRetryDelay = time.Second
+
+// ErrUploadNotReady is returned by an UploadHandler when the job cannot be processed yet, the
+// message is put back on the queue instead of failing the job
+var ErrUploadNotReady = errors.New("upload is not ready to be processed")
+
+// UploadHandler processes an upload job taken from the queue
+type UploadHandler func(ctx context.Context, job *model.UploadJob) error
+
 // UploadService handles file upload operations
 type UploadService struct {
 	tempDir        string
@@ -132,6 +145,84 @@ func (s *UploadService) HandleFileUpload(ctx context.Context, file *multipart.Fi
 	return uploadID, nil
 }
 
+// Consume delivers queued upload jobs to handler until ctx is cancelled or the queue is closed.
+// The job is marked PROCESSING while it is handled and COMPLETED or FAILED afterwards.
+func (s *UploadService) Consume(ctx context.Context, consumer string, handler UploadHandler) error {
+	if err := s.rmqClient.QoS(1, 0, false); err != nil {
+		return fmt.Errorf("failed to set QoS: %w", err)
+	}
+	deliveries, err := s.rmqClient.Consume(s.uploadQueue, consumer, false, false)
+	if err != nil {
+		logger.GetLogger().Error("failed to consume upload queue", zap.String("queue", s.uploadQueue), zap.Error(err))
+		return fmt.Errorf("failed to consume upload queue: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("upload queue %s was closed", s.uploadQueue)
+			}
+			s.handleDelivery(ctx, delivery, handler)
+		}
+	}
+}
+
+// handleDelivery runs handler for one message and acknowledges it
+func (s *UploadService) handleDelivery(ctx context.Context, delivery amqp.Delivery, handler UploadHandler) {
+	var queued model.UploadJob
+	if err := json.Unmarshal(delivery.Body, &queued); err != nil {
+		logger.GetLogger().Error("failed to decode upload job", zap.Error(err))
+		delivery.Reject(false)
+		return
+	}
+
+	job, err := s.uploadRepo.GetUploadJob(ctx, queued.ID)
+	if err != nil {
+		logger.GetLogger().Error("failed to get upload job", zap.String("job_id", queued.ID), zap.Error(err))
+		delivery.Nack(false, true)
+		return
+	}
+	if job.Status != model.UploadStatusPending && job.Status != model.UploadStatusProcessing {
+		// Redelivery of a job that was already handled
+		delivery.Ack(false)
+		return
+	}
+
+	job.Status = model.UploadStatusProcessing
+	if err := s.uploadRepo.UpdateUploadJob(ctx, job); err != nil {
+		logger.GetLogger().Error("failed to update upload job", zap.String("job_id", job.ID), zap.Error(err))
+		delivery.Nack(false, true)
+		return
+	}
+
+	err = handler(ctx, job)
+	if errors.Is(err, ErrUploadNotReady) {
+		job.Status = model.UploadStatusPending
+		s.uploadRepo.UpdateUploadJob(ctx, job)
+		// Give the producer a moment before the message comes back
+		select {
+		case <-ctx.Done():
+		case <-time.After(uploadRetryDelay):
+		}
+		delivery.Nack(false, true)
+		return
+	}
+
+	job.Status = model.UploadStatusCompleted
+	if err != nil {
+		job.Status = model.UploadStatusFailed
+		job.Error = err.Error()
+		logger.GetLogger().Error("failed to process upload job", zap.String("job_id", job.ID), zap.Error(err))
+	}
+	if err := s.uploadRepo.UpdateUploadJob(ctx, job); err != nil {
+		logger.GetLogger().Error("failed to update upload job", zap.String("job_id", job.ID), zap.Error(err))
+	}
+	delivery.Ack(false)
+}
+
 // saveToTempStorage saves the uploaded file to temporary storage
 func (s *UploadService) saveToTempStorage(file *multipart.FileHeader, tempPath string) error {
 	src, err := file.Open()
@@ -156,7 +247,7 @@ func (s *UploadService) saveToTempStorage(file *multipart.FileHeader, tempPath s
 }
 
 // GetUploadStatus retrieves the status of an upload job
-func (s *UploadService) GetUploadStatus(ctx context.Context, jobID int64) (*model.UploadJob, error) {
+func (s *UploadService) GetUploadStatus(ctx context.Context, jobID string) (*model.UploadJob, error) {
 	job, err := s.uploadRepo.GetUploadJob(ctx, jobID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get upload job: %w", err)
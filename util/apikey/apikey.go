@@ -0,0 +1,104 @@
+// Package apikey generates the API keys clients identify themselves with and decides what their
+// scopes allow. A key reads bsk_<prefix>_<secret>: the prefix finds the key in the database and the
+// SHA-256 of the whole key is compared with the stored hash.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// ScopeReadOnly keys may only send requests that read
+	ScopeReadOnly = "READ_ONLY"
+	// ScopeTransfers keys may also send requests that change data, transfers among them
+	ScopeTransfers = "TRANSFERS"
+	// ScopeAdmin keys may also call back office routes
+	ScopeAdmin = "ADMIN"
+
+	// RotationOverlapEnv overrides how long a rotated key keeps working next to its replacement
+	RotationOverlapEnv     = "API_KEY_ROTATION_OVERLAP"
+	DefaultRotationOverlap = 24 * time.Hour
+
+	keyPrefix  = "bsk"
+	prefixSize = 6
+	secretSize = 32
+)
+
+// Scopes are the scopes keys can be issued with
+var Scopes = []string{ScopeReadOnly, ScopeTransfers, ScopeAdmin}
+
+// Generate returns a new key, the prefix it is looked up by and the hash it is stored as
+func Generate() (key, prefix, hash string, err error) {
+	buf := make([]byte, prefixSize+secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("error generating API key: %w", err)
+	}
+	prefix = hex.EncodeToString(buf[:prefixSize])
+	key = keyPrefix + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(buf[prefixSize:])
+	return key, prefix, Hash(key), nil
+}
+
+// Prefix returns the prefix of key, and false when key is not shaped like an API key
+func Prefix(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix || len(parts[1]) != 2*prefixSize || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// Hash returns the hex encoded SHA-256 of a key, as it is stored. Keys are random, so a plain hash
+// is enough to keep a database copy from being usable.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches reports whether key hashes to hash, in constant time
+func Matches(key, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(key)), []byte(hash)) == 1
+}
+
+// ValidScopes reports whether scopes is a non empty list of known scopes
+func ValidScopes(scopes []string) bool {
+	if len(scopes) == 0 {
+		return false
+	}
+	for _, scope := range scopes {
+		if !HasScope(Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasScope reports whether scopes contains scope
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsMethod reports whether a key with scopes may send a request with method. Keys with only
+// the READ_ONLY scope are limited to requests that do not change anything.
+func AllowsMethod(scopes []string, method string) bool {
+	if HasScope(scopes, ScopeTransfers) || HasScope(scopes, ScopeAdmin) {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return HasScope(scopes, ScopeReadOnly)
+	}
+	return false
+}
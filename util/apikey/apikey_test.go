@@ -0,0 +1,69 @@
+package apikey_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/riad/banksystemendtoend/util/apikey"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyPrefix(t *testing.T) {
+	key, prefix, hash, err := apikey.Generate()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key, "bsk_"+prefix+"_"))
+
+	parsed, ok := apikey.Prefix(key)
+	require.True(t, ok)
+	require.Equal(t, prefix, parsed)
+	require.Equal(t, apikey.Hash(key), hash)
+
+	//? The secret may contain underscores, only the first two separate the parts
+	parsed, ok = apikey.Prefix("bsk_0123456789ab_se_cr_et")
+	require.True(t, ok)
+	require.Equal(t, "0123456789ab", parsed)
+
+	for _, malformed := range []string{
+		"",
+		"bsk",
+		"bsk_0123456789ab",
+		"bsk_0123456789ab_",
+		"xyz_0123456789ab_secret",
+		"bsk_0123_secret",
+		"bsk_0123456789abcd_secret",
+	} {
+		_, ok := apikey.Prefix(malformed)
+		require.False(t, ok, malformed)
+	}
+}
+
+func TestAPIKeyMatches(t *testing.T) {
+	key, _, hash, err := apikey.Generate()
+	require.NoError(t, err)
+	require.True(t, apikey.Matches(key, hash))
+
+	other, _, otherHash, err := apikey.Generate()
+	require.NoError(t, err)
+	require.NotEqual(t, key, other)
+	require.False(t, apikey.Matches(other, hash))
+	require.False(t, apikey.Matches(key, otherHash))
+
+	//? Keys are compared whole, a key with the right prefix and a wrong secret does not match
+	require.False(t, apikey.Matches(key+"x", hash))
+	require.False(t, apikey.Matches(key, ""))
+	require.False(t, apikey.Matches(key, strings.ToUpper(hash)))
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	require.True(t, apikey.ValidScopes([]string{apikey.ScopeReadOnly, apikey.ScopeTransfers}))
+	require.False(t, apikey.ValidScopes(nil))
+	require.False(t, apikey.ValidScopes([]string{apikey.ScopeReadOnly, "SUPERUSER"}))
+
+	readOnly := []string{apikey.ScopeReadOnly}
+	require.True(t, apikey.AllowsMethod(readOnly, http.MethodGet))
+	require.False(t, apikey.AllowsMethod(readOnly, http.MethodPost))
+	require.False(t, apikey.AllowsMethod(readOnly, http.MethodDelete))
+	require.True(t, apikey.AllowsMethod([]string{apikey.ScopeTransfers}, http.MethodPost))
+	require.True(t, apikey.AllowsMethod([]string{apikey.ScopeAdmin}, http.MethodDelete))
+}
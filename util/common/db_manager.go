@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v4/pgxpool"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
 )
 
 // ! SetupDBPool establishes a connection pool to PostgreSQL using environment-based configuration.
@@ -33,6 +34,9 @@ func SetupDBPool(ctx context.Context, envPrefix string) (*pgxpool.Pool, error) {
 	config.MaxConnIdleTime = GetEnvAsDuration(envPrefix+"_DB_CONN_IDLE_TIME", 30*time.Minute)
 	config.HealthCheckPeriod = GetEnvAsDuration(envPrefix+"_DB_HEALTH_CHECK_PERIOD", 1*time.Minute)
 
+	// Let the audit_trail triggers see who each change is made for
+	db.ConfigureAuditSession(config)
+
 	maxRetries := GetEnvAsInt(envPrefix+"_DB_MAX_RETRIES", 3)
 	var pool *pgxpool.Pool
 
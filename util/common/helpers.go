@@ -21,6 +21,18 @@ func SetNumeric(value string) (pgtype.Numeric, error) {
 	return numeric, nil
 }
 
+// !NumericToFloat64 converts a numeric value to float64, treating NULL as zero
+func NumericToFloat64(num pgtype.Numeric) float64 {
+	var value float64
+	if num.Status != pgtype.Present {
+		return 0
+	}
+	if err := num.AssignTo(&value); err != nil {
+		return 0
+	}
+	return value
+}
+
 // !GetEnvAsInt safely gets on environment variable as integer
 func GetEnvAsInt(key string, defaultVal int) int {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// GetEnvAsDuration reads an environment variable as a duration such as "90s", falling back to
+// defaultVal when it is not set or not a duration
+func GetEnvAsDuration(key string, defaultVal time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultVal
+}
@@ -1,5 +1,7 @@
 package config
 
+import "github.com/riad/banksystemendtoend/util/iso4217"
+
 // TransactionType defines different types of financial transactions
 type TransactionType struct {
 	TRANSFER   string
@@ -10,6 +12,7 @@ type TransactionType struct {
 	ADJUSTMENT string
 	FEE        string
 	INTEREST   string
+	REVERSAL   string
 }
 
 // TransactionStatus defines possible states of a transaction
@@ -21,14 +24,6 @@ type TransactionStatus struct {
 	REVERSED  string
 }
 
-// Currency defines possible states of a currencies
-type Currency struct {
-	CODE   string
-	NAME   string
-	SYMBOL string
-	RATE   float64
-}
-
 // Pre-defined transaction types
 var TransactionTypes = TransactionType{
 	TRANSFER:   "TRANSFER",
@@ -39,6 +34,7 @@ var TransactionTypes = TransactionType{
 	ADJUSTMENT: "ADJUSTMENT",
 	FEE:        "FEE",
 	INTEREST:   "INTEREST",
+	REVERSAL:   "REVERSAL",
 }
 
 // Pre-defined transaction statuses
@@ -50,35 +46,11 @@ var TransactionStatuses = TransactionStatus{
 	REVERSED:  "REVERSED",
 }
 
-// Pre-defined currencies statuses
-var TransactionCurrencies = struct {
-	USD Currency
-	EUR Currency
-	GBP Currency
-	JPY Currency
-}{
-	USD: Currency{
-		CODE:   "USD",
-		NAME:   "US Dollar",
-		SYMBOL: "$",
-		RATE:   1.0,
-	},
-	EUR: Currency{
-		CODE:   "EUR",
-		NAME:   "Euro",
-		SYMBOL: "€",
-		RATE:   1.08,
-	},
-	GBP: Currency{
-		CODE:   "GBP",
-		NAME:   "British Pound",
-		SYMBOL: "£",
-		RATE:   1.26,
-	},
-	JPY: Currency{
-		CODE:   "Japanese Yen",
-		NAME:   "Japanese Yen",
-		SYMBOL: "¥",
-		RATE:   0.0067,
-	},
+// DefaultCurrencies are active in a new currency registry, with the rate each starts at until a
+// rate provider or an admin sets one. Rates are the value of one unit in the base currency, USD.
+var DefaultCurrencies = map[string]float64{
+	iso4217.USD: 1.0,
+	iso4217.EUR: 1.08,
+	iso4217.GBP: 1.26,
+	iso4217.JPY: 0.0067,
 }
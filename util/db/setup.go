@@ -12,6 +12,7 @@ import (
 	"github.com/riad/banksystemendtoend/util/common"
 	"github.com/riad/banksystemendtoend/util/config"
 	"github.com/riad/banksystemendtoend/util/env"
+	queue_setup "github.com/riad/banksystemendtoend/util/queue"
 	"go.uber.org/zap"
 )
 
@@ -86,6 +87,11 @@ func InitializeEnvironment(environment config.Environment) error {
 		return nil
 	}
 
+	// Initialize RabbitMQ, the application runs without it when it is not configured
+	if _, err := queue_setup.InitializeRabbitMQ(); err != nil {
+		log.Error("Failed to initialize RabbitMQ", zap.Error(err))
+	}
+
 	log.Info("Successfully connected to database", zap.String("environment", string(environment.AppEnv)))
 	return nil
 }
@@ -0,0 +1,97 @@
+package exchangerate
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FileProvider reads rates from a CSV file, for offline use and tests. The file has a header row
+// naming a currency_code and a rate column, and optionally a valid_from column holding RFC 3339
+// timestamps; rows without one are valid from the time the file is read. The file is read again
+// on every fetch, so it can be replaced while the application runs.
+type FileProvider struct {
+	path string
+}
+
+// NewFileProvider creates a provider reading the CSV file at path
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+func (p *FileProvider) Name() string {
+	return "file"
+}
+
+func (p *FileProvider) FetchRates(ctx context.Context) ([]Rate, error) {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rates file: %w", err)
+	}
+	defer file.Close()
+	return ReadCSV(file, time.Now())
+}
+
+// ReadCSV parses rates in the FileProvider format, rows without a valid_from are valid from now
+func ReadCSV(r io.Reader, now time.Time) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidRates)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	codeCol, hasCode := columns["currency_code"]
+	rateCol, hasRate := columns["rate"]
+	validCol, hasValid := columns["valid_from"]
+	if !hasCode || !hasRate {
+		return nil, fmt.Errorf("%w: header must name currency_code and rate columns", ErrInvalidRates)
+	}
+
+	var rates []Rate
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRates, err)
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(col int) string {
+			if col < len(record) {
+				return strings.TrimSpace(record[col])
+			}
+			return ""
+		}
+
+		rate := Rate{CurrencyCode: strings.ToUpper(field(codeCol)), ValidFrom: now}
+		if len(rate.CurrencyCode) != 3 {
+			return nil, fmt.Errorf("%w: line %d: currency_code must be 3 letters", ErrInvalidRates, line)
+		}
+		rate.Rate, err = decimal.NewFromString(field(rateCol))
+		if err != nil || !rate.Rate.IsPositive() {
+			return nil, fmt.Errorf("%w: line %d: rate must be a positive number", ErrInvalidRates, line)
+		}
+		if hasValid && field(validCol) != "" {
+			rate.ValidFrom, err = time.Parse(time.RFC3339, field(validCol))
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: valid_from must be an RFC 3339 timestamp", ErrInvalidRates, line)
+			}
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
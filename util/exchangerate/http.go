@@ -0,0 +1,110 @@
+package exchangerate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// httpTimeout bounds a fetch when no client is given
+	httpTimeout = 10 * time.Second
+	// maxResponseSize bounds the rate document read from the source
+	maxResponseSize = 1 << 20
+	// inverseScale is the precision a quoted rate is inverted with, above the stored six digits
+	inverseScale = 10
+)
+
+// HTTPProvider fetches rates from a JSON endpoint answering
+//
+//	{"base": "USD", "timestamp": 1760659200, "rates": {"EUR": 0.9245, "JPY": 151.2}}
+//
+// where each rate is how many units of the currency one unit of the base currency buys, and the
+// timestamp is in Unix seconds. The base currency must be the base currency of the ledger; the
+// quotes are inverted into values in the base currency and the base itself is reported at 1.
+type HTTPProvider struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// NewHTTPProvider creates a provider fetching url, sending apiKey as a bearer token when set.
+// A nil client uses one with a short timeout.
+func NewHTTPProvider(url, apiKey string, client *http.Client) *HTTPProvider {
+	if client == nil {
+		client = &http.Client{Timeout: httpTimeout}
+	}
+	return &HTTPProvider{url: url, apiKey: apiKey, client: client}
+}
+
+func (p *HTTPProvider) Name() string {
+	return "http"
+}
+
+// quoteResponse is the rate document served by the source
+type quoteResponse struct {
+	Base      string                     `json:"base"`
+	Timestamp int64                      `json:"timestamp"`
+	Rates     map[string]decimal.Decimal `json:"rates"`
+}
+
+func (p *HTTPProvider) FetchRates(ctx context.Context) ([]Rate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rates request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch rates: %s answered %s", req.URL.Host, resp.Status)
+	}
+
+	var quotes quoteResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&quotes); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRates, err)
+	}
+	return quotes.toRates(time.Now())
+}
+
+// toRates inverts the quotes into values in the base currency
+func (q quoteResponse) toRates(now time.Time) ([]Rate, error) {
+	base := strings.ToUpper(q.Base)
+	if len(base) != 3 {
+		return nil, fmt.Errorf("%w: base must be a 3 letter currency code", ErrInvalidRates)
+	}
+	validFrom := now
+	if q.Timestamp > 0 {
+		validFrom = time.Unix(q.Timestamp, 0).UTC()
+	}
+
+	rates := []Rate{{CurrencyCode: base, Rate: decimal.NewFromInt(1), ValidFrom: validFrom}}
+	for code, quote := range q.Rates {
+		code = strings.ToUpper(code)
+		if code == base {
+			continue
+		}
+		if len(code) != 3 || !quote.IsPositive() {
+			return nil, fmt.Errorf("%w: %s has no positive rate", ErrInvalidRates, code)
+		}
+		rates = append(rates, Rate{
+			CurrencyCode: code,
+			Rate:         decimal.NewFromInt(1).DivRound(quote, inverseScale),
+			ValidFrom:    validFrom,
+		})
+	}
+	return rates, nil
+}
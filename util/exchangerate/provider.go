@@ -0,0 +1,52 @@
+// Package exchangerate fetches exchange rates from an external source. Rates are expressed like
+// account_currencies.exchange_rate, as the value of one unit of a currency in the base currency.
+package exchangerate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Environment variables the provider is configured with. The HTTP provider is used when a URL is
+// set, otherwise the file provider when a file is set.
+const (
+	URLEnv    = "EXCHANGE_RATES_URL"
+	APIKeyEnv = "EXCHANGE_RATES_API_KEY"
+	FileEnv   = "EXCHANGE_RATES_FILE"
+	// RefreshIntervalEnv overrides how often the rates are fetched, as a Go duration
+	RefreshIntervalEnv = "EXCHANGE_RATES_REFRESH_INTERVAL"
+)
+
+// ErrInvalidRates is returned when a source delivers rates that cannot be used
+var ErrInvalidRates = errors.New("invalid exchange rates")
+
+// Rate is the value of one unit of a currency in the base currency from ValidFrom on
+type Rate struct {
+	CurrencyCode string
+	Rate         decimal.Decimal
+	ValidFrom    time.Time
+}
+
+// ExchangeRateProvider is a source of exchange rates
+type ExchangeRateProvider interface {
+	// Name identifies the provider in the rate history
+	Name() string
+
+	// FetchRates returns the rates the source currently publishes
+	FetchRates(ctx context.Context) ([]Rate, error)
+}
+
+// ProviderFromEnv builds the provider configured in the environment, it returns nil when none is
+func ProviderFromEnv() ExchangeRateProvider {
+	if url := os.Getenv(URLEnv); url != "" {
+		return NewHTTPProvider(url, os.Getenv(APIKeyEnv), nil)
+	}
+	if path := os.Getenv(FileEnv); path != "" {
+		return NewFileProvider(path)
+	}
+	return nil
+}
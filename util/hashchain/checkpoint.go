@@ -0,0 +1,66 @@
+package hashchain
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CheckpointKeyEnv holds the base64 encoded 32 byte Ed25519 seed checkpoints are signed with
+const CheckpointKeyEnv = "AUDIT_CHECKPOINT_KEY"
+
+// ErrInvalidKey is returned for keys that are not base64 encoded Ed25519 keys
+var ErrInvalidKey = errors.New("invalid checkpoint key")
+
+// CheckpointMessage is what a checkpoint signature covers
+func CheckpointMessage(chain string, seq int64, rowHash string) []byte {
+	return []byte(fmt.Sprintf("%s:%d:%s", chain, seq, rowHash))
+}
+
+// Signer signs chain checkpoints
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// SignerFromEnv reads the checkpoint key from the environment, it returns nil when none is set
+func SignerFromEnv() (*Signer, error) {
+	encoded := os.Getenv(CheckpointKeyEnv)
+	if encoded == "" {
+		return nil, nil
+	}
+	seed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("%w: %s must be a base64 encoded %d byte seed", ErrInvalidKey, CheckpointKeyEnv, ed25519.SeedSize)
+	}
+	return &Signer{key: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// Sign returns the base64 encoded signature of a checkpoint
+func (s *Signer) Sign(chain string, seq int64, rowHash string) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, CheckpointMessage(chain, seq, rowHash)))
+}
+
+// PublicKey returns the key checkpoints signed by s verify against
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// ParsePublicKey decodes a base64 encoded Ed25519 public key
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: public key must be %d base64 encoded bytes", ErrInvalidKey, ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// VerifyCheckpoint reports whether signature is a valid signature of the checkpoint under key
+func VerifyCheckpoint(key ed25519.PublicKey, chain string, seq int64, rowHash, signature string) bool {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, CheckpointMessage(chain, seq, rowHash), sig)
+}
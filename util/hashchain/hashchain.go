@@ -0,0 +1,127 @@
+package hashchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+// Chains, named after the table they protect as in hash_chain_heads
+const (
+	ChainAuditTrail = "audit_trail"
+	ChainEntries    = "entries"
+)
+
+// Chains lists every hash chain
+var Chains = []string{ChainAuditTrail, ChainEntries}
+
+// GenesisHash is the prev_hash of the first link of a chain
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Column scales of the hashed DECIMAL columns, their text form always carries this many places
+const (
+	amountScale       = 3
+	exchangeRateScale = 10
+)
+
+// payload mirrors the chain_field SQL function: every value is written as its length in bytes,
+// a colon and the value, NULL as a single dash
+type payload struct {
+	b strings.Builder
+}
+
+func (p *payload) text(value string) {
+	p.b.WriteString(strconv.Itoa(len(value)))
+	p.b.WriteByte(':')
+	p.b.WriteString(value)
+}
+
+func (p *payload) null() {
+	p.b.WriteByte('-')
+}
+
+func (p *payload) int(value int64) {
+	p.text(strconv.FormatInt(value, 10))
+}
+
+func (p *payload) nullInt32(value int32, valid bool) {
+	if !valid {
+		p.null()
+		return
+	}
+	p.int(int64(value))
+}
+
+func (p *payload) nullText(value string, valid bool) {
+	if !valid {
+		p.null()
+		return
+	}
+	p.text(value)
+}
+
+// timestamp mirrors the chain_timestamp SQL function
+func (p *payload) timestamp(value time.Time) {
+	p.text(value.UTC().Format("2006-01-02T15:04:05.000000Z"))
+}
+
+func (p *payload) numeric(value pgtype.Numeric, scale int32) {
+	if value.Status != pgtype.Present {
+		p.null()
+		return
+	}
+	p.text(decimal.NewFromBigInt(value.Int, value.Exp).StringFixed(scale))
+}
+
+// jsonb hashes the document as PostgreSQL prints it, which is how pgx hands it over
+func (p *payload) jsonb(value pgtype.JSONB) {
+	if value.Status != pgtype.Present {
+		p.null()
+		return
+	}
+	p.text(string(value.Bytes))
+}
+
+// AuditTrailPayload encodes an audit record the way audit_trail_chain_payload does
+func AuditTrailPayload(record db.AuditTrail) string {
+	var p payload
+	p.int(int64(record.AuditID))
+	p.text(record.TableName)
+	p.text(record.RecordID)
+	p.text(record.Action)
+	p.jsonb(record.OldValues)
+	p.jsonb(record.NewValues)
+	p.nullInt32(record.UserID.Int32, record.UserID.Valid)
+	p.nullText(record.IpAddress.String, record.IpAddress.Valid)
+	p.timestamp(record.CreatedAt)
+	return p.b.String()
+}
+
+// EntryPayload encodes an entry the way entries_chain_payload does
+func EntryPayload(entry db.Entry) string {
+	var p payload
+	p.int(entry.ID)
+	p.nullInt32(entry.AccountID.Int32, entry.AccountID.Valid)
+	p.numeric(entry.Amount, amountScale)
+	p.timestamp(entry.CreatedAt)
+	p.nullInt32(entry.TransactionID.Int32, entry.TransactionID.Valid)
+	p.nullText(entry.CurrencyCode.String, entry.CurrencyCode.Valid)
+	p.numeric(entry.ExchangeRate, exchangeRateScale)
+	return p.b.String()
+}
+
+// Link computes the row_hash of the link at seq, the way append_hash_chain does
+func Link(prevHash string, seq int64, rowPayload string) string {
+	var p payload
+	p.b.WriteString(prevHash)
+	p.int(seq)
+	p.b.WriteString(rowPayload)
+	sum := sha256.Sum256([]byte(p.b.String()))
+	return hex.EncodeToString(sum[:])
+}
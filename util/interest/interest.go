@@ -0,0 +1,133 @@
+package interest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Day count conventions, mirroring the day_count_convention database enum
+const (
+	// DayCountACT365 accrues 1/365 of the annual rate every calendar day, leap years included
+	DayCountACT365 = "ACT_365"
+	// DayCount30360 is 30E/360: every month counts 30 days, the 31st accrues nothing and the last
+	// day of February accrues the days up to the 30th
+	DayCount30360 = "30_360"
+)
+
+// Posting periods, mirroring the interest_posting_period database enum
+const (
+	PeriodMonthly   = "MONTHLY"
+	PeriodQuarterly = "QUARTERLY"
+	PeriodAnnually  = "ANNUALLY"
+)
+
+// Periods lists every posting period
+var Periods = []string{PeriodMonthly, PeriodQuarterly, PeriodAnnually}
+
+// AccrualScale is the number of decimal places kept for a daily accrual
+const AccrualScale = 10
+
+var (
+	hundred = decimal.NewFromInt(100)
+	days365 = decimal.NewFromInt(365)
+	days360 = decimal.NewFromInt(360)
+)
+
+// Date truncates t to midnight UTC, the start of the accrual day it falls on
+func Date(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DayCountFraction returns the fraction of a year that the single day starting at day accrues
+func DayCountFraction(convention string, day time.Time) (decimal.Decimal, error) {
+	switch convention {
+	case DayCountACT365:
+		return decimal.NewFromInt(1).Div(days365), nil
+	case DayCount30360:
+		day = Date(day)
+		return decimal.NewFromInt(int64(days30E360(day, day.AddDate(0, 0, 1)))).Div(days360), nil
+	default:
+		return decimal.Zero, fmt.Errorf("unknown day count convention %q", convention)
+	}
+}
+
+// DailyAccrual is the interest one day of balance earns at an annual rate given in percent, rounded
+// half to even to AccrualScale. Only positive balances earn interest.
+func DailyAccrual(balance, annualRate decimal.Decimal, convention string, day time.Time) (decimal.Decimal, error) {
+	fraction, err := DayCountFraction(convention, day)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !balance.IsPositive() || !annualRate.IsPositive() {
+		return decimal.Zero, nil
+	}
+	return balance.Mul(annualRate).Div(hundred).Mul(fraction).RoundBank(AccrualScale), nil
+}
+
+// LastPeriodEnd returns the last day of the most recent posting period that ended on or before day
+func LastPeriodEnd(period string, day time.Time) (time.Time, error) {
+	months, err := periodMonths(period)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Step back from the first of the month after day to the first day of a period
+	next := Date(day).AddDate(0, 0, 1)
+	next = time.Date(next.Year(), next.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for (int(next.Month())-1)%months != 0 {
+		next = next.AddDate(0, -1, 0)
+	}
+	return next.AddDate(0, 0, -1), nil
+}
+
+// AddMonths moves day forward by months calendar months, ending on the last day of the target
+// month when it is shorter, so a term opened on January 31 matures on the last day of February
+func AddMonths(day time.Time, months int) time.Time {
+	day = Date(day)
+	firstOfTarget := time.Date(day.Year(), day.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if day.Day() < lastDay {
+		lastDay = day.Day()
+	}
+	return time.Date(firstOfTarget.Year(), firstOfTarget.Month(), lastDay, 0, 0, 0, 0, time.UTC)
+}
+
+// SimpleInterest is the ACT/365 interest a principal earns at an annual rate given in percent from
+// one day up to, not including, another, rounded half to even to cents
+func SimpleInterest(principal, annualRate decimal.Decimal, from, to time.Time) decimal.Decimal {
+	days := int64(Date(to).Sub(Date(from)).Hours() / 24)
+	if days <= 0 || !principal.IsPositive() || !annualRate.IsPositive() {
+		return decimal.Zero
+	}
+	return principal.Mul(annualRate).Div(hundred).Mul(decimal.NewFromInt(days)).Div(days365).RoundBank(2)
+}
+
+func periodMonths(period string) (int, error) {
+	switch period {
+	case PeriodMonthly:
+		return 1, nil
+	case PeriodQuarterly:
+		return 3, nil
+	case PeriodAnnually:
+		return 12, nil
+	default:
+		return 0, fmt.Errorf("unknown posting period %q", period)
+	}
+}
+
+// days30E360 counts the days between two dates under 30E/360, treating the last day of February
+// as the 30th
+func days30E360(from, to time.Time) int {
+	d1, d2 := day30(from), day30(to)
+	return 360*(to.Year()-from.Year()) + 30*(int(to.Month())-int(from.Month())) + d2 - d1
+}
+
+func day30(t time.Time) int {
+	if t.Day() == 31 || (t.Month() == time.February && t.AddDate(0, 0, 1).Month() == time.March) {
+		return 30
+	}
+	return t.Day()
+}
@@ -0,0 +1,72 @@
+package interest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/interest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// sumDayCount adds up the daily fractions of the days from start up to, not including, end
+func sumDayCount(t *testing.T, convention string, start, end time.Time) decimal.Decimal {
+	total := decimal.Zero
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		fraction, err := interest.DayCountFraction(convention, day)
+		require.NoError(t, err)
+		total = total.Add(fraction)
+	}
+	return total
+}
+
+func TestDayCountFraction(t *testing.T) {
+	month := decimal.NewFromInt(30).Div(decimal.NewFromInt(360))
+	for _, start := range []time.Time{
+		time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		total := sumDayCount(t, interest.DayCount30360, start, start.AddDate(0, 1, 0))
+		require.True(t, month.Sub(total).Abs().LessThan(decimal.New(1, -12)), "30/360 month starting %s", start)
+	}
+
+	leapYear := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	total := sumDayCount(t, interest.DayCountACT365, leapYear, leapYear.AddDate(1, 0, 0))
+	require.True(t, decimal.NewFromInt(366).Div(decimal.NewFromInt(365)).Sub(total).Abs().LessThan(decimal.New(1, -12)))
+
+	_, err := interest.DayCountFraction("ACT_ACT", leapYear)
+	require.Error(t, err)
+}
+
+func TestLastPeriodEnd(t *testing.T) {
+	day := time.Date(2025, time.May, 10, 15, 0, 0, 0, time.UTC)
+	testCases := map[string]time.Time{
+		interest.PeriodMonthly:   time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC),
+		interest.PeriodQuarterly: time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+		interest.PeriodAnnually:  time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for period, expected := range testCases {
+		end, err := interest.LastPeriodEnd(period, day)
+		require.NoError(t, err)
+		require.Equal(t, expected, end, period)
+	}
+
+	quarterEnd := time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)
+	end, err := interest.LastPeriodEnd(interest.PeriodQuarterly, quarterEnd)
+	require.NoError(t, err)
+	require.Equal(t, quarterEnd, end)
+}
+
+func TestFixedDepositInterest(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), interest.AddMonths(start, 1))
+	require.Equal(t, time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC), interest.AddMonths(start, 12))
+
+	// 1000.00 at 5% for the 365 days of 2023
+	earned := interest.SimpleInterest(decimal.RequireFromString("1000.00"), decimal.RequireFromString("5"),
+		time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	require.True(t, decimal.RequireFromString("50.00").Equal(earned), "got %s", earned)
+}
@@ -0,0 +1,276 @@
+// Package money holds amounts of money as exact decimals paired with their currency
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgtype"
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/shopspring/decimal"
+)
+
+// MaxScale and MaxPrecision match the DECIMAL(16, 3) amount and balance columns, amounts never keep
+// more decimals or more digits
+const (
+	MaxScale     = 3
+	MaxPrecision = 16
+)
+
+// defaultMinorUnits is used for currencies that are not in ISO 4217
+const defaultMinorUnits = 2
+
+var (
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+	ErrInvalidAmount    = errors.New("amount must be a decimal number of at most 16 digits")
+	ErrInvalidRatios    = errors.New("ratios must not be negative and must not all be zero")
+)
+
+// Money is an amount in a currency. The zero value is zero in no currency.
+type Money struct {
+	Amount   decimal.Decimal
+	Currency string
+}
+
+// New returns amount in currency
+func New(amount decimal.Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Zero returns nothing in currency
+func Zero(currency string) Money {
+	return Money{Amount: decimal.Zero, Currency: currency}
+}
+
+// Parse reads an amount written as a decimal number, such as "10.50"
+func Parse(amount, currency string) (Money, error) {
+	value, err := parseDecimal(amount, MaxPrecision)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	return New(value, currency), nil
+}
+
+// parseDecimal reads a plain decimal number of at most precision digits. Exponents are refused and
+// the digits counted before the decimal is built: a text as short as "1e100000000" stands for a
+// number that takes minutes to compare or print.
+func parseDecimal(text string, precision int) (decimal.Decimal, error) {
+	unsigned := strings.TrimPrefix(strings.TrimPrefix(text, "-"), "+")
+	whole, fraction, _ := strings.Cut(unsigned, ".")
+	if whole+fraction == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return decimal.Decimal{}, ErrInvalidAmount
+	}
+	if len(strings.TrimLeft(whole, "0"))+len(fraction) > precision {
+		return decimal.Decimal{}, ErrInvalidAmount
+	}
+	return decimal.NewFromString(text)
+}
+
+// FromNumeric reads a database amount, NULL reads as zero
+func FromNumeric(num pgtype.Numeric, currency string) Money {
+	if num.Status != pgtype.Present || num.Int == nil {
+		return Zero(currency)
+	}
+	return New(decimal.NewFromBigInt(num.Int, num.Exp), currency)
+}
+
+// registeredMinorUnits holds the minor units of the currency registry by currency code
+var registeredMinorUnits sync.Map
+
+// RegisterMinorUnits records the minor units the currency registry has for currency. The registry
+// is the one source of scales: Scale prefers them to ISO 4217 so amounts are checked, rounded and
+// written with the decimals the ledger keeps them to.
+func RegisterMinorUnits(currency string, minorUnits int16) {
+	registeredMinorUnits.Store(currency, minorUnits)
+}
+
+// Scale is the number of decimals amounts in a currency are written with: the minor units of the
+// currency registry, its ISO 4217 minor units until the registry is read, 2 for other currencies,
+// and never more than MaxScale
+func Scale(currency string) int32 {
+	minorUnits := int32(defaultMinorUnits)
+	if registered, ok := registeredMinorUnits.Load(currency); ok {
+		minorUnits = int32(registered.(int16))
+	} else if iso, ok := iso4217.Lookup(currency); ok {
+		minorUnits = int32(iso.MinorUnits)
+	}
+	return min(minorUnits, MaxScale)
+}
+
+// Numeric converts m for the database, keeping every decimal it has
+func (m Money) Numeric() (pgtype.Numeric, error) {
+	var num pgtype.Numeric
+	if err := num.Set(m.Amount.String()); err != nil {
+		return num, fmt.Errorf("error converting %s to numeric: %w", m, err)
+	}
+	return num, nil
+}
+
+// Scale is the number of decimals of the currency of m
+func (m Money) Scale() int32 {
+	return Scale(m.Currency)
+}
+
+// Round rounds m half to even to the decimals of its currency
+func (m Money) Round() Money {
+	return m.RoundTo(m.Scale())
+}
+
+// RoundTo rounds m half to even to scale decimals, for currencies whose scale is known otherwise
+func (m Money) RoundTo(scale int32) Money {
+	return New(m.Amount.RoundBank(scale), m.Currency)
+}
+
+// FitsScale reports whether m has no more decimals than its currency
+func (m Money) FitsScale() bool {
+	return m.Amount.Equal(m.Amount.Truncate(m.Scale()))
+}
+
+// WithCurrency returns the amount of m in another currency, without converting it
+func (m Money) WithCurrency(currency string) Money {
+	return New(m.Amount, currency)
+}
+
+// Add returns m + other, both must be in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return New(m.Amount.Add(other.Amount), m.Currency), nil
+}
+
+// Sub returns m - other, both must be in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return New(m.Amount.Sub(other.Amount), m.Currency), nil
+}
+
+// Mul returns m times factor, unrounded
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return New(m.Amount.Mul(factor), m.Currency)
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return New(m.Amount.Neg(), m.Currency)
+}
+
+// Cmp compares m with other: -1 when m is less, 0 when equal and 1 when greater
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	return m.Amount.Cmp(other.Amount), nil
+}
+
+func (m Money) IsZero() bool     { return m.Amount.IsZero() }
+func (m Money) IsPositive() bool { return m.Amount.IsPositive() }
+func (m Money) IsNegative() bool { return m.Amount.IsNegative() }
+
+// Allocate splits m, rounded to its currency, into parts proportional to ratios. The parts always
+// add up to the rounded amount: what is left after rounding every part down is handed out one
+// minor unit at a time to the first parts with a non zero ratio.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	var total int64
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, ErrInvalidRatios
+		}
+		total += ratio
+	}
+	if total == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	scale := m.Scale()
+	amount := m.Amount.RoundBank(scale)
+	sum := decimal.NewFromInt(total)
+
+	parts := make([]Money, len(ratios))
+	remainder := amount
+	for i, ratio := range ratios {
+		share := amount.Mul(decimal.NewFromInt(ratio)).Div(sum).Truncate(scale)
+		parts[i] = New(share, m.Currency)
+		remainder = remainder.Sub(share)
+	}
+
+	unit := decimal.New(1, -scale)
+	if remainder.IsNegative() {
+		unit = unit.Neg()
+	}
+	for i := 0; !remainder.IsZero(); i = (i + 1) % len(parts) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].Amount = parts[i].Amount.Add(unit)
+		remainder = remainder.Sub(unit)
+	}
+	return parts, nil
+}
+
+// Split divides m into n parts that differ by at most one minor unit
+func (m Money) Split(n int) ([]Money, error) {
+	if n < 1 {
+		return nil, ErrInvalidRatios
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// String formats m as its amount followed by its currency, such as "10.50 USD"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Text()
+	}
+	return m.Text() + " " + m.Currency
+}
+
+// Text formats the amount without the currency, with the decimals of its currency or with all of
+// its own if it has more
+func (m Money) Text() string {
+	if m.FitsScale() {
+		return m.Amount.StringFixed(m.Scale())
+	}
+	return m.Amount.String()
+}
+
+// MarshalJSON writes the amount as a JSON string, the currency is given by the field next to it
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.Text() + `"`), nil
+}
+
+// UnmarshalJSON reads an amount written as a JSON string. Plain JSON numbers are accepted too and
+// read from their text, so they are not rounded through a float on the way.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
+		}
+	}
+	value, err := parseDecimal(text, MaxPrecision)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
+	}
+	m.Amount = value
+	return nil
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/iso4217"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyAllocate(t *testing.T) {
+	amount := money.New(decimal.RequireFromString("100.00"), iso4217.USD)
+
+	parts, err := amount.Split(3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"33.34", "33.33", "33.33"},
+		[]string{parts[0].Text(), parts[1].Text(), parts[2].Text()})
+
+	//? Yen have no minor unit, the remainder is handed out in whole yen
+	yen := money.New(decimal.NewFromInt(100), iso4217.JPY)
+	parts, err = yen.Allocate(1, 0, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"34", "0", "66"}, []string{parts[0].Text(), parts[1].Text(), parts[2].Text()})
+
+	_, err = amount.Allocate(0, 0)
+	require.ErrorIs(t, err, money.ErrInvalidRatios)
+
+	var parsed money.Money
+	require.NoError(t, json.Unmarshal([]byte(`10.1`), &parsed))
+	require.True(t, parsed.Amount.Equal(decimal.RequireFromString("10.1")))
+	require.Error(t, json.Unmarshal([]byte(`"ten"`), &parsed))
+}
+
+func TestMoneyParseRefusesExponents(t *testing.T) {
+	for _, text := range []string{"10", "10.50", "-0.125", "+7", ".5", "1234567890123.456", "0000000000000000001.5"} {
+		_, err := money.Parse(text, iso4217.USD)
+		require.NoError(t, err, text)
+	}
+
+	//? Exponents stand for numbers far beyond any amount, they are refused before a decimal is built
+	start := time.Now()
+	for _, text := range []string{"1e100000000", "1E3", "1e-100000000", "-1.5e2"} {
+		_, err := money.Parse(text, iso4217.USD)
+		require.ErrorIs(t, err, money.ErrInvalidAmount, text)
+
+		var parsed money.Money
+		require.ErrorIs(t, json.Unmarshal([]byte(`"`+text+`"`), &parsed), money.ErrInvalidAmount, text)
+		require.ErrorIs(t, json.Unmarshal([]byte(text), &parsed), money.ErrInvalidAmount, text)
+	}
+	require.Less(t, time.Since(start), time.Second)
+
+	for _, text := range []string{"", "-", ".", "1.2.3", "12,50", "0x10", "Infinity", "NaN", " 1", "12345678901234.567"} {
+		_, err := money.Parse(text, iso4217.USD)
+		require.ErrorIs(t, err, money.ErrInvalidAmount, text)
+	}
+}
+
+func TestRateKeepsEveryDigit(t *testing.T) {
+	var rate money.Rate
+	require.NoError(t, json.Unmarshal([]byte(`"1.0000000001"`), &rate))
+	require.Equal(t, "1.0000000001", rate.String())
+	require.False(t, rate.FitsScale(4))
+	require.True(t, rate.FitsScale(10))
+
+	data, err := json.Marshal(rate)
+	require.NoError(t, err)
+	require.JSONEq(t, `"1.0000000001"`, string(data))
+
+	num, err := rate.Numeric()
+	require.NoError(t, err)
+	require.True(t, money.RateFromNumeric(num).Value.Equal(rate.Value))
+
+	require.NoError(t, json.Unmarshal([]byte(`0.25`), &rate))
+	require.Equal(t, "0.25", rate.String())
+
+	for _, text := range []string{`"1e100000000"`, `1E3`, `"abc"`, `"123456789012345678901"`} {
+		require.ErrorIs(t, json.Unmarshal([]byte(text), &rate), money.ErrInvalidRate, text)
+	}
+}
+
+func TestScaleFollowsTheRegistry(t *testing.T) {
+	require.EqualValues(t, 2, money.Scale("XTS"))
+
+	//? An admin registered currency keeps the minor units it was registered with
+	money.RegisterMinorUnits("XTS", 0)
+	require.EqualValues(t, 0, money.Scale("XTS"))
+	require.False(t, money.New(decimal.RequireFromString("1.5"), "XTS").FitsScale())
+
+	money.RegisterMinorUnits("XTS", 4)
+	require.EqualValues(t, money.MaxScale, money.Scale("XTS"))
+}
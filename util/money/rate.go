@@ -0,0 +1,87 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// MaxRatePrecision matches the NUMERIC(20, 10) exchange rate columns, the widest rates are kept in
+const MaxRatePrecision = 20
+
+var ErrInvalidRate = errors.New("rate must be a decimal number of at most 20 digits")
+
+// Rate is an exact decimal that is not an amount: an exchange rate, an interest rate or a
+// percentage. Like Money it is written as a JSON string so no digit is lost to a float.
+type Rate struct {
+	Value decimal.Decimal
+}
+
+// NewRate returns value as a rate
+func NewRate(value decimal.Decimal) Rate {
+	return Rate{Value: value}
+}
+
+// ParseRate reads a rate written as a decimal number, such as "1.0850"
+func ParseRate(rate string) (Rate, error) {
+	value, err := parseDecimal(rate, MaxRatePrecision)
+	if err != nil {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, rate)
+	}
+	return NewRate(value), nil
+}
+
+// RateFromNumeric reads a database rate, NULL reads as zero
+func RateFromNumeric(num pgtype.Numeric) Rate {
+	if num.Status != pgtype.Present || num.Int == nil {
+		return Rate{Value: decimal.Zero}
+	}
+	return NewRate(decimal.NewFromBigInt(num.Int, num.Exp))
+}
+
+// Numeric converts r for the database, keeping every decimal it has
+func (r Rate) Numeric() (pgtype.Numeric, error) {
+	var num pgtype.Numeric
+	if err := num.Set(r.Value.String()); err != nil {
+		return num, fmt.Errorf("error converting rate %s to numeric: %w", r, err)
+	}
+	return num, nil
+}
+
+// FitsScale reports whether r has no more than scale decimals, the scale of the column it goes to
+func (r Rate) FitsScale(scale int32) bool {
+	return r.Value.Equal(r.Value.Truncate(scale))
+}
+
+// String formats r without trailing zeros, such as "1.085"
+func (r Rate) String() string {
+	return r.Value.String()
+}
+
+// MarshalJSON writes the rate as a JSON string
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + r.String() + `"`), nil
+}
+
+// UnmarshalJSON reads a rate written as a JSON string or as a plain JSON number
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRate, data)
+		}
+	}
+	value, err := parseDecimal(text, MaxRatePrecision)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRate, data)
+	}
+	r.Value = value
+	return nil
+}
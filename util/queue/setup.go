@@ -0,0 +1,42 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/riad/banksystemendtoend/api/utils"
+	logger "github.com/riad/banksystemendtoend/pkg/log"
+	"github.com/riad/banksystemendtoend/pkg/rabbitmq"
+	"go.uber.org/zap"
+)
+
+// InitializeRabbitMQ connects the RabbitMQ client singleton. It returns false without an error when
+// RABBITMQ_HOST is not set, features relying on the queue are then disabled.
+func InitializeRabbitMQ() (bool, error) {
+	cfg := rabbitmq.Config{
+		Host:              os.Getenv("RABBITMQ_HOST"),
+		Port:              os.Getenv("RABBITMQ_PORT"),
+		Username:          os.Getenv("RABBITMQ_USER"),
+		Password:          os.Getenv("RABBITMQ_PASSWORD"),
+		VHost:             os.Getenv("RABBITMQ_VHOST"),
+		ConnectionTimeout: utils.GetEnvAsDuration("RABBITMQ_CONNECTION_TIMEOUT", 10*time.Second),
+		HeartbeatInterval: utils.GetEnvAsDuration("RABBITMQ_HEARTBEAT_INTERVAL", 10*time.Second),
+	}
+	if cfg.Host == "" {
+		logger.GetLogger().Warn("RABBITMQ_HOST is not set, queue consumers are disabled")
+		return false, nil
+	}
+	if cfg.Port == "" {
+		cfg.Port = "5672"
+	}
+
+	if err := rabbitmq.InitClient(cfg); err != nil {
+		return false, fmt.Errorf("failed to setup RabbitMQ client: %w", err)
+	}
+
+	logger.GetLogger().Info("RabbitMQ connection established",
+		zap.String("host", cfg.Host),
+		zap.String("port", cfg.Port))
+	return true, nil
+}
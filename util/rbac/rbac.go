@@ -0,0 +1,121 @@
+// Package rbac holds the permission matrix: which operations each user role may perform, and
+// whether it may perform them on the resources of other users or only on its own.
+package rbac
+
+import (
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+)
+
+// Permission names an operation routes are guarded by
+type Permission string
+
+const (
+	UsersRead   Permission = "users:read"
+	UsersWrite  Permission = "users:write"
+	UsersManage Permission = "users:manage"
+
+	AccountsRead   Permission = "accounts:read"
+	AccountsWrite  Permission = "accounts:write"
+	AccountsManage Permission = "accounts:manage"
+
+	TransfersRead       Permission = "transfers:read"
+	TransfersCreate     Permission = "transfers:create"
+	TransactionsReverse Permission = "transactions:reverse"
+
+	StatementsRead   Permission = "statements:read"
+	StatementsCreate Permission = "statements:create"
+
+	// LedgerRead covers reconciliation results, interest and fee configuration and ledger metrics
+	LedgerRead Permission = "ledger:read"
+	// LedgerOperate covers changing interest and fee configuration and starting the ledger runs
+	LedgerOperate Permission = "ledger:operate"
+
+	ReferenceDataManage Permission = "reference_data:manage"
+	AuditRead           Permission = "audit:read"
+	APIKeysManage       Permission = "api_keys:manage"
+
+	// TwoFactorManage covers enrolling in and disabling the caller's own two-factor authentication
+	TwoFactorManage Permission = "two_factor:manage"
+)
+
+// Scope says whose resources a permission may be used on
+type Scope int
+
+const (
+	// ScopeOwn limits a permission to the user's own users, accounts and what belongs to them
+	ScopeOwn Scope = iota + 1
+	// ScopeAny lets a permission be used on the resources of every user
+	ScopeAny
+)
+
+// matrix lists the permissions of every role. Admins may manage anyone's users and accounts but
+// only move money out of their own accounts, like every other role. Reversals and refunds move
+// money out of other users' accounts and are left to admins alone.
+var matrix = map[db.UserRole]map[Permission]Scope{
+	db.UserRoleCUSTOMER: {
+		UsersRead:        ScopeOwn,
+		UsersWrite:       ScopeOwn,
+		AccountsRead:     ScopeOwn,
+		AccountsWrite:    ScopeOwn,
+		TransfersRead:    ScopeOwn,
+		TransfersCreate:  ScopeOwn,
+		StatementsRead:   ScopeOwn,
+		StatementsCreate: ScopeOwn,
+		TwoFactorManage:  ScopeOwn,
+	},
+	db.UserRoleSUPPORT: {
+		UsersRead:        ScopeAny,
+		AccountsRead:     ScopeAny,
+		TransfersRead:    ScopeAny,
+		StatementsRead:   ScopeAny,
+		StatementsCreate: ScopeAny,
+		TwoFactorManage:  ScopeOwn,
+	},
+	db.UserRoleAUDITOR: {
+		UsersRead:       ScopeAny,
+		AccountsRead:    ScopeAny,
+		TransfersRead:   ScopeAny,
+		StatementsRead:  ScopeAny,
+		LedgerRead:      ScopeAny,
+		AuditRead:       ScopeAny,
+		TwoFactorManage: ScopeOwn,
+	},
+	db.UserRoleADMIN: {
+		UsersRead:           ScopeAny,
+		UsersWrite:          ScopeAny,
+		UsersManage:         ScopeAny,
+		AccountsRead:        ScopeAny,
+		AccountsWrite:       ScopeAny,
+		AccountsManage:      ScopeAny,
+		TransfersRead:       ScopeAny,
+		TransfersCreate:     ScopeOwn,
+		TransactionsReverse: ScopeAny,
+		StatementsRead:      ScopeAny,
+		StatementsCreate:    ScopeAny,
+		LedgerRead:          ScopeAny,
+		LedgerOperate:       ScopeAny,
+		ReferenceDataManage: ScopeAny,
+		AuditRead:           ScopeAny,
+		APIKeysManage:       ScopeAny,
+		TwoFactorManage:     ScopeOwn,
+	},
+}
+
+// Lookup returns the scope role holds permission in, and false when it does not hold it at all
+func Lookup(role db.UserRole, permission Permission) (Scope, bool) {
+	scope, ok := matrix[role][permission]
+	return scope, ok
+}
+
+// BackOffice reports whether permission is one customers do not hold. Routes guarded by such a
+// permission can only be called through API keys with the ADMIN scope.
+func BackOffice(permission Permission) bool {
+	_, ok := matrix[db.UserRoleCUSTOMER][permission]
+	return !ok
+}
+
+// IsValidRole reports whether role is one of the roles of the matrix
+func IsValidRole(role string) bool {
+	_, ok := matrix[db.UserRole(role)]
+	return ok
+}
@@ -0,0 +1,66 @@
+package rbac_test
+
+import (
+	"testing"
+
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/rbac"
+	"github.com/stretchr/testify/require"
+)
+
+var allRoles = []db.UserRole{db.UserRoleCUSTOMER, db.UserRoleSUPPORT, db.UserRoleAUDITOR, db.UserRoleADMIN}
+
+// holders returns the roles holding permission
+func holders(permission rbac.Permission) []db.UserRole {
+	var roles []db.UserRole
+	for _, role := range allRoles {
+		if _, ok := rbac.Lookup(role, permission); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func TestRBACMatrixMovingOthersMoney(t *testing.T) {
+	//? Reversals and refunds move money out of other users' accounts, only admins may start them
+	require.Equal(t, []db.UserRole{db.UserRoleADMIN}, holders(rbac.TransactionsReverse))
+	require.True(t, rbac.BackOffice(rbac.TransactionsReverse))
+
+	//? Every role sends money only from its own accounts
+	for _, role := range holders(rbac.TransfersCreate) {
+		scope, _ := rbac.Lookup(role, rbac.TransfersCreate)
+		require.Equal(t, rbac.ScopeOwn, scope, role)
+	}
+}
+
+func TestRBACMatrixAdminOnly(t *testing.T) {
+	for _, permission := range []rbac.Permission{
+		rbac.UsersManage,
+		rbac.AccountsManage,
+		rbac.LedgerOperate,
+		rbac.ReferenceDataManage,
+		rbac.APIKeysManage,
+	} {
+		require.Equal(t, []db.UserRole{db.UserRoleADMIN}, holders(permission), permission)
+	}
+}
+
+func TestRBACMatrixCustomer(t *testing.T) {
+	for _, permission := range []rbac.Permission{
+		rbac.UsersRead,
+		rbac.AccountsWrite,
+		rbac.TransfersCreate,
+		rbac.StatementsCreate,
+	} {
+		scope, ok := rbac.Lookup(db.UserRoleCUSTOMER, permission)
+		require.True(t, ok, permission)
+		require.Equal(t, rbac.ScopeOwn, scope, permission)
+		require.False(t, rbac.BackOffice(permission), permission)
+	}
+
+	//? Auditors read everything and change nothing
+	_, ok := rbac.Lookup(db.UserRoleAUDITOR, rbac.AccountsWrite)
+	require.False(t, ok)
+	require.True(t, rbac.IsValidRole("AUDITOR"))
+	require.False(t, rbac.IsValidRole("ROOT"))
+}
@@ -0,0 +1,118 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Frequency values mirror the schedule_frequency database enum
+const (
+	FrequencyOnce    = "ONCE"
+	FrequencyDaily   = "DAILY"
+	FrequencyWeekly  = "WEEKLY"
+	FrequencyMonthly = "MONTHLY"
+	FrequencyCron    = "CRON"
+)
+
+// ErrInvalidFrequency is returned for frequencies the scheduler does not know
+var ErrInvalidFrequency = errors.New("frequency must be one of ONCE, DAILY, WEEKLY, MONTHLY or CRON")
+
+// Schedule describes when a recurring job runs. Start is the first occurrence for the fixed
+// frequencies and the anchor for their time of day; monthly schedules keep Start's day of month
+// and fall back to the last day of shorter months. Times are evaluated in UTC.
+type Schedule struct {
+	Frequency string
+	CronExpr  string
+	Start     time.Time
+}
+
+// Validate checks the frequency and, for cron schedules, the expression
+func (s Schedule) Validate() error {
+	switch s.Frequency {
+	case FrequencyOnce, FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return nil
+	case FrequencyCron:
+		_, err := ParseCron(s.CronExpr)
+		return err
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidFrequency, s.Frequency)
+}
+
+// First returns the first occurrence at or after Start
+func (s Schedule) First() (time.Time, bool, error) {
+	if s.Frequency == FrequencyCron {
+		// Next is strictly after its argument, step back so Start itself can match
+		return s.Next(s.Start.Add(-time.Minute))
+	}
+	if err := s.Validate(); err != nil {
+		return time.Time{}, false, err
+	}
+	return s.Start.UTC(), true, nil
+}
+
+// Next returns the first occurrence strictly after t, false when the schedule has no more occurrences
+func (s Schedule) Next(t time.Time) (time.Time, bool, error) {
+	start := s.Start.UTC()
+	t = t.UTC()
+
+	switch s.Frequency {
+	case FrequencyOnce:
+		if t.Before(start) {
+			return start, true, nil
+		}
+		return time.Time{}, false, nil
+	case FrequencyDaily:
+		return s.stepAfter(t, func(n int) time.Time { return start.AddDate(0, 0, n) }), true, nil
+	case FrequencyWeekly:
+		return s.stepAfter(t, func(n int) time.Time { return start.AddDate(0, 0, 7*n) }), true, nil
+	case FrequencyMonthly:
+		return s.stepAfter(t, func(n int) time.Time { return addMonths(start, n) }), true, nil
+	case FrequencyCron:
+		cron, err := ParseCron(s.CronExpr)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		next, ok := cron.Next(t)
+		return next, ok, nil
+	}
+	return time.Time{}, false, fmt.Errorf("%w: %q", ErrInvalidFrequency, s.Frequency)
+}
+
+// stepAfter returns the first occurrence(n) strictly after t. Occurrences are always derived from
+// Start rather than from the previous one, so a short month does not shift later ones.
+func (s Schedule) stepAfter(t time.Time, occurrence func(n int) time.Time) time.Time {
+	start := s.Start.UTC()
+	if t.Before(start) {
+		return start
+	}
+
+	// Estimate the number of periods elapsed and walk forward from just before it
+	var n int
+	switch s.Frequency {
+	case FrequencyDaily:
+		n = int(t.Sub(start) / (24 * time.Hour))
+	case FrequencyWeekly:
+		n = int(t.Sub(start) / (7 * 24 * time.Hour))
+	case FrequencyMonthly:
+		n = (t.Year()-start.Year())*12 + int(t.Month()) - int(start.Month())
+	}
+	if n > 0 {
+		n--
+	}
+	for !occurrence(n).After(t) {
+		n++
+	}
+	return occurrence(n)
+}
+
+// addMonths moves t by n months, clamping the day to the end of the target month
+func addMonths(t time.Time, n int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
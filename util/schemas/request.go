@@ -1,7 +1,11 @@
 package schemas
 
 import (
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/jackc/pgtype"
+	db "github.com/riad/banksystemendtoend/db/sqlc"
 )
 
 type TransferTxParams struct {
@@ -13,5 +17,95 @@ type TransferTxParams struct {
 	StatusCode        string
 	Description       string
 	ExchangeRate      pgtype.Numeric
+	// RateAt, when ExchangeRate is not given, converts with the rates that were valid at that
+	// time instead of the current ones
+	RateAt          time.Time
+	ReferenceNumber string
+	// OriginalTransactionID links a refund or reversal to the transaction it compensates
+	OriginalTransactionID int32
+}
+
+// ReverseTxParams identifies the transaction to reverse in full
+type ReverseTxParams struct {
+	TransactionNumber uuid.UUID
+	Description       string
+	ReferenceNumber   string
+}
+
+// RefundTxParams identifies the transaction to refund and the amount, in the original currency
+type RefundTxParams struct {
+	TransactionNumber uuid.UUID
+	Amount            pgtype.Numeric
+	Description       string
 	ReferenceNumber   string
 }
+
+// AuthorizeHoldParams reserves funds on an account for a later capture by the merchant
+type AuthorizeHoldParams struct {
+	AccountID         int32
+	MerchantAccountID int32
+	Amount            pgtype.Numeric
+	CurrencyCode      string
+	Description       string
+	ExpiresAt         time.Time
+	ReferenceNumber   string
+}
+
+// CaptureHoldParams settles a hold; an Amount that is not present captures the full hold
+type CaptureHoldParams struct {
+	HoldNumber uuid.UUID
+	Amount     pgtype.Numeric
+}
+
+// TransferBatchItemParams is one transfer of a batch, in the sender's currency
+type TransferBatchItemParams struct {
+	FromAccountID int32
+	ToAccountID   int32
+	Amount        pgtype.Numeric
+	CurrencyCode  string
+	Description   string
+}
+
+// CreateTransferBatchParams stores a batch; a batch with an UploadID and no items waits for its file to be imported
+type CreateTransferBatchParams struct {
+	Mode            db.BatchMode
+	ReferenceNumber string
+	UploadID        string
+	Items           []TransferBatchItemParams
+	// UserID submitted the batch, every item must pay from one of their accounts. Zero for batches
+	// submitted without an access token.
+	UserID int32
+}
+
+// OpenFixedDepositParams moves Principal from the linked account into a new fixed deposit account.
+// The deposit account is opened in the linked account's currency and for the same user, with the
+// rates configured for TermMonths.
+type OpenFixedDepositParams struct {
+	LinkedAccountID     int32
+	AccountNumber       string
+	Principal           pgtype.Numeric
+	TermMonths          int32
+	MaturityInstruction db.MaturityInstruction
+	ReferenceNumber     string
+}
+
+// AccessDenial describes a request refused by access control, for the audit trail
+type AccessDenial struct {
+	UserID     int64
+	Role       db.UserRole
+	Permission string
+	Method     string
+	// Route is the route pattern the request matched, Path the path it was sent to
+	Route     string
+	Path      string
+	Reason    string
+	IPAddress string
+}
+
+// IssuedAPIKey is a newly issued API key, Key is the only copy of the key in clear. SigningSecret is
+// the secret requests sent with the key are signed with, empty while request signing is disabled.
+type IssuedAPIKey struct {
+	Key           string
+	SigningSecret string
+	APIKey        db.ApiKey
+}
@@ -1,6 +1,11 @@
 package schemas
 
-import db "github.com/riad/banksystemendtoend/db/sqlc"
+import (
+	"time"
+
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/shopspring/decimal"
+)
 
 type TransferTxResult struct {
 	Transaction db.Transaction
@@ -11,4 +16,180 @@ type TransferTxResult struct {
 	Status      db.TransactionStatus
 	Type        db.TransactionType
 	Currency    db.AccountCurrency
+	// Fees lists the fees the sender paid for the transfer, FromAccount includes them
+	Fees []db.FeeCharge
+}
+
+// ReversalTxResult holds the compensating transaction and the original it was booked against
+type ReversalTxResult struct {
+	Original    db.Transaction
+	Transaction db.Transaction
+	FromEntry   db.Entry
+	ToEntry     db.Entry
+	FromAccount db.Account
+	ToAccount   db.Account
+}
+
+// HoldTxResult holds an authorization hold together with its transaction and the affected accounts.
+// Entries and the merchant account are only set once the hold is captured.
+type HoldTxResult struct {
+	Hold            db.Hold
+	Transaction     db.Transaction
+	Account         db.Account
+	MerchantAccount db.Account
+	FromEntry       db.Entry
+	ToEntry         db.Entry
+}
+
+// FixedDepositTxResult holds a fixed deposit after it was opened, broken or matured. Transaction
+// funds the deposit when it is opened and pays it out when it ends; Interest and Penalty are only
+// set when interest was credited or an early break penalty charged.
+type FixedDepositTxResult struct {
+	Deposit       db.FixedDeposit
+	Account       db.Account
+	LinkedAccount db.Account
+	Transaction   db.Transaction
+	Interest      *db.Transaction
+	Penalty       *db.FeeCharge
+}
+
+// ScheduledRunResult counts the scheduled transfer attempts made by one scheduler run
+type ScheduledRunResult struct {
+	Executed int
+	Failed   int
+}
+
+// Fee is one fee a transfer would cost, in the sender's currency
+type Fee struct {
+	Type   db.FeeType
+	Amount decimal.Decimal
+}
+
+// FeePreview is what a transfer would book, without booking it
+type FeePreview struct {
+	DebitAmount    decimal.Decimal
+	DebitCurrency  string
+	CreditAmount   decimal.Decimal
+	CreditCurrency string
+	ExchangeRate   decimal.Decimal
+	Fees           []Fee
+	TotalFees      decimal.Decimal
+	// TotalDebit is the amount plus the fees, what leaves the sender account
+	TotalDebit decimal.Decimal
+}
+
+// PeriodicFeeResult counts the periodic fees charged by one run. Failures holds one error per
+// charge that could not be booked, typically because the account had no funds left.
+type PeriodicFeeResult struct {
+	Charged  int
+	Failures []error
+}
+
+// InterestRunResult counts the work done by one interest run
+type InterestRunResult struct {
+	// Through is the last day accrued, before the requested one when there were more missed days
+	// than a run catches up
+	Through time.Time
+	Days    int
+	Accrued int
+	Posted  int
+	// Remaining counts the missed days left for the next run
+	Remaining int
+}
+
+// ReconciliationResult holds a finished reconciliation run with the discrepancies it recorded.
+// Drift sums the absolute balance mismatches per currency.
+type ReconciliationResult struct {
+	Run           db.ReconciliationRun
+	Discrepancies []db.LedgerDiscrepancy
+	Drift         map[string]decimal.Decimal
+}
+
+// Statement is an account's ledger for a period. The opening balance sums the entries booked before
+// the period, every line carries the running balance after it and the closing balance is the last one.
+type Statement struct {
+	Account        db.Account
+	Holder         db.User
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+	TotalCredits   decimal.Decimal
+	TotalDebits    decimal.Decimal
+	Lines          []StatementLine
+	GeneratedAt    time.Time
+}
+
+// StatementLine is one entry of a statement, Amount is negative for debits
+type StatementLine struct {
+	EntryID               int64
+	BookedAt              time.Time
+	TransactionNumber     string
+	TypeCode              string
+	Description           string
+	ReferenceNumber       string
+	CounterpartyAccountID int32
+	Amount                decimal.Decimal
+	Balance               decimal.Decimal
+}
+
+// HashChainBreak is the first link of a hash chain that does not hold. RecordID is empty when
+// the link is missing altogether.
+type HashChainBreak struct {
+	Seq      int64
+	RecordID string
+	Reason   string
+}
+
+// HashChainVerification is the outcome of walking a hash chain; Break is nil when every link,
+// the chain head and every checkpoint matched
+type HashChainVerification struct {
+	Chain              string
+	LinksChecked       int64
+	CheckpointsChecked int
+	Head               db.HashChainHead
+	Break              *HashChainBreak
+}
+
+// ExchangeRateRefresh is the outcome of recording the rates fetched from a provider. Skipped
+// lists the currencies whose rate was not recorded, because the currency is unknown or the rate
+// is too small for the stored precision.
+type ExchangeRateRefresh struct {
+	Source    string
+	Recorded  int
+	Unchanged int
+	Skipped   []string
+	Updated   int64
+}
+
+// ExchangeRate is the rate from one currency to another that was valid at At
+type ExchangeRate struct {
+	FromCurrency string
+	ToCurrency   string
+	Rate         decimal.Decimal
+	At           time.Time
+}
+
+// AuthTokens are the tokens issued to a user at login or on refresh
+type AuthTokens struct {
+	User                  db.User
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// LoginResult is either the tokens of a new session or, for users with two-factor authentication,
+// the challenge token to send back with a code. ChallengeToken is empty when Tokens are set.
+type LoginResult struct {
+	Tokens             AuthTokens
+	ChallengeToken     string
+	ChallengeExpiresAt time.Time
+}
+
+// TwoFactorEnrollment is the secret a user adds to an authenticator app, ProvisioningURI is the
+// same secret as the otpauth:// URI apps read from a QR code
+type TwoFactorEnrollment struct {
+	Secret          string
+	ProvisioningURI string
 }
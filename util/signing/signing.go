@@ -0,0 +1,171 @@
+// Package signing signs and verifies the requests partner systems send. A client signs the method,
+// the path with its query, a timestamp, a nonce and the SHA-256 of the body with its own secret, so
+// a request cannot be changed or replayed by anyone who only saw the API key.
+//
+// Client secrets are not stored: each is an HMAC of the prefix of the client's API key under the
+// server key, so rotating the API key also gives the client a new secret.
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/config"
+)
+
+const (
+	// SecretEnv holds the server key client secrets are derived from, signing is disabled without it
+	SecretEnv = "REQUEST_SIGNING_SECRET"
+	// MaxClockSkewEnv overrides how far the timestamp of a signed request may be from the server clock
+	MaxClockSkewEnv = "REQUEST_SIGNING_MAX_SKEW"
+
+	// SignatureHeader, TimestampHeader and NonceHeader carry the signature of a request, the Unix time
+	// in seconds it was signed at and a value the client never sends twice
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Signature-Timestamp"
+	NonceHeader     = "X-Signature-Nonce"
+
+	// MinSecretSize is the shortest server key accepted, the size of an HMAC-SHA256 hash
+	MinSecretSize = 32
+
+	DefaultMaxClockSkew = 5 * time.Minute
+
+	minNonceSize = 16
+	maxNonceSize = 64
+)
+
+var (
+	ErrInvalidSecret    = errors.New("invalid request signing secret")
+	ErrInvalidSignature = errors.New("request signature is invalid")
+	ErrInvalidTimestamp = errors.New("request signature timestamp is missing or too far from the server time")
+	ErrInvalidNonce     = errors.New("request signature nonce must be 16 to 64 letters, digits, - or _")
+)
+
+// Request is what a signature covers
+type Request struct {
+	Method string
+	// Path is the escaped path followed by the raw query, if the request has one
+	Path      string
+	Timestamp string
+	Nonce     string
+	Body      []byte
+}
+
+// Signer derives client secrets and checks signatures
+type Signer struct {
+	secret       []byte
+	maxClockSkew time.Duration
+}
+
+// NewSigner creates a Signer deriving client secrets from secret, which must be at least
+// MinSecretSize bytes
+func NewSigner(secret string, maxClockSkew time.Duration) (*Signer, error) {
+	if len(secret) < MinSecretSize {
+		return nil, fmt.Errorf("%w: must be at least %d bytes", ErrInvalidSecret, MinSecretSize)
+	}
+	if maxClockSkew <= 0 {
+		return nil, fmt.Errorf("%w: clock skew must be positive", ErrInvalidSecret)
+	}
+	return &Signer{secret: []byte(secret), maxClockSkew: maxClockSkew}, nil
+}
+
+// SignerFromEnv creates a Signer from REQUEST_SIGNING_SECRET and REQUEST_SIGNING_MAX_SKEW. It returns
+// nil without an error when REQUEST_SIGNING_SECRET is not set, request signing is then disabled.
+func SignerFromEnv() (*Signer, error) {
+	secret := os.Getenv(SecretEnv)
+	if secret == "" {
+		return nil, nil
+	}
+	return NewSigner(secret, config.GetEnvAsDuration(MaxClockSkewEnv, DefaultMaxClockSkew))
+}
+
+// MaxClockSkew is how far the timestamp of a signed request may be from the server clock. Nonces must
+// be remembered for twice as long, a request is accepted that long.
+func (s *Signer) MaxClockSkew() time.Duration {
+	return s.maxClockSkew
+}
+
+// ClientSecret returns the secret of the client whose API key has keyPrefix
+func (s *Signer) ClientSecret(keyPrefix string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("request-signing:" + keyPrefix))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the timestamp, nonce and signature of req, sent by the client whose API key has
+// keyPrefix. It does not check whether the nonce was used before.
+func (s *Signer) Verify(keyPrefix string, req Request, signature string) error {
+	seconds, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	skew := time.Since(time.Unix(seconds, 0))
+	if skew > s.maxClockSkew || skew < -s.maxClockSkew {
+		return ErrInvalidTimestamp
+	}
+	if !validNonce(req.Nonce) {
+		return ErrInvalidNonce
+	}
+
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(Sign(s.ClientSecret(keyPrefix), req))
+	if !hmac.Equal(given, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// RequestPath returns the path of a request as it is signed, the escaped path followed by the raw
+// query if there is one
+func RequestPath(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.EscapedPath()
+	}
+	return u.EscapedPath() + "?" + u.RawQuery
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the string to sign of req under secret, as clients
+// compute it
+func Sign(secret string, req Request) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(StringToSign(req)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// StringToSign joins the upper case method, the path, the timestamp, the nonce and the hex encoded
+// SHA-256 of the body with newlines
+func StringToSign(req Request) string {
+	bodyHash := sha256.Sum256(req.Body)
+	return strings.Join([]string{
+		strings.ToUpper(req.Method),
+		req.Path,
+		req.Timestamp,
+		req.Nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+func validNonce(nonce string) bool {
+	if len(nonce) < minNonceSize || len(nonce) > maxNonceSize {
+		return false
+	}
+	for _, r := range nonce {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package signing_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/common"
+	"github.com/riad/banksystemendtoend/util/signing"
+	"github.com/stretchr/testify/require"
+)
+
+func newSignedRequest(at time.Time) signing.Request {
+	return signing.Request{
+		Method:    "POST",
+		Path:      "/api/v1/transfers",
+		Timestamp: strconv.FormatInt(at.Unix(), 10),
+		Nonce:     "0123456789abcdef",
+		Body:      []byte(`{"amount":"10.00"}`),
+	}
+}
+
+func TestStringToSign(t *testing.T) {
+	u, err := url.Parse("https://bank.example/api/v1/accounts/number/ACC%2F1?user_id=7&page=2")
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/accounts/number/ACC%2F1?user_id=7&page=2", signing.RequestPath(u))
+
+	u, err = url.Parse("https://bank.example/api/v1/transfers")
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/transfers", signing.RequestPath(u))
+
+	//? The method is upper cased and an empty body hashes like any other
+	req := signing.Request{Method: "get", Path: "/api/v1/accounts?user_id=7", Timestamp: "1700000000", Nonce: "0123456789abcdef"}
+	require.Equal(t, "GET\n/api/v1/accounts?user_id=7\n1700000000\n0123456789abcdef\n"+
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", signing.StringToSign(req))
+
+	mac := hmac.New(sha256.New, []byte("client-secret"))
+	mac.Write([]byte(signing.StringToSign(req)))
+	require.Equal(t, hex.EncodeToString(mac.Sum(nil)), signing.Sign("client-secret", req))
+}
+
+func TestVerifySignatureSkew(t *testing.T) {
+	signer, err := signing.NewSigner(common.RandomString(signing.MinSecretSize), time.Minute)
+	require.NoError(t, err)
+	secret := signer.ClientSecret("0123456789ab")
+
+	for _, at := range []time.Time{time.Now(), time.Now().Add(-50 * time.Second), time.Now().Add(50 * time.Second)} {
+		req := newSignedRequest(at)
+		require.NoError(t, signer.Verify("0123456789ab", req, signing.Sign(secret, req)))
+	}
+
+	//? Requests signed too long ago or too far ahead are refused even with a valid signature
+	for _, at := range []time.Time{time.Now().Add(-2 * time.Minute), time.Now().Add(2 * time.Minute)} {
+		req := newSignedRequest(at)
+		require.ErrorIs(t, signer.Verify("0123456789ab", req, signing.Sign(secret, req)), signing.ErrInvalidTimestamp)
+	}
+
+	req := newSignedRequest(time.Now())
+	req.Timestamp = "yesterday"
+	require.ErrorIs(t, signer.Verify("0123456789ab", req, signing.Sign(secret, req)), signing.ErrInvalidTimestamp)
+
+	for _, nonce := range []string{"short", "0123456789abcdef!", string(make([]byte, 65))} {
+		req := newSignedRequest(time.Now())
+		req.Nonce = nonce
+		require.ErrorIs(t, signer.Verify("0123456789ab", req, signing.Sign(secret, req)), signing.ErrInvalidNonce)
+	}
+
+	_, err = signing.NewSigner("short", time.Minute)
+	require.ErrorIs(t, err, signing.ErrInvalidSecret)
+}
+
+func TestVerifySignatureMismatch(t *testing.T) {
+	signer, err := signing.NewSigner(common.RandomString(signing.MinSecretSize), time.Minute)
+	require.NoError(t, err)
+	secret := signer.ClientSecret("0123456789ab")
+	req := newSignedRequest(time.Now())
+	signature := signing.Sign(secret, req)
+
+	//? Every part of the request is covered
+	changed := []func(r *signing.Request){
+		func(r *signing.Request) { r.Method = "PUT" },
+		func(r *signing.Request) { r.Path = "/api/v1/transfers?amount=1000" },
+		func(r *signing.Request) { r.Nonce = "fedcba9876543210" },
+		func(r *signing.Request) { r.Body = []byte(`{"amount":"1000.00"}`) },
+	}
+	for _, change := range changed {
+		tampered := req
+		change(&tampered)
+		require.ErrorIs(t, signer.Verify("0123456789ab", tampered, signature), signing.ErrInvalidSignature)
+	}
+
+	//? Another client's secret, or a signature that is not hex, does not verify
+	require.NotEqual(t, secret, signer.ClientSecret("ba9876543210"))
+	require.ErrorIs(t, signer.Verify("ba9876543210", req, signature), signing.ErrInvalidSignature)
+	require.ErrorIs(t, signer.Verify("0123456789ab", req, "not-hex"), signing.ErrInvalidSignature)
+	require.ErrorIs(t, signer.Verify("0123456789ab", req, ""), signing.ErrInvalidSignature)
+}
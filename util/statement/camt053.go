@@ -0,0 +1,198 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+	"github.com/shopspring/decimal"
+)
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+type camtDocument struct {
+	XMLName   xml.Name      `xml:"Document"`
+	Namespace string        `xml:"xmlns,attr"`
+	Statement camtStatement `xml:"BkToCstmrStmt"`
+}
+
+type camtStatement struct {
+	GroupHeader struct {
+		MessageID string `xml:"MsgId"`
+		CreatedAt string `xml:"CreDtTm"`
+	} `xml:"GrpHdr"`
+	Statement struct {
+		ID        string `xml:"Id"`
+		CreatedAt string `xml:"CreDtTm"`
+		Period    struct {
+			From string `xml:"FrDtTm"`
+			To   string `xml:"ToDtTm"`
+		} `xml:"FrToDt"`
+		Account struct {
+			ID struct {
+				Other struct {
+					ID string `xml:"Id"`
+				} `xml:"Othr"`
+			} `xml:"Id"`
+			Currency string `xml:"Ccy"`
+			Owner    struct {
+				Name string `xml:"Nm"`
+			} `xml:"Ownr"`
+		} `xml:"Acct"`
+		Balances []camtBalance `xml:"Bal"`
+		Summary  struct {
+			Entries camtSummary `xml:"TtlNtries"`
+			Credits camtSummary `xml:"TtlCdtNtries"`
+			Debits  camtSummary `xml:"TtlDbtNtries"`
+		} `xml:"TxsSummry"`
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"Stmt"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Date string `xml:"Dt"`
+}
+
+type camtBalance struct {
+	Type struct {
+		CodeOrProprietary struct {
+			Code string `xml:"Cd"`
+		} `xml:"CdOrPrtry"`
+	} `xml:"Tp"`
+	Amount      camtAmount `xml:"Amt"`
+	CreditDebit string     `xml:"CdtDbtInd"`
+	Date        camtDate   `xml:"Dt"`
+}
+
+type camtSummary struct {
+	Count string `xml:"NbOfNtries"`
+	Sum   string `xml:"Sum"`
+}
+
+type camtEntry struct {
+	Reference   string     `xml:"NtryRef"`
+	Amount      camtAmount `xml:"Amt"`
+	CreditDebit string     `xml:"CdtDbtInd"`
+	Status      string     `xml:"Sts"`
+	BookingDate camtDate   `xml:"BookgDt"`
+	ValueDate   camtDate   `xml:"ValDt"`
+	ServicerRef string     `xml:"AcctSvcrRef,omitempty"`
+	BankCode    struct {
+		Proprietary struct {
+			Code string `xml:"Cd"`
+		} `xml:"Prtry"`
+	} `xml:"BkTxCd"`
+	Details struct {
+		Transaction struct {
+			References struct {
+				EndToEndID string `xml:"EndToEndId"`
+			} `xml:"Refs"`
+			Remittance struct {
+				Unstructured string `xml:"Ustrd"`
+			} `xml:"RmtInf"`
+		} `xml:"TxDtls"`
+	} `xml:"NtryDtls"`
+}
+
+// renderCAMT053 writes an ISO 20022 camt.053.001.02 bank to customer statement with the opening
+// (OPBD) and closing (CLBD) booked balances and one booked entry per ledger entry
+func renderCAMT053(statement schemas.Statement) ([]byte, error) {
+	doc := camtDocument{Namespace: camt053Namespace}
+	currency := statement.Account.CurrencyCode
+	scale := money.Scale(currency)
+	created := statement.GeneratedAt.UTC().Format(time.RFC3339)
+	id := fmt.Sprintf("%s-%s-%s", statement.Account.AccountNumber,
+		statement.PeriodStart.Format("20060102"), statement.PeriodEnd.Format("20060102"))
+
+	doc.Statement.GroupHeader.MessageID = truncate(id, 35)
+	doc.Statement.GroupHeader.CreatedAt = created
+
+	stmt := &doc.Statement.Statement
+	stmt.ID = truncate(id, 35)
+	stmt.CreatedAt = created
+	stmt.Period.From = statement.PeriodStart.UTC().Format(time.RFC3339)
+	stmt.Period.To = statement.PeriodEnd.AddDate(0, 0, 1).Add(-time.Second).UTC().Format(time.RFC3339)
+	stmt.Account.ID.Other.ID = statement.Account.AccountNumber
+	stmt.Account.Currency = currency
+	stmt.Account.Owner.Name = truncate(holderName(statement), 70)
+	stmt.Balances = []camtBalance{
+		newCAMTBalance("OPBD", statement.OpeningBalance, currency, statement.PeriodStart),
+		newCAMTBalance("CLBD", statement.ClosingBalance, currency, statement.PeriodEnd),
+	}
+
+	credits, debits := 0, 0
+	for _, line := range statement.Lines {
+		entry := camtEntry{
+			Reference:   strconv.FormatInt(line.EntryID, 10),
+			Amount:      camtAmount{Currency: currency, Value: line.Amount.Abs().StringFixed(scale)},
+			CreditDebit: creditDebit(line.Amount),
+			Status:      "BOOK",
+			BookingDate: camtDate{Date: line.BookedAt.Format(time.DateOnly)},
+			ValueDate:   camtDate{Date: line.BookedAt.Format(time.DateOnly)},
+			ServicerRef: line.TransactionNumber,
+		}
+		entry.BankCode.Proprietary.Code = lineTypeCode(line)
+		entry.Details.Transaction.References.EndToEndID = "NOTPROVIDED"
+		if line.ReferenceNumber != "" {
+			entry.Details.Transaction.References.EndToEndID = truncate(line.ReferenceNumber, 35)
+		}
+		entry.Details.Transaction.Remittance.Unstructured = truncate(lineText(line), 140)
+		stmt.Entries = append(stmt.Entries, entry)
+
+		if line.Amount.IsNegative() {
+			debits++
+		} else {
+			credits++
+		}
+	}
+	stmt.Summary.Entries = camtSummary{
+		Count: strconv.Itoa(len(statement.Lines)),
+		Sum:   statement.TotalCredits.Add(statement.TotalDebits).StringFixed(scale),
+	}
+	stmt.Summary.Credits = camtSummary{Count: strconv.Itoa(credits), Sum: statement.TotalCredits.StringFixed(scale)}
+	stmt.Summary.Debits = camtSummary{Count: strconv.Itoa(debits), Sum: statement.TotalDebits.StringFixed(scale)}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+func newCAMTBalance(code string, amount decimal.Decimal, currency string, day time.Time) camtBalance {
+	balance := camtBalance{
+		Amount:      camtAmount{Currency: currency, Value: amount.Abs().StringFixed(money.Scale(currency))},
+		CreditDebit: creditDebit(amount),
+		Date:        camtDate{Date: day.Format(time.DateOnly)},
+	}
+	balance.Type.CodeOrProprietary.Code = code
+	return balance
+}
+
+// creditDebit is the camt indicator of an amount, zero counts as a credit
+func creditDebit(amount decimal.Decimal) string {
+	if amount.IsNegative() {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+func lineTypeCode(line schemas.StatementLine) string {
+	if line.TypeCode == "" {
+		return "ENTRY"
+	}
+	return line.TypeCode
+}
@@ -0,0 +1,72 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+var csvHeader = []string{
+	"booked_at", "transaction_number", "type", "description", "reference",
+	"counterparty_account_id", "debit", "credit", "balance", "currency",
+}
+
+// renderCSV writes one row per entry, framed by opening and closing balance rows so a spreadsheet
+// shows the whole statement
+func renderCSV(statement schemas.Statement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	currency := statement.Account.CurrencyCode
+	scale := money.Scale(currency)
+
+	rows := [][]string{
+		csvHeader,
+		{statement.PeriodStart.Format(time.DateOnly), "", "", "Opening balance", "", "", "", "",
+			statement.OpeningBalance.StringFixed(scale), currency},
+	}
+	for _, line := range statement.Lines {
+		debit, credit := "", ""
+		if line.Amount.IsNegative() {
+			debit = line.Amount.Neg().StringFixed(scale)
+		} else {
+			credit = line.Amount.StringFixed(scale)
+		}
+		counterparty := ""
+		if line.CounterpartyAccountID != 0 {
+			counterparty = strconv.Itoa(int(line.CounterpartyAccountID))
+		}
+		rows = append(rows, []string{
+			line.BookedAt.Format(time.RFC3339),
+			line.TransactionNumber,
+			line.TypeCode,
+			csvText(line.Description),
+			csvText(line.ReferenceNumber),
+			counterparty,
+			debit,
+			credit,
+			line.Balance.StringFixed(scale),
+			currency,
+		})
+	}
+	rows = append(rows, []string{statement.PeriodEnd.Format(time.DateOnly), "", "", "Closing balance", "", "",
+		statement.TotalDebits.StringFixed(scale), statement.TotalCredits.StringFixed(scale),
+		statement.ClosingBalance.StringFixed(scale), currency})
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvText keeps spreadsheets from evaluating free text that starts like a formula
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
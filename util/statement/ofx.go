@@ -0,0 +1,160 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/config"
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+// ofxBankID identifies the bank in BANKACCTFROM, personal finance apps only use it to tell banks apart
+const ofxBankID = "BANKSYSTEM"
+
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+`
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Response struct {
+			Status     ofxStatus `xml:"STATUS"`
+			ServerTime string    `xml:"DTSERVER"`
+			Language   string    `xml:"LANGUAGE"`
+		} `xml:"SONRS"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Bank struct {
+		Transaction struct {
+			ID        string       `xml:"TRNUID"`
+			Status    ofxStatus    `xml:"STATUS"`
+			Statement ofxStatement `xml:"STMTRS"`
+		} `xml:"STMTTRNRS"`
+	} `xml:"BANKMSGSRSV1"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxStatement struct {
+	Currency string `xml:"CURDEF"`
+	Account  struct {
+		BankID      string `xml:"BANKID"`
+		AccountID   string `xml:"ACCTID"`
+		AccountType string `xml:"ACCTTYPE"`
+	} `xml:"BANKACCTFROM"`
+	Transactions struct {
+		Start string           `xml:"DTSTART"`
+		End   string           `xml:"DTEND"`
+		Lines []ofxTransaction `xml:"STMTTRN"`
+	} `xml:"BANKTRANLIST"`
+	LedgerBalance ofxBalance `xml:"LEDGERBAL"`
+}
+
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	FitID  string `xml:"FITID"`
+	Name   string `xml:"NAME"`
+	Memo   string `xml:"MEMO,omitempty"`
+}
+
+type ofxBalance struct {
+	Amount string `xml:"BALAMT"`
+	AsOf   string `xml:"DTASOF"`
+}
+
+// renderOFX writes an OFX 2.2 bank statement. FITID is the entry id, which never changes, so apps
+// importing overlapping statements do not duplicate transactions.
+func renderOFX(statement schemas.Statement) ([]byte, error) {
+	var doc ofxDocument
+	ok := ofxStatus{Code: 0, Severity: "INFO"}
+	doc.SignOn.Response.Status = ok
+	doc.SignOn.Response.ServerTime = ofxTime(statement.GeneratedAt)
+	doc.SignOn.Response.Language = "ENG"
+	doc.Bank.Transaction.ID = "0"
+	doc.Bank.Transaction.Status = ok
+
+	stmt := &doc.Bank.Transaction.Statement
+	scale := money.Scale(statement.Account.CurrencyCode)
+	stmt.Currency = statement.Account.CurrencyCode
+	stmt.Account.BankID = ofxBankID
+	stmt.Account.AccountID = statement.Account.AccountNumber
+	stmt.Account.AccountType = ofxAccountType(statement.Account.AccountType)
+	stmt.Transactions.Start = ofxTime(statement.PeriodStart)
+	stmt.Transactions.End = ofxTime(statement.PeriodEnd.AddDate(0, 0, 1))
+	for _, line := range statement.Lines {
+		stmt.Transactions.Lines = append(stmt.Transactions.Lines, ofxTransaction{
+			Type:   ofxTransactionType(line),
+			Posted: ofxTime(line.BookedAt),
+			Amount: line.Amount.StringFixed(scale),
+			FitID:  strconv.FormatInt(line.EntryID, 10),
+			Name:   truncate(lineText(line), 32),
+			Memo:   line.ReferenceNumber,
+		})
+	}
+	stmt.LedgerBalance = ofxBalance{
+		Amount: statement.ClosingBalance.StringFixed(scale),
+		AsOf:   ofxTime(statement.PeriodEnd.AddDate(0, 0, 1)),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(ofxHeader)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
+
+func ofxAccountType(accountType string) string {
+	switch accountType {
+	case config.AccountTypes.SAVINGS, config.AccountTypes.FIXED_DEPOSIT:
+		return "SAVINGS"
+	case config.AccountTypes.MONEY_MARKET:
+		return "MONEYMRKT"
+	}
+	return "CHECKING"
+}
+
+func ofxTransactionType(line schemas.StatementLine) string {
+	switch line.TypeCode {
+	case config.TransactionTypes.FEE:
+		return "FEE"
+	case config.TransactionTypes.INTEREST:
+		return "INT"
+	case config.TransactionTypes.DEPOSIT:
+		if line.Amount.IsPositive() {
+			return "DEP"
+		}
+	case config.TransactionTypes.TRANSFER:
+		return "XFER"
+	case config.TransactionTypes.PAYMENT:
+		return "PAYMENT"
+	}
+	if line.Amount.IsNegative() {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
+
+// truncate cuts s to at most n runes, OFX limits the length of NAME
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
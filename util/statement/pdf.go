@@ -0,0 +1,154 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/money"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+// The PDF is plain text in the Courier base font, which every reader ships, so no font is embedded
+// and the columns line up without measuring glyphs
+const (
+	pdfPageWidth    = 595 // A4 in points
+	pdfPageHeight   = 842
+	pdfMargin       = 40
+	pdfFontSize     = 8
+	pdfLeading      = 11
+	pdfLinesPerPage = 66
+)
+
+// pdfRow lays out one table row: date, type, description, debit, credit and balance
+const pdfRow = "%-10s %-13s %-34s %14s %14s %15s"
+
+func renderPDF(statement schemas.Statement) ([]byte, error) {
+	currency := statement.Account.CurrencyCode
+	scale := money.Scale(currency)
+	header := []string{
+		"ACCOUNT STATEMENT",
+		"",
+		fmt.Sprintf("Account holder:  %s", holderName(statement)),
+		fmt.Sprintf("Account number:  %s (%s)", statement.Account.AccountNumber, statement.Account.AccountType),
+		fmt.Sprintf("Currency:        %s", currency),
+		fmt.Sprintf("Period:          %s to %s", statement.PeriodStart.Format(time.DateOnly),
+			statement.PeriodEnd.Format(time.DateOnly)),
+		fmt.Sprintf("Generated:       %s", statement.GeneratedAt.UTC().Format("2006-01-02 15:04 MST")),
+		"",
+		fmt.Sprintf("Opening balance: %s %s", statement.OpeningBalance.StringFixed(scale), currency),
+		"",
+	}
+	tableHeader := []string{
+		fmt.Sprintf(pdfRow, "Date", "Type", "Description", "Debit", "Credit", "Balance"),
+		strings.Repeat("-", 105),
+	}
+
+	var rows []string
+	for _, line := range statement.Lines {
+		debit, credit := "", ""
+		if line.Amount.IsNegative() {
+			debit = line.Amount.Neg().StringFixed(scale)
+		} else {
+			credit = line.Amount.StringFixed(scale)
+		}
+		rows = append(rows, fmt.Sprintf(pdfRow,
+			line.BookedAt.Format(time.DateOnly),
+			truncate(line.TypeCode, 13),
+			truncate(lineText(line), 34),
+			debit,
+			credit,
+			line.Balance.StringFixed(scale)))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, "No entries were booked in this period.")
+	}
+	footer := []string{
+		strings.Repeat("-", 105),
+		fmt.Sprintf(pdfRow, "", "", "Totals", statement.TotalDebits.StringFixed(scale),
+			statement.TotalCredits.StringFixed(scale), ""),
+		"",
+		fmt.Sprintf("Closing balance: %s %s", statement.ClosingBalance.StringFixed(scale), currency),
+	}
+
+	// The table header is repeated at the top of every page after the first
+	var pages [][]string
+	page := append(append([]string{}, header...), tableHeader...)
+	for _, row := range append(rows, footer...) {
+		if len(page) == pdfLinesPerPage {
+			pages = append(pages, page)
+			page = append([]string{}, tableHeader...)
+		}
+		page = append(page, row)
+	}
+	pages = append(pages, page)
+
+	for i := range pages {
+		pages[i] = append(pages[i], "", fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+	}
+	return writePDF(pages), nil
+}
+
+// writePDF writes a PDF 1.4 document with one page per slice of text lines
+func writePDF(pages [][]string) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1 to 3 are the catalog, the page tree and the font; each page then takes two objects,
+	// the page and its content stream
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 5+2*i))
+
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n",
+			pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin)
+		for _, line := range lines {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", pdfText(line))
+		}
+		content.WriteString("ET")
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfText escapes a line for a PDF string, characters outside printable ASCII are replaced so the
+// text never depends on the reader's encoding
+func pdfText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
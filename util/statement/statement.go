@@ -0,0 +1,90 @@
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	db "github.com/riad/banksystemendtoend/db/sqlc"
+	"github.com/riad/banksystemendtoend/util/schemas"
+)
+
+// ErrUnsupportedFormat is returned for formats Render cannot produce
+var ErrUnsupportedFormat = errors.New("unsupported statement format")
+
+// File is a rendered statement, ready to be downloaded or stored
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+type renderer struct {
+	extension   string
+	contentType string
+	render      func(schemas.Statement) ([]byte, error)
+}
+
+var renderers = map[db.StatementFormat]renderer{
+	db.StatementFormatCSV:     {extension: "csv", contentType: "text/csv", render: renderCSV},
+	db.StatementFormatPDF:     {extension: "pdf", contentType: "application/pdf", render: renderPDF},
+	db.StatementFormatOFX:     {extension: "ofx", contentType: "application/x-ofx", render: renderOFX},
+	db.StatementFormatCAMT053: {extension: "camt053.xml", contentType: "application/xml", render: renderCAMT053},
+}
+
+// Render produces the statement in the requested format
+func Render(format db.StatementFormat, statement schemas.Statement) (File, error) {
+	r, ok := renderers[format]
+	if !ok {
+		return File{}, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	data, err := r.render(statement)
+	if err != nil {
+		return File{}, fmt.Errorf("failed to render %s statement: %w", format, err)
+	}
+	name := fmt.Sprintf("statement-%s-%s-%s",
+		statement.Account.AccountNumber,
+		statement.PeriodStart.Format("20060102"),
+		statement.PeriodEnd.Format("20060102"))
+	return File{Name: FileName(format, name), ContentType: r.contentType, Data: data}, nil
+}
+
+// FileName appends the extension of the format to name
+func FileName(format db.StatementFormat, name string) string {
+	return name + "." + renderers[format].extension
+}
+
+// ContentType returns the media type files of the format are served with
+func ContentType(format db.StatementFormat) string {
+	return renderers[format].contentType
+}
+
+// ParseFormat accepts the format names case-insensitively, "camt.053" included
+func ParseFormat(format string) (db.StatementFormat, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(format, ".", ""))
+	switch db.StatementFormat(normalized) {
+	case db.StatementFormatCSV, db.StatementFormatPDF, db.StatementFormatOFX, db.StatementFormatCAMT053:
+		return db.StatementFormat(normalized), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// holderName is the account holder's full name, falling back to the username
+func holderName(statement schemas.Statement) string {
+	name := strings.TrimSpace(statement.Holder.FirstName.String + " " + statement.Holder.LastName.String)
+	if name == "" {
+		return statement.Holder.Username
+	}
+	return name
+}
+
+// lineText describes a line in one string: its description, else its transaction type
+func lineText(line schemas.StatementLine) string {
+	if line.Description != "" {
+		return line.Description
+	}
+	if line.TypeCode != "" {
+		return line.TypeCode
+	}
+	return "Entry"
+}
@@ -0,0 +1,208 @@
+// Package token issues the access tokens and refresh tokens handed out at login. Access tokens are
+// HS256 signed JWTs that are checked without a database lookup; refresh tokens are opaque random
+// strings of which only a hash is stored. Users with two-factor authentication first get a short
+// lived challenge token, a JWT with a purpose claim that is not accepted as an access token.
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/riad/banksystemendtoend/util/config"
+)
+
+const (
+	// SecretEnv holds the key access tokens are signed with
+	SecretEnv = "JWT_SECRET"
+	// AccessTokenTTLEnv and RefreshTokenTTLEnv override how long tokens are valid, as Go durations
+	AccessTokenTTLEnv  = "ACCESS_TOKEN_TTL"
+	RefreshTokenTTLEnv = "REFRESH_TOKEN_TTL"
+
+	// MinSecretSize is the shortest signing key accepted, the size of an HS256 hash
+	MinSecretSize = 32
+
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+	// ChallengeTokenTTL is how long a user has to enter the second factor after the password
+	ChallengeTokenTTL = 5 * time.Minute
+
+	// purposeTwoFactor marks challenge tokens, access tokens have no purpose
+	purposeTwoFactor = "2fa"
+
+	refreshTokenSize = 32
+)
+
+var (
+	ErrInvalidSecret = errors.New("invalid token secret")
+	ErrInvalidToken  = errors.New("token is invalid")
+	ErrExpiredToken  = errors.New("token has expired")
+)
+
+// jwtHeader is the only header access tokens are issued and accepted with
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Payload is what an access token says about its bearer
+type Payload struct {
+	ID        uuid.UUID
+	UserID    int64
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type claims struct {
+	Subject   string `json:"sub"`
+	ID        string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	Purpose   string `json:"purpose,omitempty"`
+}
+
+// Maker creates and verifies access tokens and creates refresh tokens
+type Maker struct {
+	secret          []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+// NewMaker creates a Maker signing with secret, which must be at least MinSecretSize bytes
+func NewMaker(secret string, accessTokenTTL, refreshTokenTTL time.Duration) (*Maker, error) {
+	if len(secret) < MinSecretSize {
+		return nil, fmt.Errorf("%w: must be at least %d bytes", ErrInvalidSecret, MinSecretSize)
+	}
+	if accessTokenTTL <= 0 || refreshTokenTTL <= 0 {
+		return nil, fmt.Errorf("%w: token lifetimes must be positive", ErrInvalidSecret)
+	}
+	return &Maker{secret: []byte(secret), accessTokenTTL: accessTokenTTL, refreshTokenTTL: refreshTokenTTL}, nil
+}
+
+// MakerFromEnv creates a Maker from JWT_SECRET and the token lifetime variables
+func MakerFromEnv() (*Maker, error) {
+	secret := os.Getenv(SecretEnv)
+	if secret == "" {
+		return nil, fmt.Errorf("%w: %s environment variable is not set", ErrInvalidSecret, SecretEnv)
+	}
+	return NewMaker(secret,
+		config.GetEnvAsDuration(AccessTokenTTLEnv, DefaultAccessTokenTTL),
+		config.GetEnvAsDuration(RefreshTokenTTLEnv, DefaultRefreshTokenTTL))
+}
+
+// CreateAccessToken issues an access token for a user, valid for the access token lifetime
+func (m *Maker) CreateAccessToken(userID int64) (string, Payload, error) {
+	return m.create(userID, "", m.accessTokenTTL)
+}
+
+// VerifyAccessToken checks the signature and expiry of an access token and returns its payload
+func (m *Maker) VerifyAccessToken(token string) (Payload, error) {
+	return m.verify(token, "")
+}
+
+// CreateChallengeToken issues the token a user who passed the password check exchanges, together
+// with a second factor, for a session
+func (m *Maker) CreateChallengeToken(userID int64) (string, Payload, error) {
+	return m.create(userID, purposeTwoFactor, ChallengeTokenTTL)
+}
+
+// VerifyChallengeToken checks the signature and expiry of a challenge token and returns its payload
+func (m *Maker) VerifyChallengeToken(token string) (Payload, error) {
+	return m.verify(token, purposeTwoFactor)
+}
+
+// NewRefreshToken returns a new random refresh token and when it expires. Only its hash is stored.
+func (m *Maker) NewRefreshToken() (string, time.Time, error) {
+	buf := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("error generating refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), time.Now().Add(m.refreshTokenTTL), nil
+}
+
+// HashRefreshToken returns the hex encoded SHA-256 of a refresh token, as it is stored. The token
+// is random, so a plain hash is enough to keep a database copy from being usable.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *Maker) create(userID int64, purpose string, ttl time.Duration) (string, Payload, error) {
+	now := time.Now()
+	payload := Payload{
+		ID:        uuid.New(),
+		UserID:    userID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+	body, err := json.Marshal(claims{
+		Subject:   strconv.FormatInt(userID, 10),
+		ID:        payload.ID.String(),
+		IssuedAt:  payload.IssuedAt.Unix(),
+		ExpiresAt: payload.ExpiresAt.Unix(),
+		Purpose:   purpose,
+	})
+	if err != nil {
+		return "", Payload{}, err
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(body)
+	return unsigned + "." + m.sign(unsigned), payload, nil
+}
+
+// verify accepts only tokens issued for purpose, so a challenge token is never taken for an access
+// token or the other way round
+func (m *Maker) verify(token, purpose string) (Payload, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return Payload{}, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Payload{}, ErrInvalidToken
+	}
+	expected, _ := base64.RawURLEncoding.DecodeString(m.sign(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, expected) {
+		return Payload{}, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Payload{}, ErrInvalidToken
+	}
+	var c claims
+	if err := json.Unmarshal(body, &c); err != nil || c.Purpose != purpose {
+		return Payload{}, ErrInvalidToken
+	}
+	userID, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return Payload{}, ErrInvalidToken
+	}
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return Payload{}, ErrInvalidToken
+	}
+
+	payload := Payload{
+		ID:        id,
+		UserID:    userID,
+		IssuedAt:  time.Unix(c.IssuedAt, 0),
+		ExpiresAt: time.Unix(c.ExpiresAt, 0),
+	}
+	if !time.Now().Before(payload.ExpiresAt) {
+		return Payload{}, ErrExpiredToken
+	}
+	return payload, nil
+}
+
+func (m *Maker) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
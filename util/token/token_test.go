@@ -0,0 +1,124 @@
+package token_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/common"
+	"github.com/riad/banksystemendtoend/util/token"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMaker(t *testing.T, accessTokenTTL time.Duration) *token.Maker {
+	maker, err := token.NewMaker(common.RandomString(token.MinSecretSize), accessTokenTTL, time.Hour)
+	require.NoError(t, err)
+	return maker
+}
+
+func TestAccessToken(t *testing.T) {
+	maker := newTestMaker(t, time.Minute)
+
+	issued, created, err := maker.CreateAccessToken(42)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyAccessToken(issued)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), payload.UserID)
+	require.Equal(t, created.ID, payload.ID)
+	require.WithinDuration(t, created.ExpiresAt, payload.ExpiresAt, time.Second)
+
+	//? Tokens signed with another secret are refused
+	_, err = newTestMaker(t, time.Minute).VerifyAccessToken(issued)
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+
+	_, err = token.NewMaker("short", time.Minute, time.Hour)
+	require.ErrorIs(t, err, token.ErrInvalidSecret)
+}
+
+func TestAccessTokenTamperedSignature(t *testing.T) {
+	maker := newTestMaker(t, time.Minute)
+	issued, _, err := maker.CreateAccessToken(42)
+	require.NoError(t, err)
+	parts := strings.Split(issued, ".")
+
+	//? A different user in the claims invalidates the signature
+	other, _, err := maker.CreateAccessToken(43)
+	require.NoError(t, err)
+	swapped := parts[0] + "." + strings.Split(other, ".")[1] + "." + parts[2]
+	_, err = maker.VerifyAccessToken(swapped)
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	signature[0] ^= 0x01
+	flipped := parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString(signature)
+	_, err = maker.VerifyAccessToken(flipped)
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+
+	_, err = maker.VerifyAccessToken(parts[0] + "." + parts[1] + ".")
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+}
+
+func TestAccessTokenWrongAlgorithm(t *testing.T) {
+	secret := common.RandomString(token.MinSecretSize)
+	maker, err := token.NewMaker(secret, time.Minute, time.Hour)
+	require.NoError(t, err)
+	issued, _, err := maker.CreateAccessToken(42)
+	require.NoError(t, err)
+	body := strings.Split(issued, ".")[1]
+
+	//? Unsigned tokens are refused
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	_, err = maker.VerifyAccessToken(none + "." + body + ".")
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+
+	//? So are tokens signed with the right secret under another algorithm
+	hs512 := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS512","typ":"JWT"}`))
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write([]byte(hs512 + "." + body))
+	signed := hs512 + "." + body + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	_, err = maker.VerifyAccessToken(signed)
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+}
+
+func TestAccessTokenExpired(t *testing.T) {
+	maker := newTestMaker(t, time.Nanosecond)
+
+	issued, _, err := maker.CreateAccessToken(42)
+	require.NoError(t, err)
+
+	_, err = maker.VerifyAccessToken(issued)
+	require.ErrorIs(t, err, token.ErrExpiredToken)
+}
+
+func TestTokenPurposes(t *testing.T) {
+	maker := newTestMaker(t, time.Minute)
+
+	//? A refresh token is opaque and never passes for an access token
+	refresh, expiresAt, err := maker.NewRefreshToken()
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Second)
+	_, err = maker.VerifyAccessToken(refresh)
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+	require.Len(t, token.HashRefreshToken(refresh), 64)
+	require.NotEqual(t, refresh, token.HashRefreshToken(refresh))
+
+	//? Challenge tokens and access tokens are not interchangeable
+	challenge, _, err := maker.CreateChallengeToken(42)
+	require.NoError(t, err)
+	_, err = maker.VerifyAccessToken(challenge)
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+
+	access, _, err := maker.CreateAccessToken(42)
+	require.NoError(t, err)
+	_, err = maker.VerifyChallengeToken(access)
+	require.ErrorIs(t, err, token.ErrInvalidToken)
+
+	payload, err := maker.VerifyChallengeToken(challenge)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), payload.UserID)
+}
@@ -0,0 +1,157 @@
+// Package totp implements the time-based one-time passwords of RFC 6238 that authenticator apps
+// show: an HMAC-SHA1 of the number of 30 second steps since the Unix epoch, truncated to 6 digits.
+// It also generates the recovery codes users fall back on when they lose their authenticator.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// IssuerEnv overrides the name authenticator apps list the account under
+	IssuerEnv     = "TOTP_ISSUER"
+	DefaultIssuer = "BankSystem"
+
+	// Digits and Period are the code size and step length every common authenticator app uses
+	Digits = 6
+	Period = 30 * time.Second
+
+	// RecoveryCodeCount is how many recovery codes a user gets when enabling two-factor authentication
+	RecoveryCodeCount = 10
+
+	secretSize       = 20
+	recoveryCodeSize = 10
+	// skew is how many steps a code may be off, to allow for clocks drifting and codes typed late
+	skew = 1
+)
+
+var ErrInvalidSecret = errors.New("invalid TOTP secret")
+
+// encoding is base32 without padding, the form secrets are shown and stored in
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// IssuerFromEnv returns TOTP_ISSUER, or DefaultIssuer when it is not set
+func IssuerFromEnv() string {
+	if issuer := os.Getenv(IssuerEnv); issuer != "" {
+		return issuer
+	}
+	return DefaultIssuer
+}
+
+// GenerateSecret returns a new random secret, base32 encoded
+func GenerateSecret() (string, error) {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error generating TOTP secret: %w", err)
+	}
+	return encoding.EncodeToString(buf), nil
+}
+
+// ProvisioningURI returns the otpauth:// URI authenticator apps add an account from, usually shown
+// as a QR code
+func ProvisioningURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period.Seconds())))
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// Step returns the time step t falls in
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Code returns the code of secret for a time step
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", ErrInvalidSecret
+	}
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1000000), nil
+}
+
+// Validate checks code against the steps around now and returns the step it matched. Steps up to
+// lastUsedStep are skipped, so a code is accepted once; callers store the returned step.
+func Validate(secret, code string, now time.Time, lastUsedStep int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+	current := Step(now)
+	for step := current - skew; step <= current+skew; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes returns RecoveryCodeCount new recovery codes, shown to the user once
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	buf := make([]byte, recoveryCodeSize)
+	for range RecoveryCodeCount {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("error generating recovery code: %w", err)
+		}
+		code := strings.ToLower(encoding.EncodeToString(buf))
+		codes = append(codes, code[:4]+"-"+code[4:8]+"-"+code[8:12]+"-"+code[12:])
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode returns the hex encoded SHA-256 of a recovery code, as it is stored. Case, spaces
+// and dashes are ignored. Codes are random, so a plain hash is enough to keep a database copy from
+// being usable.
+func HashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsRecoveryCode tells recovery codes apart from TOTP codes, which are all digits
+func IsRecoveryCode(code string) bool {
+	code = strings.TrimSpace(code)
+	return len(code) > Digits
+}
@@ -0,0 +1,98 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/riad/banksystemendtoend/util/totp"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890" base32 encoded
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	//? The last six digits of the eight digit codes in RFC 6238 appendix B
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, vector := range vectors {
+		code, err := totp.Code(rfc6238Secret, totp.Step(time.Unix(vector.unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, vector.code, code, vector.unix)
+	}
+
+	//? Secrets are accepted in lower case, the way some apps display them
+	code, err := totp.Code(strings.ToLower(rfc6238Secret), 1)
+	require.NoError(t, err)
+	require.Equal(t, "287082", code)
+
+	_, err = totp.Code("not base32!", 1)
+	require.ErrorIs(t, err, totp.ErrInvalidSecret)
+}
+
+func TestTOTPValidateWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totp.Step(now)
+	codeAt := func(step int64) string {
+		code, err := totp.Code(rfc6238Secret, step)
+		require.NoError(t, err)
+		return code
+	}
+
+	//? One step either side is accepted, for clocks drifting and codes typed late
+	for _, step := range []int64{current - 1, current, current + 1} {
+		matched, ok := totp.Validate(rfc6238Secret, codeAt(step), now, 0)
+		require.True(t, ok, step)
+		require.Equal(t, step, matched)
+	}
+	for _, step := range []int64{current - 2, current + 2} {
+		_, ok := totp.Validate(rfc6238Secret, codeAt(step), now, 0)
+		require.False(t, ok, step)
+	}
+
+	matched, ok := totp.Validate(rfc6238Secret, " "+codeAt(current)+" ", now, 0)
+	require.True(t, ok)
+	require.Equal(t, current, matched)
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		_, ok := totp.Validate(rfc6238Secret, code, now, 0)
+		require.False(t, ok, code)
+	}
+	_, ok = totp.Validate("not base32!", codeAt(current), now, 0)
+	require.False(t, ok)
+}
+
+func TestTOTPValidateOnce(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totp.Step(now)
+	code, err := totp.Code(rfc6238Secret, current)
+	require.NoError(t, err)
+
+	matched, ok := totp.Validate(rfc6238Secret, code, now, current-1)
+	require.True(t, ok)
+
+	//? Once its step is stored the same code is refused, as is any code of an earlier step
+	_, ok = totp.Validate(rfc6238Secret, code, now, matched)
+	require.False(t, ok)
+	previous, err := totp.Code(rfc6238Secret, current-1)
+	require.NoError(t, err)
+	_, ok = totp.Validate(rfc6238Secret, previous, now, matched)
+	require.False(t, ok)
+
+	//? The next step's code is still accepted
+	next, err := totp.Code(rfc6238Secret, current+1)
+	require.NoError(t, err)
+	matched, ok = totp.Validate(rfc6238Secret, next, now, matched)
+	require.True(t, ok)
+	require.Equal(t, current+1, matched)
+}